@@ -13,12 +13,16 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
 	"github.com/solanyn/tgp-operator/pkg/config"
 	"github.com/solanyn/tgp-operator/pkg/controllers"
 	"github.com/solanyn/tgp-operator/pkg/imagefactory"
+	"github.com/solanyn/tgp-operator/pkg/metrics"
 	"github.com/solanyn/tgp-operator/pkg/pricing"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+	"github.com/solanyn/tgp-operator/pkg/webhooks"
 )
 
 var (
@@ -26,6 +30,16 @@ var (
 	setupLog = ctrl.Log.WithName("setup")
 )
 
+// shutdownFlushTimeout bounds settling in-flight launches once the manager has stopped
+const shutdownFlushTimeout = 20 * time.Second
+
+// Build information, injected with -ldflags at build time
+var (
+	version = "dev"
+	commit  = "unknown"
+	date    = "unknown"
+)
+
 func init() { //nolint:gochecknoinits // Required for Kubernetes scheme registration
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(tgpv1.AddToScheme(scheme))
@@ -34,13 +48,35 @@ func init() { //nolint:gochecknoinits // Required for Kubernetes scheme registra
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
+	var leaderElectionID string
+	var leaderElectionNamespace string
+	var leaseDuration time.Duration
+	var renewDeadline time.Duration
+	var retryPeriod time.Duration
 	var probeAddr string
+	var enableWebhooks bool
+	var terminateOnShutdown bool
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"Serve the admission webhooks. Requires a serving certificate in the webhook server's cert directory.")
+	flag.BoolVar(&terminateOnShutdown, "terminate-on-shutdown", false,
+		"Terminate instances launched by this operator whose node does not exist yet when it shuts down. "+
+			"By default they are left running and recorded in their pool's status for the next leader to resume.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&leaderElectionID, "leader-election-id", "tgp-operator-leader-election",
+		"The name of the lease used for leader election.")
+	flag.StringVar(&leaderElectionNamespace, "leader-election-namespace", "",
+		"The namespace of the leader election lease. Defaults to the namespace the operator runs in.")
+	flag.DurationVar(&leaseDuration, "leader-election-lease-duration", 15*time.Second,
+		"How long non-leaders wait before attempting to acquire leadership.")
+	flag.DurationVar(&renewDeadline, "leader-election-renew-deadline", 10*time.Second,
+		"How long the leader retries refreshing leadership before giving it up.")
+	flag.DurationVar(&retryPeriod, "leader-election-retry-period", 2*time.Second,
+		"How long leader election clients wait between attempts.")
 
 	opts := zap.Options{
 		Development: true,
@@ -49,14 +85,18 @@ func main() {
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	setupLog.Info("Starting tgp-operator", "version", version, "commit", commit, "buildDate", date)
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                  scheme,
-		Metrics:                 ctrl.Options{}.Metrics,
+		Metrics:                 metricsserver.Options{BindAddress: metricsAddr},
 		HealthProbeBindAddress:  probeAddr,
 		LeaderElection:          enableLeaderElection,
-		LeaderElectionID:        "tgp-operator-leader-election",
-		LeaderElectionNamespace: "",
+		LeaderElectionID:        leaderElectionID,
+		LeaderElectionNamespace: leaderElectionNamespace,
+		LeaseDuration:           &leaseDuration,
+		RenewDeadline:           &renewDeadline,
+		RetryPeriod:             &retryPeriod,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -64,40 +104,44 @@ func main() {
 	}
 
 	pricingCache := pricing.NewCache(time.Minute * 15)
+	inventoryCache := pricing.NewInventoryCache(time.Minute * 5)
+	circuitBreakers := providers.NewCircuitBreakers(providers.DefaultCircuitBreakerThreshold, providers.DefaultCircuitBreakerCooldown)
+
+	metrics.RegisterMetrics()
+	operatorMetrics := metrics.NewMetrics()
 
-	// Load operator configuration using direct client (not cached)
 	operatorNamespace := os.Getenv("OPERATOR_NAMESPACE")
 	if operatorNamespace == "" {
 		operatorNamespace = "tgp-system" // Default namespace
 	}
 
-	// Create direct client for ConfigMap loading
-	directClient, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	// Create direct client (not cached) for the operator ConfigMap and the shutdown flush
+	directClient, err := client.NewWithWatch(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
 	if err != nil {
 		setupLog.Error(err, "failed to create direct client")
 		os.Exit(1)
 	}
 
-	operatorConfig, err := config.LoadConfig(context.Background(), directClient, "tgp-operator-config", operatorNamespace)
-	if err != nil {
-		setupLog.Error(err, "failed to load operator configuration, using defaults")
-		operatorConfig = config.DefaultConfig()
-	} else {
-		setupLog.Info("loaded operator configuration from ConfigMap",
-			"namespace", operatorNamespace,
-			"vultr.enabled", operatorConfig.Providers.Vultr.Enabled,
-			"gcp.enabled", operatorConfig.Providers.GCP.Enabled,
-			"vultr.secret", operatorConfig.Providers.Vultr.CredentialsRef.Name,
-			"vultr.key", operatorConfig.Providers.Vultr.CredentialsRef.Key,
-		)
+	// Load the operator configuration once the manager starts, so a slow API server does not
+	// hold up leader election and probes, and reload it whenever the ConfigMap changes
+	operatorConfig := config.NewLoader(directClient, config.ConfigMapName, operatorNamespace, ctrl.Log.WithName("config"))
+	if err = mgr.Add(operatorConfig); err != nil {
+		setupLog.Error(err, "unable to add operator configuration loader")
+		os.Exit(1)
 	}
 
 	// Setup GPUNodeClass controller
 	if err = (&controllers.GPUNodeClassReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-		Log:    ctrl.Log.WithName("controllers").WithName("GPUNodeClass"),
-		Config: operatorConfig,
+		Client:       mgr.GetClient(),
+		Scheme:       mgr.GetScheme(),
+		Log:          ctrl.Log.WithName("controllers").WithName("GPUNodeClass"),
+		Config:       operatorConfig,
+		PricingCache: pricingCache,
+		Inventory:    inventoryCache,
+		Metrics:      operatorMetrics,
+
+		CircuitBreakers: circuitBreakers,
+		ConfigChanges:   operatorConfig.Subscribe(),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "GPUNodeClass")
 		os.Exit(1)
@@ -107,18 +151,67 @@ func main() {
 	imageFactory := imagefactory.NewClient("")
 
 	// Setup GPUNodePool controller
-	if err = (&controllers.GPUNodePoolReconciler{
+	nodePoolReconciler := &controllers.GPUNodePoolReconciler{
 		Client:       mgr.GetClient(),
 		Scheme:       mgr.GetScheme(),
 		Log:          ctrl.Log.WithName("controllers").WithName("GPUNodePool"),
 		Config:       operatorConfig,
 		PricingCache: pricingCache,
+		Inventory:    inventoryCache,
 		ImageFactory: imageFactory,
-	}).SetupWithManager(mgr); err != nil {
+		Metrics:      operatorMetrics,
+		Recorder:     mgr.GetEventRecorderFor("gpunodepool-controller"),
+
+		CircuitBreakers: circuitBreakers,
+		LaunchLimiters: providers.NewLaunchLimiters(func(provider string) int {
+			return operatorConfig.Current().MaxConcurrentLaunches(provider)
+		}),
+		ConfigChanges:   operatorConfig.Subscribe(),
+		OperatorVersion: version,
+	}
+	if err = nodePoolReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "GPUNodePool")
 		os.Exit(1)
 	}
 
+	// Setup admission webhooks if enabled
+	if enableWebhooks {
+		if err = webhooks.NewGPUNodeClassValidator().SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "GPUNodeClass")
+			os.Exit(1)
+		}
+		if err = webhooks.NewGPUNodePoolDefaulter().SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "GPUNodePool")
+			os.Exit(1)
+		}
+		if err = webhooks.NewPodGPUDefaulter(mgr.GetClient()).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Pod")
+			os.Exit(1)
+		}
+	}
+
+	// Setup orphan node reaper, which sweeps while it is enabled in the configuration
+	if err = (&controllers.OrphanNodeReaper{
+		Client:            mgr.GetClient(),
+		Log:               ctrl.Log.WithName("controllers").WithName("OrphanNodeReaper"),
+		NodePools:         nodePoolReconciler,
+		OperatorNamespace: operatorNamespace,
+		Config:            operatorConfig,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create orphan node reaper")
+		os.Exit(1)
+	}
+
+	// Publish the cluster-wide GPU spend
+	if err = (&controllers.CostTracker{
+		Client:  mgr.GetClient(),
+		Log:     ctrl.Log.WithName("controllers").WithName("CostTracker"),
+		Metrics: operatorMetrics,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create cost tracker")
+		os.Exit(1)
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -127,9 +220,22 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if err := mgr.AddReadyzCheck("config", operatorConfig.ReadyCheck); err != nil {
+		setupLog.Error(err, "unable to set up config ready check")
+		os.Exit(1)
+	}
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	err = mgr.Start(ctrl.SetupSignalHandler())
+
+	// Settle the launches the stopped reconcilers left without a node, so they are not leaked
+	flushCtx, cancel := context.WithTimeout(context.Background(), shutdownFlushTimeout)
+	if flushErr := nodePoolReconciler.FlushInFlightLaunches(flushCtx, directClient, terminateOnShutdown); flushErr != nil {
+		setupLog.Error(flushErr, "failed to settle in-flight launches on shutdown")
+	}
+	cancel()
+
+	if err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
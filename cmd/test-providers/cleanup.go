@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// cleanupTimeout bounds listing and terminating instances, which waits on provider operations
+const cleanupTimeout = 10 * time.Minute
+
+// cleanupOrphans terminates the operator's instances at the provider that no node or
+// GPUNodePool in the cluster accounts for. Instances younger than minAge are skipped, as
+// their launch may not have been recorded yet. In dry-run mode the orphans are only listed.
+func cleanupOrphans(providerName string, providerClient providers.ProviderClient, region string, minAge time.Duration, dryRun bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), cleanupTimeout)
+	defer cancel()
+
+	kubeClient, err := newKubeClient()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	owned, err := ownedInstanceIDs(ctx, kubeClient, providerName)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	instances, err := providers.ListManagedInstances(ctx, providerClient, region)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	now := time.Now()
+	orphans := findOrphans(instances, owned, minAge, now)
+	fmt.Printf("Found %d operator instances, %d without a node or pool\n", len(instances), len(orphans))
+	for _, orphan := range orphans {
+		fmt.Printf("  %s  pool=%q  status=%s  age=%s\n",
+			orphan.ID, orphan.NodePool, orphan.Status, now.Sub(orphan.CreatedAt).Round(time.Minute))
+	}
+	if len(orphans) == 0 {
+		return
+	}
+
+	if dryRun {
+		fmt.Println("Dry run: no instances were terminated. Rerun with -dry-run=false to terminate them.")
+		return
+	}
+
+	ids := make([]string, len(orphans))
+	for i, orphan := range orphans {
+		ids[i] = orphan.ID
+	}
+	failures := providers.TerminateInstances(ctx, providerClient, ids)
+	for _, id := range ids {
+		if err, failed := failures[id]; failed {
+			fmt.Printf("Failed to terminate %s: %v\n", id, err)
+		} else {
+			fmt.Printf("Terminated %s\n", id)
+		}
+	}
+	if len(failures) > 0 {
+		os.Exit(1)
+	}
+}
+
+// newKubeClient creates an uncached client for the cluster of the current kubeconfig
+func newKubeClient() (client.Client, error) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := tgpv1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	config, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	return client.New(config, client.Options{Scheme: scheme})
+}
+
+// ownedInstanceIDs returns the IDs of the provider's instances that back a node or are
+// tracked in a GPUNodePool's status while their node joins
+func ownedInstanceIDs(ctx context.Context, c client.Client, providerName string) (map[string]bool, error) {
+	owned := make(map[string]bool)
+
+	var nodes corev1.NodeList
+	if err := c.List(ctx, &nodes, client.MatchingLabels{tgpv1.NodeLabelProvider: providerName}); err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	for _, node := range nodes.Items {
+		instanceID := node.Annotations["tgp.io/instance-id"]
+		if instanceID == "" {
+			instanceID = node.Labels["tgp.io/instance-id"]
+		}
+		if instanceID != "" {
+			owned[instanceID] = true
+		}
+	}
+
+	var pools tgpv1.GPUNodePoolList
+	if err := c.List(ctx, &pools); err != nil {
+		return nil, fmt.Errorf("failed to list GPU node pools: %w", err)
+	}
+	for _, pool := range pools.Items {
+		for _, instance := range pool.Status.Instances {
+			if instance.Provider == providerName {
+				owned[instance.InstanceID] = true
+			}
+		}
+	}
+
+	return owned, nil
+}
+
+// findOrphans returns the instances that are not owned and were created at least minAge ago,
+// oldest first
+func findOrphans(instances []providers.ManagedInstance, owned map[string]bool, minAge time.Duration, now time.Time) []providers.ManagedInstance {
+	var orphans []providers.ManagedInstance
+	for _, instance := range instances {
+		if owned[instance.ID] || now.Sub(instance.CreatedAt) < minAge {
+			continue
+		}
+		orphans = append(orphans, instance)
+	}
+	sort.SliceStable(orphans, func(i, j int) bool {
+		return orphans[i].CreatedAt.Before(orphans[j].CreatedAt)
+	})
+	return orphans
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+func TestFindOrphans(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = tgpv1.AddToScheme(scheme)
+
+	node := func(name, provider, instanceID string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{
+			tgpv1.NodeLabelProvider: provider,
+			"tgp.io/instance-id":    instanceID,
+		}}}
+	}
+	pool := &tgpv1.GPUNodePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "training", Namespace: "ml"},
+		Status: tgpv1.GPUNodePoolStatus{Instances: []tgpv1.PoolInstance{
+			{InstanceID: "joining", Provider: "aws"},
+			{InstanceID: "other-provider", Provider: "gcp"},
+		}},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		node("joined", "aws", "joined"),
+		node("gcp-node", "gcp", "gcp-node"),
+		pool,
+	).Build()
+
+	owned, err := ownedInstanceIDs(context.Background(), c, "aws")
+	if err != nil {
+		t.Fatalf("ownedInstanceIDs() error = %v", err)
+	}
+	if len(owned) != 2 || !owned["joined"] || !owned["joining"] {
+		t.Errorf("ownedInstanceIDs() = %v, want joined and joining", owned)
+	}
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	instance := func(id string, age time.Duration) providers.ManagedInstance {
+		return providers.ManagedInstance{GPUInstance: providers.GPUInstance{ID: id, CreatedAt: now.Add(-age)}}
+	}
+	orphans := findOrphans([]providers.ManagedInstance{
+		instance("joined", time.Hour),
+		instance("joining", time.Hour),
+		instance("leaked", time.Hour),
+		instance("older-leak", 2*time.Hour),
+		// Its launch may not be recorded yet
+		instance("launching", time.Minute),
+	}, owned, 15*time.Minute, now)
+
+	if len(orphans) != 2 || orphans[0].ID != "older-leak" || orphans[1].ID != "leaked" {
+		t.Errorf("findOrphans() = %+v, want older-leak and leaked", orphans)
+	}
+}
@@ -6,34 +6,46 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/solanyn/tgp-operator/pkg/providers"
+	"github.com/solanyn/tgp-operator/pkg/providers/factory"
 )
 
 func main() {
 	var (
 		provider = flag.String("provider", "", "Provider to test")
 		apiKey   = flag.String("api-key", "", "API key for the provider")
-		action   = flag.String("action", "list", "Action to perform (list, pricing, info)")
+		action   = flag.String("action", "list", "Action to perform (list, pricing, info, cleanup)")
 		gpuType  = flag.String("gpu-type", "", "GPU type to filter by")
 		region   = flag.String("region", "", "Region to filter by")
 		maxPrice = flag.Float64("max-price", 0, "Maximum price to filter by")
 		pretty   = flag.Bool("pretty", true, "Pretty print JSON output")
+		dryRun   = flag.Bool("dry-run", true, "Only list the orphaned instances cleanup would terminate")
+		minAge   = flag.Duration("min-age", 15*time.Minute, "Minimum age of instances cleanup considers orphaned")
 	)
 	flag.Parse()
 
 	if *provider == "" {
 		fmt.Println("Usage: go run cmd/test-providers/main.go -provider=<provider> -api-key=<key> [options]")
-		fmt.Println("Providers: (none currently available)")
-		fmt.Println("Actions: list, pricing, info")
+		fmt.Printf("Providers: %s\n", strings.Join(factory.Names(), ", "))
+		fmt.Println("Actions: list, pricing, info, cleanup")
 		flag.PrintDefaults()
 		os.Exit(1)
 	}
 
 	// Get API key from environment if not provided
 	if *apiKey == "" {
-		envVars := map[string]string{}
+		envVars := map[string]string{
+			"vultr":        "VULTR_API_KEY",
+			"gcp":          "GOOGLE_APPLICATION_CREDENTIALS_JSON",
+			"aws":          "AWS_CREDENTIALS_JSON",
+			"azure":        "AZURE_CREDENTIALS_JSON",
+			"coreweave":    "COREWEAVE_KUBECONFIG",
+			"digitalocean": "DIGITALOCEAN_TOKEN",
+			"oci":          "OCI_CREDENTIALS_JSON",
+		}
 		if envVar, ok := envVars[*provider]; ok {
 			*apiKey = os.Getenv(envVar)
 		}
@@ -45,10 +57,9 @@ func main() {
 	}
 
 	// Create provider client
-	var client providers.ProviderClient
-	switch *provider {
-	default:
-		fmt.Printf("Unknown provider: %s\n", *provider)
+	client, err := factory.NewClientFor(*provider, *apiKey)
+	if err != nil {
+		fmt.Printf("%v\n", err)
 		os.Exit(1)
 	}
 
@@ -64,6 +75,8 @@ func main() {
 		executeWithTimeout(func(ctx context.Context) {
 			testPricing(ctx, client, *gpuType, *region, *pretty)
 		})
+	case "cleanup":
+		cleanupOrphans(*provider, client, *region, *minAge, *dryRun)
 	default:
 		fmt.Printf("Unknown action: %s\n", *action)
 		os.Exit(1)
@@ -120,7 +133,7 @@ func testListGPUs(ctx context.Context, client providers.ProviderClient, gpuType,
 			if offer.SpotPrice > 0 {
 				fmt.Printf("Spot Price: $%.4f/hour\n", offer.SpotPrice)
 			}
-			fmt.Printf("Memory: %dGB\n", offer.Memory)
+			fmt.Printf("GPU memory: %dGiB\n", offer.GPUMemory)
 			fmt.Printf("Storage: %dGB\n", offer.Storage)
 			fmt.Printf("Available: %v\n", offer.Available)
 			fmt.Printf("Is Spot: %v\n", offer.IsSpot)
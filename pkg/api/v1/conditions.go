@@ -0,0 +1,120 @@
+package v1
+
+// Condition types reported in GPUNodeClass status
+const (
+	// ConditionTypeReady reports whether the resource is ready for use. It is reported by both
+	// GPUNodeClass and GPUNodePool.
+	ConditionTypeReady = "Ready"
+
+	// ConditionTypeProviderValidation reports a node class whose provider configuration is invalid
+	ConditionTypeProviderValidation = "ProviderValidation"
+
+	// ConditionTypeDeletionBlocked reports a node class whose deletion waits on the pools using it
+	ConditionTypeDeletionBlocked = "DeletionBlocked"
+
+	// ConditionTypeProvidersHealthy reports whether at least one of the node class's enabled
+	// providers has valid credentials and a recent inventory
+	ConditionTypeProvidersHealthy = "ProvidersHealthy"
+)
+
+// Condition types reported in GPUNodePool status
+const (
+	// ConditionTypeNodeClassReady reports whether the pool's GPUNodeClass exists
+	ConditionTypeNodeClassReady = "NodeClassReady"
+
+	// ConditionTypeProvisioning reports how provisioning for pending pods is going
+	ConditionTypeProvisioning = "Provisioning"
+
+	// ConditionTypePoolSelected reports why the pool was chosen to provision for its last pod
+	ConditionTypePoolSelected = "PoolSelected"
+
+	// ConditionTypeDryRun reports what a dry-run pool would have launched
+	ConditionTypeDryRun = "DryRun"
+
+	// ConditionTypeLimitExceeded reports a launch refused by the node class limits
+	ConditionTypeLimitExceeded = "LimitExceeded"
+
+	// ConditionTypeTerminationImminent reports nodes about to be terminated on expiry
+	ConditionTypeTerminationImminent = "TerminationImminent"
+)
+
+// ProviderConditionType returns the type of the condition reporting a provider's readiness in
+// GPUNodeClass status, such as "vultrReady"
+func ProviderConditionType(provider string) string {
+	return provider + "Ready"
+}
+
+// Reasons of the GPUNodeClass Ready, ProviderValidation and DeletionBlocked conditions
+const (
+	ReadyReasonValidationPassed          = "ValidationPassed"
+	ProviderValidationReasonFailed       = "ValidationFailed"
+	DeletionBlockedReasonActiveNodePools = "ActiveNodePools"
+)
+
+// Reasons of the per-provider readiness conditions in GPUNodeClass status
+const (
+	ProviderReasonReady               = "Ready"
+	ProviderReasonCredentialError     = "CredentialError"
+	ProviderReasonClientError         = "ClientError"
+	ProviderReasonAPIError            = "APIError"
+	ProviderReasonCircuitOpen         = "CircuitOpen"
+	ProviderReasonQualityPolicyNotMet = "QualityPolicyNotMet"
+	ProviderReasonDataResidencyNotMet = "DataResidencyNotMet"
+	ProviderReasonCPUPerGPUNotMet     = "CPUPerGPUNotMet"
+	ProviderReasonGPUMemoryNotMet     = "GPUMemoryNotMet"
+	ProviderReasonCUDAVersionNotMet   = "CUDAVersionNotMet"
+	ProviderReasonInstanceSizeNotMet  = "InstanceSizeNotMet"
+	ProviderReasonNoAllowedOffers     = "NoAllowedOffers"
+)
+
+// Reasons of the GPUNodeClass ProvidersHealthy condition. Degraded goes with a True status, as
+// some providers are still usable.
+const (
+	ProvidersHealthyReasonHealthy     = "AllProvidersHealthy"
+	ProvidersHealthyReasonDegraded    = "Degraded"
+	ProvidersHealthyReasonAllFailing  = "AllProvidersFailing"
+	ProvidersHealthyReasonNoProviders = "NoProvidersEnabled"
+)
+
+// Reasons of the GPUNodePool Ready and NodeClassReady conditions
+const (
+	ReadyReasonInitialized                = "Initialized"
+	ReadyReasonInvalidTemplate            = "InvalidTemplate"
+	ReadyReasonProvisioningFailed         = "ProvisioningFailed"
+	ReadyReasonGPUCountMismatch           = "GPUCountMismatch"
+	ReadyReasonMaxPendingDurationExceeded = "MaxPendingDurationExceeded"
+
+	NodeClassReadyReasonFound    = "NodeClassFound"
+	NodeClassReadyReasonNotFound = "NodeClassNotFound"
+)
+
+// Reasons of the GPUNodePool Provisioning condition
+const (
+	ProvisioningReasonRetrying = "Retrying"
+	ProvisioningReasonFailed   = "Failed"
+)
+
+// Reasons of the GPUNodePool PoolSelected condition
+const (
+	PoolSelectedReasonOnlyMatch     = "OnlyMatchingPool"
+	PoolSelectedReasonHighestWeight = "HighestWeight"
+	PoolSelectedReasonLowestPrice   = "LowestProjectedPrice"
+	PoolSelectedReasonTieBreak      = "NameTieBreak"
+)
+
+// Reasons of the GPUNodePool DryRun condition
+const (
+	DryRunReasonProviderSelected = "ProviderSelected"
+)
+
+// Reasons of the GPUNodePool LimitExceeded condition
+const (
+	LimitExceededReasonExceeded     = "LimitExceeded"
+	LimitExceededReasonWithinLimits = "WithinLimits"
+)
+
+// Reasons of the GPUNodePool TerminationImminent condition
+const (
+	TerminationImminentReasonExpiryApproaching   = "ExpiryApproaching"
+	TerminationImminentReasonNoExpiryApproaching = "NoExpiryApproaching"
+)
@@ -20,6 +20,11 @@ func init() { //nolint:gochecknoinits // Required for Kubernetes scheme registra
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Providers",type=string,JSONPath=`.status.conditions[?(@.type=="ProvidersHealthy")].reason`
+// +kubebuilder:printcolumn:name="Active Nodes",type=integer,JSONPath=`.status.activeNodes`
+// +kubebuilder:printcolumn:name="Total Cost",type=string,JSONPath=`.status.totalCost`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 type GPUNodeClass struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -44,6 +49,11 @@ type GPUNodeClassSpec struct {
 	// TalosConfig contains default Talos OS configuration
 	TalosConfig *TalosConfig `json:"talosConfig,omitempty"`
 
+	// TailscaleConfig configures how nodes join the tailnet. A new auth key is generated for
+	// every node and exposed to machine config templates as {{.TailscaleAuthKey}}.
+	// +optional
+	TailscaleConfig *TailscaleConfig `json:"tailscaleConfig,omitempty"`
+
 	// InstanceRequirements defines the instance constraints
 	InstanceRequirements *InstanceRequirements `json:"instanceRequirements,omitempty"`
 
@@ -53,8 +63,137 @@ type GPUNodeClassSpec struct {
 	// Tags are propagated to all instances created from this node class
 	// +optional
 	Tags map[string]string `json:"tags,omitempty"`
+
+	// QualityPolicy defines the minimum quality bar providers must meet.
+	// Providers that do not comply are excluded from inventory and provisioning.
+	// +optional
+	QualityPolicy *QualityPolicy `json:"qualityPolicy,omitempty"`
+
+	// ProviderSelection is the strategy used to choose between the providers able to launch a
+	// node. Cheapest picks the lowest price weighted by provider priority; Priority picks the
+	// provider with the lowest priority number, breaking ties on weighted price.
+	// Defaults to Cheapest.
+	// +kubebuilder:validation:Enum=Cheapest;Priority
+	// +optional
+	ProviderSelection string `json:"providerSelection,omitempty"`
+}
+
+// TailscaleConfig configures the Tailscale auth keys nodes join the tailnet with
+type TailscaleConfig struct {
+	// Tags are applied to the devices joining with the generated auth keys. Keys generated
+	// from OAuth credentials must be tagged.
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+
+	// Ephemeral removes nodes from the tailnet once they go offline
+	// +optional
+	Ephemeral bool `json:"ephemeral,omitempty"`
+
+	// AcceptRoutes is exposed to machine config templates as {{.TailscaleAcceptRoutes}}
+	// +optional
+	AcceptRoutes bool `json:"acceptRoutes,omitempty"`
+
+	// OAuthSecretRef references the OAuth client the operator uses to generate a
+	// single-use auth key for each node
+	// +optional
+	OAuthSecretRef *TailscaleOAuthSecretRef `json:"oauthSecretRef,omitempty"`
+
+	// AuthKeySecretRef references a pre-generated auth key shared by every node. It is only
+	// used when OAuthSecretRef is not set.
+	// Deprecated: use OAuthSecretRef so each node joins with its own short-lived key.
+	// +optional
+	AuthKeySecretRef *SecretKeyRef `json:"authKeySecretRef,omitempty"`
+
+	// Operator configures the resources created for the Tailscale Kubernetes operator
+	// +optional
+	Operator *TailscaleOperatorConfig `json:"operator,omitempty"`
+}
+
+// TailscaleOperatorConfig configures integration with the Tailscale Kubernetes operator
+type TailscaleOperatorConfig struct {
+	// ConnectorEnabled creates a Tailscale Connector for every node in pools of this class.
+	// Connectors are deleted with their node. Requires the Tailscale operator's Connector CRD.
+	// +optional
+	ConnectorEnabled *bool `json:"connectorEnabled,omitempty"`
+
+	// Connector is the spec of the Connectors created for nodes
+	// +optional
+	Connector *TailscaleConnectorSpec `json:"connector,omitempty"`
+}
+
+// TailscaleConnectorSpec mirrors the parts of the Tailscale Connector spec the operator manages
+type TailscaleConnectorSpec struct {
+	// SubnetRouter advertises routes to the tailnet through the Connector
+	// +optional
+	SubnetRouter *TailscaleSubnetRouter `json:"subnetRouter,omitempty"`
+
+	// ExitNode offers the Connector as an exit node
+	// +optional
+	ExitNode bool `json:"exitNode,omitempty"`
+
+	// AppConnector runs the Connector as an app connector
+	// +optional
+	AppConnector *TailscaleAppConnector `json:"appConnector,omitempty"`
+}
+
+// TailscaleSubnetRouter configures a Connector as a subnet router
+type TailscaleSubnetRouter struct {
+	// AdvertiseRoutes are the CIDRs advertised to the tailnet
+	// +kubebuilder:validation:MinItems=1
+	AdvertiseRoutes []string `json:"advertiseRoutes"`
+}
+
+// TailscaleAppConnector configures a Connector as an app connector
+type TailscaleAppConnector struct {
+	// Routes are CIDRs pre-advertised by the app connector
+	// +optional
+	Routes []string `json:"routes,omitempty"`
 }
 
+// TailscaleOAuthSecretRef references a secret holding Tailscale OAuth client credentials
+type TailscaleOAuthSecretRef struct {
+	// Name is the name of the secret
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the secret (optional, defaults to the default namespace)
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// ClientIDKey is the key holding the OAuth client ID
+	// +kubebuilder:default=client-id
+	// +optional
+	ClientIDKey string `json:"clientIdKey,omitempty"`
+
+	// ClientSecretKey is the key holding the OAuth client secret
+	// +kubebuilder:default=client-secret
+	// +optional
+	ClientSecretKey string `json:"clientSecretKey,omitempty"`
+}
+
+// QualityPolicy defines the minimum reliability required of providers and their offers
+type QualityPolicy struct {
+	// MinReliabilityTier excludes providers below this reliability tier
+	// +kubebuilder:validation:Enum=Community;Verified;Enterprise
+	// +optional
+	MinReliabilityTier ReliabilityTier `json:"minReliabilityTier,omitempty"`
+
+	// VerifiedOnly excludes offers on hosts that have not been vetted by the provider
+	// +optional
+	VerifiedOnly bool `json:"verifiedOnly,omitempty"`
+}
+
+// ReliabilityTier describes how reliable a provider's capacity is
+type ReliabilityTier string
+
+const (
+	// ReliabilityTierCommunity is capacity on unvetted marketplace hosts
+	ReliabilityTierCommunity ReliabilityTier = "Community"
+	// ReliabilityTierVerified is marketplace capacity on hosts vetted by the provider
+	ReliabilityTierVerified ReliabilityTier = "Verified"
+	// ReliabilityTierEnterprise is capacity in provider-operated datacenters
+	ReliabilityTierEnterprise ReliabilityTier = "Enterprise"
+)
+
 // GPUNodeClassStatus defines the observed state of GPUNodeClass
 type GPUNodeClassStatus struct {
 	// Conditions represent the latest available observations of the node class's state
@@ -84,6 +223,10 @@ type GPUNodeClassStatus struct {
 	// NextInventoryUpdate is when the next inventory refresh is scheduled
 	// +optional
 	NextInventoryUpdate *metav1.Time `json:"nextInventoryUpdate,omitempty"`
+
+	// LastRequeueReason records why the most recent reconcile was requeued
+	// +optional
+	LastRequeueReason string `json:"lastRequeueReason,omitempty"`
 }
 
 // ProviderStatus contains status information for a cloud provider
@@ -106,6 +249,18 @@ type ProviderStatus struct {
 	// InventoryEnabled indicates whether this provider is actively being used for inventory
 	// +optional
 	InventoryEnabled bool `json:"inventoryEnabled,omitempty"`
+
+	// Excluded indicates the provider does not meet the class quality policy
+	// +optional
+	Excluded bool `json:"excluded,omitempty"`
+
+	// ExclusionReason explains why the provider was excluded
+	// +optional
+	ExclusionReason string `json:"exclusionReason,omitempty"`
+
+	// DisabledFeatures lists provider operations disabled in the operator configuration
+	// +optional
+	DisabledFeatures []string `json:"disabledFeatures,omitempty"`
 }
 
 // GPUAvailability represents available GPU instances from a provider
@@ -133,13 +288,43 @@ type GPUAvailability struct {
 	// +optional
 	SpotPrice *string `json:"spotPrice,omitempty"`
 
+	// PriceTrend summarizes the hourly prices observed for this GPU type in the region
+	// PricePerHour was taken from, over the operator's price history window
+	// +optional
+	PriceTrend *PriceTrend `json:"priceTrend,omitempty"`
+
 	// LastUpdated is when this data was retrieved
 	LastUpdated metav1.Time `json:"lastUpdated"`
 }
 
+// PriceTrend summarizes recently observed prices (as strings to avoid float precision issues)
+type PriceTrend struct {
+	// Min is the lowest hourly price observed in the window
+	Min string `json:"min"`
+
+	// Max is the highest hourly price observed in the window
+	Max string `json:"max"`
+
+	// Average is the mean hourly price observed in the window
+	Average string `json:"average"`
+
+	// Samples is the number of price observations in the window
+	Samples int32 `json:"samples"`
+
+	// Window is how far back prices are retained
+	Window metav1.Duration `json:"window"`
+}
+
 // GPUNodePool defines provisioning pools that reference GPUNodeClass templates
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Node Class",type=string,JSONPath=`.spec.nodeClassRef.name`
+// +kubebuilder:printcolumn:name="Nodes",type=integer,JSONPath=`.status.nodeCount`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Spot",type=integer,JSONPath=`.status.spotInstances`,priority=1
+// +kubebuilder:printcolumn:name="On-Demand",type=integer,JSONPath=`.status.onDemandInstances`,priority=1
+// +kubebuilder:printcolumn:name="Spot Savings",type=string,JSONPath=`.status.estimatedSpotSavings`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 type GPUNodePool struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -180,8 +365,80 @@ type GPUNodePoolSpec struct {
 	// Higher weights are preferred. Defaults to 10.
 	// +optional
 	Weight *int32 `json:"weight,omitempty"`
+
+	// Spot controls whether nodes in this pool use spot (interruptible) capacity.
+	// Preferred compares the best spot and on-demand prices across providers and
+	// picks whichever is cheaper. Defaults to Never.
+	// +kubebuilder:validation:Enum=Required;Preferred;Never
+	// +optional
+	Spot SpotPolicy `json:"spot,omitempty"`
+
+	// SpotInterruptionPremium is the percentage added to spot prices to account for
+	// interruption risk when comparing them against on-demand prices. Defaults to 20.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SpotInterruptionPremium *int32 `json:"spotInterruptionPremium,omitempty"`
+
+	// Account overrides the provider account or project nodes are launched into.
+	// For GCP this is the project ID, replacing the project from the service account key.
+	// It must be listed in the node class provider's allowedAccounts.
+	// +optional
+	Account string `json:"account,omitempty"`
+
+	// MaxPendingDuration is how long provisioning may keep failing for pending pods before
+	// the pool reports MaxPendingDurationExceeded and stops retrying rapidly.
+	// Provisioning retries indefinitely when unset.
+	// +optional
+	MaxPendingDuration *metav1.Duration `json:"maxPendingDuration,omitempty"`
+
+	// ExpectedDuration is how long nodes in this pool are expected to run, such as the length
+	// of the jobs they serve. Providers are then compared by the cost of running a node this
+	// long, rounded up to each provider's billing increment and minimum billing period, so
+	// short jobs prefer per-second billing over a lower hourly price billed by the hour.
+	// Providers are compared by hourly price when unset.
+	// +optional
+	ExpectedDuration *metav1.Duration `json:"expectedDuration,omitempty"`
+
+	// DryRun selects a provider and prices the capacity for pending pods without launching
+	// instances. The selection is recorded in status.dryRunSelection and the DryRun condition,
+	// so pool and node class configurations can be validated without paying for nodes.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// ReadinessProbe adds a check a launched node must pass, besides its kubelet reporting
+	// Ready, before it is made schedulable and its instance is reported Ready
+	// +optional
+	ReadinessProbe *NodeReadinessProbe `json:"readinessProbe,omitempty"`
 }
 
+// NodeReadinessProbe checks that a launched node serves traffic before it is made schedulable.
+// The probe targets the node's external address, falling back to its internal address.
+type NodeReadinessProbe struct {
+	// Port is the port probed on the node. Without HTTPPath, the probe succeeds once the
+	// port accepts a TCP connection.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+
+	// HTTPPath makes the probe an HTTP GET of this path, succeeding on a 2xx or 3xx response
+	// +optional
+	HTTPPath string `json:"httpPath,omitempty"`
+
+	// Timeout bounds each probe attempt. Defaults to 5s; longer timeouts are capped at 10s,
+	// as probes run in the pool's reconcile loop.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// SpotPolicy defines how spot capacity is used when provisioning nodes
+type SpotPolicy string
+
+const (
+	SpotPolicyRequired  SpotPolicy = "Required"
+	SpotPolicyPreferred SpotPolicy = "Preferred"
+	SpotPolicyNever     SpotPolicy = "Never"
+)
+
 // GPUNodePoolStatus defines the observed state of GPUNodePool
 type GPUNodePoolStatus struct {
 	// Conditions represent the latest available observations of the pool's state
@@ -195,8 +452,198 @@ type GPUNodePoolStatus struct {
 	// NodeCount is the current number of nodes in this pool
 	// +optional
 	NodeCount int32 `json:"nodeCount,omitempty"`
+
+	// LastRequeueReason records why the most recent reconcile was requeued
+	// +optional
+	LastRequeueReason string `json:"lastRequeueReason,omitempty"`
+
+	// PendingSince is when provisioning for pending pods started failing.
+	// It is cleared once provisioning succeeds or no pods are pending.
+	// +optional
+	PendingSince *metav1.Time `json:"pendingSince,omitempty"`
+
+	// ProvisioningAttempts is the number of consecutive failed provisioning attempts.
+	// It is reset once provisioning succeeds.
+	// +optional
+	ProvisioningAttempts int32 `json:"provisioningAttempts,omitempty"`
+
+	// LastProvisioningFailure is when the most recent provisioning attempt failed
+	// +optional
+	LastProvisioningFailure *metav1.Time `json:"lastProvisioningFailure,omitempty"`
+
+	// LastTermination records the most recent instance terminated by this pool and why
+	// +optional
+	LastTermination *InstanceTermination `json:"lastTermination,omitempty"`
+
+	// DryRunSelection records the capacity a dry-run pool would have launched most recently
+	// +optional
+	DryRunSelection *DryRunSelection `json:"dryRunSelection,omitempty"`
+
+	// TerminationScheduledAt is when the pool's next node reaches ExpireAfter or the
+	// template's MaxLifetime and is terminated. It is unset when the pool has no nodes
+	// or does not expire them.
+	// +optional
+	TerminationScheduledAt *metav1.Time `json:"terminationScheduledAt,omitempty"`
+
+	// SpotInstances is the number of the pool's tracked instances running on spot capacity
+	// +optional
+	SpotInstances int32 `json:"spotInstances,omitempty"`
+
+	// OnDemandInstances is the number of the pool's tracked instances running on on-demand capacity
+	// +optional
+	OnDemandInstances int32 `json:"onDemandInstances,omitempty"`
+
+	// EstimatedSpotSavings is the estimated USD saved by running the pool's spot instances
+	// instead of on-demand, accrued by its running instances and those already removed
+	// +optional
+	EstimatedSpotSavings string `json:"estimatedSpotSavings,omitempty"`
+
+	// RealizedSpotSavings is the part of EstimatedSpotSavings accrued by instances that have
+	// since been deleted, interrupted or otherwise removed
+	// +optional
+	RealizedSpotSavings string `json:"realizedSpotSavings,omitempty"`
+
+	// Instances tracks the instances launched by this pool, so provisioning in flight
+	// resumes after a controller restart instead of launching again
+	// +optional
+	// +listType=map
+	// +listMapKey=instanceID
+	Instances []PoolInstance `json:"instances,omitempty"`
+}
+
+// PoolInstance records an instance launched by a pool and how far it has progressed
+type PoolInstance struct {
+	// InstanceID is the provider's ID of the instance
+	InstanceID string `json:"instanceID"`
+
+	// Provider is the provider the instance runs on
+	Provider string `json:"provider"`
+
+	// Phase is how far the instance has progressed towards a schedulable node
+	Phase PoolInstancePhase `json:"phase"`
+
+	// NodeName is the Kubernetes node backed by the instance, once it exists
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+
+	// Pod is the namespace/name of the pending pod the instance was launched for
+	// +optional
+	Pod string `json:"pod,omitempty"`
+
+	// GPUType is the GPU type the instance was launched with
+	GPUType string `json:"gpuType"`
+
+	// GPUCount is the number of GPUs the instance was launched with
+	// +optional
+	GPUCount int32 `json:"gpuCount,omitempty"`
+
+	// Region is the region requested for the instance, if any
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Spot reports whether the instance runs on spot capacity
+	// +optional
+	Spot bool `json:"spot,omitempty"`
+
+	// PricePerHour is the estimated hourly price of the instance in USD, if known
+	// +optional
+	PricePerHour string `json:"pricePerHour,omitempty"`
+
+	// SpotHourlySavings is the estimated hourly USD saved by running the instance on spot
+	// capacity instead of on-demand, if known
+	// +optional
+	SpotHourlySavings string `json:"spotHourlySavings,omitempty"`
+
+	// LaunchedAt is when the instance was launched
+	LaunchedAt metav1.Time `json:"launchedAt"`
 }
 
+// PoolInstancePhase describes how far a launched instance has progressed
+// +kubebuilder:validation:Enum=Launched;Registered;Ready
+type PoolInstancePhase string
+
+const (
+	// PoolInstancePhaseLaunched is an instance whose node has not been created yet
+	PoolInstancePhaseLaunched PoolInstancePhase = "Launched"
+	// PoolInstancePhaseRegistered is an instance whose node exists but is not yet schedulable
+	PoolInstancePhaseRegistered PoolInstancePhase = "Registered"
+	// PoolInstancePhaseReady is an instance whose node is Ready and schedulable
+	PoolInstancePhaseReady PoolInstancePhase = "Ready"
+)
+
+// DryRunSelection records the provider and capacity selected for a pod by a dry-run pool
+type DryRunSelection struct {
+	// Pod is the namespace/name of the pending pod the capacity was selected for
+	Pod string `json:"pod"`
+
+	// Provider is the provider that would have launched the instance
+	Provider string `json:"provider"`
+
+	// GPUType is the GPU type that would have been launched, after any fallback
+	GPUType string `json:"gpuType"`
+
+	// GPUCount is the number of GPUs the instance would have had
+	GPUCount int32 `json:"gpuCount"`
+
+	// Region is the region requested for the instance, if any
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Spot reports whether spot capacity was selected
+	// +optional
+	Spot bool `json:"spot,omitempty"`
+
+	// PricePerHour is the projected hourly cost in USD (as string to avoid float precision issues).
+	// It is empty when the provider could not price the capacity.
+	// +optional
+	PricePerHour string `json:"pricePerHour,omitempty"`
+
+	// Time is when the selection was made
+	Time metav1.Time `json:"time"`
+}
+
+// InstanceTermination records why an instance was terminated
+type InstanceTermination struct {
+	// InstanceID is the provider's ID of the terminated instance
+	InstanceID string `json:"instanceID"`
+
+	// Provider is the provider the instance ran on
+	Provider string `json:"provider"`
+
+	// NodeName is the Kubernetes node backed by the instance, if one was created
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+
+	// Reason is why the instance was terminated
+	Reason TerminationReason `json:"reason"`
+
+	// Time is when the instance was terminated
+	Time metav1.Time `json:"time"`
+}
+
+// TerminationReason describes why an instance was terminated
+// +kubebuilder:validation:Enum=Expired;PoolDeleted;Orphaned;LaunchFailed;Consolidated;Idle;Interrupted;MaxLifetime
+type TerminationReason string
+
+const (
+	// TerminationReasonExpired is an instance that reached the pool's ExpireAfter
+	TerminationReasonExpired TerminationReason = "Expired"
+	// TerminationReasonPoolDeleted is an instance removed because its pool was deleted
+	TerminationReasonPoolDeleted TerminationReason = "PoolDeleted"
+	// TerminationReasonOrphaned is an instance whose pool no longer exists
+	TerminationReasonOrphaned TerminationReason = "Orphaned"
+	// TerminationReasonLaunchFailed is an instance cleaned up after its node could not be registered
+	TerminationReasonLaunchFailed TerminationReason = "LaunchFailed"
+	// TerminationReasonConsolidated is an underutilized instance whose workloads fit on the pool's other nodes
+	TerminationReasonConsolidated TerminationReason = "Consolidated"
+	// TerminationReasonIdle is an instance that ran no GPU workloads for the pool's ConsolidateAfter
+	TerminationReasonIdle TerminationReason = "Idle"
+	// TerminationReasonInterrupted is an instance the provider terminated or failed, such as a reclaimed spot instance
+	TerminationReasonInterrupted TerminationReason = "Interrupted"
+	// TerminationReasonMaxLifetime is an instance that outlived the pool template's MaxLifetime
+	TerminationReasonMaxLifetime TerminationReason = "MaxLifetime"
+)
+
 // NodeClassReference is a reference to a GPUNodeClass
 type NodeClassReference struct {
 	// Group of the referent
@@ -243,6 +690,33 @@ type NodeSpec struct {
 	// StartupTaints are applied to nodes during startup and removed once ready
 	// +optional
 	StartupTaints []corev1.Taint `json:"startupTaints,omitempty"`
+
+	// DataDisks are existing provider volumes attached to nodes at launch,
+	// e.g. disks holding pre-populated datasets. Providers that cannot attach them
+	// at launch attach them once the instance is running, and the node is made
+	// schedulable only after they are attached.
+	// +optional
+	DataDisks []DataDisk `json:"dataDisks,omitempty"`
+
+	// MaxLifetime caps how long a node may run. Nodes reaching it go through the same cordon
+	// and drain as Disruption.ExpireAfter, but are terminated one at a time and not while the
+	// pool is failing to provision capacity for pending pods.
+	// +optional
+	MaxLifetime *metav1.Duration `json:"maxLifetime,omitempty"`
+}
+
+// DataDisk references an existing provider volume to attach to a node
+type DataDisk struct {
+	// VolumeID is the provider-specific volume identifier (e.g. GCP disk name or self link)
+	VolumeID string `json:"volumeID"`
+
+	// ReadOnly attaches the volume read-only so it can be shared by multiple nodes
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
+
+	// DeviceName is an optional device name hint for the attached volume
+	// +optional
+	DeviceName string `json:"deviceName,omitempty"`
 }
 
 // NodeSelectorRequirement contains values, a key, and an operator
@@ -277,6 +751,16 @@ const (
 	NodeLabelRegion      = "tgp.io/region"
 	NodeLabelSpot        = "tgp.io/spot"
 	NodeLabelProvisioned = "tgp.io/provisioned"
+
+	// NodeLabelNodePool records the GPUNodePool that launched the node. Launches carry it as an
+	// instance label or tag too.
+	NodeLabelNodePool = "tgp.io/nodepool"
+
+	// NodeLabelNodePoolNamespace records the namespace of the GPUNodePool that launched the node
+	NodeLabelNodePoolNamespace = "tgp.io/nodepool-namespace"
+
+	// NodeLabelNodeClass records the GPUNodeClass the node was launched from
+	NodeLabelNodeClass = "tgp.io/nodeclass"
 )
 
 // ProviderConfig defines configuration for a cloud provider
@@ -302,33 +786,80 @@ type ProviderConfig struct {
 	// Regions specifies the allowed regions for this provider
 	// +optional
 	Regions []string `json:"regions,omitempty"`
+
+	// AllowedAccounts lists the accounts or projects node pools may select with spec.account
+	// +optional
+	AllowedAccounts []string `json:"allowedAccounts,omitempty"`
 }
 
 // InstanceRequirements defines constraints for instance selection
 type InstanceRequirements struct {
-	// GPUTypes lists the allowed GPU types
+	// GPUTypes lists the allowed GPU types. Pools using the class never provision other types,
+	// fallbacks included. Empty allows any.
 	// +optional
 	GPUTypes []string `json:"gpuTypes,omitempty"`
 
-	// Regions lists the preferred regions
+	// FallbackGPUTypes lists GPU types to try, in order, when no provider has capacity
+	// for the requested GPU type. Pods that pin a GPU type with a node selector only
+	// receive fallbacks they can still schedule onto.
+	// +optional
+	FallbackGPUTypes []string `json:"fallbackGPUTypes,omitempty"`
+
+	// Regions lists the allowed regions. Pool regions outside it are skipped, and when neither
+	// the pod nor the pool picks a region these are tried in order. Empty allows any.
 	// +optional
 	Regions []string `json:"regions,omitempty"`
 
-	// SpotAllowed indicates whether spot instances are allowed
+	// SpotAllowed indicates whether spot instances are allowed. When false, pools using the
+	// class launch on-demand instances whatever their spot policy.
 	// +optional
 	SpotAllowed *bool `json:"spotAllowed,omitempty"`
 
-	// MinVCPU specifies the minimum number of vCPUs
+	// MinVCPU excludes offers with fewer vCPUs. Offers whose vCPU count is unknown are excluded.
 	// +optional
 	MinVCPU *int32 `json:"minVCPU,omitempty"`
 
-	// MinMemoryGiB specifies the minimum memory in GiB
+	// MinMemoryGiB excludes offers with less system memory in GiB. Offers whose memory is
+	// unknown are excluded.
 	// +optional
 	MinMemoryGiB *int32 `json:"minMemoryGiB,omitempty"`
 
-	// MinGPUMemoryGiB specifies the minimum GPU memory in GiB
+	// MinGPUMemoryGiB excludes offers with less than this much memory (VRAM) on
+	// each GPU. System memory does not count towards it, and offers whose GPU
+	// memory is unknown are excluded.
+	// +kubebuilder:validation:Minimum=1
 	// +optional
 	MinGPUMemoryGiB *int32 `json:"minGPUMemoryGiB,omitempty"`
+
+	// MinVCPUPerGPU excludes offers with fewer vCPUs than this for each GPU, so
+	// data-loading-bound workloads do not land on CPU-starved hosts. Offers whose
+	// vCPU or GPU count is unknown are excluded.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinVCPUPerGPU *int32 `json:"minVCPUPerGPU,omitempty"`
+
+	// MinCUDAVersion excludes offers whose host driver supports only older CUDA versions,
+	// given as major.minor (e.g., "12.1"). Offers that do not report the driver of their host
+	// get it from the node image and are kept. Like the other offer requirements, it excludes
+	// providers whose inventory is disabled, as their offers cannot be checked.
+	// +kubebuilder:validation:Pattern=`^[0-9]+\.[0-9]+$`
+	// +optional
+	MinCUDAVersion string `json:"minCUDAVersion,omitempty"`
+
+	// Countries restricts nodes to regions in these countries, for data residency.
+	// Values are ISO 3166-1 alpha-2 codes (e.g., "DE", "FR"); offers and regions
+	// whose country is unknown are excluded.
+	// +kubebuilder:validation:items:Pattern=`^[A-Z]{2}$`
+	// +optional
+	Countries []string `json:"countries,omitempty"`
+
+	// BootDiskGiB sets the size of each node's boot disk in GiB. Providers use their own
+	// default when unset and reject sizes below their minimum; providers whose plans come
+	// with a fixed disk fail launches whose plan has less.
+	// +kubebuilder:validation:Minimum=10
+	// +kubebuilder:validation:Maximum=65536
+	// +optional
+	BootDiskGiB *int32 `json:"bootDiskGiB,omitempty"`
 }
 
 // NodeClassLimits defines limits for a GPUNodeClass
@@ -355,17 +886,31 @@ type NodePoolLimits struct {
 
 // DisruptionSpec defines the disruption policy for nodes
 type DisruptionSpec struct {
-	// ConsolidationPolicy describes when nodes should be consolidated
+	// ConsolidationPolicy describes when nodes should be consolidated. Defaults to WhenIdle.
 	// +optional
 	ConsolidationPolicy ConsolidationPolicy `json:"consolidationPolicy,omitempty"`
 
-	// ConsolidateAfter is the duration after which empty nodes should be consolidated
+	// ConsolidateAfter is how long a node must stay idle or underutilized before it is consolidated.
+	// With WhenIdle, a node is idle when no workload pods requesting GPUs run on it. With
+	// WhenUnderutilized, a node is underutilized when its workload pods fit on the GPUs left
+	// free on the pool's other nodes. Defaults to 5m.
 	// +optional
 	ConsolidateAfter *metav1.Duration `json:"consolidateAfter,omitempty"`
 
 	// ExpireAfter is the duration after which nodes should be expired regardless of utilization
 	// +optional
 	ExpireAfter *metav1.Duration `json:"expireAfter,omitempty"`
+
+	// ExpireGracePeriod is how long before ExpireAfter or MaxLifetime a node is cordoned so
+	// running workloads can complete before it is drained and terminated. Defaults to 1h.
+	// +optional
+	ExpireGracePeriod *metav1.Duration `json:"expireGracePeriod,omitempty"`
+
+	// TerminationWarningPeriod is how long before ExpireAfter or MaxLifetime the pool
+	// reports the TerminationImminent condition and emits an event for the node, so
+	// workloads can checkpoint before it is terminated. Defaults to 10m.
+	// +optional
+	TerminationWarningPeriod *metav1.Duration `json:"terminationWarningPeriod,omitempty"`
 }
 
 // ConsolidationPolicy defines when nodes should be consolidated
@@ -390,6 +935,41 @@ type TalosConfig struct {
 	// KubeletImage specifies the kubelet image to use (defaults to GPU-optimized image)
 	// +optional
 	KubeletImage string `json:"kubeletImage,omitempty"`
+
+	// ExtraFiles are merged into machine.files of the rendered machine config, such as registry
+	// mirrors or monitoring agent configuration. An extra file replaces any file the template
+	// writes to the same path.
+	// +optional
+	// +listType=map
+	// +listMapKey=path
+	ExtraFiles []TalosFile `json:"extraFiles,omitempty"`
+
+	// Env is merged into machine.env of the rendered machine config, overriding any variable of
+	// the same name the template sets
+	// +optional
+	Env map[string]string `json:"env,omitempty"`
+}
+
+// TalosFile is a file Talos writes to the node's filesystem at boot
+type TalosFile struct {
+	// Path is the absolute path of the file on the node
+	// +kubebuilder:validation:Pattern=`^/`
+	Path string `json:"path"`
+
+	// Content is the content written to the file
+	Content string `json:"content"`
+
+	// Permissions is the file mode, such as 420 for 0644. Defaults to 0644.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=511
+	// +optional
+	Permissions *int32 `json:"permissions,omitempty"`
+
+	// Op is how the content is written: create a new file, append to an existing one or
+	// overwrite it. Defaults to create.
+	// +kubebuilder:validation:Enum=create;append;overwrite
+	// +optional
+	Op string `json:"op,omitempty"`
 }
 
 // SecretKeyRef references a specific key in a Kubernetes secret
@@ -404,6 +984,25 @@ type SecretKeyRef struct {
 	Namespace string `json:"namespace,omitempty"`
 }
 
+// GetConnectorEnabled returns whether a Tailscale Connector is created for each node
+func (tc *TailscaleConfig) GetConnectorEnabled() bool {
+	if tc == nil || tc.Operator == nil || tc.Operator.ConnectorEnabled == nil {
+		return false
+	}
+	return *tc.Operator.ConnectorEnabled
+}
+
+// DefaultNodePoolWeight is the weight of GPUNodePools that do not set one
+const DefaultNodePoolWeight int32 = 10
+
+// GetWeight returns the pool's weight, defaulting to DefaultNodePoolWeight
+func (s *GPUNodePoolSpec) GetWeight() int32 {
+	if s.Weight == nil {
+		return DefaultNodePoolWeight
+	}
+	return *s.Weight
+}
+
 // TalosConfig helper methods
 
 // GetNetworkingBackend returns the networking backend being used
@@ -10,6 +10,21 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataDisk) DeepCopyInto(out *DataDisk) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataDisk.
+func (in *DataDisk) DeepCopy() *DataDisk {
+	if in == nil {
+		return nil
+	}
+	out := new(DataDisk)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DisruptionSpec) DeepCopyInto(out *DisruptionSpec) {
 	*out = *in
@@ -23,6 +38,16 @@ func (in *DisruptionSpec) DeepCopyInto(out *DisruptionSpec) {
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.ExpireGracePeriod != nil {
+		in, out := &in.ExpireGracePeriod, &out.ExpireGracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TerminationWarningPeriod != nil {
+		in, out := &in.TerminationWarningPeriod, &out.TerminationWarningPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisruptionSpec.
@@ -35,6 +60,22 @@ func (in *DisruptionSpec) DeepCopy() *DisruptionSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DryRunSelection) DeepCopyInto(out *DryRunSelection) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunSelection.
+func (in *DryRunSelection) DeepCopy() *DryRunSelection {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRunSelection)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GPUAvailability) DeepCopyInto(out *GPUAvailability) {
 	*out = *in
@@ -53,6 +94,11 @@ func (in *GPUAvailability) DeepCopyInto(out *GPUAvailability) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.PriceTrend != nil {
+		in, out := &in.PriceTrend, &out.PriceTrend
+		*out = new(PriceTrend)
+		**out = **in
+	}
 	in.LastUpdated.DeepCopyInto(&out.LastUpdated)
 }
 
@@ -140,6 +186,11 @@ func (in *GPUNodeClassSpec) DeepCopyInto(out *GPUNodeClassSpec) {
 		*out = new(TalosConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TailscaleConfig != nil {
+		in, out := &in.TailscaleConfig, &out.TailscaleConfig
+		*out = new(TailscaleConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.InstanceRequirements != nil {
 		in, out := &in.InstanceRequirements, &out.InstanceRequirements
 		*out = new(InstanceRequirements)
@@ -157,6 +208,11 @@ func (in *GPUNodeClassSpec) DeepCopyInto(out *GPUNodeClassSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.QualityPolicy != nil {
+		in, out := &in.QualityPolicy, &out.QualityPolicy
+		*out = new(QualityPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUNodeClassSpec.
@@ -308,6 +364,26 @@ func (in *GPUNodePoolSpec) DeepCopyInto(out *GPUNodePoolSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.SpotInterruptionPremium != nil {
+		in, out := &in.SpotInterruptionPremium, &out.SpotInterruptionPremium
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxPendingDuration != nil {
+		in, out := &in.MaxPendingDuration, &out.MaxPendingDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ExpectedDuration != nil {
+		in, out := &in.ExpectedDuration, &out.ExpectedDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(NodeReadinessProbe)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUNodePoolSpec.
@@ -337,6 +413,35 @@ func (in *GPUNodePoolStatus) DeepCopyInto(out *GPUNodePoolStatus) {
 			(*out)[key] = val.DeepCopy()
 		}
 	}
+	if in.PendingSince != nil {
+		in, out := &in.PendingSince, &out.PendingSince
+		*out = (*in).DeepCopy()
+	}
+	if in.LastProvisioningFailure != nil {
+		in, out := &in.LastProvisioningFailure, &out.LastProvisioningFailure
+		*out = (*in).DeepCopy()
+	}
+	if in.LastTermination != nil {
+		in, out := &in.LastTermination, &out.LastTermination
+		*out = new(InstanceTermination)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DryRunSelection != nil {
+		in, out := &in.DryRunSelection, &out.DryRunSelection
+		*out = new(DryRunSelection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TerminationScheduledAt != nil {
+		in, out := &in.TerminationScheduledAt, &out.TerminationScheduledAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]PoolInstance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUNodePoolStatus.
@@ -357,6 +462,11 @@ func (in *InstanceRequirements) DeepCopyInto(out *InstanceRequirements) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.FallbackGPUTypes != nil {
+		in, out := &in.FallbackGPUTypes, &out.FallbackGPUTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Regions != nil {
 		in, out := &in.Regions, &out.Regions
 		*out = make([]string, len(*in))
@@ -382,6 +492,21 @@ func (in *InstanceRequirements) DeepCopyInto(out *InstanceRequirements) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.MinVCPUPerGPU != nil {
+		in, out := &in.MinVCPUPerGPU, &out.MinVCPUPerGPU
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Countries != nil {
+		in, out := &in.Countries, &out.Countries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BootDiskGiB != nil {
+		in, out := &in.BootDiskGiB, &out.BootDiskGiB
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceRequirements.
@@ -394,6 +519,22 @@ func (in *InstanceRequirements) DeepCopy() *InstanceRequirements {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceTermination) DeepCopyInto(out *InstanceTermination) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceTermination.
+func (in *InstanceTermination) DeepCopy() *InstanceTermination {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceTermination)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeClassLimits) DeepCopyInto(out *NodeClassLimits) {
 	*out = *in
@@ -513,6 +654,26 @@ func (in *NodePoolTemplate) DeepCopy() *NodePoolTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeReadinessProbe) DeepCopyInto(out *NodeReadinessProbe) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeReadinessProbe.
+func (in *NodeReadinessProbe) DeepCopy() *NodeReadinessProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeReadinessProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeSelectorRequirement) DeepCopyInto(out *NodeSelectorRequirement) {
 	*out = *in
@@ -557,6 +718,16 @@ func (in *NodeSpec) DeepCopyInto(out *NodeSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DataDisks != nil {
+		in, out := &in.DataDisks, &out.DataDisks
+		*out = make([]DataDisk, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxLifetime != nil {
+		in, out := &in.MaxLifetime, &out.MaxLifetime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSpec.
@@ -569,6 +740,38 @@ func (in *NodeSpec) DeepCopy() *NodeSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolInstance) DeepCopyInto(out *PoolInstance) {
+	*out = *in
+	in.LaunchedAt.DeepCopyInto(&out.LaunchedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolInstance.
+func (in *PoolInstance) DeepCopy() *PoolInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PriceTrend) DeepCopyInto(out *PriceTrend) {
+	*out = *in
+	out.Window = in.Window
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PriceTrend.
+func (in *PriceTrend) DeepCopy() *PriceTrend {
+	if in == nil {
+		return nil
+	}
+	out := new(PriceTrend)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProviderConfig) DeepCopyInto(out *ProviderConfig) {
 	*out = *in
@@ -588,6 +791,11 @@ func (in *ProviderConfig) DeepCopyInto(out *ProviderConfig) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.AllowedAccounts != nil {
+		in, out := &in.AllowedAccounts, &out.AllowedAccounts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderConfig.
@@ -611,6 +819,11 @@ func (in *ProviderStatus) DeepCopyInto(out *ProviderStatus) {
 		in, out := &in.LastPricingUpdate, &out.LastPricingUpdate
 		*out = (*in).DeepCopy()
 	}
+	if in.DisabledFeatures != nil {
+		in, out := &in.DisabledFeatures, &out.DisabledFeatures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderStatus.
@@ -623,6 +836,21 @@ func (in *ProviderStatus) DeepCopy() *ProviderStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QualityPolicy) DeepCopyInto(out *QualityPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QualityPolicy.
+func (in *QualityPolicy) DeepCopy() *QualityPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(QualityPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretKeyRef) DeepCopyInto(out *SecretKeyRef) {
 	*out = *in
@@ -638,6 +866,146 @@ func (in *SecretKeyRef) DeepCopy() *SecretKeyRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TailscaleAppConnector) DeepCopyInto(out *TailscaleAppConnector) {
+	*out = *in
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TailscaleAppConnector.
+func (in *TailscaleAppConnector) DeepCopy() *TailscaleAppConnector {
+	if in == nil {
+		return nil
+	}
+	out := new(TailscaleAppConnector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TailscaleConfig) DeepCopyInto(out *TailscaleConfig) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OAuthSecretRef != nil {
+		in, out := &in.OAuthSecretRef, &out.OAuthSecretRef
+		*out = new(TailscaleOAuthSecretRef)
+		**out = **in
+	}
+	if in.AuthKeySecretRef != nil {
+		in, out := &in.AuthKeySecretRef, &out.AuthKeySecretRef
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
+	if in.Operator != nil {
+		in, out := &in.Operator, &out.Operator
+		*out = new(TailscaleOperatorConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TailscaleConfig.
+func (in *TailscaleConfig) DeepCopy() *TailscaleConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TailscaleConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TailscaleConnectorSpec) DeepCopyInto(out *TailscaleConnectorSpec) {
+	*out = *in
+	if in.SubnetRouter != nil {
+		in, out := &in.SubnetRouter, &out.SubnetRouter
+		*out = new(TailscaleSubnetRouter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AppConnector != nil {
+		in, out := &in.AppConnector, &out.AppConnector
+		*out = new(TailscaleAppConnector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TailscaleConnectorSpec.
+func (in *TailscaleConnectorSpec) DeepCopy() *TailscaleConnectorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TailscaleConnectorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TailscaleOAuthSecretRef) DeepCopyInto(out *TailscaleOAuthSecretRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TailscaleOAuthSecretRef.
+func (in *TailscaleOAuthSecretRef) DeepCopy() *TailscaleOAuthSecretRef {
+	if in == nil {
+		return nil
+	}
+	out := new(TailscaleOAuthSecretRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TailscaleOperatorConfig) DeepCopyInto(out *TailscaleOperatorConfig) {
+	*out = *in
+	if in.ConnectorEnabled != nil {
+		in, out := &in.ConnectorEnabled, &out.ConnectorEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Connector != nil {
+		in, out := &in.Connector, &out.Connector
+		*out = new(TailscaleConnectorSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TailscaleOperatorConfig.
+func (in *TailscaleOperatorConfig) DeepCopy() *TailscaleOperatorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TailscaleOperatorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TailscaleSubnetRouter) DeepCopyInto(out *TailscaleSubnetRouter) {
+	*out = *in
+	if in.AdvertiseRoutes != nil {
+		in, out := &in.AdvertiseRoutes, &out.AdvertiseRoutes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TailscaleSubnetRouter.
+func (in *TailscaleSubnetRouter) DeepCopy() *TailscaleSubnetRouter {
+	if in == nil {
+		return nil
+	}
+	out := new(TailscaleSubnetRouter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TalosConfig) DeepCopyInto(out *TalosConfig) {
 	*out = *in
@@ -646,6 +1014,20 @@ func (in *TalosConfig) DeepCopyInto(out *TalosConfig) {
 		*out = new(SecretKeyRef)
 		**out = **in
 	}
+	if in.ExtraFiles != nil {
+		in, out := &in.ExtraFiles, &out.ExtraFiles
+		*out = make([]TalosFile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TalosConfig.
@@ -657,3 +1039,23 @@ func (in *TalosConfig) DeepCopy() *TalosConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TalosFile) DeepCopyInto(out *TalosFile) {
+	*out = *in
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TalosFile.
+func (in *TalosFile) DeepCopy() *TalosFile {
+	if in == nil {
+		return nil
+	}
+	out := new(TalosFile)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,217 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// ConfigMapName is the name of the ConfigMap holding the operator configuration
+const ConfigMapName = "tgp-operator-config"
+
+// DefaultLoadBackoff paces the attempts to read the ConfigMap while the API server cannot be
+// reached, giving up after about 30 seconds
+var DefaultLoadBackoff = wait.Backoff{Duration: time.Second, Factor: 2, Jitter: 0.1, Steps: 6}
+
+// watchRestartDelay is how long to wait before watching the ConfigMap again after a failed watch
+const watchRestartDelay = 5 * time.Second
+
+// Source provides the operator configuration, which may change while the operator runs.
+// Read it once per operation rather than keeping the returned configuration.
+type Source interface {
+	Current() *OperatorConfig
+}
+
+// Current returns the configuration itself, so a fixed configuration can serve as a Source
+func (c *OperatorConfig) Current() *OperatorConfig {
+	return c
+}
+
+// Current returns the source's configuration, or nil without a source. The accessors of a nil
+// configuration return their defaults.
+func Current(source Source) *OperatorConfig {
+	if source == nil {
+		return nil
+	}
+	return source.Current()
+}
+
+// Loader is a manager runnable that loads the operator configuration from its ConfigMap and
+// reloads it whenever the ConfigMap changes, so edits take effect without a restart. Until
+// the ConfigMap is loaded, and while it does not exist, the default configuration is used,
+// which enables no providers. Invalid configurations are logged and the current one is kept.
+type Loader struct {
+	client  client.WithWatch
+	key     types.NamespacedName
+	log     logr.Logger
+	backoff wait.Backoff
+
+	current atomic.Pointer[OperatorConfig]
+	// loaded reports whether the initial load has finished, successfully or not
+	loaded atomic.Bool
+	// resourceVersion is the version of the ConfigMap last applied, touched only by Start
+	resourceVersion string
+
+	mu          sync.Mutex
+	subscribers []chan event.GenericEvent
+}
+
+// NewLoader creates a loader for the named ConfigMap serving the default configuration until it starts
+func NewLoader(c client.WithWatch, name, namespace string, log logr.Logger) *Loader {
+	l := &Loader{
+		client:  c,
+		key:     types.NamespacedName{Name: name, Namespace: namespace},
+		log:     log,
+		backoff: DefaultLoadBackoff,
+	}
+	l.current.Store(DefaultConfig())
+	return l
+}
+
+// Current returns the most recently loaded configuration
+func (l *Loader) Current() *OperatorConfig {
+	return l.current.Load()
+}
+
+// Subscribe returns a channel receiving an event whenever a new configuration is loaded, so
+// controllers can reconcile their objects against it. Notifications coalesce: a subscriber that
+// has not yet received the previous event gets a single event for both loads. The event carries
+// the ConfigMap that was loaded.
+func (l *Loader) Subscribe() <-chan event.GenericEvent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	ch := make(chan event.GenericEvent, 1)
+	l.subscribers = append(l.subscribers, ch)
+	return ch
+}
+
+// notify tells the subscribers a new configuration was loaded from the ConfigMap
+func (l *Loader) notify(configMap *corev1.ConfigMap) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, ch := range l.subscribers {
+		select {
+		case ch <- event.GenericEvent{Object: configMap}:
+		default:
+		}
+	}
+}
+
+// NeedLeaderElection lets every replica load the configuration, so a standby is ready to lead
+func (l *Loader) NeedLeaderElection() bool {
+	return false
+}
+
+// ReadyCheck is a readiness check that passes once the initial load has finished
+func (l *Loader) ReadyCheck(_ *http.Request) error {
+	if !l.loaded.Load() {
+		return errors.New("operator configuration not loaded yet")
+	}
+	return nil
+}
+
+// Start loads the configuration, retrying with backoff while the API server cannot be reached,
+// then watches the ConfigMap for changes until the context is cancelled
+func (l *Loader) Start(ctx context.Context) error {
+	l.loadInitial(ctx)
+	l.loaded.Store(true)
+
+	for {
+		if err := l.watch(ctx); err != nil {
+			l.log.Error(err, "Operator ConfigMap watch failed, restarting", "configMap", l.key)
+			select {
+			case <-ctx.Done():
+			case <-time.After(watchRestartDelay):
+			}
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// loadInitial reads the ConfigMap, retrying failed reads other than a missing ConfigMap
+func (l *Loader) loadInitial(ctx context.Context) {
+	err := wait.ExponentialBackoffWithContext(ctx, l.backoff, func(ctx context.Context) (bool, error) {
+		var configMap corev1.ConfigMap
+		err := l.client.Get(ctx, l.key, &configMap)
+		if apierrors.IsNotFound(err) {
+			l.log.Info("Operator ConfigMap not found, using the default configuration", "configMap", l.key)
+			return true, nil
+		}
+		if err != nil {
+			l.log.Info("Failed to read operator ConfigMap, retrying", "configMap", l.key, "error", err.Error())
+			return false, nil
+		}
+		l.apply(&configMap)
+		return true, nil
+	})
+	if err != nil && ctx.Err() == nil {
+		l.log.Error(err, "Gave up reading operator ConfigMap, using the default configuration until it changes", "configMap", l.key)
+	}
+}
+
+// watch applies changes to the ConfigMap until the watch ends. A new watch first reports the
+// ConfigMap as added, so changes made while no watch was open are not missed.
+func (l *Loader) watch(ctx context.Context) error {
+	watcher, err := l.client.Watch(ctx, &corev1.ConfigMapList{},
+		client.InNamespace(l.key.Namespace), client.MatchingFields{"metadata.name": l.key.Name})
+	if err != nil {
+		return fmt.Errorf("failed to watch ConfigMap %s: %w", l.key, err)
+	}
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, open := <-watcher.ResultChan():
+			if !open {
+				return nil
+			}
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				if configMap, ok := event.Object.(*corev1.ConfigMap); ok && configMap.Name == l.key.Name {
+					l.apply(configMap)
+				}
+			case watch.Deleted:
+				l.log.Info("Operator ConfigMap deleted, keeping the current configuration", "configMap", l.key)
+			case watch.Error:
+				return apierrors.FromObject(event.Object)
+			}
+		}
+	}
+}
+
+// apply makes the ConfigMap's configuration current unless it is invalid or already applied
+func (l *Loader) apply(configMap *corev1.ConfigMap) {
+	if configMap.ResourceVersion != "" && configMap.ResourceVersion == l.resourceVersion {
+		return
+	}
+	l.resourceVersion = configMap.ResourceVersion
+
+	config, err := ParseConfigMap(configMap)
+	if err != nil {
+		l.log.Error(err, "Ignoring invalid operator configuration, keeping the current one", "configMap", l.key)
+		return
+	}
+	l.current.Store(config)
+	l.log.Info("Loaded operator configuration", "configMap", l.key,
+		"resourceVersion", configMap.ResourceVersion, "enabledProviders", config.EnabledProviders())
+	l.notify(configMap)
+}
@@ -0,0 +1,181 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// enabledConfigYAML returns a configuration enabling the providers
+func enabledConfigYAML(providers ...string) string {
+	configYAML := "providers:\n"
+	for _, provider := range providers {
+		configYAML += "  " + provider + ":\n    enabled: true\n    credentialsRef:\n      name: creds\n      key: KEY\n"
+	}
+	return configYAML
+}
+
+// startLoader runs a loader against the client until the test ends and waits for its initial load
+func startLoader(t *testing.T, c client.WithWatch) *Loader {
+	t.Helper()
+	loader := NewLoader(c, ConfigMapName, "tgp-system", logr.Discard())
+	loader.backoff = wait.Backoff{Duration: time.Millisecond, Factor: 2, Steps: 3}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = loader.Start(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	waitFor(t, "initial load", func() bool { return loader.ReadyCheck(nil) == nil })
+	return loader
+}
+
+// waitFor polls the condition until it holds, failing the test after a few seconds
+func waitFor(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestLoaderUsesDefaultsWithoutConfigMap(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	loader := startLoader(t, c)
+	if enabled := loader.Current().EnabledProviders(); len(enabled) != 0 {
+		t.Errorf("expected the default configuration to enable no providers, got %v", enabled)
+	}
+
+	// Creating the ConfigMap later is picked up by the watch
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: "tgp-system"},
+		Data:       map[string]string{"config.yaml": enabledConfigYAML("vultr")},
+	}
+	if err := c.Create(context.Background(), configMap); err != nil {
+		t.Fatalf("failed to create ConfigMap: %v", err)
+	}
+	waitFor(t, "the created ConfigMap", func() bool {
+		return slices.Equal(loader.Current().EnabledProviders(), []string{"vultr"})
+	})
+}
+
+func TestLoaderReloadsChangedConfigMap(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: "tgp-system"},
+		Data:       map[string]string{"config.yaml": enabledConfigYAML("vultr")},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build()
+
+	loader := startLoader(t, c)
+	if enabled := loader.Current().EnabledProviders(); !slices.Equal(enabled, []string{"vultr"}) {
+		t.Fatalf("EnabledProviders() = %v, want [vultr]", enabled)
+	}
+	changes := loader.Subscribe()
+
+	update := func(configYAML string) {
+		t.Helper()
+		var current corev1.ConfigMap
+		if err := c.Get(context.Background(), client.ObjectKeyFromObject(configMap), &current); err != nil {
+			t.Fatalf("failed to get ConfigMap: %v", err)
+		}
+		current.Data["config.yaml"] = configYAML
+		if err := c.Update(context.Background(), &current); err != nil {
+			t.Fatalf("failed to update ConfigMap: %v", err)
+		}
+	}
+
+	update(enabledConfigYAML("vultr", "gcp"))
+	waitFor(t, "the updated ConfigMap", func() bool {
+		return slices.Equal(loader.Current().EnabledProviders(), []string{"vultr", "gcp"})
+	})
+
+	select {
+	case e := <-changes:
+		if e.Object.GetName() != ConfigMapName {
+			t.Errorf("change event for %q, want the operator ConfigMap", e.Object.GetName())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a change event")
+	}
+}
+
+func TestLoaderKeepsConfigOnInvalidConfigMap(t *testing.T) {
+	loader := NewLoader(nil, ConfigMapName, "tgp-system", logr.Discard())
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: "tgp-system", ResourceVersion: "1"},
+		Data:       map[string]string{"config.yaml": enabledConfigYAML("aws")},
+	}
+	loader.apply(configMap)
+	changes := loader.Subscribe()
+
+	for _, configYAML := range []string{"providers: {}\n", "providers: [\n"} {
+		configMap = configMap.DeepCopy()
+		configMap.ResourceVersion += "1"
+		configMap.Data["config.yaml"] = configYAML
+		loader.apply(configMap)
+	}
+	if enabled := loader.Current().EnabledProviders(); !slices.Equal(enabled, []string{"aws"}) {
+		t.Errorf("EnabledProviders() = %v, want the last valid configuration enabling aws", enabled)
+	}
+	select {
+	case <-changes:
+		t.Error("expected no change event for invalid configurations")
+	default:
+	}
+}
+
+func TestLoaderRetriesFailedReads(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: "tgp-system"},
+		Data:       map[string]string{"config.yaml": enabledConfigYAML("oci")},
+	}
+
+	failures := 2
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if failures > 0 {
+					failures--
+					return errors.New("connection refused")
+				}
+				return c.Get(ctx, key, obj, opts...)
+			},
+			Watch: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) (watch.Interface, error) {
+				// Only the retried read may load the configuration
+				return watch.NewFake(), nil
+			},
+		}).Build()
+
+	loader := startLoader(t, c)
+	if enabled := loader.Current().EnabledProviders(); !slices.Equal(enabled, []string{"oci"}) {
+		t.Errorf("EnabledProviders() = %v, want [oci] after retrying", enabled)
+	}
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"context"
 	"fmt"
+	"os"
+	"time"
 
 	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
@@ -17,6 +19,15 @@ type OperatorConfig struct {
 
 	// Talos contains default Talos configuration
 	Talos TalosDefaults `yaml:"talos" json:"talos"`
+
+	// OrphanReaper configures the sweep that removes nodes whose GPUNodePool no longer exists
+	OrphanReaper OrphanReaperConfig `yaml:"orphanReaper" json:"orphanReaper"`
+
+	// Provisioning tunes how instances and nodes are created
+	Provisioning ProvisioningConfig `yaml:"provisioning,omitempty" json:"provisioning,omitempty"`
+
+	// Drain tunes how pods are moved off nodes before they are removed
+	Drain DrainConfig `yaml:"drain,omitempty" json:"drain,omitempty"`
 }
 
 // ProvidersConfig contains configuration for all cloud providers
@@ -25,6 +36,16 @@ type ProvidersConfig struct {
 	Vultr ProviderConfig `yaml:"vultr" json:"vultr"`
 	// GCP contains Google Cloud Platform provider configuration
 	GCP ProviderConfig `yaml:"gcp" json:"gcp"`
+	// AWS contains Amazon Web Services EC2 provider configuration
+	AWS ProviderConfig `yaml:"aws" json:"aws"`
+	// Azure contains Microsoft Azure provider configuration
+	Azure ProviderConfig `yaml:"azure" json:"azure"`
+	// DigitalOcean contains DigitalOcean GPU droplet provider configuration
+	DigitalOcean ProviderConfig `yaml:"digitalocean" json:"digitalocean"`
+	// CoreWeave contains CoreWeave virtual server provider configuration
+	CoreWeave ProviderConfig `yaml:"coreweave" json:"coreweave"`
+	// OCI contains Oracle Cloud Infrastructure provider configuration
+	OCI ProviderConfig `yaml:"oci" json:"oci"`
 }
 
 // ProviderConfig contains configuration for a single cloud provider
@@ -32,8 +53,78 @@ type ProviderConfig struct {
 	// Enabled indicates whether this provider is available
 	Enabled bool `yaml:"enabled" json:"enabled"`
 
+	// CredentialsSource is where the provider's credentials come from: secret (the default)
+	// reads credentialsRef, env reads an environment variable of the operator, and
+	// workloadIdentity uses the pod's ambient identity (AWS IRSA, GCP workload identity)
+	CredentialsSource string `yaml:"credentialsSource,omitempty" json:"credentialsSource,omitempty"`
+
 	// CredentialsRef references the secret containing API credentials
 	CredentialsRef SecretReference `yaml:"credentialsRef" json:"credentialsRef"`
+
+	// CredentialsEnv names the environment variable holding the credentials when
+	// credentialsSource is env. It defaults to credentialsRef.key.
+	CredentialsEnv string `yaml:"credentialsEnv,omitempty" json:"credentialsEnv,omitempty"`
+
+	// DisabledFeatures lists provider operations to skip, so a partially broken
+	// provider can still be used for the operations that work
+	DisabledFeatures []string `yaml:"disabledFeatures,omitempty" json:"disabledFeatures,omitempty"`
+
+	// DebugLogging logs every provider API request and response at debug level,
+	// with credentials redacted, for troubleshooting provider behaviour
+	DebugLogging bool `yaml:"debugLogging,omitempty" json:"debugLogging,omitempty"`
+
+	// MaxInstances caps the running and in-flight instances the operator keeps with this
+	// provider, such as its account quota. Providers nearing the cap are deprioritized so
+	// launches spread across providers. Zero means no limit.
+	MaxInstances int `yaml:"maxInstances,omitempty" json:"maxInstances,omitempty"`
+
+	// MaxConcurrentLaunches caps how many launch calls the operator makes to this provider
+	// at once; further launches wait for a slot. Zero uses the default of one, serializing
+	// launches per provider.
+	MaxConcurrentLaunches int `yaml:"maxConcurrentLaunches,omitempty" json:"maxConcurrentLaunches,omitempty"`
+
+	// ImagePrefix is the name prefix of the uploaded Talos images to launch from, for
+	// providers that boot custom images (GCP). The newest matching image is used.
+	ImagePrefix string `yaml:"imagePrefix,omitempty" json:"imagePrefix,omitempty"`
+}
+
+// Sources of provider credentials
+const (
+	// CredentialsSourceSecret reads the credentials from a key of a Kubernetes Secret
+	CredentialsSourceSecret = "secret"
+	// CredentialsSourceEnv reads the credentials from an environment variable of the operator
+	CredentialsSourceEnv = "env"
+	// CredentialsSourceWorkloadIdentity uses the ambient credentials of the operator's pod
+	CredentialsSourceWorkloadIdentity = "workloadIdentity"
+)
+
+// workloadIdentityProviders are the providers whose clients can use ambient credentials
+var workloadIdentityProviders = map[string]bool{
+	"aws": true,
+	"gcp": true,
+}
+
+// Provider features that can be disabled per provider
+const (
+	// FeatureInventory covers listing available GPU offers
+	FeatureInventory = "inventory"
+	// FeaturePricing covers fetching normalized pricing
+	FeaturePricing = "pricing"
+	// FeatureLaunch covers launching new instances
+	FeatureLaunch = "launch"
+	// FeatureTerminate covers terminating instances
+	FeatureTerminate = "terminate"
+	// FeatureTagging covers updating tags on running instances
+	FeatureTagging = "tagging"
+)
+
+// knownFeatures are the feature names accepted in DisabledFeatures
+var knownFeatures = map[string]bool{
+	FeatureInventory: true,
+	FeaturePricing:   true,
+	FeatureLaunch:    true,
+	FeatureTerminate: true,
+	FeatureTagging:   true,
 }
 
 // SecretReference contains a reference to a secret and key
@@ -57,16 +148,168 @@ type TalosDefaults struct {
 	Extensions []string `yaml:"extensions" json:"extensions"`
 }
 
-// GetProviderCredentials retrieves API credentials for a provider
-func (c *OperatorConfig) GetProviderCredentials(ctx context.Context, client client.Client, provider string, operatorNamespace string) (string, error) {
-	var providerConfig ProviderConfig
+// OrphanReaperConfig contains configuration for reaping orphaned nodes
+type OrphanReaperConfig struct {
+	// Enabled turns on the periodic orphan node sweep
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// DryRun only logs orphaned nodes instead of terminating and deleting them
+	DryRun bool `yaml:"dryRun" json:"dryRun"`
+
+	// Interval is how often the sweep runs (defaults to 10m)
+	Interval time.Duration `yaml:"interval,omitempty" json:"interval,omitempty"`
+}
+
+// DefaultNameCollisionRetries is how many fresh names are tried when an instance or node name is taken
+const DefaultNameCollisionRetries = 3
+
+// ProvisioningConfig contains configuration for creating instances and nodes
+type ProvisioningConfig struct {
+	// NameCollisionRetries is how many times to retry with a freshly suffixed name when
+	// an instance or node name is already taken (defaults to 3)
+	NameCollisionRetries *int `yaml:"nameCollisionRetries,omitempty" json:"nameCollisionRetries,omitempty"`
+}
+
+// NameCollisionRetries returns how many times to retry a create with a fresh name after a conflict
+func (c *OperatorConfig) NameCollisionRetries() int {
+	if c == nil || c.Provisioning.NameCollisionRetries == nil {
+		return DefaultNameCollisionRetries
+	}
+	return *c.Provisioning.NameCollisionRetries
+}
+
+// DefaultDrainTimeout is how long evicted pods get to terminate before they are force-deleted
+const DefaultDrainTimeout = 5 * time.Minute
+
+// DrainConfig contains configuration for draining nodes
+type DrainConfig struct {
+	// Timeout is how long pods evicted from a node, subject to their PodDisruptionBudgets,
+	// may take to terminate before the remaining ones are force-deleted (defaults to 5m)
+	Timeout time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
 
+// DrainTimeout returns how long to wait for evicted pods before force-deleting them
+func (c *OperatorConfig) DrainTimeout() time.Duration {
+	if c == nil || c.Drain.Timeout <= 0 {
+		return DefaultDrainTimeout
+	}
+	return c.Drain.Timeout
+}
+
+// providerConfig returns the configuration for the named provider
+func (c *OperatorConfig) providerConfig(provider string) (ProviderConfig, bool) {
 	switch provider {
 	case "vultr":
-		providerConfig = c.Providers.Vultr
+		return c.Providers.Vultr, true
 	case "gcp":
-		providerConfig = c.Providers.GCP
+		return c.Providers.GCP, true
+	case "aws":
+		return c.Providers.AWS, true
+	case "azure":
+		return c.Providers.Azure, true
+	case "digitalocean":
+		return c.Providers.DigitalOcean, true
+	case "coreweave":
+		return c.Providers.CoreWeave, true
+	case "oci":
+		return c.Providers.OCI, true
 	default:
+		return ProviderConfig{}, false
+	}
+}
+
+// providerNames are the providers the configuration has a section for
+var providerNames = []string{"vultr", "gcp", "aws", "azure", "digitalocean", "coreweave", "oci"}
+
+// EnabledProviders returns the names of the enabled providers
+func (c *OperatorConfig) EnabledProviders() []string {
+	var enabled []string
+	for _, name := range providerNames {
+		if providerConfig, _ := c.providerConfig(name); providerConfig.Enabled {
+			enabled = append(enabled, name)
+		}
+	}
+	return enabled
+}
+
+// FeatureEnabled reports whether a provider feature has not been disabled in configuration
+func (c *OperatorConfig) FeatureEnabled(provider, feature string) bool {
+	for _, disabled := range c.DisabledFeatures(provider) {
+		if disabled == feature {
+			return false
+		}
+	}
+	return true
+}
+
+// DisabledFeatures returns the features disabled for a provider
+func (c *OperatorConfig) DisabledFeatures(provider string) []string {
+	if c == nil {
+		return nil
+	}
+	providerConfig, _ := c.providerConfig(provider)
+	return providerConfig.DisabledFeatures
+}
+
+// DebugLoggingEnabled reports whether provider API exchanges should be logged
+func (c *OperatorConfig) DebugLoggingEnabled(provider string) bool {
+	if c == nil {
+		return false
+	}
+	providerConfig, _ := c.providerConfig(provider)
+	return providerConfig.DebugLogging
+}
+
+// MaxInstances returns the configured instance limit for a provider, or 0 if unlimited
+func (c *OperatorConfig) MaxInstances(provider string) int {
+	if c == nil {
+		return 0
+	}
+	providerConfig, _ := c.providerConfig(provider)
+	return providerConfig.MaxInstances
+}
+
+// MaxConcurrentLaunches returns the configured launch concurrency for a provider, or 0 for the default
+func (c *OperatorConfig) MaxConcurrentLaunches(provider string) int {
+	if c == nil {
+		return 0
+	}
+	providerConfig, _ := c.providerConfig(provider)
+	return providerConfig.MaxConcurrentLaunches
+}
+
+// ImagePrefix returns the configured Talos image name prefix for a provider, or "" for the provider default
+func (c *OperatorConfig) ImagePrefix(provider string) string {
+	if c == nil {
+		return ""
+	}
+	providerConfig, _ := c.providerConfig(provider)
+	return providerConfig.ImagePrefix
+}
+
+// CredentialsSource returns where a provider's credentials come from
+func (c *OperatorConfig) CredentialsSource(provider string) string {
+	if c == nil {
+		return CredentialsSourceSecret
+	}
+	providerConfig, _ := c.providerConfig(provider)
+	return providerConfig.credentialsSource()
+}
+
+// credentialsSource returns the configured credentials source, defaulting to secret
+func (p ProviderConfig) credentialsSource() string {
+	if p.CredentialsSource == "" {
+		return CredentialsSourceSecret
+	}
+	return p.CredentialsSource
+}
+
+// GetProviderCredentials retrieves API credentials for a provider from its credentials
+// source. Providers using workload identity have no credentials and get an empty string,
+// which their clients take as a request for the ambient credentials.
+func (c *OperatorConfig) GetProviderCredentials(ctx context.Context, client client.Client, provider string, operatorNamespace string) (string, error) {
+	providerConfig, ok := c.providerConfig(provider)
+	if !ok {
 		return "", fmt.Errorf("unknown provider: %s", provider)
 	}
 
@@ -74,6 +317,18 @@ func (c *OperatorConfig) GetProviderCredentials(ctx context.Context, client clie
 		return "", fmt.Errorf("provider %s is not enabled", provider)
 	}
 
+	switch providerConfig.credentialsSource() {
+	case CredentialsSourceWorkloadIdentity:
+		return "", nil
+	case CredentialsSourceEnv:
+		name := providerConfig.credentialsEnv()
+		value := os.Getenv(name)
+		if value == "" {
+			return "", fmt.Errorf("environment variable %s with the %s credentials is not set", name, provider)
+		}
+		return value, nil
+	}
+
 	secretNamespace := providerConfig.CredentialsRef.Namespace
 	if secretNamespace == "" {
 		secretNamespace = operatorNamespace
@@ -96,9 +351,16 @@ func (c *OperatorConfig) GetProviderCredentials(ctx context.Context, client clie
 	return string(apiKey), nil
 }
 
+// credentialsEnv returns the environment variable holding the credentials for the env source
+func (p ProviderConfig) credentialsEnv() string {
+	if p.CredentialsEnv != "" {
+		return p.CredentialsEnv
+	}
+	return p.CredentialsRef.Key
+}
+
 // LoadConfig loads operator configuration from a ConfigMap or returns default config
 func LoadConfig(ctx context.Context, client client.Client, configMapName, namespace string) (*OperatorConfig, error) {
-	// Try to load from ConfigMap first
 	configMap := &corev1.ConfigMap{}
 	err := client.Get(ctx, types.NamespacedName{
 		Name:      configMapName,
@@ -110,9 +372,14 @@ func LoadConfig(ctx context.Context, client client.Client, configMapName, namesp
 		return nil, fmt.Errorf("failed to load ConfigMap %s/%s: %w", namespace, configMapName, err)
 	}
 
+	return ParseConfigMap(configMap)
+}
+
+// ParseConfigMap parses and validates the operator configuration in a ConfigMap's config.yaml key
+func ParseConfigMap(configMap *corev1.ConfigMap) (*OperatorConfig, error) {
 	configYAML, exists := configMap.Data["config.yaml"]
 	if !exists {
-		return nil, fmt.Errorf("config.yaml key not found in ConfigMap %s/%s", namespace, configMapName)
+		return nil, fmt.Errorf("config.yaml key not found in ConfigMap %s/%s", configMap.Namespace, configMap.Name)
 	}
 
 	config := &OperatorConfig{}
@@ -139,21 +406,50 @@ func validateConfig(config *OperatorConfig) error {
 
 	if config.Providers.Vultr.Enabled {
 		hasEnabledProvider = true
-		if config.Providers.Vultr.CredentialsRef.Name == "" {
-			return fmt.Errorf("vultr provider is enabled but credentialsRef.name is empty")
-		}
-		if config.Providers.Vultr.CredentialsRef.Key == "" {
-			return fmt.Errorf("vultr provider is enabled but credentialsRef.key is empty")
+		if err := validateCredentials("vultr", config.Providers.Vultr); err != nil {
+			return err
 		}
 	}
 
 	if config.Providers.GCP.Enabled {
 		hasEnabledProvider = true
-		if config.Providers.GCP.CredentialsRef.Name == "" {
-			return fmt.Errorf("gcp provider is enabled but credentialsRef.name is empty")
+		if err := validateCredentials("gcp", config.Providers.GCP); err != nil {
+			return err
+		}
+	}
+
+	if config.Providers.AWS.Enabled {
+		hasEnabledProvider = true
+		if err := validateCredentials("aws", config.Providers.AWS); err != nil {
+			return err
+		}
+	}
+
+	if config.Providers.Azure.Enabled {
+		hasEnabledProvider = true
+		if err := validateCredentials("azure", config.Providers.Azure); err != nil {
+			return err
+		}
+	}
+
+	if config.Providers.DigitalOcean.Enabled {
+		hasEnabledProvider = true
+		if err := validateCredentials("digitalocean", config.Providers.DigitalOcean); err != nil {
+			return err
 		}
-		if config.Providers.GCP.CredentialsRef.Key == "" {
-			return fmt.Errorf("gcp provider is enabled but credentialsRef.key is empty")
+	}
+
+	if config.Providers.CoreWeave.Enabled {
+		hasEnabledProvider = true
+		if err := validateCredentials("coreweave", config.Providers.CoreWeave); err != nil {
+			return err
+		}
+	}
+
+	if config.Providers.OCI.Enabled {
+		hasEnabledProvider = true
+		if err := validateCredentials("oci", config.Providers.OCI); err != nil {
+			return err
 		}
 	}
 
@@ -161,6 +457,56 @@ func validateConfig(config *OperatorConfig) error {
 		return fmt.Errorf("no providers are enabled - at least one provider must be enabled")
 	}
 
+	for name, providerConfig := range map[string]ProviderConfig{"vultr": config.Providers.Vultr, "gcp": config.Providers.GCP, "aws": config.Providers.AWS, "azure": config.Providers.Azure, "digitalocean": config.Providers.DigitalOcean, "coreweave": config.Providers.CoreWeave, "oci": config.Providers.OCI} {
+		for _, feature := range providerConfig.DisabledFeatures {
+			if !knownFeatures[feature] {
+				return fmt.Errorf("%s provider has unknown disabled feature: %s", name, feature)
+			}
+		}
+		if providerConfig.MaxInstances < 0 {
+			return fmt.Errorf("%s provider maxInstances must not be negative", name)
+		}
+		if providerConfig.MaxConcurrentLaunches < 0 {
+			return fmt.Errorf("%s provider maxConcurrentLaunches must not be negative", name)
+		}
+	}
+
+	if config.OrphanReaper.Interval < 0 {
+		return fmt.Errorf("orphanReaper.interval must not be negative")
+	}
+
+	if retries := config.Provisioning.NameCollisionRetries; retries != nil && *retries < 0 {
+		return fmt.Errorf("provisioning.nameCollisionRetries must not be negative")
+	}
+
+	if config.Drain.Timeout < 0 {
+		return fmt.Errorf("drain.timeout must not be negative")
+	}
+
+	return nil
+}
+
+// validateCredentials validates that an enabled provider's credentials source is usable
+func validateCredentials(name string, providerConfig ProviderConfig) error {
+	switch providerConfig.credentialsSource() {
+	case CredentialsSourceSecret:
+		if providerConfig.CredentialsRef.Name == "" {
+			return fmt.Errorf("%s provider is enabled but credentialsRef.name is empty", name)
+		}
+		if providerConfig.CredentialsRef.Key == "" {
+			return fmt.Errorf("%s provider is enabled but credentialsRef.key is empty", name)
+		}
+	case CredentialsSourceEnv:
+		if providerConfig.credentialsEnv() == "" {
+			return fmt.Errorf("%s provider reads credentials from the environment but neither credentialsEnv nor credentialsRef.key is set", name)
+		}
+	case CredentialsSourceWorkloadIdentity:
+		if !workloadIdentityProviders[name] {
+			return fmt.Errorf("%s provider does not support workload identity credentials", name)
+		}
+	default:
+		return fmt.Errorf("%s provider has unknown credentialsSource: %s", name, providerConfig.CredentialsSource)
+	}
 	return nil
 }
 
@@ -182,6 +528,41 @@ func DefaultConfig() *OperatorConfig {
 					Key:  "GOOGLE_APPLICATION_CREDENTIALS_JSON",
 				},
 			},
+			AWS: ProviderConfig{
+				Enabled: false,
+				CredentialsRef: SecretReference{
+					Name: "tgp-operator-secret",
+					Key:  "AWS_CREDENTIALS_JSON",
+				},
+			},
+			Azure: ProviderConfig{
+				Enabled: false,
+				CredentialsRef: SecretReference{
+					Name: "tgp-operator-secret",
+					Key:  "AZURE_CREDENTIALS_JSON",
+				},
+			},
+			DigitalOcean: ProviderConfig{
+				Enabled: false,
+				CredentialsRef: SecretReference{
+					Name: "tgp-operator-secret",
+					Key:  "DIGITALOCEAN_TOKEN",
+				},
+			},
+			CoreWeave: ProviderConfig{
+				Enabled: false,
+				CredentialsRef: SecretReference{
+					Name: "tgp-operator-secret",
+					Key:  "COREWEAVE_KUBECONFIG",
+				},
+			},
+			OCI: ProviderConfig{
+				Enabled: false,
+				CredentialsRef: SecretReference{
+					Name: "tgp-operator-secret",
+					Key:  "OCI_CREDENTIALS_JSON",
+				},
+			},
 		},
 		Talos: TalosDefaults{
 			Version: "v1.11.0-beta.1",
@@ -195,5 +576,10 @@ func DefaultConfig() *OperatorConfig {
 				"siderolabs/i915-ucode",
 			},
 		},
+		OrphanReaper: OrphanReaperConfig{
+			Enabled:  false,
+			DryRun:   true,
+			Interval: 10 * time.Minute,
+		},
 	}
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"context"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -69,6 +70,62 @@ func TestOperatorConfig_GetProviderCredentials(t *testing.T) {
 	})
 }
 
+func TestOperatorConfig_CredentialsSource(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultConfig()
+	config.Providers.AWS.Enabled = true
+	config.Providers.AWS.CredentialsSource = CredentialsSourceWorkloadIdentity
+	config.Providers.Vultr.Enabled = true
+	config.Providers.Vultr.CredentialsSource = CredentialsSourceEnv
+
+	t.Run("should default to secret", func(t *testing.T) {
+		if got := config.CredentialsSource("gcp"); got != CredentialsSourceSecret {
+			t.Errorf("CredentialsSource(gcp) = %s, want %s", got, CredentialsSourceSecret)
+		}
+	})
+
+	t.Run("should return no credentials for workload identity", func(t *testing.T) {
+		credentials, err := config.GetProviderCredentials(ctx, nil, "aws", "default")
+		if err != nil || credentials != "" {
+			t.Errorf("Expected empty credentials, got %q, %v", credentials, err)
+		}
+	})
+
+	t.Run("should read credentials from the environment", func(t *testing.T) {
+		if _, err := config.GetProviderCredentials(ctx, nil, "vultr", "default"); err == nil {
+			t.Error("Expected error for an unset environment variable")
+		}
+
+		t.Setenv("VULTR_API_KEY", "env-key")
+		credentials, err := config.GetProviderCredentials(ctx, nil, "vultr", "default")
+		if err != nil || credentials != "env-key" {
+			t.Errorf("Expected the credentials of credentialsRef.key, got %q, %v", credentials, err)
+		}
+
+		config.Providers.Vultr.CredentialsEnv = "TGP_VULTR_KEY"
+		t.Setenv("TGP_VULTR_KEY", "other-key")
+		if credentials, _ := config.GetProviderCredentials(ctx, nil, "vultr", "default"); credentials != "other-key" {
+			t.Errorf("Expected the credentials of credentialsEnv, got %q", credentials)
+		}
+	})
+
+	t.Run("should validate credentials sources", func(t *testing.T) {
+		if err := validateConfig(config); err != nil {
+			t.Errorf("Expected valid config, got %v", err)
+		}
+
+		config.Providers.Vultr.CredentialsSource = CredentialsSourceWorkloadIdentity
+		if err := validateConfig(config); err == nil {
+			t.Error("Expected error for workload identity on a provider without support")
+		}
+
+		config.Providers.Vultr.CredentialsSource = "vault"
+		if err := validateConfig(config); err == nil {
+			t.Error("Expected error for an unknown credentials source")
+		}
+	})
+}
+
 func TestDefaultConfig(t *testing.T) {
 	config := DefaultConfig()
 
@@ -88,6 +145,61 @@ func TestDefaultConfig(t *testing.T) {
 		}
 	})
 
+	t.Run("should have AWS provider configuration", func(t *testing.T) {
+		if config.Providers.AWS.Enabled {
+			t.Error("AWS should be disabled by default")
+		}
+
+		expectedAPIKey := "AWS_CREDENTIALS_JSON"
+		if config.Providers.AWS.CredentialsRef.Key != expectedAPIKey {
+			t.Errorf("Expected API key '%s', got: %s", expectedAPIKey, config.Providers.AWS.CredentialsRef.Key)
+		}
+	})
+
+	t.Run("should have Azure provider configuration", func(t *testing.T) {
+		if config.Providers.Azure.Enabled {
+			t.Error("Azure should be disabled by default")
+		}
+
+		expectedAPIKey := "AZURE_CREDENTIALS_JSON"
+		if config.Providers.Azure.CredentialsRef.Key != expectedAPIKey {
+			t.Errorf("Expected API key '%s', got: %s", expectedAPIKey, config.Providers.Azure.CredentialsRef.Key)
+		}
+	})
+
+	t.Run("should have DigitalOcean provider configuration", func(t *testing.T) {
+		if config.Providers.DigitalOcean.Enabled {
+			t.Error("DigitalOcean should be disabled by default")
+		}
+
+		expectedAPIKey := "DIGITALOCEAN_TOKEN"
+		if config.Providers.DigitalOcean.CredentialsRef.Key != expectedAPIKey {
+			t.Errorf("Expected API key '%s', got: %s", expectedAPIKey, config.Providers.DigitalOcean.CredentialsRef.Key)
+		}
+	})
+
+	t.Run("should have CoreWeave provider configuration", func(t *testing.T) {
+		if config.Providers.CoreWeave.Enabled {
+			t.Error("CoreWeave should be disabled by default")
+		}
+
+		expectedAPIKey := "COREWEAVE_KUBECONFIG"
+		if config.Providers.CoreWeave.CredentialsRef.Key != expectedAPIKey {
+			t.Errorf("Expected API key '%s', got: %s", expectedAPIKey, config.Providers.CoreWeave.CredentialsRef.Key)
+		}
+	})
+
+	t.Run("should have OCI provider configuration", func(t *testing.T) {
+		if config.Providers.OCI.Enabled {
+			t.Error("OCI should be disabled by default")
+		}
+
+		expectedAPIKey := "OCI_CREDENTIALS_JSON"
+		if config.Providers.OCI.CredentialsRef.Key != expectedAPIKey {
+			t.Errorf("Expected API key '%s', got: %s", expectedAPIKey, config.Providers.OCI.CredentialsRef.Key)
+		}
+	})
+
 	t.Run("should have default Talos configuration", func(t *testing.T) {
 		if config.Talos.Version == "" {
 			t.Error("Talos version should not be empty")
@@ -97,4 +209,92 @@ func TestDefaultConfig(t *testing.T) {
 		}
 	})
 
+	t.Run("should have orphan reaper disabled in dry-run mode", func(t *testing.T) {
+		if config.OrphanReaper.Enabled {
+			t.Error("Orphan reaper should be disabled by default")
+		}
+		if !config.OrphanReaper.DryRun {
+			t.Error("Orphan reaper should default to dry run")
+		}
+	})
+
+}
+
+func TestOperatorConfig_DisabledFeatures(t *testing.T) {
+	config := DefaultConfig()
+	config.Providers.Vultr.Enabled = true
+	config.Providers.Vultr.DisabledFeatures = []string{FeatureTagging}
+
+	t.Run("should report disabled features per provider", func(t *testing.T) {
+		if config.FeatureEnabled("vultr", FeatureTagging) {
+			t.Error("tagging should be disabled for vultr")
+		}
+		if !config.FeatureEnabled("vultr", FeatureLaunch) {
+			t.Error("launch should remain enabled for vultr")
+		}
+		if !config.FeatureEnabled("gcp", FeatureTagging) {
+			t.Error("tagging should remain enabled for gcp")
+		}
+	})
+
+	t.Run("should reject unknown feature names", func(t *testing.T) {
+		config.Providers.Vultr.DisabledFeatures = []string{"teleport"}
+		if err := validateConfig(config); err == nil {
+			t.Error("expected error for unknown disabled feature")
+		}
+	})
+}
+
+func TestOperatorConfig_DebugLogging(t *testing.T) {
+	config := DefaultConfig()
+	config.Providers.GCP.DebugLogging = true
+
+	if !config.DebugLoggingEnabled("gcp") {
+		t.Error("debug logging should be enabled for gcp")
+	}
+	if config.DebugLoggingEnabled("vultr") {
+		t.Error("debug logging should remain disabled for vultr")
+	}
+
+	var unset *OperatorConfig
+	if unset.DebugLoggingEnabled("gcp") {
+		t.Error("debug logging should be disabled without a config")
+	}
+}
+
+func TestOperatorConfig_NameCollisionRetries(t *testing.T) {
+	config := DefaultConfig()
+	if got := config.NameCollisionRetries(); got != DefaultNameCollisionRetries {
+		t.Errorf("NameCollisionRetries() = %d, want default %d", got, DefaultNameCollisionRetries)
+	}
+
+	retries := 0
+	config.Provisioning.NameCollisionRetries = &retries
+	if got := config.NameCollisionRetries(); got != 0 {
+		t.Errorf("NameCollisionRetries() = %d, want 0 when retries are turned off", got)
+	}
+
+	retries = -1
+	config.Providers.Vultr.Enabled = true
+	if err := validateConfig(config); err == nil {
+		t.Error("expected error for negative nameCollisionRetries")
+	}
+}
+
+func TestOperatorConfig_DrainTimeout(t *testing.T) {
+	config := DefaultConfig()
+	if got := config.DrainTimeout(); got != DefaultDrainTimeout {
+		t.Errorf("DrainTimeout() = %s, want default %s", got, DefaultDrainTimeout)
+	}
+
+	config.Drain.Timeout = 30 * time.Second
+	if got := config.DrainTimeout(); got != 30*time.Second {
+		t.Errorf("DrainTimeout() = %s, want 30s", got)
+	}
+
+	config.Drain.Timeout = -time.Second
+	config.Providers.Vultr.Enabled = true
+	if err := validateConfig(config); err == nil {
+		t.Error("expected error for negative drain timeout")
+	}
 }
@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"context"
+	"sort"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/config"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// terminationBatch is a set of instances that can be terminated with one provider client
+type terminationBatch struct {
+	provider string
+	account  string
+	// nodes maps instance IDs to the names of the nodes they back
+	nodes map[string]string
+}
+
+// instanceIDs returns the batch's instance IDs in a stable order
+func (b *terminationBatch) instanceIDs() []string {
+	ids := make([]string, 0, len(b.nodes))
+	for id := range b.nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// terminationBatches holds one batch per provider and account, keyed by "provider/account"
+type terminationBatches map[string]*terminationBatch
+
+// add adds an instance to the batch of the provider and account it runs in. nodeName is
+// empty for instances that have no node yet.
+func (b terminationBatches) add(providerName, account, instanceID, nodeName string) {
+	key := providerName + "/" + account
+	batch, exists := b[key]
+	if !exists {
+		batch = &terminationBatch{provider: providerName, account: account, nodes: make(map[string]string)}
+		b[key] = batch
+	}
+	batch.nodes[instanceID] = nodeName
+}
+
+// groupNodesForTermination groups nodes by the provider and account their instances run in.
+// Nodes that do not record an instance are left out, as there is nothing to terminate.
+func groupNodesForTermination(nodes []*corev1.Node) terminationBatches {
+	batches := make(terminationBatches)
+	for _, node := range nodes {
+		instanceID, providerName := nodeInstance(node)
+		if instanceID == "" || providerName == "" {
+			continue
+		}
+		batches.add(providerName, node.Annotations[AnnotationAccount], instanceID, node.Name)
+	}
+	return batches
+}
+
+// unregisteredInstances returns the pool's tracked instances that were launched but have no
+// node among nodes, such as those settled by a shutdown before their node was created
+func unregisteredInstances(nodePool *tgpv1.GPUNodePool, nodes []corev1.Node) []tgpv1.PoolInstance {
+	registered := make(map[string]bool, len(nodes))
+	for i := range nodes {
+		if instanceID, _ := nodeInstance(&nodes[i]); instanceID != "" {
+			registered[instanceID] = true
+		}
+	}
+
+	var unregistered []tgpv1.PoolInstance
+	for _, tracked := range nodePool.Status.Instances {
+		if tracked.Phase == tgpv1.PoolInstancePhaseLaunched && !registered[tracked.InstanceID] {
+			unregistered = append(unregistered, tracked)
+		}
+	}
+	return unregistered
+}
+
+// terminatePoolInstances terminates the batched instances with one provider client per batch.
+// It returns the IDs of the instances that could not be terminated.
+func (r *GPUNodePoolReconciler) terminatePoolInstances(ctx context.Context, nodePool *tgpv1.GPUNodePool, batches terminationBatches, log logr.Logger) map[string]bool {
+	failed := make(map[string]bool)
+	if len(batches) == 0 {
+		return failed
+	}
+
+	nodeClass, err := r.getNodeClass(ctx, nodePool)
+	if err != nil {
+		log.V(1).Info("Node class unavailable, using default credentials namespace", "error", err.Error())
+		nodeClass = &tgpv1.GPUNodeClass{}
+	}
+
+	for _, batch := range batches {
+		markFailed := func() {
+			for instanceID := range batch.nodes {
+				failed[instanceID] = true
+			}
+		}
+
+		if !config.Current(r.Config).FeatureEnabled(batch.provider, config.FeatureTerminate) {
+			log.Info("Terminate is disabled for provider, keeping instances", "provider", batch.provider, "count", len(batch.nodes))
+			markFailed()
+			continue
+		}
+
+		providerClient, err := r.providerClientForClass(ctx, nodeClass, batch.provider)
+		if err == nil {
+			err = providers.SelectAccount(providerClient, batch.account)
+		}
+		if err != nil {
+			log.Error(err, "Failed to create provider client for termination", "provider", batch.provider)
+			markFailed()
+			continue
+		}
+
+		log.Info("Terminating instances", "provider", batch.provider, "count", len(batch.nodes))
+		failures := providers.TerminateInstances(ctx, providerClient, batch.instanceIDs())
+		for instanceID, nodeName := range batch.nodes {
+			if err, terminateFailed := failures[instanceID]; terminateFailed {
+				log.Error(err, "Failed to terminate instance", "provider", batch.provider, "instanceID", instanceID)
+				failed[instanceID] = true
+				continue
+			}
+			r.recordTermination(nil, batch.provider, instanceID, nodeName, tgpv1.TerminationReasonPoolDeleted)
+		}
+	}
+
+	return failed
+}
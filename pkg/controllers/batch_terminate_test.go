@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+func TestGroupNodesForTermination(t *testing.T) {
+	node := func(name, provider, instanceID, account string) *corev1.Node {
+		n := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+		}}
+		if provider != "" {
+			n.Labels[tgpv1.NodeLabelProvider] = provider
+		}
+		if instanceID != "" {
+			n.Labels["tgp.io/instance-id"] = instanceID
+		}
+		if account != "" {
+			n.Annotations[AnnotationAccount] = account
+		}
+		return n
+	}
+
+	batches := groupNodesForTermination([]*corev1.Node{
+		node("vultr-a", "vultr", "v-1", ""),
+		node("vultr-b", "vultr", "v-2", ""),
+		node("gcp-default", "gcp", "us-central1-a/g-1", ""),
+		node("gcp-other", "gcp", "us-central1-a/g-2", "other-project"),
+		node("unmanaged", "", "", ""),
+	})
+
+	if len(batches) != 3 {
+		t.Fatalf("groupNodesForTermination() returned %d batches, want 3", len(batches))
+	}
+
+	vultr := batches["vultr/"]
+	if vultr == nil {
+		t.Fatal("expected a vultr batch")
+	}
+	ids := vultr.instanceIDs()
+	if len(ids) != 2 || ids[0] != "v-1" || ids[1] != "v-2" {
+		t.Errorf("vultr batch instance IDs = %v, want [v-1 v-2]", ids)
+	}
+	if vultr.nodes["v-2"] != "vultr-b" {
+		t.Errorf("vultr batch node for v-2 = %q, want vultr-b", vultr.nodes["v-2"])
+	}
+
+	other := batches["gcp/other-project"]
+	if other == nil || other.account != "other-project" || len(other.nodes) != 1 {
+		t.Errorf("expected a separate batch for the other GCP project, got %+v", other)
+	}
+}
+
+func TestCleanupPoolNodesTerminatesUnregisteredInstances(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = tgpv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name: "registered",
+		Labels: map[string]string{
+			"tgp.io/nodepool":                "test-pool",
+			tgpv1.NodeLabelNodePoolNamespace: "default",
+			"tgp.io/instance-id":             "i-registered",
+			tgpv1.NodeLabelProvider:          "aws",
+		},
+	}}
+	nodePool := &tgpv1.GPUNodePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pool", Namespace: "default"},
+		Status: tgpv1.GPUNodePoolStatus{Instances: []tgpv1.PoolInstance{
+			{InstanceID: "i-registered", Provider: "aws", Phase: tgpv1.PoolInstancePhaseLaunched},
+			{InstanceID: "i-unregistered", Provider: "aws", Phase: tgpv1.PoolInstancePhaseLaunched},
+			{InstanceID: "i-gone", Provider: "aws", Phase: tgpv1.PoolInstancePhaseReady},
+		}},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node).Build()
+	r := &GPUNodePoolReconciler{Client: client, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+	providerClient := &instanceClient{}
+	useFakeAWS(r, providerClient)
+
+	ctx := context.Background()
+	if _, err := r.cleanupPoolNodes(ctx, nodePool, logr.Discard()); err != nil {
+		t.Fatalf("cleanupPoolNodes failed: %v", err)
+	}
+
+	// Instances whose node was already removed went with it
+	sort.Strings(providerClient.terminated)
+	if len(providerClient.terminated) != 2 || providerClient.terminated[0] != "i-registered" || providerClient.terminated[1] != "i-unregistered" {
+		t.Errorf("terminated %v, want [i-registered i-unregistered]", providerClient.terminated)
+	}
+	if err := client.Get(ctx, types.NamespacedName{Name: "registered"}, &corev1.Node{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the node to be deleted, got: %v", err)
+	}
+	for _, tracked := range nodePool.Status.Instances {
+		if tracked.InstanceID == "i-unregistered" {
+			t.Error("expected the terminated unregistered instance to be untracked")
+		}
+	}
+}
+
+func TestGPUNodePoolReconciler_handleDeletion(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = tgpv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	tests := []struct {
+		name          string
+		terminateErr  error
+		wantFinalizer bool
+	}{
+		{name: "finalizer removed once instances are terminated"},
+		{name: "finalizer kept while termination fails", terminateErr: errors.New("throttled"), wantFinalizer: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := metav1.Now()
+			nodePool := &tgpv1.GPUNodePool{ObjectMeta: metav1.ObjectMeta{
+				Name:              "test-pool",
+				Namespace:         "default",
+				Finalizers:        []string{GPUNodePoolFinalizerName},
+				DeletionTimestamp: &now,
+			}}
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+				Name: "gpu-node",
+				Labels: map[string]string{
+					"tgp.io/nodepool":                "test-pool",
+					tgpv1.NodeLabelNodePoolNamespace: "default",
+					"tgp.io/instance-id":             "i-123",
+					tgpv1.NodeLabelProvider:          "aws",
+				},
+			}}
+			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(nodePool, node).WithStatusSubresource(nodePool).Build()
+			r := &GPUNodePoolReconciler{Client: client, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+			useFakeAWS(r, &instanceClient{terminateErr: tt.terminateErr})
+
+			ctx := context.Background()
+			result, err := r.handleDeletion(ctx, nodePool, logr.Discard())
+			if err != nil {
+				t.Fatalf("handleDeletion failed: %v", err)
+			}
+
+			var pool tgpv1.GPUNodePool
+			err = client.Get(ctx, types.NamespacedName{Name: "test-pool", Namespace: "default"}, &pool)
+			if !tt.wantFinalizer {
+				if !apierrors.IsNotFound(err) {
+					t.Errorf("expected the pool to be released, got: %v", err)
+				}
+				return
+			}
+			if err != nil || !controllerutil.ContainsFinalizer(&pool, GPUNodePoolFinalizerName) {
+				t.Fatalf("expected the finalizer to be kept, got %v", err)
+			}
+			if result.RequeueAfter != terminationRetryInterval {
+				t.Errorf("expected a retry after %v, got %v", terminationRetryInterval, result.RequeueAfter)
+			}
+			if err := client.Get(ctx, types.NamespacedName{Name: "gpu-node"}, &corev1.Node{}); err != nil {
+				t.Errorf("expected the node to be kept, got: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// offerRequirement is a constraint of the node class that at least one of a provider's
+// available offers has to meet for the provider to be considered
+type offerRequirement struct {
+	// name is what the requirement checks, logged when the provider's offers cannot be listed
+	name string
+	// reason explains why a provider none of whose offers meet the requirement is excluded
+	reason string
+	// allowed reports whether an offer meets the requirement
+	allowed func(offer providers.GPUOffer) bool
+}
+
+// offerRequirementsForClass returns the node class's constraints on individual offers
+func offerRequirementsForClass(nodeClass *tgpv1.GPUNodeClass) []offerRequirement {
+	requirements := nodeClass.Spec.InstanceRequirements
+	var offerRequirements []offerRequirement
+	if verifiedOnly(nodeClass.Spec.QualityPolicy) {
+		offerRequirements = append(offerRequirements, offerRequirement{
+			name:    "verified hosts",
+			reason:  "no offers are on verified hosts",
+			allowed: func(offer providers.GPUOffer) bool { return offer.Verified },
+		})
+	}
+	if minPerGPU := minVCPUPerGPU(requirements); minPerGPU > 0 {
+		offerRequirements = append(offerRequirements, offerRequirement{
+			name:    "vCPUs per GPU",
+			reason:  fmt.Sprintf("no offers have at least %d vCPUs per GPU", minPerGPU),
+			allowed: func(offer providers.GPUOffer) bool { return providers.VCPUsPerGPUAllowed(offer, minPerGPU) },
+		})
+	}
+	if minGiB := minGPUMemoryGiB(requirements); minGiB > 0 {
+		offerRequirements = append(offerRequirements, offerRequirement{
+			name:    "GPU memory",
+			reason:  fmt.Sprintf("no offers have at least %dGiB of memory per GPU", minGiB),
+			allowed: func(offer providers.GPUOffer) bool { return providers.GPUMemoryAllowed(offer, minGiB) },
+		})
+	}
+	if minVCPUs, minMemoryGiB := minInstanceSize(requirements); minVCPUs > 0 || minMemoryGiB > 0 {
+		offerRequirements = append(offerRequirements, offerRequirement{
+			name:    "instance size",
+			reason:  fmt.Sprintf("no offers have at least %d vCPUs and %dGiB of memory", minVCPUs, minMemoryGiB),
+			allowed: func(offer providers.GPUOffer) bool { return instanceSizeAllowed(offer, minVCPUs, minMemoryGiB) },
+		})
+	}
+	if minVersion := minCUDAVersion(requirements); minVersion != "" {
+		offerRequirements = append(offerRequirements, offerRequirement{
+			name:    "CUDA version",
+			reason:  fmt.Sprintf("no offers support CUDA %s", minVersion),
+			allowed: func(offer providers.GPUOffer) bool { return providers.CUDAVersionAllowed(offer, minVersion) },
+		})
+	}
+	return offerRequirements
+}
+
+// offerExclusionReason lists the provider's offers for the requirement once and returns why
+// they cannot satisfy it: no capacity for the GPU type in its region, so launches are not
+// attempted against providers that are sold out there, or no available offer meeting one of
+// the offer requirements. It returns "" if the provider can be considered.
+func (r *GPUNodePoolReconciler) offerExclusionReason(ctx context.Context, providerClient providers.ProviderClient, requirement *GPURequirement, offerRequirements []offerRequirement) (string, error) {
+	offers, err := providerClient.ListAvailableGPUs(ctx, &providers.GPUFilters{
+		GPUType: requirement.GPUType,
+		Region:  requirement.Region,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	available := make([]providers.GPUOffer, 0, len(offers))
+	for _, offer := range offers {
+		if offer.Available {
+			available = append(available, offer)
+		}
+	}
+	if len(available) == 0 {
+		return fmt.Sprintf("no capacity for %s in region %s", requirement.GPUType, requirement.Region), nil
+	}
+	for _, offerRequirement := range offerRequirements {
+		if !slices.ContainsFunc(available, offerRequirement.allowed) {
+			return offerRequirement.reason, nil
+		}
+	}
+	return "", nil
+}
+
+// excludeFailedProvider records that launching the requirement on the provider failed, so the
+// next selection falls back to the next best provider
+func excludeFailedProvider(requirement *GPURequirement, providerName string) {
+	if requirement.FailedProviders == nil {
+		requirement.FailedProviders = make(map[string]bool)
+	}
+	requirement.FailedProviders[providerName] = true
+}
@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// regionalOffersClient reports the offers it holds for the requested region
+type regionalOffersClient struct {
+	providers.ProviderClient
+	offers map[string][]providers.GPUOffer
+}
+
+func (c *regionalOffersClient) ListAvailableGPUs(ctx context.Context, filters *providers.GPUFilters) ([]providers.GPUOffer, error) {
+	return c.offers[filters.Region], nil
+}
+
+func TestOfferExclusionReason(t *testing.T) {
+	client := &regionalOffersClient{offers: map[string][]providers.GPUOffer{
+		"ewr": {
+			{GPUType: "H100", Region: "ewr", Available: true, Verified: true, GPUCount: 1, VCPUs: 8, Memory: 64, CUDAVersion: "12.4"},
+			{GPUType: "H100", Region: "ewr", Available: false, Verified: true, GPUCount: 1, VCPUs: 64, Memory: 512},
+		},
+		"lax": {{GPUType: "H100", Region: "lax", Available: false}},
+	}}
+	r := &GPUNodePoolReconciler{}
+
+	classWith := func(requirements *tgpv1.InstanceRequirements) *tgpv1.GPUNodeClass {
+		return &tgpv1.GPUNodeClass{Spec: tgpv1.GPUNodeClassSpec{InstanceRequirements: requirements}}
+	}
+	int32Ptr := func(v int32) *int32 { return &v }
+
+	tests := []struct {
+		name      string
+		region    string
+		nodeClass *tgpv1.GPUNodeClass
+		want      string
+	}{
+		{name: "available offer", region: "ewr", nodeClass: classWith(nil)},
+		{name: "sold out", region: "lax", nodeClass: classWith(nil), want: "no capacity for H100 in region lax"},
+		{name: "no offers", region: "fra", nodeClass: classWith(nil), want: "no capacity for H100 in region fra"},
+		{
+			name:      "every requirement met by an available offer",
+			region:    "ewr",
+			nodeClass: classWith(&tgpv1.InstanceRequirements{MinVCPUPerGPU: int32Ptr(8), MinMemoryGiB: int32Ptr(64), MinCUDAVersion: "12.1"}),
+		},
+		{
+			name:      "only an unavailable offer is large enough",
+			region:    "ewr",
+			nodeClass: classWith(&tgpv1.InstanceRequirements{MinVCPU: int32Ptr(32)}),
+			want:      "no offers have at least 32 vCPUs and 0GiB of memory",
+		},
+		{
+			name:      "driver too old",
+			region:    "ewr",
+			nodeClass: classWith(&tgpv1.InstanceRequirements{MinCUDAVersion: "12.6"}),
+			want:      "no offers support CUDA 12.6",
+		},
+		{
+			name:   "verified hosts",
+			region: "ewr",
+			nodeClass: &tgpv1.GPUNodeClass{Spec: tgpv1.GPUNodeClassSpec{
+				QualityPolicy: &tgpv1.QualityPolicy{VerifiedOnly: true},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.offerExclusionReason(context.Background(), client, &GPURequirement{GPUType: "H100", Region: tt.region},
+				offerRequirementsForClass(tt.nodeClass))
+			if err != nil {
+				t.Fatalf("offerExclusionReason failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("offerExclusionReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExcludeFailedProvider(t *testing.T) {
+	requirement := &GPURequirement{GPUType: "H100"}
+	excludeFailedProvider(requirement, "vultr")
+	excludeFailedProvider(requirement, "gcp")
+
+	if !requirement.FailedProviders["vultr"] || !requirement.FailedProviders["gcp"] || requirement.FailedProviders["aws"] {
+		t.Errorf("unexpected failed providers %v", requirement.FailedProviders)
+	}
+}
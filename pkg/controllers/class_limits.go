@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+const (
+	// AnnotationHourlyPrice records the estimated hourly price of a node when it was provisioned
+	AnnotationHourlyPrice = "tgp.io/hourly-price"
+
+	// EventReasonLimitExceeded is emitted when the node class limits block provisioning
+	EventReasonLimitExceeded = tgpv1.LimitExceededReasonExceeded
+)
+
+// errClassLimitExceeded is returned when a launch would exceed the node class limits
+var errClassLimitExceeded = errors.New("node class limit exceeded")
+
+// isClassLimitExceeded reports whether a provisioning error was caused by the node class limits
+func isClassLimitExceeded(err error) bool {
+	return errors.Is(err, errClassLimitExceeded)
+}
+
+// nodeClassUsage is the number of nodes launched from a node class and their estimated hourly cost
+type nodeClassUsage struct {
+	nodes      int
+	hourlyCost float64
+}
+
+// nodeClassUsage counts the nodes launched from the node class by any pool and sums their
+// recorded hourly prices. Nodes without a recorded price count towards the node limit only.
+func (r *GPUNodePoolReconciler) nodeClassUsage(ctx context.Context, nodeClass *tgpv1.GPUNodeClass) (nodeClassUsage, error) {
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes, client.MatchingLabels{tgpv1.NodeLabelNodeClass: nodeClass.Name}); err != nil {
+		return nodeClassUsage{}, fmt.Errorf("failed to list nodes of node class %s: %w", nodeClass.Name, err)
+	}
+
+	usage := nodeClassUsage{nodes: len(nodes.Items)}
+	for _, node := range nodes.Items {
+		if price, err := strconv.ParseFloat(node.Annotations[AnnotationHourlyPrice], 64); err == nil {
+			usage.hourlyCost += price
+		}
+	}
+	return usage, nil
+}
+
+// checkNodeClassNodeLimit returns an errClassLimitExceeded error if launching another node
+// would exceed the node class MaxNodes
+func checkNodeClassNodeLimit(nodeClass *tgpv1.GPUNodeClass, usage nodeClassUsage) error {
+	if nodeClass.Spec.Limits == nil || nodeClass.Spec.Limits.MaxNodes == nil {
+		return nil
+	}
+	maxNodes := int(*nodeClass.Spec.Limits.MaxNodes)
+	if usage.nodes+1 > maxNodes {
+		return fmt.Errorf("%w: node class %s has %d of %d nodes", errClassLimitExceeded, nodeClass.Name, usage.nodes, maxNodes)
+	}
+	return nil
+}
+
+// checkNodeClassCostLimit returns an errClassLimitExceeded error if adding a node at the given
+// hourly price would exceed the node class MaxHourlyCost
+func checkNodeClassCostLimit(nodeClass *tgpv1.GPUNodeClass, usage nodeClassUsage, hourlyPrice float64) error {
+	maxCost, ok := maxHourlyCost(nodeClass)
+	if !ok {
+		return nil
+	}
+	if projected := usage.hourlyCost + hourlyPrice; projected > maxCost {
+		return fmt.Errorf("%w: node class %s would cost $%.2f/hour, over its $%.2f/hour limit",
+			errClassLimitExceeded, nodeClass.Name, projected, maxCost)
+	}
+	return nil
+}
+
+// maxHourlyCost returns the node class MaxHourlyCost in USD, accepting an optional leading
+// dollar sign, and whether one is set
+func maxHourlyCost(nodeClass *tgpv1.GPUNodeClass) (float64, bool) {
+	if nodeClass.Spec.Limits == nil || nodeClass.Spec.Limits.MaxHourlyCost == nil {
+		return 0, false
+	}
+	cost, err := strconv.ParseFloat(strings.TrimPrefix(strings.TrimSpace(*nodeClass.Spec.Limits.MaxHourlyCost), "$"), 64)
+	if err != nil {
+		return 0, false
+	}
+	return cost, true
+}
+
+// hasNodeClassLimits reports whether the node class sets a node or cost limit
+func hasNodeClassLimits(nodeClass *tgpv1.GPUNodeClass) bool {
+	if nodeClass.Spec.Limits == nil {
+		return false
+	}
+	_, hasCostLimit := maxHourlyCost(nodeClass)
+	return nodeClass.Spec.Limits.MaxNodes != nil || hasCostLimit
+}
+
+// updateLimitCondition records on the pool whether the node class limits blocked provisioning,
+// emitting a warning event when they start to
+func (r *GPUNodePoolReconciler) updateLimitCondition(nodePool *tgpv1.GPUNodePool, nodeClass *tgpv1.GPUNodeClass, limitErr error) {
+	if limitErr == nil {
+		if !hasNodeClassLimits(nodeClass) {
+			meta.RemoveStatusCondition(&nodePool.Status.Conditions, tgpv1.ConditionTypeLimitExceeded)
+			return
+		}
+		r.updateCondition(nodePool, tgpv1.ConditionTypeLimitExceeded, metav1.ConditionFalse, tgpv1.LimitExceededReasonWithinLimits,
+			fmt.Sprintf("Node class %s has capacity for more nodes", nodeClass.Name))
+		return
+	}
+
+	if !meta.IsStatusConditionTrue(nodePool.Status.Conditions, tgpv1.ConditionTypeLimitExceeded) {
+		r.recordEvent(nodePool, corev1.EventTypeWarning, EventReasonLimitExceeded, limitErr.Error())
+	}
+	r.updateCondition(nodePool, tgpv1.ConditionTypeLimitExceeded, metav1.ConditionTrue, tgpv1.LimitExceededReasonExceeded, limitErr.Error())
+}
@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+func TestNodeClassUsage(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	node := func(name, nodeClass, price string) *corev1.Node {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{tgpv1.NodeLabelNodeClass: nodeClass},
+		}}
+		if price != "" {
+			node.Annotations = map[string]string{AnnotationHourlyPrice: price}
+		}
+		return node
+	}
+
+	r := &GPUNodePoolReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			node("a", "gpu", "2.5"),
+			node("b", "gpu", "1.25"),
+			node("c", "gpu", ""),
+			node("d", "other", "9"),
+		).Build(),
+	}
+
+	usage, err := r.nodeClassUsage(context.Background(), &tgpv1.GPUNodeClass{ObjectMeta: metav1.ObjectMeta{Name: "gpu"}})
+	if err != nil {
+		t.Fatalf("nodeClassUsage() error = %v", err)
+	}
+	if usage.nodes != 3 || usage.hourlyCost != 3.75 {
+		t.Errorf("nodeClassUsage() = %+v, want 3 nodes costing 3.75/hour", usage)
+	}
+}
+
+func TestNodeClassLimits(t *testing.T) {
+	nodeClass := func(limits *tgpv1.NodeClassLimits) *tgpv1.GPUNodeClass {
+		return &tgpv1.GPUNodeClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "gpu"},
+			Spec:       tgpv1.GPUNodeClassSpec{Limits: limits},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		limits      *tgpv1.NodeClassLimits
+		usage       nodeClassUsage
+		hourlyPrice float64
+		wantNodeErr bool
+		wantCostErr bool
+	}{
+		{name: "no limits", usage: nodeClassUsage{nodes: 100, hourlyCost: 1000}, hourlyPrice: 10},
+		{name: "below max nodes", limits: &tgpv1.NodeClassLimits{MaxNodes: int32Ptr(3)}, usage: nodeClassUsage{nodes: 2}},
+		{name: "at max nodes", limits: &tgpv1.NodeClassLimits{MaxNodes: int32Ptr(2)}, usage: nodeClassUsage{nodes: 2}, wantNodeErr: true},
+		{
+			name:        "within cost",
+			limits:      &tgpv1.NodeClassLimits{MaxHourlyCost: stringPtr("10.00")},
+			usage:       nodeClassUsage{hourlyCost: 6},
+			hourlyPrice: 4,
+		},
+		{
+			name:        "over cost with dollar sign",
+			limits:      &tgpv1.NodeClassLimits{MaxHourlyCost: stringPtr("$10")},
+			usage:       nodeClassUsage{hourlyCost: 6},
+			hourlyPrice: 4.5,
+			wantCostErr: true,
+		},
+		{
+			name:        "unparseable cost is ignored",
+			limits:      &tgpv1.NodeClassLimits{MaxHourlyCost: stringPtr("lots")},
+			usage:       nodeClassUsage{hourlyCost: 6},
+			hourlyPrice: 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			class := nodeClass(tt.limits)
+			if err := checkNodeClassNodeLimit(class, tt.usage); (err != nil) != tt.wantNodeErr || (err != nil && !isClassLimitExceeded(err)) {
+				t.Errorf("checkNodeClassNodeLimit() error = %v, want error %v", err, tt.wantNodeErr)
+			}
+			if err := checkNodeClassCostLimit(class, tt.usage, tt.hourlyPrice); (err != nil) != tt.wantCostErr || (err != nil && !isClassLimitExceeded(err)) {
+				t.Errorf("checkNodeClassCostLimit() error = %v, want error %v", err, tt.wantCostErr)
+			}
+		})
+	}
+}
+
+func TestUpdateLimitCondition(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	r := &GPUNodePoolReconciler{Recorder: recorder}
+	nodePool := &tgpv1.GPUNodePool{}
+	nodeClass := &tgpv1.GPUNodeClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpu"},
+		Spec:       tgpv1.GPUNodeClassSpec{Limits: &tgpv1.NodeClassLimits{MaxNodes: int32Ptr(1)}},
+	}
+
+	limitErr := fmt.Errorf("%w: full", errClassLimitExceeded)
+	r.updateLimitCondition(nodePool, nodeClass, limitErr)
+	r.updateLimitCondition(nodePool, nodeClass, limitErr)
+	if !meta.IsStatusConditionTrue(nodePool.Status.Conditions, tgpv1.ConditionTypeLimitExceeded) {
+		t.Error("expected LimitExceeded to be true")
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("expected one event while the limit stays exceeded, got %d", len(recorder.Events))
+	}
+
+	r.updateLimitCondition(nodePool, nodeClass, nil)
+	if !meta.IsStatusConditionFalse(nodePool.Status.Conditions, tgpv1.ConditionTypeLimitExceeded) {
+		t.Error("expected LimitExceeded to be false once within limits")
+	}
+
+	nodeClass.Spec.Limits = nil
+	r.updateLimitCondition(nodePool, nodeClass, nil)
+	if meta.FindStatusCondition(nodePool.Status.Conditions, tgpv1.ConditionTypeLimitExceeded) != nil {
+		t.Error("expected LimitExceeded to be removed when the node class has no limits")
+	}
+}
+
+func int32Ptr(i int32) *int32 { return &i }
+
+func stringPtr(s string) *string { return &s }
@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"context"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+// nodeClassesForConfigChange requeues every GPUNodeClass when the operator configuration changes,
+// so their provider conditions follow the enabled providers
+func (r *GPUNodeClassReconciler) nodeClassesForConfigChange(ctx context.Context, _ client.Object) []ctrl.Request {
+	var nodeClasses tgpv1.GPUNodeClassList
+	if err := r.List(ctx, &nodeClasses); err != nil {
+		r.Log.Error(err, "Failed to list GPUNodeClasses after an operator configuration change")
+		return nil
+	}
+
+	requests := make([]ctrl.Request, 0, len(nodeClasses.Items))
+	for i := range nodeClasses.Items {
+		requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&nodeClasses.Items[i])})
+	}
+	return requests
+}
+
+// nodePoolsForConfigChange requeues every GPUNodePool when the operator configuration changes,
+// so pools waiting on a provider or setting retry with the new configuration
+func (r *GPUNodePoolReconciler) nodePoolsForConfigChange(ctx context.Context, _ client.Object) []ctrl.Request {
+	var nodePools tgpv1.GPUNodePoolList
+	if err := r.List(ctx, &nodePools); err != nil {
+		r.Log.Error(err, "Failed to list GPUNodePools after an operator configuration change")
+		return nil
+	}
+
+	requests := make([]ctrl.Request, 0, len(nodePools.Items))
+	for i := range nodePools.Items {
+		requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&nodePools.Items[i])})
+	}
+	return requests
+}
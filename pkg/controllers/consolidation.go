@@ -0,0 +1,323 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+const (
+	// AnnotationUnderutilizedSince marks when a node was first found to be consolidatable,
+	// either idle or underutilized depending on the pool's consolidation policy
+	AnnotationUnderutilizedSince = "tgp.io/underutilized-since"
+
+	// defaultConsolidateAfter is how long a node must stay underutilized before it is consolidated when unset
+	defaultConsolidateAfter = 5 * time.Minute
+
+	// consolidationPollInterval is how often underutilized nodes are re-evaluated
+	consolidationPollInterval = time.Minute
+)
+
+// Event reasons emitted during consolidation
+const (
+	EventReasonNodeIdle          = "NodeIdle"
+	EventReasonNodeUnderutilized = "NodeUnderutilized"
+	EventReasonNodeConsolidated  = "NodeConsolidated"
+)
+
+// consolidationCandidate is a node whose workloads fit on the pool's other nodes
+type consolidationCandidate struct {
+	node *corev1.Node
+	// gpuRequests is the number of GPUs requested by pods on the node
+	gpuRequests int64
+	since       time.Time
+}
+
+// reconcileConsolidation removes idle or underutilized nodes from pools using the WhenIdle or
+// WhenUnderutilized consolidation policy. A node is idle when no workload pods requesting GPUs
+// run on it, and underutilized when every workload pod on it could be rescheduled onto the
+// spare GPU capacity of the pool's other nodes. Once a node has been idle or underutilized for
+// ConsolidateAfter it is drained and its instance terminated, one node per pass so rescheduled
+// pods are accounted for before the next decision.
+// It returns how long until consolidation needs to be checked again, or 0 if nothing is pending.
+func (r *GPUNodePoolReconciler) reconcileConsolidation(ctx context.Context, nodePool *tgpv1.GPUNodePool, log logr.Logger) (time.Duration, error) {
+	if nodePool.Spec.Disruption == nil {
+		return 0, nil
+	}
+	policy := nodePool.Spec.Disruption.ConsolidationPolicy
+	var consolidatable func(node *corev1.Node, nodes []*corev1.Node, workloads map[string][]*corev1.Pod) bool
+	switch policy {
+	case tgpv1.ConsolidationPolicyWhenIdle:
+		consolidatable = isIdle
+	case tgpv1.ConsolidationPolicyWhenUnderutilized:
+		consolidatable = canRescheduleElsewhere
+	default:
+		return 0, nil
+	}
+
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes, poolNodeSelector(nodePool)); err != nil {
+		return 0, fmt.Errorf("failed to list nodes for pool %s: %w", nodePool.Name, err)
+	}
+
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods); err != nil {
+		return 0, fmt.Errorf("failed to list pods: %w", err)
+	}
+	workloads := r.workloadPodsByNode(pods.Items)
+
+	// Finish removing a node whose drain an earlier pass started; it is cordoned by now
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if node.DeletionTimestamp != nil || node.Annotations[AnnotationDrainStartedAt] == "" {
+			continue
+		}
+		if _, marked := underutilizedSince(node); marked {
+			return r.removeConsolidatedNode(ctx, nodePool, node, consolidationReason(policy), log)
+		}
+	}
+
+	// Only ready, schedulable nodes take part; cordoned nodes are booting or already being removed
+	var active []*corev1.Node
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if node.DeletionTimestamp == nil && !node.Spec.Unschedulable && len(nodeGPUCapacity(node)) > 0 {
+			active = append(active, node)
+		}
+	}
+
+	consolidateAfter := consolidateAfter(nodePool)
+	now := time.Now()
+	var next time.Duration
+	var ready []consolidationCandidate
+	for _, node := range active {
+		since, marked := underutilizedSince(node)
+		if !consolidatable(node, active, workloads) {
+			if marked {
+				if err := r.setUnderutilizedSince(ctx, node, nil); err != nil {
+					return 0, err
+				}
+				log.V(1).Info("Node is no longer consolidatable", "node", node.Name, "policy", policy)
+			}
+			continue
+		}
+
+		if !marked {
+			since = now
+			if err := r.setUnderutilizedSince(ctx, node, &since); err != nil {
+				return 0, err
+			}
+			if policy == tgpv1.ConsolidationPolicyWhenIdle {
+				r.recordEvent(nodePool, corev1.EventTypeNormal, EventReasonNodeIdle,
+					fmt.Sprintf("Node %s is idle and will be removed after %s", node.Name, consolidateAfter))
+			} else {
+				r.recordEvent(nodePool, corev1.EventTypeNormal, EventReasonNodeUnderutilized,
+					fmt.Sprintf("Node %s is underutilized and will be consolidated after %s", node.Name, consolidateAfter))
+			}
+		}
+
+		if wait := since.Add(consolidateAfter).Sub(now); wait > 0 {
+			wait = min(wait, consolidationPollInterval)
+			if next == 0 || wait < next {
+				next = wait
+			}
+			continue
+		}
+		ready = append(ready, consolidationCandidate{node: node, gpuRequests: gpuRequestCount(workloads[node.Name]), since: since})
+	}
+
+	if len(ready) == 0 {
+		return next, nil
+	}
+
+	// Remove the node with the least work to move, breaking ties by how long it has been underutilized
+	sort.Slice(ready, func(i, j int) bool {
+		if ready[i].gpuRequests != ready[j].gpuRequests {
+			return ready[i].gpuRequests < ready[j].gpuRequests
+		}
+		if !ready[i].since.Equal(ready[j].since) {
+			return ready[i].since.Before(ready[j].since)
+		}
+		return ready[i].node.Name < ready[j].node.Name
+	})
+	candidate := ready[0]
+
+	reason := consolidationReason(policy)
+	if policy == tgpv1.ConsolidationPolicyWhenIdle {
+		r.recordEvent(nodePool, corev1.EventTypeNormal, EventReasonNodeConsolidated,
+			fmt.Sprintf("Removing node %s: it has run no GPU workloads for %s", candidate.node.Name, consolidateAfter))
+		r.Metrics.RecordIdleTimeout(candidate.node.Labels[tgpv1.NodeLabelProvider], candidate.node.Labels[tgpv1.NodeLabelGPUType])
+	} else {
+		r.recordEvent(nodePool, corev1.EventTypeNormal, EventReasonNodeConsolidated,
+			fmt.Sprintf("Consolidating node %s: its %d workload pods requesting %d GPUs fit on the pool's other nodes",
+				candidate.node.Name, len(workloads[candidate.node.Name]), candidate.gpuRequests))
+	}
+
+	return r.removeConsolidatedNode(ctx, nodePool, candidate.node, reason, log)
+}
+
+// removeConsolidatedNode drains a consolidated node and terminates its instance. While its
+// evicted pods are still terminating, the node is checked again after drainPollInterval.
+func (r *GPUNodePoolReconciler) removeConsolidatedNode(ctx context.Context, nodePool *tgpv1.GPUNodePool, node *corev1.Node, reason tgpv1.TerminationReason, log logr.Logger) (time.Duration, error) {
+	nodeClass, err := r.getNodeClass(ctx, nodePool)
+	if err != nil {
+		log.V(1).Info("Node class unavailable, using default credentials namespace", "error", err.Error())
+		nodeClass = &tgpv1.GPUNodeClass{}
+	}
+	_, providerName := nodeInstance(node)
+	if err := r.cleanupNode(ctx, node, classCredentialsNamespace(nodeClass, providerName), reason, log); isDrainPending(err) {
+		log.Info("Waiting for node to drain", "node", node.Name, "reason", err.Error())
+		return drainPollInterval, nil
+	} else if err != nil {
+		return terminationRetryInterval, fmt.Errorf("failed to consolidate node %s: %w", node.Name, err)
+	}
+
+	// Re-evaluate the remaining nodes once the drained pods have been rescheduled
+	return consolidationPollInterval, nil
+}
+
+// consolidationReason returns the termination reason recorded for nodes removed under the policy
+func consolidationReason(policy tgpv1.ConsolidationPolicy) tgpv1.TerminationReason {
+	if policy == tgpv1.ConsolidationPolicyWhenIdle {
+		return tgpv1.TerminationReasonIdle
+	}
+	return tgpv1.TerminationReasonConsolidated
+}
+
+// workloadPodsByNode groups the running workload pods by the node they are bound to,
+// leaving out DaemonSet and static pods that are not rescheduled elsewhere
+func (r *GPUNodePoolReconciler) workloadPodsByNode(pods []corev1.Pod) map[string][]*corev1.Pod {
+	byNode := make(map[string][]*corev1.Pod)
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Spec.NodeName == "" || pod.DeletionTimestamp != nil {
+			continue
+		}
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if r.isDaemonSetPod(pod) || r.isStaticPod(pod) {
+			continue
+		}
+		byNode[pod.Spec.NodeName] = append(byNode[pod.Spec.NodeName], pod)
+	}
+	return byNode
+}
+
+// isIdle reports whether no workload pods requesting GPUs run on the node
+func isIdle(node *corev1.Node, _ []*corev1.Node, workloads map[string][]*corev1.Pod) bool {
+	return gpuRequestCount(workloads[node.Name]) == 0
+}
+
+// canRescheduleElsewhere reports whether every workload pod on the node would fit on the
+// spare GPU capacity of the other nodes, placing the pods one at a time
+func canRescheduleElsewhere(node *corev1.Node, nodes []*corev1.Node, workloads map[string][]*corev1.Pod) bool {
+	var targets []*corev1.Node
+	for _, other := range nodes {
+		if other.Name == node.Name {
+			continue
+		}
+		target := other.DeepCopy()
+		target.Status.Allocatable = spareGPUCapacity(other, workloads[other.Name])
+		targets = append(targets, target)
+	}
+
+	for _, pod := range workloads[node.Name] {
+		placed := false
+		for _, target := range targets {
+			if simulatePodScheduling(pod, target) != nil {
+				continue
+			}
+			for name, requested := range podGPURequests(pod) {
+				spare := target.Status.Allocatable[name]
+				spare.Sub(requested)
+				target.Status.Allocatable[name] = spare
+			}
+			placed = true
+			break
+		}
+		if !placed {
+			return false
+		}
+	}
+	return true
+}
+
+// nodeGPUCapacity returns the node's allocatable GPU resources
+func nodeGPUCapacity(node *corev1.Node) corev1.ResourceList {
+	capacity := corev1.ResourceList{}
+	for _, name := range gpuResourceNames {
+		if quantity, exists := node.Status.Allocatable[name]; exists && !quantity.IsZero() {
+			capacity[name] = quantity.DeepCopy()
+		}
+	}
+	return capacity
+}
+
+// spareGPUCapacity returns the node's allocatable GPU resources not requested by its pods
+func spareGPUCapacity(node *corev1.Node, pods []*corev1.Pod) corev1.ResourceList {
+	spare := nodeGPUCapacity(node)
+	for _, pod := range pods {
+		for name, requested := range podGPURequests(pod) {
+			if quantity, exists := spare[name]; exists {
+				quantity.Sub(requested)
+				spare[name] = quantity
+			}
+		}
+	}
+	return spare
+}
+
+// gpuRequestCount sums the GPUs requested by the pods across all GPU resources
+func gpuRequestCount(pods []*corev1.Pod) int64 {
+	var total int64
+	for _, pod := range pods {
+		for _, quantity := range podGPURequests(pod) {
+			total += quantity.Value()
+		}
+	}
+	return total
+}
+
+// underutilizedSince returns when the node was marked as underutilized
+func underutilizedSince(node *corev1.Node) (time.Time, bool) {
+	value, exists := node.Annotations[AnnotationUnderutilizedSince]
+	if !exists {
+		return time.Time{}, false
+	}
+	since, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return since, true
+}
+
+// setUnderutilizedSince marks the node as underutilized since the given time, or clears the mark when nil
+func (r *GPUNodePoolReconciler) setUnderutilizedSince(ctx context.Context, node *corev1.Node, since *time.Time) error {
+	if since == nil {
+		delete(node.Annotations, AnnotationUnderutilizedSince)
+	} else {
+		if node.Annotations == nil {
+			node.Annotations = make(map[string]string)
+		}
+		node.Annotations[AnnotationUnderutilizedSince] = since.Format(time.RFC3339)
+	}
+	if err := r.Update(ctx, node); err != nil {
+		return fmt.Errorf("failed to update node %s: %w", node.Name, err)
+	}
+	return nil
+}
+
+// consolidateAfter returns how long a node must stay underutilized before it is consolidated
+func consolidateAfter(nodePool *tgpv1.GPUNodePool) time.Duration {
+	if nodePool.Spec.Disruption != nil && nodePool.Spec.Disruption.ConsolidateAfter != nil {
+		return nodePool.Spec.Disruption.ConsolidateAfter.Duration
+	}
+	return defaultConsolidateAfter
+}
@@ -0,0 +1,224 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+func TestReconcileConsolidation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = tgpv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	poolNode := func(name string, gpus int64, annotations map[string]string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Labels:      map[string]string{"tgp.io/nodepool": "test-pool", tgpv1.NodeLabelNodePoolNamespace: "default"},
+				Annotations: annotations,
+			},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{"nvidia.com/gpu": *resource.NewQuantity(gpus, resource.DecimalSI)},
+			},
+		}
+	}
+	gpuPod := func(name, nodeName string, gpus int64) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: corev1.PodSpec{
+				NodeName: nodeName,
+				Containers: []corev1.Container{{
+					Name: "train",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{"nvidia.com/gpu": *resource.NewQuantity(gpus, resource.DecimalSI)},
+					},
+				}},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+	underutilizedFor := func(d time.Duration) map[string]string {
+		return map[string]string{AnnotationUnderutilizedSince: time.Now().Add(-d).Format(time.RFC3339)}
+	}
+
+	pool := func(policy tgpv1.ConsolidationPolicy) *tgpv1.GPUNodePool {
+		return &tgpv1.GPUNodePool{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-pool", Namespace: "default"},
+			Spec: tgpv1.GPUNodePoolSpec{
+				Disruption: &tgpv1.DisruptionSpec{
+					ConsolidationPolicy: policy,
+					ConsolidateAfter:    &metav1.Duration{Duration: 10 * time.Minute},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name              string
+		policy            tgpv1.ConsolidationPolicy
+		objects           []runtime.Object
+		expectRemoved     []string
+		expectMarked      []string
+		expectUnmarked    []string
+		expectNextAtLeast time.Duration
+	}{
+		{
+			name:   "newly underutilized nodes are marked and kept until ConsolidateAfter",
+			policy: tgpv1.ConsolidationPolicyWhenUnderutilized,
+			objects: []runtime.Object{
+				poolNode("busy", 4, nil), poolNode("light", 4, nil),
+				gpuPod("a", "busy", 2), gpuPod("b", "light", 1),
+			},
+			expectMarked:      []string{"busy", "light"},
+			expectNextAtLeast: time.Second,
+		},
+		{
+			name:   "node underutilized past ConsolidateAfter is removed",
+			policy: tgpv1.ConsolidationPolicyWhenUnderutilized,
+			objects: []runtime.Object{
+				poolNode("busy", 4, underutilizedFor(time.Hour)), poolNode("light", 4, underutilizedFor(time.Hour)),
+				gpuPod("a", "busy", 2), gpuPod("b", "light", 1),
+			},
+			expectRemoved: []string{"light"},
+			expectMarked:  []string{"busy"},
+		},
+		{
+			name:   "node whose pods do not fit elsewhere is unmarked",
+			policy: tgpv1.ConsolidationPolicyWhenUnderutilized,
+			objects: []runtime.Object{
+				poolNode("full", 4, underutilizedFor(time.Hour)), poolNode("other", 4, underutilizedFor(time.Hour)),
+				gpuPod("a", "full", 4), gpuPod("b", "other", 4),
+			},
+			expectUnmarked: []string{"full", "other"},
+		},
+		{
+			name:   "idle nodes are marked and kept until ConsolidateAfter",
+			policy: tgpv1.ConsolidationPolicyWhenIdle,
+			objects: []runtime.Object{
+				poolNode("busy", 4, nil), poolNode("idle", 4, nil),
+				gpuPod("a", "busy", 1),
+			},
+			expectMarked:      []string{"idle"},
+			expectUnmarked:    []string{"busy"},
+			expectNextAtLeast: time.Second,
+		},
+		{
+			name:   "node idle past ConsolidateAfter is removed",
+			policy: tgpv1.ConsolidationPolicyWhenIdle,
+			objects: []runtime.Object{
+				poolNode("busy", 4, underutilizedFor(time.Hour)), poolNode("idle", 4, underutilizedFor(time.Hour)),
+				gpuPod("a", "busy", 1),
+			},
+			expectRemoved:  []string{"idle"},
+			expectUnmarked: []string{"busy"},
+		},
+		{
+			name:   "other policies leave nodes alone",
+			policy: tgpv1.ConsolidationPolicyNever,
+			objects: []runtime.Object{
+				poolNode("busy", 4, nil), poolNode("light", 4, underutilizedFor(time.Hour)),
+			},
+			expectMarked: []string{"light"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodePool := pool(tt.policy)
+			objects := make([]runtime.Object, 0, len(tt.objects)+1)
+			objects = append(objects, nodePool)
+			for _, object := range tt.objects {
+				objects = append(objects, object.DeepCopyObject())
+			}
+			client := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build()
+
+			reconciler := &GPUNodePoolReconciler{
+				Client:   client,
+				Log:      logr.Discard(),
+				Scheme:   scheme,
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			ctx := context.Background()
+			next, err := reconciler.reconcileConsolidation(ctx, nodePool, logr.Discard())
+			if err != nil {
+				t.Fatalf("reconcileConsolidation failed: %v", err)
+			}
+			if next < tt.expectNextAtLeast {
+				t.Errorf("expected next check after at least %v, got %v", tt.expectNextAtLeast, next)
+			}
+
+			var node corev1.Node
+			for _, name := range tt.expectRemoved {
+				if err := client.Get(ctx, types.NamespacedName{Name: name}, &node); !apierrors.IsNotFound(err) {
+					t.Errorf("expected node %s to be consolidated, got: %v", name, err)
+				}
+			}
+			for _, name := range tt.expectMarked {
+				if err := client.Get(ctx, types.NamespacedName{Name: name}, &node); err != nil {
+					t.Fatalf("expected node %s to remain: %v", name, err)
+				}
+				if _, marked := underutilizedSince(&node); !marked {
+					t.Errorf("expected node %s to be marked as underutilized", name)
+				}
+			}
+			for _, name := range tt.expectUnmarked {
+				if err := client.Get(ctx, types.NamespacedName{Name: name}, &node); err != nil {
+					t.Fatalf("expected node %s to remain: %v", name, err)
+				}
+				if _, marked := underutilizedSince(&node); marked {
+					t.Errorf("expected node %s not to be marked as underutilized", name)
+				}
+			}
+		})
+	}
+}
+
+func TestCanRescheduleElsewhere(t *testing.T) {
+	node := func(name string, gpus int64) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{"nvidia.com/gpu": *resource.NewQuantity(gpus, resource.DecimalSI)},
+			},
+		}
+	}
+	pod := func(gpus int64) *corev1.Pod {
+		return &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{"nvidia.com/gpu": *resource.NewQuantity(gpus, resource.DecimalSI)},
+			},
+		}}}}
+	}
+
+	nodes := []*corev1.Node{node("a", 2), node("b", 2), node("c", 2)}
+
+	// Two single-GPU pods fit on one free GPU each on b and c
+	workloads := map[string][]*corev1.Pod{
+		"a": {pod(1), pod(1)},
+		"b": {pod(1)},
+		"c": {pod(1)},
+	}
+	if !canRescheduleElsewhere(nodes[0], nodes, workloads) {
+		t.Error("expected pods on a to fit on b and c")
+	}
+
+	// A two-GPU pod cannot be split across the free GPUs on b and c
+	workloads["a"] = []*corev1.Pod{pod(2)}
+	if canRescheduleElsewhere(nodes[0], nodes, workloads) {
+		t.Error("expected a two-GPU pod not to fit on one free GPU")
+	}
+}
@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/metrics"
+)
+
+// defaultCostInterval is how often the active cost is recomputed when no interval is configured
+const defaultCostInterval = time.Minute
+
+// ActiveCost is the hourly cost of the GPU nodes running in the cluster, along with how many
+// of them run on spot and on-demand capacity
+type ActiveCost struct {
+	Total      float64
+	ByProvider map[string]float64
+	ByGPUType  map[string]float64
+
+	SpotInstances     map[metrics.InstanceGroup]int
+	OnDemandInstances map[metrics.InstanceGroup]int
+}
+
+// CostTracker periodically sums the hourly price recorded on every node launched by the
+// operator and publishes the cluster-wide GPU spend, with per-provider and per-GPU-type
+// breakdowns, as metrics. It also publishes the number of spot and on-demand nodes.
+type CostTracker struct {
+	client.Client
+	Log     logr.Logger
+	Metrics *metrics.Metrics
+
+	// Interval is how often the cost is recomputed
+	Interval time.Duration
+}
+
+// Start publishes the active cost until the context is cancelled
+func (t *CostTracker) Start(ctx context.Context) error {
+	interval := t.Interval
+	if interval <= 0 {
+		interval = defaultCostInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		cost, err := t.Collect(ctx)
+		if err != nil {
+			t.Log.Error(err, "Failed to compute active cost")
+		} else {
+			t.Metrics.SetTotalActiveCost(cost.Total)
+			t.Metrics.SetActiveCostBreakdown(cost.ByProvider, cost.ByGPUType)
+			t.Metrics.SetCapacityTypeCounts(cost.SpotInstances, cost.OnDemandInstances)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection ensures only the elected manager publishes the cost, so replicas do
+// not report it twice
+func (t *CostTracker) NeedLeaderElection() bool {
+	return true
+}
+
+// Collect sums the hourly price of the operator's nodes and counts them by capacity type from
+// their spot label. Nodes without a recorded price, such as those launched before prices were
+// recorded, are left out of the cost but still counted.
+func (t *CostTracker) Collect(ctx context.Context) (ActiveCost, error) {
+	var nodes corev1.NodeList
+	if err := t.List(ctx, &nodes, client.HasLabels{"tgp.io/nodepool"}); err != nil {
+		return ActiveCost{}, fmt.Errorf("failed to list operator nodes: %w", err)
+	}
+
+	cost := ActiveCost{
+		ByProvider: make(map[string]float64),
+		ByGPUType:  make(map[string]float64),
+
+		SpotInstances:     make(map[metrics.InstanceGroup]int),
+		OnDemandInstances: make(map[metrics.InstanceGroup]int),
+	}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		group := metrics.InstanceGroup{Provider: node.Labels[tgpv1.NodeLabelProvider], GPUType: node.Labels[tgpv1.NodeLabelGPUType]}
+		if node.Labels[tgpv1.NodeLabelSpot] == "true" {
+			cost.SpotInstances[group]++
+		} else {
+			cost.OnDemandInstances[group]++
+		}
+
+		price, err := strconv.ParseFloat(node.Annotations[AnnotationHourlyPrice], 64)
+		if err != nil {
+			continue
+		}
+		cost.Total += price
+		cost.ByProvider[node.Labels[tgpv1.NodeLabelProvider]] += price
+		cost.ByGPUType[node.Labels[tgpv1.NodeLabelGPUType]] += price
+	}
+	return cost, nil
+}
+
+// SetupWithManager registers the tracker to run with the manager
+func (t *CostTracker) SetupWithManager(mgr ctrl.Manager) error {
+	return mgr.Add(t)
+}
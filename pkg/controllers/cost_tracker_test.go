@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/metrics"
+)
+
+func TestCostTracker_Collect(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	node := func(name string, labels map[string]string, price string) *corev1.Node {
+		n := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+		if price != "" {
+			n.Annotations = map[string]string{AnnotationHourlyPrice: price}
+		}
+		return n
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		node("h100-a", map[string]string{"tgp.io/nodepool": "train", tgpv1.NodeLabelProvider: "vultr", tgpv1.NodeLabelGPUType: "H100"}, "2.5000"),
+		node("h100-b", map[string]string{"tgp.io/nodepool": "train", tgpv1.NodeLabelProvider: "gcp", tgpv1.NodeLabelGPUType: "H100", tgpv1.NodeLabelSpot: "true"}, "3.0000"),
+		node("a100", map[string]string{"tgp.io/nodepool": "eval", tgpv1.NodeLabelProvider: "vultr", tgpv1.NodeLabelGPUType: "A100"}, "1.5000"),
+		// Launched before prices were recorded
+		node("unpriced", map[string]string{"tgp.io/nodepool": "eval", tgpv1.NodeLabelProvider: "gcp", tgpv1.NodeLabelGPUType: "A100"}, ""),
+		// Not launched by the operator
+		node("control-plane", map[string]string{}, "9.0000"),
+	).Build()
+
+	tracker := &CostTracker{Client: client}
+	cost, err := tracker.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	if cost.Total != 7 {
+		t.Errorf("expected a total of 7, got %v", cost.Total)
+	}
+	if cost.ByProvider["vultr"] != 4 || cost.ByProvider["gcp"] != 3 {
+		t.Errorf("unexpected per-provider cost %v", cost.ByProvider)
+	}
+	if cost.ByGPUType["H100"] != 5.5 || cost.ByGPUType["A100"] != 1.5 {
+		t.Errorf("unexpected per-GPU-type cost %v", cost.ByGPUType)
+	}
+
+	wantSpot := map[metrics.InstanceGroup]int{{Provider: "gcp", GPUType: "H100"}: 1}
+	wantOnDemand := map[metrics.InstanceGroup]int{
+		{Provider: "vultr", GPUType: "H100"}: 1,
+		{Provider: "vultr", GPUType: "A100"}: 1,
+		{Provider: "gcp", GPUType: "A100"}:   1,
+	}
+	if !maps.Equal(cost.SpotInstances, wantSpot) {
+		t.Errorf("SpotInstances = %v, want %v", cost.SpotInstances, wantSpot)
+	}
+	if !maps.Equal(cost.OnDemandInstances, wantOnDemand) {
+		t.Errorf("OnDemandInstances = %v, want %v", cost.OnDemandInstances, wantOnDemand)
+	}
+}
@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// minVCPUPerGPU returns the minimum vCPUs per GPU the class instance requirements ask for, or 0 if unrestricted
+func minVCPUPerGPU(requirements *tgpv1.InstanceRequirements) int {
+	if requirements == nil || requirements.MinVCPUPerGPU == nil {
+		return 0
+	}
+	return int(*requirements.MinVCPUPerGPU)
+}
+
+// filterOffersByCPURatio drops offers with too few vCPUs for their GPUs
+func filterOffersByCPURatio(requirements *tgpv1.InstanceRequirements, offers []providers.GPUOffer) []providers.GPUOffer {
+	minPerGPU := minVCPUPerGPU(requirements)
+	if minPerGPU == 0 {
+		return offers
+	}
+
+	filtered := make([]providers.GPUOffer, 0, len(offers))
+	for _, offer := range offers {
+		if providers.VCPUsPerGPUAllowed(offer, minPerGPU) {
+			filtered = append(filtered, offer)
+		}
+	}
+	return filtered
+}
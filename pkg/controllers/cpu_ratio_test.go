@@ -0,0 +1,26 @@
+package controllers
+
+import (
+	"testing"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+func TestFilterOffersByCPURatio(t *testing.T) {
+	offers := []providers.GPUOffer{
+		{ID: "balanced", VCPUs: 96, GPUCount: 8},
+		{ID: "starved", VCPUs: 8, GPUCount: 8},
+		{ID: "unknown", GPUCount: 1},
+	}
+
+	if got := filterOffersByCPURatio(nil, offers); len(got) != len(offers) {
+		t.Errorf("expected all offers without a minimum, got %d", len(got))
+	}
+
+	minPerGPU := int32(8)
+	got := filterOffersByCPURatio(&tgpv1.InstanceRequirements{MinVCPUPerGPU: &minPerGPU}, offers)
+	if len(got) != 1 || got[0].ID != "balanced" {
+		t.Errorf("filterOffersByCPURatio() = %+v, want only the balanced offer", got)
+	}
+}
@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// minCUDAVersion returns the oldest CUDA version the class instance requirements ask for, or "" if unrestricted
+func minCUDAVersion(requirements *tgpv1.InstanceRequirements) string {
+	if requirements == nil {
+		return ""
+	}
+	return requirements.MinCUDAVersion
+}
+
+// filterOffersByCUDAVersion drops offers whose host driver is too old for the class
+func filterOffersByCUDAVersion(requirements *tgpv1.InstanceRequirements, offers []providers.GPUOffer) []providers.GPUOffer {
+	minVersion := minCUDAVersion(requirements)
+	if minVersion == "" {
+		return offers
+	}
+
+	filtered := make([]providers.GPUOffer, 0, len(offers))
+	for _, offer := range offers {
+		if providers.CUDAVersionAllowed(offer, minVersion) {
+			filtered = append(filtered, offer)
+		}
+	}
+	return filtered
+}
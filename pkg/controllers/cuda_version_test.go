@@ -0,0 +1,25 @@
+package controllers
+
+import (
+	"testing"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+func TestFilterOffersByCUDAVersion(t *testing.T) {
+	offers := []providers.GPUOffer{
+		{ID: "current", CUDAVersion: "12.4"},
+		{ID: "old", CUDAVersion: "11.8"},
+		{ID: "image-driver"},
+	}
+
+	if got := filterOffersByCUDAVersion(nil, offers); len(got) != len(offers) {
+		t.Errorf("expected all offers without a minimum, got %d", len(got))
+	}
+
+	got := filterOffersByCUDAVersion(&tgpv1.InstanceRequirements{MinCUDAVersion: "12.1"}, offers)
+	if len(got) != 2 || got[0].ID != "current" || got[1].ID != "image-driver" {
+		t.Errorf("filterOffersByCUDAVersion() = %+v, want the current and image-driver offers", got)
+	}
+}
@@ -0,0 +1,169 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+const (
+	// AnnotationPendingDataDisks lists, as JSON, the pool's data disks still to be attached to
+	// a node whose provider attaches them once the instance is running rather than at launch
+	AnnotationPendingDataDisks = "tgp.io/pending-data-disks"
+
+	// EventReasonDataDisksAttached is emitted when a node's data disks have been attached after launch
+	EventReasonDataDisksAttached = "DataDisksAttached"
+
+	// EventReasonDataDiskAttachFailed is emitted when a data disk could not be attached after launch
+	EventReasonDataDiskAttachFailed = "DataDiskAttachFailed"
+
+	// dataDiskPollInterval is how often nodes with data disks still to attach are re-checked
+	dataDiskPollInterval = 30 * time.Second
+)
+
+// attachesDataDisksAfterLaunch reports whether the provider attaches data disks with
+// AttachDataDisk once the instance is running instead of in the launch request
+func attachesDataDisksAfterLaunch(providerClient providers.ProviderClient) bool {
+	info := providerClient.GetProviderInfo()
+	return info != nil && info.AttachesDataDisksAfterLaunch
+}
+
+// dataDiskExclusionReason returns why a provider cannot attach the pool's data disks, or an
+// empty string if it can. Providers that cannot attach a disk read-only are excluded up front,
+// since the attach would fail after launch and leave the node cordoned.
+func dataDiskExclusionReason(nodePool *tgpv1.GPUNodePool, info *providers.ProviderInfo) string {
+	if info != nil && info.SupportsReadOnlyDataDisks {
+		return ""
+	}
+	for _, disk := range nodePool.Spec.Template.Spec.DataDisks {
+		if disk.ReadOnly {
+			return fmt.Sprintf("cannot attach data disk %s read-only", disk.VolumeID)
+		}
+	}
+	return ""
+}
+
+// encodeDataDisks encodes the data disks still to attach for the node annotation
+func encodeDataDisks(disks []providers.DataDisk) (string, error) {
+	pending := make([]tgpv1.DataDisk, 0, len(disks))
+	for _, disk := range disks {
+		pending = append(pending, tgpv1.DataDisk{VolumeID: disk.VolumeID, ReadOnly: disk.ReadOnly, DeviceName: disk.DeviceName})
+	}
+	encoded, err := json.Marshal(pending)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode data disks: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// pendingDataDisks returns the data disks still to be attached to the node
+func pendingDataDisks(node *corev1.Node) ([]providers.DataDisk, error) {
+	encoded := node.Annotations[AnnotationPendingDataDisks]
+	if encoded == "" {
+		return nil, nil
+	}
+	var pending []tgpv1.DataDisk
+	if err := json.Unmarshal([]byte(encoded), &pending); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation on node %s: %w", AnnotationPendingDataDisks, node.Name, err)
+	}
+	disks := make([]providers.DataDisk, 0, len(pending))
+	for _, disk := range pending {
+		disks = append(disks, providers.DataDisk{VolumeID: disk.VolumeID, ReadOnly: disk.ReadOnly, DeviceName: disk.DeviceName})
+	}
+	return disks, nil
+}
+
+// reconcileDataDisks attaches the pool's data disks to nodes launched on providers that only
+// attach them once the instance is running. Nodes stay cordoned until their disks are attached.
+// It returns how long until a node with disks still to attach needs to be checked again, or 0.
+func (r *GPUNodePoolReconciler) reconcileDataDisks(ctx context.Context, nodePool *tgpv1.GPUNodePool, nodeClass *tgpv1.GPUNodeClass, log logr.Logger) (time.Duration, error) {
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes, poolNodeSelector(nodePool)); err != nil {
+		return 0, fmt.Errorf("failed to list nodes for pool %s: %w", nodePool.Name, err)
+	}
+
+	var next time.Duration
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if node.DeletionTimestamp != nil || node.Annotations[AnnotationPendingDataDisks] == "" {
+			continue
+		}
+
+		_, providerName := nodeInstance(node)
+		providerClient, err := r.providerClientForClass(ctx, nodeClass, providerName)
+		if err == nil {
+			err = providers.SelectAccount(providerClient, node.Annotations[AnnotationAccount])
+		}
+		if err != nil {
+			log.Error(err, "Failed to create provider client to attach data disks", "node", node.Name, "provider", providerName)
+			next = dataDiskPollInterval
+			continue
+		}
+
+		if err := r.attachPendingDataDisks(ctx, nodePool, node, providerClient, log); err != nil {
+			log.Error(err, "Failed to attach data disks", "node", node.Name)
+		}
+		if node.Annotations[AnnotationPendingDataDisks] != "" {
+			next = dataDiskPollInterval
+		}
+	}
+	return next, nil
+}
+
+// attachPendingDataDisks attaches the node's pending data disks once its instance is running,
+// removing each from the annotation as it is attached so a failed attach is retried alone
+func (r *GPUNodePoolReconciler) attachPendingDataDisks(ctx context.Context, nodePool *tgpv1.GPUNodePool, node *corev1.Node, providerClient providers.ProviderClient, log logr.Logger) error {
+	disks, err := pendingDataDisks(node)
+	if err != nil {
+		return err
+	}
+	instanceID, _ := nodeInstance(node)
+
+	status, err := providerClient.GetInstanceStatus(ctx, instanceID)
+	if err != nil {
+		return fmt.Errorf("failed to get status of instance %s: %w", instanceID, err)
+	}
+	if status.State != providers.InstanceStateRunning {
+		log.V(1).Info("Waiting for instance to run before attaching data disks", "node", node.Name, "state", status.State)
+		return nil
+	}
+
+	attached := 0
+	var attachErr error
+	for _, disk := range disks {
+		if attachErr = providerClient.AttachDataDisk(ctx, instanceID, disk); attachErr != nil {
+			r.recordEvent(nodePool, corev1.EventTypeWarning, EventReasonDataDiskAttachFailed,
+				fmt.Sprintf("Failed to attach data disk %s to node %s: %v", disk.VolumeID, node.Name, attachErr))
+			attachErr = fmt.Errorf("failed to attach data disk %s: %w", disk.VolumeID, attachErr)
+			break
+		}
+		log.Info("Attached data disk", "node", node.Name, "instanceID", instanceID, "volumeID", disk.VolumeID)
+		attached++
+	}
+	if attached == 0 && attachErr != nil {
+		return attachErr
+	}
+
+	if disks = disks[attached:]; len(disks) == 0 {
+		delete(node.Annotations, AnnotationPendingDataDisks)
+		r.recordEvent(nodePool, corev1.EventTypeNormal, EventReasonDataDisksAttached,
+			fmt.Sprintf("Attached data disks to node %s", node.Name))
+	} else {
+		encoded, err := encodeDataDisks(disks)
+		if err != nil {
+			return err
+		}
+		node.Annotations[AnnotationPendingDataDisks] = encoded
+	}
+	if err := r.Update(ctx, node); err != nil {
+		return fmt.Errorf("failed to record attached data disks on node %s: %w", node.Name, err)
+	}
+	return attachErr
+}
@@ -0,0 +1,182 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// diskAttachClient reports a fixed instance state and records attached volumes, failing for
+// volumes listed in failing
+type diskAttachClient struct {
+	providers.ProviderClient
+	state    providers.InstanceState
+	failing  map[string]bool
+	attached []string
+}
+
+func (c *diskAttachClient) GetInstanceStatus(ctx context.Context, instanceID string) (*providers.InstanceStatus, error) {
+	return &providers.InstanceStatus{State: c.state}, nil
+}
+
+func (c *diskAttachClient) AttachDataDisk(ctx context.Context, instanceID string, disk providers.DataDisk) error {
+	if c.failing[disk.VolumeID] {
+		return errors.New("volume is busy")
+	}
+	c.attached = append(c.attached, disk.VolumeID)
+	return nil
+}
+
+func TestAttachPendingDataDisks(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	pending, err := encodeDataDisks([]providers.DataDisk{{VolumeID: "datasets"}, {VolumeID: "checkpoints"}})
+	if err != nil {
+		t.Fatalf("encodeDataDisks() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		state        providers.InstanceState
+		failing      map[string]bool
+		wantAttached []string
+		wantPending  []string
+	}{
+		{
+			name:        "waits for the instance to run",
+			state:       providers.InstanceStatePending,
+			wantPending: []string{"datasets", "checkpoints"},
+		},
+		{
+			name:         "attaches every disk once running",
+			state:        providers.InstanceStateRunning,
+			wantAttached: []string{"datasets", "checkpoints"},
+		},
+		{
+			name:         "keeps the disks left after a failed attach",
+			state:        providers.InstanceStateRunning,
+			failing:      map[string]bool{"checkpoints": true},
+			wantAttached: []string{"datasets"},
+			wantPending:  []string{"checkpoints"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+				Name:        "tgp-pool-abc",
+				Annotations: map[string]string{"tgp.io/instance-id": "ewr/abc", AnnotationPendingDataDisks: pending},
+			}}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node).Build()
+			r := &GPUNodePoolReconciler{Client: fakeClient, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+			providerClient := &diskAttachClient{state: tt.state, failing: tt.failing}
+
+			ctx := context.Background()
+			err := r.attachPendingDataDisks(ctx, &tgpv1.GPUNodePool{}, node, providerClient, logr.Discard())
+			if (err != nil) != (len(tt.failing) > 0) {
+				t.Errorf("attachPendingDataDisks() error = %v", err)
+			}
+			if len(providerClient.attached) != len(tt.wantAttached) {
+				t.Errorf("attached %v, want %v", providerClient.attached, tt.wantAttached)
+			}
+
+			var updated corev1.Node
+			if err := fakeClient.Get(ctx, types.NamespacedName{Name: node.Name}, &updated); err != nil {
+				t.Fatalf("failed to get node: %v", err)
+			}
+			remaining, err := pendingDataDisks(&updated)
+			if err != nil {
+				t.Fatalf("pendingDataDisks() error = %v", err)
+			}
+			if len(remaining) != len(tt.wantPending) {
+				t.Fatalf("pending disks = %v, want %v", remaining, tt.wantPending)
+			}
+			for i, disk := range remaining {
+				if disk.VolumeID != tt.wantPending[i] {
+					t.Errorf("pending disks = %v, want %v", remaining, tt.wantPending)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateKubernetesNodeRecordsPendingDataDisks(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = tgpv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	nodePool := &tgpv1.GPUNodePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "burst", UID: "pool-uid"},
+		Spec: tgpv1.GPUNodePoolSpec{Template: tgpv1.NodePoolTemplate{Spec: tgpv1.NodeSpec{
+			DataDisks: []tgpv1.DataDisk{{VolumeID: "datasets", ReadOnly: true}},
+		}}},
+	}
+
+	for _, deferred := range []bool{false, true} {
+		r := &GPUNodePoolReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), Scheme: scheme}
+		requirement := &GPURequirement{GPUType: "H100", GPUCount: 1, AttachDataDisksAfterLaunch: deferred}
+		instance := &providers.GPUInstance{ID: "abcdef12-3456", CreatedAt: time.Now()}
+
+		ctx := context.Background()
+		if err := r.createKubernetesNode(ctx, nodePool, requirement, instance, nil, &tgpv1.ProviderConfig{Name: "vultr"}, logr.Discard()); err != nil {
+			t.Fatalf("createKubernetesNode() error = %v", err)
+		}
+		var node corev1.Node
+		if err := r.Get(ctx, types.NamespacedName{Name: "tgp-burst-abcdef12"}, &node); err != nil {
+			t.Fatalf("failed to get node: %v", err)
+		}
+		disks, err := pendingDataDisks(&node)
+		if err != nil {
+			t.Fatalf("pendingDataDisks() error = %v", err)
+		}
+		if deferred && (len(disks) != 1 || disks[0].VolumeID != "datasets" || !disks[0].ReadOnly) {
+			t.Errorf("expected the data disk to be pending, got %v", disks)
+		}
+		if !deferred && len(disks) != 0 {
+			t.Errorf("expected no pending data disks when attached at launch, got %v", disks)
+		}
+	}
+}
+
+func TestDataDiskExclusionReason(t *testing.T) {
+	poolWith := func(disks ...tgpv1.DataDisk) *tgpv1.GPUNodePool {
+		nodePool := &tgpv1.GPUNodePool{}
+		nodePool.Spec.Template.Spec.DataDisks = disks
+		return nodePool
+	}
+	readWrite := tgpv1.DataDisk{VolumeID: "scratch"}
+	readOnly := tgpv1.DataDisk{VolumeID: "datasets", ReadOnly: true}
+
+	tests := []struct {
+		name     string
+		nodePool *tgpv1.GPUNodePool
+		info     *providers.ProviderInfo
+		excluded bool
+	}{
+		{"no data disks", poolWith(), &providers.ProviderInfo{}, false},
+		{"read-write disks on any provider", poolWith(readWrite), &providers.ProviderInfo{}, false},
+		{"read-only disk without read-only support", poolWith(readWrite, readOnly), &providers.ProviderInfo{}, true},
+		{"read-only disk without provider info", poolWith(readOnly), nil, true},
+		{"read-only disk with read-only support", poolWith(readOnly), &providers.ProviderInfo{SupportsReadOnlyDataDisks: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if reason := dataDiskExclusionReason(tt.nodePool, tt.info); (reason != "") != tt.excluded {
+				t.Errorf("dataDiskExclusionReason() = %q, want excluded %v", reason, tt.excluded)
+			}
+		})
+	}
+}
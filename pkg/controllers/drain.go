@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// AnnotationDrainStartedAt records when the operator started evicting a node's pods, so
+	// the drain timeout holds across reconciles
+	AnnotationDrainStartedAt = "tgp.io/drain-started-at"
+
+	// drainPollInterval is how often a node whose evicted pods are still terminating is re-checked
+	drainPollInterval = 15 * time.Second
+)
+
+// errDrainPending is returned while evicted pods are still terminating within the drain timeout
+var errDrainPending = errors.New("pods are still terminating")
+
+// isDrainPending reports whether err only means a drain has not finished yet
+func isDrainPending(err error) bool {
+	return errors.Is(err, errDrainPending)
+}
+
+// drainNode evicts the pods on a node through the Eviction API, so PodDisruptionBudgets and
+// termination grace periods are respected. While evicted pods are still terminating it returns
+// errDrainPending; once the drain timeout has passed since the first eviction, the remaining
+// pods are force-deleted. DaemonSet and static pods are left in place.
+func (r *GPUNodePoolReconciler) drainNode(ctx context.Context, node *corev1.Node, drainTimeout time.Duration, log logr.Logger) error {
+	pods, err := r.drainablePods(ctx, node)
+	if err != nil {
+		return err
+	}
+	if len(pods) == 0 {
+		log.Info("No pods to drain from node", "node", node.Name)
+		return nil
+	}
+
+	if drainTimeout <= 0 {
+		return r.forceDeletePods(ctx, node, pods, log)
+	}
+	startedAt, err := r.drainStartedAt(ctx, node)
+	if err != nil {
+		return err
+	}
+	if time.Since(startedAt) >= drainTimeout {
+		return r.forceDeletePods(ctx, node, pods, log)
+	}
+
+	log.Info("Evicting pods from node", "node", node.Name, "podCount", len(pods))
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		eviction := &policyv1.Eviction{ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace}}
+		if err := r.SubResource("eviction").Create(ctx, pod, eviction); err != nil {
+			switch {
+			case apierrors.IsNotFound(err):
+			case apierrors.IsTooManyRequests(err):
+				log.V(1).Info("Eviction blocked by disruption budget", "pod", pod.Name, "namespace", pod.Namespace, "node", node.Name)
+			default:
+				log.Error(err, "Failed to evict pod", "pod", pod.Name, "namespace", pod.Namespace)
+			}
+		}
+	}
+
+	// Pods without a grace period are gone right away
+	remaining, err := r.drainablePods(ctx, node)
+	if err != nil {
+		return err
+	}
+	if len(remaining) > 0 {
+		return fmt.Errorf("%w: %d pods left on node %s", errDrainPending, len(remaining), node.Name)
+	}
+	return nil
+}
+
+// drainablePods returns the pods on the node that a drain removes, leaving out DaemonSet and
+// static pods and pods that have already completed
+func (r *GPUNodePoolReconciler) drainablePods(ctx context.Context, node *corev1.Node) ([]corev1.Pod, error) {
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods); err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	var drainable []corev1.Pod
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName != node.Name || r.isDaemonSetPod(&pod) || r.isStaticPod(&pod) {
+			continue
+		}
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		drainable = append(drainable, pod)
+	}
+	return drainable, nil
+}
+
+// drainStartedAt returns when the node's drain started, recording now on the node if it has
+// not started yet
+func (r *GPUNodePoolReconciler) drainStartedAt(ctx context.Context, node *corev1.Node) (time.Time, error) {
+	if startedAt, err := time.Parse(time.RFC3339, node.Annotations[AnnotationDrainStartedAt]); err == nil {
+		return startedAt, nil
+	}
+
+	now := time.Now()
+	if node.Annotations == nil {
+		node.Annotations = make(map[string]string)
+	}
+	node.Annotations[AnnotationDrainStartedAt] = now.Format(time.RFC3339)
+	if err := r.Update(ctx, node); err != nil {
+		return time.Time{}, fmt.Errorf("failed to record drain start on node %s: %w", node.Name, err)
+	}
+	return now, nil
+}
+
+// forceDeletePods deletes the pods without a grace period
+func (r *GPUNodePoolReconciler) forceDeletePods(ctx context.Context, node *corev1.Node, pods []corev1.Pod, log logr.Logger) error {
+	log.Info("Force-deleting pods from node", "node", node.Name, "podCount", len(pods))
+	for i := range pods {
+		pod := &pods[i]
+		if err := r.Delete(ctx, pod, client.GracePeriodSeconds(0)); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to force-delete pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+func TestDrainNode(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = tgpv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	podOn := func(name, nodeName string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+	daemon := podOn("daemon", "gpu-node")
+	daemon.OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "DaemonSet", Name: "agent", UID: "ds-uid"}}
+	// Its finalizer keeps it terminating after eviction, like a pod with a long grace period
+	slow := podOn("slow", "gpu-node")
+	slow.Finalizers = []string{"example.com/checkpoint"}
+
+	ctx := context.Background()
+	getPod := func(r *GPUNodePoolReconciler, name string) (*corev1.Pod, error) {
+		var pod corev1.Pod
+		err := r.Get(ctx, types.NamespacedName{Namespace: "default", Name: name}, &pod)
+		return &pod, err
+	}
+
+	t.Run("evicts pods and waits for them to terminate", func(t *testing.T) {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "gpu-node"}}
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			node, podOn("web", "gpu-node"), slow.DeepCopy(), daemon.DeepCopy(), podOn("elsewhere", "other-node"),
+		).Build()
+		r := &GPUNodePoolReconciler{Client: client, Scheme: scheme}
+
+		err := r.drainNode(ctx, node, time.Hour, logr.Discard())
+		if !isDrainPending(err) {
+			t.Fatalf("expected the drain to wait for the slow pod, got %v", err)
+		}
+		if node.Annotations[AnnotationDrainStartedAt] == "" {
+			t.Error("expected the drain start to be recorded on the node")
+		}
+		if _, err := getPod(r, "web"); !apierrors.IsNotFound(err) {
+			t.Errorf("expected the web pod to be evicted, got %v", err)
+		}
+		if pod, err := getPod(r, "slow"); err != nil || pod.DeletionTimestamp == nil {
+			t.Errorf("expected the slow pod to be terminating, got %v", err)
+		}
+		for _, name := range []string{"daemon", "elsewhere"} {
+			if _, err := getPod(r, name); err != nil {
+				t.Errorf("expected pod %s to be left in place, got %v", name, err)
+			}
+		}
+	})
+
+	t.Run("force-deletes pods once the drain times out", func(t *testing.T) {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:        "gpu-node",
+			Annotations: map[string]string{AnnotationDrainStartedAt: time.Now().Add(-time.Hour).Format(time.RFC3339)},
+		}}
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node, podOn("web", "gpu-node")).Build()
+		r := &GPUNodePoolReconciler{Client: client, Scheme: scheme}
+
+		if err := r.drainNode(ctx, node, 5*time.Minute, logr.Discard()); err != nil {
+			t.Fatalf("expected the timed-out drain to finish, got %v", err)
+		}
+		if _, err := getPod(r, "web"); !apierrors.IsNotFound(err) {
+			t.Errorf("expected the web pod to be force-deleted, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+const (
+	// EventReasonDryRun is the event recorded for each launch a dry-run pool skipped
+	EventReasonDryRun = "DryRun"
+)
+
+// recordDryRunSelection records the provider and price selected for a pod in the status of a
+// dry-run pool, in place of launching an instance. Capacity priced by the selection keeps its
+// price; otherwise the provider's normalized on-demand price is used when it has one.
+func (r *GPUNodePoolReconciler) recordDryRunSelection(ctx context.Context, nodePool *tgpv1.GPUNodePool, pod *corev1.Pod, providerName string, providerClient providers.ProviderClient, requirement *GPURequirement, now time.Time, log logr.Logger) {
+	price := requirement.HourlyPrice
+	if price <= 0 && !requirement.Spot {
+		pricing, err := providerClient.GetNormalizedPricing(ctx, requirement.GPUType, requirement.Region)
+		if err != nil {
+			log.V(1).Info("Could not price dry-run selection", "provider", providerName, "error", err.Error())
+		} else {
+			price = pricing.PricePerHour
+		}
+	}
+
+	selection := &tgpv1.DryRunSelection{
+		Pod:      pod.Namespace + "/" + pod.Name,
+		Provider: providerName,
+		GPUType:  requirement.GPUType,
+		GPUCount: int32(max(requirement.GPUCount, 1)),
+		Region:   requirement.Region,
+		Spot:     requirement.Spot,
+		Time:     metav1.NewTime(now),
+	}
+	if price > 0 {
+		selection.PricePerHour = strconv.FormatFloat(price, 'f', 4, 64)
+	} else {
+		price = math.Inf(1)
+	}
+	nodePool.Status.DryRunSelection = selection
+
+	capacity := "on-demand"
+	if selection.Spot {
+		capacity = "spot"
+	}
+	message := fmt.Sprintf("Would launch %d %s GPU(s) on %s %s capacity at %s for pod %s",
+		selection.GPUCount, selection.GPUType, providerName, capacity, formatProjectedPrice(price), selection.Pod)
+
+	log.Info("Dry run selected capacity without launching",
+		"pod", selection.Pod,
+		"provider", providerName,
+		"gpuType", selection.GPUType,
+		"spot", selection.Spot,
+		"pricePerHour", selection.PricePerHour)
+	r.updateCondition(nodePool, tgpv1.ConditionTypeDryRun, metav1.ConditionTrue, tgpv1.DryRunReasonProviderSelected, message)
+	r.recordEvent(nodePool, corev1.EventTypeNormal, EventReasonDryRun, message)
+}
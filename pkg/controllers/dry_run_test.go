@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// pricingClient prices every GPU type at price, or fails with err, and must never launch
+type pricingClient struct {
+	providers.ProviderClient
+	price float64
+	err   error
+}
+
+func (c *pricingClient) GetNormalizedPricing(ctx context.Context, gpuType, region string) (*providers.NormalizedPricing, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &providers.NormalizedPricing{PricePerHour: c.price}, nil
+}
+
+func TestRecordDryRunSelection(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "train", Namespace: "ml"}}
+
+	tests := []struct {
+		name        string
+		requirement GPURequirement
+		client      *pricingClient
+		wantPrice   string
+	}{
+		{
+			name:        "selected spot price is kept",
+			requirement: GPURequirement{GPUType: "H100", GPUCount: 8, Spot: true, HourlyPrice: 12.5},
+			client:      &pricingClient{price: 99},
+			wantPrice:   "12.5000",
+		},
+		{
+			name:        "unpriced selection uses normalized pricing",
+			requirement: GPURequirement{GPUType: "A100", Region: "us-east"},
+			client:      &pricingClient{price: 3.2},
+			wantPrice:   "3.2000",
+		},
+		{
+			name:        "pricing failure leaves the price empty",
+			requirement: GPURequirement{GPUType: "A100"},
+			client:      &pricingClient{err: errors.New("pricing unavailable")},
+			wantPrice:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(1)
+			r := &GPUNodePoolReconciler{Recorder: recorder}
+			pool := &tgpv1.GPUNodePool{Spec: tgpv1.GPUNodePoolSpec{DryRun: true}}
+
+			requirement := tt.requirement
+			r.recordDryRunSelection(context.Background(), pool, pod, "vultr", tt.client, &requirement, now, logr.Discard())
+
+			selection := pool.Status.DryRunSelection
+			if selection == nil {
+				t.Fatal("Expected a dry-run selection in the status")
+			}
+			if selection.Pod != "ml/train" || selection.Provider != "vultr" || selection.GPUType != requirement.GPUType {
+				t.Errorf("Unexpected selection: %+v", selection)
+			}
+			if selection.GPUCount != int32(max(requirement.GPUCount, 1)) || selection.Spot != requirement.Spot || !selection.Time.Time.Equal(now) {
+				t.Errorf("Unexpected capacity in selection: %+v", selection)
+			}
+			if selection.PricePerHour != tt.wantPrice {
+				t.Errorf("Expected price %q, got %q", tt.wantPrice, selection.PricePerHour)
+			}
+
+			condition := meta.FindStatusCondition(pool.Status.Conditions, tgpv1.ConditionTypeDryRun)
+			if condition == nil || condition.Status != metav1.ConditionTrue || condition.Reason != tgpv1.DryRunReasonProviderSelected {
+				t.Errorf("Expected DryRun condition, got %+v", condition)
+			}
+			if event := <-recorder.Events; !strings.Contains(event, EventReasonDryRun) || !strings.Contains(event, "ml/train") {
+				t.Errorf("Expected a DryRun event for the pod, got %q", event)
+			}
+		})
+	}
+}
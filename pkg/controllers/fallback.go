@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// AnnotationRequestedGPUType records the GPU type a pod requested when the node was
+// launched with one of the class's fallback GPU types instead
+const AnnotationRequestedGPUType = "tgp.io/requested-gpu-type"
+
+// EventReasonFallbackGPUType is emitted when a node is provisioned with a fallback GPU type
+const EventReasonFallbackGPUType = "FallbackGPUType"
+
+// fallbackGPUTypes returns the class's fallback GPU types to try after the requested one,
+// in order and without repeating the requested type
+func fallbackGPUTypes(nodeClass *tgpv1.GPUNodeClass, requested string) []string {
+	if nodeClass.Spec.InstanceRequirements == nil {
+		return nil
+	}
+
+	seen := map[string]bool{requested: true}
+	var fallbacks []string
+	for _, gpuType := range nodeClass.Spec.InstanceRequirements.FallbackGPUTypes {
+		if gpuType == "" || seen[gpuType] {
+			continue
+		}
+		seen[gpuType] = true
+		fallbacks = append(fallbacks, gpuType)
+	}
+	return fallbacks
+}
+
+// selectProviderWithFallback selects a provider for the requested GPU type and, if no provider
+// has capacity for it, tries the class's fallback GPU types in order. A fallback is only used
+// if the pod would still schedule onto the resulting node. On success the requirement carries
+// the selected GPU type and, for a fallback, the originally requested one.
+func (r *GPUNodePoolReconciler) selectProviderWithFallback(ctx context.Context, nodePool *tgpv1.GPUNodePool, nodeClass *tgpv1.GPUNodeClass, pod *corev1.Pod, requirement *GPURequirement, log logr.Logger) (*tgpv1.ProviderConfig, providers.ProviderClient, error) {
+	selectedProvider, providerClient, err := r.selectBestProvider(ctx, nodePool, nodeClass, requirement, log)
+	if err == nil || !errors.Is(err, errNoSuitableProvider) {
+		return selectedProvider, providerClient, err
+	}
+
+	requested := requirement.GPUType
+	for _, fallback := range fallbackGPUTypes(nodeClass, requested) {
+		requirement.GPUType = fallback
+		provider, client, fallbackErr := r.selectBestProvider(ctx, nodePool, nodeClass, requirement, log)
+		if fallbackErr != nil {
+			log.V(1).Info("Fallback GPU type unavailable", "gpuType", fallback, "reason", fallbackErr.Error())
+			continue
+		}
+		if schedErr := simulatePodScheduling(pod, r.buildPlannedNode(nodePool, requirement, provider.Name)); schedErr != nil {
+			log.V(1).Info("Pod would not schedule onto fallback GPU type", "gpuType", fallback, "reason", schedErr.Error())
+			continue
+		}
+
+		requirement.RequestedGPUType = requested
+		log.Info("Using fallback GPU type", "requestedGPUType", requested, "gpuType", fallback, "provider", provider.Name)
+		r.recordEvent(nodePool, corev1.EventTypeNormal, EventReasonFallbackGPUType,
+			fmt.Sprintf("No capacity for GPU type %s, provisioning fallback %s on %s for pod %s/%s", requested, fallback, provider.Name, pod.Namespace, pod.Name))
+		return provider, client, nil
+	}
+
+	requirement.GPUType = requested
+	return nil, nil, err
+}
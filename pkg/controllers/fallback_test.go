@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+func TestFallbackGPUTypes(t *testing.T) {
+	tests := []struct {
+		name         string
+		requirements *tgpv1.InstanceRequirements
+		requested    string
+		want         []string
+	}{
+		{
+			name:      "no instance requirements",
+			requested: "NVIDIA_A100",
+		},
+		{
+			name:         "fallbacks keep their order",
+			requirements: &tgpv1.InstanceRequirements{FallbackGPUTypes: []string{"NVIDIA_A40", "NVIDIA_L40S"}},
+			requested:    "NVIDIA_A100",
+			want:         []string{"NVIDIA_A40", "NVIDIA_L40S"},
+		},
+		{
+			name:         "requested type and duplicates are skipped",
+			requirements: &tgpv1.InstanceRequirements{FallbackGPUTypes: []string{"NVIDIA_A100", "NVIDIA_A40", "", "NVIDIA_A40"}},
+			requested:    "NVIDIA_A100",
+			want:         []string{"NVIDIA_A40"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeClass := &tgpv1.GPUNodeClass{Spec: tgpv1.GPUNodeClassSpec{InstanceRequirements: tt.requirements}}
+			if got := fallbackGPUTypes(nodeClass, tt.requested); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fallbackGPUTypes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
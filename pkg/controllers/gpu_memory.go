@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// minGPUMemoryGiB returns the minimum memory per GPU the class instance requirements ask for, or 0 if unrestricted
+func minGPUMemoryGiB(requirements *tgpv1.InstanceRequirements) int64 {
+	if requirements == nil || requirements.MinGPUMemoryGiB == nil {
+		return 0
+	}
+	return int64(*requirements.MinGPUMemoryGiB)
+}
+
+// filterOffersByGPUMemory drops offers whose GPUs have too little memory
+func filterOffersByGPUMemory(requirements *tgpv1.InstanceRequirements, offers []providers.GPUOffer) []providers.GPUOffer {
+	minGiB := minGPUMemoryGiB(requirements)
+	if minGiB == 0 {
+		return offers
+	}
+
+	filtered := make([]providers.GPUOffer, 0, len(offers))
+	for _, offer := range offers {
+		if providers.GPUMemoryAllowed(offer, minGiB) {
+			filtered = append(filtered, offer)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,26 @@
+package controllers
+
+import (
+	"testing"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+func TestFilterOffersByGPUMemory(t *testing.T) {
+	offers := []providers.GPUOffer{
+		{ID: "large", GPUMemory: 80, Memory: 64},
+		{ID: "small", GPUMemory: 24, Memory: 512},
+		{ID: "unknown", Memory: 128},
+	}
+
+	if got := filterOffersByGPUMemory(nil, offers); len(got) != len(offers) {
+		t.Errorf("expected all offers without a minimum, got %d", len(got))
+	}
+
+	minGiB := int32(40)
+	got := filterOffersByGPUMemory(&tgpv1.InstanceRequirements{MinGPUMemoryGiB: &minGiB}, offers)
+	if len(got) != 1 || got[0].ID != "large" {
+		t.Errorf("filterOffersByGPUMemory() = %+v, want only the large offer", got)
+	}
+}
@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+const (
+	// AnnotationExpectedGPUCount records how many GPUs the node was launched with
+	AnnotationExpectedGPUCount = "tgp.io/expected-gpu-count"
+
+	// AnnotationGPUValidation records the result of comparing the node's allocatable GPUs
+	// against the expected count, either "passed" or "failed"
+	AnnotationGPUValidation = "tgp.io/gpu-validation"
+
+	// gpuDetectionGracePeriod is how long after a node becomes Ready its GPUs may still be registering
+	gpuDetectionGracePeriod = 5 * time.Minute
+)
+
+// GPU validation results
+const (
+	gpuValidationPassed = "passed"
+	gpuValidationFailed = "failed"
+)
+
+// EventReasonGPUCountMismatch is emitted when a joined node advertises fewer GPUs than it was launched with
+const EventReasonGPUCountMismatch = tgpv1.ReadyReasonGPUCountMismatch
+
+// validateNodeGPUs compares the allocatable GPUs of joined nodes in the pool against the
+// count they were launched with, and returns the names of nodes exposing fewer GPUs
+func (r *GPUNodePoolReconciler) validateNodeGPUs(ctx context.Context, nodePool *tgpv1.GPUNodePool, log logr.Logger) ([]string, error) {
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes, poolNodeSelector(nodePool)); err != nil {
+		return nil, fmt.Errorf("failed to list nodes for pool %s: %w", nodePool.Name, err)
+	}
+
+	var mismatched []string
+	now := time.Now()
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if node.DeletionTimestamp != nil || node.Annotations[AnnotationGPUValidation] == gpuValidationPassed {
+			continue
+		}
+
+		expected, err := strconv.Atoi(node.Annotations[AnnotationExpectedGPUCount])
+		if err != nil || expected <= 0 {
+			continue
+		}
+
+		result, allocatable := checkNodeGPUs(node, expected, now)
+		if result == "" {
+			continue
+		}
+		if result == gpuValidationFailed {
+			mismatched = append(mismatched, node.Name)
+		}
+		if node.Annotations[AnnotationGPUValidation] == result {
+			continue
+		}
+
+		if result == gpuValidationFailed {
+			message := fmt.Sprintf("Node %s advertises %d of %d expected GPUs", node.Name, allocatable, expected)
+			log.Info("Node GPU count below expected", "node", node.Name, "allocatable", allocatable, "expected", expected)
+			r.recordEvent(nodePool, corev1.EventTypeWarning, EventReasonGPUCountMismatch, message)
+		}
+
+		node.Annotations[AnnotationGPUValidation] = result
+		if err := r.Update(ctx, node); err != nil {
+			log.Error(err, "Failed to record GPU validation result", "node", node.Name)
+		}
+	}
+
+	return mismatched, nil
+}
+
+// checkNodeGPUs returns the validation result for a node and its allocatable GPU count.
+// The result is empty while the node has not joined or its GPUs may still be registering.
+func checkNodeGPUs(node *corev1.Node, expected int, now time.Time) (string, int64) {
+	var readySince time.Time
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+			readySince = condition.LastTransitionTime.Time
+		}
+	}
+	if readySince.IsZero() {
+		return "", 0
+	}
+
+	var allocatable int64
+	if quantity, exists := node.Status.Allocatable[corev1.ResourceName("nvidia.com/gpu")]; exists {
+		allocatable = quantity.Value()
+	}
+
+	if allocatable >= int64(expected) {
+		return gpuValidationPassed, allocatable
+	}
+	if now.Sub(readySince) < gpuDetectionGracePeriod {
+		return "", allocatable
+	}
+	return gpuValidationFailed, allocatable
+}
+
+// gpuMismatchMessage describes the nodes that advertise fewer GPUs than expected
+func gpuMismatchMessage(nodes []string) string {
+	return fmt.Sprintf("Nodes advertise fewer GPUs than requested: %s", strings.Join(nodes, ", "))
+}
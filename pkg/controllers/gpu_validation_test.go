@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+func gpuNode(name string, expected string, allocatable int64, readyFor time.Duration) *corev1.Node {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      map[string]string{"tgp.io/nodepool": "gpu-pool", tgpv1.NodeLabelNodePoolNamespace: "default"},
+			Annotations: map[string]string{AnnotationExpectedGPUCount: expected},
+		},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceName("nvidia.com/gpu"): *resource.NewQuantity(allocatable, resource.DecimalSI),
+			},
+		},
+	}
+	if readyFor > 0 {
+		node.Status.Conditions = []corev1.NodeCondition{{
+			Type:               corev1.NodeReady,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(time.Now().Add(-readyFor)),
+		}}
+	}
+	return node
+}
+
+func TestCheckNodeGPUs(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     *corev1.Node
+		expected int
+		result   string
+	}{
+		{
+			name:     "node that has not joined is not checked",
+			node:     gpuNode("pending", "8", 0, 0),
+			expected: 8,
+			result:   "",
+		},
+		{
+			name:     "all expected GPUs passes",
+			node:     gpuNode("full", "8", 8, time.Minute),
+			expected: 8,
+			result:   gpuValidationPassed,
+		},
+		{
+			name:     "missing GPUs within grace period are not flagged yet",
+			node:     gpuNode("registering", "8", 0, time.Minute),
+			expected: 8,
+			result:   "",
+		},
+		{
+			name:     "missing GPUs after grace period fail",
+			node:     gpuNode("partial", "8", 4, 10*time.Minute),
+			expected: 8,
+			result:   gpuValidationFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _ := checkNodeGPUs(tt.node, tt.expected, time.Now())
+			if result != tt.result {
+				t.Errorf("checkNodeGPUs() = %q, want %q", result, tt.result)
+			}
+		})
+	}
+}
+
+func TestValidateNodeGPUs(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = tgpv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	nodePool := &tgpv1.GPUNodePool{ObjectMeta: metav1.ObjectMeta{Name: "gpu-pool", Namespace: "default"}}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			gpuNode("full", "8", 8, time.Hour),
+			gpuNode("partial", "8", 4, time.Hour),
+			gpuNode("legacy", "", 0, time.Hour),
+		).
+		Build()
+
+	reconciler := &GPUNodePoolReconciler{Client: fakeClient, Scheme: scheme}
+
+	mismatched, err := reconciler.validateNodeGPUs(context.Background(), nodePool, logr.Discard())
+	if err != nil {
+		t.Fatalf("validateNodeGPUs() error = %v", err)
+	}
+	if len(mismatched) != 1 || mismatched[0] != "partial" {
+		t.Errorf("validateNodeGPUs() = %v, want [partial]", mismatched)
+	}
+
+	expectResults := map[string]string{
+		"full":    gpuValidationPassed,
+		"partial": gpuValidationFailed,
+		"legacy":  "",
+	}
+	for name, want := range expectResults {
+		var node corev1.Node
+		if err := fakeClient.Get(context.Background(), types.NamespacedName{Name: name}, &node); err != nil {
+			t.Fatalf("failed to get node %s: %v", name, err)
+		}
+		if got := node.Annotations[AnnotationGPUValidation]; got != want {
+			t.Errorf("node %s validation = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -14,12 +14,15 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
 	"github.com/solanyn/tgp-operator/pkg/config"
+	"github.com/solanyn/tgp-operator/pkg/metrics"
+	"github.com/solanyn/tgp-operator/pkg/pricing"
 	"github.com/solanyn/tgp-operator/pkg/providers"
-	"github.com/solanyn/tgp-operator/pkg/providers/gcp"
-	"github.com/solanyn/tgp-operator/pkg/providers/vultr"
 )
 
 const (
@@ -29,9 +32,20 @@ const (
 // GPUNodeClassReconciler reconciles a GPUNodeClass object
 type GPUNodeClassReconciler struct {
 	client.Client
-	Log    logr.Logger
-	Scheme *runtime.Scheme
-	Config *config.OperatorConfig
+	Log          logr.Logger
+	Scheme       *runtime.Scheme
+	Config       config.Source
+	PricingCache *pricing.Cache
+	Inventory    *pricing.InventoryCache
+	Metrics      *metrics.Metrics
+
+	// CircuitBreakers stops calls to provider APIs that keep failing, shared with the node pool controller
+	CircuitBreakers *providers.CircuitBreakers
+
+	// ConfigChanges receives an event whenever the operator configuration is reloaded
+	ConfigChanges <-chan event.GenericEvent
+
+	rateLimiters providerRateLimiters
 }
 
 // +kubebuilder:rbac:groups=tgp.io,resources=gpunodeclasses,verbs=get;list;watch;create;update;patch;delete
@@ -72,15 +86,17 @@ func (r *GPUNodeClassReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	// Validate provider configurations
 	if err := r.validateProviders(ctx, &nodeClass, log); err != nil {
 		log.Error(err, "Provider validation failed")
-		r.updateCondition(&nodeClass, "ProviderValidation", metav1.ConditionFalse, "ValidationFailed", err.Error())
+		r.updateCondition(&nodeClass, tgpv1.ConditionTypeProviderValidation, metav1.ConditionFalse, tgpv1.ProviderValidationReasonFailed, err.Error())
+		nodeClass.Status.LastRequeueReason = RequeueReasonValidationFailed
 		if updateErr := r.Status().Update(ctx, &nodeClass); updateErr != nil {
 			log.Error(updateErr, "Failed to update status")
 		}
-		return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
+		return requeuePeriodically(r.Metrics, controllerNameGPUNodeClass, RequeueReasonValidationFailed, 5*time.Minute), nil
 	}
 
 	// Update ready condition
-	r.updateCondition(&nodeClass, "Ready", metav1.ConditionTrue, "ValidationPassed", "GPUNodeClass is ready")
+	r.updateCondition(&nodeClass, tgpv1.ConditionTypeReady, metav1.ConditionTrue, tgpv1.ReadyReasonValidationPassed, "GPUNodeClass is ready")
+	nodeClass.Status.LastRequeueReason = RequeueReasonPeriodicResync
 	if err := r.Status().Update(ctx, &nodeClass); err != nil {
 		log.Error(err, "Failed to update status")
 		return ctrl.Result{}, err
@@ -93,7 +109,7 @@ func (r *GPUNodeClassReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	}
 
 	log.Info("GPUNodeClass reconciled successfully")
-	return ctrl.Result{RequeueAfter: 10 * time.Minute}, nil
+	return requeuePeriodically(r.Metrics, controllerNameGPUNodeClass, RequeueReasonPeriodicResync, 10*time.Minute), nil
 }
 
 // handleDeletion handles GPUNodeClass deletion
@@ -110,12 +126,13 @@ func (r *GPUNodeClassReconciler) handleDeletion(ctx context.Context, nodeClass *
 	if len(activeNodePools) > 0 {
 		log.Info("Cannot delete GPUNodeClass with active GPUNodePools", "activeCount", len(activeNodePools))
 		// Update status condition to indicate blocking
-		r.updateCondition(nodeClass, "DeletionBlocked", metav1.ConditionTrue, "ActiveNodePools",
+		r.updateCondition(nodeClass, tgpv1.ConditionTypeDeletionBlocked, metav1.ConditionTrue, tgpv1.DeletionBlockedReasonActiveNodePools,
 			fmt.Sprintf("Cannot delete: %d active GPUNodePools still reference this class", len(activeNodePools)))
+		nodeClass.Status.LastRequeueReason = RequeueReasonDeletionBlocked
 		if updateErr := r.Status().Update(ctx, nodeClass); updateErr != nil {
 			log.Error(updateErr, "Failed to update status")
 		}
-		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		return requeueAfter(r.Metrics, controllerNameGPUNodeClass, RequeueReasonDeletionBlocked, 30*time.Second), nil
 	}
 	controllerutil.RemoveFinalizer(nodeClass, GPUNodeClassFinalizerName)
 	if err := r.Update(ctx, nodeClass); err != nil {
@@ -139,13 +156,14 @@ func (r *GPUNodeClassReconciler) validateProviders(ctx context.Context, nodeClas
 		if namespace == "" {
 			namespace = nodeClass.Namespace
 		}
-		credentials, err := r.Config.GetProviderCredentials(ctx, r.Client, providerConfig.Name, namespace)
+		credentials, err := config.Current(r.Config).GetProviderCredentials(ctx, r.Client, providerConfig.Name, namespace)
 		if err != nil {
 			return fmt.Errorf("failed to get credentials for provider %s: %w", providerConfig.Name, err)
 		}
 
-		// Test credentials by creating a client (basic validation)
-		if credentials == "" {
+		// Test credentials by creating a client (basic validation). Workload identity has
+		// no credentials to check here; the client uses the pod's ambient identity.
+		if credentials == "" && config.Current(r.Config).CredentialsSource(providerConfig.Name) != config.CredentialsSourceWorkloadIdentity {
 			return fmt.Errorf("empty credentials for provider %s", providerConfig.Name)
 		}
 
@@ -160,25 +178,17 @@ func (r *GPUNodeClassReconciler) validateProviders(ctx context.Context, nodeClas
 	return nil
 }
 
-// validateProviderClient creates a provider client and tests basic functionality
+// validateProviderClient creates a provider client and checks its credentials with a cheap authenticated call
 func (r *GPUNodeClassReconciler) validateProviderClient(ctx context.Context, providerName, credentials string, log logr.Logger) error {
-	// Create provider client based on provider name
-	var providerClient providers.ProviderClient
-	switch providerName {
-	case "vultr":
-		client, err := vultr.NewClient(credentials)
-		if err != nil {
-			return fmt.Errorf("failed to create Vultr client: %w", err)
-		}
-		providerClient = client
-	case "gcp":
-		client := gcp.NewClient(credentials)
-		if err := client.Initialize(ctx); err != nil {
-			return fmt.Errorf("failed to initialize GCP client: %w", err)
+	providerClient, err := newProviderClient(config.Current(r.Config), providerName, credentials)
+	if err != nil {
+		return err
+	}
+	// Clients that connect lazily, such as GCP's, are connected now to check the credentials
+	if initializer, ok := providerClient.(interface{ Initialize(context.Context) error }); ok {
+		if err := initializer.Initialize(ctx); err != nil {
+			return fmt.Errorf("failed to initialize %s client: %w", providerName, err)
 		}
-		providerClient = client
-	default:
-		return fmt.Errorf("unsupported provider: %s", providerName)
 	}
 
 	// Test basic functionality - get provider info (this is usually lightweight)
@@ -191,9 +201,11 @@ func (r *GPUNodeClassReconciler) validateProviderClient(ctx context.Context, pro
 		"provider", providerName,
 		"providerInfo", providerInfo.Name)
 
-	// TODO: Consider adding a lightweight API call test here
-	// For now, successful client creation and GetProviderInfo is sufficient
-	// Future enhancement could test ListAvailableGPUs with a timeout
+	if checked, err := providers.CheckCredentials(ctx, providerClient); err != nil {
+		return fmt.Errorf("credential check failed: %w", err)
+	} else if !checked {
+		log.V(1).Info("Provider has no credential check, relying on client creation", "provider", providerName)
+	}
 
 	return nil
 }
@@ -262,6 +274,7 @@ func (r *GPUNodeClassReconciler) updateGPUAvailability(ctx context.Context, node
 			CredentialsValid:    false,
 			LastCredentialCheck: &now,
 			InventoryEnabled:    providerConfig.Enabled == nil || *providerConfig.Enabled,
+			DisabledFeatures:    config.Current(r.Config).DisabledFeatures(providerName),
 		}
 
 		// Skip disabled providers
@@ -277,11 +290,11 @@ func (r *GPUNodeClassReconciler) updateGPUAvailability(ctx context.Context, node
 			namespace = nodeClass.Namespace
 		}
 
-		credentials, err := r.Config.GetProviderCredentials(ctx, r.Client, providerConfig.Name, namespace)
+		credentials, err := config.Current(r.Config).GetProviderCredentials(ctx, r.Client, providerConfig.Name, namespace)
 		if err != nil {
 			providerStatus.Error = fmt.Sprintf("Failed to get credentials: %v", err)
 			providerStatuses[providerName] = providerStatus
-			r.updateProviderCondition(nodeClass, providerName, metav1.ConditionFalse, "CredentialError", providerStatus.Error)
+			r.updateProviderCondition(nodeClass, providerName, metav1.ConditionFalse, tgpv1.ProviderReasonCredentialError, providerStatus.Error)
 			log.Error(err, "Failed to get credentials for provider", "provider", providerName)
 			continue
 		}
@@ -291,43 +304,148 @@ func (r *GPUNodeClassReconciler) updateGPUAvailability(ctx context.Context, node
 		if err != nil {
 			providerStatus.Error = fmt.Sprintf("Failed to create client: %v", err)
 			providerStatuses[providerName] = providerStatus
-			r.updateProviderCondition(nodeClass, providerName, metav1.ConditionFalse, "ClientError", providerStatus.Error)
+			r.updateProviderCondition(nodeClass, providerName, metav1.ConditionFalse, tgpv1.ProviderReasonClientError, providerStatus.Error)
 			log.Error(err, "Failed to create provider client", "provider", providerName)
 			continue
 		}
 
 		log.V(1).Info("Provider client created successfully", "provider", providerName)
+
+		// Check the credentials against the provider API
+		if _, err := providers.CheckCredentials(ctx, providerClient); err != nil {
+			providerStatus.Error = fmt.Sprintf("Credential check failed: %v", err)
+			providerStatuses[providerName] = providerStatus
+			r.updateProviderCondition(nodeClass, providerName, metav1.ConditionFalse, tgpv1.ProviderReasonCredentialError, providerStatus.Error)
+			log.Error(err, "Provider credential check failed", "provider", providerName)
+			continue
+		}
 		log.Info("Provider credentials validated", "provider", providerName)
 
 		// Credentials are valid
 		providerStatus.CredentialsValid = true
-		r.updateProviderCondition(nodeClass, providerName, metav1.ConditionTrue, "Ready", "Provider credentials validated and client ready")
+		r.updateProviderCondition(nodeClass, providerName, metav1.ConditionTrue, tgpv1.ProviderReasonReady, "Provider credentials validated and client ready")
+
+		// Exclude providers that do not meet the class quality policy
+		if reason := qualityExclusionReason(nodeClass.Spec.QualityPolicy, providerClient.GetProviderInfo()); reason != "" {
+			providerStatus.Excluded = true
+			providerStatus.ExclusionReason = reason
+			providerStatuses[providerName] = providerStatus
+			r.updateProviderCondition(nodeClass, providerName, metav1.ConditionFalse, tgpv1.ProviderReasonQualityPolicyNotMet, reason)
+			log.Info("Provider excluded by quality policy", "provider", providerName, "reason", reason)
+			continue
+		}
 
-		// Apply rate limiting to avoid hitting API limits
-		if rateLimitErr := r.rateLimitProvider(providerName); rateLimitErr != nil {
-			providerStatus.Error = fmt.Sprintf("Rate limited: %v", rateLimitErr)
+		// Skip the inventory query when it is disabled for this provider
+		if !config.Current(r.Config).FeatureEnabled(providerName, config.FeatureInventory) {
+			providerStatus.InventoryEnabled = false
 			providerStatuses[providerName] = providerStatus
-			log.V(1).Info("Provider rate limited, skipping this cycle", "provider", providerName)
+			log.V(1).Info("Inventory disabled for provider, skipping GPU availability query", "provider", providerName)
 			continue
 		}
 
 		// Query available GPUs with error handling
-		offers, err := providerClient.ListAvailableGPUs(ctx, &providers.GPUFilters{})
+		offers, fetchedAt, err := r.listOffers(ctx, nodeClass, providerName, namespace, providerClient)
+		if isInventoryRateLimited(err) {
+			// Keep the last inventory until the provider's rate limit allows another query
+			if previous, exists := nodeClass.Status.Providers[providerName]; exists {
+				providerStatus.LastPricingUpdate = previous.LastPricingUpdate
+				providerStatus.Excluded = previous.Excluded
+				providerStatus.ExclusionReason = previous.ExclusionReason
+			}
+			if previous, exists := nodeClass.Status.AvailableGPUs[providerName]; exists {
+				availableGPUs[providerName] = previous
+			}
+			providerStatuses[providerName] = providerStatus
+			log.V(1).Info("Provider rate limit reached, skipping inventory refresh", "provider", providerName)
+			continue
+		}
 		if err != nil {
 			// Handle specific API errors gracefully
 			errorMsg := r.handleProviderAPIError(providerName, err)
+			reason := tgpv1.ProviderReasonAPIError
+			if circuit := r.CircuitBreakers.Status(providerName); circuit.State == providers.CircuitOpen {
+				reason = tgpv1.ProviderReasonCircuitOpen
+				errorMsg = fmt.Sprintf("API calls paused until %s after %d consecutive failures: %v",
+					circuit.OpenUntil.Format(time.RFC3339), circuit.ConsecutiveFailures, circuit.LastError)
+			}
 			providerStatus.Error = errorMsg
 			providerStatuses[providerName] = providerStatus
-			r.updateProviderCondition(nodeClass, providerName, metav1.ConditionFalse, "APIError", errorMsg)
+			r.updateProviderCondition(nodeClass, providerName, metav1.ConditionFalse, reason, errorMsg)
 			log.Error(err, "Failed to query GPU availability", "provider", providerName)
 			continue
 		}
 
 		// Successfully fetched pricing data
-		providerStatus.LastPricingUpdate = &now
+		lastPricingUpdate := metav1.NewTime(fetchedAt)
+		providerStatus.LastPricingUpdate = &lastPricingUpdate
+
+		// Drop offers that do not meet the class quality policy
+		compliantOffers := filterOffersByQuality(nodeClass.Spec.QualityPolicy, offers)
+		if len(offers) > 0 && len(compliantOffers) == 0 {
+			providerStatus.Excluded = true
+			providerStatus.ExclusionReason = "no offers are on verified hosts"
+			r.updateProviderCondition(nodeClass, providerName, metav1.ConditionFalse, tgpv1.ProviderReasonQualityPolicyNotMet, providerStatus.ExclusionReason)
+		}
+		offers = compliantOffers
+
+		// Drop offers for GPU types and regions the class does not allow
+		allowedOffers := filterOffersByGPUTypeAndRegion(nodeClass.Spec.InstanceRequirements, offers)
+		if len(offers) > 0 && len(allowedOffers) == 0 {
+			providerStatus.Excluded = true
+			providerStatus.ExclusionReason = "no offers are for the allowed GPU types and regions"
+			r.updateProviderCondition(nodeClass, providerName, metav1.ConditionFalse, tgpv1.ProviderReasonNoAllowedOffers, providerStatus.ExclusionReason)
+		}
+		offers = allowedOffers
+
+		// Drop offers outside the countries allowed by the class
+		residentOffers := filterOffersByResidency(nodeClass.Spec.InstanceRequirements, offers)
+		if len(offers) > 0 && len(residentOffers) == 0 {
+			providerStatus.Excluded = true
+			providerStatus.ExclusionReason = "no offers are in the allowed countries"
+			r.updateProviderCondition(nodeClass, providerName, metav1.ConditionFalse, tgpv1.ProviderReasonDataResidencyNotMet, providerStatus.ExclusionReason)
+		}
+		offers = residentOffers
+
+		// Drop offers without enough vCPUs to feed their GPUs
+		balancedOffers := filterOffersByCPURatio(nodeClass.Spec.InstanceRequirements, offers)
+		if len(offers) > 0 && len(balancedOffers) == 0 {
+			providerStatus.Excluded = true
+			providerStatus.ExclusionReason = fmt.Sprintf("no offers have at least %d vCPUs per GPU", minVCPUPerGPU(nodeClass.Spec.InstanceRequirements))
+			r.updateProviderCondition(nodeClass, providerName, metav1.ConditionFalse, tgpv1.ProviderReasonCPUPerGPUNotMet, providerStatus.ExclusionReason)
+		}
+		offers = balancedOffers
+
+		// Drop offers with fewer vCPUs or less memory than the class asks for
+		sizedOffers := filterOffersByInstanceSize(nodeClass.Spec.InstanceRequirements, offers)
+		if len(offers) > 0 && len(sizedOffers) == 0 {
+			minVCPUs, minMemoryGiB := minInstanceSize(nodeClass.Spec.InstanceRequirements)
+			providerStatus.Excluded = true
+			providerStatus.ExclusionReason = fmt.Sprintf("no offers have at least %d vCPUs and %dGiB of memory", minVCPUs, minMemoryGiB)
+			r.updateProviderCondition(nodeClass, providerName, metav1.ConditionFalse, tgpv1.ProviderReasonInstanceSizeNotMet, providerStatus.ExclusionReason)
+		}
+		offers = sizedOffers
+
+		// Drop offers whose GPUs have too little memory
+		memoryOffers := filterOffersByGPUMemory(nodeClass.Spec.InstanceRequirements, offers)
+		if len(offers) > 0 && len(memoryOffers) == 0 {
+			providerStatus.Excluded = true
+			providerStatus.ExclusionReason = fmt.Sprintf("no offers have at least %dGiB of memory per GPU", minGPUMemoryGiB(nodeClass.Spec.InstanceRequirements))
+			r.updateProviderCondition(nodeClass, providerName, metav1.ConditionFalse, tgpv1.ProviderReasonGPUMemoryNotMet, providerStatus.ExclusionReason)
+		}
+		offers = memoryOffers
+
+		// Drop offers whose host driver is too old for the required CUDA version
+		cudaOffers := filterOffersByCUDAVersion(nodeClass.Spec.InstanceRequirements, offers)
+		if len(offers) > 0 && len(cudaOffers) == 0 {
+			providerStatus.Excluded = true
+			providerStatus.ExclusionReason = fmt.Sprintf("no offers support CUDA %s", minCUDAVersion(nodeClass.Spec.InstanceRequirements))
+			r.updateProviderCondition(nodeClass, providerName, metav1.ConditionFalse, tgpv1.ProviderReasonCUDAVersionNotMet, providerStatus.ExclusionReason)
+		}
+		offers = cudaOffers
 
 		// Convert offers to GPU availability format
-		gpuAvailability := r.convertOffersToGPUAvailability(offers, now)
+		r.recordObservedPrices(providerName, offers, fetchedAt)
+		gpuAvailability := r.convertOffersToGPUAvailability(providerName, offers, now)
 
 		if len(gpuAvailability) > 0 {
 			availableGPUs[providerName] = gpuAvailability
@@ -342,6 +460,9 @@ func (r *GPUNodeClassReconciler) updateGPUAvailability(ctx context.Context, node
 	nodeClass.Status.Providers = providerStatuses
 	nodeClass.Status.LastInventoryUpdate = &now
 
+	healthStatus, healthReason, healthMessage := providersHealthyCondition(nodeClass, providerStatuses, now.Time)
+	r.updateCondition(nodeClass, tgpv1.ConditionTypeProvidersHealthy, healthStatus, healthReason, healthMessage)
+
 	// Schedule next inventory update (5 minutes from now)
 	nextUpdate := metav1.NewTime(now.Add(5 * time.Minute))
 	nodeClass.Status.NextInventoryUpdate = &nextUpdate
@@ -353,45 +474,53 @@ func (r *GPUNodeClassReconciler) updateGPUAvailability(ctx context.Context, node
 	return nil
 }
 
+// listOffers returns the provider's GPU offers and when they were fetched, reading from the
+// shared inventory cache when one is configured
+func (r *GPUNodeClassReconciler) listOffers(ctx context.Context, nodeClass *tgpv1.GPUNodeClass, providerName, credentialsNamespace string, providerClient providers.ProviderClient) ([]providers.GPUOffer, time.Time, error) {
+	countries := allowedCountries(nodeClass.Spec.InstanceRequirements)
+	if r.Inventory != nil {
+		// Classes with the same data residency share the provider's offers
+		key := providerName + "/" + credentialsNamespace + "/" + strings.Join(countries, ",")
+		if r.Inventory.NeedsRefresh(key) && !r.rateLimiters.allow(providerClient) {
+			return nil, time.Time{}, errInventoryRateLimited
+		}
+		return r.Inventory.ListOffers(ctx, key, providerClient, &providers.GPUFilters{Countries: countries})
+	}
+
+	if !r.rateLimiters.allow(providerClient) {
+		return nil, time.Time{}, errInventoryRateLimited
+	}
+	offers, err := providerClient.ListAvailableGPUs(ctx, &providers.GPUFilters{
+		VerifiedOnly: verifiedOnly(nodeClass.Spec.QualityPolicy),
+		Countries:    countries,
+	})
+	return offers, time.Now(), err
+}
+
 // updateProviderCondition updates the condition for a specific provider
 func (r *GPUNodeClassReconciler) updateProviderCondition(nodeClass *tgpv1.GPUNodeClass, providerName string, status metav1.ConditionStatus, reason, message string) {
-	conditionType := fmt.Sprintf("%sReady", providerName)
+	conditionType := tgpv1.ProviderConditionType(providerName)
 	r.updateCondition(nodeClass, conditionType, status, reason, message)
 }
 
-// rateLimitProvider implements rate limiting for provider API calls
-func (r *GPUNodeClassReconciler) rateLimitProvider(providerName string) error {
-	// TODO: Implement actual rate limiting with token bucket or similar
-	// For now, just add a small delay to avoid overwhelming APIs
-	time.Sleep(100 * time.Millisecond)
-	return nil
-}
-
 // handleProviderAPIError handles specific provider API errors and returns user-friendly messages
 func (r *GPUNodeClassReconciler) handleProviderAPIError(providerName string, err error) string {
-	errStr := err.Error()
-
-	switch providerName {
-	default:
-		// No provider-specific error handling
-	}
-
-	// Generic error handling
-	if contains(errStr, "429") || contains(errStr, "rate limit") {
+	switch classifyProviderAPIError(err) {
+	case providerErrorRateLimit:
 		return fmt.Sprintf("API rate limit exceeded: %v", err)
-	}
-	if contains(errStr, "401") || contains(errStr, "403") || contains(errStr, "Unauthorized") {
+	case providerErrorAuth:
 		return fmt.Sprintf("Authentication failed: %v", err)
-	}
-	if contains(errStr, "network") || contains(errStr, "connection") {
+	case providerErrorBilling:
+		return fmt.Sprintf("Billing error: %v", err)
+	case providerErrorNetwork:
 		return fmt.Sprintf("Network error: %v", err)
+	default:
+		return fmt.Sprintf("API error: %v", err)
 	}
-
-	return fmt.Sprintf("API error: %v", err)
 }
 
 // convertOffersToGPUAvailability converts provider offers to GPUAvailability format
-func (r *GPUNodeClassReconciler) convertOffersToGPUAvailability(offers []providers.GPUOffer, timestamp metav1.Time) []tgpv1.GPUAvailability {
+func (r *GPUNodeClassReconciler) convertOffersToGPUAvailability(providerName string, offers []providers.GPUOffer, timestamp metav1.Time) []tgpv1.GPUAvailability {
 	var gpuAvailability []tgpv1.GPUAvailability
 	gpuTypeMap := make(map[string]*tgpv1.GPUAvailability)
 
@@ -410,9 +539,10 @@ func (r *GPUNodeClassReconciler) convertOffersToGPUAvailability(offers []provide
 				GPUType:      offer.GPUType,
 				Regions:      []string{offer.Region},
 				PricePerHour: fmt.Sprintf("%.2f", offer.HourlyPrice),
-				Memory:       offer.Memory,
+				Memory:       offer.GPUMemory,
 				Available:    offer.Available,
 				SpotPrice:    &spotPrice,
+				PriceTrend:   r.priceTrend(providerName, offer.GPUType, offer.Region),
 				LastUpdated:  timestamp,
 			}
 			gpuTypeMap[key] = gpu
@@ -423,27 +553,52 @@ func (r *GPUNodeClassReconciler) convertOffersToGPUAvailability(offers []provide
 	return gpuAvailability
 }
 
+// recordObservedPrices adds the offer prices to the pricing cache history and updates the price window metric
+func (r *GPUNodeClassReconciler) recordObservedPrices(providerName string, offers []providers.GPUOffer, observedAt time.Time) {
+	if r.PricingCache == nil {
+		return
+	}
+
+	for _, offer := range offers {
+		r.PricingCache.RecordPrice(providerName, offer.GPUType, offer.Region, offer.HourlyPrice, observedAt)
+		if summary, ok := r.PricingCache.PriceSummary(providerName, offer.GPUType, offer.Region); ok {
+			r.Metrics.SetPriceWindow(providerName, offer.GPUType, offer.Region, summary.Min, summary.Max, summary.Average)
+		}
+	}
+}
+
+// priceTrend returns the observed price trend for a GPU type in a region, if any prices were recorded
+func (r *GPUNodeClassReconciler) priceTrend(providerName, gpuType, region string) *tgpv1.PriceTrend {
+	if r.PricingCache == nil {
+		return nil
+	}
+
+	summary, ok := r.PricingCache.PriceSummary(providerName, gpuType, region)
+	if !ok {
+		return nil
+	}
+
+	return &tgpv1.PriceTrend{
+		Min:     fmt.Sprintf("%.2f", summary.Min),
+		Max:     fmt.Sprintf("%.2f", summary.Max),
+		Average: fmt.Sprintf("%.2f", summary.Average),
+		Samples: int32(summary.Samples),
+		Window:  metav1.Duration{Duration: summary.Window},
+	}
+}
+
 // contains is a helper function to check if a string contains a substring
 func contains(s, substr string) bool {
 	return strings.Contains(s, substr)
 }
 
-// createProviderClient creates a provider client based on provider name (duplicate of gpunodepool_controller)
+// createProviderClient creates a provider client based on provider name
 func (r *GPUNodeClassReconciler) createProviderClient(providerName, credentials string) (providers.ProviderClient, error) {
-	switch providerName {
-	case "vultr":
-		client, err := vultr.NewClient(credentials)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create Vultr client: %w", err)
-		}
-		return client, nil
-	case "gcp":
-		client := gcp.NewClient(credentials)
-		// Initialize will be called when needed
-		return client, nil
-	default:
-		return nil, fmt.Errorf("unsupported provider: %s", providerName)
+	client, err := newProviderClient(config.Current(r.Config), providerName, credentials)
+	if err != nil {
+		return nil, err
 	}
+	return r.CircuitBreakers.Wrap(instrumentProviderClient(r.Metrics, client)), nil
 }
 
 // mergeRegions combines two region slices, removing duplicates
@@ -465,7 +620,12 @@ func mergeRegions(existing, new []string) []string {
 
 // SetupWithManager sets up the controller with the Manager
 func (r *GPUNodeClassReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&tgpv1.GPUNodeClass{}).
-		Complete(r)
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&tgpv1.GPUNodeClass{})
+	if r.ConfigChanges != nil {
+		// Reconcile everything against a reloaded configuration, e.g. a newly enabled provider
+		builder = builder.WatchesRawSource(source.Channel(r.ConfigChanges,
+			handler.EnqueueRequestsFromMapFunc(r.nodeClassesForConfigChange)))
+	}
+	return builder.Complete(r)
 }
@@ -16,21 +16,38 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
 	"github.com/solanyn/tgp-operator/pkg/config"
 	"github.com/solanyn/tgp-operator/pkg/imagefactory"
+	"github.com/solanyn/tgp-operator/pkg/metrics"
 	"github.com/solanyn/tgp-operator/pkg/pricing"
 	"github.com/solanyn/tgp-operator/pkg/providers"
-	"github.com/solanyn/tgp-operator/pkg/providers/gcp"
-	"github.com/solanyn/tgp-operator/pkg/providers/vultr"
+	"github.com/solanyn/tgp-operator/pkg/providers/factory"
 )
 
 const (
 	GPUNodePoolFinalizerName = "tgp.io/gpunodepool-finalizer"
+
+	// AnnotationSpotHourlySavings records the estimated hourly saving of a spot node versus on-demand
+	AnnotationSpotHourlySavings = "tgp.io/spot-hourly-savings"
+
+	// AnnotationOperatorVersion records the operator version that provisioned a node
+	AnnotationOperatorVersion = "tgp.io/operator-version"
+
+	// TagOperatorVersion is the cloud tag recording the operator version that launched an instance
+	TagOperatorVersion = "tgp-operator-version"
+
+	// AnnotationAccount records the provider account or project a node was launched into
+	AnnotationAccount = "tgp.io/account"
 )
 
 // GPUNodePoolReconciler reconciles a GPUNodePool object
@@ -38,17 +55,46 @@ type GPUNodePoolReconciler struct {
 	client.Client
 	Log          logr.Logger
 	Scheme       *runtime.Scheme
-	Config       *config.OperatorConfig
+	Config       config.Source
 	PricingCache *pricing.Cache
+	Inventory    *pricing.InventoryCache
 	ImageFactory *imagefactory.Client
+	Metrics      *metrics.Metrics
+	Recorder     record.EventRecorder
+
+	// CircuitBreakers stops calls to provider APIs that keep failing, shared with the node class controller
+	CircuitBreakers *providers.CircuitBreakers
+
+	// LaunchLimiters bounds the concurrent launches to each provider
+	LaunchLimiters *providers.LaunchLimiters
+
+	// ConfigChanges receives an event whenever the operator configuration is reloaded
+	ConfigChanges <-chan event.GenericEvent
+
+	// OperatorVersion is recorded on the nodes and instances this reconciler provisions
+	OperatorVersion string
+
+	// TailscaleAPIURL overrides the Tailscale API used to generate node auth keys
+	TailscaleAPIURL string
+
+	translations translationCache
+	quota        quotaTracker
+	launches     launchRegistry
+	tailscale    tailscaleClients
+
+	// newClient replaces the provider factory when set, so tests can supply fake clients
+	newClient func(providerName, credentials string) (providers.ProviderClient, error)
 }
 
 // +kubebuilder:rbac:groups=tgp.io,resources=gpunodepools,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=tgp.io,resources=gpunodepools/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=tgp.io,resources=gpunodepools/finalizers,verbs=update
 // +kubebuilder:rbac:groups=tgp.io,resources=gpunodeclasses,verbs=get;list;watch
-// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups="",resources=pods/eviction,verbs=create
 // +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=tailscale.com,resources=connectors,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile handles GPUNodePool reconciliation
 func (r *GPUNodePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -83,41 +129,194 @@ func (r *GPUNodePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	nodeClass, err := r.getNodeClass(ctx, &nodePool)
 	if err != nil {
 		log.Error(err, "Failed to get referenced GPUNodeClass")
-		r.updateCondition(&nodePool, "NodeClassReady", metav1.ConditionFalse, "NodeClassNotFound", err.Error())
+		r.updateCondition(&nodePool, tgpv1.ConditionTypeNodeClassReady, metav1.ConditionFalse, tgpv1.NodeClassReadyReasonNotFound, err.Error())
+		nodePool.Status.LastRequeueReason = RequeueReasonNodeClassNotFound
 		if updateErr := r.Status().Update(ctx, &nodePool); updateErr != nil {
 			log.Error(updateErr, "Failed to update status")
 		}
-		return ctrl.Result{RequeueAfter: 1 * time.Minute}, nil
+		return requeuePeriodically(r.Metrics, controllerNameGPUNodePool, RequeueReasonNodeClassNotFound, 1*time.Minute), nil
 	}
 
 	// Update NodeClass ready condition
-	r.updateCondition(&nodePool, "NodeClassReady", metav1.ConditionTrue, "NodeClassFound", "Referenced GPUNodeClass is available")
+	r.updateCondition(&nodePool, tgpv1.ConditionTypeNodeClassReady, metav1.ConditionTrue, tgpv1.NodeClassReadyReasonFound, "Referenced GPUNodeClass is available")
 
-	// Check for unschedulable pods that need GPU nodes
-	if err := r.handlePodDrivenProvisioning(ctx, &nodePool, nodeClass, log); err != nil {
-		log.Error(err, "Failed to handle pod-driven provisioning")
-		r.updateCondition(&nodePool, "Ready", metav1.ConditionFalse, "ProvisioningFailed", err.Error())
-		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	// Reject templates whose taints could not be applied to the created nodes
+	if err := validateTemplateTaints(&nodePool); err != nil {
+		log.Error(err, "Node template validation failed")
+		r.updateCondition(&nodePool, tgpv1.ConditionTypeReady, metav1.ConditionFalse, tgpv1.ReadyReasonInvalidTemplate, err.Error())
+		nodePool.Status.LastRequeueReason = RequeueReasonValidationFailed
+		if updateErr := r.Status().Update(ctx, &nodePool); updateErr != nil {
+			log.Error(updateErr, "Failed to update status")
+		}
+		return requeuePeriodically(r.Metrics, controllerNameGPUNodePool, RequeueReasonValidationFailed, 5*time.Minute), nil
+	}
+
+	// Provision nodes for unschedulable pods, unless waiting out the backoff after a failed attempt
+	now := time.Now()
+	var provisionErr error
+	var realized []spotSavings
+	backoff := provisioningBackoffRemaining(&nodePool, now)
+	if backoff > 0 {
+		log.V(1).Info("Provisioning is backing off after a failed attempt",
+			"attempts", nodePool.Status.ProvisioningAttempts, "retryIn", backoff)
+	} else {
+		// Resume launches interrupted by a restart before provisioning anything new
+		var err error
+		if realized, err = r.reconcileInstances(ctx, &nodePool, nodeClass, log); err != nil {
+			log.Error(err, "Failed to reconcile tracked instances")
+		}
+		provisionErr = r.handlePodDrivenProvisioning(ctx, &nodePool, nodeClass, log)
+	}
+	pendingTimedOut := backoff == 0 && trackPendingProvisioning(&nodePool, provisionErr, now)
+
+	// Maintain the nodes the pool already has whatever the outcome of provisioning, so a pod
+	// that cannot be placed does not hold up readiness, expiry or replacement of running nodes
+	maintenance := r.maintainNodes(ctx, &nodePool, nodeClass, log)
+
+	requeueReason, requeueDelay := RequeueReasonPeriodicResync, 10*time.Minute
+	switch {
+	case backoff > 0:
+		requeueReason, requeueDelay = RequeueReasonProvisioningFailed, backoff
+	case pendingTimedOut:
+		r.markPendingTimeout(&nodePool, provisionErr, log)
+		requeueReason, requeueDelay = RequeueReasonPendingTimeout, pendingTimeoutRetryInterval
+	case provisionErr != nil:
+		log.Error(provisionErr, "Failed to handle pod-driven provisioning")
+		r.updateCondition(&nodePool, tgpv1.ConditionTypeReady, metav1.ConditionFalse, provisioningReadyReason(provisionErr), provisionErr.Error())
+		requeueReason, requeueDelay = provisioningRequeueReason(provisionErr), recordProvisioningFailure(&nodePool, provisionErr, now)
+	default:
+		resetProvisioningAttempts(&nodePool)
+		if len(maintenance.mismatchedGPUs) > 0 {
+			r.updateCondition(&nodePool, tgpv1.ConditionTypeReady, metav1.ConditionFalse, tgpv1.ReadyReasonGPUCountMismatch, gpuMismatchMessage(maintenance.mismatchedGPUs))
+		} else {
+			r.updateCondition(&nodePool, tgpv1.ConditionTypeReady, metav1.ConditionTrue, tgpv1.ReadyReasonInitialized, "GPUNodePool is ready for provisioning")
+		}
+	}
+	if maintenance.requeueDelay > 0 && maintenance.requeueDelay < requeueDelay {
+		requeueReason, requeueDelay = maintenance.requeueReason, maintenance.requeueDelay
 	}
-	r.updateCondition(&nodePool, "Ready", metav1.ConditionTrue, "Initialized", "GPUNodePool is ready for provisioning")
+
+	nodePool.Status.LastRequeueReason = requeueReason
 	if err := r.Status().Update(ctx, &nodePool); err != nil {
 		log.Error(err, "Failed to update status")
 		return ctrl.Result{}, err
 	}
+	r.recordSpotSavings(realized)
+
+	if provisionErr == nil && backoff == 0 {
+		log.Info("GPUNodePool reconciled successfully", "nodeClass", nodeClass.Name)
+	}
+	if requeueReason == RequeueReasonPeriodicResync {
+		return requeuePeriodically(r.Metrics, controllerNameGPUNodePool, requeueReason, requeueDelay), nil
+	}
+	return requeueAfter(r.Metrics, controllerNameGPUNodePool, requeueReason, requeueDelay), nil
+}
+
+// nodeMaintenance is the outcome of maintaining a pool's existing nodes
+type nodeMaintenance struct {
+	// requeueReason and requeueDelay give the soonest a node needs attention again, a delay of 0 if none does
+	requeueReason string
+	requeueDelay  time.Duration
+
+	// mismatchedGPUs are joined nodes exposing fewer GPUs than they were launched with
+	mismatchedGPUs []string
+}
+
+// next records that a node needs attention again after delay, keeping the soonest
+func (m *nodeMaintenance) next(reason string, delay time.Duration) {
+	if delay > 0 && (m.requeueDelay == 0 || delay < m.requeueDelay) {
+		m.requeueReason, m.requeueDelay = reason, delay
+	}
+}
+
+// maintainNodes enforces lifecycle policies on the pool's existing nodes, makes joined nodes
+// schedulable, replaces interrupted ones, and keeps their labels, tags and connectors current.
+// Failures are logged so one failing step does not stop the others.
+func (r *GPUNodePoolReconciler) maintainNodes(ctx context.Context, nodePool *tgpv1.GPUNodePool, nodeClass *tgpv1.GPUNodeClass, log logr.Logger) nodeMaintenance {
+	var maintenance nodeMaintenance
+
+	// Enforce lifecycle policies such as node expiry and the template's MaxLifetime
+	nextTransition, err := r.reconcileNodeLifecycle(ctx, nodePool, log)
+	if err != nil {
+		log.Error(err, "Failed to reconcile node lifecycle")
+	}
+	maintenance.next(RequeueReasonNodeExpiring, nextTransition)
+
+	// Attach data disks to launched instances whose provider cannot attach them at launch
+	nextDataDiskCheck, err := r.reconcileDataDisks(ctx, nodePool, nodeClass, log)
+	if err != nil {
+		log.Error(err, "Failed to reconcile data disks")
+	}
+	maintenance.next(RequeueReasonNodeJoining, nextDataDiskCheck)
+
+	// Make launched nodes schedulable once their kubelet reports Ready
+	nextReadinessCheck, err := r.reconcileNodeReadiness(ctx, nodePool, log)
+	if err != nil {
+		log.Error(err, "Failed to reconcile node readiness")
+	}
+	maintenance.next(RequeueReasonNodeJoining, nextReadinessCheck)
 
-	log.Info("GPUNodePool reconciled successfully", "nodeClass", nodeClass.Name)
-	return ctrl.Result{RequeueAfter: 10 * time.Minute}, nil
+	// Replace nodes whose instances the provider reclaimed or failed
+	nextInterruptionCheck, err := r.reconcileInterruptions(ctx, nodePool, nodeClass, log)
+	if err != nil {
+		log.Error(err, "Failed to check for interrupted instances")
+	}
+	maintenance.next(RequeueReasonInstanceInterrupted, nextInterruptionCheck)
+
+	// Remove nodes whose workloads fit on the rest of the pool
+	nextConsolidation, err := r.reconcileConsolidation(ctx, nodePool, log)
+	if err != nil {
+		log.Error(err, "Failed to consolidate nodes")
+	}
+	maintenance.next(RequeueReasonConsolidating, nextConsolidation)
+
+	// Keep nodes labelled, and running instances tagged, to match the pool template and node class
+	if err := r.reconcileNodeLabels(ctx, nodePool, log); err != nil {
+		log.Error(err, "Failed to reconcile node labels")
+	}
+	if err := r.reconcileInstanceTags(ctx, nodePool, nodeClass, log); err != nil {
+		log.Error(err, "Failed to reconcile instance tags")
+	}
+
+	// Run a Tailscale Connector for every node when the node class enables them
+	if err := r.reconcileTailscaleConnectors(ctx, nodePool, nodeClass, log); err != nil {
+		log.Error(err, "Failed to reconcile Tailscale connectors")
+	}
+
+	// Flag joined nodes that expose fewer GPUs than they were launched with
+	maintenance.mismatchedGPUs, err = r.validateNodeGPUs(ctx, nodePool, log)
+	if err != nil {
+		log.Error(err, "Failed to validate node GPUs")
+	}
+
+	return maintenance
 }
 
 // handleDeletion handles GPUNodePool deletion
 func (r *GPUNodePoolReconciler) handleDeletion(ctx context.Context, nodePool *tgpv1.GPUNodePool, log logr.Logger) (ctrl.Result, error) {
 	log.Info("Handling GPUNodePool deletion")
 
-	// Clean up all nodes created by this pool
-	if err := r.cleanupPoolNodes(ctx, nodePool, log); err != nil {
-		log.Error(err, "Failed to clean up pool nodes")
-		// Don't fail deletion if cleanup fails, but log the error
-		// In production, this might need retry logic or manual intervention
+	// Clean up all nodes created by this pool. The finalizer is kept until the pool's pods have
+	// been evicted or the drain times out, and until every instance has been terminated, so no
+	// instance is left running without a pool to retry it.
+	realized, err := r.cleanupPoolNodes(ctx, nodePool, log)
+	if err != nil {
+		reason, delay := RequeueReasonDeletionBlocked, terminationRetryInterval
+		if isDrainPending(err) {
+			log.Info("Waiting for pool nodes to drain", "reason", err.Error())
+			reason, delay = RequeueReasonDraining, drainPollInterval
+		} else {
+			log.Error(err, "Failed to clean up pool nodes, retrying")
+		}
+
+		// Stop tracking the instances already terminated so they are not terminated again
+		nodePool.Status.LastRequeueReason = reason
+		if updateErr := r.Status().Update(ctx, nodePool); updateErr != nil {
+			log.Error(updateErr, "Failed to update status")
+		} else {
+			r.recordSpotSavings(realized)
+		}
+		return requeueAfter(r.Metrics, controllerNameGPUNodePool, reason, delay), nil
 	}
 
 	controllerutil.RemoveFinalizer(nodePool, GPUNodePoolFinalizerName)
@@ -126,6 +325,14 @@ func (r *GPUNodePoolReconciler) handleDeletion(ctx context.Context, nodePool *tg
 		return ctrl.Result{}, err
 	}
 
+	// The pool's instances are gone with it, so whatever they saved is realized now. The pool
+	// status is not kept, only the metric records them.
+	now := time.Now()
+	for _, tracked := range nodePool.Status.Instances {
+		realized = append(realized, spotSavings{provider: tracked.Provider, gpuType: tracked.GPUType, dollars: spotSavingsAccrued(tracked, now)})
+	}
+	r.recordSpotSavings(realized)
+
 	log.Info("GPUNodePool deleted successfully")
 	return ctrl.Result{}, nil
 }
@@ -190,9 +397,15 @@ func (r *GPUNodePoolReconciler) handlePodDrivenProvisioning(ctx context.Context,
 	// Filter pods that match this node pool's capabilities
 	var matchingPods []corev1.Pod
 	for _, pod := range pendingPods {
-		if r.podMatchesPool(pod, nodePool, log) {
-			matchingPods = append(matchingPods, pod)
+		if !r.podMatchesPool(pod, nodePool, log) {
+			continue
+		}
+		// Wait for the node already launched for the pod rather than launching another
+		if podHasInstanceInFlight(nodePool, &pod, time.Now()) {
+			log.V(1).Info("Pod is waiting for an instance launched for it", "pod", pod.Name)
+			continue
 		}
+		matchingPods = append(matchingPods, pod)
 	}
 
 	if len(matchingPods) == 0 {
@@ -200,15 +413,46 @@ func (r *GPUNodePoolReconciler) handlePodDrivenProvisioning(ctx context.Context,
 		return nil
 	}
 
-	log.Info("Found pods that need GPU nodes", "count", len(matchingPods))
+	// Leave pods that a higher-weight or cheaper pool also matches to that pool,
+	// so several pools do not provision for the same pod
+	var pools tgpv1.GPUNodePoolList
+	if err := r.List(ctx, &pools); err != nil {
+		return fmt.Errorf("failed to list node pools: %w", err)
+	}
+	var selectedPods []corev1.Pod
+	var selections []*poolSelection
+	for i := range matchingPods {
+		selection := r.selectPoolForPod(ctx, &matchingPods[i], pools.Items, log)
+		if selection == nil || poolKey(selection.pool) != poolKey(nodePool) {
+			log.V(1).Info("Pod is provisioned by another pool", "pod", matchingPods[i].Name)
+			continue
+		}
+		selectedPods = append(selectedPods, matchingPods[i])
+		selections = append(selections, selection)
+	}
+
+	if len(selectedPods) == 0 {
+		log.V(1).Info("All matching pods are provisioned by other pools")
+		return nil
+	}
+
+	log.Info("Found pods that need GPU nodes", "count", len(selectedPods))
 
 	// For now, provision one node per unschedulable pod (simple implementation)
 	// TODO: Optimize by batching and considering existing capacity
-	for _, pod := range matchingPods[:1] { // Start with just one pod to avoid over-provisioning
+	for i, pod := range selectedPods[:1] { // Start with just one pod to avoid over-provisioning
+		r.recordPoolSelection(nodePool, selections[i])
 		if err := r.provisionNodeForPod(ctx, nodePool, nodeClass, &pod, log); err != nil {
-			log.Error(err, "Failed to provision node for pod", "pod", pod.Name)
-			continue
+			if isSchedulingMismatch(err) {
+				log.Info("Skipping launch for pod", "pod", pod.Name, "reason", err.Error())
+				continue
+			}
+			if isClassLimitExceeded(err) {
+				r.updateLimitCondition(nodePool, nodeClass, err)
+			}
+			return fmt.Errorf("failed to provision node for pod %s: %w", pod.Name, err)
 		}
+		r.updateLimitCondition(nodePool, nodeClass, nil)
 		break // Only provision one node per reconcile cycle to avoid race conditions
 	}
 
@@ -267,7 +511,9 @@ func (r *GPUNodePoolReconciler) podMatchesPool(pod corev1.Pod, nodePool *tgpv1.G
 	return true
 }
 
-// poolSupportsRequirement checks if the node pool can satisfy a node selector requirement
+// poolSupportsRequirement checks if the node pool can satisfy a node selector requirement.
+// Template labels must match exactly; otherwise the value must satisfy every pool requirement
+// on the key, evaluated with its operator.
 func (r *GPUNodePoolReconciler) poolSupportsRequirement(nodePool *tgpv1.GPUNodePool, key, value string) bool {
 	// Check template labels
 	if nodePool.Spec.Template.Metadata != nil && nodePool.Spec.Template.Metadata.Labels != nil {
@@ -277,17 +523,28 @@ func (r *GPUNodePoolReconciler) poolSupportsRequirement(nodePool *tgpv1.GPUNodeP
 	}
 
 	// Check node requirements
+	constrained := false
 	for _, req := range nodePool.Spec.Template.Spec.Requirements {
-		if req.Key == key {
-			for _, reqValue := range req.Values {
-				if reqValue == value {
-					return true
-				}
-			}
+		if req.Key != key {
+			continue
+		}
+		constrained = true
+		if !poolRequirementAllows(req, value) {
+			return false
 		}
 	}
 
-	return false
+	return constrained
+}
+
+// poolRequirementAllows reports whether nodes launched under a pool requirement can carry the
+// label value. Exists allows any value, DoesNotExist none, and Gt/Lt compare integers.
+func poolRequirementAllows(req tgpv1.NodeSelectorRequirement, value string) bool {
+	return nodeSelectorRequirementMatches(corev1.NodeSelectorRequirement{
+		Key:      req.Key,
+		Operator: corev1.NodeSelectorOperator(req.Operator),
+		Values:   req.Values,
+	}, value, true)
 }
 
 // podToleratesTaint checks if a pod tolerates a specific taint
@@ -304,65 +561,166 @@ func (r *GPUNodePoolReconciler) podToleratesTaint(pod corev1.Pod, taint corev1.T
 func (r *GPUNodePoolReconciler) provisionNodeForPod(ctx context.Context, nodePool *tgpv1.GPUNodePool, nodeClass *tgpv1.GPUNodeClass, pod *corev1.Pod, log logr.Logger) error {
 	log.Info("Provisioning GPU node for pod", "pod", pod.Name, "namespace", pod.Namespace)
 
+	// Refuse to launch past the node class limits, counting the nodes of every pool using it
+	var classUsage nodeClassUsage
+	if hasNodeClassLimits(nodeClass) {
+		usage, err := r.nodeClassUsage(ctx, nodeClass)
+		if err != nil {
+			return err
+		}
+		if err := checkNodeClassNodeLimit(nodeClass, usage); err != nil {
+			return err
+		}
+		classUsage = usage
+	}
+
 	// Extract GPU requirements from the pod
 	gpuRequirement, err := r.extractGPURequirement(pod)
 	if err != nil {
 		return fmt.Errorf("failed to extract GPU requirement: %w", err)
 	}
+	gpuRequirement.SpotPolicy = podSpotPolicy(pod)
+
+	// Without a region from the pod, try the pool's regions in the order they are listed
+	regions := preferredRegions(nodePool, nodeClass, gpuRequirement)
+
+	// Launch on the best provider with capacity, falling back to the next best one in
+	// selection order when a launch fails
+	var selectedProvider *tgpv1.ProviderConfig
+	var providerClient providers.ProviderClient
+	var instance *providers.GPUInstance
+	var launchErr error
+	for {
+		// Select the best provider in the first preferred region with capacity, falling back
+		// to the class's alternative GPU types when the requested one has no capacity
+		selectedProvider, providerClient, err = r.selectProviderInPreferredRegions(ctx, nodePool, nodeClass, pod, gpuRequirement, regions, log)
+		if err != nil {
+			if launchErr != nil {
+				return fmt.Errorf("failed to launch instance on any provider: %w", launchErr)
+			}
+			return fmt.Errorf("failed to select provider: %w", err)
+		}
 
-	// If no region specified in pod, select from node pool requirements
-	if gpuRequirement.Region == "" {
-		gpuRequirement.Region = r.selectRegionFromNodePool(nodePool)
-	}
+		if err := checkNodeClassCostLimit(nodeClass, classUsage, gpuRequirement.HourlyPrice); err != nil {
+			return err
+		}
 
-	// Select the best provider/region for this request
-	selectedProvider, providerClient, err := r.selectBestProvider(ctx, nodeClass, gpuRequirement, log)
-	if err != nil {
-		return fmt.Errorf("failed to select provider: %w", err)
-	}
+		log.Info("Selected provider for provisioning",
+			"provider", selectedProvider.Name,
+			"region", gpuRequirement.Region,
+			"gpuType", gpuRequirement.GPUType,
+			"spot", gpuRequirement.Spot,
+			"estimatedHourlySavings", gpuRequirement.SpotSavings)
 
-	log.Info("Selected provider for provisioning",
-		"provider", selectedProvider.Name,
-		"gpuType", gpuRequirement.GPUType)
+		gpuRequirement.AttachDataDisksAfterLaunch = attachesDataDisksAfterLaunch(providerClient)
 
-	// Create launch request
-	launchRequest, err := r.createLaunchRequest(ctx, nodePool, nodeClass, gpuRequirement, selectedProvider.Name)
-	if err != nil {
-		return fmt.Errorf("failed to create launch request: %w", err)
-	}
+		// Confirm the pod would actually bind to the node we are about to launch
+		plannedNode := r.buildPlannedNode(nodePool, gpuRequirement, selectedProvider.Name)
+		if err := simulatePodScheduling(pod, plannedNode); err != nil {
+			return err
+		}
 
-	// Launch the instance
-	instance, err := providerClient.LaunchInstance(ctx, launchRequest)
-	if err != nil {
-		return fmt.Errorf("failed to launch instance: %w", err)
+		// Dry-run pools stop here, reporting what they would have launched
+		if nodePool.Spec.DryRun {
+			r.recordDryRunSelection(ctx, nodePool, pod, selectedProvider.Name, providerClient, gpuRequirement, time.Now(), log)
+			return nil
+		}
+
+		// Count the launch against the provider's limit until its node exists
+		endLaunch := r.quota.begin(selectedProvider.Name)
+		instance, launchErr = r.launchOnProvider(ctx, nodePool, nodeClass, pod, gpuRequirement, selectedProvider.Name, providerClient, log)
+		if launchErr == nil {
+			defer endLaunch()
+			break
+		}
+		endLaunch()
+
+		// The cached offers may no longer reflect the provider's capacity
+		r.Inventory.ExpireProvider(selectedProvider.Name)
+		log.Info("Launch failed, falling back to the next provider", "provider", selectedProvider.Name, "error", launchErr.Error())
+		excludeFailedProvider(gpuRequirement, selectedProvider.Name)
 	}
 
 	log.Info("Instance launched successfully",
 		"instanceID", instance.ID,
-		"provider", selectedProvider.Name)
+		"provider", selectedProvider.Name,
+		"operatorVersion", r.OperatorVersion)
+	r.trackLaunchedInstance(ctx, nodePool, pod, selectedProvider.Name, providerClient, gpuRequirement, instance, log)
 
 	// Create Kubernetes Node object
-	if err := r.createKubernetesNode(ctx, nodePool, instance, selectedProvider, log); err != nil {
+	if err := r.createKubernetesNode(ctx, nodePool, gpuRequirement, instance, instanceTags(nodePool, nodeClass), selectedProvider, log); err != nil {
 		// If node creation fails, attempt to clean up the cloud instance
 		if cleanupErr := providerClient.TerminateInstance(ctx, instance.ID); cleanupErr != nil {
 			log.Error(cleanupErr, "Failed to cleanup instance after node creation failure", "instanceID", instance.ID)
+		} else {
+			r.launches.remove(instance.ID)
+			untrackInstance(nodePool, instance.ID)
+			r.recordTermination(nodePool, selectedProvider.Name, instance.ID, "", tgpv1.TerminationReasonLaunchFailed)
 		}
 		return fmt.Errorf("failed to create Kubernetes node: %w", err)
 	}
+	r.launches.remove(instance.ID)
+	setInstancePhase(nodePool, instance.ID, tgpv1.PoolInstancePhaseRegistered)
 
 	log.Info("GPU node provisioned successfully",
 		"pod", pod.Name,
 		"instanceID", instance.ID,
-		"provider", selectedProvider.Name)
+		"provider", selectedProvider.Name,
+		"operatorVersion", r.OperatorVersion)
 
 	return nil
 }
 
+// launchOnProvider builds the launch request for the selected provider and launches the instance
+func (r *GPUNodePoolReconciler) launchOnProvider(ctx context.Context, nodePool *tgpv1.GPUNodePool, nodeClass *tgpv1.GPUNodeClass, pod *corev1.Pod, requirement *GPURequirement, providerName string, providerClient providers.ProviderClient, log logr.Logger) (*providers.GPUInstance, error) {
+	launchRequest, err := r.createLaunchRequest(ctx, nodePool, nodeClass, requirement, providerName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create launch request: %w", err)
+	}
+
+	// Adapt the bootstrap payload to the selected provider's mechanism
+	launchRequest.UserData, err = providers.RenderUserData(providerClient, launchRequest.UserData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render user data for provider %s: %w", providerName, err)
+	}
+
+	instance, err := r.launchWithOnDemandFallback(ctx, nodePool, nodeClass, pod, requirement, providerName, providerClient, launchRequest, log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to launch instance on %s: %w", providerName, err)
+	}
+	return instance, nil
+}
+
 // GPURequirement represents GPU requirements extracted from a pod
 type GPURequirement struct {
 	GPUType  string
 	GPUCount int
 	Region   string // Preferred region from node selector or annotations
+	Spot     bool   // Whether spot capacity was selected for this requirement
+
+	// SpotSavings is the estimated hourly saving of the selected spot price versus on-demand
+	SpotSavings float64
+
+	// HourlyPrice is the estimated hourly price of the selected capacity, 0 if unknown. It is
+	// the price the provider charges, without the interruption premium used to rank spot offers.
+	HourlyPrice float64
+
+	// OnDemandPrice is the selected provider's on-demand hourly price, 0 if unknown, used when
+	// a spot launch falls back to on-demand capacity
+	OnDemandPrice float64
+
+	// RequestedGPUType is the GPU type originally requested when a fallback type was selected
+	RequestedGPUType string
+
+	// SpotPolicy is the spot policy the pod asked for through its annotation, overriding the pool's
+	SpotPolicy tgpv1.SpotPolicy
+
+	// AttachDataDisksAfterLaunch is set when the selected provider attaches the pool's data
+	// disks once the instance is running rather than at launch
+	AttachDataDisksAfterLaunch bool
+
+	// FailedProviders are providers a launch for this requirement already failed on
+	FailedProviders map[string]bool
 }
 
 // extractGPURequirement extracts GPU requirements from a pod specification
@@ -420,72 +778,80 @@ func (r *GPUNodePoolReconciler) extractGPURequirement(pod *corev1.Pod) (*GPURequ
 	return requirement, nil
 }
 
-// selectRegionFromNodePool selects a region from node pool requirements
-func (r *GPUNodePoolReconciler) selectRegionFromNodePool(nodePool *tgpv1.GPUNodePool) string {
-	// Look for region requirement in node pool template
-	for _, req := range nodePool.Spec.Template.Spec.Requirements {
-		if req.Key == "tgp.io/region" && len(req.Values) > 0 {
-			// Select the first available region (could be improved with pricing logic)
-			return req.Values[0]
-		}
-	}
-	return "" // No region requirement found
-}
-
 // selectGPUFromTGPRequirements selects optimal GPU based on TGP resource requirements
 func (r *GPUNodePoolReconciler) selectGPUFromTGPRequirements(tgpReqs *providers.TGPResourceRequirements, baseReq *GPURequirement) (*GPURequirement, error) {
-	// TODO: This is a simplified implementation - we should get actual available GPUs from providers
-	// For now, use static selection based on VRAM requirements
-
 	requirement := &GPURequirement{
 		GPUCount: int(tgpReqs.GPUCount),
-	}
-
-	// Select GPU type based on VRAM requirements and vendor preference
-	if tgpReqs.MinVRAM <= 2 {
-		// Small VRAM requirements
-		if tgpReqs.PreferredVendor == "amd" {
-			requirement.GPUType = "AMD_MI325X" // Placeholder - would need real AMD options
-		} else {
-			requirement.GPUType = "NVIDIA_A16" // 2GB VRAM
-		}
-	} else if tgpReqs.MinVRAM <= 8 {
-		// Medium VRAM requirements
-		if tgpReqs.PreferredVendor == "amd" {
-			requirement.GPUType = "AMD_MI300X"
-		} else {
-			requirement.GPUType = "NVIDIA_A40" // 48GB VRAM (overkill but available)
-		}
-	} else {
-		// High VRAM requirements
-		if tgpReqs.PreferredVendor == "amd" {
-			requirement.GPUType = "AMD_MI300X"
-		} else {
-			requirement.GPUType = "NVIDIA_A100" // 80GB VRAM
-		}
+		GPUType:  providers.SelectGPUType(tgpReqs),
 	}
 
 	return requirement, nil
 }
 
-// selectBestProvider selects the optimal provider based on pricing and availability
-func (r *GPUNodePoolReconciler) selectBestProvider(ctx context.Context, nodeClass *tgpv1.GPUNodeClass, requirement *GPURequirement, log logr.Logger) (*tgpv1.ProviderConfig, providers.ProviderClient, error) {
-	var bestProvider *tgpv1.ProviderConfig
-	var bestClient providers.ProviderClient
-	bestPrice := float64(^uint(0) >> 1) // Max float64
+// selectBestProvider selects the optimal provider based on pricing and availability.
+// Depending on the pool's spot policy, spot and on-demand prices are compared across
+// all providers, and the node class's selection strategy chooses between the providers
+// that can launch. The chosen capacity type is recorded in requirement.Spot, along
+// with the estimated hourly saving versus on-demand in requirement.SpotSavings and the
+// selected price in requirement.HourlyPrice.
+func (r *GPUNodePoolReconciler) selectBestProvider(ctx context.Context, nodePool *tgpv1.GPUNodePool, nodeClass *tgpv1.GPUNodeClass, requirement *GPURequirement, log logr.Logger) (*tgpv1.ProviderConfig, providers.ProviderClient, error) {
+	strategy, err := providers.SelectionStrategyFor(nodeClass.Spec.ProviderSelection)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Only provision GPU types and regions the node class allows
+	if reason := instanceRequirementsReason(nodeClass.Spec.InstanceRequirements, requirement); reason != "" {
+		return nil, nil, fmt.Errorf("%w for GPU type %s: %s", errNoSuitableProvider, requirement.GPUType, reason)
+	}
+
+	var candidates []providers.ProviderCandidate
+	evaluated := make(map[string]evaluatedProvider)
+
+	policy := spotPolicyForLaunch(nodePool, nodeClass, requirement)
+	premium := spotPremiumForPool(nodePool)
+	expectedDuration := expectedDurationForPool(nodePool)
+	offerRequirements := offerRequirementsForClass(nodeClass)
+	var unsupported []string
+
+	// Count running instances so providers near their configured limit can be deprioritized
+	running, err := r.runningInstancesByProvider(ctx)
+	if err != nil {
+		log.Error(err, "Failed to count running instances, ignoring provider limits")
+	}
 
 	// Evaluate each enabled provider
 	for _, providerConfig := range nodeClass.Spec.Providers {
 		if providerConfig.Enabled != nil && !*providerConfig.Enabled {
 			continue
 		}
+		if !config.Current(r.Config).FeatureEnabled(providerConfig.Name, config.FeatureLaunch) {
+			log.V(1).Info("Launch disabled for provider", "provider", providerConfig.Name)
+			continue
+		}
+		if requirement.FailedProviders[providerConfig.Name] {
+			unsupported = append(unsupported, fmt.Sprintf("provider %s: launch failed", providerConfig.Name))
+			continue
+		}
+
+		// Skip providers already at their instance limit
+		utilization, limited := r.quotaUtilization(providerConfig.Name, running)
+		if limited {
+			r.Metrics.SetProviderQuotaUtilization(providerConfig.Name, utilization)
+			if utilization >= 1.0 {
+				reason := fmt.Sprintf("provider %s is at its limit of %d instances", providerConfig.Name, config.Current(r.Config).MaxInstances(providerConfig.Name))
+				log.Info("Provider excluded by instance limit", "provider", providerConfig.Name, "reason", reason)
+				unsupported = append(unsupported, reason)
+				continue
+			}
+		}
 
 		// Get credentials for this provider
 		namespace := providerConfig.CredentialsRef.Namespace
 		if namespace == "" {
 			namespace = "default" // fallback
 		}
-		credentials, err := r.Config.GetProviderCredentials(ctx, r.Client, providerConfig.Name, namespace)
+		credentials, err := config.Current(r.Config).GetProviderCredentials(ctx, r.Client, providerConfig.Name, namespace)
 		if err != nil {
 			log.Error(err, "Failed to get credentials for provider", "provider", providerConfig.Name)
 			continue
@@ -498,58 +864,313 @@ func (r *GPUNodePoolReconciler) selectBestProvider(ctx context.Context, nodeClas
 			continue
 		}
 
-		// Get pricing for this GPU type
-		pricing, err := providerClient.GetNormalizedPricing(ctx, requirement.GPUType, requirement.Region)
-		if err != nil {
-			log.V(1).Info("Failed to get pricing", "provider", providerConfig.Name, "error", err)
+		// Skip providers that cannot express the requested GPU type or region before querying them
+		if _, _, err := r.translations.translate(providerClient, requirement.GPUType, requirement.Region); err != nil {
+			log.Info("Provider cannot satisfy requirement", "provider", providerConfig.Name, "reason", err.Error())
+			unsupported = append(unsupported, err.Error())
+			continue
+		}
+
+		// Only launch in regions in the countries the class allows
+		if reason := residencyExclusionReason(nodeClass.Spec.InstanceRequirements, providerClient, requirement.Region); reason != "" {
+			log.Info("Provider excluded by data residency", "provider", providerConfig.Name, "reason", reason)
+			unsupported = append(unsupported, fmt.Sprintf("provider %s: %s", providerConfig.Name, reason))
+			continue
+		}
+
+		// Point the client at the pool's account, if the class allows it for this provider
+		if err := selectPoolAccount(nodePool, &providerConfig, providerClient); err != nil {
+			log.V(1).Info("Skipping provider for pool account", "provider", providerConfig.Name, "reason", err.Error())
+			continue
+		}
+
+		// Share offer lookups with the node class controller and other pools using the same account
+		providerClient = r.Inventory.Wrap(providerClient, namespace+"/"+nodePool.Spec.Account)
+
+		// Skip providers that do not meet the class quality policy
+		if reason := qualityExclusionReason(nodeClass.Spec.QualityPolicy, providerClient.GetProviderInfo()); reason != "" {
+			log.V(1).Info("Provider excluded by quality policy", "provider", providerConfig.Name, "reason", reason)
+			continue
+		}
+
+		// Skip providers that cannot attach the pool's data disks as requested
+		if reason := dataDiskExclusionReason(nodePool, providerClient.GetProviderInfo()); reason != "" {
+			log.Info("Provider excluded by data disks", "provider", providerConfig.Name, "reason", reason)
+			unsupported = append(unsupported, fmt.Sprintf("provider %s: %s", providerConfig.Name, reason))
+			continue
+		}
+		inventoryEnabled := config.Current(r.Config).FeatureEnabled(providerConfig.Name, config.FeatureInventory)
+
+		// Skip providers without capacity for the GPU type in the region, or whose offers do not
+		// meet the class requirements. Without inventory capacity is assumed, but the offer
+		// requirements cannot be checked.
+		if !inventoryEnabled && len(offerRequirements) > 0 {
+			log.V(1).Info("Inventory disabled for provider, cannot check offer requirements",
+				"provider", providerConfig.Name, "requirement", offerRequirements[0].name)
 			continue
 		}
+		if inventoryEnabled {
+			reason, err := r.offerExclusionReason(ctx, providerClient, requirement, offerRequirements)
+			if err != nil {
+				log.V(1).Info("Failed to check offers", "provider", providerConfig.Name, "error", err)
+				continue
+			}
+			if reason != "" {
+				log.Info("Provider excluded by offers", "provider", providerConfig.Name, "reason", reason)
+				unsupported = append(unsupported, fmt.Sprintf("provider %s: %s", providerConfig.Name, reason))
+				continue
+			}
+		}
+
+		// Get on-demand pricing for this GPU type, also used as the reference for spot savings
+		onDemandPrice := 0.0
+		if config.Current(r.Config).FeatureEnabled(providerConfig.Name, config.FeaturePricing) {
+			pricing, err := providerClient.GetNormalizedPricing(ctx, requirement.GPUType, requirement.Region)
+			if isNotAvailableInRegion(err) {
+				reason := fmt.Sprintf("%s is not available in region %s", requirement.GPUType, requirement.Region)
+				log.Info("Provider excluded by region", "provider", providerConfig.Name, "reason", reason)
+				unsupported = append(unsupported, fmt.Sprintf("provider %s: %s", providerConfig.Name, reason))
+				continue
+			}
+			if err != nil {
+				log.V(1).Info("Failed to get pricing", "provider", providerConfig.Name, "error", err)
+			} else {
+				onDemandPrice = pricing.PricePerHour
+			}
+		}
 
-		// Apply priority weighting (lower priority number = higher preference)
-		weightedPrice := pricing.PricePerHour
-		if providerConfig.Priority > 0 {
-			weightedPrice = pricing.PricePerHour * (1.0 + float64(providerConfig.Priority)*0.1)
+		// Get spot pricing when the policy allows it and the provider supports it
+		spotPrice := 0.0
+		if policy != tgpv1.SpotPolicyNever && inventoryEnabled && providerClient.GetProviderInfo().SupportsSpotInstances {
+			spotPrice, err = r.getBestSpotPrice(ctx, providerClient, requirement, nodeClass)
+			if err != nil {
+				log.V(1).Info("Failed to get spot pricing", "provider", providerConfig.Name, "error", err)
+			}
 		}
 
-		if weightedPrice < bestPrice {
-			bestPrice = weightedPrice
-			bestProvider = &providerConfig
-			bestClient = providerClient
+		price, spot, ok := chooseCapacityType(policy, premium, onDemandPrice, spotPrice)
+		if !ok {
+			continue
+		}
+
+		// Deprioritize providers nearing their instance limit, and those billing for much
+		// longer than the pool's nodes are expected to run
+		candidate := providers.ProviderCandidate{
+			Name:            providerConfig.Name,
+			Client:          providerClient,
+			Priority:        int(providerConfig.Priority),
+			Price:           price,
+			Spot:            spot,
+			Penalty:         quotaPenalty(utilization),
+			BillingOverhead: providers.BillingOverhead(providerClient.GetProviderInfo(), expectedDuration),
+		}
+		candidates = append(candidates, candidate)
+		savings := 0.0
+		if spot && onDemandPrice > spotPrice {
+			savings = onDemandPrice - spotPrice
+		}
+		hourlyPrice := onDemandPrice
+		if spot {
+			hourlyPrice = spotPrice
+		}
+		evaluated[providerConfig.Name] = evaluatedProvider{
+			config:        &providerConfig,
+			candidate:     candidate,
+			hourlyPrice:   hourlyPrice,
+			onDemandPrice: onDemandPrice,
+			savings:       savings,
 		}
 
 		log.V(1).Info("Evaluated provider",
 			"provider", providerConfig.Name,
-			"price", pricing.PricePerHour,
-			"weightedPrice", weightedPrice)
+			"onDemandPrice", onDemandPrice,
+			"spotPrice", spotPrice,
+			"spot", spot,
+			"quotaUtilization", utilization,
+			"billingOverhead", candidate.BillingOverhead,
+			"weightedPrice", candidate.WeightedPrice())
+	}
+
+	if len(candidates) == 0 {
+		if len(unsupported) > 0 {
+			return nil, nil, fmt.Errorf("%w for GPU type %s: %s", errNoSuitableProvider, requirement.GPUType, strings.Join(unsupported, "; "))
+		}
+		return nil, nil, fmt.Errorf("%w for GPU type %s", errNoSuitableProvider, requirement.GPUType)
+	}
+
+	name, client, err := strategy.Select(ctx, candidates, providers.SelectionRequirement{
+		GPUType:  requirement.GPUType,
+		GPUCount: requirement.GPUCount,
+		Region:   requirement.Region,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to select provider: %w", err)
+	}
+	selected, ok := evaluated[name]
+	if !ok {
+		return nil, nil, fmt.Errorf("provider selection strategy chose %q, which was not a candidate", name)
+	}
+	requirement.Spot = selected.candidate.Spot
+	requirement.SpotSavings = selected.savings
+	requirement.HourlyPrice = selected.hourlyPrice
+	requirement.OnDemandPrice = selected.onDemandPrice
+	return selected.config, client, nil
+}
+
+// evaluatedProvider is a provider considered by selectBestProvider
+type evaluatedProvider struct {
+	config    *tgpv1.ProviderConfig
+	candidate providers.ProviderCandidate
+	// hourlyPrice is the price of the chosen capacity type; candidate.Price includes the
+	// interruption premium when ranking spot capacity
+	hourlyPrice float64
+	// onDemandPrice is the provider's on-demand price, 0 if unknown
+	onDemandPrice float64
+	// savings is the estimated hourly saving of its spot price versus on-demand
+	savings float64
+}
+
+// getBestSpotPrice returns the cheapest available spot price for the requirement among the offers
+// that meet the node class's quality policy and instance requirements, or 0 if none is offered
+func (r *GPUNodePoolReconciler) getBestSpotPrice(ctx context.Context, providerClient providers.ProviderClient, requirement *GPURequirement, nodeClass *tgpv1.GPUNodeClass) (float64, error) {
+	requirements := nodeClass.Spec.InstanceRequirements
+	verified := verifiedOnly(nodeClass.Spec.QualityPolicy)
+	_, minMemoryGiB := minInstanceSize(requirements)
+	offers, err := providerClient.ListAvailableGPUs(ctx, &providers.GPUFilters{
+		GPUType:         requirement.GPUType,
+		Region:          requirement.Region,
+		SpotOnly:        true,
+		VerifiedOnly:    verified,
+		MinMemory:       minMemoryGiB,
+		MinVCPUPerGPU:   minVCPUPerGPU(requirements),
+		MinGPUMemoryGiB: minGPUMemoryGiB(requirements),
+		MinCUDAVersion:  minCUDAVersion(requirements),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	best := 0.0
+	for _, offer := range offers {
+		if !offer.Available || (verified && !offer.Verified) || !offerMeetsInstanceRequirements(requirements, offer) {
+			continue
+		}
+		price := offer.SpotPrice
+		if price <= 0 {
+			price = offer.HourlyPrice
+		}
+		if price > 0 && (best == 0 || price < best) {
+			best = price
+		}
+	}
+	return best, nil
+}
+
+// selectPoolAccount points the provider client at the pool's account after checking
+// that the node class allows it for this provider
+func selectPoolAccount(nodePool *tgpv1.GPUNodePool, providerConfig *tgpv1.ProviderConfig, providerClient providers.ProviderClient) error {
+	account := nodePool.Spec.Account
+	if account == "" {
+		return nil
+	}
+	if !containsString(providerConfig.AllowedAccounts, account) {
+		return fmt.Errorf("account %s is not allowed for provider %s", account, providerConfig.Name)
 	}
+	return providers.SelectAccount(providerClient, account)
+}
+
+// spotPolicyForPool returns the pool's spot policy, defaulting to Never
+func spotPolicyForPool(nodePool *tgpv1.GPUNodePool) tgpv1.SpotPolicy {
+	if nodePool.Spec.Spot == "" {
+		return tgpv1.SpotPolicyNever
+	}
+	return nodePool.Spec.Spot
+}
 
-	if bestProvider == nil {
-		return nil, nil, fmt.Errorf("no suitable provider found for GPU type %s", requirement.GPUType)
+// spotPremiumForPool returns the interruption premium as a fraction, defaulting to 20%
+func spotPremiumForPool(nodePool *tgpv1.GPUNodePool) float64 {
+	if nodePool.Spec.SpotInterruptionPremium != nil {
+		return float64(*nodePool.Spec.SpotInterruptionPremium) / 100.0
+	}
+	return 0.2
+}
+
+// expectedDurationForPool returns how long the pool's nodes are expected to run, or 0 if unknown
+func expectedDurationForPool(nodePool *tgpv1.GPUNodePool) time.Duration {
+	if nodePool.Spec.ExpectedDuration == nil {
+		return 0
+	}
+	return nodePool.Spec.ExpectedDuration.Duration
+}
+
+// chooseCapacityType decides between spot and on-demand capacity for a single provider.
+// Prices of 0 mean the capacity type is unavailable. It returns the comparison price,
+// whether spot was chosen and whether any capacity type is usable.
+func chooseCapacityType(policy tgpv1.SpotPolicy, premium, onDemandPrice, spotPrice float64) (float64, bool, bool) {
+	switch policy {
+	case tgpv1.SpotPolicyRequired:
+		if spotPrice <= 0 {
+			return 0, false, false
+		}
+		return spotPrice, true, true
+	case tgpv1.SpotPolicyPreferred:
+		effectiveSpot := spotPrice * (1.0 + premium)
+		if spotPrice > 0 && (onDemandPrice <= 0 || effectiveSpot < onDemandPrice) {
+			return effectiveSpot, true, true
+		}
 	}
 
-	return bestProvider, bestClient, nil
+	if onDemandPrice <= 0 {
+		return 0, false, false
+	}
+	return onDemandPrice, false, true
 }
 
 // createProviderClient creates a provider client based on provider name
 func (r *GPUNodePoolReconciler) createProviderClient(providerName, credentials string) (providers.ProviderClient, error) {
-	switch providerName {
-	case "vultr":
-		client, err := vultr.NewClient(credentials)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create Vultr client: %w", err)
-		}
-		return client, nil
-	case "gcp":
-		client := gcp.NewClient(credentials)
-		// Initialize will be called when needed
-		return client, nil
-	default:
-		return nil, fmt.Errorf("unsupported provider: %s", providerName)
+	var client providers.ProviderClient
+	var err error
+	if r.newClient != nil {
+		client, err = r.newClient(providerName, credentials)
+	} else {
+		client, err = newProviderClient(config.Current(r.Config), providerName, credentials)
 	}
+	if err != nil {
+		return nil, err
+	}
+	return r.LaunchLimiters.Wrap(r.CircuitBreakers.Wrap(instrumentProviderClient(r.Metrics, client))), nil
+}
+
+// newProviderClient creates the named provider's client with the operator config's image
+// prefix and debug logging applied
+func newProviderClient(cfg *config.OperatorConfig, providerName, credentials string) (providers.ProviderClient, error) {
+	client, err := factory.NewClientFor(providerName, credentials)
+	if err != nil {
+		return nil, err
+	}
+	if prefixed, ok := client.(interface{ SetImagePrefix(string) }); ok {
+		prefixed.SetImagePrefix(cfg.ImagePrefix(providerName))
+	}
+	enableProviderDebugLogging(cfg, client)
+	return client, nil
+}
+
+// enableProviderDebugLogging turns on API request/response logging when configured for the provider
+func enableProviderDebugLogging(cfg *config.OperatorConfig, client providers.ProviderClient) {
+	providerName := client.GetProviderInfo().Name
+	if !cfg.DebugLoggingEnabled(providerName) {
+		return
+	}
+	providers.EnableDebugLogging(client, ctrl.Log.WithName("provider-api").WithValues("provider", providerName))
 }
 
 // createLaunchRequest creates a launch request for the selected provider
 func (r *GPUNodePoolReconciler) createLaunchRequest(ctx context.Context, nodePool *tgpv1.GPUNodePool, nodeClass *tgpv1.GPUNodeClass, requirement *GPURequirement, providerName string) (*providers.LaunchRequest, error) {
+	// Never launch a GPU type, region or capacity type the node class rules out
+	if reason := instanceRequirementsReason(nodeClass.Spec.InstanceRequirements, requirement); reason != "" {
+		return nil, fmt.Errorf("launch violates the instance requirements of node class %s: %s", nodeClass.Name, reason)
+	}
+
 	// Build user data script for node setup
 	userData, err := r.buildUserDataScript(ctx, nodePool, nodeClass, providerName)
 	if err != nil {
@@ -559,7 +1180,7 @@ func (r *GPUNodePoolReconciler) createLaunchRequest(ctx context.Context, nodePoo
 	// Create labels for the instance
 	labels := make(map[string]string)
 	labels["tgp.io/nodepool"] = nodePool.Name
-	labels["tgp.io/nodeclass"] = nodeClass.Name
+	labels[tgpv1.NodeLabelNodeClass] = nodeClass.Name
 	labels["tgp.io/gpu-type"] = requirement.GPUType
 	if nodePool.Spec.Template.Metadata != nil && nodePool.Spec.Template.Metadata.Labels != nil {
 		for k, v := range nodePool.Spec.Template.Metadata.Labels {
@@ -569,24 +1190,74 @@ func (r *GPUNodePoolReconciler) createLaunchRequest(ctx context.Context, nodePoo
 
 	// Determine max price
 	maxPrice := 10.0 // Default max price per hour
-	if nodePool.Spec.MaxHourlyPrice != nil {
-		if price, err := strconv.ParseFloat(*nodePool.Spec.MaxHourlyPrice, 64); err == nil {
-			maxPrice = price
-		}
+	if price, capped := poolMaxHourlyPrice(nodePool); capped {
+		maxPrice = price
+	}
+
+	// Providers that cannot attach data disks at launch get them once the instance runs
+	var dataDisks []providers.DataDisk
+	if !requirement.AttachDataDisksAfterLaunch {
+		dataDisks = dataDisksForPool(nodePool)
 	}
 
 	return &providers.LaunchRequest{
 		GPUType:      requirement.GPUType,
+		GPUCount:     requirement.GPUCount,
 		Region:       requirement.Region,
 		Image:        "talos", // Use Vultr's native Talos OS image
 		UserData:     userData,
 		Labels:       labels,
-		SpotInstance: false, // TODO: Support spot instances
+		SpotInstance: requirement.Spot,
 		MaxPrice:     maxPrice,
 		TalosConfig:  nodeClass.Spec.TalosConfig,
+		DataDisks:    dataDisks,
+		Tags:         r.launchTags(nodePool, nodeClass),
+		BootDiskGiB:  bootDiskForClass(nodeClass),
+
+		MinCUDAVersion: minCUDAVersion(nodeClass.Spec.InstanceRequirements),
+
+		NameCollisionRetries: config.Current(r.Config).NameCollisionRetries(),
 	}, nil
 }
 
+// launchTags returns the cloud tags for a new instance: the pool's instance tags plus the
+// operator version. Only the instance tags are recorded as applied, so the version tag is
+// never removed when instance tags are reconciled.
+func (r *GPUNodePoolReconciler) launchTags(nodePool *tgpv1.GPUNodePool, nodeClass *tgpv1.GPUNodeClass) map[string]string {
+	desired := instanceTags(nodePool, nodeClass)
+	if r.OperatorVersion == "" {
+		return desired
+	}
+
+	tags := make(map[string]string, len(desired)+1)
+	for k, v := range desired {
+		tags[k] = v
+	}
+	tags[TagOperatorVersion] = r.OperatorVersion
+	return tags
+}
+
+// dataDisksForPool converts the pool's data disk templates into provider data disks
+func dataDisksForPool(nodePool *tgpv1.GPUNodePool) []providers.DataDisk {
+	var disks []providers.DataDisk
+	for _, disk := range nodePool.Spec.Template.Spec.DataDisks {
+		disks = append(disks, providers.DataDisk{
+			VolumeID:   disk.VolumeID,
+			ReadOnly:   disk.ReadOnly,
+			DeviceName: disk.DeviceName,
+		})
+	}
+	return disks
+}
+
+// bootDiskForClass returns the class's boot disk size in GiB, or 0 for the provider default
+func bootDiskForClass(nodeClass *tgpv1.GPUNodeClass) int {
+	if requirements := nodeClass.Spec.InstanceRequirements; requirements != nil && requirements.BootDiskGiB != nil {
+		return int(*requirements.BootDiskGiB)
+	}
+	return 0
+}
+
 // buildUserDataScript creates provider-specific initialization data for new nodes
 func (r *GPUNodePoolReconciler) buildUserDataScript(ctx context.Context, nodePool *tgpv1.GPUNodePool, nodeClass *tgpv1.GPUNodeClass, providerName string) (string, error) {
 	// Generate Talos machine configuration
@@ -618,6 +1289,19 @@ func (r *GPUNodePoolReconciler) generateTalosMachineConfig(ctx context.Context,
 		return "", fmt.Errorf("failed to apply machine config template: %w", err)
 	}
 
+	// Refuse configs that would launch a node unable to join the cluster. The rendered template is
+	// checked before the extras are merged, since extra file contents are used verbatim and may
+	// legitimately contain template syntax.
+	if err := validateMachineConfig(config); err != nil {
+		return "", err
+	}
+
+	// Layer the class's extra files and environment over the rendered template
+	config, err = mergeTalosExtras(config, nodeClass.Spec.TalosConfig)
+	if err != nil {
+		return "", fmt.Errorf("failed to merge extra files and env into machine config: %w", err)
+	}
+
 	return config, nil
 }
 
@@ -668,7 +1352,9 @@ func (r *GPUNodePoolReconciler) getMachineConfigTemplateFromSecret(ctx context.C
 	return string(templateData), nil
 }
 
-// getDefaultMachineConfigTemplate returns a default Talos machine configuration template
+// getDefaultMachineConfigTemplate returns a default Talos machine configuration template. Node
+// labels and taints are passed to the kubelet as single comma-separated flags, so the node
+// registers with them and the rendered config has no duplicate keys.
 func (r *GPUNodePoolReconciler) getDefaultMachineConfigTemplate() string {
 	return `version: v1alpha1
 debug: false
@@ -687,17 +1373,10 @@ machine:
       validSubnets:
         - 0.0.0.0/0
     extraArgs:
-      {{- range $key, $value := .NodeLabels}}
-      node-labels: "{{$key}}={{$value}}"
+      node-labels: "{{$sep := ""}}{{range $key, $value := .NodeLabels}}{{$sep}}{{$key}}={{$value}}{{$sep = ","}}{{end}}"
+      {{- if .NodeTaints}}
+      register-with-taints: "{{$sep := ""}}{{range .NodeTaints}}{{$sep}}{{.Key}}={{.Value}}:{{.Effect}}{{$sep = ","}}{{end}}"
       {{- end}}
-    {{- if .NodeTaints}}
-    registerWithTaints:
-      {{- range .NodeTaints}}
-      - key: "{{.Key}}"
-        value: "{{.Value}}"
-        effect: "{{.Effect}}"
-      {{- end}}
-    {{- end}}
   kernel:
     modules:
       - name: nvidia
@@ -712,74 +1391,6 @@ machine:
   features:
     rbac: true
     stableHostname: true
-  files:
-    - content: |
-        #!/bin/bash
-        # TGP Node Setup - GPU Operator will handle GPU drivers
-        echo "TGP node setup complete for pool: {{.NodePoolName}}"
-        
-        # Wait for kubelet to be ready, then remove startup taint
-        while ! kubectl --kubeconfig=/etc/kubernetes/kubelet.conf get nodes $(hostname) > /dev/null 2>&1; do
-          echo "Waiting for node to register..."
-          sleep 10
-        done
-        
-        # Remove startup taint once node is ready
-        kubectl --kubeconfig=/etc/kubernetes/kubelet.conf taint node $(hostname) node-initializing- || true
-        echo "Node initialization complete"
-      path: /opt/tgp/node-setup.sh
-      permissions: 0755
-  systemd:
-    units:
-      - name: tgp-node-setup.service
-        enabled: true
-        contents: |
-          [Unit]
-          Description=TGP Node Setup
-          After=kubelet.service
-          Wants=kubelet.service
-          
-          [Service]
-          Type=oneshot
-          ExecStart=/opt/tgp/node-setup.sh
-          RemainAfterExit=true
-          
-          [Install]
-          WantedBy=multi-user.target
-  files:
-    - content: |
-        #!/bin/bash
-        # TGP Node Setup - GPU Operator will handle GPU drivers
-        echo "TGP node setup complete for pool: {{.NodePoolName}}"
-        
-        # Wait for kubelet to be ready, then remove startup taint
-        while ! kubectl --kubeconfig=/etc/kubernetes/kubelet.conf get nodes $(hostname) > /dev/null 2>&1; do
-          echo "Waiting for node to register..."
-          sleep 10
-        done
-        
-        # Remove startup taint once node is ready
-        kubectl --kubeconfig=/etc/kubernetes/kubelet.conf taint node $(hostname) node-initializing- || true
-        echo "Node initialization complete"
-      path: /opt/tgp/node-setup.sh
-      permissions: 0755
-  systemd:
-    units:
-      - name: tgp-node-setup.service
-        enabled: true
-        contents: |
-          [Unit]
-          Description=TGP Node Setup
-          After=kubelet.service
-          Wants=kubelet.service
-          
-          [Service]
-          Type=oneshot
-          ExecStart=/opt/tgp/node-setup.sh
-          RemainAfterExit=true
-          
-          [Install]
-          WantedBy=multi-user.target
 cluster:
   id: {{.ClusterID}}
   secret: {{.ClusterSecret}}
@@ -798,8 +1409,7 @@ cluster:
     enabled: true
     registries:
       kubernetes:
-        disabled: false
-        endpoint: {{.ControlPlaneEndpoint}}`
+        disabled: false`
 }
 
 // buildTemplateVariables creates a map of variables for template substitution
@@ -824,7 +1434,8 @@ func (r *GPUNodePoolReconciler) getProviderMachineConfig(ctx context.Context, no
 // getImageForProvider returns the image URL for a specific provider using Image Factory
 func (r *GPUNodePoolReconciler) getImageForProvider(ctx context.Context, provider string) (string, error) {
 	// Use Image Factory for dynamic generation
-	if len(r.Config.Talos.Extensions) > 0 && r.Config.Talos.Version != "" {
+	talos := config.Current(r.Config).Talos
+	if len(talos.Extensions) > 0 && talos.Version != "" {
 		// Map provider names to Image Factory platforms
 		var platform imagefactory.Platform
 		switch provider {
@@ -832,16 +1443,22 @@ func (r *GPUNodePoolReconciler) getImageForProvider(ctx context.Context, provide
 			platform = imagefactory.PlatformVultr
 		case "gcp":
 			platform = imagefactory.PlatformGCP
+		case "aws":
+			platform = imagefactory.PlatformAWS
+		case "azure":
+			platform = imagefactory.PlatformAzure
 		case "digitalocean":
 			platform = imagefactory.PlatformDigitalOcean
+		case "oci":
+			platform = imagefactory.PlatformOracle
 		default:
 			return "", fmt.Errorf("unsupported provider for Image Factory: %s", provider)
 		}
 
-		return r.ImageFactory.GenerateImageForExtensions(ctx, r.Config.Talos.Extensions, r.Config.Talos.Version, platform)
+		return r.ImageFactory.GenerateImageForExtensions(ctx, talos.Extensions, talos.Version, platform)
 	}
 
-	return "", fmt.Errorf("missing required Talos configuration: version=%q extensions=%v", r.Config.Talos.Version, r.Config.Talos.Extensions)
+	return "", fmt.Errorf("missing required Talos configuration: version=%q extensions=%v", talos.Version, talos.Extensions)
 }
 
 func (r *GPUNodePoolReconciler) buildTemplateVariables(ctx context.Context, nodePool *tgpv1.GPUNodePool, nodeClass *tgpv1.GPUNodeClass, providerName string) (map[string]interface{}, error) {
@@ -854,6 +1471,13 @@ func (r *GPUNodePoolReconciler) buildTemplateVariables(ctx context.Context, node
 		return nil, fmt.Errorf("failed to get image for provider %s: %w", providerName, err)
 	}
 
+	// Generate a fresh Tailscale auth key for every node
+	tailscaleAuthKey, err := r.tailscaleAuthKey(ctx, nodePool, nodeClass)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Tailscale auth key: %w", err)
+	}
+	acceptRoutes := nodeClass.Spec.TailscaleConfig != nil && nodeClass.Spec.TailscaleConfig.AcceptRoutes
+
 	// Build node labels
 	nodeLabels := make(map[string]string)
 	if nodePool.Spec.Template.Metadata != nil && nodePool.Spec.Template.Metadata.Labels != nil {
@@ -863,6 +1487,7 @@ func (r *GPUNodePoolReconciler) buildTemplateVariables(ctx context.Context, node
 	}
 	// Add TGP-specific labels
 	nodeLabels["tgp.io/nodepool"] = nodePool.Name
+	nodeLabels[tgpv1.NodeLabelNodePoolNamespace] = nodePool.Namespace
 	nodeLabels["tgp.io/provisioned"] = "true"
 	nodeLabels["node.kubernetes.io/instance-type"] = "gpu"
 
@@ -879,6 +1504,10 @@ func (r *GPUNodePoolReconciler) buildTemplateVariables(ctx context.Context, node
 		"TalosImage":           talosImage,
 		"KubeletImage":         getKubeletImage(nodeClass),
 
+		// Tailscale configuration
+		"TailscaleAuthKey":      tailscaleAuthKey,
+		"TailscaleAcceptRoutes": acceptRoutes,
+
 		// Node configuration
 		"NodePoolName": nodePool.Name,
 		"NodeLabels":   nodeLabels,
@@ -917,30 +1546,30 @@ func (r *GPUNodePoolReconciler) applyTemplate(tmplStr string, vars map[string]in
 }
 
 // createKubernetesNode creates a Kubernetes Node object for the provisioned instance
-func (r *GPUNodePoolReconciler) createKubernetesNode(ctx context.Context, nodePool *tgpv1.GPUNodePool, instance *providers.GPUInstance, provider *tgpv1.ProviderConfig, log logr.Logger) error {
+func (r *GPUNodePoolReconciler) createKubernetesNode(ctx context.Context, nodePool *tgpv1.GPUNodePool, requirement *GPURequirement, instance *providers.GPUInstance, tags map[string]string, provider *tgpv1.ProviderConfig, log logr.Logger) error {
 	// Generate node name
-	nodeName := fmt.Sprintf("tgp-%s-%s", nodePool.Name, instance.ID[:8])
+	nodeName := fmt.Sprintf("tgp-%s-%s", nodePool.Name, nodeNameSuffix(instance.ID))
+
+	// Use the same labels the scheduling simulation evaluated. The label identifies the
+	// instance for selectors; the annotation holds its full ID.
+	labels := buildNodeLabels(nodePool, requirement, provider.Name, instance.IsSpot)
+	labels["tgp.io/instance-id"] = instanceIDLabelValue(instance.ID)
 
 	// Create Node object
 	node := &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: nodeName,
-			Labels: map[string]string{
-				"tgp.io/nodepool":                  nodePool.Name,
-				"tgp.io/instance-id":               instance.ID,
-				"tgp.io/provider":                  provider.Name,
-				"kubernetes.io/arch":               "amd64",
-				"kubernetes.io/os":                 "linux",
-				"node.kubernetes.io/instance-type": "gpu",
-			},
+			Name:   nodeName,
+			Labels: labels,
 			Annotations: map[string]string{
-				"tgp.io/created-at":  instance.CreatedAt.Format(time.RFC3339),
-				"tgp.io/instance-id": instance.ID,
-				"tgp.io/provider":    provider.Name,
+				"tgp.io/created-at":        instance.CreatedAt.Format(time.RFC3339),
+				"tgp.io/instance-id":       instance.ID,
+				"tgp.io/provider":          provider.Name,
+				AnnotationExpectedGPUCount: strconv.Itoa(requirement.GPUCount),
+				AnnotationAwaitingReady:    "true",
 			},
 		},
 		Spec: corev1.NodeSpec{
-			// Node will be initially unschedulable until it's ready
+			// Node will be initially unschedulable until it's ready; see reconcileNodeReadiness
 			Unschedulable: true,
 		},
 		Status: corev1.NodeStatus{
@@ -967,13 +1596,15 @@ func (r *GPUNodePoolReconciler) createKubernetesNode(ctx context.Context, nodePo
 		},
 	}
 
-	// Apply template labels and taints
+	if requirement.RequestedGPUType != "" {
+		node.Annotations[AnnotationRequestedGPUType] = requirement.RequestedGPUType
+	}
+	if r.OperatorVersion != "" {
+		node.Annotations[AnnotationOperatorVersion] = r.OperatorVersion
+	}
+
+	// Apply template annotations
 	if nodePool.Spec.Template.Metadata != nil {
-		if nodePool.Spec.Template.Metadata.Labels != nil {
-			for k, v := range nodePool.Spec.Template.Metadata.Labels {
-				node.Labels[k] = v
-			}
-		}
 		if nodePool.Spec.Template.Metadata.Annotations != nil {
 			for k, v := range nodePool.Spec.Template.Metadata.Annotations {
 				node.Annotations[k] = v
@@ -981,6 +1612,42 @@ func (r *GPUNodePoolReconciler) createKubernetesNode(ctx context.Context, nodePo
 		}
 	}
 
+	// Record the tags the instance was launched with, and the template labels the node was
+	// created with, so later template and class changes can be reconciled
+	appliedTags, err := encodeTags(tags)
+	if err != nil {
+		return err
+	}
+	node.Annotations[AnnotationAppliedTags] = appliedTags
+	appliedLabels, err := encodeTags(templateLabels(nodePool))
+	if err != nil {
+		return err
+	}
+	node.Annotations[AnnotationAppliedLabels] = appliedLabels
+
+	// Record the data disks to attach once the instance is running; see reconcileDataDisks
+	if disks := dataDisksForPool(nodePool); requirement.AttachDataDisksAfterLaunch && len(disks) > 0 {
+		pending, err := encodeDataDisks(disks)
+		if err != nil {
+			return err
+		}
+		node.Annotations[AnnotationPendingDataDisks] = pending
+	}
+
+	// Record the account so the instance can be managed after the pool changes
+	if nodePool.Spec.Account != "" {
+		node.Annotations[AnnotationAccount] = nodePool.Spec.Account
+	}
+
+	// Record the estimated spot saving so it can be reported when the node is removed, and the
+	// price so the node counts towards the node class cost limit
+	if instance.IsSpot && requirement.SpotSavings > 0 {
+		node.Annotations[AnnotationSpotHourlySavings] = strconv.FormatFloat(requirement.SpotSavings, 'f', 4, 64)
+	}
+	if requirement.HourlyPrice > 0 {
+		node.Annotations[AnnotationHourlyPrice] = strconv.FormatFloat(requirement.HourlyPrice, 'f', 4, 64)
+	}
+
 	// Apply taints from template
 	if len(nodePool.Spec.Template.Spec.Taints) > 0 {
 		node.Spec.Taints = append(node.Spec.Taints, nodePool.Spec.Template.Spec.Taints...)
@@ -991,47 +1658,112 @@ func (r *GPUNodePoolReconciler) createKubernetesNode(ctx context.Context, nodePo
 		return fmt.Errorf("failed to set controller reference: %w", err)
 	}
 
-	// Create the node
-	if err := r.Create(ctx, node); err != nil {
+	// Create the node, retrying with a freshly suffixed name if a concurrent launch took it
+	err = r.Create(ctx, node)
+	for attempt := 0; errors.IsAlreadyExists(err) && attempt < config.Current(r.Config).NameCollisionRetries(); attempt++ {
+		log.V(1).Info("Node name already taken, retrying with a new suffix", "nodeName", node.Name)
+		node.Name = fmt.Sprintf("%s-%s", nodeName, utilrand.String(5))
+		err = r.Create(ctx, node)
+	}
+	if err != nil {
 		return fmt.Errorf("failed to create Kubernetes node: %w", err)
 	}
 
-	log.Info("Kubernetes node created", "nodeName", nodeName, "instanceID", instance.ID)
+	log.Info("Kubernetes node created", "nodeName", node.Name, "instanceID", instance.ID)
 	return nil
 }
 
-// cleanupPoolNodes drains and deletes all nodes created by this GPUNodePool
-func (r *GPUNodePoolReconciler) cleanupPoolNodes(ctx context.Context, nodePool *tgpv1.GPUNodePool, log logr.Logger) error {
+// cleanupPoolNodes drains and deletes all nodes created by this GPUNodePool, terminating their
+// instances and those of tracked instances that have no node yet. It returns the spot savings
+// realized by the tracked instances it stopped tracking.
+func (r *GPUNodePoolReconciler) cleanupPoolNodes(ctx context.Context, nodePool *tgpv1.GPUNodePool, log logr.Logger) ([]spotSavings, error) {
 	// Find all nodes that belong to this pool
 	var nodes corev1.NodeList
-	if err := r.List(ctx, &nodes, client.MatchingLabels{
-		"tgp.io/nodepool": nodePool.Name,
-	}); err != nil {
-		return fmt.Errorf("failed to list nodes for pool %s: %w", nodePool.Name, err)
+	if err := r.List(ctx, &nodes, poolNodeSelector(nodePool)); err != nil {
+		return nil, fmt.Errorf("failed to list nodes for pool %s: %w", nodePool.Name, err)
 	}
 
-	if len(nodes.Items) == 0 {
+	// Instances launched without a node yet have nothing to drain but still need terminating
+	unregistered := unregisteredInstances(nodePool, nodes.Items)
+	if len(nodes.Items) == 0 && len(unregistered) == 0 {
 		log.Info("No nodes found for cleanup")
-		return nil
+		return nil, nil
 	}
 
-	log.Info("Found nodes to clean up", "count", len(nodes.Items))
+	log.Info("Found nodes to clean up", "count", len(nodes.Items), "unregisteredInstances", len(unregistered))
 
-	// Process each node for cleanup
-	for _, node := range nodes.Items {
-		if err := r.cleanupNode(ctx, &node, log); err != nil {
+	// Drain every node first so their instances can be terminated together
+	var drained []*corev1.Node
+	draining := 0
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if err := r.cordonAndDrainNode(ctx, node, config.Current(r.Config).DrainTimeout(), log); isDrainPending(err) {
+			draining++
+			continue
+		} else if err != nil {
 			log.Error(err, "Failed to cleanup node", "node", node.Name)
 			// Continue with other nodes even if one fails
+			continue
 		}
+		drained = append(drained, node)
 	}
 
-	return nil
+	batches := groupNodesForTermination(drained)
+	for _, tracked := range unregistered {
+		batches.add(tracked.Provider, nodePool.Spec.Account, tracked.InstanceID, "")
+	}
+
+	// Nodes whose instances could not be terminated are kept so pool deletion can retry them
+	failed := r.terminatePoolInstances(ctx, nodePool, batches, log)
+	for _, node := range drained {
+		if instanceID, _ := nodeInstance(node); failed[instanceID] {
+			continue
+		}
+		if err := r.deleteNode(ctx, node, log); err != nil {
+			log.Error(err, "Failed to cleanup node", "node", node.Name)
+		}
+	}
+
+	now := time.Now()
+	var realized []spotSavings
+	for _, tracked := range unregistered {
+		if !failed[tracked.InstanceID] {
+			realized = append(realized, realizeSpotSavings(nodePool, tracked, now))
+			untrackInstance(nodePool, tracked.InstanceID)
+		}
+	}
+
+	if len(failed) > 0 {
+		return realized, fmt.Errorf("failed to terminate %d instances", len(failed))
+	}
+	if draining > 0 {
+		return realized, fmt.Errorf("%w on %d nodes", errDrainPending, draining)
+	}
+	return realized, nil
 }
 
-// cleanupNode handles the cleanup of a single node
-func (r *GPUNodePoolReconciler) cleanupNode(ctx context.Context, node *corev1.Node, log logr.Logger) error {
+// cleanupNode drains a single node, terminates the instance backing it and deletes it.
+// The node is kept when termination fails so the instance ID is not lost and can be retried.
+func (r *GPUNodePoolReconciler) cleanupNode(ctx context.Context, node *corev1.Node, credentialsNamespace string, reason tgpv1.TerminationReason, log logr.Logger) error {
 	log.Info("Cleaning up node", "node", node.Name)
 
+	if err := r.cordonAndDrainNode(ctx, node, config.Current(r.Config).DrainTimeout(), log); err != nil {
+		return err
+	}
+
+	if instanceID, providerName := nodeInstance(node); instanceID == "" || providerName == "" {
+		log.Info("Node does not record its instance, skipping termination", "node", node.Name)
+	} else if err := r.terminateNodeInstance(ctx, node, credentialsNamespace, reason); err != nil {
+		return err
+	}
+
+	return r.deleteNode(ctx, node, log)
+}
+
+// cordonAndDrainNode cordons a node and evicts its pods ahead of removal, force-deleting
+// those still running once the drain timeout has passed. A zero timeout force-deletes them
+// right away, for nodes whose kubelet is gone.
+func (r *GPUNodePoolReconciler) cordonAndDrainNode(ctx context.Context, node *corev1.Node, drainTimeout time.Duration, log logr.Logger) error {
 	// First, cordon the node to prevent new pods from being scheduled
 	if !node.Spec.Unschedulable {
 		node.Spec.Unschedulable = true
@@ -1041,17 +1773,15 @@ func (r *GPUNodePoolReconciler) cleanupNode(ctx context.Context, node *corev1.No
 		log.Info("Cordoned node", "node", node.Name)
 	}
 
-	// Drain the node by deleting pods
-	if err := r.drainNode(ctx, node, log); err != nil {
+	if err := r.drainNode(ctx, node, drainTimeout, log); err != nil {
 		return fmt.Errorf("failed to drain node %s: %w", node.Name, err)
 	}
 
-	// TODO: Terminate the cloud instance
-	// This would involve:
-	// 1. Finding the provider that created this instance
-	// 2. Extracting the instance ID from node labels/annotations
-	// 3. Calling provider.TerminateInstance()
+	return nil
+}
 
+// deleteNode removes a drained node from Kubernetes
+func (r *GPUNodePoolReconciler) deleteNode(ctx context.Context, node *corev1.Node, log logr.Logger) error {
 	// Delete the node from Kubernetes
 	if err := r.Delete(ctx, node); err != nil {
 		return fmt.Errorf("failed to delete node %s: %w", node.Name, err)
@@ -1061,54 +1791,66 @@ func (r *GPUNodePoolReconciler) cleanupNode(ctx context.Context, node *corev1.No
 	return nil
 }
 
-// drainNode removes all pods from a node
-func (r *GPUNodePoolReconciler) drainNode(ctx context.Context, node *corev1.Node, log logr.Logger) error {
-	// List all pods and filter by node name
-	var pods corev1.PodList
-	if err := r.List(ctx, &pods); err != nil {
-		return fmt.Errorf("failed to list pods: %w", err)
+// terminateNodeInstance terminates the cloud instance backing a node using the provider recorded on it
+func (r *GPUNodePoolReconciler) terminateNodeInstance(ctx context.Context, node *corev1.Node, credentialsNamespace string, reason tgpv1.TerminationReason) error {
+	instanceID, providerName := nodeInstance(node)
+	if instanceID == "" || providerName == "" {
+		return fmt.Errorf("node %s does not record its instance ID and provider", node.Name)
 	}
-
-	// Filter pods running on this node
-	var nodePods []corev1.Pod
-	for _, pod := range pods.Items {
-		if pod.Spec.NodeName == node.Name {
-			nodePods = append(nodePods, pod)
-		}
+	if !config.Current(r.Config).FeatureEnabled(providerName, config.FeatureTerminate) {
+		return fmt.Errorf("terminate is disabled for provider %s", providerName)
 	}
 
-	if len(nodePods) == 0 {
-		log.Info("No pods to drain from node", "node", node.Name)
-		return nil
+	credentials, err := config.Current(r.Config).GetProviderCredentials(ctx, r.Client, providerName, credentialsNamespace)
+	if err != nil {
+		return fmt.Errorf("failed to get credentials for provider %s: %w", providerName, err)
 	}
 
-	log.Info("Draining pods from node", "node", node.Name, "podCount", len(nodePods))
+	providerClient, err := r.createProviderClient(providerName, credentials)
+	if err != nil {
+		return err
+	}
+	if err := providers.SelectAccount(providerClient, node.Annotations[AnnotationAccount]); err != nil {
+		return err
+	}
 
-	// Delete non-DaemonSet pods
-	for _, pod := range nodePods {
-		// Skip pods that are already terminating
-		if pod.DeletionTimestamp != nil {
-			continue
-		}
+	if err := providerClient.TerminateInstance(ctx, instanceID); err != nil {
+		return fmt.Errorf("failed to terminate instance %s: %w", instanceID, err)
+	}
 
-		// Skip DaemonSet pods (they will be handled by the DaemonSet controller)
-		if r.isDaemonSetPod(&pod) {
-			continue
-		}
+	r.recordTermination(nil, providerName, instanceID, node.Name, reason)
+	return nil
+}
 
-		// Skip static pods (controlled by kubelet)
-		if r.isStaticPod(&pod) {
-			continue
-		}
+// recordTermination counts a terminated instance by reason and, when the pool still exists,
+// records it as the pool's last termination
+func (r *GPUNodePoolReconciler) recordTermination(nodePool *tgpv1.GPUNodePool, providerName, instanceID, nodeName string, reason tgpv1.TerminationReason) {
+	r.Metrics.RecordInstanceTermination(providerName, string(reason))
+	if nodePool == nil {
+		return
+	}
 
-		log.Info("Deleting pod from node", "pod", pod.Name, "namespace", pod.Namespace, "node", node.Name)
-		if err := r.Delete(ctx, &pod); err != nil {
-			log.Error(err, "Failed to delete pod", "pod", pod.Name, "namespace", pod.Namespace)
-			// Continue with other pods
-		}
+	nodePool.Status.LastTermination = &tgpv1.InstanceTermination{
+		InstanceID: instanceID,
+		Provider:   providerName,
+		NodeName:   nodeName,
+		Reason:     reason,
+		Time:       metav1.Now(),
 	}
+}
 
-	return nil
+// nodeInstance returns the instance ID and provider recorded on a node. The annotation holds
+// the full ID; the label may be shortened to a valid label value.
+func nodeInstance(node *corev1.Node) (string, string) {
+	instanceID := node.Annotations["tgp.io/instance-id"]
+	if instanceID == "" {
+		instanceID = node.Labels["tgp.io/instance-id"]
+	}
+	providerName := node.Labels[tgpv1.NodeLabelProvider]
+	if providerName == "" {
+		providerName = node.Annotations["tgp.io/provider"]
+	}
+	return instanceID, providerName
 }
 
 // isDaemonSetPod checks if a pod is controlled by a DaemonSet
@@ -1139,8 +1881,13 @@ func (r *GPUNodePoolReconciler) isStaticPod(pod *corev1.Pod) bool {
 
 // SetupWithManager sets up the controller with the Manager
 func (r *GPUNodePoolReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&tgpv1.GPUNodePool{}).
-		Owns(&corev1.Node{}). // Watch nodes created by this controller
-		Complete(r)
+		Owns(&corev1.Node{}) // Watch nodes created by this controller
+	if r.ConfigChanges != nil {
+		// Reconcile everything against a reloaded configuration, e.g. a newly enabled provider
+		builder = builder.WatchesRawSource(source.Channel(r.ConfigChanges,
+			handler.EnqueueRequestsFromMapFunc(r.nodePoolsForConfigChange)))
+	}
+	return builder.Complete(r)
 }
@@ -2,18 +2,31 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-logr/logr"
+	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
 	"github.com/solanyn/tgp-operator/pkg/config"
 	"github.com/solanyn/tgp-operator/pkg/imagefactory"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+	"github.com/solanyn/tgp-operator/pkg/providers/gcp"
+	"github.com/solanyn/tgp-operator/pkg/providers/vultr"
 )
 
 func TestBuildUserDataScript(t *testing.T) {
@@ -26,7 +39,7 @@ func TestBuildUserDataScript(t *testing.T) {
 		validate    func(t *testing.T, result string)
 	}{
 		{
-			name: "default template with cluster credentials",
+			name: "default template without cluster credentials is rejected",
 			nodePool: &tgpv1.GPUNodePool{
 				ObjectMeta: metav1.ObjectMeta{Name: "test-pool"},
 				Spec: tgpv1.GPUNodePoolSpec{
@@ -58,25 +71,9 @@ func TestBuildUserDataScript(t *testing.T) {
 					},
 				},
 			},
-			validate: func(t *testing.T, result string) {
-				// Since template variables are now user-provided, verify basic template generation
-				if !contains(result, "{{.MachineToken}}") {
-					t.Error("machine token template variable not found")
-				}
-				if !contains(result, "{{.ClusterCA}}") {
-					t.Error("cluster CA template variable not found")
-				}
-				if !contains(result, "{{.ControlPlaneEndpoint}}") {
-					t.Error("control plane endpoint template variable not found")
-				}
-				// Verify node-specific configuration is still substituted
-				if !contains(result, "gpu-tier=high-end") {
-					t.Error("node labels not included")
-				}
-				if !contains(result, "gpu-node") {
-					t.Error("node taints not included")
-				}
-			},
+			// The default template leaves the cluster credentials to the user, so the rendered
+			// config cannot join a node and must be rejected before launch
+			expectError: true,
 		},
 		{
 			name: "custom template overrides default",
@@ -101,8 +98,8 @@ func TestBuildUserDataScript(t *testing.T) {
 				},
 			},
 			validate: func(t *testing.T, result string) {
-				if !contains(result, "{{.MachineToken}}") {
-					t.Error("custom template token variable not found")
+				if !contains(result, "token: abcdef.0123456789abcdef") {
+					t.Error("custom template token not found")
 				}
 				if !contains(result, "# Custom template for custom-pool") {
 					t.Error("custom template not used")
@@ -113,6 +110,37 @@ func TestBuildUserDataScript(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "extra files may contain template syntax",
+			nodePool: &tgpv1.GPUNodePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "custom-pool"},
+			},
+			nodeClass: &tgpv1.GPUNodeClass{
+				Spec: tgpv1.GPUNodeClassSpec{
+					TalosConfig: &tgpv1.TalosConfig{
+						MachineConfigSecretRef: &tgpv1.SecretKeyRef{
+							Name:      "custom-talos-config",
+							Key:       "machine-config",
+							Namespace: "default",
+						},
+						ExtraFiles: []tgpv1.TalosFile{
+							{Path: "/var/etc/alerts.tmpl", Content: "summary: {{ .Labels.instance }} is down"},
+						},
+					},
+				},
+			},
+			config: &config.OperatorConfig{
+				Talos: config.TalosDefaults{
+					Version:    "v1.11.0-beta.1",
+					Extensions: []string{"siderolabs/nvidia-container-toolkit-production"},
+				},
+			},
+			validate: func(t *testing.T, result string) {
+				if !contains(result, "{{ .Labels.instance }} is down") {
+					t.Error("extra file content not kept verbatim")
+				}
+			},
+		},
 		{
 			name: "malformed template returns error",
 			nodePool: &tgpv1.GPUNodePool{
@@ -134,6 +162,13 @@ func TestBuildUserDataScript(t *testing.T) {
 		},
 	}
 
+	// Serve schematics locally so the test does not depend on the public Image Factory
+	factory := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"test-schematic"}`))
+	}))
+	defer factory.Close()
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			scheme := runtime.NewScheme()
@@ -151,8 +186,11 @@ func TestBuildUserDataScript(t *testing.T) {
 					templateContent = `version: v1alpha1
 machine:
   type: worker
-  token: {{.MachineToken}}
-  # Custom template for {{.NodePoolName}}`
+  token: abcdef.0123456789abcdef
+  # Custom template for {{.NodePoolName}}
+cluster:
+  controlPlane:
+    endpoint: https://10.0.0.1:6443`
 				case "invalid-talos-config":
 					templateContent = `invalid template {{.InvalidField`
 				}
@@ -173,7 +211,7 @@ machine:
 				Client:       fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
 				Log:          logr.Discard(),
 				Config:       tt.config,
-				ImageFactory: imagefactory.NewClient(""),
+				ImageFactory: imagefactory.NewClient(factory.URL),
 			}
 
 			result, err := reconciler.buildUserDataScript(context.Background(), tt.nodePool, tt.nodeClass, "vultr")
@@ -238,3 +276,485 @@ FalseSection: should not appear
 		}
 	}
 }
+
+// talosMachineConfig is the part of the Talos v1alpha1 machine config schema the default template
+// uses. Decoding strictly into it rejects fields Talos does not know or that are misplaced.
+type talosMachineConfig struct {
+	Version string `yaml:"version"`
+	Debug   bool   `yaml:"debug"`
+	Persist bool   `yaml:"persist"`
+	Machine struct {
+		Type  string `yaml:"type"`
+		Token string `yaml:"token"`
+		CA    struct {
+			Crt string `yaml:"crt"`
+		} `yaml:"ca"`
+		CertSANs []string `yaml:"certSANs"`
+		Kubelet  struct {
+			Image      string   `yaml:"image"`
+			ClusterDNS []string `yaml:"clusterDNS"`
+			NodeIP     struct {
+				ValidSubnets []string `yaml:"validSubnets"`
+			} `yaml:"nodeIP"`
+			ExtraArgs map[string]string `yaml:"extraArgs"`
+		} `yaml:"kubelet"`
+		Kernel struct {
+			Modules []struct {
+				Name string `yaml:"name"`
+			} `yaml:"modules"`
+		} `yaml:"kernel"`
+		Install struct {
+			Disk       string `yaml:"disk"`
+			Image      string `yaml:"image"`
+			Bootloader bool   `yaml:"bootloader"`
+			Wipe       bool   `yaml:"wipe"`
+		} `yaml:"install"`
+		Features struct {
+			RBAC           bool `yaml:"rbac"`
+			StableHostname bool `yaml:"stableHostname"`
+		} `yaml:"features"`
+	} `yaml:"machine"`
+	Cluster struct {
+		ID           string `yaml:"id"`
+		Secret       string `yaml:"secret"`
+		ControlPlane struct {
+			Endpoint string `yaml:"endpoint"`
+		} `yaml:"controlPlane"`
+		ClusterName string `yaml:"clusterName"`
+		Network     struct {
+			DNSDomain      string   `yaml:"dnsDomain"`
+			PodSubnets     []string `yaml:"podSubnets"`
+			ServiceSubnets []string `yaml:"serviceSubnets"`
+		} `yaml:"network"`
+		Proxy struct {
+			Disabled bool `yaml:"disabled"`
+		} `yaml:"proxy"`
+		Discovery struct {
+			Enabled    bool `yaml:"enabled"`
+			Registries struct {
+				Kubernetes struct {
+					Disabled bool `yaml:"disabled"`
+				} `yaml:"kubernetes"`
+			} `yaml:"registries"`
+		} `yaml:"discovery"`
+	} `yaml:"cluster"`
+}
+
+// duplicateYAMLKeys returns the keys defined more than once in any mapping of the YAML node
+func duplicateYAMLKeys(node *yaml.Node) []string {
+	var duplicates []string
+	if node.Kind == yaml.MappingNode {
+		seen := make(map[string]bool)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if seen[key] {
+				duplicates = append(duplicates, fmt.Sprintf("%s (line %d)", key, node.Content[i].Line))
+			}
+			seen[key] = true
+		}
+	}
+	for _, child := range node.Content {
+		duplicates = append(duplicates, duplicateYAMLKeys(child)...)
+	}
+	return duplicates
+}
+
+func TestDefaultMachineConfigTemplate(t *testing.T) {
+	reconciler := &GPUNodePoolReconciler{}
+	vars := map[string]interface{}{
+		"MachineToken":         "abcdef.0123456789abcdef",
+		"ClusterCA":            "LS0tLS1CRUdJTi0tLS0t",
+		"ClusterID":            "cluster-id",
+		"ClusterSecret":        "cluster-secret",
+		"ControlPlaneEndpoint": "https://10.0.0.1:6443",
+		"ClusterName":          "gpu-cluster",
+		"TalosImage":           "factory.talos.dev/installer/abc:v1.11.0",
+		"KubeletImage":         "ghcr.io/siderolabs/kubelet:v1.31.1",
+		"NodePoolName":         "train",
+		"NodeLabels":           map[string]string{"tgp.io/nodepool": "train", "tgp.io/provisioned": "true", "gpu-tier": "high-end"},
+		"NodeTaints": []corev1.Taint{
+			{Key: "nvidia.com/gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+			{Key: "nvidia.com/gpu", Value: "true", Effect: corev1.TaintEffectNoExecute},
+		},
+	}
+
+	rendered, err := reconciler.applyTemplate(reconciler.getDefaultMachineConfigTemplate(), vars)
+	if err != nil {
+		t.Fatalf("failed to render the default template: %v", err)
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(rendered), &document); err != nil {
+		t.Fatalf("rendered config is not valid YAML: %v\n%s", err, rendered)
+	}
+	if duplicates := duplicateYAMLKeys(&document); len(duplicates) > 0 {
+		t.Errorf("rendered config has duplicate keys %v:\n%s", duplicates, rendered)
+	}
+
+	if err := validateMachineConfig(rendered); err != nil {
+		t.Errorf("rendered config failed validation: %v", err)
+	}
+
+	var machineConfig talosMachineConfig
+	decoder := yaml.NewDecoder(strings.NewReader(rendered))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&machineConfig); err != nil {
+		t.Fatalf("rendered config does not match the Talos machine config schema: %v\n%s", err, rendered)
+	}
+
+	if machineConfig.Version != "v1alpha1" || machineConfig.Machine.Type != "worker" ||
+		machineConfig.Machine.Token != vars["MachineToken"] || machineConfig.Cluster.ControlPlane.Endpoint != vars["ControlPlaneEndpoint"] {
+		t.Errorf("unexpected machine config %+v", machineConfig)
+	}
+	extraArgs := machineConfig.Machine.Kubelet.ExtraArgs
+	if want := "gpu-tier=high-end,tgp.io/nodepool=train,tgp.io/provisioned=true"; extraArgs["node-labels"] != want {
+		t.Errorf("node-labels = %q, want %q", extraArgs["node-labels"], want)
+	}
+	if want := "nvidia.com/gpu=true:NoSchedule,nvidia.com/gpu=true:NoExecute"; extraArgs["register-with-taints"] != want {
+		t.Errorf("register-with-taints = %q, want %q", extraArgs["register-with-taints"], want)
+	}
+}
+
+func TestChooseCapacityType(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        tgpv1.SpotPolicy
+		onDemandPrice float64
+		spotPrice     float64
+		expectPrice   float64
+		expectSpot    bool
+		expectOK      bool
+	}{
+		{"never ignores spot", tgpv1.SpotPolicyNever, 2.0, 0.5, 2.0, false, true},
+		{"never without on-demand", tgpv1.SpotPolicyNever, 0, 0.5, 0, false, false},
+		{"required uses spot", tgpv1.SpotPolicyRequired, 2.0, 1.5, 1.5, true, true},
+		{"required without spot", tgpv1.SpotPolicyRequired, 2.0, 0, 0, false, false},
+		{"preferred picks cheaper spot", tgpv1.SpotPolicyPreferred, 2.0, 1.0, 1.2, true, true},
+		{"preferred premium favours on-demand", tgpv1.SpotPolicyPreferred, 2.0, 1.8, 2.0, false, true},
+		{"preferred falls back to on-demand", tgpv1.SpotPolicyPreferred, 2.0, 0, 2.0, false, true},
+		{"preferred spot only", tgpv1.SpotPolicyPreferred, 0, 1.0, 1.2, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, spot, ok := chooseCapacityType(tt.policy, 0.2, tt.onDemandPrice, tt.spotPrice)
+			if ok != tt.expectOK {
+				t.Fatalf("expected ok=%v, got %v", tt.expectOK, ok)
+			}
+			if spot != tt.expectSpot {
+				t.Errorf("expected spot=%v, got %v", tt.expectSpot, spot)
+			}
+			if diff := price - tt.expectPrice; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("expected price %f, got %f", tt.expectPrice, price)
+			}
+		})
+	}
+}
+
+func TestSpotPolicyDefaults(t *testing.T) {
+	pool := &tgpv1.GPUNodePool{}
+	if got := spotPolicyForPool(pool); got != tgpv1.SpotPolicyNever {
+		t.Errorf("expected default policy Never, got %s", got)
+	}
+	if got := spotPremiumForPool(pool); got != 0.2 {
+		t.Errorf("expected default premium 0.2, got %f", got)
+	}
+
+	premium := int32(50)
+	pool.Spec.Spot = tgpv1.SpotPolicyPreferred
+	pool.Spec.SpotInterruptionPremium = &premium
+	if got := spotPolicyForPool(pool); got != tgpv1.SpotPolicyPreferred {
+		t.Errorf("expected policy Preferred, got %s", got)
+	}
+	if got := spotPremiumForPool(pool); got != 0.5 {
+		t.Errorf("expected premium 0.5, got %f", got)
+	}
+}
+
+func TestSelectPoolAccount(t *testing.T) {
+	vultrClient, err := vultr.NewClient("test-key")
+	if err != nil {
+		t.Fatalf("Failed to create Vultr client: %v", err)
+	}
+
+	gcpConfig := &tgpv1.ProviderConfig{Name: "gcp", AllowedAccounts: []string{"team-ml-prod"}}
+	vultrConfig := &tgpv1.ProviderConfig{Name: "vultr", AllowedAccounts: []string{"team-ml-prod"}}
+
+	tests := []struct {
+		name           string
+		account        string
+		providerConfig *tgpv1.ProviderConfig
+		providerClient providers.ProviderClient
+		expectError    bool
+	}{
+		{"no account uses credentials default", "", gcpConfig, gcp.NewClient("{}"), false},
+		{"allowed account is selected", "team-ml-prod", gcpConfig, gcp.NewClient("{}"), false},
+		{"account not in allow list is rejected", "other-project", gcpConfig, gcp.NewClient("{}"), true},
+		{"provider without account selection is rejected", "team-ml-prod", vultrConfig, vultrClient, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodePool := &tgpv1.GPUNodePool{Spec: tgpv1.GPUNodePoolSpec{Account: tt.account}}
+			err := selectPoolAccount(nodePool, tt.providerConfig, tt.providerClient)
+			if (err != nil) != tt.expectError {
+				t.Errorf("selectPoolAccount() error = %v, expectError %v", err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestCreateKubernetesNodeNameCollisions(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = tgpv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	nodePool := &tgpv1.GPUNodePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "burst", UID: "pool-uid"},
+	}
+	provider := &tgpv1.ProviderConfig{Name: "gcp"}
+	requirement := &GPURequirement{GPUType: "NVIDIA_L4", GPUCount: 1}
+
+	// Instance IDs sharing their first eight characters collide
+	launch := func(r *GPUNodePoolReconciler, count int) error {
+		for i := 0; i < count; i++ {
+			instance := &providers.GPUInstance{
+				ID:        fmt.Sprintf("tgp-burst-%d", i),
+				CreatedAt: time.Now(),
+			}
+			if err := r.createKubernetesNode(context.Background(), nodePool, requirement, instance, nil, provider, logr.Discard()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	t.Run("colliding names are retried with a fresh suffix", func(t *testing.T) {
+		r := &GPUNodePoolReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+			Scheme: scheme,
+		}
+		if err := launch(r, 5); err != nil {
+			t.Fatalf("createKubernetesNode() error = %v", err)
+		}
+
+		var nodes corev1.NodeList
+		if err := r.List(context.Background(), &nodes); err != nil {
+			t.Fatalf("failed to list nodes: %v", err)
+		}
+		if len(nodes.Items) != 5 {
+			t.Errorf("expected 5 nodes, got %d", len(nodes.Items))
+		}
+	})
+
+	t.Run("collisions fail once retries are exhausted", func(t *testing.T) {
+		retries := 0
+		r := &GPUNodePoolReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+			Scheme: scheme,
+			Config: &config.OperatorConfig{Provisioning: config.ProvisioningConfig{NameCollisionRetries: &retries}},
+		}
+		err := launch(r, 2)
+		if err == nil || !strings.Contains(err.Error(), "already exists") {
+			t.Errorf("expected a name collision error without retries, got: %v", err)
+		}
+	})
+}
+
+func TestLaunchTags(t *testing.T) {
+	nodeClass := &tgpv1.GPUNodeClass{Spec: tgpv1.GPUNodeClassSpec{Tags: map[string]string{"team": "ml"}}}
+	nodePool := &tgpv1.GPUNodePool{Spec: tgpv1.GPUNodePoolSpec{Template: tgpv1.NodePoolTemplate{
+		Metadata: &tgpv1.NodeMetadata{Labels: map[string]string{"project": "llm", "team": "research"}},
+	}}}
+
+	r := &GPUNodePoolReconciler{OperatorVersion: "v1.4.0"}
+	tags := r.launchTags(nodePool, nodeClass)
+	if tags["team"] != "ml" || tags["project"] != "llm" || tags[TagOperatorVersion] != "v1.4.0" {
+		t.Errorf("launchTags() = %v, want template labels overridden by class tags, plus the operator version", tags)
+	}
+	if _, modified := nodeClass.Spec.Tags[TagOperatorVersion]; modified {
+		t.Error("launchTags() must not modify the class tags")
+	}
+
+	unversioned := &GPUNodePoolReconciler{}
+	if tags := unversioned.launchTags(&tgpv1.GPUNodePool{}, nodeClass); len(tags) != 1 {
+		t.Errorf("launchTags() = %v, want only class tags without an operator version", tags)
+	}
+}
+
+func TestPoolSupportsRequirement(t *testing.T) {
+	pool := func(requirements ...tgpv1.NodeSelectorRequirement) *tgpv1.GPUNodePool {
+		return &tgpv1.GPUNodePool{Spec: tgpv1.GPUNodePoolSpec{
+			Template: tgpv1.NodePoolTemplate{Spec: tgpv1.NodeSpec{Requirements: requirements}},
+		}}
+	}
+	gpuTypes := func(op tgpv1.NodeSelectorOperator, values ...string) tgpv1.NodeSelectorRequirement {
+		return tgpv1.NodeSelectorRequirement{Key: tgpv1.NodeLabelGPUType, Operator: op, Values: values}
+	}
+	vram := func(op tgpv1.NodeSelectorOperator, value string) tgpv1.NodeSelectorRequirement {
+		return tgpv1.NodeSelectorRequirement{Key: "tgp.io/gpu-memory-gib", Operator: op, Values: []string{value}}
+	}
+
+	tests := []struct {
+		name     string
+		nodePool *tgpv1.GPUNodePool
+		key      string
+		value    string
+		want     bool
+	}{
+		{"In matches listed value", pool(gpuTypes(tgpv1.NodeSelectorOpIn, "A100", "H100")), tgpv1.NodeLabelGPUType, "H100", true},
+		{"In rejects unlisted value", pool(gpuTypes(tgpv1.NodeSelectorOpIn, "A100", "H100")), tgpv1.NodeLabelGPUType, "L4", false},
+		{"NotIn allows unlisted value", pool(gpuTypes(tgpv1.NodeSelectorOpNotIn, "L4")), tgpv1.NodeLabelGPUType, "H100", true},
+		{"NotIn rejects listed value", pool(gpuTypes(tgpv1.NodeSelectorOpNotIn, "L4")), tgpv1.NodeLabelGPUType, "L4", false},
+		{"Exists allows any value", pool(gpuTypes(tgpv1.NodeSelectorOpExists)), tgpv1.NodeLabelGPUType, "A100", true},
+		{"DoesNotExist rejects any value", pool(gpuTypes(tgpv1.NodeSelectorOpDoesNotExist)), tgpv1.NodeLabelGPUType, "A100", false},
+		{"Gt allows larger value", pool(vram(tgpv1.NodeSelectorOpGt, "40")), "tgp.io/gpu-memory-gib", "80", true},
+		{"Gt rejects smaller value", pool(vram(tgpv1.NodeSelectorOpGt, "40")), "tgp.io/gpu-memory-gib", "24", false},
+		{"Lt allows smaller value", pool(vram(tgpv1.NodeSelectorOpLt, "48")), "tgp.io/gpu-memory-gib", "24", true},
+		{"Lt rejects larger value", pool(vram(tgpv1.NodeSelectorOpLt, "48")), "tgp.io/gpu-memory-gib", "80", false},
+		{"Gt rejects non-numeric value", pool(vram(tgpv1.NodeSelectorOpGt, "40")), "tgp.io/gpu-memory-gib", "large", false},
+		{"every requirement on the key must hold", pool(vram(tgpv1.NodeSelectorOpGt, "40"), vram(tgpv1.NodeSelectorOpLt, "64")), "tgp.io/gpu-memory-gib", "80", false},
+		{"unconstrained key", pool(gpuTypes(tgpv1.NodeSelectorOpIn, "H100")), tgpv1.NodeLabelRegion, "ewr", false},
+		{
+			"template label must match exactly",
+			&tgpv1.GPUNodePool{Spec: tgpv1.GPUNodePoolSpec{Template: tgpv1.NodePoolTemplate{
+				Metadata: &tgpv1.NodeMetadata{Labels: map[string]string{"team": "ml"}},
+			}}},
+			"team", "ml", true,
+		},
+	}
+
+	r := &GPUNodePoolReconciler{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.poolSupportsRequirement(tt.nodePool, tt.key, tt.value); got != tt.want {
+				t.Errorf("poolSupportsRequirement(%s=%s) = %v, want %v", tt.key, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileMaintainsNodesWhileProvisioningBacksOff(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = tgpv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	lastFailure := metav1.NewTime(time.Now().Add(-time.Second))
+	nodePool := &tgpv1.GPUNodePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pool", Namespace: "default", UID: "pool-uid"},
+		Spec:       tgpv1.GPUNodePoolSpec{NodeClassRef: tgpv1.NodeClassReference{Name: "test-class"}},
+		Status: tgpv1.GPUNodePoolStatus{
+			ProvisioningAttempts:    3,
+			LastProvisioningFailure: &lastFailure,
+		},
+	}
+	nodeClass := &tgpv1.GPUNodeClass{ObjectMeta: metav1.ObjectMeta{Name: "test-class"}}
+	joined := launchedNode("joined", corev1.ConditionTrue, "10.0.0.1")
+
+	client := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(nodePool, nodeClass, joined).WithStatusSubresource(nodePool).Build()
+	r := &GPUNodePoolReconciler{Client: client, Scheme: scheme, Log: logr.Discard()}
+
+	ctx := context.Background()
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "test-pool", Namespace: "default"}})
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	backoff := provisioningBackoffRemaining(nodePool, time.Now())
+	if result.RequeueAfter <= 0 || result.RequeueAfter > backoff+time.Second {
+		t.Errorf("expected requeue within the %s backoff, got %s", backoff, result.RequeueAfter)
+	}
+
+	var node corev1.Node
+	if err := client.Get(ctx, types.NamespacedName{Name: "joined"}, &node); err != nil {
+		t.Fatalf("failed to get node: %v", err)
+	}
+	if node.Spec.Unschedulable {
+		t.Error("expected the joined node to be made schedulable while provisioning backs off")
+	}
+
+	var updated tgpv1.GPUNodePool
+	if err := client.Get(ctx, types.NamespacedName{Name: "test-pool", Namespace: "default"}, &updated); err != nil {
+		t.Fatalf("failed to get pool: %v", err)
+	}
+	if updated.Status.ProvisioningAttempts != 3 {
+		t.Errorf("expected backoff to leave the attempt count at 3, got %d", updated.Status.ProvisioningAttempts)
+	}
+}
+
+func TestReconcileHoldsBackMaxLifetimeWhilePodsArePending(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = tgpv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	tests := []struct {
+		name       string
+		pendingPod bool
+		wantKept   bool
+	}{
+		{name: "aged node is recycled", wantKept: false},
+		{name: "held back while a pending pod cannot be provisioned", pendingPod: true, wantKept: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodePool := &tgpv1.GPUNodePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-pool", Namespace: "default"},
+				Spec: tgpv1.GPUNodePoolSpec{
+					NodeClassRef: tgpv1.NodeClassReference{Name: "test-class"},
+					Template: tgpv1.NodePoolTemplate{Spec: tgpv1.NodeSpec{
+						MaxLifetime: &metav1.Duration{Duration: 24 * time.Hour},
+					}},
+				},
+			}
+			// The node class enables no providers, so no capacity can be provisioned
+			nodeClass := &tgpv1.GPUNodeClass{ObjectMeta: metav1.ObjectMeta{Name: "test-class"}}
+			aged := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "aged",
+					Labels:      map[string]string{"tgp.io/nodepool": "test-pool", tgpv1.NodeLabelNodePoolNamespace: "default"},
+					Annotations: map[string]string{"tgp.io/created-at": time.Now().Add(-25 * time.Hour).Format(time.RFC3339)},
+				},
+			}
+			objects := []client.Object{nodePool, nodeClass, aged}
+			if tt.pendingPod {
+				objects = append(objects, &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "training", Namespace: "default"},
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{
+						Name: "trainer",
+						Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+							"nvidia.com/gpu": resource.MustParse("1"),
+						}},
+					}}},
+					Status: corev1.PodStatus{Phase: corev1.PodPending},
+				})
+			}
+
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(objects...).WithStatusSubresource(nodePool).Build()
+			r := &GPUNodePoolReconciler{Client: fakeClient, Scheme: scheme, Log: logr.Discard()}
+
+			ctx := context.Background()
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "test-pool", Namespace: "default"}}); err != nil {
+				t.Fatalf("Reconcile failed: %v", err)
+			}
+
+			var node corev1.Node
+			err := fakeClient.Get(ctx, types.NamespacedName{Name: "aged"}, &node)
+			if tt.wantKept && err != nil {
+				t.Errorf("expected the aged node to be kept: %v", err)
+			}
+			if !tt.wantKept && !apierrors.IsNotFound(err) {
+				t.Errorf("expected the aged node to be recycled, got: %v", err)
+			}
+
+			var updated tgpv1.GPUNodePool
+			if err := fakeClient.Get(ctx, types.NamespacedName{Name: "test-pool", Namespace: "default"}, &updated); err != nil {
+				t.Fatalf("failed to get pool: %v", err)
+			}
+			if pending := updated.Status.PendingSince != nil; pending != tt.pendingPod {
+				t.Errorf("expected pool pending %v, got %v", tt.pendingPod, pending)
+			}
+		})
+	}
+}
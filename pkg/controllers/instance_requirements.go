@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// gpuTypeAllowed reports whether the class instance requirements allow the GPU type. A class
+// that lists no GPU types allows any.
+func gpuTypeAllowed(requirements *tgpv1.InstanceRequirements, gpuType string) bool {
+	if requirements == nil || len(requirements.GPUTypes) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(requirements.GPUTypes, func(allowed string) bool {
+		return strings.EqualFold(allowed, gpuType)
+	})
+}
+
+// regionAllowed reports whether the class instance requirements allow the region. A class
+// that lists no regions allows any, including leaving the region to the provider.
+func regionAllowed(requirements *tgpv1.InstanceRequirements, region string) bool {
+	if requirements == nil || len(requirements.Regions) == 0 {
+		return true
+	}
+	return slices.Contains(requirements.Regions, region)
+}
+
+// spotAllowed reports whether the class instance requirements allow spot instances
+func spotAllowed(requirements *tgpv1.InstanceRequirements) bool {
+	return requirements == nil || requirements.SpotAllowed == nil || *requirements.SpotAllowed
+}
+
+// instanceRequirementsReason returns why the class instance requirements rule out the
+// requirement's GPU type, region or capacity type, or "" if they allow it
+func instanceRequirementsReason(requirements *tgpv1.InstanceRequirements, requirement *GPURequirement) string {
+	if !gpuTypeAllowed(requirements, requirement.GPUType) {
+		return fmt.Sprintf("GPU type %s is not in the node class's allowed GPU types %v", requirement.GPUType, requirements.GPUTypes)
+	}
+	if !regionAllowed(requirements, requirement.Region) {
+		if requirement.Region == "" {
+			return fmt.Sprintf("a region must be chosen from the node class's allowed regions %v", requirements.Regions)
+		}
+		return fmt.Sprintf("region %s is not in the node class's allowed regions %v", requirement.Region, requirements.Regions)
+	}
+	if requirement.Spot && !spotAllowed(requirements) {
+		return "the node class does not allow spot instances"
+	}
+	return ""
+}
+
+// minInstanceSize returns the minimum vCPUs and system memory in GiB the class instance
+// requirements ask for, 0 where unrestricted
+func minInstanceSize(requirements *tgpv1.InstanceRequirements) (int, int64) {
+	if requirements == nil {
+		return 0, 0
+	}
+	vcpus, memoryGiB := 0, int64(0)
+	if requirements.MinVCPU != nil {
+		vcpus = int(*requirements.MinVCPU)
+	}
+	if requirements.MinMemoryGiB != nil {
+		memoryGiB = int64(*requirements.MinMemoryGiB)
+	}
+	return vcpus, memoryGiB
+}
+
+// instanceSizeAllowed reports whether an offer has at least minVCPUs vCPUs and minMemoryGiB of
+// system memory. No minimum permits any offer, while an offer with an unknown vCPU count or
+// memory never satisfies a minimum on it.
+func instanceSizeAllowed(offer providers.GPUOffer, minVCPUs int, minMemoryGiB int64) bool {
+	return offer.VCPUs >= minVCPUs && offer.Memory >= minMemoryGiB
+}
+
+// offerMeetsInstanceRequirements reports whether an offer satisfies every per-offer constraint
+// of the class instance requirements
+func offerMeetsInstanceRequirements(requirements *tgpv1.InstanceRequirements, offer providers.GPUOffer) bool {
+	minVCPUs, minMemoryGiB := minInstanceSize(requirements)
+	return instanceSizeAllowed(offer, minVCPUs, minMemoryGiB) &&
+		providers.VCPUsPerGPUAllowed(offer, minVCPUPerGPU(requirements)) &&
+		providers.GPUMemoryAllowed(offer, minGPUMemoryGiB(requirements)) &&
+		providers.CUDAVersionAllowed(offer, minCUDAVersion(requirements))
+}
+
+// filterOffersByGPUTypeAndRegion drops offers for GPU types or in regions the class does not allow
+func filterOffersByGPUTypeAndRegion(requirements *tgpv1.InstanceRequirements, offers []providers.GPUOffer) []providers.GPUOffer {
+	if requirements == nil || (len(requirements.GPUTypes) == 0 && len(requirements.Regions) == 0) {
+		return offers
+	}
+
+	filtered := make([]providers.GPUOffer, 0, len(offers))
+	for _, offer := range offers {
+		if gpuTypeAllowed(requirements, offer.GPUType) && regionAllowed(requirements, offer.Region) {
+			filtered = append(filtered, offer)
+		}
+	}
+	return filtered
+}
+
+// filterOffersByInstanceSize drops offers with fewer vCPUs or less system memory than the class asks for
+func filterOffersByInstanceSize(requirements *tgpv1.InstanceRequirements, offers []providers.GPUOffer) []providers.GPUOffer {
+	minVCPUs, minMemoryGiB := minInstanceSize(requirements)
+	if minVCPUs == 0 && minMemoryGiB == 0 {
+		return offers
+	}
+
+	filtered := make([]providers.GPUOffer, 0, len(offers))
+	for _, offer := range offers {
+		if instanceSizeAllowed(offer, minVCPUs, minMemoryGiB) {
+			filtered = append(filtered, offer)
+		}
+	}
+	return filtered
+}
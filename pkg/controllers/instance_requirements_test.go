@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"testing"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+func TestInstanceRequirementsReason(t *testing.T) {
+	noSpot := false
+	requirements := &tgpv1.InstanceRequirements{
+		GPUTypes:    []string{"H100", "A100"},
+		Regions:     []string{"us-east-1", "us-west-2"},
+		SpotAllowed: &noSpot,
+	}
+
+	tests := []struct {
+		name         string
+		requirements *tgpv1.InstanceRequirements
+		requirement  GPURequirement
+		wantReason   bool
+	}{
+		{"no requirements allow anything", nil, GPURequirement{GPUType: "RTX4090", Spot: true}, false},
+		{"allowed GPU type and region", requirements, GPURequirement{GPUType: "H100", Region: "us-west-2"}, false},
+		{"GPU types match case-insensitively", requirements, GPURequirement{GPUType: "a100", Region: "us-east-1"}, false},
+		{"disallowed GPU type", requirements, GPURequirement{GPUType: "RTX4090", Region: "us-east-1"}, true},
+		{"disallowed region", requirements, GPURequirement{GPUType: "H100", Region: "eu-west-1"}, true},
+		{"region left to the provider", requirements, GPURequirement{GPUType: "H100"}, true},
+		{"spot not allowed", requirements, GPURequirement{GPUType: "H100", Region: "us-east-1", Spot: true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := instanceRequirementsReason(tt.requirements, &tt.requirement)
+			if (reason != "") != tt.wantReason {
+				t.Errorf("instanceRequirementsReason() = %q, want a reason: %v", reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestFilterOffersByInstanceRequirements(t *testing.T) {
+	offers := []providers.GPUOffer{
+		{ID: "large", GPUType: "H100", Region: "us-east-1", VCPUs: 32, Memory: 256},
+		{ID: "few-cpus", GPUType: "H100", Region: "us-east-1", VCPUs: 4, Memory: 256},
+		{ID: "little-memory", GPUType: "H100", Region: "us-east-1", VCPUs: 32, Memory: 16},
+		{ID: "unknown-size", GPUType: "H100", Region: "us-east-1"},
+		{ID: "other-type", GPUType: "RTX4090", Region: "us-east-1", VCPUs: 32, Memory: 256},
+		{ID: "other-region", GPUType: "H100", Region: "eu-west-1", VCPUs: 32, Memory: 256},
+	}
+
+	if got := filterOffersByInstanceSize(nil, offers); len(got) != len(offers) {
+		t.Errorf("expected all offers without a minimum size, got %d", len(got))
+	}
+	if got := filterOffersByGPUTypeAndRegion(nil, offers); len(got) != len(offers) {
+		t.Errorf("expected all offers without allowed GPU types or regions, got %d", len(got))
+	}
+
+	minVCPU, minMemoryGiB := int32(16), int32(64)
+	requirements := &tgpv1.InstanceRequirements{
+		GPUTypes:     []string{"H100"},
+		Regions:      []string{"us-east-1"},
+		MinVCPU:      &minVCPU,
+		MinMemoryGiB: &minMemoryGiB,
+	}
+
+	got := filterOffersByInstanceSize(requirements, filterOffersByGPUTypeAndRegion(requirements, offers))
+	if len(got) != 1 || got[0].ID != "large" {
+		t.Errorf("filtered offers = %+v, want only the large offer", got)
+	}
+
+	for _, offer := range offers {
+		want := offer.ID == "large" || offer.ID == "other-type" || offer.ID == "other-region"
+		if got := offerMeetsInstanceRequirements(requirements, offer); got != want {
+			t.Errorf("offerMeetsInstanceRequirements(%s) = %v, want %v", offer.ID, got, want)
+		}
+	}
+}
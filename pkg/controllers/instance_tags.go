@@ -0,0 +1,273 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/config"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+const (
+	// AnnotationAppliedTags records the pool template labels and node class tags last applied
+	// to the node's instance
+	AnnotationAppliedTags = "tgp.io/applied-tags"
+
+	// AnnotationTagsVerifiedAt records when the node's instance was last found to carry its tags
+	AnnotationTagsVerifiedAt = "tgp.io/tags-verified-at"
+
+	// AnnotationTagsUnsupported records the operator version that found the provider of the
+	// node's instance unable to update tags, so it is reported once per node rather than on
+	// every reconcile. An upgraded operator checks again.
+	AnnotationTagsUnsupported = "tgp.io/tags-unsupported"
+
+	// maxTagUpdatesPerReconcile bounds how many instances are re-tagged or verified in a single reconcile
+	maxTagUpdatesPerReconcile = 5
+
+	// tagVerificationInterval is how often an instance's tags are read back from the provider
+	// to catch changes made outside the operator
+	tagVerificationInterval = time.Hour
+)
+
+// instanceTags returns the tags instances in the pool should carry: the pool template labels,
+// so costs can be attributed to them, overridden by the node class's cost-allocation tags
+func instanceTags(nodePool *tgpv1.GPUNodePool, nodeClass *tgpv1.GPUNodeClass) map[string]string {
+	if nodePool.Spec.Template.Metadata == nil || len(nodePool.Spec.Template.Metadata.Labels) == 0 {
+		return nodeClass.Spec.Tags
+	}
+
+	tags := make(map[string]string, len(nodePool.Spec.Template.Metadata.Labels)+len(nodeClass.Spec.Tags))
+	for k, v := range nodePool.Spec.Template.Metadata.Labels {
+		tags[k] = v
+	}
+	for k, v := range nodeClass.Spec.Tags {
+		tags[k] = v
+	}
+	return tags
+}
+
+// reconcileInstanceTags updates the tags of running instances in the pool to match the pool
+// template labels and node class tags. Instances whose tags are up to date are read back from
+// the provider every tagVerificationInterval, restoring tags changed outside the operator. At
+// most maxTagUpdatesPerReconcile instances are re-tagged or verified per call.
+func (r *GPUNodePoolReconciler) reconcileInstanceTags(ctx context.Context, nodePool *tgpv1.GPUNodePool, nodeClass *tgpv1.GPUNodeClass, log logr.Logger) error {
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes, poolNodeSelector(nodePool)); err != nil {
+		return fmt.Errorf("failed to list nodes for pool %s: %w", nodePool.Name, err)
+	}
+
+	desired := instanceTags(nodePool, nodeClass)
+	providerClients := make(map[string]providers.ProviderClient)
+	attempts := 0
+	now := time.Now()
+
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if node.DeletionTimestamp != nil || tagsUnsupported(node, r.OperatorVersion) {
+			continue
+		}
+
+		set, remove := diffTags(appliedTags(node), desired)
+		verify := len(set) == 0 && len(remove) == 0
+		if verify && (len(desired) == 0 || !tagVerificationDue(node, now)) {
+			continue
+		}
+
+		if attempts >= maxTagUpdatesPerReconcile {
+			log.V(1).Info("Deferring remaining instance tag updates to the next reconcile", "limit", maxTagUpdatesPerReconcile)
+			break
+		}
+
+		instanceID, providerName := nodeInstance(node)
+		if providerName == "" || instanceID == "" || !config.Current(r.Config).FeatureEnabled(providerName, config.FeatureTagging) {
+			continue
+		}
+
+		account := node.Annotations[AnnotationAccount]
+		clientKey := providerName + "/" + account
+		providerClient, cached := providerClients[clientKey]
+		if !cached {
+			var err error
+			providerClient, err = r.providerClientForClass(ctx, nodeClass, providerName)
+			if err == nil {
+				err = providers.SelectAccount(providerClient, account)
+			}
+			if err != nil {
+				log.Error(err, "Failed to create provider client for tag update", "provider", providerName)
+				continue
+			}
+			providerClients[clientKey] = providerClient
+		}
+
+		updater, ok := providers.Unwrap(providerClient).(providers.TagUpdater)
+		if !ok {
+			log.V(1).Info("Provider does not support updating instance tags", "node", node.Name, "provider", providerName)
+			r.Metrics.RecordInstanceTagUpdate(providerName, "unsupported")
+			if err := r.recordTagsUnsupported(ctx, node); err != nil {
+				log.Error(err, "Failed to record unsupported instance tags", "node", node.Name)
+			}
+			continue
+		}
+
+		if verify {
+			verifier, ok := providers.Unwrap(providerClient).(providers.TagVerifier)
+			if !ok {
+				continue
+			}
+			attempts++
+			missing, err := verifier.MissingInstanceTags(ctx, instanceID, desired)
+			if err != nil {
+				log.Error(err, "Failed to verify instance tags", "node", node.Name, "instanceID", instanceID)
+				r.Metrics.RecordInstanceTagUpdate(providerName, "error")
+				continue
+			}
+			if len(missing) == 0 {
+				if err := r.recordTagsVerified(ctx, node, now); err != nil {
+					log.Error(err, "Failed to record verified tags", "node", node.Name)
+				}
+				continue
+			}
+			log.Info("Instance tags changed outside the operator, restoring them", "node", node.Name, "instanceID", instanceID, "tags", len(missing))
+			set = missing
+		} else {
+			attempts++
+		}
+
+		if err := updater.UpdateInstanceTags(ctx, instanceID, set, remove); err != nil {
+			log.Error(err, "Failed to update instance tags", "node", node.Name, "instanceID", instanceID)
+			r.Metrics.RecordInstanceTagUpdate(providerName, "error")
+			continue
+		}
+
+		if err := r.recordAppliedTags(ctx, node, desired, now); err != nil {
+			log.Error(err, "Failed to record applied tags", "node", node.Name)
+		}
+		r.Metrics.RecordInstanceTagUpdate(providerName, "updated")
+		log.Info("Updated instance tags", "node", node.Name, "instanceID", instanceID, "set", len(set), "removed", len(remove))
+	}
+
+	return nil
+}
+
+// providerClientForClass creates a client for the provider using the credentials configured on the node class
+func (r *GPUNodePoolReconciler) providerClientForClass(ctx context.Context, nodeClass *tgpv1.GPUNodeClass, providerName string) (providers.ProviderClient, error) {
+	credentials, err := config.Current(r.Config).GetProviderCredentials(ctx, r.Client, providerName, classCredentialsNamespace(nodeClass, providerName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credentials for provider %s: %w", providerName, err)
+	}
+
+	return r.createProviderClient(providerName, credentials)
+}
+
+// classCredentialsNamespace returns the namespace the node class reads the provider's credentials from
+func classCredentialsNamespace(nodeClass *tgpv1.GPUNodeClass, providerName string) string {
+	namespace := "default"
+	for _, providerConfig := range nodeClass.Spec.Providers {
+		if providerConfig.Name == providerName && providerConfig.CredentialsRef.Namespace != "" {
+			namespace = providerConfig.CredentialsRef.Namespace
+		}
+	}
+	return namespace
+}
+
+// recordAppliedTags stores the tags applied to the node's instance in its annotations. Tags
+// just applied need no verification until tagVerificationInterval has passed.
+func (r *GPUNodePoolReconciler) recordAppliedTags(ctx context.Context, node *corev1.Node, tags map[string]string, now time.Time) error {
+	encoded, err := encodeTags(tags)
+	if err != nil {
+		return err
+	}
+
+	if node.Annotations == nil {
+		node.Annotations = make(map[string]string)
+	}
+	node.Annotations[AnnotationAppliedTags] = encoded
+	node.Annotations[AnnotationTagsVerifiedAt] = now.Format(time.RFC3339)
+	return r.Update(ctx, node)
+}
+
+// recordTagsVerified stores when the node's instance was found to carry its tags
+func (r *GPUNodePoolReconciler) recordTagsVerified(ctx context.Context, node *corev1.Node, now time.Time) error {
+	if node.Annotations == nil {
+		node.Annotations = make(map[string]string)
+	}
+	node.Annotations[AnnotationTagsVerifiedAt] = now.Format(time.RFC3339)
+	return r.Update(ctx, node)
+}
+
+// recordTagsUnsupported stores that the provider of the node's instance cannot update its tags
+func (r *GPUNodePoolReconciler) recordTagsUnsupported(ctx context.Context, node *corev1.Node) error {
+	if node.Annotations == nil {
+		node.Annotations = make(map[string]string)
+	}
+	node.Annotations[AnnotationTagsUnsupported] = r.OperatorVersion
+	return r.Update(ctx, node)
+}
+
+// tagsUnsupported reports whether this operator version already found that the provider of
+// the node's instance cannot update its tags
+func tagsUnsupported(node *corev1.Node, operatorVersion string) bool {
+	version, exists := node.Annotations[AnnotationTagsUnsupported]
+	return exists && version == operatorVersion
+}
+
+// tagVerificationDue reports whether the node's instance tags should be read back from the
+// provider: tagVerificationInterval after they were last verified, or after the node was
+// created if they never were
+func tagVerificationDue(node *corev1.Node, now time.Time) bool {
+	last, exists := node.Annotations[AnnotationTagsVerifiedAt]
+	if !exists {
+		last = node.Annotations["tgp.io/created-at"]
+	}
+	verifiedAt, err := time.Parse(time.RFC3339, last)
+	if err != nil {
+		return true
+	}
+	return now.Sub(verifiedAt) >= tagVerificationInterval
+}
+
+// appliedTags returns the tags last applied to the node's instance
+func appliedTags(node *corev1.Node) map[string]string {
+	tags := map[string]string{}
+	if encoded, exists := node.Annotations[AnnotationAppliedTags]; exists {
+		_ = json.Unmarshal([]byte(encoded), &tags)
+	}
+	return tags
+}
+
+// encodeTags serializes tags for the applied-tags annotation
+func encodeTags(tags map[string]string) (string, error) {
+	if tags == nil {
+		tags = map[string]string{}
+	}
+	encoded, err := json.Marshal(tags)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode tags: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// diffTags returns the tags that must be set and the keys that must be removed to go from applied to desired
+func diffTags(applied, desired map[string]string) (map[string]string, []string) {
+	set := make(map[string]string)
+	for k, v := range desired {
+		if current, exists := applied[k]; !exists || current != v {
+			set[k] = v
+		}
+	}
+
+	var remove []string
+	for k := range applied {
+		if _, exists := desired[k]; !exists {
+			remove = append(remove, k)
+		}
+	}
+
+	return set, remove
+}
@@ -0,0 +1,158 @@
+package controllers
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+func TestDiffTags(t *testing.T) {
+	tests := []struct {
+		name         string
+		applied      map[string]string
+		desired      map[string]string
+		expectSet    map[string]string
+		expectRemove []string
+	}{
+		{
+			name:    "unchanged tags need no update",
+			applied: map[string]string{"team": "ml"},
+			desired: map[string]string{"team": "ml"},
+		},
+		{
+			name:      "changed and added tags are set",
+			applied:   map[string]string{"team": "ml", "cost-center": "old"},
+			desired:   map[string]string{"team": "ml", "cost-center": "new", "env": "prod"},
+			expectSet: map[string]string{"cost-center": "new", "env": "prod"},
+		},
+		{
+			name:         "dropped tags are removed",
+			applied:      map[string]string{"team": "ml", "env": "dev"},
+			desired:      map[string]string{"team": "ml"},
+			expectRemove: []string{"env"},
+		},
+		{
+			name:         "clearing all tags removes them",
+			applied:      map[string]string{"team": "ml", "env": "dev"},
+			desired:      nil,
+			expectRemove: []string{"env", "team"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set, remove := diffTags(tt.applied, tt.desired)
+			sort.Strings(remove)
+
+			if len(set) != len(tt.expectSet) {
+				t.Errorf("diffTags() set = %v, want %v", set, tt.expectSet)
+			}
+			for k, v := range tt.expectSet {
+				if set[k] != v {
+					t.Errorf("diffTags() set[%s] = %q, want %q", k, set[k], v)
+				}
+			}
+			if len(remove) != len(tt.expectRemove) {
+				t.Fatalf("diffTags() remove = %v, want %v", remove, tt.expectRemove)
+			}
+			for i := range tt.expectRemove {
+				if remove[i] != tt.expectRemove[i] {
+					t.Errorf("diffTags() remove = %v, want %v", remove, tt.expectRemove)
+				}
+			}
+		})
+	}
+}
+
+func TestAppliedTagsRoundTrip(t *testing.T) {
+	encoded, err := encodeTags(map[string]string{"team": "ml"})
+	if err != nil {
+		t.Fatalf("encodeTags() error = %v", err)
+	}
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{AnnotationAppliedTags: encoded},
+	}}
+	if tags := appliedTags(node); tags["team"] != "ml" || len(tags) != 1 {
+		t.Errorf("appliedTags() = %v, want map[team:ml]", tags)
+	}
+
+	if tags := appliedTags(&corev1.Node{}); len(tags) != 0 {
+		t.Errorf("appliedTags() without annotation = %v, want empty", tags)
+	}
+}
+
+func TestInstanceTags(t *testing.T) {
+	nodeClass := &tgpv1.GPUNodeClass{Spec: tgpv1.GPUNodeClassSpec{Tags: map[string]string{"team": "ml"}}}
+	nodePool := &tgpv1.GPUNodePool{Spec: tgpv1.GPUNodePoolSpec{Template: tgpv1.NodePoolTemplate{
+		Metadata: &tgpv1.NodeMetadata{Labels: map[string]string{"team": "research", "project": "llm"}},
+	}}}
+
+	tags := instanceTags(nodePool, nodeClass)
+	if len(tags) != 2 || tags["team"] != "ml" || tags["project"] != "llm" {
+		t.Errorf("instanceTags() = %v, want template labels overridden by class tags", tags)
+	}
+	if nodePool.Spec.Template.Metadata.Labels["team"] != "research" {
+		t.Error("instanceTags() must not modify the template labels")
+	}
+	if tags := instanceTags(&tgpv1.GPUNodePool{}, nodeClass); len(tags) != 1 || tags["team"] != "ml" {
+		t.Errorf("instanceTags() without template labels = %v, want the class tags", tags)
+	}
+}
+
+func TestTagVerificationDue(t *testing.T) {
+	now := time.Now()
+	node := func(annotations map[string]string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}
+	}
+	ago := func(d time.Duration) string { return now.Add(-d).Format(time.RFC3339) }
+
+	tests := []struct {
+		name string
+		node *corev1.Node
+		want bool
+	}{
+		{"recently verified", node(map[string]string{AnnotationTagsVerifiedAt: ago(time.Minute)}), false},
+		{"verified long ago", node(map[string]string{AnnotationTagsVerifiedAt: ago(2 * time.Hour)}), true},
+		{"never verified, recently created", node(map[string]string{"tgp.io/created-at": ago(time.Minute)}), false},
+		{"never verified, created long ago", node(map[string]string{"tgp.io/created-at": ago(2 * time.Hour)}), true},
+		{"no timestamps", node(nil), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tagVerificationDue(tt.node, now); got != tt.want {
+				t.Errorf("tagVerificationDue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagsUnsupported(t *testing.T) {
+	node := func(annotations map[string]string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}
+	}
+
+	tests := []struct {
+		name string
+		node *corev1.Node
+		want bool
+	}{
+		{"never checked", node(nil), false},
+		{"found unsupported by this version", node(map[string]string{AnnotationTagsUnsupported: "v1.2.0"}), true},
+		{"found unsupported by an earlier version", node(map[string]string{AnnotationTagsUnsupported: "v1.1.0"}), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tagsUnsupported(tt.node, "v1.2.0"); got != tt.want {
+				t.Errorf("tagsUnsupported() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+const (
+	// EventReasonInstanceInterrupted is emitted when a node's instance was terminated or failed
+	// outside the operator's control, such as a reclaimed spot instance
+	EventReasonInstanceInterrupted = "InstanceInterrupted"
+
+	// interruptionPollInterval is how often a NotReady node is re-checked while its instance is
+	// still shutting down
+	interruptionPollInterval = time.Minute
+)
+
+// reconcileInterruptions checks the instances behind the pool's NotReady nodes and removes
+// nodes whose instance the provider terminated or failed, such as reclaimed spot instances.
+// Surviving pods are drained so pod-driven provisioning launches replacement capacity.
+// It returns how long until a node needs to be checked again, or 0 if none does.
+func (r *GPUNodePoolReconciler) reconcileInterruptions(ctx context.Context, nodePool *tgpv1.GPUNodePool, nodeClass *tgpv1.GPUNodeClass, log logr.Logger) (time.Duration, error) {
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes, poolNodeSelector(nodePool)); err != nil {
+		return 0, fmt.Errorf("failed to list nodes for pool %s: %w", nodePool.Name, err)
+	}
+
+	providerClients := make(map[string]providers.ProviderClient)
+	var next time.Duration
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if node.DeletionTimestamp != nil || isNodeReady(node) {
+			continue
+		}
+		instanceID, providerName := nodeInstance(node)
+		if instanceID == "" || providerName == "" {
+			continue
+		}
+
+		account := node.Annotations[AnnotationAccount]
+		clientKey := providerName + "/" + account
+		providerClient, cached := providerClients[clientKey]
+		if !cached {
+			var err error
+			providerClient, err = r.providerClientForClass(ctx, nodeClass, providerName)
+			if err == nil {
+				err = providers.SelectAccount(providerClient, account)
+			}
+			if err != nil {
+				log.Error(err, "Failed to create provider client for interruption check", "provider", providerName)
+				continue
+			}
+			providerClients[clientKey] = providerClient
+		}
+
+		status, err := providerClient.GetInstanceStatus(ctx, instanceID)
+		if errors.Is(err, providers.ErrNotFound) {
+			// The provider no longer knows the instance, so it was purged after terminating
+			status, err = &providers.InstanceStatus{State: providers.InstanceStateTerminated, Message: "instance no longer exists"}, nil
+		}
+		if err != nil {
+			log.V(1).Info("Failed to get instance status for NotReady node", "node", node.Name, "instanceID", instanceID, "error", err.Error())
+			continue
+		}
+
+		switch status.State {
+		case providers.InstanceStateTerminating:
+			next = interruptionPollInterval
+		case providers.InstanceStateTerminated, providers.InstanceStateFailed:
+			if err := r.handleInterruptedNode(ctx, nodePool, nodeClass, node, status, log); err != nil {
+				log.Error(err, "Failed to remove interrupted node", "node", node.Name)
+				next = terminationRetryInterval
+			}
+		}
+	}
+
+	return next, nil
+}
+
+// handleInterruptedNode removes a node whose instance is gone. The instance is terminated
+// either way: failed instances and those reported terminated because they were only
+// stopped still hold disks, addresses and, on some providers, compute charges.
+func (r *GPUNodePoolReconciler) handleInterruptedNode(ctx context.Context, nodePool *tgpv1.GPUNodePool, nodeClass *tgpv1.GPUNodeClass, node *corev1.Node, status *providers.InstanceStatus, log logr.Logger) error {
+	instanceID, providerName := nodeInstance(node)
+	r.recordEvent(nodePool, corev1.EventTypeWarning, EventReasonInstanceInterrupted, interruptionMessage(node, providerName, status))
+	log.Info("Instance interrupted, removing node", "node", node.Name, "instanceID", instanceID, "state", status.State)
+
+	// The instance is gone, so its pods cannot terminate gracefully
+	if err := r.cordonAndDrainNode(ctx, node, 0, log); err != nil {
+		return err
+	}
+
+	// Providers treat instances that no longer exist as terminated
+	if err := r.terminateNodeInstance(ctx, node, classCredentialsNamespace(nodeClass, providerName), tgpv1.TerminationReasonInterrupted); err != nil {
+		return err
+	}
+
+	return r.deleteNode(ctx, node, log)
+}
+
+// interruptionMessage describes an interrupted instance for the pool's event
+func interruptionMessage(node *corev1.Node, providerName string, status *providers.InstanceStatus) string {
+	instanceID, _ := nodeInstance(node)
+	kind := "Instance"
+	if node.Labels[tgpv1.NodeLabelSpot] == "true" {
+		kind = "Spot instance"
+	}
+	message := fmt.Sprintf("%s %s backing node %s is %s at %s; draining and removing the node", kind, instanceID, node.Name, status.State, providerName)
+	if status.Message != "" {
+		message += ": " + status.Message
+	}
+	return message
+}
+
+// isNodeReady reports whether the node's Ready condition is True
+func isNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
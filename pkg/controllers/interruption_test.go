@@ -0,0 +1,196 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/config"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// instanceClient reports a fixed instance status and records terminated instances
+type instanceClient struct {
+	providers.ProviderClient
+	status       *providers.InstanceStatus
+	statusErr    error
+	terminateErr error
+	terminated   []string
+}
+
+func (c *instanceClient) GetInstanceStatus(ctx context.Context, instanceID string) (*providers.InstanceStatus, error) {
+	return c.status, c.statusErr
+}
+
+func (c *instanceClient) TerminateInstance(ctx context.Context, instanceID string) error {
+	if c.terminateErr != nil {
+		return c.terminateErr
+	}
+	c.terminated = append(c.terminated, instanceID)
+	return nil
+}
+
+// useFakeAWS enables AWS with workload identity, so no credentials secret is needed, and
+// makes the reconciler use providerClient as its AWS client
+func useFakeAWS(r *GPUNodePoolReconciler, providerClient providers.ProviderClient) {
+	operatorConfig := config.DefaultConfig()
+	operatorConfig.Providers.AWS = config.ProviderConfig{Enabled: true, CredentialsSource: config.CredentialsSourceWorkloadIdentity}
+	r.Config = operatorConfig
+	r.newClient = func(name, credentials string) (providers.ProviderClient, error) {
+		if name != "aws" {
+			return nil, fmt.Errorf("unexpected provider %s", name)
+		}
+		return providerClient, nil
+	}
+}
+
+func TestIsNodeReady(t *testing.T) {
+	node := func(status corev1.ConditionStatus) *corev1.Node {
+		return &corev1.Node{Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}}}}
+	}
+
+	if !isNodeReady(node(corev1.ConditionTrue)) {
+		t.Error("expected node with Ready=True to be ready")
+	}
+	if isNodeReady(node(corev1.ConditionUnknown)) || isNodeReady(&corev1.Node{}) {
+		t.Error("expected unreachable and unjoined nodes not to be ready")
+	}
+}
+
+func TestHandleInterruptedNode(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = tgpv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name: "spot-node",
+		Labels: map[string]string{
+			"tgp.io/nodepool":                "test-pool",
+			tgpv1.NodeLabelNodePoolNamespace: "default",
+			"tgp.io/instance-id":             "i-123",
+			tgpv1.NodeLabelProvider:          "aws",
+			tgpv1.NodeLabelSpot:              "true",
+		},
+	}}
+	workload := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "train", Namespace: "default"},
+		Spec:       corev1.PodSpec{NodeName: "spot-node"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node, workload).Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &GPUNodePoolReconciler{Client: client, Scheme: scheme, Recorder: recorder}
+	providerClient := &instanceClient{}
+	useFakeAWS(r, providerClient)
+
+	nodePool := &tgpv1.GPUNodePool{ObjectMeta: metav1.ObjectMeta{Name: "test-pool", Namespace: "default"}}
+	status := &providers.InstanceStatus{State: providers.InstanceStateTerminated, Message: "spot capacity reclaimed"}
+	if err := r.handleInterruptedNode(context.Background(), nodePool, &tgpv1.GPUNodeClass{}, node, status, logr.Discard()); err != nil {
+		t.Fatalf("handleInterruptedNode failed: %v", err)
+	}
+
+	// Instances reported terminated may only be stopped, so they are terminated too
+	if len(providerClient.terminated) != 1 || providerClient.terminated[0] != "i-123" {
+		t.Errorf("expected instance i-123 to be terminated, got %v", providerClient.terminated)
+	}
+
+	ctx := context.Background()
+	if err := client.Get(ctx, types.NamespacedName{Name: "spot-node"}, &corev1.Node{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the interrupted node to be deleted, got: %v", err)
+	}
+	if err := client.Get(ctx, types.NamespacedName{Name: "train", Namespace: "default"}, &corev1.Pod{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the workload pod to be drained, got: %v", err)
+	}
+
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, EventReasonInstanceInterrupted) || !strings.Contains(event, "Spot instance i-123") {
+			t.Errorf("unexpected event: %s", event)
+		}
+	default:
+		t.Error("expected an interruption event")
+	}
+}
+
+func TestReconcileInterruptions(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = tgpv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	tests := []struct {
+		name          string
+		status        *providers.InstanceStatus
+		statusErr     error
+		terminateErr  error
+		wantRemoved   bool
+		wantRetryNext bool
+	}{
+		{
+			name:   "running instance is left alone",
+			status: &providers.InstanceStatus{State: providers.InstanceStateRunning},
+		},
+		{
+			name:        "stopped instance is terminated and removed",
+			status:      &providers.InstanceStatus{State: providers.InstanceStateTerminated},
+			wantRemoved: true,
+		},
+		{
+			name:        "purged instance is removed",
+			statusErr:   fmt.Errorf("instance i-123 %w", providers.ErrNotFound),
+			wantRemoved: true,
+		},
+		{
+			name:      "status errors are retried",
+			statusErr: fmt.Errorf("connection reset"),
+		},
+		{
+			name:          "node is kept when termination fails",
+			status:        &providers.InstanceStatus{State: providers.InstanceStateFailed},
+			terminateErr:  fmt.Errorf("throttled"),
+			wantRetryNext: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+				Name: "gpu-node",
+				Labels: map[string]string{
+					"tgp.io/nodepool":                "test-pool",
+					tgpv1.NodeLabelNodePoolNamespace: "default",
+					"tgp.io/instance-id":             "i-123",
+					tgpv1.NodeLabelProvider:          "aws",
+				},
+			}}
+			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node).Build()
+			r := &GPUNodePoolReconciler{Client: client, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+			useFakeAWS(r, &instanceClient{status: tt.status, statusErr: tt.statusErr, terminateErr: tt.terminateErr})
+
+			ctx := context.Background()
+			nodePool := &tgpv1.GPUNodePool{ObjectMeta: metav1.ObjectMeta{Name: "test-pool", Namespace: "default"}}
+			next, err := r.reconcileInterruptions(ctx, nodePool, &tgpv1.GPUNodeClass{}, logr.Discard())
+			if err != nil {
+				t.Fatalf("reconcileInterruptions failed: %v", err)
+			}
+			if (next == terminationRetryInterval) != tt.wantRetryNext {
+				t.Errorf("next check in %v, want retry %v", next, tt.wantRetryNext)
+			}
+
+			err = client.Get(ctx, types.NamespacedName{Name: "gpu-node"}, &corev1.Node{})
+			if removed := apierrors.IsNotFound(err); removed != tt.wantRemoved {
+				t.Errorf("node removed = %v, want %v (get error: %v)", removed, tt.wantRemoved, err)
+			}
+		})
+	}
+}
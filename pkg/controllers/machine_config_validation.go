@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+// errInvalidMachineConfig is returned when the rendered machine config could not configure a node
+var errInvalidMachineConfig = errors.New("invalid machine config")
+
+// unrenderedPlaceholder matches template actions left in a rendered config, capturing the
+// field name of simple {{.Field}} placeholders
+var unrenderedPlaceholder = regexp.MustCompile(`\{\{-?\s*(?:\.(\w+))?[^}]*\}\}`)
+
+// requiredMachineConfigFields are the fields a worker needs to join the cluster, as paths
+// into the document with the machine section
+var requiredMachineConfigFields = [][]string{
+	{"machine", "token"},
+	{"cluster", "controlPlane", "endpoint"},
+}
+
+// validateMachineConfig checks that a rendered machine config is valid YAML, has no template
+// placeholders left unsubstituted and sets the fields a node needs to join the cluster, so a
+// broken template fails before an instance is paid for rather than leaving a node that never joins
+func validateMachineConfig(machineConfig string) error {
+	if matches := unrenderedPlaceholder.FindAllStringSubmatch(machineConfig, -1); len(matches) > 0 {
+		var placeholders []string
+		for _, match := range matches {
+			placeholder := match[0]
+			if match[1] != "" {
+				placeholder = match[1]
+			}
+			if !slices.Contains(placeholders, placeholder) {
+				placeholders = append(placeholders, placeholder)
+			}
+		}
+		return fmt.Errorf("%w: placeholders were not substituted: %s",
+			errInvalidMachineConfig, strings.Join(placeholders, ", "))
+	}
+
+	var root *yaml.Node
+	decoder := yaml.NewDecoder(bytes.NewBufferString(machineConfig))
+	for {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("%w: not valid YAML: %w", errInvalidMachineConfig, err)
+		}
+		if root == nil && len(document.Content) > 0 && mappingValue(document.Content[0], "machine") != nil {
+			root = document.Content[0]
+		}
+	}
+	if root == nil {
+		return fmt.Errorf("%w: no machine section", errInvalidMachineConfig)
+	}
+
+	var missing []string
+	for _, path := range requiredMachineConfigFields {
+		value := root
+		for _, key := range path {
+			value = mappingValue(value, key)
+		}
+		if value == nil || value.Kind != yaml.ScalarNode || value.Tag == "!!null" || strings.TrimSpace(value.Value) == "" {
+			missing = append(missing, strings.Join(path, "."))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing %s", errInvalidMachineConfig, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// provisioningReadyReason returns the Ready condition reason for a provisioning error, blaming
+// the template when the rendered machine config was rejected
+func provisioningReadyReason(err error) string {
+	if errors.Is(err, errInvalidMachineConfig) {
+		return tgpv1.ReadyReasonInvalidTemplate
+	}
+	return tgpv1.ReadyReasonProvisioningFailed
+}
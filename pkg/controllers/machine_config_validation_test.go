@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+func TestValidateMachineConfig(t *testing.T) {
+	const valid = `version: v1alpha1
+machine:
+  type: worker
+  token: abcdef.0123456789abcdef
+cluster:
+  controlPlane:
+    endpoint: https://10.0.0.1:6443
+`
+
+	tests := []struct {
+		name          string
+		machineConfig string
+		wantErr       string
+	}{
+		{"complete config", valid, ""},
+		{"multiple documents", "apiVersion: v1alpha1\nkind: ExtensionServiceConfig\nname: tailscale\n---\n" + valid, ""},
+		{
+			name:          "unsubstituted placeholders",
+			machineConfig: strings.ReplaceAll(valid, "abcdef.0123456789abcdef", "{{.MachineToken}}") + "# {{ .ClusterCA }} {{.MachineToken}}\n",
+			wantErr:       "placeholders were not substituted: MachineToken, ClusterCA",
+		},
+		{"invalid YAML", "machine: [\n", "not valid YAML"},
+		{"no machine section", "version: v1alpha1\n", "no machine section"},
+		{
+			name:          "missing token and endpoint",
+			machineConfig: "machine:\n  type: worker\n  token: \"\"\ncluster:\n  controlPlane: {}\n",
+			wantErr:       "missing machine.token, cluster.controlPlane.endpoint",
+		},
+		{"null endpoint", strings.Replace(valid, "https://10.0.0.1:6443", "~", 1), "missing cluster.controlPlane.endpoint"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMachineConfig(tt.machineConfig)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateMachineConfig() = %v, want no error", err)
+				}
+				return
+			}
+			if !errors.Is(err, errInvalidMachineConfig) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateMachineConfig() = %v, want an invalid machine config error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProvisioningReadyReason(t *testing.T) {
+	invalid := fmt.Errorf("failed to generate Talos machine config: %w", fmt.Errorf("%w: missing machine.token", errInvalidMachineConfig))
+	if reason := provisioningReadyReason(invalid); reason != tgpv1.ReadyReasonInvalidTemplate {
+		t.Errorf("provisioningReadyReason(invalid config) = %s, want %s", reason, tgpv1.ReadyReasonInvalidTemplate)
+	}
+	if reason := provisioningRequeueReason(invalid); reason != RequeueReasonValidationFailed {
+		t.Errorf("provisioningRequeueReason(invalid config) = %s, want %s", reason, RequeueReasonValidationFailed)
+	}
+	if reason := provisioningReadyReason(errNoSuitableProvider); reason != tgpv1.ReadyReasonProvisioningFailed {
+		t.Errorf("provisioningReadyReason(no provider) = %s, want %s", reason, tgpv1.ReadyReasonProvisioningFailed)
+	}
+}
@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+// AnnotationAppliedLabels records the pool template labels last applied to the node
+const AnnotationAppliedLabels = "tgp.io/applied-labels"
+
+// templateLabels returns the labels the pool template puts on its nodes
+func templateLabels(nodePool *tgpv1.GPUNodePool) map[string]string {
+	if nodePool.Spec.Template.Metadata == nil {
+		return nil
+	}
+	return nodePool.Spec.Template.Metadata.Labels
+}
+
+// reconcileNodeLabels updates the labels of the pool's nodes to match the pool template,
+// without re-provisioning them. Labels the template no longer sets are removed, or reset to
+// the value the operator gives every node, while labels set by anything else are kept.
+func (r *GPUNodePoolReconciler) reconcileNodeLabels(ctx context.Context, nodePool *tgpv1.GPUNodePool, log logr.Logger) error {
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes, poolNodeSelector(nodePool)); err != nil {
+		return fmt.Errorf("failed to list nodes for pool %s: %w", nodePool.Name, err)
+	}
+
+	desired := templateLabels(nodePool)
+	encoded, err := encodeTags(desired)
+	if err != nil {
+		return err
+	}
+
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if node.DeletionTimestamp != nil {
+			continue
+		}
+
+		applied, recorded := appliedLabels(node)
+		set, remove := diffTags(applied, desired)
+		// Restore template labels changed on the node itself
+		for k, v := range desired {
+			if current, exists := node.Labels[k]; !exists || current != v {
+				set[k] = v
+			}
+		}
+		if recorded && len(set) == 0 && len(remove) == 0 {
+			continue
+		}
+
+		if node.Labels == nil {
+			node.Labels = make(map[string]string)
+		}
+		base := operatorNodeLabels(nodePool, node)
+		for _, k := range remove {
+			if v, exists := base[k]; exists {
+				node.Labels[k] = v
+			} else {
+				delete(node.Labels, k)
+			}
+		}
+		for k, v := range set {
+			node.Labels[k] = v
+		}
+		if node.Annotations == nil {
+			node.Annotations = make(map[string]string)
+		}
+		node.Annotations[AnnotationAppliedLabels] = encoded
+
+		if err := r.Update(ctx, node); err != nil {
+			log.Error(err, "Failed to update node labels", "node", node.Name)
+			continue
+		}
+		if len(set) > 0 || len(remove) > 0 {
+			log.Info("Updated node labels to match the pool template", "node", node.Name, "set", len(set), "removed", len(remove))
+		}
+	}
+
+	return nil
+}
+
+// operatorNodeLabels returns the labels the operator gives the node regardless of the pool
+// template, which a label dropped from the template falls back to
+func operatorNodeLabels(nodePool *tgpv1.GPUNodePool, node *corev1.Node) map[string]string {
+	withoutTemplate := nodePool.DeepCopy()
+	withoutTemplate.Spec.Template.Metadata = nil
+	requirement := &GPURequirement{
+		GPUType: node.Labels[tgpv1.NodeLabelGPUType],
+		Region:  node.Labels[tgpv1.NodeLabelRegion],
+	}
+	return buildNodeLabels(withoutTemplate, requirement, node.Labels[tgpv1.NodeLabelProvider], node.Labels[tgpv1.NodeLabelSpot] == "true")
+}
+
+// appliedLabels returns the template labels last applied to the node, and whether they were
+// recorded. Nodes created before labels were recorded report none, so no label is removed
+// from them until the template labels are recorded on the next update.
+func appliedLabels(node *corev1.Node) (map[string]string, bool) {
+	labels := map[string]string{}
+	encoded, exists := node.Annotations[AnnotationAppliedLabels]
+	if exists {
+		_ = json.Unmarshal([]byte(encoded), &labels)
+	}
+	return labels, exists
+}
@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+func TestReconcileNodeLabels(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = tgpv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	applied, _ := encodeTags(map[string]string{"team": "ml", "tier": "gold", "kubernetes.io/arch": "arm64"})
+	current := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name: "current",
+		Labels: map[string]string{
+			"tgp.io/nodepool":                "test-pool",
+			tgpv1.NodeLabelNodePoolNamespace: "default",
+			"team":                           "ml",
+			"tier":                           "gold",
+			"kubernetes.io/arch":             "arm64",
+			"added-by-admin":                 "yes",
+		},
+		Annotations: map[string]string{AnnotationAppliedLabels: applied},
+	}}
+	legacy := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:   "legacy",
+		Labels: map[string]string{"tgp.io/nodepool": "test-pool", tgpv1.NodeLabelNodePoolNamespace: "default", "team": "old", "tier": "gold"},
+	}}
+	nodePool := &tgpv1.GPUNodePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pool", Namespace: "default"},
+		Spec: tgpv1.GPUNodePoolSpec{Template: tgpv1.NodePoolTemplate{
+			Metadata: &tgpv1.NodeMetadata{Labels: map[string]string{"team": "research", "project": "llm"}},
+		}},
+	}
+
+	r := &GPUNodePoolReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(current, legacy).Build(),
+	}
+	if err := r.reconcileNodeLabels(context.Background(), nodePool, logr.Discard()); err != nil {
+		t.Fatalf("reconcileNodeLabels() error = %v", err)
+	}
+
+	get := func(name string) *corev1.Node {
+		var node corev1.Node
+		if err := r.Get(context.Background(), types.NamespacedName{Name: name}, &node); err != nil {
+			t.Fatalf("failed to get node %s: %v", name, err)
+		}
+		return &node
+	}
+
+	node := get("current")
+	want := map[string]string{
+		"tgp.io/nodepool":                "test-pool",
+		tgpv1.NodeLabelNodePoolNamespace: "default",
+		"team":                           "research",
+		"project":                        "llm",
+		// The operator's own value returns once the template stops overriding it
+		"kubernetes.io/arch": "amd64",
+		"added-by-admin":     "yes",
+	}
+	if len(node.Labels) != len(want) {
+		t.Errorf("labels = %v, want %v", node.Labels, want)
+	}
+	for k, v := range want {
+		if node.Labels[k] != v {
+			t.Errorf("label %s = %q, want %q", k, node.Labels[k], v)
+		}
+	}
+	if labels, recorded := appliedLabels(node); !recorded || len(labels) != 2 || labels["team"] != "research" {
+		t.Errorf("appliedLabels() = %v, %v, want the current template labels", labels, recorded)
+	}
+
+	// Labels of nodes created before they were recorded are set but never removed
+	node = get("legacy")
+	if node.Labels["team"] != "research" || node.Labels["project"] != "llm" || node.Labels["tier"] != "gold" {
+		t.Errorf("legacy node labels = %v, want template labels set and tier kept", node.Labels)
+	}
+	if _, recorded := appliedLabels(node); !recorded {
+		t.Error("expected the template labels to be recorded on the legacy node")
+	}
+
+	// A label changed on the node is restored
+	node.Labels["team"] = "edited"
+	if err := r.Update(context.Background(), node); err != nil {
+		t.Fatalf("failed to update node: %v", err)
+	}
+	if err := r.reconcileNodeLabels(context.Background(), nodePool, logr.Discard()); err != nil {
+		t.Fatalf("reconcileNodeLabels() error = %v", err)
+	}
+	if node = get("legacy"); node.Labels["team"] != "research" {
+		t.Errorf("team label = %q, want the template value restored", node.Labels["team"])
+	}
+}
@@ -0,0 +1,257 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+const (
+	// AnnotationExpiringSince marks a node that has been cordoned ahead of its expiry
+	AnnotationExpiringSince = "tgp.io/expiring-since"
+
+	// defaultExpireGracePeriod is how long before expiry nodes are cordoned when unset
+	defaultExpireGracePeriod = time.Hour
+
+	// expiryPollInterval is how often a cordoned node is checked for remaining workloads
+	expiryPollInterval = time.Minute
+
+	// terminationRetryInterval is how soon an expired node is retried after its instance
+	// could not be terminated
+	terminationRetryInterval = 30 * time.Second
+
+	// maxLifetimeStaggerInterval is the minimum time between two MaxLifetime terminations in a pool
+	maxLifetimeStaggerInterval = 5 * time.Minute
+
+	// maxLifetimePollInterval is how often a node past its MaxLifetime is retried while held back
+	maxLifetimePollInterval = time.Minute
+)
+
+// Event reasons emitted during the node lifecycle
+const (
+	EventReasonNodeExpiring = "NodeExpiring"
+	EventReasonNodeExpired  = "NodeExpired"
+)
+
+// reconcileNodeLifecycle enforces lifecycle policies on nodes owned by the pool.
+// It returns how long until the next lifecycle transition is due, or 0 if none is pending.
+func (r *GPUNodePoolReconciler) reconcileNodeLifecycle(ctx context.Context, nodePool *tgpv1.GPUNodePool, log logr.Logger) (time.Duration, error) {
+	maxAge, reason := nodeMaxAge(nodePool)
+	if maxAge <= 0 {
+		clearTerminationWarning(nodePool)
+		return 0, nil
+	}
+
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes, poolNodeSelector(nodePool)); err != nil {
+		return 0, fmt.Errorf("failed to list nodes for pool %s: %w", nodePool.Name, err)
+	}
+
+	// Oldest nodes first, so they are the first recycled when only one may go at a time
+	sort.Slice(nodes.Items, func(i, j int) bool {
+		ci, cj := nodeCreationTime(&nodes.Items[i]), nodeCreationTime(&nodes.Items[j])
+		if !ci.Equal(cj) {
+			return ci.Before(cj)
+		}
+		return nodes.Items[i].Name < nodes.Items[j].Name
+	})
+
+	// Warn about nodes nearing expiry before they are cordoned or terminated
+	next, err := r.warnImminentTerminations(ctx, nodePool, nodes.Items, maxAge, time.Now(), log)
+	if err != nil {
+		log.Error(err, "Failed to warn about imminent terminations")
+		next = terminationRetryInterval
+	}
+
+	for i := range nodes.Items {
+		wait, err := r.enforceNodeExpiry(ctx, nodePool, nodes.Items, &nodes.Items[i], maxAge, reason, log)
+		if err != nil {
+			log.Error(err, "Failed to enforce node expiry", "node", nodes.Items[i].Name)
+			wait = terminationRetryInterval
+		}
+		if wait > 0 && (next == 0 || wait < next) {
+			next = wait
+		}
+	}
+
+	return next, nil
+}
+
+// nodeMaxAge returns how long the pool's nodes may run, the shorter of Disruption.ExpireAfter
+// and the template's MaxLifetime, and the termination reason of nodes reaching that age.
+// It returns 0 when neither is set.
+func nodeMaxAge(nodePool *tgpv1.GPUNodePool) (time.Duration, tgpv1.TerminationReason) {
+	var maxAge time.Duration
+	reason := tgpv1.TerminationReasonExpired
+	if nodePool.Spec.Disruption != nil && nodePool.Spec.Disruption.ExpireAfter != nil {
+		maxAge = nodePool.Spec.Disruption.ExpireAfter.Duration
+	}
+	if maxLifetime := nodePool.Spec.Template.Spec.MaxLifetime; maxLifetime != nil && maxLifetime.Duration > 0 &&
+		(maxAge <= 0 || maxLifetime.Duration < maxAge) {
+		maxAge, reason = maxLifetime.Duration, tgpv1.TerminationReasonMaxLifetime
+	}
+	return maxAge, reason
+}
+
+// enforceNodeExpiry cordons a node as it approaches its maximum age, waits for its workloads
+// to complete within the grace period, then drains and terminates it once expired or empty.
+// It returns how long until the node needs to be checked again.
+func (r *GPUNodePoolReconciler) enforceNodeExpiry(ctx context.Context, nodePool *tgpv1.GPUNodePool, nodes []corev1.Node, node *corev1.Node, maxAge time.Duration, reason tgpv1.TerminationReason, log logr.Logger) (time.Duration, error) {
+	if node.DeletionTimestamp != nil {
+		return 0, nil
+	}
+
+	grace := expireGracePeriod(nodePool)
+	now := time.Now()
+	expireAt := nodeCreationTime(node).Add(maxAge)
+	cordonAt := expireAt.Add(-grace)
+
+	if now.Before(cordonAt) {
+		return cordonAt.Sub(now), nil
+	}
+
+	// Cordon the node so no new workloads land on it before expiry
+	if _, marked := node.Annotations[AnnotationExpiringSince]; !marked && now.Before(expireAt) {
+		if node.Annotations == nil {
+			node.Annotations = make(map[string]string)
+		}
+		node.Annotations[AnnotationExpiringSince] = now.Format(time.RFC3339)
+		node.Spec.Unschedulable = true
+		if err := r.Update(ctx, node); err != nil {
+			return 0, fmt.Errorf("failed to cordon expiring node %s: %w", node.Name, err)
+		}
+		log.Info("Cordoned expiring node", "node", node.Name, "expireAt", expireAt.Format(time.RFC3339))
+		r.recordEvent(nodePool, corev1.EventTypeNormal, EventReasonNodeExpiring,
+			fmt.Sprintf("Node %s expires at %s; cordoned and waiting for workloads to complete", node.Name, expireAt.Format(time.RFC3339)))
+	}
+
+	// Wait for running workloads to complete until the node actually expires
+	if now.Before(expireAt) {
+		activePods, err := r.countWorkloadPods(ctx, node)
+		if err != nil {
+			return 0, err
+		}
+		if activePods > 0 {
+			log.V(1).Info("Waiting for workloads on expiring node", "node", node.Name, "pods", activePods)
+			return min(expireAt.Sub(now), expiryPollInterval), nil
+		}
+	}
+
+	draining := node.Annotations[AnnotationDrainStartedAt] != ""
+	if reason == tgpv1.TerminationReasonMaxLifetime && !draining {
+		if wait := maxLifetimeHoldBack(nodePool, nodes, now, log); wait > 0 {
+			return wait, nil
+		}
+	}
+
+	if !draining {
+		r.recordEvent(nodePool, corev1.EventTypeNormal, EventReasonNodeExpired,
+			fmt.Sprintf("Node %s reached its maximum age of %s; draining and terminating", node.Name, maxAge))
+	}
+	nodeClass, err := r.getNodeClass(ctx, nodePool)
+	if err != nil {
+		log.V(1).Info("Node class unavailable, using default credentials namespace", "error", err.Error())
+		nodeClass = &tgpv1.GPUNodeClass{}
+	}
+	instanceID, providerName := nodeInstance(node)
+	if err := r.cleanupNode(ctx, node, classCredentialsNamespace(nodeClass, providerName), reason, log); isDrainPending(err) {
+		log.Info("Waiting for expired node to drain", "node", node.Name, "reason", err.Error())
+		return drainPollInterval, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("failed to recycle expired node %s: %w", node.Name, err)
+	}
+
+	if reason != tgpv1.TerminationReasonMaxLifetime {
+		return 0, nil
+	}
+	// The termination is recorded on the pool so the next node waits out the stagger interval
+	nodePool.Status.LastTermination = &tgpv1.InstanceTermination{
+		InstanceID: instanceID,
+		Provider:   providerName,
+		NodeName:   node.Name,
+		Reason:     reason,
+		Time:       metav1.Now(),
+	}
+	return maxLifetimeStaggerInterval, nil
+}
+
+// maxLifetimeHoldBack returns how long to wait before draining another node past the template's
+// MaxLifetime, or 0 if it may start now. Such nodes are drained one at a time, at least
+// maxLifetimeStaggerInterval apart, and not while the pool is failing to provision pending pods.
+func maxLifetimeHoldBack(nodePool *tgpv1.GPUNodePool, nodes []corev1.Node, now time.Time, log logr.Logger) time.Duration {
+	if nodePool.Status.PendingSince != nil {
+		log.V(1).Info("Holding back MaxLifetime recycling while pending pods are unprovisioned",
+			"pendingSince", nodePool.Status.PendingSince.Time)
+		return maxLifetimePollInterval
+	}
+	for i := range nodes {
+		if nodes[i].DeletionTimestamp == nil && nodes[i].Annotations[AnnotationDrainStartedAt] != "" {
+			log.V(1).Info("Holding back MaxLifetime recycling while another node drains", "draining", nodes[i].Name)
+			return drainPollInterval
+		}
+	}
+	if last := nodePool.Status.LastTermination; last != nil && last.Reason == tgpv1.TerminationReasonMaxLifetime {
+		if wait := last.Time.Add(maxLifetimeStaggerInterval).Sub(now); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
+// countWorkloadPods counts pods on the node that still need to complete before it can be drained
+func (r *GPUNodePoolReconciler) countWorkloadPods(ctx context.Context, node *corev1.Node) (int, error) {
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods); err != nil {
+		return 0, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	count := 0
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName != node.Name || pod.DeletionTimestamp != nil {
+			continue
+		}
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if r.isDaemonSetPod(pod) || r.isStaticPod(pod) {
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
+
+// recordEvent emits an event for the object if an event recorder is configured
+func (r *GPUNodePoolReconciler) recordEvent(object runtime.Object, eventType, reason, message string) {
+	if r.Recorder == nil {
+		return
+	}
+	r.Recorder.Event(object, eventType, reason, message)
+}
+
+// expireGracePeriod returns the pool's expiry grace period, defaulting to one hour
+func expireGracePeriod(nodePool *tgpv1.GPUNodePool) time.Duration {
+	if nodePool.Spec.Disruption != nil && nodePool.Spec.Disruption.ExpireGracePeriod != nil {
+		return nodePool.Spec.Disruption.ExpireGracePeriod.Duration
+	}
+	return defaultExpireGracePeriod
+}
+
+// nodeCreationTime returns when the node's instance was created, preferring the tgp.io/created-at annotation
+func nodeCreationTime(node *corev1.Node) time.Time {
+	if createdAt, exists := node.Annotations["tgp.io/created-at"]; exists {
+		if parsed, err := time.Parse(time.RFC3339, createdAt); err == nil {
+			return parsed
+		}
+	}
+	return node.CreationTimestamp.Time
+}
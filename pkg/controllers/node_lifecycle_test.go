@@ -0,0 +1,296 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/config"
+)
+
+func TestReconcileNodeLifecycle_Expiry(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = tgpv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	nodePool := &tgpv1.GPUNodePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pool", Namespace: "default"},
+		Spec: tgpv1.GPUNodePoolSpec{
+			Disruption: &tgpv1.DisruptionSpec{
+				ExpireAfter:       &metav1.Duration{Duration: 24 * time.Hour},
+				ExpireGracePeriod: &metav1.Duration{Duration: time.Hour},
+			},
+		},
+	}
+
+	poolNode := func(name string, age time.Duration) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{"tgp.io/nodepool": "test-pool", tgpv1.NodeLabelNodePoolNamespace: "default"},
+				Annotations: map[string]string{
+					"tgp.io/created-at": time.Now().Add(-age).Format(time.RFC3339),
+				},
+			},
+		}
+	}
+
+	youngNode := poolNode("young", time.Hour)
+	busyNode := poolNode("busy", 23*time.Hour+30*time.Minute)
+	idleNode := poolNode("idle", 23*time.Hour+30*time.Minute)
+	expiredNode := poolNode("expired", 25*time.Hour)
+
+	runningPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "default"},
+		Spec:       corev1.PodSpec{NodeName: "busy"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(nodePool, youngNode, busyNode, idleNode, expiredNode, runningPod).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &GPUNodePoolReconciler{
+		Client:   client,
+		Log:      logr.Discard(),
+		Scheme:   scheme,
+		Recorder: recorder,
+	}
+
+	ctx := context.Background()
+	next, err := reconciler.reconcileNodeLifecycle(ctx, nodePool, logr.Discard())
+	if err != nil {
+		t.Fatalf("reconcileNodeLifecycle failed: %v", err)
+	}
+
+	if next <= 0 || next > expiryPollInterval {
+		t.Errorf("expected next check within %v, got %v", expiryPollInterval, next)
+	}
+
+	var node corev1.Node
+	if err := client.Get(ctx, types.NamespacedName{Name: "young"}, &node); err != nil {
+		t.Fatalf("expected young node to remain: %v", err)
+	}
+	if node.Spec.Unschedulable {
+		t.Error("expected young node to remain schedulable")
+	}
+
+	if err := client.Get(ctx, types.NamespacedName{Name: "busy"}, &node); err != nil {
+		t.Fatalf("expected busy node to remain until expiry: %v", err)
+	}
+	if !node.Spec.Unschedulable {
+		t.Error("expected busy node to be cordoned")
+	}
+	if _, marked := node.Annotations[AnnotationExpiringSince]; !marked {
+		t.Error("expected busy node to be marked as expiring")
+	}
+
+	for _, name := range []string{"idle", "expired"} {
+		err := client.Get(ctx, types.NamespacedName{Name: name}, &node)
+		if !apierrors.IsNotFound(err) {
+			t.Errorf("expected node %s to be terminated, got: %v", name, err)
+		}
+	}
+
+	// busy and idle are cordoned, idle and expired are recycled
+	events := len(recorder.Events)
+	if events != 4 {
+		t.Errorf("expected 4 lifecycle events, got %d", events)
+	}
+}
+
+func TestReconcileNodeLifecycle_TerminationFailure(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = tgpv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	nodePool := &tgpv1.GPUNodePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pool", Namespace: "default"},
+		Spec: tgpv1.GPUNodePoolSpec{
+			Disruption: &tgpv1.DisruptionSpec{
+				ExpireAfter: &metav1.Duration{Duration: 24 * time.Hour},
+			},
+		},
+	}
+	expiredNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "expired",
+			Labels: map[string]string{
+				"tgp.io/nodepool":                "test-pool",
+				tgpv1.NodeLabelNodePoolNamespace: "default",
+				"tgp.io/instance-id":             "expired-instance",
+				tgpv1.NodeLabelProvider:          "vultr",
+			},
+			Annotations: map[string]string{
+				"tgp.io/created-at": time.Now().Add(-25 * time.Hour).Format(time.RFC3339),
+			},
+		},
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(nodePool, expiredNode).
+		Build()
+
+	// Provider credentials are unavailable, so the instance cannot be terminated
+	operatorConfig := config.DefaultConfig()
+	operatorConfig.Providers.Vultr.Enabled = true
+	reconciler := &GPUNodePoolReconciler{
+		Client:   client,
+		Log:      logr.Discard(),
+		Scheme:   scheme,
+		Config:   operatorConfig,
+		Recorder: record.NewFakeRecorder(10),
+	}
+
+	ctx := context.Background()
+	next, err := reconciler.reconcileNodeLifecycle(ctx, nodePool, logr.Discard())
+	if err != nil {
+		t.Fatalf("reconcileNodeLifecycle failed: %v", err)
+	}
+	if next != terminationRetryInterval {
+		t.Errorf("expected termination to be retried after %v, got %v", terminationRetryInterval, next)
+	}
+
+	var node corev1.Node
+	if err := client.Get(ctx, types.NamespacedName{Name: "expired"}, &node); err != nil {
+		t.Fatalf("expected node to be kept when its instance cannot be terminated: %v", err)
+	}
+	if !node.Spec.Unschedulable {
+		t.Error("expected node to remain cordoned")
+	}
+}
+
+func TestReconcileNodeLifecycle_NoExpiry(t *testing.T) {
+	reconciler := &GPUNodePoolReconciler{}
+	next, err := reconciler.reconcileNodeLifecycle(context.Background(), &tgpv1.GPUNodePool{}, logr.Discard())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if next != 0 {
+		t.Errorf("expected no pending transition, got %v", next)
+	}
+}
+
+func TestReconcileNodeLifecycle_MaxLifetime(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = tgpv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	poolNode := func(name string, age time.Duration) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{"tgp.io/nodepool": "test-pool", tgpv1.NodeLabelNodePoolNamespace: "default"},
+				Annotations: map[string]string{
+					"tgp.io/created-at": time.Now().Add(-age).Format(time.RFC3339),
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		disruption  *tgpv1.DisruptionSpec
+		mutate      func(*tgpv1.GPUNodePool)
+		wantRemoved []string
+		wantKept    []string
+		wantNext    time.Duration
+	}{
+		{
+			name:        "oldest aged node is recycled first",
+			wantRemoved: []string{"oldest"},
+			wantKept:    []string{"old", "young"},
+			wantNext:    maxLifetimeStaggerInterval,
+		},
+		{
+			name:        "shorter than ExpireAfter",
+			disruption:  &tgpv1.DisruptionSpec{ExpireAfter: &metav1.Duration{Duration: 48 * time.Hour}},
+			wantRemoved: []string{"oldest"},
+			wantKept:    []string{"old", "young"},
+			wantNext:    maxLifetimeStaggerInterval,
+		},
+		{
+			name:        "ExpireAfter is shorter and recycles every expired node",
+			disruption:  &tgpv1.DisruptionSpec{ExpireAfter: &metav1.Duration{Duration: 20 * time.Hour}},
+			wantRemoved: []string{"oldest", "old"},
+			wantKept:    []string{"young"},
+			wantNext:    20 * time.Hour,
+		},
+		{
+			name: "staggered after a recent recycle",
+			mutate: func(pool *tgpv1.GPUNodePool) {
+				pool.Status.LastTermination = &tgpv1.InstanceTermination{
+					Reason: tgpv1.TerminationReasonMaxLifetime,
+					Time:   metav1.NewTime(time.Now().Add(-time.Minute)),
+				}
+			},
+			wantKept: []string{"oldest", "old", "young"},
+			wantNext: maxLifetimeStaggerInterval,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodePool := &tgpv1.GPUNodePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-pool", Namespace: "default"},
+				Spec: tgpv1.GPUNodePoolSpec{
+					Disruption: tt.disruption,
+					Template: tgpv1.NodePoolTemplate{Spec: tgpv1.NodeSpec{
+						MaxLifetime: &metav1.Duration{Duration: 24 * time.Hour},
+					}},
+				},
+			}
+			if tt.mutate != nil {
+				tt.mutate(nodePool)
+			}
+
+			client := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(nodePool, poolNode("old", 25*time.Hour), poolNode("oldest", 30*time.Hour), poolNode("young", time.Hour)).
+				Build()
+			reconciler := &GPUNodePoolReconciler{
+				Client:   client,
+				Log:      logr.Discard(),
+				Scheme:   scheme,
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			ctx := context.Background()
+			next, err := reconciler.reconcileNodeLifecycle(ctx, nodePool, logr.Discard())
+			if err != nil {
+				t.Fatalf("reconcileNodeLifecycle failed: %v", err)
+			}
+			if next <= 0 || next > tt.wantNext {
+				t.Errorf("expected next check within %v, got %v", tt.wantNext, next)
+			}
+			if nodePool.Status.TerminationScheduledAt == nil {
+				t.Error("expected the next termination to be scheduled")
+			}
+
+			var node corev1.Node
+			for _, name := range tt.wantRemoved {
+				if err := client.Get(ctx, types.NamespacedName{Name: name}, &node); !apierrors.IsNotFound(err) {
+					t.Errorf("expected node %s to be recycled, got: %v", name, err)
+				}
+			}
+			for _, name := range tt.wantKept {
+				if err := client.Get(ctx, types.NamespacedName{Name: name}, &node); err != nil {
+					t.Errorf("expected node %s to be kept: %v", name, err)
+				}
+			}
+		})
+	}
+}
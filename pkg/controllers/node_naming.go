@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// instanceIDHashLength is the number of hex characters of an instance ID hash used in names
+const instanceIDHashLength = 8
+
+var (
+	// invalidNodeNameChars matches characters that may not appear in a node name
+	invalidNodeNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+	// invalidLabelValueChars matches characters that may not appear in a label value
+	invalidLabelValueChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+)
+
+// nodeNameSuffix returns the part of a node's name derived from its instance ID: the first
+// eight characters of a plain ID, or a hash of IDs scoped by region, zone or resource group,
+// which would otherwise share their scope as prefix
+func nodeNameSuffix(instanceID string) string {
+	if !strings.Contains(instanceID, "/") {
+		suffix := strings.ToLower(instanceID[:min(len(instanceID), 8)])
+		if suffix = strings.Trim(invalidNodeNameChars.ReplaceAllString(suffix, "-"), "-"); suffix != "" {
+			return suffix
+		}
+	}
+	return instanceIDHash(instanceID)
+}
+
+// instanceIDLabelValue returns the tgp.io/instance-id label value of a node. IDs scoped by
+// region or resource group contain "/" and may be longer than a label value allows, so they
+// are reduced to the provider-local ID, truncated and suffixed with a hash of the full ID when
+// still too long. The full ID is kept in the tgp.io/instance-id annotation.
+func instanceIDLabelValue(instanceID string) string {
+	if len(validation.IsValidLabelValue(instanceID)) == 0 {
+		return instanceID
+	}
+
+	local := instanceID[strings.LastIndex(instanceID, "/")+1:]
+	local = invalidLabelValueChars.ReplaceAllString(local, "-")
+	if len(validation.IsValidLabelValue(local)) == 0 {
+		return local
+	}
+
+	hash := instanceIDHash(instanceID)
+	local = local[:min(len(local), validation.LabelValueMaxLength-len(hash)-1)]
+	if local = strings.Trim(local, "-_."); local == "" {
+		return hash
+	}
+	return local + "-" + hash
+}
+
+// instanceIDHash returns a short hash of an instance ID for use in names and label values
+func instanceIDHash(instanceID string) string {
+	sum := sha256.Sum256([]byte(instanceID))
+	return hex.EncodeToString(sum[:])[:instanceIDHashLength]
+}
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func TestNodeNameSuffix(t *testing.T) {
+	tests := []struct {
+		name       string
+		instanceID string
+		want       string
+	}{
+		{name: "plain ID", instanceID: "6f1b2c3d-aaaa-bbbb-cccc-000000000000", want: "6f1b2c3d"},
+		{name: "short ID", instanceID: "12345", want: "12345"},
+		{name: "mixed case", instanceID: "Tgp_Node-1", want: "tgp-node"},
+		{name: "AWS region scoped", instanceID: "us-east-1/i-0abc123def4567890", want: instanceIDHash("us-east-1/i-0abc123def4567890")},
+		{name: "Azure resource group scoped", instanceID: "tgp-rg/tgp-burst-abc12", want: instanceIDHash("tgp-rg/tgp-burst-abc12")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nodeNameSuffix(tt.instanceID)
+			if got != tt.want {
+				t.Errorf("nodeNameSuffix(%q) = %q, want %q", tt.instanceID, got, tt.want)
+			}
+			if errs := validation.IsDNS1123Subdomain("tgp-pool-" + got); len(errs) > 0 {
+				t.Errorf("node name for %q is invalid: %v", tt.instanceID, errs)
+			}
+		})
+	}
+
+	// Instances in the same region get distinct names
+	if nodeNameSuffix("us-east-1/i-0aaaaaaaaaaaaaaaa") == nodeNameSuffix("us-east-1/i-0bbbbbbbbbbbbbbbb") {
+		t.Error("expected region scoped instances to get distinct node name suffixes")
+	}
+}
+
+func TestInstanceIDLabelValue(t *testing.T) {
+	ocid := "us-ashburn-1/ocid1.instance.oc1.iad.anuwcljrbnqp2fyc7ln3dkq7xjyu2hbjjlnmz6gkqsvvvp5ytzhcyqzfz5ha"
+
+	tests := []struct {
+		name       string
+		instanceID string
+		want       string
+	}{
+		{name: "plain ID is kept", instanceID: "6f1b2c3d-aaaa-bbbb-cccc-000000000000", want: "6f1b2c3d-aaaa-bbbb-cccc-000000000000"},
+		{name: "AWS region is dropped", instanceID: "us-east-1/i-0abc123def4567890", want: "i-0abc123def4567890"},
+		{name: "Azure resource group is dropped", instanceID: "tgp-rg/tgp-burst-abc12", want: "tgp-burst-abc12"},
+		{name: "long OCI ID is truncated and hashed", instanceID: ocid,
+			want: "ocid1.instance.oc1.iad.anuwcljrbnqp2fyc7ln3dkq7xjyu2hb-" + instanceIDHash(ocid)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := instanceIDLabelValue(tt.instanceID)
+			if got != tt.want {
+				t.Errorf("instanceIDLabelValue(%q) = %q, want %q", tt.instanceID, got, tt.want)
+			}
+			if errs := validation.IsValidLabelValue(got); len(errs) > 0 {
+				t.Errorf("label value %q is invalid: %s", got, strings.Join(errs, "; "))
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+const (
+	// AnnotationAwaitingReady marks a node the operator created cordoned, to be made
+	// schedulable once the instance's kubelet reports Ready. Nodes cordoned for any other
+	// reason, such as expiry, do not carry it and stay cordoned.
+	AnnotationAwaitingReady = "tgp.io/awaiting-ready"
+
+	// EventReasonNodeReady is emitted when a launched node joins and is made schedulable
+	EventReasonNodeReady = "NodeReady"
+
+	// nodeReadyPollInterval is how often nodes waiting for their kubelet are re-checked
+	nodeReadyPollInterval = 30 * time.Second
+)
+
+// reconcileNodeReadiness makes the pool's newly launched nodes schedulable once their kubelet
+// reports Ready and they pass the pool's readiness probe, if any. A kubelet registering under
+// the operator-created node's name takes that node over, which is then uncordoned. A kubelet
+// registering under another name is matched to the placeholder node by address; the
+// placeholder's labels, annotations, taints and owner are moved to the registered node and
+// the placeholder is deleted. It returns how long until a waiting node needs to be checked
+// again, or 0 if none does.
+func (r *GPUNodePoolReconciler) reconcileNodeReadiness(ctx context.Context, nodePool *tgpv1.GPUNodePool, log logr.Logger) (time.Duration, error) {
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes, poolNodeSelector(nodePool)); err != nil {
+		return 0, fmt.Errorf("failed to list nodes for pool %s: %w", nodePool.Name, err)
+	}
+
+	var registered *corev1.NodeList
+	var next time.Duration
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if node.DeletionTimestamp != nil || node.Annotations[AnnotationAwaitingReady] == "" {
+			continue
+		}
+
+		// Nodes stay cordoned until the data disks attached after launch are in place
+		if node.Annotations[AnnotationPendingDataDisks] != "" {
+			log.V(1).Info("Node is waiting for its data disks", "node", node.Name)
+			next = nodeReadyPollInterval
+			continue
+		}
+
+		if isNodeReady(node) {
+			if err := probeNodeReadiness(ctx, nodePool, node); err != nil {
+				log.V(1).Info("Node is ready but failed its readiness probe", "node", node.Name, "reason", err.Error())
+				next = nodeReadyPollInterval
+				continue
+			}
+			if err := r.uncordonReadyNode(ctx, nodePool, node, log); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		// Nodes registered by a kubelet under its own name are only listed when needed
+		if registered == nil {
+			registered = &corev1.NodeList{}
+			if err := r.List(ctx, registered); err != nil {
+				return 0, fmt.Errorf("failed to list nodes: %w", err)
+			}
+		}
+		if real := findRegisteredNode(node, registered.Items); real != nil {
+			if err := probeNodeReadiness(ctx, nodePool, real); err != nil {
+				log.V(1).Info("Registered node failed its readiness probe", "node", real.Name, "reason", err.Error())
+				next = nodeReadyPollInterval
+				continue
+			}
+			if err := r.adoptRegisteredNode(ctx, nodePool, node, real, log); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		next = nodeReadyPollInterval
+	}
+	return next, nil
+}
+
+// uncordonReadyNode makes a node whose kubelet reports Ready schedulable
+func (r *GPUNodePoolReconciler) uncordonReadyNode(ctx context.Context, nodePool *tgpv1.GPUNodePool, node *corev1.Node, log logr.Logger) error {
+	delete(node.Annotations, AnnotationAwaitingReady)
+	node.Spec.Unschedulable = false
+	if err := r.Update(ctx, node); err != nil {
+		return fmt.Errorf("failed to uncordon ready node %s: %w", node.Name, err)
+	}
+
+	log.Info("Node is ready, made schedulable", "node", node.Name)
+	r.recordEvent(nodePool, corev1.EventTypeNormal, EventReasonNodeReady,
+		fmt.Sprintf("Node %s joined and is schedulable", node.Name))
+	return nil
+}
+
+// adoptRegisteredNode moves the pool's labels, annotations, taints and ownership from a
+// placeholder node to the Ready node its instance's kubelet registered, then deletes the
+// placeholder. Labels and annotations the kubelet set are kept.
+func (r *GPUNodePoolReconciler) adoptRegisteredNode(ctx context.Context, nodePool *tgpv1.GPUNodePool, placeholder, registered *corev1.Node, log logr.Logger) error {
+	if registered.Labels == nil {
+		registered.Labels = make(map[string]string)
+	}
+	for key, value := range placeholder.Labels {
+		if _, exists := registered.Labels[key]; !exists {
+			registered.Labels[key] = value
+		}
+	}
+	if registered.Annotations == nil {
+		registered.Annotations = make(map[string]string)
+	}
+	for key, value := range placeholder.Annotations {
+		if _, exists := registered.Annotations[key]; !exists && key != AnnotationAwaitingReady {
+			registered.Annotations[key] = value
+		}
+	}
+	for _, taint := range placeholder.Spec.Taints {
+		if !hasTaint(registered.Spec.Taints, taint) {
+			registered.Spec.Taints = append(registered.Spec.Taints, taint)
+		}
+	}
+	for _, owner := range placeholder.OwnerReferences {
+		if !slices.ContainsFunc(registered.OwnerReferences, func(existing metav1.OwnerReference) bool { return existing.UID == owner.UID }) {
+			registered.OwnerReferences = append(registered.OwnerReferences, owner)
+		}
+	}
+	if err := r.Update(ctx, registered); err != nil {
+		return fmt.Errorf("failed to adopt registered node %s: %w", registered.Name, err)
+	}
+
+	if err := r.Delete(ctx, placeholder); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete placeholder node %s: %w", placeholder.Name, err)
+	}
+
+	log.Info("Kubelet registered under its own name, replaced placeholder node",
+		"node", registered.Name, "placeholder", placeholder.Name)
+	r.recordEvent(nodePool, corev1.EventTypeNormal, EventReasonNodeReady,
+		fmt.Sprintf("Node %s joined for placeholder %s and is schedulable", registered.Name, placeholder.Name))
+	return nil
+}
+
+// findRegisteredNode returns the Ready node, not yet part of any pool, that shares an address
+// with the placeholder node, or nil if the instance's kubelet has not registered yet
+func findRegisteredNode(placeholder *corev1.Node, nodes []corev1.Node) *corev1.Node {
+	addresses := make(map[string]bool)
+	for _, address := range placeholder.Status.Addresses {
+		if address.Address != "" {
+			addresses[address.Address] = true
+		}
+	}
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	for i := range nodes {
+		node := &nodes[i]
+		if node.Name == placeholder.Name || node.DeletionTimestamp != nil || !isNodeReady(node) {
+			continue
+		}
+		if _, pooled := node.Labels["tgp.io/nodepool"]; pooled {
+			continue
+		}
+		for _, address := range node.Status.Addresses {
+			if addresses[address.Address] {
+				return node
+			}
+		}
+	}
+	return nil
+}
+
+// hasTaint reports whether taints contains a taint with the same key and effect
+func hasTaint(taints []corev1.Taint, taint corev1.Taint) bool {
+	for _, existing := range taints {
+		if existing.Key == taint.Key && existing.Effect == taint.Effect {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+// launchedNode returns a cordoned node of test-pool as created at launch, with the given
+// Ready status and address
+func launchedNode(name string, ready corev1.ConditionStatus, address string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      map[string]string{"tgp.io/nodepool": "test-pool", tgpv1.NodeLabelNodePoolNamespace: "default", tgpv1.NodeLabelGPUType: "H100"},
+			Annotations: map[string]string{AnnotationAwaitingReady: "true", "tgp.io/instance-id": "i-123"},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: tgpv1.GroupVersion.String(), Kind: "GPUNodePool", Name: "test-pool", UID: "pool-uid",
+			}},
+		},
+		Spec: corev1.NodeSpec{
+			Unschedulable: true,
+			Taints:        []corev1.Taint{{Key: "nvidia.com/gpu", Effect: corev1.TaintEffectNoSchedule}},
+		},
+		Status: corev1.NodeStatus{
+			Addresses:  []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: address}},
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+		},
+	}
+}
+
+func TestReconcileNodeReadiness(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = tgpv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	// The kubelet took over the node created for it
+	joined := launchedNode("joined", corev1.ConditionTrue, "10.0.0.1")
+
+	// Cordoned for expiry, not waiting for its kubelet
+	expiring := launchedNode("expiring", corev1.ConditionTrue, "10.0.0.2")
+	delete(expiring.Annotations, AnnotationAwaitingReady)
+
+	// The kubelet registered under its own name
+	placeholder := launchedNode("placeholder", corev1.ConditionFalse, "10.0.0.3")
+	registered := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "talos-abc", Labels: map[string]string{"kubernetes.io/hostname": "talos-abc"}},
+		Status: corev1.NodeStatus{
+			Addresses:  []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: "10.0.0.3"}},
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+
+	nodePool := &tgpv1.GPUNodePool{ObjectMeta: metav1.ObjectMeta{Name: "test-pool", Namespace: "default", UID: "pool-uid"}}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(joined, expiring, placeholder, registered).Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &GPUNodePoolReconciler{Client: client, Scheme: scheme, Recorder: recorder}
+
+	ctx := context.Background()
+	next, err := r.reconcileNodeReadiness(ctx, nodePool, logr.Discard())
+	if err != nil {
+		t.Fatalf("reconcileNodeReadiness failed: %v", err)
+	}
+	if next != 0 {
+		t.Errorf("expected no node left waiting, got recheck in %s", next)
+	}
+
+	var node corev1.Node
+	if err := client.Get(ctx, types.NamespacedName{Name: "joined"}, &node); err != nil {
+		t.Fatalf("failed to get joined node: %v", err)
+	}
+	if node.Spec.Unschedulable || node.Annotations[AnnotationAwaitingReady] != "" {
+		t.Errorf("expected the ready node to be uncordoned, got unschedulable=%v annotations=%v", node.Spec.Unschedulable, node.Annotations)
+	}
+
+	if err := client.Get(ctx, types.NamespacedName{Name: "expiring"}, &node); err != nil {
+		t.Fatalf("failed to get expiring node: %v", err)
+	}
+	if !node.Spec.Unschedulable {
+		t.Error("expected a node cordoned for another reason to stay cordoned")
+	}
+
+	if err := client.Get(ctx, types.NamespacedName{Name: "placeholder"}, &corev1.Node{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the placeholder node to be deleted, got: %v", err)
+	}
+	if err := client.Get(ctx, types.NamespacedName{Name: "talos-abc"}, &node); err != nil {
+		t.Fatalf("failed to get registered node: %v", err)
+	}
+	if node.Labels["tgp.io/nodepool"] != "test-pool" || node.Labels["kubernetes.io/hostname"] != "talos-abc" {
+		t.Errorf("expected pool labels alongside the kubelet's, got %v", node.Labels)
+	}
+	if node.Annotations["tgp.io/instance-id"] != "i-123" || node.Annotations[AnnotationAwaitingReady] != "" {
+		t.Errorf("expected the placeholder's annotations without the readiness marker, got %v", node.Annotations)
+	}
+	if len(node.Spec.Taints) != 1 || len(node.OwnerReferences) != 1 || node.OwnerReferences[0].Name != "test-pool" {
+		t.Errorf("expected the template taint and pool ownership, got taints %v owners %v", node.Spec.Taints, node.OwnerReferences)
+	}
+
+	if len(recorder.Events) != 2 {
+		t.Errorf("expected a NodeReady event for each joined node, got %d", len(recorder.Events))
+	}
+}
+
+func TestReconcileNodeReadinessWaiting(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = tgpv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	waiting := launchedNode("waiting", corev1.ConditionFalse, "10.0.0.4")
+	// Ready, but already part of a pool
+	other := launchedNode("other", corev1.ConditionTrue, "10.0.0.4")
+	other.Labels["tgp.io/nodepool"] = "other-pool"
+
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(waiting, other).Build()
+	r := &GPUNodePoolReconciler{Client: client, Scheme: scheme}
+
+	nodePool := &tgpv1.GPUNodePool{ObjectMeta: metav1.ObjectMeta{Name: "test-pool", Namespace: "default"}}
+	next, err := r.reconcileNodeReadiness(context.Background(), nodePool, logr.Discard())
+	if err != nil {
+		t.Fatalf("reconcileNodeReadiness failed: %v", err)
+	}
+	if next != nodeReadyPollInterval {
+		t.Errorf("expected a recheck in %s while the kubelet has not joined, got %s", nodeReadyPollInterval, next)
+	}
+
+	var node corev1.Node
+	if err := client.Get(context.Background(), types.NamespacedName{Name: "waiting"}, &node); err != nil {
+		t.Fatalf("failed to get waiting node: %v", err)
+	}
+	if !node.Spec.Unschedulable {
+		t.Error("expected the node to stay cordoned until its kubelet is ready")
+	}
+}
@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/config"
+)
+
+// defaultOrphanSweepInterval is how often orphaned nodes are swept when no interval is configured
+const defaultOrphanSweepInterval = 10 * time.Minute
+
+// OrphanNodeReaper periodically finds nodes launched by the operator whose GPUNodePool
+// no longer exists, for example because the pool was force-deleted with its finalizer
+// removed, then terminates their cloud instances and deletes the nodes.
+type OrphanNodeReaper struct {
+	client.Client
+	Log logr.Logger
+
+	// NodePools provides node cleanup and instance termination
+	NodePools *GPUNodePoolReconciler
+
+	// OperatorNamespace is used to resolve provider credentials
+	OperatorNamespace string
+
+	// Config provides the orphanReaper settings: whether the sweep runs, how often, and
+	// whether it only logs orphaned nodes without terminating or deleting them
+	Config config.Source
+}
+
+// Start runs the sweep until the context is cancelled. The settings are read before each
+// sweep, so enabling the reaper or changing its interval takes effect without a restart.
+func (r *OrphanNodeReaper) Start(ctx context.Context) error {
+	r.Log.Info("Starting orphan node reaper")
+
+	for {
+		settings := config.Current(r.Config).OrphanReaper
+		if settings.Enabled {
+			if _, err := r.Sweep(ctx); err != nil {
+				r.Log.Error(err, "Orphan node sweep failed")
+			}
+		}
+
+		interval := settings.Interval
+		if interval <= 0 {
+			interval = defaultOrphanSweepInterval
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// NeedLeaderElection ensures only the elected manager reaps nodes
+func (r *OrphanNodeReaper) NeedLeaderElection() bool {
+	return true
+}
+
+// Sweep finds orphaned nodes and reaps them unless running in dry-run mode.
+// It returns the names of the orphaned nodes that were found.
+func (r *OrphanNodeReaper) Sweep(ctx context.Context) ([]string, error) {
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes, client.HasLabels{"tgp.io/nodepool", "tgp.io/instance-id"}); err != nil {
+		return nil, fmt.Errorf("failed to list operator nodes: %w", err)
+	}
+
+	dryRun := config.Current(r.Config).OrphanReaper.DryRun
+	var orphans []string
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if node.DeletionTimestamp != nil {
+			continue
+		}
+
+		exists, err := r.nodePoolExists(ctx, node)
+		if err != nil {
+			r.Log.Error(err, "Failed to look up owning node pool", "node", node.Name)
+			continue
+		}
+		if exists {
+			continue
+		}
+
+		orphans = append(orphans, node.Name)
+		instanceID, _ := nodeInstance(node)
+		log := r.Log.WithValues("node", node.Name, "nodePool", node.Labels["tgp.io/nodepool"], "instanceID", instanceID)
+
+		if dryRun {
+			log.Info("Found orphaned node (dry run, not reaping)")
+			continue
+		}
+
+		log.Info("Reaping orphaned node")
+
+		// The node is kept if termination fails so the instance ID is not lost
+		if err := r.NodePools.cleanupNode(ctx, node, r.OperatorNamespace, tgpv1.TerminationReasonOrphaned, log); err != nil {
+			log.Error(err, "Failed to clean up orphaned node")
+		}
+	}
+
+	return orphans, nil
+}
+
+// nodePoolExists checks whether the GPUNodePool that launched the node still exists.
+// Nodes without a namespace label are matched against pools in any namespace.
+func (r *OrphanNodeReaper) nodePoolExists(ctx context.Context, node *corev1.Node) (bool, error) {
+	poolName := node.Labels["tgp.io/nodepool"]
+
+	if namespace, ok := node.Labels[tgpv1.NodeLabelNodePoolNamespace]; ok {
+		var nodePool tgpv1.GPUNodePool
+		err := r.Get(ctx, types.NamespacedName{Name: poolName, Namespace: namespace}, &nodePool)
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	var nodePools tgpv1.GPUNodePoolList
+	if err := r.List(ctx, &nodePools); err != nil {
+		return false, fmt.Errorf("failed to list node pools: %w", err)
+	}
+	for _, nodePool := range nodePools.Items {
+		if nodePool.Name == poolName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SetupWithManager registers the reaper to run with the manager
+func (r *OrphanNodeReaper) SetupWithManager(mgr ctrl.Manager) error {
+	return mgr.Add(r)
+}
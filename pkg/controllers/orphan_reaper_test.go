@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/config"
+)
+
+func TestOrphanNodeReaper_Sweep(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = tgpv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	nodePool := &tgpv1.GPUNodePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "live-pool", Namespace: "default"},
+	}
+
+	poolNode := func(name string, labels map[string]string) *corev1.Node {
+		labels["tgp.io/instance-id"] = name + "-instance"
+		labels[tgpv1.NodeLabelProvider] = "vultr"
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+
+	ownedNode := poolNode("owned", map[string]string{
+		"tgp.io/nodepool":                "live-pool",
+		tgpv1.NodeLabelNodePoolNamespace: "default",
+	})
+	legacyNode := poolNode("legacy", map[string]string{
+		"tgp.io/nodepool": "live-pool",
+	})
+	orphanNode := poolNode("orphan", map[string]string{
+		"tgp.io/nodepool":                "deleted-pool",
+		tgpv1.NodeLabelNodePoolNamespace: "default",
+	})
+	wrongNamespaceNode := poolNode("wrong-namespace", map[string]string{
+		"tgp.io/nodepool":                "live-pool",
+		tgpv1.NodeLabelNodePoolNamespace: "other",
+	})
+	unmanagedNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "unmanaged"}}
+
+	tests := []struct {
+		name          string
+		dryRun        bool
+		expectOrphans []string
+		expectNodes   []string
+	}{
+		{
+			name:          "dry run only reports orphans",
+			dryRun:        true,
+			expectOrphans: []string{"orphan", "wrong-namespace"},
+			expectNodes:   []string{"owned", "legacy", "orphan", "wrong-namespace", "unmanaged"},
+		},
+		{
+			// Provider credentials are unavailable, so termination fails and the nodes are kept
+			name:          "nodes are kept when the instance cannot be terminated",
+			dryRun:        false,
+			expectOrphans: []string{"orphan", "wrong-namespace"},
+			expectNodes:   []string{"owned", "legacy", "orphan", "wrong-namespace", "unmanaged"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(nodePool, ownedNode.DeepCopy(), legacyNode.DeepCopy(), orphanNode.DeepCopy(),
+					wrongNamespaceNode.DeepCopy(), unmanagedNode.DeepCopy()).
+				Build()
+
+			reaper := &OrphanNodeReaper{
+				Client: client,
+				Log:    logr.Discard(),
+				NodePools: &GPUNodePoolReconciler{
+					Client: client,
+					Log:    logr.Discard(),
+					Scheme: scheme,
+					Config: config.DefaultConfig(),
+				},
+				OperatorNamespace: "tgp-system",
+				Config:            &config.OperatorConfig{OrphanReaper: config.OrphanReaperConfig{DryRun: tt.dryRun}},
+			}
+
+			orphans, err := reaper.Sweep(context.Background())
+			if err != nil {
+				t.Fatalf("Sweep() error = %v", err)
+			}
+
+			if len(orphans) != len(tt.expectOrphans) {
+				t.Fatalf("Sweep() orphans = %v, want %v", orphans, tt.expectOrphans)
+			}
+			for _, name := range tt.expectOrphans {
+				if !containsString(orphans, name) {
+					t.Errorf("Sweep() orphans = %v, missing %s", orphans, name)
+				}
+			}
+
+			for _, name := range tt.expectNodes {
+				var node corev1.Node
+				if err := client.Get(context.Background(), types.NamespacedName{Name: name}, &node); err != nil {
+					t.Errorf("expected node %s to remain: %v", name, err)
+				}
+			}
+		})
+	}
+}
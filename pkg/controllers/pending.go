@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+// EventReasonMaxPendingDurationExceeded is emitted when pending pods could not be provisioned in time
+const EventReasonMaxPendingDurationExceeded = tgpv1.ReadyReasonMaxPendingDurationExceeded
+
+// pendingTimeoutRetryInterval is how often provisioning is retried once MaxPendingDuration is exceeded
+const pendingTimeoutRetryInterval = 10 * time.Minute
+
+// trackPendingProvisioning records when provisioning started failing in the pool status and
+// reports whether the pool's MaxPendingDuration has been exceeded
+func trackPendingProvisioning(nodePool *tgpv1.GPUNodePool, provisionErr error, now time.Time) bool {
+	if provisionErr == nil {
+		nodePool.Status.PendingSince = nil
+		return false
+	}
+
+	if nodePool.Status.PendingSince == nil {
+		pendingSince := metav1.NewTime(now)
+		nodePool.Status.PendingSince = &pendingSince
+	}
+
+	if nodePool.Spec.MaxPendingDuration == nil {
+		return false
+	}
+	return now.Sub(nodePool.Status.PendingSince.Time) >= nodePool.Spec.MaxPendingDuration.Duration
+}
+
+// markPendingTimeout marks the pool as failing to provision and emits a warning event on the
+// transition. The pool then retries every pendingTimeoutRetryInterval instead of every few seconds.
+func (r *GPUNodePoolReconciler) markPendingTimeout(nodePool *tgpv1.GPUNodePool, provisionErr error, log logr.Logger) {
+	message := fmt.Sprintf("Pending pods could not be provisioned within %s: %v",
+		nodePool.Spec.MaxPendingDuration.Duration, provisionErr)
+
+	ready := meta.FindStatusCondition(nodePool.Status.Conditions, tgpv1.ConditionTypeReady)
+	if ready == nil || ready.Reason != tgpv1.ReadyReasonMaxPendingDurationExceeded {
+		log.Info("Maximum pending duration exceeded", "pendingSince", nodePool.Status.PendingSince.Time, "error", provisionErr.Error())
+		r.recordEvent(nodePool, corev1.EventTypeWarning, EventReasonMaxPendingDurationExceeded, message)
+	}
+
+	r.updateCondition(nodePool, tgpv1.ConditionTypeReady, metav1.ConditionFalse, tgpv1.ReadyReasonMaxPendingDurationExceeded, message)
+}
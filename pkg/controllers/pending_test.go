@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+func TestTrackPendingProvisioning(t *testing.T) {
+	now := time.Now()
+	provisionErr := errors.New("no capacity")
+
+	tests := []struct {
+		name              string
+		maxPending        *metav1.Duration
+		pendingSince      *time.Time
+		err               error
+		expectExceeded    bool
+		expectPendingFrom *time.Time
+	}{
+		{
+			name:              "first failure starts tracking",
+			maxPending:        &metav1.Duration{Duration: time.Hour},
+			err:               provisionErr,
+			expectPendingFrom: &now,
+		},
+		{
+			name:              "failure within limit is not exceeded",
+			maxPending:        &metav1.Duration{Duration: time.Hour},
+			pendingSince:      timePtr(now.Add(-30 * time.Minute)),
+			err:               provisionErr,
+			expectPendingFrom: timePtr(now.Add(-30 * time.Minute)),
+		},
+		{
+			name:              "failure beyond limit is exceeded",
+			maxPending:        &metav1.Duration{Duration: time.Hour},
+			pendingSince:      timePtr(now.Add(-2 * time.Hour)),
+			err:               provisionErr,
+			expectExceeded:    true,
+			expectPendingFrom: timePtr(now.Add(-2 * time.Hour)),
+		},
+		{
+			name:              "no limit never exceeds",
+			pendingSince:      timePtr(now.Add(-48 * time.Hour)),
+			err:               provisionErr,
+			expectPendingFrom: timePtr(now.Add(-48 * time.Hour)),
+		},
+		{
+			name:         "success clears tracking",
+			maxPending:   &metav1.Duration{Duration: time.Hour},
+			pendingSince: timePtr(now.Add(-2 * time.Hour)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodePool := &tgpv1.GPUNodePool{Spec: tgpv1.GPUNodePoolSpec{MaxPendingDuration: tt.maxPending}}
+			if tt.pendingSince != nil {
+				pendingSince := metav1.NewTime(*tt.pendingSince)
+				nodePool.Status.PendingSince = &pendingSince
+			}
+
+			exceeded := trackPendingProvisioning(nodePool, tt.err, now)
+			if exceeded != tt.expectExceeded {
+				t.Errorf("trackPendingProvisioning() = %v, want %v", exceeded, tt.expectExceeded)
+			}
+
+			if tt.expectPendingFrom == nil {
+				if nodePool.Status.PendingSince != nil {
+					t.Errorf("expected PendingSince to be cleared, got %v", nodePool.Status.PendingSince)
+				}
+				return
+			}
+			if nodePool.Status.PendingSince == nil || !nodePool.Status.PendingSince.Time.Equal(*tt.expectPendingFrom) {
+				t.Errorf("PendingSince = %v, want %v", nodePool.Status.PendingSince, *tt.expectPendingFrom)
+			}
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
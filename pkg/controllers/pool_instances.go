@@ -0,0 +1,251 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// instanceLaunchTimeout bounds how long a pod waits on an instance launched for it before
+// another one may be launched
+const instanceLaunchTimeout = 30 * time.Minute
+
+// trackLaunchedInstance records a freshly launched instance in the pool status and persists it
+// right away, so a controller restart before its node is created resumes instead of relaunching.
+// The instance stays in the launch registry until its node exists, so a shutdown can settle it.
+func (r *GPUNodePoolReconciler) trackLaunchedInstance(ctx context.Context, nodePool *tgpv1.GPUNodePool, pod *corev1.Pod, providerName string, providerClient providers.ProviderClient, requirement *GPURequirement, instance *providers.GPUInstance, log logr.Logger) {
+	tracked := tgpv1.PoolInstance{
+		InstanceID: instance.ID,
+		Provider:   providerName,
+		Phase:      tgpv1.PoolInstancePhaseLaunched,
+		Pod:        pod.Namespace + "/" + pod.Name,
+		GPUType:    requirement.GPUType,
+		GPUCount:   int32(max(requirement.GPUCount, 1)),
+		Region:     requirement.Region,
+		Spot:       instance.IsSpot,
+		LaunchedAt: metav1.NewTime(instance.CreatedAt),
+	}
+	if tracked.LaunchedAt.IsZero() {
+		tracked.LaunchedAt = metav1.Now()
+	}
+	if requirement.HourlyPrice > 0 {
+		tracked.PricePerHour = strconv.FormatFloat(requirement.HourlyPrice, 'f', 4, 64)
+	}
+	if instance.IsSpot && requirement.SpotSavings > 0 {
+		tracked.SpotHourlySavings = strconv.FormatFloat(requirement.SpotSavings, 'f', 4, 64)
+	}
+	nodePool.Status.Instances = append(nodePool.Status.Instances, tracked)
+	countCapacityTypes(nodePool)
+	r.launches.add(&inFlightLaunch{pool: client.ObjectKeyFromObject(nodePool), instance: tracked, client: providerClient})
+
+	if err := r.Status().Update(ctx, nodePool); err != nil {
+		log.Error(err, "Failed to persist launched instance", "instanceID", instance.ID)
+		return
+	}
+	r.launches.markPersisted(instance.ID)
+}
+
+// setInstancePhase moves a tracked instance to the given phase
+func setInstancePhase(nodePool *tgpv1.GPUNodePool, instanceID string, phase tgpv1.PoolInstancePhase) {
+	for i := range nodePool.Status.Instances {
+		if nodePool.Status.Instances[i].InstanceID == instanceID {
+			nodePool.Status.Instances[i].Phase = phase
+			return
+		}
+	}
+}
+
+// untrackInstance drops an instance from the pool status
+func untrackInstance(nodePool *tgpv1.GPUNodePool, instanceID string) {
+	nodePool.Status.Instances = slices.DeleteFunc(nodePool.Status.Instances, func(tracked tgpv1.PoolInstance) bool {
+		return tracked.InstanceID == instanceID
+	})
+	countCapacityTypes(nodePool)
+}
+
+// countCapacityTypes records how many of the pool's tracked instances run on spot and on-demand capacity
+func countCapacityTypes(nodePool *tgpv1.GPUNodePool) {
+	nodePool.Status.SpotInstances, nodePool.Status.OnDemandInstances = 0, 0
+	for _, tracked := range nodePool.Status.Instances {
+		if tracked.Spot {
+			nodePool.Status.SpotInstances++
+		} else {
+			nodePool.Status.OnDemandInstances++
+		}
+	}
+}
+
+// reconcileInstances brings the pool's tracked instances in line with its nodes. Launched
+// instances without a node, left behind by a restart between launch and node creation, get
+// their node created; instances whose node was removed are dropped; nodes launched before
+// tracking existed are adopted. The spot savings of dropped instances are realized and returned
+// for recording once the status is persisted. It is safe to run on every reconcile.
+func (r *GPUNodePoolReconciler) reconcileInstances(ctx context.Context, nodePool *tgpv1.GPUNodePool, nodeClass *tgpv1.GPUNodeClass, log logr.Logger) ([]spotSavings, error) {
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes, poolNodeSelector(nodePool)); err != nil {
+		return nil, fmt.Errorf("failed to list nodes for pool %s: %w", nodePool.Name, err)
+	}
+
+	nodesByInstance := make(map[string]*corev1.Node, len(nodes.Items))
+	for i := range nodes.Items {
+		if instanceID, _ := nodeInstance(&nodes.Items[i]); instanceID != "" {
+			nodesByInstance[instanceID] = &nodes.Items[i]
+		}
+	}
+
+	now := time.Now()
+	var realized []spotSavings
+	instances := make([]tgpv1.PoolInstance, 0, len(nodePool.Status.Instances))
+	for _, tracked := range nodePool.Status.Instances {
+		node, exists := nodesByInstance[tracked.InstanceID]
+		delete(nodesByInstance, tracked.InstanceID)
+
+		if !exists {
+			if tracked.Phase != tgpv1.PoolInstancePhaseLaunched {
+				// The node was removed along with its instance
+				realized = append(realized, realizeSpotSavings(nodePool, tracked, now))
+				continue
+			}
+			resumed, err := r.resumeLaunchedInstance(ctx, nodePool, nodeClass, tracked, log)
+			switch {
+			case err != nil:
+				// Keep the instance so the next reconcile retries
+				log.Error(err, "Failed to resume launched instance", "instanceID", tracked.InstanceID)
+			case !resumed:
+				realized = append(realized, realizeSpotSavings(nodePool, tracked, now))
+				continue
+			default:
+				tracked.Phase = tgpv1.PoolInstancePhaseRegistered
+			}
+			instances = append(instances, tracked)
+			continue
+		}
+
+		tracked.NodeName = node.Name
+		tracked.Phase = nodeInstancePhase(node)
+		if tracked.SpotHourlySavings == "" {
+			tracked.SpotHourlySavings = node.Annotations[AnnotationSpotHourlySavings]
+		}
+		instances = append(instances, tracked)
+	}
+
+	// Adopt nodes launched before their instances were tracked
+	for instanceID, node := range nodesByInstance {
+		instances = append(instances, untrackedInstance(instanceID, node))
+	}
+	slices.SortStableFunc(instances, func(a, b tgpv1.PoolInstance) int {
+		return a.LaunchedAt.Compare(b.LaunchedAt.Time)
+	})
+
+	nodePool.Status.Instances = instances
+	countCapacityTypes(nodePool)
+	updateEstimatedSpotSavings(nodePool, now)
+	return realized, nil
+}
+
+// resumeLaunchedInstance creates the node of an instance launched before a restart. It reports
+// false without an error when the instance no longer exists, so it can be dropped.
+func (r *GPUNodePoolReconciler) resumeLaunchedInstance(ctx context.Context, nodePool *tgpv1.GPUNodePool, nodeClass *tgpv1.GPUNodeClass, tracked tgpv1.PoolInstance, log logr.Logger) (bool, error) {
+	providerClient, err := r.providerClientForClass(ctx, nodeClass, tracked.Provider)
+	if err == nil {
+		err = providers.SelectAccount(providerClient, nodePool.Spec.Account)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to create provider client: %w", err)
+	}
+
+	status, err := providerClient.GetInstanceStatus(ctx, tracked.InstanceID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get instance status: %w", err)
+	}
+	if status.State == providers.InstanceStateTerminated || status.State == providers.InstanceStateFailed {
+		log.Info("Launched instance is gone, no longer tracking it", "instanceID", tracked.InstanceID, "state", status.State)
+		return false, nil
+	}
+
+	requirement := &GPURequirement{
+		GPUType:  tracked.GPUType,
+		GPUCount: int(tracked.GPUCount),
+		Region:   tracked.Region,
+		Spot:     tracked.Spot,
+	}
+	if price, err := strconv.ParseFloat(tracked.PricePerHour, 64); err == nil {
+		requirement.HourlyPrice = price
+	}
+	if savings, err := strconv.ParseFloat(tracked.SpotHourlySavings, 64); err == nil {
+		requirement.SpotSavings = savings
+	}
+	instance := &providers.GPUInstance{
+		ID:        tracked.InstanceID,
+		PublicIP:  status.PublicIP,
+		PrivateIP: status.PrivateIP,
+		Status:    status.State,
+		CreatedAt: tracked.LaunchedAt.Time,
+		IsSpot:    tracked.Spot,
+	}
+
+	provider := &tgpv1.ProviderConfig{Name: tracked.Provider}
+	for i := range nodeClass.Spec.Providers {
+		if nodeClass.Spec.Providers[i].Name == tracked.Provider {
+			provider = &nodeClass.Spec.Providers[i]
+			break
+		}
+	}
+	if err := r.createKubernetesNode(ctx, nodePool, requirement, instance, instanceTags(nodePool, nodeClass), provider, log); err != nil {
+		return false, err
+	}
+
+	log.Info("Resumed provisioning of launched instance", "instanceID", tracked.InstanceID, "provider", tracked.Provider)
+	return true, nil
+}
+
+// nodeInstancePhase returns the phase of an instance whose node exists
+func nodeInstancePhase(node *corev1.Node) tgpv1.PoolInstancePhase {
+	if isNodeReady(node) && node.Annotations[AnnotationAwaitingReady] == "" {
+		return tgpv1.PoolInstancePhaseReady
+	}
+	return tgpv1.PoolInstancePhaseRegistered
+}
+
+// untrackedInstance rebuilds the tracking of an instance from the labels and annotations of its node
+func untrackedInstance(instanceID string, node *corev1.Node) tgpv1.PoolInstance {
+	_, providerName := nodeInstance(node)
+	tracked := tgpv1.PoolInstance{
+		InstanceID:        instanceID,
+		Provider:          providerName,
+		Phase:             nodeInstancePhase(node),
+		NodeName:          node.Name,
+		GPUType:           node.Labels[tgpv1.NodeLabelGPUType],
+		Region:            node.Labels[tgpv1.NodeLabelRegion],
+		Spot:              node.Labels[tgpv1.NodeLabelSpot] == "true",
+		PricePerHour:      node.Annotations[AnnotationHourlyPrice],
+		SpotHourlySavings: node.Annotations[AnnotationSpotHourlySavings],
+		LaunchedAt:        metav1.NewTime(nodeCreationTime(node)),
+	}
+	if count, err := strconv.ParseInt(node.Annotations[AnnotationExpectedGPUCount], 10, 32); err == nil {
+		tracked.GPUCount = int32(count)
+	}
+	return tracked
+}
+
+// podHasInstanceInFlight reports whether an instance launched for the pod within the launch
+// timeout has not become a Ready node yet, so provisioning for it again would launch a duplicate
+func podHasInstanceInFlight(nodePool *tgpv1.GPUNodePool, pod *corev1.Pod, now time.Time) bool {
+	key := pod.Namespace + "/" + pod.Name
+	for _, tracked := range nodePool.Status.Instances {
+		if tracked.Pod == key && tracked.Phase != tgpv1.PoolInstancePhaseReady && now.Sub(tracked.LaunchedAt.Time) < instanceLaunchTimeout {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/config"
+)
+
+func TestReconcileInstances(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = tgpv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	launchedAt := metav1.NewTime(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	poolNode := func(name, instanceID string, ready corev1.ConditionStatus) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					"tgp.io/nodepool":                "test-pool",
+					tgpv1.NodeLabelNodePoolNamespace: "default",
+					"tgp.io/instance-id":             instanceID,
+					tgpv1.NodeLabelProvider:          "vultr",
+					tgpv1.NodeLabelGPUType:           "NVIDIA_H100",
+				},
+				Annotations: map[string]string{
+					"tgp.io/created-at":        launchedAt.Format(time.RFC3339),
+					AnnotationHourlyPrice:      "2.5000",
+					AnnotationExpectedGPUCount: "8",
+				},
+			},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}}},
+		}
+	}
+
+	nodePool := &tgpv1.GPUNodePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pool", Namespace: "default"},
+		Status: tgpv1.GPUNodePoolStatus{Instances: []tgpv1.PoolInstance{
+			// Its node has since joined
+			{InstanceID: "joined", Provider: "vultr", Phase: tgpv1.PoolInstancePhaseRegistered, Pod: "ml/train", Spot: true, LaunchedAt: launchedAt},
+			// Its node was consolidated away
+			{InstanceID: "removed", Provider: "vultr", Phase: tgpv1.PoolInstancePhaseReady, Spot: true, SpotHourlySavings: "1.0000", LaunchedAt: launchedAt},
+			// Launched before a restart, its node was never created
+			{InstanceID: "interrupted", Provider: "vultr", Phase: tgpv1.PoolInstancePhaseLaunched, Pod: "ml/eval", LaunchedAt: launchedAt},
+		}},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		poolNode("tgp-joined", "joined", corev1.ConditionTrue),
+		poolNode("tgp-legacy", "legacy", corev1.ConditionFalse),
+	).Build()
+
+	// No provider credentials exist, so the interrupted launch cannot be resumed yet
+	r := &GPUNodePoolReconciler{Client: client, Scheme: scheme, Config: config.DefaultConfig()}
+	if _, err := r.reconcileInstances(context.Background(), nodePool, &tgpv1.GPUNodeClass{}, logr.Discard()); err != nil {
+		t.Fatalf("reconcileInstances failed: %v", err)
+	}
+
+	instances := make(map[string]tgpv1.PoolInstance)
+	for _, tracked := range nodePool.Status.Instances {
+		instances[tracked.InstanceID] = tracked
+	}
+	if len(instances) != 3 {
+		t.Fatalf("expected joined, interrupted and legacy instances, got %+v", nodePool.Status.Instances)
+	}
+	if joined := instances["joined"]; joined.Phase != tgpv1.PoolInstancePhaseReady || joined.NodeName != "tgp-joined" || joined.Pod != "ml/train" {
+		t.Errorf("expected the joined instance to be Ready on its node, got %+v", joined)
+	}
+	if interrupted := instances["interrupted"]; interrupted.Phase != tgpv1.PoolInstancePhaseLaunched {
+		t.Errorf("expected the interrupted launch to be kept for a retry, got %+v", interrupted)
+	}
+	legacy := instances["legacy"]
+	if legacy.Phase != tgpv1.PoolInstancePhaseRegistered || legacy.Provider != "vultr" || legacy.GPUType != "NVIDIA_H100" ||
+		legacy.GPUCount != 8 || legacy.PricePerHour != "2.5000" || !legacy.LaunchedAt.Equal(&launchedAt) {
+		t.Errorf("expected the untracked node to be adopted from its labels, got %+v", legacy)
+	}
+	if nodePool.Status.SpotInstances != 1 || nodePool.Status.OnDemandInstances != 2 {
+		t.Errorf("expected 1 spot and 2 on-demand instances, got %d and %d",
+			nodePool.Status.SpotInstances, nodePool.Status.OnDemandInstances)
+	}
+	if nodePool.Status.RealizedSpotSavings == "" || nodePool.Status.EstimatedSpotSavings != nodePool.Status.RealizedSpotSavings {
+		t.Errorf("expected the removed spot instance's savings to be realized, got realized %q and estimated %q",
+			nodePool.Status.RealizedSpotSavings, nodePool.Status.EstimatedSpotSavings)
+	}
+}
+
+func TestPodHasInstanceInFlight(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "train", Namespace: "ml"}}
+
+	tests := []struct {
+		name    string
+		tracked tgpv1.PoolInstance
+		want    bool
+	}{
+		{"launched for the pod", tgpv1.PoolInstance{Pod: "ml/train", Phase: tgpv1.PoolInstancePhaseLaunched, LaunchedAt: metav1.NewTime(now.Add(-time.Minute))}, true},
+		{"node joined", tgpv1.PoolInstance{Pod: "ml/train", Phase: tgpv1.PoolInstancePhaseReady, LaunchedAt: metav1.NewTime(now.Add(-time.Minute))}, false},
+		{"launched for another pod", tgpv1.PoolInstance{Pod: "ml/eval", Phase: tgpv1.PoolInstancePhaseRegistered, LaunchedAt: metav1.NewTime(now.Add(-time.Minute))}, false},
+		{"launch timed out", tgpv1.PoolInstance{Pod: "ml/train", Phase: tgpv1.PoolInstancePhaseRegistered, LaunchedAt: metav1.NewTime(now.Add(-time.Hour))}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodePool := &tgpv1.GPUNodePool{Status: tgpv1.GPUNodePoolStatus{Instances: []tgpv1.PoolInstance{tt.tracked}}}
+			if got := podHasInstanceInFlight(nodePool, pod, now); got != tt.want {
+				t.Errorf("podHasInstanceInFlight() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
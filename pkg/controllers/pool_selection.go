@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+// poolCandidate is a pool that could provision a node for a pod
+type poolCandidate struct {
+	pool   *tgpv1.GPUNodePool
+	weight int32
+	// price is the projected hourly price of a node for the pod, or +Inf when unknown
+	price float64
+}
+
+// poolSelection is the pool chosen to provision for a pod and why
+type poolSelection struct {
+	pool    *tgpv1.GPUNodePool
+	reason  string
+	message string
+}
+
+// selectPoolForPod arbitrates between every pool that could host the pod so only one
+// provisions for it. Pools are ranked by weight, highest first, breaking ties on the
+// lowest projected hourly price and then on namespace and name.
+func (r *GPUNodePoolReconciler) selectPoolForPod(ctx context.Context, pod *corev1.Pod, pools []tgpv1.GPUNodePool, log logr.Logger) *poolSelection {
+	requirement, err := r.extractGPURequirement(pod)
+	if err != nil {
+		requirement = &GPURequirement{}
+	}
+
+	var candidates []poolCandidate
+	for i := range pools {
+		pool := &pools[i]
+		if pool.DeletionTimestamp != nil || !r.podMatchesPool(*pod, pool, log) {
+			continue
+		}
+		candidates = append(candidates, poolCandidate{
+			pool:   pool,
+			weight: pool.Spec.GetWeight(),
+			price:  r.projectedHourlyPrice(ctx, pool, requirement.GPUType),
+		})
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.weight != b.weight {
+			return a.weight > b.weight
+		}
+		if a.price != b.price {
+			return a.price < b.price
+		}
+		return poolKey(a.pool) < poolKey(b.pool)
+	})
+
+	winner := candidates[0]
+	selection := &poolSelection{pool: winner.pool}
+	if len(candidates) == 1 {
+		selection.reason = tgpv1.PoolSelectedReasonOnlyMatch
+		selection.message = fmt.Sprintf("Only pool matching pod %s/%s", pod.Namespace, pod.Name)
+		return selection
+	}
+
+	runnerUp := candidates[1]
+	switch {
+	case winner.weight != runnerUp.weight:
+		selection.reason = tgpv1.PoolSelectedReasonHighestWeight
+	case winner.price != runnerUp.price:
+		selection.reason = tgpv1.PoolSelectedReasonLowestPrice
+	default:
+		selection.reason = tgpv1.PoolSelectedReasonTieBreak
+	}
+
+	others := make([]string, 0, len(candidates)-1)
+	for _, candidate := range candidates[1:] {
+		others = append(others, fmt.Sprintf("%s (weight %d, %s)", poolKey(candidate.pool), candidate.weight, formatProjectedPrice(candidate.price)))
+	}
+	selection.message = fmt.Sprintf("Selected for pod %s/%s with weight %d and %s over %s",
+		pod.Namespace, pod.Name, winner.weight, formatProjectedPrice(winner.price), strings.Join(others, ", "))
+	return selection
+}
+
+// projectedHourlyPrice returns the cheapest available price for the GPU type among the offers
+// in the pool's node class inventory, or +Inf when the class or a price is unavailable.
+// Any GPU type is considered when the pod does not ask for one.
+func (r *GPUNodePoolReconciler) projectedHourlyPrice(ctx context.Context, pool *tgpv1.GPUNodePool, gpuType string) float64 {
+	best := math.Inf(1)
+	nodeClass, err := r.getNodeClass(ctx, pool)
+	if err != nil {
+		return best
+	}
+
+	for _, offers := range nodeClass.Status.AvailableGPUs {
+		for _, offer := range offers {
+			if !offer.Available || (gpuType != "" && !strings.EqualFold(offer.GPUType, gpuType)) {
+				continue
+			}
+			if price, err := strconv.ParseFloat(offer.PricePerHour, 64); err == nil && price < best {
+				best = price
+			}
+		}
+	}
+	return best
+}
+
+// recordPoolSelection documents why the pool was chosen in its PoolSelected condition
+func (r *GPUNodePoolReconciler) recordPoolSelection(nodePool *tgpv1.GPUNodePool, selection *poolSelection) {
+	r.updateCondition(nodePool, tgpv1.ConditionTypePoolSelected, metav1.ConditionTrue, selection.reason, selection.message)
+}
+
+// poolKey identifies a pool by namespace and name
+func poolKey(pool *tgpv1.GPUNodePool) string {
+	return pool.Namespace + "/" + pool.Name
+}
+
+// formatProjectedPrice describes a projected hourly price for condition messages
+func formatProjectedPrice(price float64) string {
+	if math.IsInf(price, 1) {
+		return "unknown price"
+	}
+	return fmt.Sprintf("$%.4f/hr", price)
+}
@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+func TestSelectPoolForPod(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = tgpv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	nodeClass := func(name, price string) *tgpv1.GPUNodeClass {
+		return &tgpv1.GPUNodeClass{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: tgpv1.GPUNodeClassStatus{
+				AvailableGPUs: map[string][]tgpv1.GPUAvailability{
+					"vultr": {{GPUType: "H100", PricePerHour: price, Available: true}},
+				},
+			},
+		}
+	}
+	pool := func(name, className string, weight *int32) tgpv1.GPUNodePool {
+		return tgpv1.GPUNodePool{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: tgpv1.GPUNodePoolSpec{
+				NodeClassRef: tgpv1.NodeClassReference{Kind: "GPUNodeClass", Name: className},
+				Weight:       weight,
+			},
+		}
+	}
+	weight := func(w int32) *int32 { return &w }
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "train", Namespace: "default"},
+		Spec: corev1.PodSpec{
+			NodeSelector: map[string]string{"tgp.io/gpu-type": "H100"},
+			Containers: []corev1.Container{{
+				Name: "train",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")},
+				},
+			}},
+		},
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(nodeClass("cheap", "2.00"), nodeClass("pricey", "3.50")).
+		Build()
+	reconciler := &GPUNodePoolReconciler{Client: client, Log: logr.Discard(), Scheme: scheme}
+
+	// The pod pins a GPU type, so every pool must offer it
+	matchAll := func(p tgpv1.GPUNodePool) tgpv1.GPUNodePool {
+		p.Spec.Template.Spec.Requirements = []tgpv1.NodeSelectorRequirement{{
+			Key:      "tgp.io/gpu-type",
+			Operator: tgpv1.NodeSelectorOpIn,
+			Values:   []string{"H100"},
+		}}
+		return p
+	}
+
+	tests := []struct {
+		name         string
+		pools        []tgpv1.GPUNodePool
+		expectPool   string
+		expectReason string
+	}{
+		{
+			name:         "single matching pool",
+			pools:        []tgpv1.GPUNodePool{matchAll(pool("a", "pricey", nil))},
+			expectPool:   "a",
+			expectReason: tgpv1.PoolSelectedReasonOnlyMatch,
+		},
+		{
+			name: "highest weight wins over a cheaper pool",
+			pools: []tgpv1.GPUNodePool{
+				matchAll(pool("cheap", "cheap", nil)),
+				matchAll(pool("preferred", "pricey", weight(50))),
+			},
+			expectPool:   "preferred",
+			expectReason: tgpv1.PoolSelectedReasonHighestWeight,
+		},
+		{
+			name: "equal weights break on projected price",
+			pools: []tgpv1.GPUNodePool{
+				matchAll(pool("pricey", "pricey", weight(10))),
+				matchAll(pool("cheap", "cheap", nil)),
+			},
+			expectPool:   "cheap",
+			expectReason: tgpv1.PoolSelectedReasonLowestPrice,
+		},
+		{
+			name: "equal weights and prices break on name",
+			pools: []tgpv1.GPUNodePool{
+				matchAll(pool("b", "cheap", nil)),
+				matchAll(pool("a", "cheap", nil)),
+			},
+			expectPool:   "a",
+			expectReason: tgpv1.PoolSelectedReasonTieBreak,
+		},
+		{
+			name: "pools the pod does not match are ignored",
+			pools: []tgpv1.GPUNodePool{
+				pool("heavy", "cheap", weight(100)),
+				matchAll(pool("light", "pricey", weight(1))),
+			},
+			expectPool:   "light",
+			expectReason: tgpv1.PoolSelectedReasonOnlyMatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selection := reconciler.selectPoolForPod(context.Background(), pod, tt.pools, logr.Discard())
+			if selection == nil {
+				t.Fatal("expected a pool to be selected")
+			}
+			if selection.pool.Name != tt.expectPool {
+				t.Errorf("expected pool %s, got %s", tt.expectPool, selection.pool.Name)
+			}
+			if selection.reason != tt.expectReason {
+				t.Errorf("expected reason %s, got %s (%s)", tt.expectReason, selection.reason, selection.message)
+			}
+		})
+	}
+}
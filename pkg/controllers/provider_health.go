@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+// providerInventoryMaxAge is how old a provider's last successful inventory may be before the
+// provider counts as failing, allowing a couple of missed inventory updates
+const providerInventoryMaxAge = 30 * time.Minute
+
+// providerHealthProblem returns why an enabled provider is not usable, or "" if its credentials
+// are valid and its inventory is recent. Providers excluded by the class requirements are
+// working and not counted as failing.
+func providerHealthProblem(status tgpv1.ProviderStatus, now time.Time) string {
+	if status.Error != "" {
+		return status.Error
+	}
+	if !status.CredentialsValid {
+		return "credentials not validated"
+	}
+	if !status.InventoryEnabled || status.Excluded {
+		return ""
+	}
+	if status.LastPricingUpdate == nil {
+		return "no successful inventory query yet"
+	}
+	if age := now.Sub(status.LastPricingUpdate.Time); age > providerInventoryMaxAge {
+		return fmt.Sprintf("no successful inventory query for %s", age.Truncate(time.Minute))
+	}
+	return ""
+}
+
+// providersHealthyCondition summarizes the health of the class's enabled providers: True when they
+// are all healthy, True with the Degraded reason when some fail and False when all fail
+func providersHealthyCondition(nodeClass *tgpv1.GPUNodeClass, statuses map[string]tgpv1.ProviderStatus, now time.Time) (metav1.ConditionStatus, string, string) {
+	enabled := 0
+	var failing []string
+	for _, providerConfig := range nodeClass.Spec.Providers {
+		if providerConfig.Enabled != nil && !*providerConfig.Enabled {
+			continue
+		}
+		enabled++
+		if problem := providerHealthProblem(statuses[providerConfig.Name], now); problem != "" {
+			failing = append(failing, fmt.Sprintf("%s: %s", providerConfig.Name, problem))
+		}
+	}
+	sort.Strings(failing)
+
+	switch {
+	case enabled == 0:
+		return metav1.ConditionFalse, tgpv1.ProvidersHealthyReasonNoProviders, "No providers are enabled"
+	case len(failing) == 0:
+		return metav1.ConditionTrue, tgpv1.ProvidersHealthyReasonHealthy, fmt.Sprintf("All %d enabled providers are healthy", enabled)
+	case len(failing) < enabled:
+		return metav1.ConditionTrue, tgpv1.ProvidersHealthyReasonDegraded,
+			fmt.Sprintf("%d of %d enabled providers failing: %s", len(failing), enabled, strings.Join(failing, "; "))
+	default:
+		return metav1.ConditionFalse, tgpv1.ProvidersHealthyReasonAllFailing,
+			fmt.Sprintf("All %d enabled providers failing: %s", enabled, strings.Join(failing, "; "))
+	}
+}
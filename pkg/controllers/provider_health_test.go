@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+func TestProvidersHealthyCondition(t *testing.T) {
+	now := time.Now()
+	recent := metav1.NewTime(now.Add(-5 * time.Minute))
+	old := metav1.NewTime(now.Add(-2 * time.Hour))
+	disabled := false
+
+	healthy := tgpv1.ProviderStatus{CredentialsValid: true, InventoryEnabled: true, LastPricingUpdate: &recent}
+	badCredentials := tgpv1.ProviderStatus{Error: "Credential check failed: unauthorized"}
+	staleInventory := tgpv1.ProviderStatus{CredentialsValid: true, InventoryEnabled: true, LastPricingUpdate: &old}
+	excluded := tgpv1.ProviderStatus{CredentialsValid: true, InventoryEnabled: true, Excluded: true}
+	noInventory := tgpv1.ProviderStatus{CredentialsValid: true}
+
+	tests := []struct {
+		name        string
+		providers   []tgpv1.ProviderConfig
+		statuses    map[string]tgpv1.ProviderStatus
+		wantStatus  metav1.ConditionStatus
+		wantReason  string
+		wantFailing []string
+	}{
+		{
+			name:       "all healthy",
+			providers:  []tgpv1.ProviderConfig{{Name: "vultr"}, {Name: "gcp"}, {Name: "aws"}},
+			statuses:   map[string]tgpv1.ProviderStatus{"vultr": healthy, "gcp": excluded, "aws": noInventory},
+			wantStatus: metav1.ConditionTrue,
+			wantReason: tgpv1.ProvidersHealthyReasonHealthy,
+		},
+		{
+			name:        "some failing",
+			providers:   []tgpv1.ProviderConfig{{Name: "vultr"}, {Name: "gcp"}, {Name: "aws"}},
+			statuses:    map[string]tgpv1.ProviderStatus{"vultr": healthy, "gcp": badCredentials, "aws": staleInventory},
+			wantStatus:  metav1.ConditionTrue,
+			wantReason:  tgpv1.ProvidersHealthyReasonDegraded,
+			wantFailing: []string{"aws", "gcp"},
+		},
+		{
+			name:        "all failing, including a provider without a status",
+			providers:   []tgpv1.ProviderConfig{{Name: "vultr"}, {Name: "gcp"}},
+			statuses:    map[string]tgpv1.ProviderStatus{"gcp": badCredentials},
+			wantStatus:  metav1.ConditionFalse,
+			wantReason:  tgpv1.ProvidersHealthyReasonAllFailing,
+			wantFailing: []string{"gcp", "vultr"},
+		},
+		{
+			name:       "disabled providers are ignored",
+			providers:  []tgpv1.ProviderConfig{{Name: "vultr"}, {Name: "gcp", Enabled: &disabled}},
+			statuses:   map[string]tgpv1.ProviderStatus{"vultr": healthy, "gcp": {Error: "Provider disabled in configuration"}},
+			wantStatus: metav1.ConditionTrue,
+			wantReason: tgpv1.ProvidersHealthyReasonHealthy,
+		},
+		{
+			name:       "no enabled providers",
+			providers:  []tgpv1.ProviderConfig{{Name: "gcp", Enabled: &disabled}},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: tgpv1.ProvidersHealthyReasonNoProviders,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeClass := &tgpv1.GPUNodeClass{Spec: tgpv1.GPUNodeClassSpec{Providers: tt.providers}}
+			status, reason, message := providersHealthyCondition(nodeClass, tt.statuses, now)
+			if status != tt.wantStatus || reason != tt.wantReason {
+				t.Errorf("providersHealthyCondition() = %s/%s, want %s/%s", status, reason, tt.wantStatus, tt.wantReason)
+			}
+			for _, provider := range tt.wantFailing {
+				if !strings.Contains(message, provider+":") {
+					t.Errorf("message %q does not list failing provider %s", message, provider)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/solanyn/tgp-operator/pkg/metrics"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// Provider API operations recorded in the provider_request_duration_seconds metric
+const (
+	providerOperationList      = "list"
+	providerOperationPricing   = "pricing"
+	providerOperationLaunch    = "launch"
+	providerOperationStatus    = "status"
+	providerOperationTerminate = "terminate"
+)
+
+// Provider API error classes recorded in the provider_api_errors_total metric
+const (
+	providerErrorAuth       = "auth"
+	providerErrorRateLimit  = "rate_limit"
+	providerErrorBilling    = "billing"
+	providerErrorNoCapacity = "no_capacity"
+	providerErrorNotFound   = "not_found"
+	providerErrorNetwork    = "network"
+	providerErrorOther      = "other"
+)
+
+// classifyProviderAPIError returns the class of a provider API error from its kind
+func classifyProviderAPIError(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, providers.ErrRateLimited):
+		return providerErrorRateLimit
+	case errors.Is(err, providers.ErrUnauthorized):
+		return providerErrorAuth
+	case errors.Is(err, providers.ErrBilling):
+		return providerErrorBilling
+	case errors.Is(err, providers.ErrNoCapacity):
+		return providerErrorNoCapacity
+	case errors.Is(err, providers.ErrNotFound):
+		return providerErrorNotFound
+	case errors.As(err, &netErr):
+		return providerErrorNetwork
+	}
+	return providerErrorOther
+}
+
+// instrumentProviderClient returns a client that records the latency and errors of its
+// provider API calls. A nil Metrics returns the client unchanged.
+func instrumentProviderClient(m *metrics.Metrics, client providers.ProviderClient) providers.ProviderClient {
+	if m == nil || client == nil {
+		return client
+	}
+	return &instrumentedClient{ProviderClient: client, metrics: m, provider: client.GetProviderInfo().Name}
+}
+
+// instrumentedClient records metrics for the API calls of a provider client. Metadata and
+// translation calls do not reach the provider API and pass straight through.
+type instrumentedClient struct {
+	providers.ProviderClient
+	metrics  *metrics.Metrics
+	provider string
+}
+
+// Unwrap returns the instrumented client so its optional capabilities can be detected
+func (c *instrumentedClient) Unwrap() providers.ProviderClient {
+	return c.ProviderClient
+}
+
+// observe records the duration and outcome of an API call that started at start
+func (c *instrumentedClient) observe(operation string, start time.Time, err error) {
+	c.metrics.RecordProviderRequestDuration(c.provider, operation, time.Since(start).Seconds())
+	if err != nil {
+		c.metrics.RecordProviderRequest(c.provider, operation, "error")
+		c.metrics.RecordProviderAPIError(c.provider, classifyProviderAPIError(err))
+		return
+	}
+	c.metrics.RecordProviderRequest(c.provider, operation, "success")
+}
+
+func (c *instrumentedClient) ListAvailableGPUs(ctx context.Context, filters *providers.GPUFilters) ([]providers.GPUOffer, error) {
+	start := time.Now()
+	offers, err := c.ProviderClient.ListAvailableGPUs(ctx, filters)
+	c.observe(providerOperationList, start, err)
+	return offers, err
+}
+
+func (c *instrumentedClient) GetNormalizedPricing(ctx context.Context, gpuType, region string) (*providers.NormalizedPricing, error) {
+	start := time.Now()
+	pricing, err := c.ProviderClient.GetNormalizedPricing(ctx, gpuType, region)
+	c.observe(providerOperationPricing, start, err)
+	return pricing, err
+}
+
+func (c *instrumentedClient) LaunchInstance(ctx context.Context, req *providers.LaunchRequest) (*providers.GPUInstance, error) {
+	c.metrics.AddLaunchesInFlight(c.provider, 1)
+	defer c.metrics.AddLaunchesInFlight(c.provider, -1)
+
+	start := time.Now()
+	instance, err := c.ProviderClient.LaunchInstance(ctx, req)
+	c.observe(providerOperationLaunch, start, err)
+	return instance, err
+}
+
+func (c *instrumentedClient) GetInstanceStatus(ctx context.Context, instanceID string) (*providers.InstanceStatus, error) {
+	start := time.Now()
+	status, err := c.ProviderClient.GetInstanceStatus(ctx, instanceID)
+	c.observe(providerOperationStatus, start, err)
+	return status, err
+}
+
+func (c *instrumentedClient) TerminateInstance(ctx context.Context, instanceID string) error {
+	start := time.Now()
+	err := c.ProviderClient.TerminateInstance(ctx, instanceID)
+	c.observe(providerOperationTerminate, start, err)
+	return err
+}
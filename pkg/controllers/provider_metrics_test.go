@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/solanyn/tgp-operator/pkg/metrics"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+func TestClassifyProviderAPIError(t *testing.T) {
+	cause := errors.New("request failed")
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{providers.NewAPIError("test", nil, http.StatusTooManyRequests, cause), providerErrorRateLimit},
+		{providers.NewAPIError("test", nil, http.StatusUnauthorized, cause), providerErrorAuth},
+		{fmt.Errorf("launch: %w", providers.NewAPIError("test", nil, http.StatusForbidden, cause)), providerErrorAuth},
+		{providers.NewAPIError("test", nil, http.StatusPaymentRequired, cause), providerErrorBilling},
+		{providers.NewAPIError("test", providers.ErrNoCapacity, 0, cause), providerErrorNoCapacity},
+		{providers.NewAPIError("test", nil, http.StatusNotFound, cause), providerErrorNotFound},
+		{&net.OpError{Op: "dial", Err: errors.New("connection refused")}, providerErrorNetwork},
+		{providers.NewAPIError("test", nil, http.StatusInternalServerError, cause), providerErrorOther},
+		// Messages are not classified, only error kinds
+		{errors.New("HTTP 429 Too Many Requests"), providerErrorOther},
+	}
+
+	for _, tt := range tests {
+		if got := classifyProviderAPIError(tt.err); got != tt.want {
+			t.Errorf("classifyProviderAPIError(%v) = %s, want %s", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestInstrumentProviderClient(t *testing.T) {
+	client := &limitedClient{}
+	if instrumentProviderClient(nil, client) != client {
+		t.Error("expected a nil Metrics to leave the client unwrapped")
+	}
+
+	instrumented := instrumentProviderClient(metrics.NewMetrics(), client)
+	if providers.Unwrap(instrumented) != client {
+		t.Error("expected Unwrap to return the instrumented client")
+	}
+	offers, err := instrumented.ListAvailableGPUs(context.Background(), nil)
+	if err != nil || len(offers) != 1 || client.calls != 1 {
+		t.Errorf("expected the call to reach the provider, got %v, %v after %d calls", offers, err, client.calls)
+	}
+}
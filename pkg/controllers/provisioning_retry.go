@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+const (
+	// maxProvisioningAttempts is how many consecutive failures are retried with backoff
+	// before the pool stays failed and only retries at provisioningRetryMaxDelay
+	maxProvisioningAttempts = 5
+
+	// provisioningRetryBaseDelay is the delay after the first failed attempt, doubled for each further failure
+	provisioningRetryBaseDelay = 30 * time.Second
+
+	// provisioningRetryMaxDelay caps the delay between provisioning attempts
+	provisioningRetryMaxDelay = 10 * time.Minute
+)
+
+// recordProvisioningFailure counts a failed provisioning attempt in the pool status and
+// returns how long to wait before the next attempt. Retriable failures back off exponentially
+// until maxProvisioningAttempts; after that, or for failures that are not worth retrying,
+// the pool stays failed and retries at provisioningRetryMaxDelay.
+func recordProvisioningFailure(nodePool *tgpv1.GPUNodePool, provisionErr error, now time.Time) time.Duration {
+	nodePool.Status.ProvisioningAttempts++
+	failedAt := metav1.NewTime(now)
+	nodePool.Status.LastProvisioningFailure = &failedAt
+
+	attempts := nodePool.Status.ProvisioningAttempts
+	delay := provisioningRetryDelay(attempts)
+	if !isRetriableProvisioningError(provisionErr) || attempts >= maxProvisioningAttempts {
+		meta.SetStatusCondition(&nodePool.Status.Conditions, metav1.Condition{
+			Type:   tgpv1.ConditionTypeProvisioning,
+			Status: metav1.ConditionFalse,
+			Reason: tgpv1.ProvisioningReasonFailed,
+			Message: fmt.Sprintf("Provisioning failed after %d attempts, retrying every %s: %v",
+				attempts, provisioningRetryMaxDelay, provisionErr),
+		})
+		return provisioningRetryMaxDelay
+	}
+
+	meta.SetStatusCondition(&nodePool.Status.Conditions, metav1.Condition{
+		Type:   tgpv1.ConditionTypeProvisioning,
+		Status: metav1.ConditionFalse,
+		Reason: tgpv1.ProvisioningReasonRetrying,
+		Message: fmt.Sprintf("Provisioning attempt %d of %d failed, retrying in %s: %v",
+			attempts, maxProvisioningAttempts, delay, provisionErr),
+	})
+	return delay
+}
+
+// resetProvisioningAttempts clears the failure tracking once provisioning succeeds
+func resetProvisioningAttempts(nodePool *tgpv1.GPUNodePool) {
+	nodePool.Status.ProvisioningAttempts = 0
+	nodePool.Status.LastProvisioningFailure = nil
+	meta.RemoveStatusCondition(&nodePool.Status.Conditions, tgpv1.ConditionTypeProvisioning)
+}
+
+// provisioningBackoffRemaining returns how long until the pool may attempt provisioning again
+// after a failure, or 0 if it may provision now
+func provisioningBackoffRemaining(nodePool *tgpv1.GPUNodePool, now time.Time) time.Duration {
+	if nodePool.Status.ProvisioningAttempts == 0 || nodePool.Status.LastProvisioningFailure == nil {
+		return 0
+	}
+
+	delay := provisioningRetryMaxDelay
+	if condition := meta.FindStatusCondition(nodePool.Status.Conditions, tgpv1.ConditionTypeProvisioning); condition != nil &&
+		condition.Reason == tgpv1.ProvisioningReasonRetrying {
+		delay = provisioningRetryDelay(nodePool.Status.ProvisioningAttempts)
+	}
+
+	remaining := nodePool.Status.LastProvisioningFailure.Add(delay).Sub(now)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// provisioningRetryDelay returns the backoff after the given number of failed attempts
+func provisioningRetryDelay(attempts int32) time.Duration {
+	delay := provisioningRetryBaseDelay
+	for i := int32(1); i < attempts && delay < provisioningRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, provisioningRetryMaxDelay)
+}
+
+// isRetriableProvisioningError reports whether a provisioning failure is likely to succeed
+// on a later attempt: transient provider errors, a temporary lack of capacity and node class
+// limits that free up as nodes are removed
+func isRetriableProvisioningError(err error) bool {
+	if errors.Is(err, errNoSuitableProvider) || errors.Is(err, errClassLimitExceeded) {
+		return true
+	}
+	retriable, _ := providers.IsRetriableError(err)
+	return retriable
+}
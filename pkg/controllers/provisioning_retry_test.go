@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+func TestRecordProvisioningFailure(t *testing.T) {
+	now := time.Now()
+	transient := fmt.Errorf("failed to launch instance: %w", errors.New("service temporarily unavailable"))
+
+	t.Run("retriable failures back off exponentially", func(t *testing.T) {
+		nodePool := &tgpv1.GPUNodePool{}
+		expected := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute}
+		for i, want := range expected {
+			if got := recordProvisioningFailure(nodePool, transient, now); got != want {
+				t.Errorf("attempt %d: expected delay %v, got %v", i+1, want, got)
+			}
+		}
+
+		condition := meta.FindStatusCondition(nodePool.Status.Conditions, tgpv1.ConditionTypeProvisioning)
+		if condition == nil || condition.Reason != tgpv1.ProvisioningReasonRetrying {
+			t.Fatalf("expected Retrying condition, got %+v", condition)
+		}
+		if nodePool.Status.ProvisioningAttempts != 4 {
+			t.Errorf("expected 4 attempts, got %d", nodePool.Status.ProvisioningAttempts)
+		}
+		if got := provisioningBackoffRemaining(nodePool, now.Add(time.Minute)); got != 3*time.Minute {
+			t.Errorf("expected 3m of backoff remaining, got %v", got)
+		}
+	})
+
+	t.Run("pool stays failed after the maximum attempts", func(t *testing.T) {
+		nodePool := &tgpv1.GPUNodePool{}
+		var delay time.Duration
+		for i := 0; i < maxProvisioningAttempts; i++ {
+			delay = recordProvisioningFailure(nodePool, errNoSuitableProvider, now)
+		}
+		if delay != provisioningRetryMaxDelay {
+			t.Errorf("expected delay %v once attempts are exhausted, got %v", provisioningRetryMaxDelay, delay)
+		}
+		condition := meta.FindStatusCondition(nodePool.Status.Conditions, tgpv1.ConditionTypeProvisioning)
+		if condition == nil || condition.Reason != tgpv1.ProvisioningReasonFailed {
+			t.Fatalf("expected Failed condition, got %+v", condition)
+		}
+	})
+
+	t.Run("non-retriable failures are not backed off", func(t *testing.T) {
+		nodePool := &tgpv1.GPUNodePool{}
+		if delay := recordProvisioningFailure(nodePool, errors.New("invalid machine config"), now); delay != provisioningRetryMaxDelay {
+			t.Errorf("expected delay %v, got %v", provisioningRetryMaxDelay, delay)
+		}
+		condition := meta.FindStatusCondition(nodePool.Status.Conditions, tgpv1.ConditionTypeProvisioning)
+		if condition == nil || condition.Reason != tgpv1.ProvisioningReasonFailed {
+			t.Fatalf("expected Failed condition, got %+v", condition)
+		}
+	})
+
+	t.Run("success resets the attempts", func(t *testing.T) {
+		nodePool := &tgpv1.GPUNodePool{}
+		recordProvisioningFailure(nodePool, transient, now)
+		resetProvisioningAttempts(nodePool)
+
+		if nodePool.Status.ProvisioningAttempts != 0 || nodePool.Status.LastProvisioningFailure != nil {
+			t.Errorf("expected attempts to be reset, got %+v", nodePool.Status)
+		}
+		if meta.FindStatusCondition(nodePool.Status.Conditions, tgpv1.ConditionTypeProvisioning) != nil {
+			t.Error("expected Provisioning condition to be removed")
+		}
+		if got := provisioningBackoffRemaining(nodePool, now); got != 0 {
+			t.Errorf("expected no backoff, got %v", got)
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"fmt"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// qualityExclusionReason returns why a provider does not meet the class quality policy,
+// or an empty string if it complies
+func qualityExclusionReason(policy *tgpv1.QualityPolicy, info *providers.ProviderInfo) string {
+	if policy == nil || policy.MinReliabilityTier == "" {
+		return ""
+	}
+
+	tier := providers.ReliabilityTierCommunity
+	if info != nil && info.ReliabilityTier != "" {
+		tier = info.ReliabilityTier
+	}
+
+	minimum := providers.ReliabilityTier(policy.MinReliabilityTier)
+	if !tier.Meets(minimum) {
+		return fmt.Sprintf("reliability tier %s is below the required %s", tier, minimum)
+	}
+	return ""
+}
+
+// verifiedOnly reports whether the policy only allows offers on verified hosts
+func verifiedOnly(policy *tgpv1.QualityPolicy) bool {
+	return policy != nil && policy.VerifiedOnly
+}
+
+// filterOffersByQuality drops offers that do not meet the class quality policy
+func filterOffersByQuality(policy *tgpv1.QualityPolicy, offers []providers.GPUOffer) []providers.GPUOffer {
+	if !verifiedOnly(policy) {
+		return offers
+	}
+
+	filtered := make([]providers.GPUOffer, 0, len(offers))
+	for _, offer := range offers {
+		if offer.Verified {
+			filtered = append(filtered, offer)
+		}
+	}
+	return filtered
+}
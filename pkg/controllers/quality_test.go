@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"testing"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+func TestQualityExclusionReason(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       *tgpv1.QualityPolicy
+		info         *providers.ProviderInfo
+		wantExcluded bool
+	}{
+		{
+			name:         "no policy allows any provider",
+			policy:       nil,
+			info:         &providers.ProviderInfo{ReliabilityTier: providers.ReliabilityTierCommunity},
+			wantExcluded: false,
+		},
+		{
+			name:         "enterprise provider meets verified minimum",
+			policy:       &tgpv1.QualityPolicy{MinReliabilityTier: tgpv1.ReliabilityTierVerified},
+			info:         &providers.ProviderInfo{ReliabilityTier: providers.ReliabilityTierEnterprise},
+			wantExcluded: false,
+		},
+		{
+			name:         "equal tier meets minimum",
+			policy:       &tgpv1.QualityPolicy{MinReliabilityTier: tgpv1.ReliabilityTierVerified},
+			info:         &providers.ProviderInfo{ReliabilityTier: providers.ReliabilityTierVerified},
+			wantExcluded: false,
+		},
+		{
+			name:         "community provider is below verified minimum",
+			policy:       &tgpv1.QualityPolicy{MinReliabilityTier: tgpv1.ReliabilityTierVerified},
+			info:         &providers.ProviderInfo{ReliabilityTier: providers.ReliabilityTierCommunity},
+			wantExcluded: true,
+		},
+		{
+			name:         "unknown tier is treated as community",
+			policy:       &tgpv1.QualityPolicy{MinReliabilityTier: tgpv1.ReliabilityTierVerified},
+			info:         &providers.ProviderInfo{},
+			wantExcluded: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := qualityExclusionReason(tt.policy, tt.info)
+			if (reason != "") != tt.wantExcluded {
+				t.Errorf("qualityExclusionReason() = %q, wantExcluded %v", reason, tt.wantExcluded)
+			}
+		})
+	}
+}
+
+func TestFilterOffersByQuality(t *testing.T) {
+	offers := []providers.GPUOffer{
+		{ID: "verified", Verified: true},
+		{ID: "unverified", Verified: false},
+	}
+
+	if got := filterOffersByQuality(nil, offers); len(got) != 2 {
+		t.Errorf("filterOffersByQuality() without policy returned %d offers, want 2", len(got))
+	}
+
+	got := filterOffersByQuality(&tgpv1.QualityPolicy{VerifiedOnly: true}, offers)
+	if len(got) != 1 || got[0].ID != "verified" {
+		t.Errorf("filterOffersByQuality() with VerifiedOnly = %v, want only the verified offer", got)
+	}
+}
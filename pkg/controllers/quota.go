@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/config"
+)
+
+const (
+	// quotaNearLimitThreshold is the utilization above which a provider is deprioritized
+	quotaNearLimitThreshold = 0.8
+
+	// quotaMaxPenalty is the price penalty applied to a provider one launch away from its limit,
+	// as a fraction of its price
+	quotaMaxPenalty = 1.0
+)
+
+// quotaTracker counts launches in progress per provider, so concurrent reconciles see
+// instances that do not have a node yet. The zero value is ready to use.
+type quotaTracker struct {
+	mutex    sync.Mutex
+	inFlight map[string]int
+}
+
+// begin records a launch in progress with the provider and returns a function that ends it
+func (q *quotaTracker) begin(provider string) func() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.inFlight == nil {
+		q.inFlight = make(map[string]int)
+	}
+	q.inFlight[provider]++
+
+	return func() {
+		q.mutex.Lock()
+		defer q.mutex.Unlock()
+		q.inFlight[provider]--
+	}
+}
+
+// launching returns the number of launches in progress with the provider
+func (q *quotaTracker) launching(provider string) int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.inFlight[provider]
+}
+
+// runningInstancesByProvider counts the operator's nodes per provider across all pools
+func (r *GPUNodePoolReconciler) runningInstancesByProvider(ctx context.Context) (map[string]int, error) {
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes, client.HasLabels{tgpv1.NodeLabelProvider}); err != nil {
+		return nil, fmt.Errorf("failed to list provisioned nodes: %w", err)
+	}
+
+	counts := make(map[string]int)
+	for _, node := range nodes.Items {
+		counts[node.Labels[tgpv1.NodeLabelProvider]]++
+	}
+	return counts, nil
+}
+
+// quotaUtilization returns the fraction of the provider's instance limit in use, counting
+// running and in-flight instances, and whether the provider has a limit at all
+func (r *GPUNodePoolReconciler) quotaUtilization(provider string, running map[string]int) (float64, bool) {
+	maxInstances := config.Current(r.Config).MaxInstances(provider)
+	if maxInstances <= 0 {
+		return 0, false
+	}
+	used := running[provider] + r.quota.launching(provider)
+	return float64(used) / float64(maxInstances), true
+}
+
+// quotaPenalty returns the multiplier applied to a provider's price at the given utilization.
+// Providers below the near-limit threshold are not penalized; above it the penalty grows
+// linearly so launches spread to providers with headroom before any one is saturated.
+func quotaPenalty(utilization float64) float64 {
+	if utilization <= quotaNearLimitThreshold {
+		return 1.0
+	}
+	return 1.0 + quotaMaxPenalty*(utilization-quotaNearLimitThreshold)/(1.0-quotaNearLimitThreshold)
+}
@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/config"
+)
+
+func TestQuotaPenalty(t *testing.T) {
+	tests := []struct {
+		utilization float64
+		want        float64
+	}{
+		{0, 1.0},
+		{0.8, 1.0},
+		{0.9, 1.5},
+		{1.0, 2.0},
+	}
+
+	for _, tt := range tests {
+		if got := quotaPenalty(tt.utilization); got < tt.want-1e-9 || got > tt.want+1e-9 {
+			t.Errorf("quotaPenalty(%v) = %v, want %v", tt.utilization, got, tt.want)
+		}
+	}
+}
+
+func TestQuotaUtilization(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	node := func(name, provider string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{tgpv1.NodeLabelProvider: provider},
+		}}
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Providers.Vultr.MaxInstances = 4
+	r := &GPUNodePoolReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			node("vultr-a", "vultr"),
+			node("vultr-b", "vultr"),
+			node("gcp-a", "gcp"),
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "unmanaged"}},
+		).Build(),
+		Config: cfg,
+	}
+
+	running, err := r.runningInstancesByProvider(context.Background())
+	if err != nil {
+		t.Fatalf("runningInstancesByProvider() error = %v", err)
+	}
+	if running["vultr"] != 2 || running["gcp"] != 1 || len(running) != 2 {
+		t.Errorf("runningInstancesByProvider() = %v, want 2 vultr and 1 gcp", running)
+	}
+
+	end := r.quota.begin("vultr")
+	if utilization, limited := r.quotaUtilization("vultr", running); !limited || utilization != 0.75 {
+		t.Errorf("quotaUtilization(vultr) = %v, %v, want 0.75 counting the launch in flight", utilization, limited)
+	}
+	end()
+	if utilization, _ := r.quotaUtilization("vultr", running); utilization != 0.5 {
+		t.Errorf("quotaUtilization(vultr) = %v after the launch ended, want 0.5", utilization)
+	}
+
+	if _, limited := r.quotaUtilization("gcp", running); limited {
+		t.Error("expected gcp without maxInstances to be unlimited")
+	}
+}
@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"errors"
+	"sync"
+
+	"golang.org/x/time/rate"
+
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// errInventoryRateLimited is returned instead of querying a provider whose rate limit has no
+// requests left, so the inventory refresh for that provider is skipped rather than waited for
+var errInventoryRateLimited = errors.New("provider API rate limit reached")
+
+// isInventoryRateLimited reports whether an inventory query was skipped by the rate limit
+func isInventoryRateLimited(err error) bool {
+	return errors.Is(err, errInventoryRateLimited)
+}
+
+// providerRateLimiters holds a token bucket per provider, seeded from the provider's
+// published rate limits, so bursts of reconciles do not trip provider 429s.
+// The zero value is ready to use.
+type providerRateLimiters struct {
+	limiters sync.Map
+}
+
+// allow reports whether a request to the provider may be made now, taking a token if so
+func (l *providerRateLimiters) allow(client providers.ProviderClient) bool {
+	providerName := client.GetProviderInfo().Name
+	if limiter, ok := l.limiters.Load(providerName); ok {
+		return limiter.(*rate.Limiter).Allow()
+	}
+
+	limiter, _ := l.limiters.LoadOrStore(providerName, newProviderLimiter(client.GetRateLimits()))
+	return limiter.(*rate.Limiter).Allow()
+}
+
+// newProviderLimiter creates a token bucket refilled at the provider's requests per second
+// holding up to its burst capacity. Providers without published limits are not limited.
+func newProviderLimiter(limits *providers.RateLimitInfo) *rate.Limiter {
+	if limits == nil || limits.RequestsPerSecond <= 0 {
+		return rate.NewLimiter(rate.Inf, 0)
+	}
+	return rate.NewLimiter(rate.Limit(limits.RequestsPerSecond), max(limits.BurstCapacity, 1))
+}
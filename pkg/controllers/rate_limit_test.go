@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/pricing"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// limitedClient publishes fixed rate limits and counts inventory queries
+type limitedClient struct {
+	providers.ProviderClient
+	limits *providers.RateLimitInfo
+	calls  int
+}
+
+func (c *limitedClient) GetProviderInfo() *providers.ProviderInfo {
+	return &providers.ProviderInfo{Name: "limited"}
+}
+
+func (c *limitedClient) GetRateLimits() *providers.RateLimitInfo {
+	return c.limits
+}
+
+func (c *limitedClient) ListAvailableGPUs(ctx context.Context, filters *providers.GPUFilters) ([]providers.GPUOffer, error) {
+	c.calls++
+	return []providers.GPUOffer{{GPUType: "H100", Region: "us-east", HourlyPrice: 2}}, nil
+}
+
+func TestProviderRateLimiters(t *testing.T) {
+	var limiters providerRateLimiters
+	client := &limitedClient{limits: &providers.RateLimitInfo{RequestsPerSecond: 1, BurstCapacity: 2}}
+
+	if !limiters.allow(client) || !limiters.allow(client) {
+		t.Fatal("expected the burst capacity to be allowed")
+	}
+	if limiters.allow(client) {
+		t.Error("expected requests past the burst capacity to be refused")
+	}
+
+	// The bucket belongs to the provider, not the client, so a new client shares it
+	if limiters.allow(&limitedClient{limits: client.limits}) {
+		t.Error("expected a new client for the provider to share the exhausted bucket")
+	}
+
+	unlimited := newProviderLimiter(nil)
+	for i := 0; i < 100; i++ {
+		if !unlimited.Allow() {
+			t.Fatal("expected providers without published limits not to be limited")
+		}
+	}
+}
+
+func TestListOffersRateLimited(t *testing.T) {
+	ctx := context.Background()
+	nodeClass := &tgpv1.GPUNodeClass{}
+	client := &limitedClient{limits: &providers.RateLimitInfo{RequestsPerSecond: 1, BurstCapacity: 1}}
+
+	t.Run("without inventory cache", func(t *testing.T) {
+		r := &GPUNodeClassReconciler{}
+		if _, _, err := r.listOffers(ctx, nodeClass, "limited", "default", client); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, _, err := r.listOffers(ctx, nodeClass, "limited", "default", client); !isInventoryRateLimited(err) {
+			t.Errorf("expected rate limited error, got %v", err)
+		}
+		if client.calls != 1 {
+			t.Errorf("expected 1 provider query, got %d", client.calls)
+		}
+	})
+
+	t.Run("cached offers do not use tokens", func(t *testing.T) {
+		client.calls = 0
+		r := &GPUNodeClassReconciler{Inventory: pricing.NewInventoryCache(time.Minute)}
+		for i := 0; i < 3; i++ {
+			offers, _, err := r.listOffers(ctx, nodeClass, "limited", "default", client)
+			if err != nil || len(offers) != 1 {
+				t.Fatalf("call %d: expected cached offers, got %v, %v", i+1, offers, err)
+			}
+		}
+		if client.calls != 1 {
+			t.Errorf("expected 1 provider query, got %d", client.calls)
+		}
+
+		// An expired entry needs a token again
+		r.Inventory.ExpireProvider("limited")
+		if !r.Inventory.NeedsRefresh("limited/default/") {
+			t.Error("expected expired offers to need a refresh")
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+const (
+	// defaultReadinessProbeTimeout bounds a readiness probe attempt when the pool sets no timeout
+	defaultReadinessProbeTimeout = 5 * time.Second
+
+	// maxReadinessProbeTimeout caps the pool's probe timeout. Probes run synchronously in the
+	// reconcile loop, so a slow node must not hold up the pool's other work.
+	maxReadinessProbeTimeout = 10 * time.Second
+)
+
+// probeNodeReadiness runs the pool's readiness probe against the node, returning why it
+// failed. Pools without a probe only wait for the kubelet, so every node passes.
+func probeNodeReadiness(ctx context.Context, nodePool *tgpv1.GPUNodePool, node *corev1.Node) error {
+	probe := nodePool.Spec.ReadinessProbe
+	if probe == nil {
+		return nil
+	}
+
+	address := probeAddress(node)
+	if address == "" {
+		return fmt.Errorf("node %s has no address to probe", node.Name)
+	}
+	target := net.JoinHostPort(address, strconv.Itoa(int(probe.Port)))
+
+	ctx, cancel := context.WithTimeout(ctx, readinessProbeTimeout(probe))
+	defer cancel()
+
+	if probe.HTTPPath == "" {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", target)
+		if err != nil {
+			return fmt.Errorf("tcp probe of %s failed: %w", target, err)
+		}
+		return conn.Close()
+	}
+
+	url := "http://" + target + probe.HTTPPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("invalid http probe %s: %w", url, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("http probe of %s failed: %w", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("http probe of %s returned %s", url, resp.Status)
+	}
+	return nil
+}
+
+// readinessProbeTimeout returns how long a probe attempt may take: the pool's timeout, or
+// defaultReadinessProbeTimeout, capped at maxReadinessProbeTimeout
+func readinessProbeTimeout(probe *tgpv1.NodeReadinessProbe) time.Duration {
+	if probe.Timeout == nil || probe.Timeout.Duration <= 0 {
+		return defaultReadinessProbeTimeout
+	}
+	return min(probe.Timeout.Duration, maxReadinessProbeTimeout)
+}
+
+// probeAddress returns the node's external address, falling back to its internal address
+func probeAddress(node *corev1.Node) string {
+	var internal string
+	for _, address := range node.Status.Addresses {
+		switch address.Type {
+		case corev1.NodeExternalIP:
+			return address.Address
+		case corev1.NodeInternalIP:
+			if internal == "" {
+				internal = address.Address
+			}
+		}
+	}
+	return internal
+}
@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+func TestProbeNodeReadiness(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	host, portString, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to parse server address: %v", err)
+	}
+	port, _ := strconv.Atoi(portString)
+
+	// A port nothing listens on, taken from a closed listener
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	closedPort := closed.Addr().(*net.TCPAddr).Port
+	_ = closed.Close()
+
+	node := launchedNode("node", corev1.ConditionTrue, host)
+
+	tests := []struct {
+		name    string
+		probe   *tgpv1.NodeReadinessProbe
+		wantErr bool
+	}{
+		{"no probe", nil, false},
+		{"tcp port open", &tgpv1.NodeReadinessProbe{Port: int32(port)}, false},
+		{"tcp port closed", &tgpv1.NodeReadinessProbe{Port: int32(closedPort)}, true},
+		{"http healthy", &tgpv1.NodeReadinessProbe{Port: int32(port), HTTPPath: "/healthz"}, false},
+		{"http unhealthy", &tgpv1.NodeReadinessProbe{Port: int32(port), HTTPPath: "/ready"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodePool := &tgpv1.GPUNodePool{Spec: tgpv1.GPUNodePoolSpec{ReadinessProbe: tt.probe}}
+			err := probeNodeReadiness(context.Background(), nodePool, node)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("probeNodeReadiness() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadinessProbeTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout *metav1.Duration
+		want    time.Duration
+	}{
+		{"unset", nil, defaultReadinessProbeTimeout},
+		{"zero", &metav1.Duration{}, defaultReadinessProbeTimeout},
+		{"short", &metav1.Duration{Duration: 2 * time.Second}, 2 * time.Second},
+		{"capped", &metav1.Duration{Duration: time.Minute}, maxReadinessProbeTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := readinessProbeTimeout(&tgpv1.NodeReadinessProbe{Port: 22, Timeout: tt.timeout}); got != tt.want {
+				t.Errorf("readinessProbeTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileNodeReadinessWaitsForProbe(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = tgpv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	closedPort := closed.Addr().(*net.TCPAddr).Port
+	_ = closed.Close()
+
+	nodePool := &tgpv1.GPUNodePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pool", Namespace: "default", UID: "pool-uid"},
+		Spec:       tgpv1.GPUNodePoolSpec{ReadinessProbe: &tgpv1.NodeReadinessProbe{Port: int32(closedPort)}},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(launchedNode("joined", corev1.ConditionTrue, "127.0.0.1")).Build()
+	r := &GPUNodePoolReconciler{Client: client, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.Background()
+	next, err := r.reconcileNodeReadiness(ctx, nodePool, logr.Discard())
+	if err != nil {
+		t.Fatalf("reconcileNodeReadiness failed: %v", err)
+	}
+	if next != nodeReadyPollInterval {
+		t.Errorf("expected the node to be checked again in %s, got %s", nodeReadyPollInterval, next)
+	}
+
+	var node corev1.Node
+	if err := client.Get(ctx, types.NamespacedName{Name: "joined"}, &node); err != nil {
+		t.Fatalf("failed to get node: %v", err)
+	}
+	if !node.Spec.Unschedulable || node.Annotations[AnnotationAwaitingReady] == "" {
+		t.Error("expected the node to stay cordoned until it passes its readiness probe")
+	}
+	if nodeInstancePhase(&node) != tgpv1.PoolInstancePhaseRegistered {
+		t.Errorf("expected the instance to stay Registered, got %s", nodeInstancePhase(&node))
+	}
+}
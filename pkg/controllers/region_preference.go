@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// preferredRegions returns the regions to try for a requirement, in order of preference: the
+// region the pod asked for, the values of the pool's tgp.io/region requirement in the order
+// they are listed, or the node class's allowed regions. Pool regions the class does not allow
+// are skipped. An empty region leaves the choice to the providers.
+func preferredRegions(nodePool *tgpv1.GPUNodePool, nodeClass *tgpv1.GPUNodeClass, requirement *GPURequirement) []string {
+	if requirement.Region != "" {
+		return []string{requirement.Region}
+	}
+	requirements := nodeClass.Spec.InstanceRequirements
+	for _, req := range nodePool.Spec.Template.Spec.Requirements {
+		if req.Key == tgpv1.NodeLabelRegion && req.Operator == tgpv1.NodeSelectorOpIn && len(req.Values) > 0 {
+			var allowed []string
+			for _, region := range req.Values {
+				if regionAllowed(requirements, region) {
+					allowed = append(allowed, region)
+				}
+			}
+			if len(allowed) == 0 {
+				// Selection reports that the class rules them out
+				return req.Values
+			}
+			return allowed
+		}
+	}
+	if requirements != nil && len(requirements.Regions) > 0 {
+		return requirements.Regions
+	}
+	return []string{""}
+}
+
+// poolMaxHourlyPrice returns the pool's maximum hourly price, if it sets a valid one
+func poolMaxHourlyPrice(nodePool *tgpv1.GPUNodePool) (float64, bool) {
+	if nodePool.Spec.MaxHourlyPrice == nil {
+		return 0, false
+	}
+	price, err := strconv.ParseFloat(*nodePool.Spec.MaxHourlyPrice, 64)
+	if err != nil {
+		return 0, false
+	}
+	return price, true
+}
+
+// selectProviderInPreferredRegions tries the regions in order and selects a provider in the first
+// one with capacity for the requirement within the pool's maximum hourly price. The chosen region
+// is left in requirement.Region.
+func (r *GPUNodePoolReconciler) selectProviderInPreferredRegions(ctx context.Context, nodePool *tgpv1.GPUNodePool, nodeClass *tgpv1.GPUNodeClass, pod *corev1.Pod, requirement *GPURequirement, regions []string, log logr.Logger) (*tgpv1.ProviderConfig, providers.ProviderClient, error) {
+	requestedGPUType := requirement.GPUType
+	maxPrice, capped := poolMaxHourlyPrice(nodePool)
+
+	var lastErr error
+	for _, region := range regions {
+		requirement.Region = region
+		requirement.GPUType, requirement.RequestedGPUType = requestedGPUType, ""
+
+		provider, client, err := r.selectProviderWithFallback(ctx, nodePool, nodeClass, pod, requirement, log)
+		if err == nil && capped && requirement.HourlyPrice > maxPrice {
+			err = fmt.Errorf("%w for GPU type %s: cheapest offer in region %s costs %.4f/h, above the pool maximum of %.4f/h",
+				errNoSuitableProvider, requirement.GPUType, region, requirement.HourlyPrice, maxPrice)
+		}
+		if err == nil {
+			return provider, client, nil
+		}
+		if !errors.Is(err, errNoSuitableProvider) {
+			return nil, nil, err
+		}
+
+		lastErr = err
+		if len(regions) > 1 {
+			log.Info("No suitable provider in preferred region, trying the next", "region", region, "reason", err.Error())
+		}
+	}
+	return nil, nil, lastErr
+}
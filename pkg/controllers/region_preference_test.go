@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"slices"
+	"testing"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+func TestPreferredRegions(t *testing.T) {
+	pool := func(requirements ...tgpv1.NodeSelectorRequirement) *tgpv1.GPUNodePool {
+		return &tgpv1.GPUNodePool{Spec: tgpv1.GPUNodePoolSpec{
+			Template: tgpv1.NodePoolTemplate{Spec: tgpv1.NodeSpec{Requirements: requirements}},
+		}}
+	}
+	regionsIn := tgpv1.NodeSelectorRequirement{Key: tgpv1.NodeLabelRegion, Operator: tgpv1.NodeSelectorOpIn, Values: []string{"ewr", "ord", "lax"}}
+	regionsNotIn := tgpv1.NodeSelectorRequirement{Key: tgpv1.NodeLabelRegion, Operator: tgpv1.NodeSelectorOpNotIn, Values: []string{"fra"}}
+
+	class := func(regions ...string) *tgpv1.GPUNodeClass {
+		return &tgpv1.GPUNodeClass{Spec: tgpv1.GPUNodeClassSpec{
+			InstanceRequirements: &tgpv1.InstanceRequirements{Regions: regions},
+		}}
+	}
+
+	tests := []struct {
+		name        string
+		nodePool    *tgpv1.GPUNodePool
+		nodeClass   *tgpv1.GPUNodeClass
+		requirement *GPURequirement
+		want        []string
+	}{
+		{"pod region wins", pool(regionsIn), class(), &GPURequirement{Region: "sea"}, []string{"sea"}},
+		{"pool regions in listed order", pool(regionsIn), class(), &GPURequirement{}, []string{"ewr", "ord", "lax"}},
+		{"excluded regions are not preferences", pool(regionsNotIn), class(), &GPURequirement{}, []string{""}},
+		{"no region requirement", pool(), class(), &GPURequirement{}, []string{""}},
+		{"pool regions the class disallows are skipped", pool(regionsIn), class("lax", "ewr"), &GPURequirement{}, []string{"ewr", "lax"}},
+		{"class regions without pool regions", pool(), class("fra", "ams"), &GPURequirement{}, []string{"fra", "ams"}},
+		{"pool regions all disallowed", pool(regionsIn), class("fra"), &GPURequirement{}, []string{"ewr", "ord", "lax"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := preferredRegions(tt.nodePool, tt.nodeClass, tt.requirement); !slices.Equal(got, tt.want) {
+				t.Errorf("preferredRegions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPoolMaxHourlyPrice(t *testing.T) {
+	if _, capped := poolMaxHourlyPrice(&tgpv1.GPUNodePool{}); capped {
+		t.Error("expected pools without maxHourlyPrice to be uncapped")
+	}
+	if _, capped := poolMaxHourlyPrice(&tgpv1.GPUNodePool{Spec: tgpv1.GPUNodePoolSpec{MaxHourlyPrice: stringPtr("cheap")}}); capped {
+		t.Error("expected an invalid maxHourlyPrice to be ignored")
+	}
+	if price, capped := poolMaxHourlyPrice(&tgpv1.GPUNodePool{Spec: tgpv1.GPUNodePoolSpec{MaxHourlyPrice: stringPtr("2.5")}}); !capped || price != 2.5 {
+		t.Errorf("poolMaxHourlyPrice() = %v, %v, want 2.5, true", price, capped)
+	}
+}
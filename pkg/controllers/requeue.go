@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/solanyn/tgp-operator/pkg/metrics"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// Requeue reasons recorded in status and the reconcile_requeue_total metric
+const (
+	RequeueReasonPeriodicResync      = "periodic_resync"
+	RequeueReasonValidationFailed    = "validation_failed"
+	RequeueReasonDeletionBlocked     = "deletion_blocked"
+	RequeueReasonNodeClassNotFound   = "node_class_not_found"
+	RequeueReasonNoCapacity          = "no_capacity"
+	RequeueReasonRateLimited         = "rate_limited"
+	RequeueReasonProvisioningFailed  = "provisioning_failed"
+	RequeueReasonNodeExpiring        = "node_expiring"
+	RequeueReasonPendingTimeout      = "pending_timeout"
+	RequeueReasonConsolidating       = "consolidating"
+	RequeueReasonLimitExceeded       = "limit_exceeded"
+	RequeueReasonInstanceInterrupted = "instance_interrupted"
+	RequeueReasonNodeJoining         = "node_joining"
+	RequeueReasonDraining            = "draining"
+)
+
+// Controller names used as metric labels
+const (
+	controllerNameGPUNodeClass = "gpunodeclass"
+	controllerNameGPUNodePool  = "gpunodepool"
+)
+
+// errNoSuitableProvider is returned when no provider can satisfy a GPU requirement
+var errNoSuitableProvider = errors.New("no suitable provider found")
+
+// requeueJitter is the fraction by which periodic requeue intervals are randomly lengthened or
+// shortened, so objects reconciled together after a restart drift apart instead of hitting the
+// provider APIs in lockstep
+const requeueJitter = 0.2
+
+// jitter randomly spreads a periodic interval by up to requeueJitter either way
+func jitter(interval time.Duration) time.Duration {
+	spread := time.Duration(float64(interval) * requeueJitter)
+	if spread <= 0 {
+		return interval
+	}
+	return interval - spread + rand.N(2*spread+1)
+}
+
+// requeueAfter records the requeue reason metric and returns a result that requeues after the given duration
+func requeueAfter(m *metrics.Metrics, controller, reason string, after time.Duration) ctrl.Result {
+	m.RecordReconcileRequeue(controller, reason)
+	return ctrl.Result{RequeueAfter: after}
+}
+
+// requeuePeriodically is requeueAfter for periodic checks, whose interval is jittered
+func requeuePeriodically(m *metrics.Metrics, controller, reason string, interval time.Duration) ctrl.Result {
+	return requeueAfter(m, controller, reason, jitter(interval))
+}
+
+// provisioningRequeueReason classifies a provisioning error into a requeue reason
+func provisioningRequeueReason(err error) string {
+	if errors.Is(err, errNoSuitableProvider) {
+		return RequeueReasonNoCapacity
+	}
+	if errors.Is(err, errClassLimitExceeded) {
+		return RequeueReasonLimitExceeded
+	}
+	if errors.Is(err, errInvalidMachineConfig) {
+		return RequeueReasonValidationFailed
+	}
+	if retriable, errType := providers.IsRetriableError(err); retriable && errType == providers.RetriableErrorRateLimit {
+		return RequeueReasonRateLimited
+	}
+	return RequeueReasonProvisioningFailed
+}
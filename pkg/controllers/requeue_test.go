@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestProvisioningRequeueReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			name:     "no suitable provider",
+			err:      fmt.Errorf("failed to select provider: %w", fmt.Errorf("%w for GPU type A100", errNoSuitableProvider)),
+			expected: RequeueReasonNoCapacity,
+		},
+		{
+			name:     "provider rate limit",
+			err:      errors.New("failed to launch instance: rate limit exceeded"),
+			expected: RequeueReasonRateLimited,
+		},
+		{
+			name:     "generic failure",
+			err:      errors.New("failed to create Kubernetes node: conflict"),
+			expected: RequeueReasonProvisioningFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := provisioningRequeueReason(tt.err); got != tt.expected {
+				t.Errorf("expected reason %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRequeueAfterWithNilMetrics(t *testing.T) {
+	result := requeueAfter(nil, controllerNameGPUNodePool, RequeueReasonPeriodicResync, 0)
+	if result.RequeueAfter != 0 {
+		t.Errorf("expected zero requeue, got %v", result.RequeueAfter)
+	}
+}
+
+func TestJitter(t *testing.T) {
+	interval := 10 * time.Minute
+	seen := make(map[time.Duration]bool)
+	for range 100 {
+		got := jitter(interval)
+		if got < 8*time.Minute || got > 12*time.Minute {
+			t.Fatalf("jitter(%v) = %v, want within 20%%", interval, got)
+		}
+		seen[got] = true
+	}
+	if len(seen) < 2 {
+		t.Error("expected jittered intervals to vary")
+	}
+
+	if got := jitter(0); got != 0 {
+		t.Errorf("jitter(0) = %v, want 0", got)
+	}
+}
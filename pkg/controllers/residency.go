@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// allowedCountries returns the countries the class instance requirements allow, or nil if unrestricted
+func allowedCountries(requirements *tgpv1.InstanceRequirements) []string {
+	if requirements == nil {
+		return nil
+	}
+	return requirements.Countries
+}
+
+// residencyExclusionReason returns why launching in the region would violate the countries
+// allowed by the class, or an empty string if it complies
+func residencyExclusionReason(requirements *tgpv1.InstanceRequirements, client providers.ProviderClient, region string) string {
+	countries := allowedCountries(requirements)
+	if len(countries) == 0 {
+		return ""
+	}
+
+	locator, ok := providers.Unwrap(client).(providers.RegionLocator)
+	if !ok {
+		return "provider cannot report which country its regions are in"
+	}
+	if region == "" {
+		return "no region is selected to check against data residency"
+	}
+
+	country, known := locator.RegionCountry(region)
+	if !known {
+		return fmt.Sprintf("country of region %s is unknown", region)
+	}
+	if !providers.CountryAllowed(country, countries) {
+		return fmt.Sprintf("region %s is in %s, outside the allowed countries %s", region, country, strings.Join(countries, ", "))
+	}
+	return ""
+}
+
+// filterOffersByResidency drops offers outside the countries allowed by the class
+func filterOffersByResidency(requirements *tgpv1.InstanceRequirements, offers []providers.GPUOffer) []providers.GPUOffer {
+	countries := allowedCountries(requirements)
+	if len(countries) == 0 {
+		return offers
+	}
+
+	filtered := make([]providers.GPUOffer, 0, len(offers))
+	for _, offer := range offers {
+		if providers.CountryAllowed(offer.Country, countries) {
+			filtered = append(filtered, offer)
+		}
+	}
+	return filtered
+}
+
+// isNotAvailableInRegion reports whether a provider failed because it does not offer the GPU type in the region
+func isNotAvailableInRegion(err error) bool {
+	return errors.Is(err, providers.ErrNotAvailableInRegion)
+}
@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"testing"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+	"github.com/solanyn/tgp-operator/pkg/providers/gcp"
+)
+
+func TestResidencyExclusionReason(t *testing.T) {
+	euOnly := &tgpv1.InstanceRequirements{Countries: []string{"DE", "FR"}}
+	gcpClient := gcp.NewClient("")
+
+	tests := []struct {
+		name          string
+		requirements  *tgpv1.InstanceRequirements
+		client        providers.ProviderClient
+		region        string
+		expectExclude bool
+	}{
+		{
+			name:         "no restriction allows any region",
+			requirements: nil,
+			client:       gcpClient,
+			region:       "us-central1",
+		},
+		{
+			name:         "region in an allowed country is allowed",
+			requirements: euOnly,
+			client:       gcpClient,
+			region:       "europe-west3",
+		},
+		{
+			name:          "region outside allowed countries is excluded",
+			requirements:  euOnly,
+			client:        gcpClient,
+			region:        "us-central1",
+			expectExclude: true,
+		},
+		{
+			name:          "unknown region is excluded",
+			requirements:  euOnly,
+			client:        gcpClient,
+			region:        "mars-north1",
+			expectExclude: true,
+		},
+		{
+			name:          "unselected region is excluded",
+			requirements:  euOnly,
+			client:        gcpClient,
+			expectExclude: true,
+		},
+		{
+			name:          "provider without region locations is excluded",
+			requirements:  euOnly,
+			client:        &countingTranslator{},
+			region:        "europe-west3",
+			expectExclude: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := residencyExclusionReason(tt.requirements, tt.client, tt.region)
+			if (reason != "") != tt.expectExclude {
+				t.Errorf("residencyExclusionReason() = %q, expectExclude %v", reason, tt.expectExclude)
+			}
+		})
+	}
+}
+
+func TestFilterOffersByResidency(t *testing.T) {
+	offers := []providers.GPUOffer{
+		{ID: "de", Country: "DE"},
+		{ID: "us", Country: "US"},
+		{ID: "unknown"},
+	}
+
+	if filtered := filterOffersByResidency(nil, offers); len(filtered) != 3 {
+		t.Errorf("filterOffersByResidency() without restriction = %d offers, want 3", len(filtered))
+	}
+
+	filtered := filterOffersByResidency(&tgpv1.InstanceRequirements{Countries: []string{"DE"}}, offers)
+	if len(filtered) != 1 || filtered[0].ID != "de" {
+		t.Errorf("filterOffersByResidency() = %v, want only the DE offer", filtered)
+	}
+}
@@ -0,0 +1,245 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// gpuResourceNames are the extended resources used to request GPUs
+var gpuResourceNames = []corev1.ResourceName{"nvidia.com/gpu", "amd.com/gpu", providers.ResourceTGPGPU}
+
+// errPodWouldNotSchedule is returned when a pod would not bind to the node we are about to launch
+var errPodWouldNotSchedule = errors.New("pod would not schedule on planned node")
+
+// isSchedulingMismatch reports whether err came from a failed scheduling simulation
+func isSchedulingMismatch(err error) bool {
+	return errors.Is(err, errPodWouldNotSchedule)
+}
+
+// validateTemplateTaints checks that the taints in the pool template can be applied to a node
+func validateTemplateTaints(nodePool *tgpv1.GPUNodePool) error {
+	taints := append([]corev1.Taint{}, nodePool.Spec.Template.Spec.Taints...)
+	taints = append(taints, nodePool.Spec.Template.Spec.StartupTaints...)
+
+	for _, taint := range taints {
+		if taint.Key == "" {
+			return fmt.Errorf("taint with value %q has an empty key", taint.Value)
+		}
+		switch taint.Effect {
+		case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+		default:
+			return fmt.Errorf("taint %s has unsupported effect %q, must be one of %s, %s or %s",
+				taint.Key, taint.Effect, corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute)
+		}
+	}
+	return nil
+}
+
+// poolNodeSelector selects the nodes launched by the pool. Pools with the same name in
+// different namespaces are told apart by the namespace label.
+func poolNodeSelector(nodePool *tgpv1.GPUNodePool) client.MatchingLabels {
+	return client.MatchingLabels{
+		tgpv1.NodeLabelNodePool:          nodePool.Name,
+		tgpv1.NodeLabelNodePoolNamespace: nodePool.Namespace,
+	}
+}
+
+// buildNodeLabels builds the labels applied to nodes launched by this pool
+func buildNodeLabels(nodePool *tgpv1.GPUNodePool, requirement *GPURequirement, providerName string, spot bool) map[string]string {
+	labels := map[string]string{
+		"tgp.io/nodepool":                  nodePool.Name,
+		tgpv1.NodeLabelNodePoolNamespace:   nodePool.Namespace,
+		tgpv1.NodeLabelNodeClass:           nodePool.Spec.NodeClassRef.Name,
+		tgpv1.NodeLabelProvider:            providerName,
+		tgpv1.NodeLabelGPUType:             requirement.GPUType,
+		tgpv1.NodeLabelSpot:                strconv.FormatBool(spot),
+		"kubernetes.io/arch":               "amd64",
+		"kubernetes.io/os":                 "linux",
+		"node.kubernetes.io/instance-type": "gpu",
+	}
+	if requirement.Region != "" {
+		labels[tgpv1.NodeLabelRegion] = requirement.Region
+	}
+
+	// Single-valued In requirements pin the label value for every node in the pool
+	for _, req := range nodePool.Spec.Template.Spec.Requirements {
+		if req.Operator == tgpv1.NodeSelectorOpIn && len(req.Values) == 1 {
+			if _, exists := labels[req.Key]; !exists {
+				labels[req.Key] = req.Values[0]
+			}
+		}
+	}
+
+	if nodePool.Spec.Template.Metadata != nil {
+		for k, v := range nodePool.Spec.Template.Metadata.Labels {
+			labels[k] = v
+		}
+	}
+
+	return labels
+}
+
+// buildPlannedNode builds the node that would be created for a requirement, without provider-assigned fields
+func (r *GPUNodePoolReconciler) buildPlannedNode(nodePool *tgpv1.GPUNodePool, requirement *GPURequirement, providerName string) *corev1.Node {
+	vendorResource := corev1.ResourceName("nvidia.com/gpu")
+	if strings.HasPrefix(strings.ToUpper(requirement.GPUType), "AMD") {
+		vendorResource = "amd.com/gpu"
+	}
+
+	// Providers launch an instance with at least the requested number of GPUs
+	gpus := *resource.NewQuantity(int64(max(requirement.GPUCount, 1)), resource.DecimalSI)
+
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   fmt.Sprintf("tgp-%s-planned", nodePool.Name),
+			Labels: buildNodeLabels(nodePool, requirement, providerName, requirement.Spot),
+		},
+		Spec: corev1.NodeSpec{
+			Taints: nodePool.Spec.Template.Spec.Taints,
+		},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				vendorResource:           gpus,
+				providers.ResourceTGPGPU: gpus,
+			},
+		},
+	}
+}
+
+// simulatePodScheduling checks whether the pod would bind to the given node, evaluating
+// the pod's nodeSelector, required node affinity, tolerations and GPU requests.
+// It returns an error wrapping errPodWouldNotSchedule describing the first mismatch.
+func simulatePodScheduling(pod *corev1.Pod, node *corev1.Node) error {
+	for key, value := range pod.Spec.NodeSelector {
+		if nodeValue, exists := node.Labels[key]; !exists || nodeValue != value {
+			return fmt.Errorf("%w: node selector %s=%s does not match", errPodWouldNotSchedule, key, value)
+		}
+	}
+
+	if affinity := pod.Spec.Affinity; affinity != nil && affinity.NodeAffinity != nil {
+		if required := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution; required != nil {
+			matched := false
+			for _, term := range required.NodeSelectorTerms {
+				if nodeSelectorTermMatches(term, node) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return fmt.Errorf("%w: required node affinity does not match", errPodWouldNotSchedule)
+			}
+		}
+	}
+
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		tolerated := false
+		for _, toleration := range pod.Spec.Tolerations {
+			if toleration.ToleratesTaint(&taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return fmt.Errorf("%w: taint %s=%s:%s is not tolerated", errPodWouldNotSchedule, taint.Key, taint.Value, taint.Effect)
+		}
+	}
+
+	requests := podGPURequests(pod)
+	for name, requested := range requests {
+		allocatable, exists := node.Status.Allocatable[name]
+		if !exists || requested.Cmp(allocatable) > 0 {
+			return fmt.Errorf("%w: requests %s %s but node provides %s", errPodWouldNotSchedule, requested.String(), name, allocatable.String())
+		}
+	}
+
+	return nil
+}
+
+// nodeSelectorTermMatches checks whether all expressions and fields in the term match the node
+func nodeSelectorTermMatches(term corev1.NodeSelectorTerm, node *corev1.Node) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+	for _, expr := range term.MatchExpressions {
+		value, exists := node.Labels[expr.Key]
+		if !nodeSelectorRequirementMatches(expr, value, exists) {
+			return false
+		}
+	}
+	for _, field := range term.MatchFields {
+		if field.Key != "metadata.name" || !nodeSelectorRequirementMatches(field, node.Name, true) {
+			return false
+		}
+	}
+	return true
+}
+
+// nodeSelectorRequirementMatches evaluates a single node selector requirement against a label value
+func nodeSelectorRequirementMatches(req corev1.NodeSelectorRequirement, value string, exists bool) bool {
+	switch req.Operator {
+	case corev1.NodeSelectorOpIn:
+		return exists && containsString(req.Values, value)
+	case corev1.NodeSelectorOpNotIn:
+		return !exists || !containsString(req.Values, value)
+	case corev1.NodeSelectorOpExists:
+		return exists
+	case corev1.NodeSelectorOpDoesNotExist:
+		return !exists
+	case corev1.NodeSelectorOpGt, corev1.NodeSelectorOpLt:
+		if !exists || len(req.Values) != 1 {
+			return false
+		}
+		nodeValue, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return false
+		}
+		reqValue, err := strconv.ParseInt(req.Values[0], 10, 64)
+		if err != nil {
+			return false
+		}
+		if req.Operator == corev1.NodeSelectorOpGt {
+			return nodeValue > reqValue
+		}
+		return nodeValue < reqValue
+	default:
+		return false
+	}
+}
+
+// podGPURequests sums the GPU resource requests across the pod's containers
+func podGPURequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		for _, name := range gpuResourceNames {
+			if quantity, exists := container.Resources.Requests[name]; exists {
+				total := requests[name]
+				total.Add(quantity)
+				requests[name] = total
+			}
+		}
+	}
+	return requests
+}
+
+// containsString checks if a slice contains the given string
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,219 @@
+package controllers
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+func TestSimulatePodScheduling(t *testing.T) {
+	nodePool := &tgpv1.GPUNodePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pool"},
+		Spec: tgpv1.GPUNodePoolSpec{
+			Template: tgpv1.NodePoolTemplate{
+				Metadata: &tgpv1.NodeMetadata{
+					Labels: map[string]string{"gpu-tier": "high-end"},
+				},
+				Spec: tgpv1.NodeSpec{
+					Requirements: []tgpv1.NodeSelectorRequirement{
+						{Key: "tgp.io/zone-class", Operator: tgpv1.NodeSelectorOpIn, Values: []string{"premium"}},
+					},
+					Taints: []corev1.Taint{
+						{Key: "gpu-node", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+					},
+				},
+			},
+		},
+	}
+	requirement := &GPURequirement{GPUType: "NVIDIA_A100", GPUCount: 1, Region: "us-east"}
+
+	reconciler := &GPUNodePoolReconciler{}
+	node := reconciler.buildPlannedNode(nodePool, requirement, "vultr")
+
+	gpuToleration := corev1.Toleration{Key: "gpu-node", Operator: corev1.TolerationOpEqual, Value: "true", Effect: corev1.TaintEffectNoSchedule}
+
+	podWith := func(mutate func(pod *corev1.Pod)) *corev1.Pod {
+		pod := &corev1.Pod{
+			Spec: corev1.PodSpec{
+				Tolerations: []corev1.Toleration{gpuToleration},
+				Containers: []corev1.Container{{
+					Name: "main",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")},
+					},
+				}},
+			},
+		}
+		if mutate != nil {
+			mutate(pod)
+		}
+		return pod
+	}
+
+	tests := []struct {
+		name        string
+		pod         *corev1.Pod
+		expectError bool
+	}{
+		{
+			name: "matching pod",
+			pod: podWith(func(pod *corev1.Pod) {
+				pod.Spec.NodeSelector = map[string]string{
+					"tgp.io/gpu-type":   "NVIDIA_A100",
+					"tgp.io/region":     "us-east",
+					"gpu-tier":          "high-end",
+					"tgp.io/zone-class": "premium",
+				}
+			}),
+		},
+		{
+			name: "node selector mismatch",
+			pod: podWith(func(pod *corev1.Pod) {
+				pod.Spec.NodeSelector = map[string]string{"tgp.io/gpu-type": "NVIDIA_H100"}
+			}),
+			expectError: true,
+		},
+		{
+			name: "missing toleration",
+			pod: podWith(func(pod *corev1.Pod) {
+				pod.Spec.Tolerations = nil
+			}),
+			expectError: true,
+		},
+		{
+			name: "too many GPUs requested",
+			pod: podWith(func(pod *corev1.Pod) {
+				pod.Spec.Containers[0].Resources.Requests["nvidia.com/gpu"] = resource.MustParse("4")
+			}),
+			expectError: true,
+		},
+		{
+			name: "wrong GPU vendor",
+			pod: podWith(func(pod *corev1.Pod) {
+				pod.Spec.Containers[0].Resources.Requests = corev1.ResourceList{"amd.com/gpu": resource.MustParse("1")}
+			}),
+			expectError: true,
+		},
+		{
+			name: "required node affinity matches",
+			pod: podWith(func(pod *corev1.Pod) {
+				pod.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{
+							{MatchExpressions: []corev1.NodeSelectorRequirement{
+								{Key: "tgp.io/provider", Operator: corev1.NodeSelectorOpIn, Values: []string{"vultr", "gcp"}},
+								{Key: "tgp.io/spot", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"true"}},
+							}},
+						},
+					},
+				}}
+			}),
+		},
+		{
+			name: "required node affinity mismatch",
+			pod: podWith(func(pod *corev1.Pod) {
+				pod.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{
+							{MatchExpressions: []corev1.NodeSelectorRequirement{
+								{Key: "tgp.io/provider", Operator: corev1.NodeSelectorOpIn, Values: []string{"gcp"}},
+							}},
+						},
+					},
+				}}
+			}),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := simulatePodScheduling(tt.pod, node)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected scheduling simulation to fail")
+				}
+				if !isSchedulingMismatch(err) {
+					t.Errorf("expected scheduling mismatch error, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+	t.Run("multi-GPU node fits a multi-GPU pod", func(t *testing.T) {
+		multiGPU := reconciler.buildPlannedNode(nodePool, &GPURequirement{GPUType: "NVIDIA_A100", GPUCount: 4, Region: "us-east"}, "vultr")
+		pod := podWith(func(pod *corev1.Pod) {
+			pod.Spec.Containers[0].Resources.Requests["nvidia.com/gpu"] = resource.MustParse("4")
+		})
+		if err := simulatePodScheduling(pod, multiGPU); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+}
+
+func TestPoolNodeSelector(t *testing.T) {
+	nodePool := &tgpv1.GPUNodePool{ObjectMeta: metav1.ObjectMeta{Name: "train", Namespace: "ml"}}
+	sameName := &tgpv1.GPUNodePool{ObjectMeta: metav1.ObjectMeta{Name: "train", Namespace: "research"}}
+	nodeLabels := labels.Set(buildNodeLabels(nodePool, &GPURequirement{GPUType: "H100"}, "vultr", false))
+
+	if !labels.SelectorFromSet(labels.Set(poolNodeSelector(nodePool))).Matches(nodeLabels) {
+		t.Error("expected the pool's selector to match the nodes it launches")
+	}
+	if labels.SelectorFromSet(labels.Set(poolNodeSelector(sameName))).Matches(nodeLabels) {
+		t.Error("expected a pool with the same name in another namespace not to match")
+	}
+}
+
+func TestValidateTemplateTaints(t *testing.T) {
+	tests := []struct {
+		name          string
+		taints        []corev1.Taint
+		startupTaints []corev1.Taint
+		expectErr     bool
+	}{
+		{
+			name:   "supported effects are valid",
+			taints: []corev1.Taint{{Key: "gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule}},
+			startupTaints: []corev1.Taint{
+				{Key: "tgp.io/initializing", Effect: corev1.TaintEffectNoExecute},
+			},
+		},
+		{
+			name:      "unknown effect is rejected",
+			taints:    []corev1.Taint{{Key: "gpu", Value: "true", Effect: "NoScheduleEver"}},
+			expectErr: true,
+		},
+		{
+			name:      "missing effect is rejected",
+			taints:    []corev1.Taint{{Key: "gpu", Value: "true"}},
+			expectErr: true,
+		},
+		{
+			name:          "empty startup taint key is rejected",
+			startupTaints: []corev1.Taint{{Effect: corev1.TaintEffectNoSchedule}},
+			expectErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodePool := &tgpv1.GPUNodePool{}
+			nodePool.Spec.Template.Spec.Taints = tt.taints
+			nodePool.Spec.Template.Spec.StartupTaints = tt.startupTaints
+
+			err := validateTemplateTaints(nodePool)
+			if (err != nil) != tt.expectErr {
+				t.Errorf("validateTemplateTaints() error = %v, expectErr %v", err, tt.expectErr)
+			}
+		})
+	}
+}
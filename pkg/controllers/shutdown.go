@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// inFlightLaunch is an instance launched by this process whose node does not exist yet
+type inFlightLaunch struct {
+	pool     types.NamespacedName
+	instance tgpv1.PoolInstance
+	// client is the provider client that launched the instance
+	client providers.ProviderClient
+	// persisted reports whether the instance is recorded in the pool status
+	persisted bool
+}
+
+// launchRegistry records the in-flight launches of this process, so a shutdown can persist
+// or terminate the ones the stopped reconcilers left behind. The zero value is ready to use.
+type launchRegistry struct {
+	mutex    sync.Mutex
+	launches map[string]*inFlightLaunch
+}
+
+// add records a launched instance
+func (l *launchRegistry) add(launch *inFlightLaunch) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.launches == nil {
+		l.launches = make(map[string]*inFlightLaunch)
+	}
+	l.launches[launch.instance.InstanceID] = launch
+}
+
+// markPersisted records that the instance is in its pool's status
+func (l *launchRegistry) markPersisted(instanceID string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if launch, exists := l.launches[instanceID]; exists {
+		launch.persisted = true
+	}
+}
+
+// remove forgets an instance once its node exists or it was terminated
+func (l *launchRegistry) remove(instanceID string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	delete(l.launches, instanceID)
+}
+
+// drain returns the recorded launches and forgets them
+func (l *launchRegistry) drain() []*inFlightLaunch {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	launches := make([]*inFlightLaunch, 0, len(l.launches))
+	for _, launch := range l.launches {
+		launches = append(launches, launch)
+	}
+	l.launches = nil
+	return launches
+}
+
+// FlushInFlightLaunches settles the instances this process launched whose node does not exist
+// yet, for use once the manager has stopped. Instances missing from their pool's status are
+// recorded there so the next leader resumes them instead of leaking them. With terminate the
+// instances are terminated instead and dropped from the status. c must not depend on the
+// manager's stopped cache.
+func (r *GPUNodePoolReconciler) FlushInFlightLaunches(ctx context.Context, c client.Client, terminate bool) error {
+	var errs []error
+	for _, launch := range r.launches.drain() {
+		if err := r.flushLaunch(ctx, c, launch, terminate); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// flushLaunch persists or terminates one in-flight launch
+func (r *GPUNodePoolReconciler) flushLaunch(ctx context.Context, c client.Client, launch *inFlightLaunch, terminate bool) error {
+	instanceID := launch.instance.InstanceID
+	log := r.Log.WithValues("gpunodepool", launch.pool, "instanceID", instanceID, "provider", launch.instance.Provider)
+
+	if terminate {
+		if err := launch.client.TerminateInstance(ctx, instanceID); err != nil {
+			return fmt.Errorf("failed to terminate in-flight instance %s: %w", instanceID, err)
+		}
+		log.Info("Terminated in-flight instance on shutdown")
+		if !launch.persisted {
+			return nil
+		}
+	} else if launch.persisted {
+		return nil
+	}
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var nodePool tgpv1.GPUNodePool
+		if err := c.Get(ctx, launch.pool, &nodePool); err != nil {
+			return err
+		}
+		untrackInstance(&nodePool, instanceID)
+		if !terminate {
+			nodePool.Status.Instances = append(nodePool.Status.Instances, launch.instance)
+		}
+		return c.Status().Update(ctx, &nodePool)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update pool %s for in-flight instance %s: %w", launch.pool, instanceID, err)
+	}
+	if !terminate {
+		log.Info("Persisted in-flight instance on shutdown")
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// terminateRecordingClient records the instances it terminates
+type terminateRecordingClient struct {
+	providers.ProviderClient
+	terminated []string
+}
+
+func (c *terminateRecordingClient) TerminateInstance(ctx context.Context, instanceID string) error {
+	c.terminated = append(c.terminated, instanceID)
+	return nil
+}
+
+func TestFlushInFlightLaunches(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = tgpv1.AddToScheme(scheme)
+
+	poolKey := types.NamespacedName{Name: "test-pool", Namespace: "default"}
+	instance := func(id string) tgpv1.PoolInstance {
+		return tgpv1.PoolInstance{InstanceID: id, Provider: "vultr", Phase: tgpv1.PoolInstancePhaseLaunched}
+	}
+
+	tests := []struct {
+		name           string
+		terminate      bool
+		wantInstances  []string
+		wantTerminated []string
+	}{
+		{"persist", false, []string{"persisted", "unpersisted"}, nil},
+		{"terminate", true, nil, []string{"persisted", "unpersisted"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodePool := &tgpv1.GPUNodePool{
+				ObjectMeta: metav1.ObjectMeta{Name: poolKey.Name, Namespace: poolKey.Namespace},
+				Status:     tgpv1.GPUNodePoolStatus{Instances: []tgpv1.PoolInstance{instance("persisted")}},
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(nodePool).WithStatusSubresource(nodePool).Build()
+			providerClient := &terminateRecordingClient{}
+
+			r := &GPUNodePoolReconciler{Log: logr.Discard()}
+			r.launches.add(&inFlightLaunch{pool: poolKey, instance: instance("persisted"), client: providerClient})
+			r.launches.markPersisted("persisted")
+			r.launches.add(&inFlightLaunch{pool: poolKey, instance: instance("unpersisted"), client: providerClient})
+			// Its node was created, so it is no longer in flight
+			r.launches.add(&inFlightLaunch{pool: poolKey, instance: instance("joined"), client: providerClient})
+			r.launches.remove("joined")
+
+			if err := r.FlushInFlightLaunches(context.Background(), c, tt.terminate); err != nil {
+				t.Fatalf("FlushInFlightLaunches() error = %v", err)
+			}
+
+			var updated tgpv1.GPUNodePool
+			if err := c.Get(context.Background(), poolKey, &updated); err != nil {
+				t.Fatalf("failed to get pool: %v", err)
+			}
+			var ids []string
+			for _, tracked := range updated.Status.Instances {
+				ids = append(ids, tracked.InstanceID)
+			}
+			if !slices.Equal(ids, tt.wantInstances) {
+				t.Errorf("pool instances = %v, want %v", ids, tt.wantInstances)
+			}
+			slices.Sort(providerClient.terminated)
+			if !slices.Equal(providerClient.terminated, tt.wantTerminated) {
+				t.Errorf("terminated = %v, want %v", providerClient.terminated, tt.wantTerminated)
+			}
+			if len(r.launches.drain()) != 0 {
+				t.Error("expected the flush to forget the launches")
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// AnnotationSpot lets a pod override its pool's spot policy with Required, Preferred or
+// Never. "true" and "false" are accepted for Preferred and Never.
+const AnnotationSpot = "tgp.io/spot"
+
+// podSpotPolicy returns the spot policy a pod asks for through its annotation, or "" if none
+func podSpotPolicy(pod *corev1.Pod) tgpv1.SpotPolicy {
+	switch strings.ToLower(pod.Annotations[AnnotationSpot]) {
+	case "required":
+		return tgpv1.SpotPolicyRequired
+	case "true", "preferred":
+		return tgpv1.SpotPolicyPreferred
+	case "false", "never":
+		return tgpv1.SpotPolicyNever
+	default:
+		return ""
+	}
+}
+
+// spotPolicyForLaunch returns the spot policy for a launch: the pod's policy when it sets one,
+// otherwise the pool's. A node class that does not allow spot instances always gets Never.
+func spotPolicyForLaunch(nodePool *tgpv1.GPUNodePool, nodeClass *tgpv1.GPUNodeClass, requirement *GPURequirement) tgpv1.SpotPolicy {
+	if !spotAllowed(nodeClass.Spec.InstanceRequirements) {
+		return tgpv1.SpotPolicyNever
+	}
+	if requirement.SpotPolicy != "" {
+		return requirement.SpotPolicy
+	}
+	return spotPolicyForPool(nodePool)
+}
+
+// launchWithOnDemandFallback launches the instance, retrying as on-demand when a spot launch
+// fails because the provider ran out of spot capacity since it was priced. The fallback is
+// only taken when the spot policy allows on-demand capacity and the pod would still bind to
+// an on-demand node.
+func (r *GPUNodePoolReconciler) launchWithOnDemandFallback(ctx context.Context, nodePool *tgpv1.GPUNodePool, nodeClass *tgpv1.GPUNodeClass, pod *corev1.Pod, requirement *GPURequirement, providerName string, providerClient providers.ProviderClient, launchRequest *providers.LaunchRequest, log logr.Logger) (*providers.GPUInstance, error) {
+	instance, err := providerClient.LaunchInstance(ctx, launchRequest)
+	if err == nil || !launchRequest.SpotInstance || spotPolicyForLaunch(nodePool, nodeClass, requirement) == tgpv1.SpotPolicyRequired {
+		return instance, err
+	}
+	// Other failures, such as bad credentials, billing or timeouts, would fail on-demand too
+	// and may have left a spot instance starting
+	if !errors.Is(err, providers.ErrNoCapacity) {
+		return nil, err
+	}
+
+	onDemand := *requirement
+	onDemand.Spot = false
+	onDemand.SpotSavings = 0
+	onDemand.HourlyPrice = requirement.OnDemandPrice
+	if simulatePodScheduling(pod, r.buildPlannedNode(nodePool, &onDemand, providerName)) != nil {
+		return nil, err
+	}
+
+	log.Info("Spot launch failed, falling back to on-demand capacity", "provider", providerName, "error", err.Error())
+	launchRequest.SpotInstance = false
+	instance, err = providerClient.LaunchInstance(ctx, launchRequest)
+	if err != nil {
+		return nil, err
+	}
+	*requirement = onDemand
+	return instance, nil
+}
+
+// spotSavingsAccrued returns the estimated USD a tracked spot instance has saved versus
+// on-demand from its launch until now
+func spotSavingsAccrued(tracked tgpv1.PoolInstance, now time.Time) float64 {
+	hourlySavings, err := strconv.ParseFloat(tracked.SpotHourlySavings, 64)
+	if err != nil || hourlySavings <= 0 {
+		return 0
+	}
+	hours := now.Sub(tracked.LaunchedAt.Time).Hours()
+	if hours <= 0 {
+		return 0
+	}
+	return hourlySavings * hours
+}
+
+// spotSavings are the savings an instance accrued before it stopped running, to be added to
+// the spot savings metric once the pool status realizing them has been persisted
+type spotSavings struct {
+	provider string
+	gpuType  string
+	dollars  float64
+}
+
+// realizeSpotSavings adds the savings accrued by an instance that is no longer running, whether
+// deleted by the operator, interrupted or removed out of band, to the pool's realized savings.
+// The savings are returned for recordSpotSavings once the status is persisted, so a status
+// update that fails and is retried does not count them twice.
+func realizeSpotSavings(nodePool *tgpv1.GPUNodePool, tracked tgpv1.PoolInstance, now time.Time) spotSavings {
+	savings := spotSavings{provider: tracked.Provider, gpuType: tracked.GPUType, dollars: spotSavingsAccrued(tracked, now)}
+	if savings.dollars <= 0 {
+		return savings
+	}
+	realized, _ := strconv.ParseFloat(nodePool.Status.RealizedSpotSavings, 64)
+	nodePool.Status.RealizedSpotSavings = strconv.FormatFloat(realized+savings.dollars, 'f', 2, 64)
+	return savings
+}
+
+// recordSpotSavings adds realized savings to the spot savings metric
+func (r *GPUNodePoolReconciler) recordSpotSavings(realized []spotSavings) {
+	for _, savings := range realized {
+		if savings.dollars > 0 {
+			r.Metrics.RecordSpotSavings(savings.provider, savings.gpuType, savings.dollars)
+		}
+	}
+}
+
+// updateEstimatedSpotSavings records the pool's estimated spot savings: those realized by
+// removed instances plus those accrued so far by its tracked instances
+func updateEstimatedSpotSavings(nodePool *tgpv1.GPUNodePool, now time.Time) {
+	total, _ := strconv.ParseFloat(nodePool.Status.RealizedSpotSavings, 64)
+	for _, tracked := range nodePool.Status.Instances {
+		total += spotSavingsAccrued(tracked, now)
+	}
+	nodePool.Status.EstimatedSpotSavings = ""
+	if total > 0 {
+		nodePool.Status.EstimatedSpotSavings = strconv.FormatFloat(total, 'f', 2, 64)
+	}
+}
@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// spotLaunchClient fails spot launches, with a capacity error unless spotErr is set, and
+// records the capacity type of each launch
+type spotLaunchClient struct {
+	providers.ProviderClient
+	spotErr  error
+	launches []bool
+}
+
+func (c *spotLaunchClient) LaunchInstance(ctx context.Context, req *providers.LaunchRequest) (*providers.GPUInstance, error) {
+	c.launches = append(c.launches, req.SpotInstance)
+	if req.SpotInstance {
+		if c.spotErr != nil {
+			return nil, c.spotErr
+		}
+		return nil, fmt.Errorf("no spot instances left: %w", providers.ErrNoCapacity)
+	}
+	return &providers.GPUInstance{ID: "i-123"}, nil
+}
+
+func TestSpotPolicyForLaunch(t *testing.T) {
+	pool := &tgpv1.GPUNodePool{Spec: tgpv1.GPUNodePoolSpec{Spot: tgpv1.SpotPolicyPreferred}}
+	spotAllowed := func(allowed bool) *tgpv1.GPUNodeClass {
+		return &tgpv1.GPUNodeClass{Spec: tgpv1.GPUNodeClassSpec{
+			InstanceRequirements: &tgpv1.InstanceRequirements{SpotAllowed: &allowed},
+		}}
+	}
+	pod := func(annotation string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationSpot: annotation}}}
+	}
+
+	tests := []struct {
+		name      string
+		nodeClass *tgpv1.GPUNodeClass
+		pod       *corev1.Pod
+		want      tgpv1.SpotPolicy
+	}{
+		{name: "pool policy", nodeClass: &tgpv1.GPUNodeClass{}, pod: &corev1.Pod{}, want: tgpv1.SpotPolicyPreferred},
+		{name: "pod requires spot", nodeClass: spotAllowed(true), pod: pod("Required"), want: tgpv1.SpotPolicyRequired},
+		{name: "pod opts out", nodeClass: &tgpv1.GPUNodeClass{}, pod: pod("false"), want: tgpv1.SpotPolicyNever},
+		{name: "unknown annotation uses pool policy", nodeClass: &tgpv1.GPUNodeClass{}, pod: pod("maybe"), want: tgpv1.SpotPolicyPreferred},
+		{name: "class forbids spot", nodeClass: spotAllowed(false), pod: pod("true"), want: tgpv1.SpotPolicyNever},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requirement := &GPURequirement{SpotPolicy: podSpotPolicy(tt.pod)}
+			if got := spotPolicyForLaunch(pool, tt.nodeClass, requirement); got != tt.want {
+				t.Errorf("spotPolicyForLaunch() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLaunchWithOnDemandFallback(t *testing.T) {
+	r := &GPUNodePoolReconciler{}
+	nodeClass := &tgpv1.GPUNodeClass{}
+	pool := func(policy tgpv1.SpotPolicy) *tgpv1.GPUNodePool {
+		return &tgpv1.GPUNodePool{ObjectMeta: metav1.ObjectMeta{Name: "pool"}, Spec: tgpv1.GPUNodePoolSpec{Spot: policy}}
+	}
+
+	t.Run("preferred falls back to on-demand", func(t *testing.T) {
+		client := &spotLaunchClient{}
+		requirement := &GPURequirement{GPUType: "H100", Spot: true, SpotSavings: 1.5, HourlyPrice: 1.0, OnDemandPrice: 2.5}
+		instance, err := r.launchWithOnDemandFallback(context.Background(), pool(tgpv1.SpotPolicyPreferred), nodeClass, &corev1.Pod{},
+			requirement, "vultr", client, &providers.LaunchRequest{SpotInstance: true}, logr.Discard())
+		if err != nil || instance == nil {
+			t.Fatalf("expected on-demand launch, got %v, %v", instance, err)
+		}
+		if len(client.launches) != 2 || client.launches[1] {
+			t.Errorf("expected a spot then an on-demand launch, got %v", client.launches)
+		}
+		if requirement.Spot || requirement.SpotSavings != 0 || requirement.HourlyPrice != 2.5 {
+			t.Errorf("expected the requirement to record on-demand capacity, got %+v", requirement)
+		}
+	})
+
+	t.Run("required does not fall back", func(t *testing.T) {
+		client := &spotLaunchClient{}
+		if _, err := r.launchWithOnDemandFallback(context.Background(), pool(tgpv1.SpotPolicyRequired), nodeClass, &corev1.Pod{},
+			&GPURequirement{GPUType: "H100", Spot: true}, "vultr", client, &providers.LaunchRequest{SpotInstance: true}, logr.Discard()); err == nil {
+			t.Error("expected the spot launch error")
+		}
+		if len(client.launches) != 1 {
+			t.Errorf("expected a single launch, got %v", client.launches)
+		}
+	})
+
+	t.Run("errors other than capacity do not fall back", func(t *testing.T) {
+		client := &spotLaunchClient{spotErr: fmt.Errorf("invalid key: %w", providers.ErrUnauthorized)}
+		_, err := r.launchWithOnDemandFallback(context.Background(), pool(tgpv1.SpotPolicyPreferred), nodeClass, &corev1.Pod{},
+			&GPURequirement{GPUType: "H100", Spot: true}, "vultr", client, &providers.LaunchRequest{SpotInstance: true}, logr.Discard())
+		if !errors.Is(err, providers.ErrUnauthorized) {
+			t.Errorf("expected the spot launch error, got %v", err)
+		}
+		if len(client.launches) != 1 {
+			t.Errorf("expected a single launch, got %v", client.launches)
+		}
+	})
+
+	t.Run("pod selecting spot nodes does not fall back", func(t *testing.T) {
+		client := &spotLaunchClient{}
+		pod := &corev1.Pod{Spec: corev1.PodSpec{NodeSelector: map[string]string{tgpv1.NodeLabelSpot: "true"}}}
+		if _, err := r.launchWithOnDemandFallback(context.Background(), pool(tgpv1.SpotPolicyPreferred), nodeClass, pod,
+			&GPURequirement{GPUType: "H100", Spot: true}, "vultr", client, &providers.LaunchRequest{SpotInstance: true}, logr.Discard()); err == nil {
+			t.Error("expected the spot launch error")
+		}
+		if len(client.launches) != 1 {
+			t.Errorf("expected a single launch, got %v", client.launches)
+		}
+	})
+}
+
+func TestSpotSavingsAccrued(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		tracked tgpv1.PoolInstance
+		want    float64
+	}{
+		{
+			name:    "hourly savings over the instance's lifetime",
+			tracked: tgpv1.PoolInstance{SpotHourlySavings: "1.5000", LaunchedAt: metav1.NewTime(now.Add(-4 * time.Hour))},
+			want:    6,
+		},
+		{
+			name:    "on-demand instance",
+			tracked: tgpv1.PoolInstance{LaunchedAt: metav1.NewTime(now.Add(-4 * time.Hour))},
+		},
+		{
+			name:    "launched in the future",
+			tracked: tgpv1.PoolInstance{SpotHourlySavings: "1.5000", LaunchedAt: metav1.NewTime(now.Add(time.Hour))},
+		},
+		{
+			name:    "unparseable savings",
+			tracked: tgpv1.PoolInstance{SpotHourlySavings: "cheap", LaunchedAt: metav1.NewTime(now.Add(-4 * time.Hour))},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := spotSavingsAccrued(tt.tracked, now); got != tt.want {
+				t.Errorf("spotSavingsAccrued() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEstimatedSpotSavings(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	running := tgpv1.PoolInstance{InstanceID: "running", SpotHourlySavings: "0.5000", LaunchedAt: metav1.NewTime(now.Add(-2 * time.Hour))}
+	interrupted := tgpv1.PoolInstance{InstanceID: "interrupted", SpotHourlySavings: "2.0000", LaunchedAt: metav1.NewTime(now.Add(-3 * time.Hour))}
+
+	nodePool := &tgpv1.GPUNodePool{Status: tgpv1.GPUNodePoolStatus{
+		RealizedSpotSavings: "4.00",
+		Instances:           []tgpv1.PoolInstance{running},
+	}}
+
+	// The interrupted instance's savings are kept after it leaves the pool
+	if savings := realizeSpotSavings(nodePool, interrupted, now); savings.dollars != 6 {
+		t.Errorf("realizeSpotSavings() = %v, want 6", savings.dollars)
+	}
+	if nodePool.Status.RealizedSpotSavings != "10.00" {
+		t.Errorf("RealizedSpotSavings = %q, want 10.00", nodePool.Status.RealizedSpotSavings)
+	}
+
+	updateEstimatedSpotSavings(nodePool, now)
+	if nodePool.Status.EstimatedSpotSavings != "11.00" {
+		t.Errorf("EstimatedSpotSavings = %q, want 11.00", nodePool.Status.EstimatedSpotSavings)
+	}
+
+	empty := &tgpv1.GPUNodePool{Status: tgpv1.GPUNodePoolStatus{EstimatedSpotSavings: "1.00"}}
+	updateEstimatedSpotSavings(empty, now)
+	if empty.Status.EstimatedSpotSavings != "" {
+		t.Errorf("expected no estimated savings without spot instances, got %q", empty.Status.EstimatedSpotSavings)
+	}
+}
@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/tailscale"
+)
+
+const (
+	// defaultTailscaleClientIDKey is the secret key of the OAuth client ID when unset
+	defaultTailscaleClientIDKey = "client-id"
+
+	// defaultTailscaleClientSecretKey is the secret key of the OAuth client secret when unset
+	defaultTailscaleClientSecretKey = "client-secret"
+)
+
+// tailscaleClients caches Tailscale API clients by OAuth client, so access tokens are reused
+// across launches
+type tailscaleClients struct {
+	mu      sync.Mutex
+	clients map[string]*tailscale.Client
+}
+
+// get returns the cached client for the OAuth credentials, creating it on first use
+func (c *tailscaleClients) get(baseURL, clientID, clientSecret string) *tailscale.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := baseURL + "\x00" + clientID + "\x00" + clientSecret
+	if client, ok := c.clients[key]; ok {
+		return client
+	}
+	if c.clients == nil {
+		c.clients = make(map[string]*tailscale.Client)
+	}
+	client := tailscale.NewClient(baseURL, clientID, clientSecret)
+	c.clients[key] = client
+	return client
+}
+
+// tailscaleAuthKey returns the auth key a new node of the pool joins the tailnet with. With OAuth
+// credentials a single-use key is generated for every call, so keys are never shared between
+// nodes; otherwise the deprecated pre-generated key is read from its secret. It returns an
+// empty key when the class does not configure Tailscale.
+func (r *GPUNodePoolReconciler) tailscaleAuthKey(ctx context.Context, nodePool *tgpv1.GPUNodePool, nodeClass *tgpv1.GPUNodeClass) (string, error) {
+	tailscaleConfig := nodeClass.Spec.TailscaleConfig
+	if tailscaleConfig == nil {
+		return "", nil
+	}
+
+	if ref := tailscaleConfig.OAuthSecretRef; ref != nil {
+		clientIDKey, clientSecretKey := ref.ClientIDKey, ref.ClientSecretKey
+		if clientIDKey == "" {
+			clientIDKey = defaultTailscaleClientIDKey
+		}
+		if clientSecretKey == "" {
+			clientSecretKey = defaultTailscaleClientSecretKey
+		}
+
+		clientID, err := r.readSecretValue(ctx, ref.Namespace, ref.Name, clientIDKey)
+		if err != nil {
+			return "", fmt.Errorf("failed to read Tailscale OAuth client ID: %w", err)
+		}
+		clientSecret, err := r.readSecretValue(ctx, ref.Namespace, ref.Name, clientSecretKey)
+		if err != nil {
+			return "", fmt.Errorf("failed to read Tailscale OAuth client secret: %w", err)
+		}
+
+		client := r.tailscale.get(r.TailscaleAPIURL, clientID, clientSecret)
+		authKey, err := client.CreateAuthKey(ctx, tailscale.AuthKeyOptions{
+			Tags:        tailscaleConfig.Tags,
+			Ephemeral:   tailscaleConfig.Ephemeral,
+			Description: fmt.Sprintf("tgp-operator node for %s/%s", nodePool.Namespace, nodePool.Name),
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to generate Tailscale auth key: %w", err)
+		}
+		return authKey, nil
+	}
+
+	if ref := tailscaleConfig.AuthKeySecretRef; ref != nil {
+		authKey, err := r.readSecretValue(ctx, ref.Namespace, ref.Name, ref.Key)
+		if err != nil {
+			return "", fmt.Errorf("failed to read Tailscale auth key: %w", err)
+		}
+		return authKey, nil
+	}
+
+	return "", fmt.Errorf("tailscaleConfig sets neither oauthSecretRef nor authKeySecretRef")
+}
+
+// readSecretValue reads a key of a secret, in the default namespace when none is given
+func (r *GPUNodePoolReconciler) readSecretValue(ctx context.Context, namespace, name, key string) (string, error) {
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, secret); err != nil {
+		return "", fmt.Errorf("failed to get secret %s/%s: %w", namespace, name, err)
+	}
+
+	value, exists := secret.Data[key]
+	if !exists {
+		return "", fmt.Errorf("key %s not found in secret %s/%s", key, namespace, name)
+	}
+	return string(value), nil
+}
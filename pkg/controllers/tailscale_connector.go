@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+// connectorGVK is the Connector resource of the Tailscale Kubernetes operator
+var connectorGVK = schema.GroupVersionKind{Group: "tailscale.com", Version: "v1alpha1", Kind: "Connector"}
+
+// reconcileTailscaleConnectors creates or updates a Tailscale Connector for every node in the
+// pool when the node class enables them. Connectors are owned by their node, so they are
+// garbage collected with it. Nothing is done when the Connector CRD is not installed.
+func (r *GPUNodePoolReconciler) reconcileTailscaleConnectors(ctx context.Context, nodePool *tgpv1.GPUNodePool, nodeClass *tgpv1.GPUNodeClass, log logr.Logger) error {
+	tailscaleConfig := nodeClass.Spec.TailscaleConfig
+	if !tailscaleConfig.GetConnectorEnabled() {
+		return nil
+	}
+
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes, poolNodeSelector(nodePool)); err != nil {
+		return fmt.Errorf("failed to list nodes for pool %s: %w", nodePool.Name, err)
+	}
+
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if node.DeletionTimestamp != nil {
+			continue
+		}
+
+		if err := r.ensureTailscaleConnector(ctx, node, tailscaleConfig, log); err != nil {
+			if meta.IsNoMatchError(err) {
+				log.V(1).Info("Tailscale Connector CRD is not installed, skipping connectors")
+				return nil
+			}
+			log.Error(err, "Failed to reconcile Tailscale connector", "node", node.Name)
+		}
+	}
+
+	return nil
+}
+
+// ensureTailscaleConnector creates the node's Connector, or updates its spec when it has drifted
+func (r *GPUNodePoolReconciler) ensureTailscaleConnector(ctx context.Context, node *corev1.Node, tailscaleConfig *tgpv1.TailscaleConfig, log logr.Logger) error {
+	desired := buildTailscaleConnector(node, tailscaleConfig)
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(connectorGVK)
+	err := r.Get(ctx, types.NamespacedName{Name: desired.GetName()}, existing)
+	if errors.IsNotFound(err) {
+		if err := r.Create(ctx, desired); err != nil {
+			return err
+		}
+		log.Info("Created Tailscale connector", "node", node.Name)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if equality.Semantic.DeepEqual(existing.Object["spec"], desired.Object["spec"]) {
+		return nil
+	}
+	existing.Object["spec"] = desired.Object["spec"]
+	if err := r.Update(ctx, existing); err != nil {
+		return err
+	}
+	log.Info("Updated Tailscale connector", "node", node.Name)
+	return nil
+}
+
+// buildTailscaleConnector returns the Connector for a node, named and owned by it
+func buildTailscaleConnector(node *corev1.Node, tailscaleConfig *tgpv1.TailscaleConfig) *unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"hostname": node.Name,
+	}
+	if len(tailscaleConfig.Tags) > 0 {
+		spec["tags"] = stringsToInterfaces(tailscaleConfig.Tags)
+	}
+	if connector := tailscaleConfig.Operator.Connector; connector != nil {
+		if connector.SubnetRouter != nil {
+			spec["subnetRouter"] = map[string]interface{}{
+				"advertiseRoutes": stringsToInterfaces(connector.SubnetRouter.AdvertiseRoutes),
+			}
+		}
+		if connector.ExitNode {
+			spec["exitNode"] = true
+		}
+		if connector.AppConnector != nil {
+			appConnector := map[string]interface{}{}
+			if len(connector.AppConnector.Routes) > 0 {
+				appConnector["routes"] = stringsToInterfaces(connector.AppConnector.Routes)
+			}
+			spec["appConnector"] = appConnector
+		}
+	}
+
+	connector := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	connector.SetGroupVersionKind(connectorGVK)
+	connector.SetName(node.Name)
+	connector.SetLabels(map[string]string{"tgp.io/nodepool": node.Labels["tgp.io/nodepool"]})
+	connector.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: "v1",
+		Kind:       "Node",
+		Name:       node.Name,
+		UID:        node.UID,
+	}})
+	return connector
+}
+
+// stringsToInterfaces converts a string slice to the form unstructured objects hold lists in
+func stringsToInterfaces(values []string) []interface{} {
+	out := make([]interface{}, len(values))
+	for i, v := range values {
+		out[i] = v
+	}
+	return out
+}
@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+func TestReconcileTailscaleConnectors(t *testing.T) {
+	enabled := true
+	nodePool := &tgpv1.GPUNodePool{ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "default"}}
+	nodeClass := &tgpv1.GPUNodeClass{Spec: tgpv1.GPUNodeClassSpec{TailscaleConfig: &tgpv1.TailscaleConfig{
+		Tags: []string{"tag:gpu"},
+		Operator: &tgpv1.TailscaleOperatorConfig{
+			ConnectorEnabled: &enabled,
+			Connector: &tgpv1.TailscaleConnectorSpec{
+				SubnetRouter: &tgpv1.TailscaleSubnetRouter{AdvertiseRoutes: []string{"10.0.0.0/24"}},
+				ExitNode:     true,
+			},
+		},
+	}}}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:   "gpu-node-1",
+		UID:    "node-uid",
+		Labels: map[string]string{"tgp.io/nodepool": "pool", tgpv1.NodeLabelNodePoolNamespace: "default"},
+	}}
+
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	newReconciler := func(connectorCRD bool) *GPUNodePoolReconciler {
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(corev1.SchemeGroupVersion.WithKind("Node"), meta.RESTScopeRoot)
+		if connectorCRD {
+			mapper.Add(connectorGVK, meta.RESTScopeRoot)
+		}
+		return &GPUNodePoolReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).WithObjects(node.DeepCopy()).Build(),
+		}
+	}
+
+	t.Run("creates a connector owned by each node", func(t *testing.T) {
+		r := newReconciler(true)
+		if err := r.reconcileTailscaleConnectors(context.Background(), nodePool, nodeClass, logr.Discard()); err != nil {
+			t.Fatalf("reconcileTailscaleConnectors failed: %v", err)
+		}
+
+		connector := &unstructured.Unstructured{}
+		connector.SetGroupVersionKind(connectorGVK)
+		if err := r.Get(context.Background(), types.NamespacedName{Name: node.Name}, connector); err != nil {
+			t.Fatalf("expected a connector for the node: %v", err)
+		}
+
+		owners := connector.GetOwnerReferences()
+		if len(owners) != 1 || owners[0].Kind != "Node" || owners[0].UID != node.UID {
+			t.Errorf("expected the connector to be owned by the node, got %v", owners)
+		}
+		routes, _, _ := unstructured.NestedStringSlice(connector.Object, "spec", "subnetRouter", "advertiseRoutes")
+		exitNode, _, _ := unstructured.NestedBool(connector.Object, "spec", "exitNode")
+		tags, _, _ := unstructured.NestedStringSlice(connector.Object, "spec", "tags")
+		if len(routes) != 1 || routes[0] != "10.0.0.0/24" || !exitNode || len(tags) != 1 {
+			t.Errorf("unexpected connector spec %v", connector.Object["spec"])
+		}
+
+		// Spec changes on the node class are applied to existing connectors
+		updated := nodeClass.DeepCopy()
+		updated.Spec.TailscaleConfig.Operator.Connector.ExitNode = false
+		if err := r.reconcileTailscaleConnectors(context.Background(), nodePool, updated, logr.Discard()); err != nil {
+			t.Fatalf("reconcileTailscaleConnectors failed: %v", err)
+		}
+		if err := r.Get(context.Background(), types.NamespacedName{Name: node.Name}, connector); err != nil {
+			t.Fatalf("failed to get connector: %v", err)
+		}
+		if _, found, _ := unstructured.NestedBool(connector.Object, "spec", "exitNode"); found {
+			t.Error("expected exitNode to be removed from the connector")
+		}
+	})
+
+	t.Run("skips when the Connector CRD is not installed", func(t *testing.T) {
+		r := newReconciler(false)
+		if err := r.reconcileTailscaleConnectors(context.Background(), nodePool, nodeClass, logr.Discard()); err != nil {
+			t.Errorf("expected a missing CRD to be skipped, got %v", err)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		r := newReconciler(true)
+		disabled := nodeClass.DeepCopy()
+		disabled.Spec.TailscaleConfig.Operator.ConnectorEnabled = nil
+		if err := r.reconcileTailscaleConnectors(context.Background(), nodePool, disabled, logr.Discard()); err != nil {
+			t.Fatalf("reconcileTailscaleConnectors failed: %v", err)
+		}
+		connector := &unstructured.Unstructured{}
+		connector.SetGroupVersionKind(connectorGVK)
+		if err := r.Get(context.Background(), types.NamespacedName{Name: node.Name}, connector); err == nil {
+			t.Error("expected no connector when connectors are disabled")
+		}
+	})
+}
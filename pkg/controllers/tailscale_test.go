@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+func TestTailscaleAuthKey(t *testing.T) {
+	keys := 0
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/oauth/token":
+			if r.FormValue("client_id") != "oauth-id" || r.FormValue("client_secret") != "oauth-secret" {
+				http.Error(w, "invalid client", http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token":"token","expires_in":3600}`))
+		case "/api/v2/tailnet/-/keys":
+			keys++
+			_, _ = fmt.Fprintf(w, `{"id":"k%d","key":"tskey-auth-%d"}`, keys, keys)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer api.Close()
+
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "tailscale", Namespace: "default"},
+		Data: map[string][]byte{
+			"client-id":     []byte("oauth-id"),
+			"client-secret": []byte("oauth-secret"),
+			"authkey":       []byte("tskey-shared"),
+		},
+	}
+	r := &GPUNodePoolReconciler{
+		Client:          fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build(),
+		TailscaleAPIURL: api.URL,
+	}
+	nodePool := &tgpv1.GPUNodePool{ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "default"}}
+	nodeClass := func(tailscaleConfig *tgpv1.TailscaleConfig) *tgpv1.GPUNodeClass {
+		return &tgpv1.GPUNodeClass{Spec: tgpv1.GPUNodeClassSpec{TailscaleConfig: tailscaleConfig}}
+	}
+
+	t.Run("no tailscale config", func(t *testing.T) {
+		key, err := r.tailscaleAuthKey(context.Background(), nodePool, nodeClass(nil))
+		if err != nil || key != "" {
+			t.Errorf("tailscaleAuthKey() = %q, %v, want no key", key, err)
+		}
+	})
+
+	t.Run("OAuth generates a key per node", func(t *testing.T) {
+		class := nodeClass(&tgpv1.TailscaleConfig{
+			Tags:           []string{"tag:gpu"},
+			OAuthSecretRef: &tgpv1.TailscaleOAuthSecretRef{Name: "tailscale"},
+			// The deprecated key is ignored when OAuth is configured
+			AuthKeySecretRef: &tgpv1.SecretKeyRef{Name: "tailscale", Key: "authkey"},
+		})
+		first, err := r.tailscaleAuthKey(context.Background(), nodePool, class)
+		if err != nil {
+			t.Fatalf("tailscaleAuthKey failed: %v", err)
+		}
+		second, err := r.tailscaleAuthKey(context.Background(), nodePool, class)
+		if err != nil {
+			t.Fatalf("tailscaleAuthKey failed: %v", err)
+		}
+		if first != "tskey-auth-1" || second != "tskey-auth-2" {
+			t.Errorf("expected a new generated key per node, got %q and %q", first, second)
+		}
+	})
+
+	t.Run("falls back to the deprecated auth key", func(t *testing.T) {
+		class := nodeClass(&tgpv1.TailscaleConfig{AuthKeySecretRef: &tgpv1.SecretKeyRef{Name: "tailscale", Key: "authkey"}})
+		key, err := r.tailscaleAuthKey(context.Background(), nodePool, class)
+		if err != nil || key != "tskey-shared" {
+			t.Errorf("tailscaleAuthKey() = %q, %v, want tskey-shared", key, err)
+		}
+	})
+
+	t.Run("missing OAuth secret key", func(t *testing.T) {
+		class := nodeClass(&tgpv1.TailscaleConfig{
+			Tags:           []string{"tag:gpu"},
+			OAuthSecretRef: &tgpv1.TailscaleOAuthSecretRef{Name: "tailscale", ClientIDKey: "missing"},
+		})
+		if _, err := r.tailscaleAuthKey(context.Background(), nodePool, class); err == nil {
+			t.Error("expected an error for a missing client ID key")
+		}
+	})
+
+	t.Run("no credentials", func(t *testing.T) {
+		if _, err := r.tailscaleAuthKey(context.Background(), nodePool, nodeClass(&tgpv1.TailscaleConfig{})); err == nil {
+			t.Error("expected an error when no credentials are referenced")
+		}
+	})
+}
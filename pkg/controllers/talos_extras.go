@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+const (
+	// defaultTalosFilePermissions is the mode of extra files that do not set one
+	defaultTalosFilePermissions = 0o644
+
+	// defaultTalosFileOp is how extra files that do not set an op are written
+	defaultTalosFileOp = "create"
+)
+
+// mergeTalosExtras merges the class's extra files and environment variables into the rendered
+// machine config. Extra files replace template files with the same path and extra variables
+// override template variables with the same name; everything else the template sets is kept.
+// Multi-document configs are merged into the document with the machine section.
+func mergeTalosExtras(machineConfig string, talosConfig *tgpv1.TalosConfig) (string, error) {
+	if talosConfig == nil || (len(talosConfig.ExtraFiles) == 0 && len(talosConfig.Env) == 0) {
+		return machineConfig, nil
+	}
+
+	var documents []*yaml.Node
+	decoder := yaml.NewDecoder(bytes.NewBufferString(machineConfig))
+	for {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("rendered machine config is not valid YAML: %w", err)
+		}
+		documents = append(documents, &document)
+	}
+
+	var root *yaml.Node
+	for _, document := range documents {
+		if len(document.Content) > 0 && mappingValue(document.Content[0], "machine") != nil {
+			root = document.Content[0]
+			break
+		}
+	}
+	if root == nil {
+		return "", fmt.Errorf("rendered machine config has no machine section to merge extra files and env into")
+	}
+
+	machine := ensureMappingValue(root, "machine", yaml.MappingNode)
+	if len(talosConfig.ExtraFiles) > 0 {
+		mergeTalosFiles(ensureMappingValue(machine, "files", yaml.SequenceNode), talosConfig.ExtraFiles)
+	}
+	if len(talosConfig.Env) > 0 {
+		env := ensureMappingValue(machine, "env", yaml.MappingNode)
+		names := make([]string, 0, len(talosConfig.Env))
+		for name := range talosConfig.Env {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			setMappingValue(env, name, stringNode(talosConfig.Env[name]))
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	for _, document := range documents {
+		if err := encoder.Encode(document); err != nil {
+			return "", fmt.Errorf("failed to encode merged machine config: %w", err)
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("failed to encode merged machine config: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// mergeTalosFiles replaces the entries of a machine.files sequence written to the same path as an
+// extra file, and appends the other extra files
+func mergeTalosFiles(files *yaml.Node, extraFiles []tgpv1.TalosFile) {
+	for _, extra := range extraFiles {
+		permissions := int32(defaultTalosFilePermissions)
+		if extra.Permissions != nil {
+			permissions = *extra.Permissions
+		}
+		op := extra.Op
+		if op == "" {
+			op = defaultTalosFileOp
+		}
+
+		entry := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		setMappingValue(entry, "content", stringNode(extra.Content))
+		setMappingValue(entry, "permissions", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: fmt.Sprintf("0o%o", permissions)})
+		setMappingValue(entry, "path", stringNode(extra.Path))
+		setMappingValue(entry, "op", stringNode(op))
+
+		replaced := false
+		for i, existing := range files.Content {
+			if path := mappingValue(existing, "path"); path != nil && path.Value == extra.Path {
+				files.Content[i] = entry
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			files.Content = append(files.Content, entry)
+		}
+	}
+}
+
+// mappingValue returns the value of a key in a YAML mapping, or nil if the node is not a mapping
+// or lacks the key
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// ensureMappingValue returns the value of a key in a YAML mapping, adding an empty node of the
+// given kind when the key is missing or null
+func ensureMappingValue(mapping *yaml.Node, key string, kind yaml.Kind) *yaml.Node {
+	if value := mappingValue(mapping, key); value != nil && value.Tag != "!!null" {
+		return value
+	}
+	value := &yaml.Node{Kind: kind}
+	setMappingValue(mapping, key, value)
+	return value
+}
+
+// setMappingValue sets the value of a key in a YAML mapping, adding the key if it is missing
+func setMappingValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content, stringNode(key), value)
+}
+
+// stringNode returns a YAML string scalar, written as a literal block when it spans lines
+func stringNode(value string) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	if strings.Contains(value, "\n") {
+		node.Style = yaml.LiteralStyle
+	}
+	return node
+}
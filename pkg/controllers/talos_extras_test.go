@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+func TestMergeTalosExtras(t *testing.T) {
+	rendered := `version: v1alpha1
+machine:
+  type: worker
+  env:
+    HTTP_PROXY: http://template-proxy:3128
+    GRPC_GO_LOG_SEVERITY_LEVEL: error
+  files:
+    - content: template registry
+      path: /var/etc/registry.toml
+      permissions: 0o600
+      op: create
+    - content: template agent
+      path: /var/etc/agent.conf
+      permissions: 0o644
+      op: create
+cluster:
+  clusterName: test
+`
+	permissions := int32(0o600)
+	talosConfig := &tgpv1.TalosConfig{
+		ExtraFiles: []tgpv1.TalosFile{
+			{Path: "/var/etc/registry.toml", Content: "[mirrors]\nendpoint = \"https://mirror\"\n", Op: "overwrite"},
+			{Path: "/var/etc/monitoring.yaml", Content: "scrape: true", Permissions: &permissions},
+		},
+		Env: map[string]string{"HTTP_PROXY": "http://class-proxy:3128", "NO_PROXY": "10.0.0.0/8"},
+	}
+
+	merged, err := mergeTalosExtras(rendered, talosConfig)
+	if err != nil {
+		t.Fatalf("mergeTalosExtras failed: %v", err)
+	}
+
+	var config struct {
+		Version string `yaml:"version"`
+		Machine struct {
+			Env   map[string]string `yaml:"env"`
+			Files []struct {
+				Content     string `yaml:"content"`
+				Path        string `yaml:"path"`
+				Permissions int    `yaml:"permissions"`
+				Op          string `yaml:"op"`
+			} `yaml:"files"`
+		} `yaml:"machine"`
+		Cluster struct {
+			ClusterName string `yaml:"clusterName"`
+		} `yaml:"cluster"`
+	}
+	if err := yaml.Unmarshal([]byte(merged), &config); err != nil {
+		t.Fatalf("merged config is not valid YAML: %v\n%s", err, merged)
+	}
+
+	if config.Version != "v1alpha1" || config.Cluster.ClusterName != "test" {
+		t.Errorf("expected the rest of the template to be kept, got\n%s", merged)
+	}
+	wantEnv := map[string]string{
+		"HTTP_PROXY":                 "http://class-proxy:3128",
+		"GRPC_GO_LOG_SEVERITY_LEVEL": "error",
+		"NO_PROXY":                   "10.0.0.0/8",
+	}
+	for name, value := range wantEnv {
+		if config.Machine.Env[name] != value {
+			t.Errorf("env %s = %q, want %q", name, config.Machine.Env[name], value)
+		}
+	}
+
+	if len(config.Machine.Files) != 3 {
+		t.Fatalf("expected the replaced, kept and appended files, got %+v", config.Machine.Files)
+	}
+	registry, agent, monitoring := config.Machine.Files[0], config.Machine.Files[1], config.Machine.Files[2]
+	if registry.Path != "/var/etc/registry.toml" || !strings.Contains(registry.Content, "https://mirror") ||
+		registry.Op != "overwrite" || registry.Permissions != 0o644 {
+		t.Errorf("expected the class file to replace the template file at its path, got %+v", registry)
+	}
+	if agent.Content != "template agent" {
+		t.Errorf("expected the other template file to be kept, got %+v", agent)
+	}
+	if monitoring.Path != "/var/etc/monitoring.yaml" || monitoring.Op != "create" || monitoring.Permissions != 0o600 {
+		t.Errorf("expected the new class file to be appended with defaults, got %+v", monitoring)
+	}
+}
+
+func TestMergeTalosExtrasMultiDocument(t *testing.T) {
+	rendered := "version: v1alpha1\nmachine:\n  type: worker\n---\napiVersion: v1alpha1\nkind: ExtensionServiceConfig\nname: tailscale\n"
+	merged, err := mergeTalosExtras(rendered, &tgpv1.TalosConfig{Env: map[string]string{"FOO": "bar"}})
+	if err != nil {
+		t.Fatalf("mergeTalosExtras failed: %v", err)
+	}
+	if !strings.Contains(merged, "kind: ExtensionServiceConfig") || !strings.Contains(merged, "FOO: bar") {
+		t.Errorf("expected the env merged into the machine document and the other document kept, got\n%s", merged)
+	}
+}
+
+func TestMergeTalosExtrasWithoutExtras(t *testing.T) {
+	// Left untouched, so templates that are not plain YAML keep working
+	rendered := "token: {{.MachineToken}}"
+	for _, talosConfig := range []*tgpv1.TalosConfig{nil, {}} {
+		merged, err := mergeTalosExtras(rendered, talosConfig)
+		if err != nil || merged != rendered {
+			t.Errorf("mergeTalosExtras() = %q, %v, want the config unchanged", merged, err)
+		}
+	}
+
+	if _, err := mergeTalosExtras("cluster:\n  clusterName: test\n", &tgpv1.TalosConfig{Env: map[string]string{"FOO": "bar"}}); err == nil {
+		t.Error("expected an error for a config without a machine section")
+	}
+}
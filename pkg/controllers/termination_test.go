@@ -0,0 +1,28 @@
+package controllers
+
+import (
+	"testing"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+func TestRecordTermination(t *testing.T) {
+	r := &GPUNodePoolReconciler{}
+	nodePool := &tgpv1.GPUNodePool{}
+
+	r.recordTermination(nodePool, "vultr", "v-1", "", tgpv1.TerminationReasonLaunchFailed)
+
+	last := nodePool.Status.LastTermination
+	if last == nil {
+		t.Fatal("expected the termination to be recorded in the pool status")
+	}
+	if last.InstanceID != "v-1" || last.Provider != "vultr" || last.Reason != tgpv1.TerminationReasonLaunchFailed {
+		t.Errorf("LastTermination = %+v, want v-1 on vultr with reason LaunchFailed", last)
+	}
+	if last.Time.IsZero() {
+		t.Error("expected the termination time to be set")
+	}
+
+	// Terminations for pools that no longer exist are only counted
+	r.recordTermination(nil, "gcp", "g-1", "node-1", tgpv1.TerminationReasonOrphaned)
+}
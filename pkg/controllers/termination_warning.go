@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+const (
+	// AnnotationTerminationScheduledAt marks a node within the termination warning period
+	// with the time it expires
+	AnnotationTerminationScheduledAt = "tgp.io/termination-scheduled-at"
+
+	// EventReasonTerminationImminent is emitted once for each node entering the warning period
+	EventReasonTerminationImminent = "TerminationImminent"
+
+	// defaultTerminationWarningPeriod is how long before expiry nodes are reported when unset
+	defaultTerminationWarningPeriod = 10 * time.Minute
+)
+
+// warnImminentTerminations records when the pool's next node expires in its status, and marks
+// and reports nodes expiring within the warning period so their workloads can checkpoint.
+// It returns how long until the next node enters the warning period, or 0 if none will.
+func (r *GPUNodePoolReconciler) warnImminentTerminations(ctx context.Context, nodePool *tgpv1.GPUNodePool, nodes []corev1.Node, maxAge time.Duration, now time.Time, log logr.Logger) (time.Duration, error) {
+	warning := terminationWarningPeriod(nodePool)
+
+	var earliest time.Time
+	var imminent []string
+	var next time.Duration
+	for i := range nodes {
+		node := &nodes[i]
+		if node.DeletionTimestamp != nil {
+			continue
+		}
+
+		expireAt := nodeCreationTime(node).Add(maxAge)
+		if earliest.IsZero() || expireAt.Before(earliest) {
+			earliest = expireAt
+		}
+
+		warnAt := expireAt.Add(-warning)
+		if now.Before(warnAt) {
+			if wait := warnAt.Sub(now); next == 0 || wait < next {
+				next = wait
+			}
+			continue
+		}
+		// Expired nodes are terminated without further warning
+		if !now.Before(expireAt) {
+			continue
+		}
+		imminent = append(imminent, node.Name)
+
+		if _, marked := node.Annotations[AnnotationTerminationScheduledAt]; marked {
+			continue
+		}
+		if node.Annotations == nil {
+			node.Annotations = make(map[string]string)
+		}
+		node.Annotations[AnnotationTerminationScheduledAt] = expireAt.Format(time.RFC3339)
+		if err := r.Update(ctx, node); err != nil {
+			return 0, fmt.Errorf("failed to mark node %s for termination: %w", node.Name, err)
+		}
+		log.Info("Node termination is imminent", "node", node.Name, "expireAt", expireAt.Format(time.RFC3339))
+		r.recordEvent(nodePool, corev1.EventTypeWarning, EventReasonTerminationImminent,
+			fmt.Sprintf("Node %s reaches its maximum age and is terminated at %s", node.Name, expireAt.Format(time.RFC3339)))
+	}
+
+	nodePool.Status.TerminationScheduledAt = nil
+	if !earliest.IsZero() {
+		scheduledAt := metav1.NewTime(earliest)
+		nodePool.Status.TerminationScheduledAt = &scheduledAt
+	}
+
+	if len(imminent) > 0 {
+		r.updateCondition(nodePool, tgpv1.ConditionTypeTerminationImminent, metav1.ConditionTrue, tgpv1.TerminationImminentReasonExpiryApproaching,
+			fmt.Sprintf("Nodes expiring within %s: %s", warning, strings.Join(imminent, ", ")))
+	} else {
+		r.updateCondition(nodePool, tgpv1.ConditionTypeTerminationImminent, metav1.ConditionFalse, tgpv1.TerminationImminentReasonNoExpiryApproaching,
+			fmt.Sprintf("No nodes expire within %s", warning))
+	}
+	return next, nil
+}
+
+// clearTerminationWarning drops the termination schedule of a pool that does not expire nodes
+func clearTerminationWarning(nodePool *tgpv1.GPUNodePool) {
+	nodePool.Status.TerminationScheduledAt = nil
+	meta.RemoveStatusCondition(&nodePool.Status.Conditions, tgpv1.ConditionTypeTerminationImminent)
+}
+
+// terminationWarningPeriod returns the pool's termination warning period, defaulting to ten minutes
+func terminationWarningPeriod(nodePool *tgpv1.GPUNodePool) time.Duration {
+	if nodePool.Spec.Disruption != nil && nodePool.Spec.Disruption.TerminationWarningPeriod != nil {
+		return nodePool.Spec.Disruption.TerminationWarningPeriod.Duration
+	}
+	return defaultTerminationWarningPeriod
+}
@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+func TestWarnImminentTerminations(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = tgpv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	nodePool := &tgpv1.GPUNodePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pool", Namespace: "default"},
+		Spec: tgpv1.GPUNodePoolSpec{
+			Disruption: &tgpv1.DisruptionSpec{
+				ExpireAfter:              &metav1.Duration{Duration: 24 * time.Hour},
+				TerminationWarningPeriod: &metav1.Duration{Duration: 15 * time.Minute},
+			},
+		},
+	}
+
+	poolNode := func(name string, age time.Duration) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Labels:      map[string]string{"tgp.io/nodepool": "test-pool", tgpv1.NodeLabelNodePoolNamespace: "default"},
+				Annotations: map[string]string{"tgp.io/created-at": now.Add(-age).Format(time.RFC3339)},
+			},
+		}
+	}
+	young := poolNode("young", time.Hour)
+	expiring := poolNode("expiring", 24*time.Hour-5*time.Minute)
+
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&young, &expiring).Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &GPUNodePoolReconciler{Client: client, Scheme: scheme, Recorder: recorder}
+
+	ctx := context.Background()
+	nodes := []corev1.Node{young, expiring}
+	next, err := r.warnImminentTerminations(ctx, nodePool, nodes, 24*time.Hour, now, logr.Discard())
+	if err != nil {
+		t.Fatalf("warnImminentTerminations failed: %v", err)
+	}
+	if want := 23*time.Hour - 15*time.Minute; next != want {
+		t.Errorf("expected the young node to be checked again in %s, got %s", want, next)
+	}
+
+	expireAt := now.Add(5 * time.Minute)
+	if scheduled := nodePool.Status.TerminationScheduledAt; scheduled == nil || !scheduled.Time.Equal(expireAt) {
+		t.Errorf("expected termination scheduled at %s, got %v", expireAt, scheduled)
+	}
+	condition := meta.FindStatusCondition(nodePool.Status.Conditions, tgpv1.ConditionTypeTerminationImminent)
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		t.Errorf("expected TerminationImminent condition, got %+v", condition)
+	}
+
+	var node corev1.Node
+	if err := client.Get(ctx, types.NamespacedName{Name: "expiring"}, &node); err != nil {
+		t.Fatalf("failed to get expiring node: %v", err)
+	}
+	if node.Annotations[AnnotationTerminationScheduledAt] != expireAt.Format(time.RFC3339) {
+		t.Errorf("expected the node to be marked with its termination time, got %v", node.Annotations)
+	}
+
+	// The warning is only emitted once for each node
+	if _, err := r.warnImminentTerminations(ctx, nodePool, []corev1.Node{young, node}, 24*time.Hour, now, logr.Discard()); err != nil {
+		t.Fatalf("warnImminentTerminations failed: %v", err)
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("expected one TerminationImminent event, got %d", len(recorder.Events))
+	}
+
+	// Pools that stop expiring nodes drop the schedule
+	nodePool.Spec.Disruption = nil
+	clearTerminationWarning(nodePool)
+	if nodePool.Status.TerminationScheduledAt != nil || meta.FindStatusCondition(nodePool.Status.Conditions, tgpv1.ConditionTypeTerminationImminent) != nil {
+		t.Error("expected the termination schedule to be cleared")
+	}
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// translationResult is a cached provider translation and the error it returned
+type translationResult struct {
+	value string
+	err   error
+}
+
+// translationCache caches GPU type and region translations per provider, since the
+// mappings are static and are consulted for every provider on every provisioning attempt.
+// The zero value is ready to use.
+type translationCache struct {
+	results sync.Map
+}
+
+// translate returns the provider-specific GPU type and region for a requirement, or an
+// error naming what the provider does not support
+func (c *translationCache) translate(client providers.ProviderClient, gpuType, region string) (string, string, error) {
+	providerName := client.GetProviderInfo().Name
+
+	providerGPUType, err := c.lookup(providerName, "gpu", gpuType, client.TranslateGPUType)
+	if err != nil {
+		return "", "", fmt.Errorf("provider %s does not support GPU type %s: %w", providerName, gpuType, err)
+	}
+
+	if region == "" {
+		return providerGPUType, "", nil
+	}
+	providerRegion, err := c.lookup(providerName, "region", region, client.TranslateRegion)
+	if err != nil {
+		return "", "", fmt.Errorf("provider %s does not support region %s: %w", providerName, region, err)
+	}
+
+	return providerGPUType, providerRegion, nil
+}
+
+func (c *translationCache) lookup(providerName, kind, standard string, translate func(string) (string, error)) (string, error) {
+	key := providerName + "/" + kind + "/" + standard
+	if cached, ok := c.results.Load(key); ok {
+		result := cached.(translationResult)
+		return result.value, result.err
+	}
+
+	value, err := translate(standard)
+	c.results.Store(key, translationResult{value: value, err: err})
+	return value, err
+}
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// countingTranslator supports a fixed set of GPU types and counts translation calls
+type countingTranslator struct {
+	providers.ProviderClient
+	gpuTypes map[string]string
+	calls    int
+}
+
+func (c *countingTranslator) GetProviderInfo() *providers.ProviderInfo {
+	return &providers.ProviderInfo{Name: "test"}
+}
+
+func (c *countingTranslator) TranslateGPUType(standard string) (string, error) {
+	c.calls++
+	if translated, ok := c.gpuTypes[standard]; ok {
+		return translated, nil
+	}
+	return "", fmt.Errorf("unsupported GPU type: %s", standard)
+}
+
+func (c *countingTranslator) TranslateRegion(standard string) (string, error) {
+	c.calls++
+	return standard, nil
+}
+
+func TestTranslationCache(t *testing.T) {
+	var cache translationCache
+	client := &countingTranslator{gpuTypes: map[string]string{"H100": "nvidia-h100"}}
+
+	gpuType, region, err := cache.translate(client, "H100", "us-east")
+	if err != nil {
+		t.Fatalf("translate() error = %v", err)
+	}
+	if gpuType != "nvidia-h100" || region != "us-east" {
+		t.Errorf("translate() = %q, %q, want nvidia-h100, us-east", gpuType, region)
+	}
+
+	if _, _, err := cache.translate(client, "H100", "us-east"); err != nil {
+		t.Fatalf("translate() error = %v", err)
+	}
+	if client.calls != 2 {
+		t.Errorf("expected cached translations to skip the provider, got %d calls", client.calls)
+	}
+
+	_, _, err = cache.translate(client, "RTX4090", "us-east")
+	if err == nil || !strings.Contains(err.Error(), "provider test does not support GPU type RTX4090") {
+		t.Errorf("translate() error = %v, want unsupported GPU type", err)
+	}
+	_, _, _ = cache.translate(client, "RTX4090", "us-east")
+	if client.calls != 3 {
+		t.Errorf("expected failed translations to be cached, got %d calls", client.calls)
+	}
+}
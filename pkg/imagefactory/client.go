@@ -61,12 +61,27 @@ const (
 	PlatformVultr        Platform = "vultr"
 	PlatformGCP          Platform = "gcp"
 	PlatformDigitalOcean Platform = "digital-ocean"
+	PlatformAWS          Platform = "aws"
+	PlatformAzure        Platform = "azure"
+	PlatformOracle       Platform = "oracle"
 )
 
 var supportedPlatforms = map[Platform]bool{
 	PlatformVultr:        true,
 	PlatformGCP:          true,
 	PlatformDigitalOcean: true,
+	PlatformAWS:          true,
+	PlatformAzure:        true,
+	PlatformOracle:       true,
+}
+
+// diskFormat returns the file extension of the platform's disk image. Oracle imports
+// QCOW2 images; the other platforms use compressed raw disks.
+func (p Platform) diskFormat() string {
+	if p == PlatformOracle {
+		return "qcow2"
+	}
+	return "raw.gz"
 }
 
 // IsPlatformSupported checks if a platform is supported
@@ -116,7 +131,7 @@ func (c *Client) GetImageURL(schematicID string, version string, platform Platfo
 		return "", fmt.Errorf("unsupported platform: %s", platform)
 	}
 
-	return fmt.Sprintf("%s/image/%s/%s/%s-amd64.raw.gz", c.baseURL, schematicID, version, platform), nil
+	return fmt.Sprintf("%s/image/%s/%s/%s-amd64.%s", c.baseURL, schematicID, version, platform, platform.diskFormat()), nil
 }
 
 // GenerateImageForExtensions creates a schematic and returns the image URL
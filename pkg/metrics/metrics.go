@@ -51,6 +51,24 @@ var (
 		[]string{"provider", "gpu_type", "region"},
 	)
 
+	instancesSpot = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: subsystem,
+			Name:      "instances_spot",
+			Help:      "Number of GPU instances running on spot capacity",
+		},
+		[]string{"provider", "gpu_type"},
+	)
+
+	instancesOnDemand = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: subsystem,
+			Name:      "instances_ondemand",
+			Help:      "Number of GPU instances running on on-demand capacity",
+		},
+		[]string{"provider", "gpu_type"},
+	)
+
 	// Cost metrics
 	instanceHourlyCost = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -61,6 +79,41 @@ var (
 		[]string{"provider", "gpu_type", "region"},
 	)
 
+	totalActiveCost = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Subsystem: subsystem,
+			Name:      "active_hourly_cost_usd",
+			Help:      "Total hourly cost in USD of all active GPU nodes",
+		},
+	)
+
+	activeCostByProvider = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: subsystem,
+			Name:      "active_hourly_cost_by_provider_usd",
+			Help:      "Hourly cost in USD of active GPU nodes, by provider",
+		},
+		[]string{"provider"},
+	)
+
+	activeCostByGPUType = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: subsystem,
+			Name:      "active_hourly_cost_by_gpu_type_usd",
+			Help:      "Hourly cost in USD of active GPU nodes, by GPU type",
+		},
+		[]string{"gpu_type"},
+	)
+
+	spotSavingsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: subsystem,
+			Name:      "spot_savings_dollars_total",
+			Help:      "Estimated USD saved by running spot instances instead of on-demand",
+		},
+		[]string{"provider", "gpu_type"},
+	)
+
 	// Provider metrics
 	providerRequests = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -81,6 +134,15 @@ var (
 		[]string{"provider", "operation"},
 	)
 
+	providerAPIErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: subsystem,
+			Name:      "provider_api_errors_total",
+			Help:      "Total number of failed cloud provider API calls, by error class",
+		},
+		[]string{"provider", "error_class"},
+	)
+
 	// Health check metrics
 	healthChecksTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -100,6 +162,66 @@ var (
 		},
 		[]string{"provider", "gpu_type"},
 	)
+
+	// Reconcile metrics
+	reconcileRequeueTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: subsystem,
+			Name:      "reconcile_requeue_total",
+			Help:      "Total number of reconciles requeued, by controller and reason",
+		},
+		[]string{"controller", "reason"},
+	)
+
+	// Instance tag metrics
+	instanceTagUpdatesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: subsystem,
+			Name:      "instance_tag_updates_total",
+			Help:      "Total number of running instances re-tagged after a node class tag change",
+		},
+		[]string{"provider", "result"},
+	)
+
+	// Termination metrics
+	instanceTerminationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: subsystem,
+			Name:      "instance_terminations_total",
+			Help:      "Total number of instances terminated by the operator, by reason",
+		},
+		[]string{"provider", "reason"},
+	)
+
+	// Price history metrics
+	gpuPriceWindow = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: subsystem,
+			Name:      "gpu_price_window_usd_per_hour",
+			Help:      "Min, max and average hourly GPU price observed over the price history window",
+		},
+		[]string{"provider", "gpu_type", "region", "stat"},
+	)
+
+	// Quota metrics
+	providerQuotaUtilization = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: subsystem,
+			Name:      "provider_quota_utilization_ratio",
+			Help:      "Running and in-flight instances as a fraction of the provider's configured maximum",
+		},
+		[]string{"provider"},
+	)
+
+	// Launch concurrency metrics
+	providerLaunchesInFlight = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: subsystem,
+			Name:      "provider_launches_in_flight",
+			Help:      "Number of instance launch calls currently in progress at each provider",
+		},
+		[]string{"provider"},
+	)
 )
 
 // RegisterMetrics registers all metrics with the controller-runtime metrics registry
@@ -109,11 +231,24 @@ func RegisterMetrics() {
 		gpuRequestDuration,
 		instanceLaunchDuration,
 		instancesActive,
+		instancesSpot,
+		instancesOnDemand,
 		instanceHourlyCost,
+		totalActiveCost,
+		activeCostByProvider,
+		activeCostByGPUType,
+		spotSavingsTotal,
 		providerRequests,
 		providerRequestDuration,
+		providerAPIErrors,
 		healthChecksTotal,
 		idleTimeoutsTotal,
+		reconcileRequeueTotal,
+		instanceTagUpdatesTotal,
+		gpuPriceWindow,
+		instanceTerminationsTotal,
+		providerQuotaUtilization,
+		providerLaunchesInFlight,
 	)
 }
 
@@ -145,11 +280,53 @@ func (m *Metrics) SetInstanceActive(provider, gpuType, region string, count floa
 	instancesActive.WithLabelValues(provider, gpuType, region).Set(count)
 }
 
+// InstanceGroup identifies the instances of one GPU type at one provider
+type InstanceGroup struct {
+	Provider string
+	GPUType  string
+}
+
+// SetCapacityTypeCounts replaces the number of spot and on-demand instances of each group,
+// dropping groups no longer running
+func (m *Metrics) SetCapacityTypeCounts(spot, onDemand map[InstanceGroup]int) {
+	instancesSpot.Reset()
+	for group, count := range spot {
+		instancesSpot.WithLabelValues(group.Provider, group.GPUType).Set(float64(count))
+	}
+	instancesOnDemand.Reset()
+	for group, count := range onDemand {
+		instancesOnDemand.WithLabelValues(group.Provider, group.GPUType).Set(float64(count))
+	}
+}
+
 // SetInstanceCost sets the hourly cost for an instance
 func (m *Metrics) SetInstanceCost(provider, gpuType, region string, cost float64) {
 	instanceHourlyCost.WithLabelValues(provider, gpuType, region).Set(cost)
 }
 
+// SetTotalActiveCost sets the total hourly cost of all active GPU nodes
+func (m *Metrics) SetTotalActiveCost(total float64) {
+	totalActiveCost.Set(total)
+}
+
+// SetActiveCostBreakdown replaces the per-provider and per-GPU-type hourly costs of active
+// GPU nodes, dropping providers and GPU types no longer running
+func (m *Metrics) SetActiveCostBreakdown(byProvider, byGPUType map[string]float64) {
+	activeCostByProvider.Reset()
+	for provider, cost := range byProvider {
+		activeCostByProvider.WithLabelValues(provider).Set(cost)
+	}
+	activeCostByGPUType.Reset()
+	for gpuType, cost := range byGPUType {
+		activeCostByGPUType.WithLabelValues(gpuType).Set(cost)
+	}
+}
+
+// RecordSpotSavings records the estimated savings of a spot instance versus on-demand
+func (m *Metrics) RecordSpotSavings(provider, gpuType string, dollars float64) {
+	spotSavingsTotal.WithLabelValues(provider, gpuType).Add(dollars)
+}
+
 // RecordProviderRequest records a request to a cloud provider
 func (m *Metrics) RecordProviderRequest(provider, operation, status string) {
 	providerRequests.WithLabelValues(provider, operation, status).Inc()
@@ -160,6 +337,11 @@ func (m *Metrics) RecordProviderRequestDuration(provider, operation string, dura
 	providerRequestDuration.WithLabelValues(provider, operation).Observe(duration)
 }
 
+// RecordProviderAPIError records a failed provider API call and the class of its error
+func (m *Metrics) RecordProviderAPIError(provider, errorClass string) {
+	providerAPIErrors.WithLabelValues(provider, errorClass).Inc()
+}
+
 // RecordHealthCheck records a health check result
 func (m *Metrics) RecordHealthCheck(provider, status string) {
 	healthChecksTotal.WithLabelValues(provider, status).Inc()
@@ -169,3 +351,35 @@ func (m *Metrics) RecordHealthCheck(provider, status string) {
 func (m *Metrics) RecordIdleTimeout(provider, gpuType string) {
 	idleTimeoutsTotal.WithLabelValues(provider, gpuType).Inc()
 }
+
+// RecordReconcileRequeue records a reconcile that was requeued and why
+func (m *Metrics) RecordReconcileRequeue(controller, reason string) {
+	reconcileRequeueTotal.WithLabelValues(controller, reason).Inc()
+}
+
+// RecordInstanceTagUpdate records an attempt to update the tags of a running instance
+func (m *Metrics) RecordInstanceTagUpdate(provider, result string) {
+	instanceTagUpdatesTotal.WithLabelValues(provider, result).Inc()
+}
+
+// RecordInstanceTermination records an instance terminated by the operator and why
+func (m *Metrics) RecordInstanceTermination(provider, reason string) {
+	instanceTerminationsTotal.WithLabelValues(provider, reason).Inc()
+}
+
+// SetPriceWindow records the min, max and average price observed over the price history window
+func (m *Metrics) SetPriceWindow(provider, gpuType, region string, minPrice, maxPrice, avgPrice float64) {
+	gpuPriceWindow.WithLabelValues(provider, gpuType, region, "min").Set(minPrice)
+	gpuPriceWindow.WithLabelValues(provider, gpuType, region, "max").Set(maxPrice)
+	gpuPriceWindow.WithLabelValues(provider, gpuType, region, "avg").Set(avgPrice)
+}
+
+// SetProviderQuotaUtilization records how much of a provider's configured instance limit is in use
+func (m *Metrics) SetProviderQuotaUtilization(provider string, ratio float64) {
+	providerQuotaUtilization.WithLabelValues(provider).Set(ratio)
+}
+
+// AddLaunchesInFlight adjusts the number of launch calls in progress at a provider by delta
+func (m *Metrics) AddLaunchesInFlight(provider string, delta float64) {
+	providerLaunchesInFlight.WithLabelValues(provider).Add(delta)
+}
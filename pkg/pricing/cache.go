@@ -17,15 +17,19 @@ type cacheEntry struct {
 }
 
 type Cache struct {
-	data  map[string]*cacheEntry
-	mutex sync.RWMutex
-	ttl   time.Duration
+	data          map[string]*cacheEntry
+	history       map[string][]priceSample
+	historyWindow time.Duration
+	mutex         sync.RWMutex
+	ttl           time.Duration
 }
 
 func NewCache(ttl time.Duration) *Cache {
 	return &Cache{
-		data: make(map[string]*cacheEntry),
-		ttl:  ttl,
+		data:          make(map[string]*cacheEntry),
+		history:       make(map[string][]priceSample),
+		historyWindow: DefaultHistoryWindow,
+		ttl:           ttl,
 	}
 }
 
@@ -61,6 +65,7 @@ func (c *Cache) GetPricing(
 	}
 
 	pricing := make(map[string]*providers.NormalizedPricing)
+	now := time.Now()
 
 	for providerName, provider := range providerClients {
 		priceInfo, err := provider.GetNormalizedPricing(ctx, gpuType, region)
@@ -68,11 +73,12 @@ func (c *Cache) GetPricing(
 			continue
 		}
 		pricing[providerName] = priceInfo
+		c.recordPriceLocked(providerName, gpuType, region, priceInfo.PricePerHour, now)
 	}
 
 	c.data[key] = &cacheEntry{
 		pricing:   pricing,
-		timestamp: time.Now(),
+		timestamp: now,
 	}
 
 	return pricing, nil
@@ -131,4 +137,5 @@ func (c *Cache) ClearCache() {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 	c.data = make(map[string]*cacheEntry)
+	c.history = make(map[string][]priceSample)
 }
@@ -53,6 +53,14 @@ func (m *mockProvider) GetInstanceStatus(ctx context.Context, instanceID string)
 	return nil, nil
 }
 
+func (m *mockProvider) AttachDataDisk(ctx context.Context, instanceID string, disk providers.DataDisk) error {
+	return nil
+}
+
+func (m *mockProvider) DetachDataDisk(ctx context.Context, instanceID, volumeID string) error {
+	return nil
+}
+
 func (m *mockProvider) ListAvailableGPUs(ctx context.Context, filters *providers.GPUFilters) ([]providers.GPUOffer, error) {
 	return nil, nil
 }
@@ -298,3 +306,74 @@ func TestCache_ClearCache(t *testing.T) {
 		}
 	})
 }
+
+func TestCache_PriceHistory(t *testing.T) {
+	cache := NewCache(time.Minute * 5)
+	cache.SetHistoryWindow(time.Hour)
+	now := time.Now()
+
+	t.Run("should return no summary without observations", func(t *testing.T) {
+		if _, ok := cache.PriceSummary("vultr", "H100", "us-east"); ok {
+			t.Error("Expected no summary before any prices are recorded")
+		}
+	})
+
+	t.Run("should summarize prices within the window", func(t *testing.T) {
+		cache.RecordPrice("vultr", "H100", "us-east", 5.00, now.Add(-2*time.Hour))
+		cache.RecordPrice("vultr", "H100", "us-east", 2.00, now.Add(-30*time.Minute))
+		cache.RecordPrice("vultr", "H100", "us-east", 3.00, now.Add(-10*time.Minute))
+		cache.RecordPrice("vultr", "H100", "us-east", 4.00, now)
+
+		summary, ok := cache.PriceSummary("vultr", "H100", "us-east")
+		if !ok {
+			t.Fatal("Expected a price summary")
+		}
+		if summary.Samples != 3 {
+			t.Errorf("Expected 3 samples within the window, got: %d", summary.Samples)
+		}
+		if summary.Min != 2.00 || summary.Max != 4.00 || summary.Average != 3.00 {
+			t.Errorf("Expected min 2.00, max 4.00, avg 3.00, got: %+v", summary)
+		}
+		if summary.Window != time.Hour {
+			t.Errorf("Expected window of 1h, got: %v", summary.Window)
+		}
+	})
+
+	t.Run("should keep history separate per provider and region", func(t *testing.T) {
+		if _, ok := cache.PriceSummary("gcp", "H100", "us-east"); ok {
+			t.Error("Expected no summary for a different provider")
+		}
+		if _, ok := cache.PriceSummary("vultr", "H100", "eu-west"); ok {
+			t.Error("Expected no summary for a different region")
+		}
+	})
+
+	t.Run("should record prices fetched into the cache", func(t *testing.T) {
+		provider := &mockProvider{
+			name:    "vast.ai",
+			pricing: &providers.NormalizedPricing{PricePerHour: 0.50},
+		}
+		_, err := cache.GetPricing(context.Background(), map[string]providers.ProviderClient{"vast.ai": provider}, "RTX3090", "us-east-1")
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		summary, ok := cache.PriceSummary("vast.ai", "RTX3090", "us-east-1")
+		if !ok || summary.Samples != 1 || summary.Average != 0.50 {
+			t.Errorf("Expected one sample of 0.50, got: %+v", summary)
+		}
+	})
+}
+
+func TestCache_RecordPriceIgnoresRepeatedObservation(t *testing.T) {
+	cache := NewCache(time.Minute * 5)
+	observedAt := time.Now()
+
+	cache.RecordPrice("vultr", "H100", "us-east", 2.00, observedAt)
+	cache.RecordPrice("vultr", "H100", "us-east", 2.00, observedAt)
+
+	summary, ok := cache.PriceSummary("vultr", "H100", "us-east")
+	if !ok || summary.Samples != 1 {
+		t.Errorf("Expected a repeated observation to be recorded once, got: %+v", summary)
+	}
+}
@@ -0,0 +1,91 @@
+package pricing
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultHistoryWindow is how long observed prices are retained when no window is configured
+const DefaultHistoryWindow = 24 * time.Hour
+
+type priceSample struct {
+	price     float64
+	timestamp time.Time
+}
+
+// PriceSummary summarizes the prices observed for a provider, GPU type and region over the history window
+type PriceSummary struct {
+	Min     float64
+	Max     float64
+	Average float64
+	Samples int
+	Window  time.Duration
+}
+
+// SetHistoryWindow changes how long observed prices are retained
+func (c *Cache) SetHistoryWindow(window time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.historyWindow = window
+}
+
+// RecordPrice adds an observed hourly price to the rolling history
+func (c *Cache) RecordPrice(provider, gpuType, region string, price float64, observedAt time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.recordPriceLocked(provider, gpuType, region, price, observedAt)
+}
+
+// PriceSummary returns the min, max and average price observed within the history window
+func (c *Cache) PriceSummary(provider, gpuType, region string) (PriceSummary, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	cutoff := time.Now().Add(-c.historyWindow)
+	summary := PriceSummary{Window: c.historyWindow}
+	total := 0.0
+
+	for _, sample := range c.history[c.getHistoryKey(provider, gpuType, region)] {
+		if sample.timestamp.Before(cutoff) {
+			continue
+		}
+		if summary.Samples == 0 || sample.price < summary.Min {
+			summary.Min = sample.price
+		}
+		if summary.Samples == 0 || sample.price > summary.Max {
+			summary.Max = sample.price
+		}
+		total += sample.price
+		summary.Samples++
+	}
+
+	if summary.Samples == 0 {
+		return PriceSummary{}, false
+	}
+	summary.Average = total / float64(summary.Samples)
+	return summary, true
+}
+
+func (c *Cache) getHistoryKey(provider, gpuType, region string) string {
+	return fmt.Sprintf("%s:%s:%s", provider, gpuType, region)
+}
+
+// recordPriceLocked appends a sample and drops samples older than the history window, ignoring
+// repeats of the latest observation; callers must hold the write lock
+func (c *Cache) recordPriceLocked(provider, gpuType, region string, price float64, observedAt time.Time) {
+	key := c.getHistoryKey(provider, gpuType, region)
+	cutoff := observedAt.Add(-c.historyWindow)
+
+	// The same observation can be reported by several classes sharing an inventory refresh
+	if samples := c.history[key]; len(samples) > 0 && samples[len(samples)-1].timestamp.Equal(observedAt) {
+		return
+	}
+
+	samples := c.history[key][:0]
+	for _, sample := range c.history[key] {
+		if !sample.timestamp.Before(cutoff) {
+			samples = append(samples, sample)
+		}
+	}
+	c.history[key] = append(samples, priceSample{price: price, timestamp: observedAt})
+}
@@ -0,0 +1,152 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// DefaultInventoryErrorBackoff is how long a failed inventory query is reused before the provider is queried again
+const DefaultInventoryErrorBackoff = 30 * time.Second
+
+type inventoryEntry struct {
+	mutex     sync.Mutex
+	offers    []providers.GPUOffer
+	err       error
+	fetchedAt time.Time
+}
+
+// InventoryCache shares provider GPU offers across all GPUNodeClasses, so each provider
+// is queried at most once per refresh interval however many classes reference it
+type InventoryCache struct {
+	entries      map[string]*inventoryEntry
+	mutex        sync.Mutex
+	ttl          time.Duration
+	errorBackoff time.Duration
+}
+
+// NewInventoryCache creates an inventory cache that refreshes each provider's offers once per ttl
+func NewInventoryCache(ttl time.Duration) *InventoryCache {
+	return &InventoryCache{
+		entries:      make(map[string]*inventoryEntry),
+		ttl:          ttl,
+		errorBackoff: DefaultInventoryErrorBackoff,
+	}
+}
+
+// ListOffers returns the provider's offers and when they were fetched, querying the provider
+// only when the cached offers have expired. Concurrent callers for the same key share one query.
+// The key identifies the provider, the credentials used to query it and the filters, and starts
+// with the provider name followed by a slash.
+func (c *InventoryCache) ListOffers(ctx context.Context, key string, client providers.ProviderClient, filters *providers.GPUFilters) ([]providers.GPUOffer, time.Time, error) {
+	entry := c.entry(key)
+
+	entry.mutex.Lock()
+	defer entry.mutex.Unlock()
+
+	if !entry.fetchedAt.IsZero() {
+		age := time.Since(entry.fetchedAt)
+		if entry.err == nil && age < c.ttl {
+			return entry.offers, entry.fetchedAt, nil
+		}
+		if entry.err != nil && age < c.errorBackoff {
+			return nil, entry.fetchedAt, entry.err
+		}
+	}
+
+	offers, err := client.ListAvailableGPUs(ctx, filters)
+	entry.fetchedAt = time.Now()
+	entry.err = err
+	if err == nil {
+		entry.offers = offers
+	}
+
+	return offers, entry.fetchedAt, err
+}
+
+// NeedsRefresh reports whether ListOffers for the key would query the provider rather than
+// return cached offers or a recent error
+func (c *InventoryCache) NeedsRefresh(key string) bool {
+	entry := c.entry(key)
+
+	entry.mutex.Lock()
+	defer entry.mutex.Unlock()
+
+	if entry.fetchedAt.IsZero() {
+		return true
+	}
+	age := time.Since(entry.fetchedAt)
+	if entry.err != nil {
+		return age >= c.errorBackoff
+	}
+	return age >= c.ttl
+}
+
+func (c *InventoryCache) entry(key string) *inventoryEntry {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, exists := c.entries[key]
+	if !exists {
+		entry = &inventoryEntry{}
+		c.entries[key] = entry
+	}
+	return entry
+}
+
+// ExpireProvider drops the cached offers of a provider so the next lookup queries it again
+func (c *InventoryCache) ExpireProvider(provider string) {
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for key := range c.entries {
+		if strings.HasPrefix(key, provider+"/") {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// ClearCache drops the cached offers of every provider
+func (c *InventoryCache) ClearCache() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries = make(map[string]*inventoryEntry)
+}
+
+// Wrap returns a client whose ListAvailableGPUs calls are served from the cache. The scope
+// identifies the credentials and account the client queries, so clients for different
+// accounts never share offers. A nil InventoryCache returns the client unchanged.
+func (c *InventoryCache) Wrap(client providers.ProviderClient, scope string) providers.ProviderClient {
+	if c == nil || client == nil {
+		return client
+	}
+	return &cachedInventoryClient{ProviderClient: client, cache: c, scope: scope}
+}
+
+// cachedInventoryClient serves a provider client's offer listings from an InventoryCache
+type cachedInventoryClient struct {
+	providers.ProviderClient
+	cache *InventoryCache
+	scope string
+}
+
+// Unwrap returns the wrapped client so its optional capabilities can be detected
+func (c *cachedInventoryClient) Unwrap() providers.ProviderClient {
+	return c.ProviderClient
+}
+
+func (c *cachedInventoryClient) ListAvailableGPUs(ctx context.Context, filters *providers.GPUFilters) ([]providers.GPUOffer, error) {
+	if filters == nil {
+		filters = &providers.GPUFilters{}
+	}
+	key := fmt.Sprintf("%s/%s/%+v", c.GetProviderInfo().Name, c.scope, *filters)
+	offers, _, err := c.cache.ListOffers(ctx, key, c.ProviderClient, filters)
+	return offers, err
+}
@@ -0,0 +1,173 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+type inventoryProvider struct {
+	mockProvider
+	mutex     sync.Mutex
+	listCalls int
+	err       error
+}
+
+func (p *inventoryProvider) ListAvailableGPUs(ctx context.Context, filters *providers.GPUFilters) ([]providers.GPUOffer, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.listCalls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return []providers.GPUOffer{{GPUType: "H100", Region: "us-east", HourlyPrice: 2.50}}, nil
+}
+
+func (p *inventoryProvider) calls() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.listCalls
+}
+
+func TestInventoryCache_ListOffers(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should share one query between concurrent callers", func(t *testing.T) {
+		provider := &inventoryProvider{}
+		cache := NewInventoryCache(time.Minute)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				offers, _, err := cache.ListOffers(ctx, "vultr/default", provider, &providers.GPUFilters{})
+				if err != nil || len(offers) != 1 {
+					t.Errorf("Expected one offer and no error, got: %v, %v", offers, err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if provider.calls() != 1 {
+			t.Errorf("Expected provider to be queried once, got: %d", provider.calls())
+		}
+	})
+
+	t.Run("should keep keys separate", func(t *testing.T) {
+		provider := &inventoryProvider{}
+		cache := NewInventoryCache(time.Minute)
+
+		_, _, _ = cache.ListOffers(ctx, "vultr/default", provider, &providers.GPUFilters{})
+		_, _, _ = cache.ListOffers(ctx, "vultr/team-a", provider, &providers.GPUFilters{})
+
+		if provider.calls() != 2 {
+			t.Errorf("Expected one query per key, got: %d", provider.calls())
+		}
+	})
+
+	t.Run("should refresh after TTL expires", func(t *testing.T) {
+		provider := &inventoryProvider{}
+		cache := NewInventoryCache(time.Millisecond * 100)
+
+		_, first, _ := cache.ListOffers(ctx, "vultr/default", provider, &providers.GPUFilters{})
+		time.Sleep(time.Millisecond * 150)
+		_, second, _ := cache.ListOffers(ctx, "vultr/default", provider, &providers.GPUFilters{})
+
+		if provider.calls() != 2 {
+			t.Errorf("Expected provider to be queried twice after expiry, got: %d", provider.calls())
+		}
+		if !second.After(first) {
+			t.Errorf("Expected refreshed fetch time after %v, got: %v", first, second)
+		}
+	})
+
+	t.Run("should back off after a failed query", func(t *testing.T) {
+		provider := &inventoryProvider{err: errors.New("429 rate limit")}
+		cache := NewInventoryCache(time.Minute)
+
+		_, _, err := cache.ListOffers(ctx, "vultr/default", provider, &providers.GPUFilters{})
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+		_, _, err = cache.ListOffers(ctx, "vultr/default", provider, &providers.GPUFilters{})
+		if err == nil {
+			t.Error("Expected the cached error during backoff")
+		}
+
+		if provider.calls() != 1 {
+			t.Errorf("Expected provider to be queried once during backoff, got: %d", provider.calls())
+		}
+	})
+}
+
+func TestInventoryCache_Expiry(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should expire only the given provider", func(t *testing.T) {
+		vultr := &inventoryProvider{}
+		gcp := &inventoryProvider{}
+		cache := NewInventoryCache(time.Minute)
+
+		_, _, _ = cache.ListOffers(ctx, "vultr/default", vultr, &providers.GPUFilters{})
+		_, _, _ = cache.ListOffers(ctx, "gcp/default", gcp, &providers.GPUFilters{})
+		cache.ExpireProvider("vultr")
+		_, _, _ = cache.ListOffers(ctx, "vultr/default", vultr, &providers.GPUFilters{})
+		_, _, _ = cache.ListOffers(ctx, "gcp/default", gcp, &providers.GPUFilters{})
+
+		if vultr.calls() != 2 {
+			t.Errorf("Expected expired provider to be queried again, got: %d", vultr.calls())
+		}
+		if gcp.calls() != 1 {
+			t.Errorf("Expected other providers to stay cached, got: %d", gcp.calls())
+		}
+	})
+
+	t.Run("should clear cache and force refresh", func(t *testing.T) {
+		provider := &inventoryProvider{err: errors.New("429 rate limit")}
+		cache := NewInventoryCache(time.Minute)
+
+		_, _, _ = cache.ListOffers(ctx, "vultr/default", provider, &providers.GPUFilters{})
+		cache.ClearCache()
+		provider.err = nil
+		offers, _, err := cache.ListOffers(ctx, "vultr/default", provider, &providers.GPUFilters{})
+
+		if err != nil || len(offers) != 1 {
+			t.Errorf("Expected fresh offers after clearing, got: %v, %v", offers, err)
+		}
+		if provider.calls() != 2 {
+			t.Errorf("Expected provider to be queried again after clearing, got: %d", provider.calls())
+		}
+	})
+}
+
+func TestInventoryCache_Wrap(t *testing.T) {
+	ctx := context.Background()
+	provider := &inventoryProvider{mockProvider: mockProvider{name: "vultr"}}
+	cache := NewInventoryCache(time.Minute)
+
+	filters := &providers.GPUFilters{GPUType: "H100", Region: "us-east"}
+	_, _ = cache.Wrap(provider, "default/").ListAvailableGPUs(ctx, filters)
+	_, _ = cache.Wrap(provider, "default/").ListAvailableGPUs(ctx, &providers.GPUFilters{GPUType: "H100", Region: "us-east"})
+	if provider.calls() != 1 {
+		t.Errorf("Expected identical lookups to share one query, got: %d", provider.calls())
+	}
+
+	_, _ = cache.Wrap(provider, "default/").ListAvailableGPUs(ctx, &providers.GPUFilters{GPUType: "A100", Region: "us-east"})
+	_, _ = cache.Wrap(provider, "team-a/").ListAvailableGPUs(ctx, filters)
+	if provider.calls() != 3 {
+		t.Errorf("Expected one query per filter and scope, got: %d", provider.calls())
+	}
+
+	if providers.Unwrap(cache.Wrap(provider, "default/")) != providers.ProviderClient(provider) {
+		t.Error("Expected Unwrap to return the wrapped client")
+	}
+	var disabled *InventoryCache
+	if disabled.Wrap(provider, "default/") != providers.ProviderClient(provider) {
+		t.Error("Expected a nil cache to return the client unchanged")
+	}
+}
@@ -0,0 +1,21 @@
+package providers
+
+import "fmt"
+
+// AccountSelector is implemented by providers whose credentials can operate on an
+// account or project other than the one they belong to, such as GCP projects.
+type AccountSelector interface {
+	SelectAccount(account string) error
+}
+
+// SelectAccount points the client at the given account. An empty account keeps the
+// credentials' default; providers without account selection reject any other value.
+func SelectAccount(client ProviderClient, account string) error {
+	if account == "" {
+		return nil
+	}
+	if selector, ok := Unwrap(client).(AccountSelector); ok {
+		return selector.SelectAccount(account)
+	}
+	return fmt.Errorf("provider %s does not support selecting an account", client.GetProviderInfo().Name)
+}
@@ -0,0 +1,581 @@
+package aws
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+
+	v1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+const testCredentials = `{"accessKeyId":"AKIATEST","secretAccessKey":"secret","region":"us-west-2"}`
+
+// fakeEC2 records requests and returns canned responses
+type fakeEC2 struct {
+	ec2API
+
+	runInput       *ec2.RunInstancesInput
+	terminateErr   error
+	terminated     []string
+	images         []types.Image
+	createdTags    []types.Tag
+	deletedTags    []types.Tag
+	attachedVolume *ec2.AttachVolumeInput
+	// listPages are the pages of instances returned for filtered DescribeInstances calls
+	listPages   [][]types.Instance
+	listFilters []types.Filter
+	// instanceTags are the tags of instances described by ID
+	instanceTags []types.Tag
+}
+
+func (f *fakeEC2) RunInstances(ctx context.Context, params *ec2.RunInstancesInput, optFns ...func(*ec2.Options)) (*ec2.RunInstancesOutput, error) {
+	f.runInput = params
+	lifecycle := types.InstanceLifecycleType("")
+	if params.InstanceMarketOptions != nil {
+		lifecycle = types.InstanceLifecycleTypeSpot
+	}
+	return &ec2.RunInstancesOutput{Instances: []types.Instance{{
+		InstanceId:        aws.String("i-0123456789abcdef0"),
+		State:             &types.InstanceState{Name: types.InstanceStateNamePending},
+		PrivateIpAddress:  aws.String("10.0.0.10"),
+		InstanceLifecycle: lifecycle,
+	}}}, nil
+}
+
+func (f *fakeEC2) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	if len(params.InstanceIds) == 0 {
+		f.listFilters = params.Filters
+		page := 0
+		if params.NextToken != nil {
+			fmt.Sscan(*params.NextToken, &page)
+		}
+		output := &ec2.DescribeInstancesOutput{Reservations: []types.Reservation{{Instances: f.listPages[page]}}}
+		if page+1 < len(f.listPages) {
+			output.NextToken = aws.String(fmt.Sprint(page + 1))
+		}
+		return output, nil
+	}
+	lifecycle := types.InstanceLifecycleType("")
+	if f.runInput != nil && f.runInput.InstanceMarketOptions != nil {
+		lifecycle = types.InstanceLifecycleTypeSpot
+	}
+	return &ec2.DescribeInstancesOutput{Reservations: []types.Reservation{{Instances: []types.Instance{{
+		InstanceId:        aws.String(params.InstanceIds[0]),
+		State:             &types.InstanceState{Name: types.InstanceStateNameRunning},
+		PublicIpAddress:   aws.String("203.0.113.10"),
+		PrivateIpAddress:  aws.String("10.0.0.10"),
+		InstanceLifecycle: lifecycle,
+		Tags:              f.instanceTags,
+	}}}}}, nil
+}
+
+func (f *fakeEC2) DescribeImages(ctx context.Context, params *ec2.DescribeImagesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error) {
+	return &ec2.DescribeImagesOutput{Images: f.images}, nil
+}
+
+func (f *fakeEC2) TerminateInstances(ctx context.Context, params *ec2.TerminateInstancesInput, optFns ...func(*ec2.Options)) (*ec2.TerminateInstancesOutput, error) {
+	f.terminated = append(f.terminated, params.InstanceIds...)
+	return &ec2.TerminateInstancesOutput{}, f.terminateErr
+}
+
+func (f *fakeEC2) AttachVolume(ctx context.Context, params *ec2.AttachVolumeInput, optFns ...func(*ec2.Options)) (*ec2.AttachVolumeOutput, error) {
+	f.attachedVolume = params
+	return &ec2.AttachVolumeOutput{}, nil
+}
+
+func (f *fakeEC2) CreateTags(ctx context.Context, params *ec2.CreateTagsInput, optFns ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error) {
+	f.createdTags = params.Tags
+	return &ec2.CreateTagsOutput{}, nil
+}
+
+func (f *fakeEC2) DeleteTags(ctx context.Context, params *ec2.DeleteTagsInput, optFns ...func(*ec2.Options)) (*ec2.DeleteTagsOutput, error) {
+	f.deletedTags = params.Tags
+	return &ec2.DeleteTagsOutput{}, nil
+}
+
+// newTestClient returns a client whose EC2 calls in every region go to fake
+func newTestClient(t *testing.T, fake *fakeEC2) *Client {
+	t.Helper()
+	client, err := NewClient(testCredentials)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	client.newEC2 = func(region string) ec2API { return fake }
+	return client
+}
+
+func TestNewClient(t *testing.T) {
+	client, err := NewClient(testCredentials)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	if client.defaultRegion != "us-west-2" {
+		t.Errorf("Expected default region us-west-2, got %s", client.defaultRegion)
+	}
+
+	client, err = NewClient(`{"accessKeyId":"AKIATEST","secretAccessKey":"secret"}`)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	if client.defaultRegion != DefaultRegion {
+		t.Errorf("Expected default region %s, got %s", DefaultRegion, client.defaultRegion)
+	}
+
+	for _, invalid := range []string{"not-json", `{"accessKeyId":"AKIATEST"}`, `{"secretAccessKey":"secret"}`} {
+		if _, err := NewClient(invalid); err == nil {
+			t.Errorf("Expected error for credentials %q", invalid)
+		}
+	}
+}
+
+func TestNewClientAmbient(t *testing.T) {
+	t.Setenv("AWS_REGION", "eu-central-1")
+	t.Setenv("AWS_CONFIG_FILE", "/nonexistent")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "/nonexistent")
+
+	client, err := NewClient("")
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	if client.defaultRegion != "eu-central-1" {
+		t.Errorf("Expected the region of the environment, got %s", client.defaultRegion)
+	}
+	if client.credentials == nil {
+		t.Error("Expected the default credential chain")
+	}
+}
+
+func TestTranslateGPUType(t *testing.T) {
+	client := newTestClient(t, &fakeEC2{})
+
+	tests := map[string]string{
+		"T4":               "g4dn.xlarge",
+		"NVIDIA_A10G":      "g5.xlarge",
+		"a10g":             "g5.xlarge",
+		"V100":             "p3.2xlarge",
+		"A100":             "p4d.24xlarge",
+		"A100-80GB":        "p4de.24xlarge",
+		"NVIDIA_H100_80GB": "p5.48xlarge",
+	}
+	for gpuType, expected := range tests {
+		got, err := client.TranslateGPUType(gpuType)
+		if err != nil {
+			t.Errorf("TranslateGPUType(%s) failed: %v", gpuType, err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("TranslateGPUType(%s) = %s, expected %s", gpuType, got, expected)
+		}
+	}
+
+	if _, err := client.TranslateGPUType("RTX4090"); err == nil {
+		t.Error("Expected error for GPU type AWS does not offer")
+	}
+}
+
+func TestTranslateRegion(t *testing.T) {
+	client := newTestClient(t, &fakeEC2{})
+
+	tests := map[string]string{
+		providers.RegionUSEast:    "us-east-1",
+		providers.RegionEUCentral: "eu-central-1",
+		"eu-west-1":               "eu-west-1",
+	}
+	for standard, expected := range tests {
+		got, err := client.TranslateRegion(standard)
+		if err != nil || got != expected {
+			t.Errorf("TranslateRegion(%s) = %s, %v, expected %s", standard, got, err, expected)
+		}
+	}
+
+	if _, err := client.TranslateRegion("mars-north-1"); err == nil {
+		t.Error("Expected error for unknown region")
+	}
+}
+
+func TestListAvailableGPUs(t *testing.T) {
+	client := newTestClient(t, &fakeEC2{})
+
+	offers, err := client.ListAvailableGPUs(context.Background(), &providers.GPUFilters{GPUType: "A100", Region: "us-east-1"})
+	if err != nil {
+		t.Fatalf("ListAvailableGPUs failed: %v", err)
+	}
+	if len(offers) != 2 {
+		t.Fatalf("Expected on-demand and spot offers, got %d", len(offers))
+	}
+	for _, offer := range offers {
+		if offer.GPUType != "NVIDIA_A100" || offer.GPUCount != 8 || offer.VCPUs != 96 || offer.Country != "US" {
+			t.Errorf("Unexpected offer: %+v", offer)
+		}
+	}
+
+	// p-family instances are not offered in every region
+	offers, err = client.ListAvailableGPUs(context.Background(), &providers.GPUFilters{GPUType: "H100", Region: "ap-south-1"})
+	if err != nil {
+		t.Fatalf("ListAvailableGPUs failed: %v", err)
+	}
+	if len(offers) != 0 {
+		t.Errorf("Expected no H100 offers in ap-south-1, got %d", len(offers))
+	}
+
+	offers, err = client.ListAvailableGPUs(context.Background(), &providers.GPUFilters{
+		GPUType:       "V100",
+		Countries:     []string{"DE"},
+		SpotOnly:      true,
+		MinVCPUPerGPU: 8,
+	})
+	if err != nil {
+		t.Fatalf("ListAvailableGPUs failed: %v", err)
+	}
+	if len(offers) != 0 {
+		t.Errorf("Expected no V100 offers in Germany, got %d", len(offers))
+	}
+
+	offers, err = client.ListAvailableGPUs(context.Background(), &providers.GPUFilters{GPUType: "T4", MinVCPUPerGPU: 8})
+	if err != nil {
+		t.Fatalf("ListAvailableGPUs failed: %v", err)
+	}
+	if len(offers) != 0 {
+		t.Errorf("Expected no T4 offers with 8 vCPUs per GPU, got %d", len(offers))
+	}
+}
+
+func TestGetNormalizedPricing(t *testing.T) {
+	client := newTestClient(t, &fakeEC2{})
+
+	pricing, err := client.GetNormalizedPricing(context.Background(), "T4", "us-east-1")
+	if err != nil {
+		t.Fatalf("GetNormalizedPricing failed: %v", err)
+	}
+	if pricing.PricePerHour != 0.526 || pricing.BillingModel != providers.BillingPerSecond {
+		t.Errorf("Unexpected pricing: %+v", pricing)
+	}
+
+	regional, err := client.GetNormalizedPricing(context.Background(), "T4", "ap-northeast-1")
+	if err != nil {
+		t.Fatalf("GetNormalizedPricing failed: %v", err)
+	}
+	if regional.PricePerHour <= pricing.PricePerHour {
+		t.Errorf("Expected Tokyo to cost more than us-east-1, got %f", regional.PricePerHour)
+	}
+}
+
+func TestLaunchInstance(t *testing.T) {
+	fake := &fakeEC2{images: []types.Image{
+		{ImageId: aws.String("ami-old"), CreationDate: aws.String("2025-01-01T00:00:00.000Z")},
+		{ImageId: aws.String("ami-new"), CreationDate: aws.String("2025-06-01T00:00:00.000Z")},
+	}}
+	client := newTestClient(t, fake)
+
+	instance, err := client.LaunchInstance(context.Background(), &providers.LaunchRequest{
+		GPUType:      "A10G",
+		Region:       providers.RegionUSEast,
+		UserData:     "machine: config",
+		Labels:       map[string]string{"tgp.io/nodepool": "pool"},
+		Tags:         map[string]string{"team": "ml", "aws:reserved": "x"},
+		SpotInstance: true,
+		MaxPrice:     0.5,
+		TalosConfig:  &v1.TalosConfig{Image: "ghcr.io/siderolabs/talos:v1.10.5"},
+	})
+	if err != nil {
+		t.Fatalf("LaunchInstance failed: %v", err)
+	}
+
+	if instance.ID != "us-east-1/i-0123456789abcdef0" {
+		t.Errorf("Expected region-qualified instance ID, got %s", instance.ID)
+	}
+	// The launch returns without waiting for the instance to run
+	if instance.PrivateIP != "10.0.0.10" || instance.Status != providers.InstanceStatePending || !instance.IsSpot {
+		t.Errorf("Unexpected instance: %+v", instance)
+	}
+
+	input := fake.runInput
+	if aws.ToString(input.ImageId) != "ami-new" {
+		t.Errorf("Expected newest Talos AMI, got %s", aws.ToString(input.ImageId))
+	}
+	if input.InstanceType != "g5.xlarge" {
+		t.Errorf("Expected g5.xlarge, got %s", input.InstanceType)
+	}
+	userData, _ := base64.StdEncoding.DecodeString(aws.ToString(input.UserData))
+	if string(userData) != "machine: config" {
+		t.Errorf("Expected base64 user data, got %q", userData)
+	}
+	if input.InstanceMarketOptions == nil || input.InstanceMarketOptions.MarketType != types.MarketTypeSpot {
+		t.Fatal("Expected spot market options")
+	}
+	if aws.ToString(input.InstanceMarketOptions.SpotOptions.MaxPrice) != "0.5000" {
+		t.Errorf("Expected spot max price 0.5000, got %s", aws.ToString(input.InstanceMarketOptions.SpotOptions.MaxPrice))
+	}
+
+	tags := make(map[string]string)
+	for _, tag := range input.TagSpecifications[0].Tags {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	if tags["team"] != "ml" || tags["tgp.io/nodepool"] != "pool" || tags["Name"] != "tgp-a10g" {
+		t.Errorf("Unexpected tags: %v", tags)
+	}
+	if _, exists := tags["aws:reserved"]; exists {
+		t.Error("Expected reserved aws: tag to be skipped")
+	}
+
+	if _, err := client.LaunchInstance(context.Background(), &providers.LaunchRequest{
+		GPUType:   "T4",
+		DataDisks: []providers.DataDisk{{VolumeID: "vol-1"}},
+	}); err == nil {
+		t.Error("Expected error for data disks at launch")
+	}
+	if !client.GetProviderInfo().AttachesDataDisksAfterLaunch {
+		t.Error("Expected data disks to be attached once the instance is running")
+	}
+}
+
+func TestLaunchInstanceOnDemandWithAMI(t *testing.T) {
+	fake := &fakeEC2{}
+	client := newTestClient(t, fake)
+
+	instance, err := client.LaunchInstance(context.Background(), &providers.LaunchRequest{GPUType: "T4", Image: "ami-custom"})
+	if err != nil {
+		t.Fatalf("LaunchInstance failed: %v", err)
+	}
+	if instance.ID != "us-west-2/i-0123456789abcdef0" {
+		t.Errorf("Expected instance in the default region, got %s", instance.ID)
+	}
+	if aws.ToString(fake.runInput.ImageId) != "ami-custom" {
+		t.Errorf("Expected requested AMI, got %s", aws.ToString(fake.runInput.ImageId))
+	}
+	if fake.runInput.InstanceMarketOptions != nil {
+		t.Error("Expected on-demand launch")
+	}
+	if len(fake.runInput.BlockDeviceMappings) != 0 {
+		t.Errorf("Expected the AMI's root volume size, got %+v", fake.runInput.BlockDeviceMappings)
+	}
+
+	if _, err := client.LaunchInstance(context.Background(), &providers.LaunchRequest{GPUType: "T4", Image: "ami-custom", BootDiskGiB: 200}); err != nil {
+		t.Fatalf("LaunchInstance failed: %v", err)
+	}
+	mappings := fake.runInput.BlockDeviceMappings
+	if len(mappings) != 1 || aws.ToString(mappings[0].DeviceName) != "/dev/xvda" || aws.ToInt32(mappings[0].Ebs.VolumeSize) != 200 {
+		t.Errorf("Expected a 200GiB root volume, got %+v", mappings)
+	}
+}
+
+func TestLaunchInstanceMultiGPU(t *testing.T) {
+	fake := &fakeEC2{}
+	client := newTestClient(t, fake)
+
+	if _, err := client.LaunchInstance(context.Background(), &providers.LaunchRequest{GPUType: "L4", GPUCount: 2, Image: "ami-custom"}); err != nil {
+		t.Fatalf("LaunchInstance failed: %v", err)
+	}
+	if fake.runInput.InstanceType != "g6.12xlarge" {
+		t.Errorf("Expected the smallest L4 instance with 2 GPUs, g6.12xlarge, got %s", fake.runInput.InstanceType)
+	}
+
+	if _, err := client.LaunchInstance(context.Background(), &providers.LaunchRequest{GPUType: "T4", GPUCount: 8, Image: "ami-custom"}); err == nil {
+		t.Error("Expected error when no T4 instance type has 8 GPUs")
+	}
+}
+
+func TestTerminateInstance(t *testing.T) {
+	fake := &fakeEC2{}
+	client := newTestClient(t, fake)
+
+	if err := client.TerminateInstance(context.Background(), "eu-west-1/i-abc"); err != nil {
+		t.Fatalf("TerminateInstance failed: %v", err)
+	}
+	if len(fake.terminated) != 1 || fake.terminated[0] != "i-abc" {
+		t.Errorf("Expected i-abc to be terminated, got %v", fake.terminated)
+	}
+
+	fake.terminateErr = &smithy.GenericAPIError{Code: "InvalidInstanceID.NotFound"}
+	if err := client.TerminateInstance(context.Background(), "eu-west-1/i-gone"); err != nil {
+		t.Errorf("Expected missing instance to count as terminated, got %v", err)
+	}
+
+	fake.terminateErr = fmt.Errorf("throttled")
+	if err := client.TerminateInstance(context.Background(), "eu-west-1/i-abc"); err == nil {
+		t.Error("Expected termination error")
+	}
+
+	fake.terminateErr = &smithy.GenericAPIError{Code: "RequestLimitExceeded"}
+	if err := client.TerminateInstance(context.Background(), "eu-west-1/i-abc"); !errors.Is(err, providers.ErrRateLimited) {
+		t.Errorf("Expected a rate limited error, got %v", err)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	tests := map[string]error{
+		"InsufficientInstanceCapacity": providers.ErrNoCapacity,
+		"UnauthorizedOperation":        providers.ErrUnauthorized,
+		"PendingVerification":          providers.ErrBilling,
+		"InvalidAMIID.NotFound":        providers.ErrNotFound,
+	}
+	for code, want := range tests {
+		if err := apiError(&smithy.GenericAPIError{Code: code}); !errors.Is(err, want) {
+			t.Errorf("apiError(%s) = %v, want %v", code, err, want)
+		}
+	}
+
+	if err := apiError(&smithy.GenericAPIError{Code: "InvalidParameterValue"}); errors.Is(err, providers.ErrNoCapacity) {
+		t.Errorf("Expected an unclassified error, got %v", err)
+	}
+}
+
+func TestParseInstanceID(t *testing.T) {
+	client := newTestClient(t, &fakeEC2{})
+
+	region, id, err := client.parseInstanceID("eu-west-1/i-abc")
+	if err != nil || region != "eu-west-1" || id != "i-abc" {
+		t.Errorf("Unexpected parse: %s %s %v", region, id, err)
+	}
+
+	region, id, err = client.parseInstanceID("i-abc")
+	if err != nil || region != "us-west-2" || id != "i-abc" {
+		t.Errorf("Expected default region for bare ID, got %s %s %v", region, id, err)
+	}
+
+	if _, _, err := client.parseInstanceID(""); err == nil {
+		t.Error("Expected error for empty instance ID")
+	}
+}
+
+func TestUpdateInstanceTags(t *testing.T) {
+	fake := &fakeEC2{}
+	client := newTestClient(t, fake)
+
+	err := client.UpdateInstanceTags(context.Background(), "us-east-1/i-abc", map[string]string{"team": "ml"}, []string{"old"})
+	if err != nil {
+		t.Fatalf("UpdateInstanceTags failed: %v", err)
+	}
+	if len(fake.createdTags) != 1 || aws.ToString(fake.createdTags[0].Key) != "team" {
+		t.Errorf("Unexpected created tags: %v", fake.createdTags)
+	}
+	if len(fake.deletedTags) != 1 || aws.ToString(fake.deletedTags[0].Key) != "old" {
+		t.Errorf("Unexpected deleted tags: %v", fake.deletedTags)
+	}
+}
+
+func TestMissingInstanceTags(t *testing.T) {
+	fake := &fakeEC2{instanceTags: []types.Tag{
+		{Key: aws.String("team"), Value: aws.String("ml")},
+		{Key: aws.String("env"), Value: aws.String("dev")},
+	}}
+	client := newTestClient(t, fake)
+
+	missing, err := client.MissingInstanceTags(context.Background(), "us-east-1/i-abc",
+		map[string]string{"team": "ml", "env": "prod", "cost-center": "42", "aws:reserved": "x"})
+	if err != nil {
+		t.Fatalf("MissingInstanceTags failed: %v", err)
+	}
+	if len(missing) != 2 || missing["env"] != "prod" || missing["cost-center"] != "42" {
+		t.Errorf("MissingInstanceTags() = %v, want the changed env and absent cost-center tags", missing)
+	}
+}
+
+func TestAttachDataDisk(t *testing.T) {
+	fake := &fakeEC2{}
+	client := newTestClient(t, fake)
+
+	if err := client.AttachDataDisk(context.Background(), "us-east-1/i-abc", providers.DataDisk{VolumeID: "vol-1"}); err != nil {
+		t.Fatalf("AttachDataDisk failed: %v", err)
+	}
+	if aws.ToString(fake.attachedVolume.Device) != "/dev/sdf" {
+		t.Errorf("Expected default device /dev/sdf, got %s", aws.ToString(fake.attachedVolume.Device))
+	}
+
+	if err := client.AttachDataDisk(context.Background(), "us-east-1/i-abc", providers.DataDisk{VolumeID: "vol-1", ReadOnly: true}); err == nil {
+		t.Error("Expected error for read-only volume")
+	}
+}
+
+func TestMapInstanceState(t *testing.T) {
+	tests := map[types.InstanceStateName]providers.InstanceState{
+		types.InstanceStateNamePending:      providers.InstanceStatePending,
+		types.InstanceStateNameRunning:      providers.InstanceStateRunning,
+		types.InstanceStateNameShuttingDown: providers.InstanceStateTerminating,
+		types.InstanceStateNameTerminated:   providers.InstanceStateTerminated,
+	}
+	for name, expected := range tests {
+		if got := mapInstanceState(&types.InstanceState{Name: name}); got != expected {
+			t.Errorf("mapInstanceState(%s) = %s, expected %s", name, got, expected)
+		}
+	}
+	if got := mapInstanceState(nil); got != providers.InstanceStateUnknown {
+		t.Errorf("Expected unknown state for nil, got %s", got)
+	}
+}
+
+func TestTalosImageNamePattern(t *testing.T) {
+	tests := []struct {
+		talosConfig *v1.TalosConfig
+		expected    string
+	}{
+		{nil, "talos-v*-*-amd64"},
+		{&v1.TalosConfig{Image: "ghcr.io/siderolabs/talos:v1.10.5"}, "talos-v1.10.5-*-amd64"},
+		{&v1.TalosConfig{Image: "registry:5000/talos"}, "talos-v*-*-amd64"},
+	}
+	for _, tt := range tests {
+		if got := talosImageNamePattern(&providers.LaunchRequest{TalosConfig: tt.talosConfig}); got != tt.expected {
+			t.Errorf("talosImageNamePattern(%v) = %s, expected %s", tt.talosConfig, got, tt.expected)
+		}
+	}
+}
+
+func TestListManagedInstances(t *testing.T) {
+	fake := &fakeEC2{listPages: [][]types.Instance{
+		{{
+			InstanceId: aws.String("i-1"),
+			State:      &types.InstanceState{Name: types.InstanceStateNameRunning},
+			Tags:       []types.Tag{{Key: aws.String(v1.NodeLabelNodePool), Value: aws.String("training")}},
+		}},
+		{{
+			InstanceId:        aws.String("i-2"),
+			State:             &types.InstanceState{Name: types.InstanceStateNamePending},
+			InstanceLifecycle: types.InstanceLifecycleTypeSpot,
+		}},
+	}}
+	client := newTestClient(t, fake)
+
+	instances, err := providers.ListManagedInstances(context.Background(), client, "")
+	if err != nil {
+		t.Fatalf("ListManagedInstances failed: %v", err)
+	}
+	if len(instances) != 2 {
+		t.Fatalf("Expected an instance from each page, got %+v", instances)
+	}
+	if instances[0].ID != "us-west-2/i-1" || instances[0].NodePool != "training" || instances[0].Status != providers.InstanceStateRunning {
+		t.Errorf("Unexpected first instance %+v", instances[0])
+	}
+	if instances[1].ID != "us-west-2/i-2" || instances[1].NodePool != "" || !instances[1].IsSpot {
+		t.Errorf("Unexpected second instance %+v", instances[1])
+	}
+	if len(fake.listFilters) == 0 || aws.ToString(fake.listFilters[0].Name) != "tag-key" || fake.listFilters[0].Values[0] != v1.NodeLabelNodePool {
+		t.Errorf("Expected instances to be filtered by the node pool tag, got %+v", fake.listFilters)
+	}
+
+	if _, err := client.ListManagedInstances(context.Background(), "mars-1"); err == nil {
+		t.Error("Expected an error for an unknown region")
+	}
+}
+
+func TestRenderUserData(t *testing.T) {
+	client := newTestClient(t, &fakeEC2{})
+
+	config := "version: v1alpha1\nmachine:\n  type: worker\n"
+	if rendered, err := providers.RenderUserData(client, config); err != nil || rendered != config {
+		t.Errorf("RenderUserData() = %q, %v, want the machine config unchanged", rendered, err)
+	}
+
+	oversized := strings.Repeat("a", maxUserDataBytes+1)
+	if _, err := providers.RenderUserData(client, oversized); err == nil {
+		t.Error("Expected error for machine config exceeding the user data limit")
+	}
+}
@@ -0,0 +1,738 @@
+package aws
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+	"github.com/go-logr/logr"
+
+	v1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+const (
+	ProviderName = "aws"
+
+	// DefaultRegion is used when the credentials do not name a region
+	DefaultRegion = "us-east-1"
+
+	// rootDeviceName is the root device of the Talos AMIs
+	rootDeviceName = "/dev/xvda"
+
+	// minRootVolumeGiB is the smallest root volume a Talos node can install to
+	minRootVolumeGiB = 10
+
+	// maxUserDataBytes is the EC2 limit on user data before it is base64-encoded
+	maxUserDataBytes = 16 * 1024
+)
+
+// ec2API is the subset of the EC2 API used by the client
+type ec2API interface {
+	RunInstances(ctx context.Context, params *ec2.RunInstancesInput, optFns ...func(*ec2.Options)) (*ec2.RunInstancesOutput, error)
+	TerminateInstances(ctx context.Context, params *ec2.TerminateInstancesInput, optFns ...func(*ec2.Options)) (*ec2.TerminateInstancesOutput, error)
+	DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)
+	DescribeImages(ctx context.Context, params *ec2.DescribeImagesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error)
+	AttachVolume(ctx context.Context, params *ec2.AttachVolumeInput, optFns ...func(*ec2.Options)) (*ec2.AttachVolumeOutput, error)
+	DetachVolume(ctx context.Context, params *ec2.DetachVolumeInput, optFns ...func(*ec2.Options)) (*ec2.DetachVolumeOutput, error)
+	CreateTags(ctx context.Context, params *ec2.CreateTagsInput, optFns ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error)
+	DeleteTags(ctx context.Context, params *ec2.DeleteTagsInput, optFns ...func(*ec2.Options)) (*ec2.DeleteTagsOutput, error)
+	DescribeRegions(ctx context.Context, params *ec2.DescribeRegionsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeRegionsOutput, error)
+}
+
+// Credentials is the JSON structure of the AWS credentials secret
+type Credentials struct {
+	AccessKeyID     string `json:"accessKeyId"`
+	SecretAccessKey string `json:"secretAccessKey"`
+	SessionToken    string `json:"sessionToken,omitempty"`
+	Region          string `json:"region,omitempty"`
+}
+
+// Client implements the ProviderClient interface for AWS EC2
+type Client struct {
+	credentials   aws.CredentialsProvider
+	defaultRegion string
+	// httpClient replaces the SDK's default HTTP client when debug logging is enabled
+	httpClient *http.Client
+	// newEC2 creates the EC2 client for a region
+	newEC2 func(region string) ec2API
+
+	mutex   sync.Mutex
+	clients map[string]ec2API
+}
+
+// NewClient creates a new AWS provider client from a JSON credentials blob holding an
+// access key ID, secret access key and optionally a session token and default region.
+// Empty credentials use the SDK's default credential chain instead, such as IRSA or EKS
+// Pod Identity.
+func NewClient(credentialsJSON string) (*Client, error) {
+	if strings.TrimSpace(credentialsJSON) == "" {
+		return newAmbientClient()
+	}
+
+	var creds Credentials
+	if err := json.Unmarshal([]byte(credentialsJSON), &creds); err != nil {
+		return nil, fmt.Errorf("failed to parse AWS credentials JSON: %w", err)
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return nil, fmt.Errorf("AWS credentials require accessKeyId and secretAccessKey")
+	}
+
+	provider := credentials.NewStaticCredentialsProvider(creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken)
+	return newClient(provider, creds.Region), nil
+}
+
+// newAmbientClient creates a client with the credentials and region of the SDK's default
+// configuration, read from the environment, shared config files and web identity tokens
+func newAmbientClient() (*Client, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load default AWS configuration: %w", err)
+	}
+	return newClient(cfg.Credentials, cfg.Region), nil
+}
+
+// newClient creates a client with the credentials, defaulting the region to DefaultRegion
+func newClient(provider aws.CredentialsProvider, region string) *Client {
+	if region == "" {
+		region = DefaultRegion
+	}
+
+	c := &Client{
+		credentials:   provider,
+		defaultRegion: region,
+		clients:       make(map[string]ec2API),
+	}
+	c.newEC2 = c.newEC2Client
+	return c
+}
+
+// newEC2Client creates an EC2 API client for the region with the client's credentials
+func (c *Client) newEC2Client(region string) ec2API {
+	return ec2.New(ec2.Options{
+		Region:      region,
+		Credentials: aws.NewCredentialsCache(c.credentials),
+		HTTPClient:  c.httpClient,
+	})
+}
+
+// EnableDebugLogging logs every EC2 API request and response at debug level
+func (c *Client) EnableDebugLogging(log logr.Logger) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.httpClient = &http.Client{Transport: providers.NewDebugTransport(nil, log)}
+	c.clients = make(map[string]ec2API)
+}
+
+// ec2For returns the EC2 client for a region, creating it on first use
+func (c *Client) ec2For(region string) ec2API {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	client, exists := c.clients[region]
+	if !exists {
+		client = c.newEC2(region)
+		c.clients[region] = client
+	}
+	return client
+}
+
+// GetProviderInfo returns information about the AWS provider
+func (c *Client) GetProviderInfo() *providers.ProviderInfo {
+	return &providers.ProviderInfo{
+		Name:                  ProviderName,
+		APIVersion:            "2016-11-15",
+		SupportedRegions:      supportedRegions(),
+		SupportedGPUTypes:     supportedGPUTypes(),
+		SupportsSpotInstances: true,
+		SupportsMultiGPU:      true,
+		BillingGranularity:    providers.BillingPerSecond,
+		MinBillingPeriod:      time.Minute,
+		ReliabilityTier:       providers.ReliabilityTierEnterprise,
+
+		AttachesDataDisksAfterLaunch: true,
+	}
+}
+
+// GetRateLimits returns the rate limits for the EC2 API
+func (c *Client) GetRateLimits() *providers.RateLimitInfo {
+	return &providers.RateLimitInfo{
+		RequestsPerSecond: 20,
+		BurstCapacity:     100,
+		BackoffStrategy:   "exponential",
+		ResetWindow:       time.Second,
+	}
+}
+
+// CheckCredentials lists the regions enabled for the account in the default region,
+// which fails when the access key is invalid
+func (c *Client) CheckCredentials(ctx context.Context) error {
+	if _, err := c.ec2For(c.defaultRegion).DescribeRegions(ctx, &ec2.DescribeRegionsInput{}); err != nil {
+		return fmt.Errorf("failed to describe AWS regions: %w", apiError(err))
+	}
+	return nil
+}
+
+// ListAvailableGPUs returns EC2 GPU instance types matching the filters
+func (c *Client) ListAvailableGPUs(ctx context.Context, filters *providers.GPUFilters) ([]providers.GPUOffer, error) {
+	if filters == nil {
+		filters = &providers.GPUFilters{}
+	}
+
+	regions := supportedRegions()
+	if filters.Region != "" {
+		region, err := c.TranslateRegion(filters.Region)
+		if err != nil {
+			return nil, err
+		}
+		regions = []string{region}
+	}
+
+	var offers []providers.GPUOffer
+	for _, region := range regions {
+		if !providers.CountryAllowed(regionCountries[region], filters.Countries) {
+			continue
+		}
+		for _, gpuType := range regionGPUTypes(region) {
+			offers = append(offers, instanceOffers(instanceTypes[gpuType], region)...)
+		}
+	}
+
+	return filterOffers(offers, filters), nil
+}
+
+// GetNormalizedPricing returns on-demand pricing for a GPU type in a region
+func (c *Client) GetNormalizedPricing(ctx context.Context, gpuType, region string) (*providers.NormalizedPricing, error) {
+	instanceType, err := lookupInstanceType(gpuType)
+	if err != nil {
+		return nil, err
+	}
+
+	if region == "" {
+		region = c.defaultRegion
+	}
+	awsRegion, err := c.TranslateRegion(region)
+	if err != nil {
+		return nil, err
+	}
+
+	price := instanceType.HourlyPrice * regionalPriceMultiplier(awsRegion)
+	return &providers.NormalizedPricing{
+		PricePerHour:   price,
+		PricePerSecond: price / 3600,
+		Currency:       "USD",
+		BillingModel:   providers.BillingPerSecond,
+		LastUpdated:    time.Now(),
+		ProviderSpecific: map[string]interface{}{
+			"instanceType": instanceType.Name,
+			"gpuCount":     instanceType.GPUCount,
+		},
+	}, nil
+}
+
+// RenderUserData validates the Talos machine config fits in EC2 user data
+func (c *Client) RenderUserData(machineConfig string) (string, error) {
+	if len(machineConfig) > maxUserDataBytes {
+		return "", fmt.Errorf("machine config is %d bytes, exceeding the EC2 user data limit of %d bytes", len(machineConfig), maxUserDataBytes)
+	}
+	return machineConfig, nil
+}
+
+// LaunchInstance launches an EC2 instance for the requested GPU type. It returns as soon as
+// EC2 accepts the launch, so the instance is usually still pending; its state is tracked
+// through GetInstanceStatus rather than waited on while holding the launch slot.
+func (c *Client) LaunchInstance(ctx context.Context, req *providers.LaunchRequest) (*providers.GPUInstance, error) {
+	if len(req.DataDisks) > 0 {
+		return nil, fmt.Errorf("aws does not support attaching data disks at launch; use AttachDataDisk once the instance is running")
+	}
+
+	instanceType, err := lookupInstanceTypeForGPUs(req.GPUType, req.RequestedGPUs())
+	if err != nil {
+		return nil, err
+	}
+
+	region := c.defaultRegion
+	if req.Region != "" {
+		if region, err = c.TranslateRegion(req.Region); err != nil {
+			return nil, err
+		}
+	}
+	client := c.ec2For(region)
+
+	// A size of 0 keeps the root volume size of the AMI
+	rootVolumeGiB, err := req.BootDiskSize(0, minRootVolumeGiB)
+	if err != nil {
+		return nil, err
+	}
+
+	imageID, err := c.resolveImage(ctx, client, req)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &ec2.RunInstancesInput{
+		ImageId:      aws.String(imageID),
+		InstanceType: types.InstanceType(instanceType.Name),
+		MinCount:     aws.Int32(1),
+		MaxCount:     aws.Int32(1),
+		TagSpecifications: []types.TagSpecification{
+			{ResourceType: types.ResourceTypeInstance, Tags: buildTags(req)},
+		},
+	}
+	if req.UserData != "" {
+		input.UserData = aws.String(base64.StdEncoding.EncodeToString([]byte(req.UserData)))
+	}
+	if req.SpotInstance {
+		input.InstanceMarketOptions = spotMarketOptions(req.MaxPrice)
+	}
+	if rootVolumeGiB > 0 {
+		input.BlockDeviceMappings = []types.BlockDeviceMapping{{
+			DeviceName: aws.String(rootDeviceName),
+			Ebs: &types.EbsBlockDevice{
+				VolumeSize:          aws.Int32(int32(rootVolumeGiB)),
+				VolumeType:          types.VolumeTypeGp3,
+				DeleteOnTermination: aws.Bool(true),
+			},
+		}}
+	}
+
+	output, err := client.RunInstances(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to launch instance: %w", apiError(err))
+	}
+	if len(output.Instances) == 0 {
+		return nil, fmt.Errorf("failed to launch instance: no instance returned")
+	}
+	instance := output.Instances[0]
+	awsInstanceID := aws.ToString(instance.InstanceId)
+
+	return &providers.GPUInstance{
+		ID:        formatInstanceID(region, awsInstanceID),
+		PublicIP:  aws.ToString(instance.PublicIpAddress),
+		PrivateIP: aws.ToString(instance.PrivateIpAddress),
+		Status:    mapInstanceState(instance.State),
+		CreatedAt: aws.ToTime(instance.LaunchTime),
+		IsSpot:    instance.InstanceLifecycle == types.InstanceLifecycleTypeSpot,
+	}, nil
+}
+
+// TerminateInstance terminates an instance. Instances that no longer exist are treated as terminated.
+func (c *Client) TerminateInstance(ctx context.Context, instanceID string) error {
+	region, awsInstanceID, err := c.parseInstanceID(instanceID)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.ec2For(region).TerminateInstances(ctx, &ec2.TerminateInstancesInput{
+		InstanceIds: []string{awsInstanceID},
+	})
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("failed to terminate instance %s: %w", awsInstanceID, apiError(err))
+	}
+	return nil
+}
+
+// GetInstanceStatus returns the current status of an instance
+func (c *Client) GetInstanceStatus(ctx context.Context, instanceID string) (*providers.InstanceStatus, error) {
+	region, awsInstanceID, err := c.parseInstanceID(instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := c.ec2For(region).DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: []string{awsInstanceID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance %s: %w", awsInstanceID, apiError(err))
+	}
+
+	instance := firstInstance(output)
+	if instance == nil {
+		return nil, fmt.Errorf("instance %s %w", awsInstanceID, providers.ErrNotFound)
+	}
+
+	status := &providers.InstanceStatus{
+		State:     mapInstanceState(instance.State),
+		PublicIP:  aws.ToString(instance.PublicIpAddress),
+		PrivateIP: aws.ToString(instance.PrivateIpAddress),
+		UpdatedAt: time.Now(),
+	}
+	if instance.StateReason != nil {
+		status.Message = aws.ToString(instance.StateReason.Message)
+	}
+	return status, nil
+}
+
+// AttachDataDisk attaches an existing EBS volume to a running instance
+func (c *Client) AttachDataDisk(ctx context.Context, instanceID string, disk providers.DataDisk) error {
+	if disk.ReadOnly {
+		return fmt.Errorf("aws does not support attaching EBS volume %s read-only", disk.VolumeID)
+	}
+
+	region, awsInstanceID, err := c.parseInstanceID(instanceID)
+	if err != nil {
+		return err
+	}
+
+	device := disk.DeviceName
+	if device == "" {
+		device = "/dev/sdf"
+	}
+
+	_, err = c.ec2For(region).AttachVolume(ctx, &ec2.AttachVolumeInput{
+		InstanceId: aws.String(awsInstanceID),
+		VolumeId:   aws.String(disk.VolumeID),
+		Device:     aws.String(device),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to attach volume %s: %w", disk.VolumeID, apiError(err))
+	}
+	return nil
+}
+
+// DetachDataDisk detaches an EBS volume from an instance without deleting it
+func (c *Client) DetachDataDisk(ctx context.Context, instanceID, volumeID string) error {
+	region, awsInstanceID, err := c.parseInstanceID(instanceID)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.ec2For(region).DetachVolume(ctx, &ec2.DetachVolumeInput{
+		InstanceId: aws.String(awsInstanceID),
+		VolumeId:   aws.String(volumeID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to detach volume %s: %w", volumeID, apiError(err))
+	}
+	return nil
+}
+
+// UpdateInstanceTags sets and removes tags on a running instance
+func (c *Client) UpdateInstanceTags(ctx context.Context, instanceID string, set map[string]string, remove []string) error {
+	region, awsInstanceID, err := c.parseInstanceID(instanceID)
+	if err != nil {
+		return err
+	}
+	client := c.ec2For(region)
+
+	if len(set) > 0 {
+		_, err := client.CreateTags(ctx, &ec2.CreateTagsInput{
+			Resources: []string{awsInstanceID},
+			Tags:      toTags(set),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set tags on instance %s: %w", awsInstanceID, apiError(err))
+		}
+	}
+
+	if len(remove) > 0 {
+		tags := make([]types.Tag, 0, len(remove))
+		for _, key := range remove {
+			tags = append(tags, types.Tag{Key: aws.String(key)})
+		}
+		_, err := client.DeleteTags(ctx, &ec2.DeleteTagsInput{
+			Resources: []string{awsInstanceID},
+			Tags:      tags,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to remove tags from instance %s: %w", awsInstanceID, apiError(err))
+		}
+	}
+
+	return nil
+}
+
+// MissingInstanceTags returns the wanted tags the instance lacks or holds a different value for.
+// Keys in the reserved aws: namespace are never set, so they are not reported.
+func (c *Client) MissingInstanceTags(ctx context.Context, instanceID string, want map[string]string) (map[string]string, error) {
+	region, awsInstanceID, err := c.parseInstanceID(instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := c.ec2For(region).DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: []string{awsInstanceID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance %s: %w", awsInstanceID, apiError(err))
+	}
+	instance := firstInstance(output)
+	if instance == nil {
+		return nil, fmt.Errorf("instance %s %w", awsInstanceID, providers.ErrNotFound)
+	}
+
+	actual := make(map[string]string, len(instance.Tags))
+	for _, tag := range instance.Tags {
+		actual[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	settable := make(map[string]string, len(want))
+	for _, tag := range toTags(want) {
+		settable[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return providers.MissingTags(actual, settable, nil), nil
+}
+
+// ListManagedInstances returns the instances in a region carrying the node pool tag every
+// launch sets. An empty region lists the default region.
+func (c *Client) ListManagedInstances(ctx context.Context, region string) ([]providers.ManagedInstance, error) {
+	if region == "" {
+		region = c.defaultRegion
+	} else {
+		var err error
+		if region, err = c.TranslateRegion(region); err != nil {
+			return nil, err
+		}
+	}
+
+	input := &ec2.DescribeInstancesInput{Filters: []types.Filter{
+		{Name: aws.String("tag-key"), Values: []string{v1.NodeLabelNodePool}},
+		{Name: aws.String("instance-state-name"), Values: []string{"pending", "running", "stopping", "stopped"}},
+	}}
+	client := c.ec2For(region)
+
+	var instances []providers.ManagedInstance
+	for {
+		output, err := client.DescribeInstances(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list instances in %s: %w", region, apiError(err))
+		}
+		for _, reservation := range output.Reservations {
+			for _, instance := range reservation.Instances {
+				instances = append(instances, providers.ManagedInstance{
+					GPUInstance: providers.GPUInstance{
+						ID:        formatInstanceID(region, aws.ToString(instance.InstanceId)),
+						PublicIP:  aws.ToString(instance.PublicIpAddress),
+						PrivateIP: aws.ToString(instance.PrivateIpAddress),
+						Status:    mapInstanceState(instance.State),
+						CreatedAt: aws.ToTime(instance.LaunchTime),
+						IsSpot:    instance.InstanceLifecycle == types.InstanceLifecycleTypeSpot,
+					},
+					NodePool: tagValue(instance.Tags, v1.NodeLabelNodePool),
+				})
+			}
+		}
+		if aws.ToString(output.NextToken) == "" {
+			return instances, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+// TranslateGPUType translates a standard GPU type to the EC2 instance type that provides it
+func (c *Client) TranslateGPUType(standard string) (string, error) {
+	instanceType, err := lookupInstanceType(standard)
+	if err != nil {
+		return "", err
+	}
+	return instanceType.Name, nil
+}
+
+// TranslateRegion translates standard regions to AWS regions. AWS region names are used directly.
+func (c *Client) TranslateRegion(standard string) (string, error) {
+	if region, exists := standardRegions[standard]; exists {
+		return region, nil
+	}
+	if _, exists := regionCountries[standard]; exists {
+		return standard, nil
+	}
+	return "", fmt.Errorf("unsupported region: %s", standard)
+}
+
+// RegionCountry returns the country an AWS region is in
+func (c *Client) RegionCountry(region string) (string, bool) {
+	country, ok := regionCountries[region]
+	return country, ok
+}
+
+// resolveImage returns the AMI to launch: the requested AMI ID, or the newest official Talos AMI
+func (c *Client) resolveImage(ctx context.Context, client ec2API, req *providers.LaunchRequest) (string, error) {
+	if strings.HasPrefix(req.Image, "ami-") {
+		return req.Image, nil
+	}
+
+	output, err := client.DescribeImages(ctx, &ec2.DescribeImagesInput{
+		Owners: []string{talosImageOwner},
+		Filters: []types.Filter{
+			{Name: aws.String("name"), Values: []string{talosImageNamePattern(req)}},
+			{Name: aws.String("architecture"), Values: []string{"x86_64"}},
+			{Name: aws.String("state"), Values: []string{"available"}},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to find Talos AMI: %w", apiError(err))
+	}
+	if len(output.Images) == 0 {
+		return "", fmt.Errorf("no Talos AMI found matching %s", talosImageNamePattern(req))
+	}
+
+	// CreationDate is ISO 8601, so the newest image sorts last
+	images := output.Images
+	sort.Slice(images, func(i, j int) bool {
+		return aws.ToString(images[i].CreationDate) < aws.ToString(images[j].CreationDate)
+	})
+	return aws.ToString(images[len(images)-1].ImageId), nil
+}
+
+// parseInstanceID splits a "region/instance-id" ID. IDs without a region use the default region.
+func (c *Client) parseInstanceID(instanceID string) (region, awsInstanceID string, err error) {
+	if instanceID == "" {
+		return "", "", fmt.Errorf("instance ID is required")
+	}
+	if region, awsInstanceID, found := strings.Cut(instanceID, "/"); found {
+		return region, awsInstanceID, nil
+	}
+	return c.defaultRegion, instanceID, nil
+}
+
+// formatInstanceID records the region with the EC2 instance ID, as EC2 APIs are regional
+func formatInstanceID(region, awsInstanceID string) string {
+	return region + "/" + awsInstanceID
+}
+
+// spotMarketOptions requests a one-time spot instance, capped at maxPrice per hour if set
+func spotMarketOptions(maxPrice float64) *types.InstanceMarketOptionsRequest {
+	options := &types.SpotMarketOptions{
+		SpotInstanceType:             types.SpotInstanceTypeOneTime,
+		InstanceInterruptionBehavior: types.InstanceInterruptionBehaviorTerminate,
+	}
+	if maxPrice > 0 {
+		options.MaxPrice = aws.String(fmt.Sprintf("%.4f", maxPrice))
+	}
+	return &types.InstanceMarketOptionsRequest{
+		MarketType:  types.MarketTypeSpot,
+		SpotOptions: options,
+	}
+}
+
+// buildTags returns the instance tags for a launch: its labels, cost-allocation tags and a Name
+func buildTags(req *providers.LaunchRequest) []types.Tag {
+	tags := make(map[string]string, len(req.Labels)+len(req.Tags)+1)
+	for key, value := range req.Labels {
+		tags[key] = value
+	}
+	for key, value := range req.Tags {
+		tags[key] = value
+	}
+	if _, exists := tags["Name"]; !exists {
+		tags["Name"] = "tgp-" + strings.ToLower(req.GPUType)
+	}
+	return toTags(tags)
+}
+
+// toTags converts a map to EC2 tags in a stable order, skipping keys reserved by AWS
+func toTags(values map[string]string) []types.Tag {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if strings.HasPrefix(strings.ToLower(key), "aws:") {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	tags := make([]types.Tag, 0, len(keys))
+	for _, key := range keys {
+		tags = append(tags, types.Tag{Key: aws.String(key), Value: aws.String(values[key])})
+	}
+	return tags
+}
+
+// tagValue returns the value of the tag with the key, or "" when it is not set
+func tagValue(tags []types.Tag, key string) string {
+	for _, tag := range tags {
+		if aws.ToString(tag.Key) == key {
+			return aws.ToString(tag.Value)
+		}
+	}
+	return ""
+}
+
+// firstInstance returns the first instance in a DescribeInstances response
+func firstInstance(output *ec2.DescribeInstancesOutput) *types.Instance {
+	if output == nil {
+		return nil
+	}
+	for _, reservation := range output.Reservations {
+		if len(reservation.Instances) > 0 {
+			return &reservation.Instances[0]
+		}
+	}
+	return nil
+}
+
+// mapInstanceState translates an EC2 instance state to the standard instance state
+func mapInstanceState(state *types.InstanceState) providers.InstanceState {
+	if state == nil {
+		return providers.InstanceStateUnknown
+	}
+	switch state.Name {
+	case types.InstanceStateNamePending:
+		return providers.InstanceStatePending
+	case types.InstanceStateNameRunning:
+		return providers.InstanceStateRunning
+	case types.InstanceStateNameShuttingDown, types.InstanceStateNameStopping:
+		return providers.InstanceStateTerminating
+	case types.InstanceStateNameTerminated, types.InstanceStateNameStopped:
+		return providers.InstanceStateTerminated
+	default:
+		return providers.InstanceStateUnknown
+	}
+}
+
+// isNotFound reports whether an EC2 API error means the instance does not exist
+func isNotFound(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == "InvalidInstanceID.NotFound"
+}
+
+// errorKinds maps EC2 API error codes to provider error kinds
+var errorKinds = map[string]error{
+	"RequestLimitExceeded":                 providers.ErrRateLimited,
+	"Throttling":                           providers.ErrRateLimited,
+	"AuthFailure":                          providers.ErrUnauthorized,
+	"UnauthorizedOperation":                providers.ErrUnauthorized,
+	"InvalidClientTokenId":                 providers.ErrUnauthorized,
+	"SignatureDoesNotMatch":                providers.ErrUnauthorized,
+	"OptInRequired":                        providers.ErrBilling,
+	"PendingVerification":                  providers.ErrBilling,
+	"Blocked":                              providers.ErrBilling,
+	"InsufficientInstanceCapacity":         providers.ErrNoCapacity,
+	"InsufficientHostCapacity":             providers.ErrNoCapacity,
+	"InsufficientReservedInstanceCapacity": providers.ErrNoCapacity,
+	"InstanceLimitExceeded":                providers.ErrNoCapacity,
+	"VcpuLimitExceeded":                    providers.ErrNoCapacity,
+	"MaxSpotInstanceCountExceeded":         providers.ErrNoCapacity,
+}
+
+// apiError classifies an EC2 API error by its error code, falling back to its HTTP status
+func apiError(err error) error {
+	var kind error
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		kind = errorKinds[apiErr.ErrorCode()]
+		if kind == nil && strings.HasSuffix(apiErr.ErrorCode(), ".NotFound") {
+			kind = providers.ErrNotFound
+		}
+	}
+
+	statusCode := 0
+	var respErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &respErr) {
+		statusCode = respErr.HTTPStatusCode()
+	}
+	return providers.NewAPIError(ProviderName, kind, statusCode, err)
+}
@@ -0,0 +1,277 @@
+package aws
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+const (
+	// talosImageOwner is the AWS account Sidero Labs publishes official Talos AMIs from
+	talosImageOwner = "540036508848"
+
+	// spotPriceFactor approximates the spot discount against on-demand pricing
+	spotPriceFactor = 0.35
+)
+
+// InstanceType describes the EC2 instance type used for a GPU type
+type InstanceType struct {
+	Name         string
+	GPUType      string
+	GPUCount     int
+	VCPUs        int
+	GPUMemoryGiB int64
+	// HourlyPrice is the approximate us-east-1 on-demand price in USD.
+	// These should ideally come from the AWS Price List API.
+	HourlyPrice float64
+}
+
+// instanceTypes maps standard GPU types to the smallest EC2 instance type providing them
+var instanceTypes = map[string]InstanceType{
+	"NVIDIA_T4":        {Name: "g4dn.xlarge", GPUType: "NVIDIA_T4", GPUCount: 1, VCPUs: 4, GPUMemoryGiB: 16, HourlyPrice: 0.526},
+	"NVIDIA_A10G":      {Name: "g5.xlarge", GPUType: "NVIDIA_A10G", GPUCount: 1, VCPUs: 4, GPUMemoryGiB: 24, HourlyPrice: 1.006},
+	"NVIDIA_L4":        {Name: "g6.xlarge", GPUType: "NVIDIA_L4", GPUCount: 1, VCPUs: 4, GPUMemoryGiB: 24, HourlyPrice: 0.805},
+	"NVIDIA_L40S":      {Name: "g6e.xlarge", GPUType: "NVIDIA_L40S", GPUCount: 1, VCPUs: 4, GPUMemoryGiB: 48, HourlyPrice: 1.861},
+	"NVIDIA_V100":      {Name: "p3.2xlarge", GPUType: "NVIDIA_V100", GPUCount: 1, VCPUs: 8, GPUMemoryGiB: 16, HourlyPrice: 3.06},
+	"NVIDIA_A100":      {Name: "p4d.24xlarge", GPUType: "NVIDIA_A100", GPUCount: 8, VCPUs: 96, GPUMemoryGiB: 40, HourlyPrice: 32.773},
+	"NVIDIA_A100_80GB": {Name: "p4de.24xlarge", GPUType: "NVIDIA_A100_80GB", GPUCount: 8, VCPUs: 96, GPUMemoryGiB: 80, HourlyPrice: 40.966},
+	"NVIDIA_H100":      {Name: "p5.48xlarge", GPUType: "NVIDIA_H100", GPUCount: 8, VCPUs: 192, GPUMemoryGiB: 80, HourlyPrice: 55.04},
+}
+
+// multiGPUInstanceTypes lists the larger instance types of each GPU type, by increasing GPU count
+var multiGPUInstanceTypes = map[string][]InstanceType{
+	"NVIDIA_T4": {
+		{Name: "g4dn.12xlarge", GPUType: "NVIDIA_T4", GPUCount: 4, VCPUs: 48, GPUMemoryGiB: 16, HourlyPrice: 3.912},
+	},
+	"NVIDIA_A10G": {
+		{Name: "g5.12xlarge", GPUType: "NVIDIA_A10G", GPUCount: 4, VCPUs: 48, GPUMemoryGiB: 24, HourlyPrice: 5.672},
+		{Name: "g5.48xlarge", GPUType: "NVIDIA_A10G", GPUCount: 8, VCPUs: 192, GPUMemoryGiB: 24, HourlyPrice: 16.288},
+	},
+	"NVIDIA_L4": {
+		{Name: "g6.12xlarge", GPUType: "NVIDIA_L4", GPUCount: 4, VCPUs: 48, GPUMemoryGiB: 24, HourlyPrice: 4.602},
+		{Name: "g6.48xlarge", GPUType: "NVIDIA_L4", GPUCount: 8, VCPUs: 192, GPUMemoryGiB: 24, HourlyPrice: 13.35},
+	},
+	"NVIDIA_L40S": {
+		{Name: "g6e.12xlarge", GPUType: "NVIDIA_L40S", GPUCount: 4, VCPUs: 48, GPUMemoryGiB: 48, HourlyPrice: 10.493},
+		{Name: "g6e.48xlarge", GPUType: "NVIDIA_L40S", GPUCount: 8, VCPUs: 192, GPUMemoryGiB: 48, HourlyPrice: 30.131},
+	},
+	"NVIDIA_V100": {
+		{Name: "p3.8xlarge", GPUType: "NVIDIA_V100", GPUCount: 4, VCPUs: 32, GPUMemoryGiB: 16, HourlyPrice: 12.24},
+		{Name: "p3.16xlarge", GPUType: "NVIDIA_V100", GPUCount: 8, VCPUs: 64, GPUMemoryGiB: 16, HourlyPrice: 24.48},
+	},
+}
+
+// gpuTypeAliases maps the short and alternative standard GPU type names to instanceTypes keys
+var gpuTypeAliases = map[string]string{
+	"T4":               "NVIDIA_T4",
+	"A10G":             "NVIDIA_A10G",
+	"L4":               "NVIDIA_L4",
+	"L40S":             "NVIDIA_L40S",
+	"V100":             "NVIDIA_V100",
+	"A100":             "NVIDIA_A100",
+	"A100-80GB":        "NVIDIA_A100_80GB",
+	"A100_80GB":        "NVIDIA_A100_80GB",
+	"H100":             "NVIDIA_H100",
+	"NVIDIA_H100_80GB": "NVIDIA_H100",
+}
+
+// lookupInstanceType returns the EC2 instance type for a standard GPU type
+func lookupInstanceType(gpuType string) (InstanceType, error) {
+	key := strings.ToUpper(gpuType)
+	if alias, exists := gpuTypeAliases[key]; exists {
+		key = alias
+	}
+	instanceType, exists := instanceTypes[key]
+	if !exists {
+		return InstanceType{}, fmt.Errorf("unsupported GPU type: %s", gpuType)
+	}
+	return instanceType, nil
+}
+
+// lookupInstanceTypeForGPUs returns the smallest EC2 instance type with at least count GPUs
+// of a standard GPU type
+func lookupInstanceTypeForGPUs(gpuType string, count int) (InstanceType, error) {
+	instanceType, err := lookupInstanceType(gpuType)
+	if err != nil || instanceType.GPUCount >= count {
+		return instanceType, err
+	}
+
+	for _, larger := range multiGPUInstanceTypes[instanceType.GPUType] {
+		if larger.GPUCount >= count {
+			return larger, nil
+		}
+		instanceType = larger
+	}
+	return InstanceType{}, fmt.Errorf("no instance type offers %d %s GPUs, the largest is %s with %d",
+		count, gpuType, instanceType.Name, instanceType.GPUCount)
+}
+
+// supportedGPUTypes returns the standard GPU types AWS offers, sorted
+func supportedGPUTypes() []string {
+	gpuTypes := make([]string, 0, len(instanceTypes))
+	for gpuType := range instanceTypes {
+		gpuTypes = append(gpuTypes, gpuType)
+	}
+	sort.Strings(gpuTypes)
+	return gpuTypes
+}
+
+// standardRegions maps standard regions to AWS regions
+var standardRegions = map[string]string{
+	providers.RegionUSEast:      "us-east-1",
+	providers.RegionUSWest:      "us-west-2",
+	providers.RegionEUCentral:   "eu-central-1",
+	providers.RegionAsiaPacific: "ap-northeast-1",
+}
+
+// regionCountries maps AWS regions to the ISO 3166-1 alpha-2 code of the country they are in
+var regionCountries = map[string]string{
+	"us-east-1":      "US",
+	"us-east-2":      "US",
+	"us-west-2":      "US",
+	"ca-central-1":   "CA",
+	"eu-west-1":      "IE",
+	"eu-west-2":      "GB",
+	"eu-central-1":   "DE",
+	"eu-north-1":     "SE",
+	"ap-northeast-1": "JP",
+	"ap-northeast-2": "KR",
+	"ap-south-1":     "IN",
+	"ap-southeast-2": "AU",
+}
+
+// p-family instance types are only offered in a subset of regions
+var largeInstanceRegions = map[string]bool{
+	"us-east-1":      true,
+	"us-east-2":      true,
+	"us-west-2":      true,
+	"eu-west-1":      true,
+	"ap-northeast-1": true,
+}
+
+// supportedRegions returns the AWS regions searched for offers, sorted
+func supportedRegions() []string {
+	regions := make([]string, 0, len(regionCountries))
+	for region := range regionCountries {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+// regionGPUTypes returns the standard GPU types offered in a region, sorted
+func regionGPUTypes(region string) []string {
+	var gpuTypes []string
+	for _, gpuType := range supportedGPUTypes() {
+		if strings.HasPrefix(instanceTypes[gpuType].Name, "p") && !largeInstanceRegions[region] {
+			continue
+		}
+		gpuTypes = append(gpuTypes, gpuType)
+	}
+	return gpuTypes
+}
+
+// regionalPriceMultiplier returns the approximate price of a region relative to us-east-1
+func regionalPriceMultiplier(region string) float64 {
+	switch {
+	case region == "us-east-1", region == "us-east-2", region == "us-west-2":
+		return 1.0
+	case strings.HasPrefix(region, "ca-"), strings.HasPrefix(region, "eu-north-"):
+		return 1.05
+	case strings.HasPrefix(region, "eu-"):
+		return 1.12
+	case strings.HasPrefix(region, "ap-"):
+		return 1.25
+	default:
+		return 1.1
+	}
+}
+
+// instanceOffers returns the on-demand and spot offers for an instance type in a region
+func instanceOffers(instanceType InstanceType, region string) []providers.GPUOffer {
+	price := instanceType.HourlyPrice * regionalPriceMultiplier(region)
+	spotPrice := price * spotPriceFactor
+
+	offer := providers.GPUOffer{
+		ID:          fmt.Sprintf("%s-%s", region, instanceType.Name),
+		GPUType:     instanceType.GPUType,
+		GPUCount:    instanceType.GPUCount,
+		Region:      region,
+		HourlyPrice: price,
+		SpotPrice:   spotPrice,
+		GPUMemory:   instanceType.GPUMemoryGiB,
+		Available:   true,
+		Provider:    ProviderName,
+		Verified:    true,
+		Country:     regionCountries[region],
+		VCPUs:       instanceType.VCPUs,
+	}
+
+	spot := offer
+	spot.ID += "-spot"
+	spot.HourlyPrice = spotPrice
+	spot.IsSpot = true
+
+	return []providers.GPUOffer{offer, spot}
+}
+
+// filterOffers applies the filters to offers
+func filterOffers(offers []providers.GPUOffer, filters *providers.GPUFilters) []providers.GPUOffer {
+	var filtered []providers.GPUOffer
+
+	for _, offer := range offers {
+		if filters.GPUType != "" {
+			instanceType, err := lookupInstanceType(filters.GPUType)
+			if err != nil || instanceType.GPUType != offer.GPUType {
+				continue
+			}
+		}
+
+		if !providers.CountryAllowed(offer.Country, filters.Countries) {
+			continue
+		}
+
+		if filters.MaxPrice > 0 && offer.HourlyPrice > filters.MaxPrice {
+			continue
+		}
+
+		if filters.MinMemory > 0 && offer.Memory < filters.MinMemory {
+			continue
+		}
+
+		if filters.SpotOnly && !offer.IsSpot {
+			continue
+		}
+
+		if filters.OnDemandOnly && offer.IsSpot {
+			continue
+		}
+
+		if !providers.VCPUsPerGPUAllowed(offer, filters.MinVCPUPerGPU) {
+			continue
+		}
+
+		if !providers.GPUMemoryAllowed(offer, filters.MinGPUMemoryGiB) {
+			continue
+		}
+
+		filtered = append(filtered, offer)
+	}
+
+	return filtered
+}
+
+// talosImageNamePattern returns the AMI name pattern for the requested Talos version,
+// taken from the Talos installer image tag, or any version if none is set
+func talosImageNamePattern(req *providers.LaunchRequest) string {
+	version := "v*"
+	if req.TalosConfig != nil {
+		image := req.TalosConfig.Image
+		if i := strings.LastIndex(image, ":"); i >= 0 && strings.HasPrefix(image[i+1:], "v") {
+			version = image[i+1:]
+		}
+	}
+	return fmt.Sprintf("talos-%s-*-amd64", version)
+}
@@ -0,0 +1,453 @@
+package azure
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
+
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+const (
+	testSubnet      = "/subscriptions/sub/resourceGroups/net/providers/Microsoft.Network/virtualNetworks/vnet/subnets/default"
+	testImage       = "/subscriptions/sub/resourceGroups/images/providers/Microsoft.Compute/images/talos"
+	testCredentials = `{"tenantId":"tenant","clientId":"client","clientSecret":"secret","subscriptionId":"sub",
+		"subnets":{"eastus":"` + testSubnet + `"},"imageId":"` + testImage + `"}`
+)
+
+// fakeVMAPI records requests and returns canned responses
+type fakeVMAPI struct {
+	created     armcompute.VirtualMachine
+	deleteErr   error
+	deleted     []string
+	vm          armcompute.VirtualMachine
+	updated     armcompute.VirtualMachineUpdate
+	attachments []armcompute.AttachDetachDataDisksRequest
+	listErr     error
+	listed      []string
+}
+
+func (f *fakeVMAPI) CreateVM(ctx context.Context, resourceGroup, name string, vm armcompute.VirtualMachine) (armcompute.VirtualMachine, error) {
+	f.created = vm
+	vm.Properties.NetworkProfile.NetworkInterfaces = []*armcompute.NetworkInterfaceReference{{ID: to.Ptr("/nic/" + name)}}
+	return vm, nil
+}
+
+func (f *fakeVMAPI) DeleteVM(ctx context.Context, resourceGroup, name string) error {
+	f.deleted = append(f.deleted, resourceGroup+"/"+name)
+	return f.deleteErr
+}
+
+func (f *fakeVMAPI) GetVM(ctx context.Context, resourceGroup, name string) (armcompute.VirtualMachine, error) {
+	return f.vm, nil
+}
+
+func (f *fakeVMAPI) UpdateVM(ctx context.Context, resourceGroup, name string, update armcompute.VirtualMachineUpdate) error {
+	f.updated = update
+	return nil
+}
+
+func (f *fakeVMAPI) AttachDetachDataDisks(ctx context.Context, resourceGroup, name string, request armcompute.AttachDetachDataDisksRequest) error {
+	f.attachments = append(f.attachments, request)
+	return nil
+}
+
+func (f *fakeVMAPI) ListVMsPage(ctx context.Context, resourceGroup string) error {
+	f.listed = append(f.listed, resourceGroup)
+	return f.listErr
+}
+
+func (f *fakeVMAPI) InterfaceAddresses(ctx context.Context, interfaceID string) (string, string, error) {
+	return "203.0.113.20", "10.1.0.4", nil
+}
+
+// newTestClient returns a client whose API calls go to fake
+func newTestClient(t *testing.T, fake *fakeVMAPI) *Client {
+	t.Helper()
+	client, err := NewClient(testCredentials)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	client.api = fake
+	return client
+}
+
+func TestNewClient(t *testing.T) {
+	client, err := NewClient(testCredentials)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	if client.servicePrincipal.ResourceGroup != DefaultResourceGroup {
+		t.Errorf("Expected default resource group %s, got %s", DefaultResourceGroup, client.servicePrincipal.ResourceGroup)
+	}
+
+	for _, invalid := range []string{"", "not-json", `{"tenantId":"t","clientId":"c","clientSecret":"s"}`} {
+		if _, err := NewClient(invalid); err == nil {
+			t.Errorf("Expected error for credentials %q", invalid)
+		}
+	}
+}
+
+func TestTranslateGPUType(t *testing.T) {
+	client := newTestClient(t, &fakeVMAPI{})
+
+	tests := map[string]string{
+		"V100":        "Standard_NC6s_v3",
+		"NVIDIA_A100": "Standard_ND96asr_v4",
+		"A100-80GB":   "Standard_ND96amsr_A100_v4",
+		"h100":        "Standard_ND96isr_H100_v5",
+		"MI25":        "Standard_NV32as_v4",
+	}
+	for gpuType, expected := range tests {
+		got, err := client.TranslateGPUType(gpuType)
+		if err != nil || got != expected {
+			t.Errorf("TranslateGPUType(%s) = %s, %v, expected %s", gpuType, got, err, expected)
+		}
+	}
+
+	if _, err := client.TranslateGPUType("RTX4090"); err == nil {
+		t.Error("Expected error for GPU type Azure does not offer")
+	}
+}
+
+func TestTranslateRegion(t *testing.T) {
+	client := newTestClient(t, &fakeVMAPI{})
+
+	tests := map[string]string{
+		providers.RegionUSEast: "eastus",
+		"West Europe":          "westeurope",
+		"japaneast":            "japaneast",
+	}
+	for standard, expected := range tests {
+		got, err := client.TranslateRegion(standard)
+		if err != nil || got != expected {
+			t.Errorf("TranslateRegion(%s) = %s, %v, expected %s", standard, got, err, expected)
+		}
+	}
+
+	if _, err := client.TranslateRegion("moon"); err == nil {
+		t.Error("Expected error for unknown region")
+	}
+}
+
+func TestListAvailableGPUs(t *testing.T) {
+	client := newTestClient(t, &fakeVMAPI{})
+
+	offers, err := client.ListAvailableGPUs(context.Background(), &providers.GPUFilters{GPUType: "V100"})
+	if err != nil {
+		t.Fatalf("ListAvailableGPUs failed: %v", err)
+	}
+	if len(offers) != 2 {
+		t.Fatalf("Expected pay-as-you-go and spot offers, got %d", len(offers))
+	}
+	for _, offer := range offers {
+		if offer.Region != "eastus" || offer.VCPUs != 6 || offer.Country != "US" {
+			t.Errorf("Unexpected offer: %+v", offer)
+		}
+	}
+
+	// Locations without a subnet cannot be launched in
+	offers, err = client.ListAvailableGPUs(context.Background(), &providers.GPUFilters{Region: "westeurope"})
+	if err != nil {
+		t.Fatalf("ListAvailableGPUs failed: %v", err)
+	}
+	if len(offers) != 0 {
+		t.Errorf("Expected no offers without a subnet, got %d", len(offers))
+	}
+
+	offers, err = client.ListAvailableGPUs(context.Background(), &providers.GPUFilters{MinVCPUPerGPU: 13})
+	if err != nil {
+		t.Fatalf("ListAvailableGPUs failed: %v", err)
+	}
+	for _, offer := range offers {
+		if offer.GPUType != "AMD_MI25" {
+			t.Errorf("Expected only MI25 offers with 13 vCPUs per GPU, got %s", offer.GPUType)
+		}
+	}
+}
+
+func TestLaunchInstance(t *testing.T) {
+	fake := &fakeVMAPI{}
+	client := newTestClient(t, fake)
+
+	instance, err := client.LaunchInstance(context.Background(), &providers.LaunchRequest{
+		GPUType:      "V100",
+		Region:       providers.RegionUSEast,
+		Image:        "talos",
+		UserData:     "machine: config",
+		Labels:       map[string]string{"tgp.io/nodepool": "pool"},
+		Tags:         map[string]string{"team": "ml"},
+		SpotInstance: true,
+		DataDisks:    []providers.DataDisk{{VolumeID: "/subscriptions/sub/disks/data"}},
+	})
+	if err != nil {
+		t.Fatalf("LaunchInstance failed: %v", err)
+	}
+
+	if !strings.HasPrefix(instance.ID, DefaultResourceGroup+"/tgp-") {
+		t.Errorf("Expected resource group qualified instance ID, got %s", instance.ID)
+	}
+	if instance.PublicIP != "203.0.113.20" || instance.PrivateIP != "10.1.0.4" || !instance.IsSpot {
+		t.Errorf("Unexpected instance: %+v", instance)
+	}
+
+	props := fake.created.Properties
+	if *fake.created.Location != "eastus" || *props.HardwareProfile.VMSize != "Standard_NC6s_v3" {
+		t.Errorf("Unexpected location or size: %s %s", *fake.created.Location, *props.HardwareProfile.VMSize)
+	}
+	if *props.StorageProfile.ImageReference.ID != testImage {
+		t.Errorf("Expected configured Talos image, got %v", props.StorageProfile.ImageReference)
+	}
+	if *props.Priority != armcompute.VirtualMachinePriorityTypesSpot || *props.BillingProfile.MaxPrice != -1 {
+		t.Errorf("Expected spot priority capped at pay-as-you-go, got %v %v", *props.Priority, *props.BillingProfile.MaxPrice)
+	}
+	customData, _ := base64.StdEncoding.DecodeString(*props.OSProfile.CustomData)
+	if string(customData) != "machine: config" {
+		t.Errorf("Expected base64 custom data, got %q", customData)
+	}
+	subnet := props.NetworkProfile.NetworkInterfaceConfigurations[0].Properties.IPConfigurations[0].Properties.Subnet
+	if *subnet.ID != testSubnet {
+		t.Errorf("Expected configured subnet, got %s", *subnet.ID)
+	}
+	if len(props.StorageProfile.DataDisks) != 1 || *props.StorageProfile.DataDisks[0].DeleteOption != armcompute.DiskDeleteOptionTypesDetach {
+		t.Errorf("Expected data disk kept on delete, got %v", props.StorageProfile.DataDisks)
+	}
+	if fake.created.Tags["tgp.io_nodepool"] == nil || *fake.created.Tags["team"] != "ml" {
+		t.Errorf("Unexpected tags: %v", fake.created.Tags)
+	}
+
+	if _, err := client.LaunchInstance(context.Background(), &providers.LaunchRequest{GPUType: "V100", Region: "westeurope"}); err == nil {
+		t.Error("Expected error for location without a subnet")
+	}
+}
+
+func TestLaunchInstanceOnDemand(t *testing.T) {
+	fake := &fakeVMAPI{}
+	client := newTestClient(t, fake)
+
+	if _, err := client.LaunchInstance(context.Background(), &providers.LaunchRequest{
+		GPUType: "MI25",
+		Image:   "siderolabs:talos:talos-x64:1.10.5",
+	}); err != nil {
+		t.Fatalf("LaunchInstance failed: %v", err)
+	}
+
+	props := fake.created.Properties
+	if props.Priority != nil || props.BillingProfile != nil {
+		t.Error("Expected pay-as-you-go VM")
+	}
+	if *props.StorageProfile.ImageReference.Publisher != "siderolabs" || *props.StorageProfile.ImageReference.Version != "1.10.5" {
+		t.Errorf("Expected marketplace image reference, got %v", props.StorageProfile.ImageReference)
+	}
+	if *props.StorageProfile.OSDisk.DiskSizeGB != 100 {
+		t.Errorf("Expected the default 100GiB OS disk, got %d", *props.StorageProfile.OSDisk.DiskSizeGB)
+	}
+
+	if _, err := client.LaunchInstance(context.Background(), &providers.LaunchRequest{GPUType: "MI25", BootDiskGiB: 20}); err == nil {
+		t.Error("Expected error for an OS disk below the minimum")
+	}
+}
+
+func TestLaunchInstanceMultiGPU(t *testing.T) {
+	fake := &fakeVMAPI{}
+	client := newTestClient(t, fake)
+
+	if _, err := client.LaunchInstance(context.Background(), &providers.LaunchRequest{
+		GPUType:  "V100",
+		GPUCount: 3,
+		Region:   providers.RegionUSEast,
+		Image:    "talos",
+	}); err != nil {
+		t.Fatalf("LaunchInstance failed: %v", err)
+	}
+	if size := *fake.created.Properties.HardwareProfile.VMSize; size != "Standard_NC24s_v3" {
+		t.Errorf("Expected the smallest V100 size with 3 GPUs, Standard_NC24s_v3, got %s", size)
+	}
+
+	if _, err := client.LaunchInstance(context.Background(), &providers.LaunchRequest{GPUType: "MI25", GPUCount: 2}); err == nil {
+		t.Error("Expected error when no MI25 size has 2 GPUs")
+	}
+}
+
+func TestTerminateInstance(t *testing.T) {
+	fake := &fakeVMAPI{}
+	client := newTestClient(t, fake)
+
+	if err := client.TerminateInstance(context.Background(), "rg/tgp-vm"); err != nil {
+		t.Fatalf("TerminateInstance failed: %v", err)
+	}
+	if len(fake.deleted) != 1 || fake.deleted[0] != "rg/tgp-vm" {
+		t.Errorf("Expected rg/tgp-vm to be deleted, got %v", fake.deleted)
+	}
+
+	fake.deleteErr = &azcore.ResponseError{StatusCode: http.StatusNotFound}
+	if err := client.TerminateInstance(context.Background(), "rg/gone"); err != nil {
+		t.Errorf("Expected missing VM to count as terminated, got %v", err)
+	}
+
+	fake.deleteErr = &azcore.ResponseError{StatusCode: http.StatusTooManyRequests}
+	if err := client.TerminateInstance(context.Background(), "rg/tgp-vm"); !errors.Is(err, providers.ErrRateLimited) {
+		t.Errorf("Expected a rate limited error, got %v", err)
+	}
+}
+
+func TestCheckCredentials(t *testing.T) {
+	fake := &fakeVMAPI{}
+	client := newTestClient(t, fake)
+
+	if err := client.CheckCredentials(context.Background()); err != nil {
+		t.Fatalf("CheckCredentials failed: %v", err)
+	}
+	if len(fake.listed) != 1 || fake.listed[0] != DefaultResourceGroup {
+		t.Errorf("Expected VMs in %s to be listed, got %v", DefaultResourceGroup, fake.listed)
+	}
+
+	fake.listErr = &azcore.ResponseError{StatusCode: http.StatusUnauthorized}
+	if err := client.CheckCredentials(context.Background()); !errors.Is(err, providers.ErrUnauthorized) {
+		t.Errorf("Expected an unauthorized error, got %v", err)
+	}
+}
+
+func TestGetInstanceStatus(t *testing.T) {
+	fake := &fakeVMAPI{vm: armcompute.VirtualMachine{Properties: &armcompute.VirtualMachineProperties{
+		ProvisioningState: to.Ptr("Succeeded"),
+		InstanceView: &armcompute.VirtualMachineInstanceView{Statuses: []*armcompute.InstanceViewStatus{
+			{Code: to.Ptr("ProvisioningState/succeeded")},
+			{Code: to.Ptr("PowerState/running")},
+		}},
+	}}}
+	client := newTestClient(t, fake)
+
+	status, err := client.GetInstanceStatus(context.Background(), "rg/tgp-vm")
+	if err != nil {
+		t.Fatalf("GetInstanceStatus failed: %v", err)
+	}
+	if status.State != providers.InstanceStateRunning {
+		t.Errorf("Expected running, got %s", status.State)
+	}
+}
+
+func TestMapVMState(t *testing.T) {
+	vmWith := func(provisioningState, power string) armcompute.VirtualMachine {
+		return armcompute.VirtualMachine{Properties: &armcompute.VirtualMachineProperties{
+			ProvisioningState: to.Ptr(provisioningState),
+			InstanceView: &armcompute.VirtualMachineInstanceView{Statuses: []*armcompute.InstanceViewStatus{
+				{Code: to.Ptr("PowerState/" + power)},
+			}},
+		}}
+	}
+
+	tests := []struct {
+		vm       armcompute.VirtualMachine
+		expected providers.InstanceState
+	}{
+		{vmWith("Creating", "starting"), providers.InstanceStatePending},
+		{vmWith("Succeeded", "running"), providers.InstanceStateRunning},
+		{vmWith("Deleting", "running"), providers.InstanceStateTerminating},
+		{vmWith("Succeeded", "deallocated"), providers.InstanceStateTerminated},
+		{vmWith("Failed", "stopped"), providers.InstanceStateFailed},
+		{armcompute.VirtualMachine{}, providers.InstanceStateUnknown},
+	}
+	for _, tt := range tests {
+		if got := mapVMState(tt.vm); got != tt.expected {
+			t.Errorf("mapVMState() = %s, expected %s", got, tt.expected)
+		}
+	}
+}
+
+func TestUpdateInstanceTags(t *testing.T) {
+	fake := &fakeVMAPI{vm: armcompute.VirtualMachine{Tags: map[string]*string{
+		"keep": to.Ptr("yes"),
+		"old":  to.Ptr("value"),
+	}}}
+	client := newTestClient(t, fake)
+
+	err := client.UpdateInstanceTags(context.Background(), "rg/tgp-vm", map[string]string{"team/name": "ml"}, []string{"old"})
+	if err != nil {
+		t.Fatalf("UpdateInstanceTags failed: %v", err)
+	}
+
+	tags := fake.updated.Tags
+	if len(tags) != 2 || *tags["keep"] != "yes" || *tags["team_name"] != "ml" {
+		t.Errorf("Unexpected tags: %v", tags)
+	}
+}
+
+func TestMissingInstanceTags(t *testing.T) {
+	fake := &fakeVMAPI{vm: armcompute.VirtualMachine{Tags: map[string]*string{
+		"team_name": to.Ptr("ml"),
+		"env":       to.Ptr("dev"),
+	}}}
+	client := newTestClient(t, fake)
+
+	missing, err := client.MissingInstanceTags(context.Background(), "rg/tgp-vm",
+		map[string]string{"team/name": "ml", "env": "prod"})
+	if err != nil {
+		t.Fatalf("MissingInstanceTags failed: %v", err)
+	}
+	if len(missing) != 1 || missing["env"] != "prod" {
+		t.Errorf("MissingInstanceTags() = %v, want only the changed env tag", missing)
+	}
+}
+
+func TestDataDisks(t *testing.T) {
+	fake := &fakeVMAPI{}
+	client := newTestClient(t, fake)
+
+	if err := client.AttachDataDisk(context.Background(), "rg/tgp-vm", providers.DataDisk{VolumeID: "disk-1"}); err != nil {
+		t.Fatalf("AttachDataDisk failed: %v", err)
+	}
+	if err := client.DetachDataDisk(context.Background(), "rg/tgp-vm", "disk-1"); err != nil {
+		t.Fatalf("DetachDataDisk failed: %v", err)
+	}
+	if len(fake.attachments) != 2 ||
+		*fake.attachments[0].DataDisksToAttach[0].DiskID != "disk-1" ||
+		*fake.attachments[1].DataDisksToDetach[0].DiskID != "disk-1" {
+		t.Errorf("Unexpected attach/detach requests: %v", fake.attachments)
+	}
+
+	if err := client.AttachDataDisk(context.Background(), "rg/tgp-vm", providers.DataDisk{VolumeID: "disk-1", ReadOnly: true}); err == nil {
+		t.Error("Expected error for read-only disk")
+	}
+}
+
+func TestImageReference(t *testing.T) {
+	if ref, err := imageReference("/CommunityGalleries/talos/Images/talos-x64/Versions/latest"); err != nil || ref.CommunityGalleryImageID == nil {
+		t.Errorf("Expected community gallery reference, got %v, %v", ref, err)
+	}
+	if _, err := imageReference("talos"); err == nil {
+		t.Error("Expected error for non-Azure image")
+	}
+}
+
+func TestGenerateVMName(t *testing.T) {
+	name := generateVMName(&providers.LaunchRequest{Labels: map[string]string{"nodepool": "My_Pool"}})
+	if !strings.HasPrefix(name, "tgp-my-pool-") {
+		t.Errorf("Expected sanitized name, got %s", name)
+	}
+	if len(name) > 64 {
+		t.Errorf("Expected name of at most 64 characters, got %d", len(name))
+	}
+}
+
+func TestRenderUserData(t *testing.T) {
+	client := newTestClient(t, &fakeVMAPI{})
+
+	config := "version: v1alpha1\nmachine:\n  type: worker\n"
+	if rendered, err := providers.RenderUserData(client, config); err != nil || rendered != config {
+		t.Errorf("RenderUserData() = %q, %v, want the machine config unchanged", rendered, err)
+	}
+
+	// The limit applies once encoded, so a config under 64 KB can still be too large
+	oversized := strings.Repeat("a", maxCustomDataBytes*3/4+1)
+	if _, err := providers.RenderUserData(client, oversized); err == nil {
+		t.Error("Expected error for machine config exceeding the custom data limit")
+	}
+}
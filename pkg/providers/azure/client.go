@@ -0,0 +1,499 @@
+package azure
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
+	"github.com/go-logr/logr"
+
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+const (
+	ProviderName = "azure"
+
+	// DefaultResourceGroup is used when the credentials do not name a resource group
+	DefaultResourceGroup = "tgp-operator"
+
+	// maxCustomDataBytes is the Azure limit on custom data once base64-encoded
+	maxCustomDataBytes = 64 * 1024
+)
+
+// ServicePrincipal is the JSON structure of the Azure credentials secret
+type ServicePrincipal struct {
+	TenantID       string `json:"tenantId"`
+	ClientID       string `json:"clientId"`
+	ClientSecret   string `json:"clientSecret"`
+	SubscriptionID string `json:"subscriptionId"`
+
+	// ResourceGroup holds the VMs the operator creates
+	ResourceGroup string `json:"resourceGroup,omitempty"`
+	// Subnets maps each location to provision in to the ID of the subnet VMs join there
+	Subnets map[string]string `json:"subnets,omitempty"`
+	// ImageID is the Talos image used when a launch does not reference an Azure image
+	ImageID string `json:"imageId,omitempty"`
+}
+
+// Client implements the ProviderClient interface for Microsoft Azure
+type Client struct {
+	servicePrincipal ServicePrincipal
+	// httpClient replaces the SDK's default HTTP client when debug logging is enabled
+	httpClient *http.Client
+
+	mutex sync.Mutex
+	api   vmAPI
+}
+
+// NewClient creates a new Azure provider client from a service principal JSON blob
+func NewClient(credentialsJSON string) (*Client, error) {
+	var sp ServicePrincipal
+	if err := json.Unmarshal([]byte(credentialsJSON), &sp); err != nil {
+		return nil, fmt.Errorf("failed to parse Azure service principal JSON: %w", err)
+	}
+	if sp.TenantID == "" || sp.ClientID == "" || sp.ClientSecret == "" || sp.SubscriptionID == "" {
+		return nil, fmt.Errorf("azure service principal requires tenantId, clientId, clientSecret and subscriptionId")
+	}
+	if sp.ResourceGroup == "" {
+		sp.ResourceGroup = DefaultResourceGroup
+	}
+	return &Client{servicePrincipal: sp}, nil
+}
+
+// EnableDebugLogging logs every Azure Resource Manager request and response at debug level.
+// Token requests are not logged, as they carry the client secret.
+func (c *Client) EnableDebugLogging(log logr.Logger) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.httpClient = &http.Client{Transport: providers.NewDebugTransport(nil, log)}
+	c.api = nil
+}
+
+// vmAPI returns the Azure API client, creating it on first use
+func (c *Client) vmAPI() (vmAPI, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.api != nil {
+		return c.api, nil
+	}
+
+	sp := c.servicePrincipal
+	credential, err := azidentity.NewClientSecretCredential(sp.TenantID, sp.ClientID, sp.ClientSecret, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Azure credential: %w", err)
+	}
+
+	var options *arm.ClientOptions
+	if c.httpClient != nil {
+		options = &arm.ClientOptions{ClientOptions: policy.ClientOptions{Transport: c.httpClient}}
+	}
+
+	api, err := newARMVMAPI(sp.SubscriptionID, credential, options)
+	if err != nil {
+		return nil, err
+	}
+	c.api = api
+	return c.api, nil
+}
+
+// GetProviderInfo returns information about the Azure provider
+func (c *Client) GetProviderInfo() *providers.ProviderInfo {
+	return &providers.ProviderInfo{
+		Name:                  ProviderName,
+		APIVersion:            "2024-07-01",
+		SupportedRegions:      c.locations(),
+		SupportedGPUTypes:     supportedGPUTypes(),
+		SupportsSpotInstances: true,
+		SupportsMultiGPU:      true,
+		BillingGranularity:    providers.BillingPerMinute,
+		MinBillingPeriod:      time.Minute,
+		ReliabilityTier:       providers.ReliabilityTierEnterprise,
+	}
+}
+
+// GetRateLimits returns the rate limits for the Azure Resource Manager API
+func (c *Client) GetRateLimits() *providers.RateLimitInfo {
+	return &providers.RateLimitInfo{
+		RequestsPerSecond: 10,
+		RequestsPerMinute: 250,
+		BurstCapacity:     50,
+		BackoffStrategy:   "exponential",
+		ResetWindow:       time.Minute,
+	}
+}
+
+// CheckCredentials lists the VMs in the resource group, which fails when the service
+// principal cannot authenticate or has no access to it
+func (c *Client) CheckCredentials(ctx context.Context) error {
+	api, err := c.vmAPI()
+	if err != nil {
+		return err
+	}
+	if err := api.ListVMsPage(ctx, c.servicePrincipal.ResourceGroup); err != nil {
+		return fmt.Errorf("failed to list VMs in resource group %s: %w", c.servicePrincipal.ResourceGroup, apiError(err))
+	}
+	return nil
+}
+
+// locations returns the locations VMs can be launched in: those with a configured subnet, sorted
+func (c *Client) locations() []string {
+	locations := make([]string, 0, len(c.servicePrincipal.Subnets))
+	for location := range c.servicePrincipal.Subnets {
+		locations = append(locations, location)
+	}
+	sort.Strings(locations)
+	return locations
+}
+
+// ListAvailableGPUs returns Azure GPU VM sizes matching the filters in locations with a subnet
+func (c *Client) ListAvailableGPUs(ctx context.Context, filters *providers.GPUFilters) ([]providers.GPUOffer, error) {
+	if filters == nil {
+		filters = &providers.GPUFilters{}
+	}
+
+	locations := c.locations()
+	if filters.Region != "" {
+		location, err := c.TranslateRegion(filters.Region)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := c.servicePrincipal.Subnets[location]; !exists {
+			return nil, nil
+		}
+		locations = []string{location}
+	}
+
+	var offers []providers.GPUOffer
+	for _, location := range locations {
+		if !providers.CountryAllowed(regionCountries[location], filters.Countries) {
+			continue
+		}
+		for _, gpuType := range supportedGPUTypes() {
+			offers = append(offers, vmSizeOffers(vmSizes[gpuType], location)...)
+		}
+	}
+
+	return filterOffers(offers, filters), nil
+}
+
+// GetNormalizedPricing returns pay-as-you-go pricing for a GPU type in a location
+func (c *Client) GetNormalizedPricing(ctx context.Context, gpuType, region string) (*providers.NormalizedPricing, error) {
+	size, err := lookupVMSize(gpuType)
+	if err != nil {
+		return nil, err
+	}
+
+	location := "eastus"
+	if region != "" {
+		if location, err = c.TranslateRegion(region); err != nil {
+			return nil, err
+		}
+	}
+
+	price := size.HourlyPrice * regionalPriceMultiplier(location)
+	return &providers.NormalizedPricing{
+		PricePerHour:   price,
+		PricePerSecond: price / 3600,
+		Currency:       "USD",
+		BillingModel:   providers.BillingPerMinute,
+		LastUpdated:    time.Now(),
+		ProviderSpecific: map[string]interface{}{
+			"vmSize":   size.Name,
+			"series":   size.Series,
+			"gpuCount": size.GPUCount,
+		},
+	}, nil
+}
+
+// RenderUserData validates the Talos machine config fits in VM custom data
+func (c *Client) RenderUserData(machineConfig string) (string, error) {
+	if encoded := base64.StdEncoding.EncodedLen(len(machineConfig)); encoded > maxCustomDataBytes {
+		return "", fmt.Errorf("machine config is %d bytes base64-encoded, exceeding the Azure custom data limit of %d bytes", encoded, maxCustomDataBytes)
+	}
+	return machineConfig, nil
+}
+
+// LaunchInstance creates a VM for the requested GPU type and waits for it to be provisioned
+func (c *Client) LaunchInstance(ctx context.Context, req *providers.LaunchRequest) (*providers.GPUInstance, error) {
+	size, err := lookupVMSizeForGPUs(req.GPUType, req.RequestedGPUs())
+	if err != nil {
+		return nil, err
+	}
+
+	location, subnetID, err := c.launchLocation(req.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := c.launchImage(req.Image)
+	if err != nil {
+		return nil, err
+	}
+
+	osDiskGiB, err := req.BootDiskSize(defaultOSDiskGiB, minOSDiskGiB)
+	if err != nil {
+		return nil, err
+	}
+
+	api, err := c.vmAPI()
+	if err != nil {
+		return nil, err
+	}
+
+	resourceGroup := c.servicePrincipal.ResourceGroup
+	name := generateVMName(req)
+	vm, err := api.CreateVM(ctx, resourceGroup, name, buildVirtualMachine(name, location, subnetID, size, image, osDiskGiB, req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create VM %s: %w", name, apiError(err))
+	}
+
+	instance := &providers.GPUInstance{
+		ID:        formatInstanceID(resourceGroup, name),
+		Status:    providers.InstanceStateRunning,
+		CreatedAt: time.Now(),
+		IsSpot:    req.SpotInstance,
+	}
+	if vm.Properties != nil && vm.Properties.TimeCreated != nil {
+		instance.CreatedAt = *vm.Properties.TimeCreated
+	}
+	if nicID := primaryInterfaceID(vm); nicID != "" {
+		// Addresses are informational; the node joins the cluster over its own network
+		instance.PublicIP, instance.PrivateIP, _ = api.InterfaceAddresses(ctx, nicID)
+	}
+
+	return instance, nil
+}
+
+// launchLocation returns the location to launch in and the subnet VMs join there
+func (c *Client) launchLocation(region string) (string, string, error) {
+	if region == "" {
+		locations := c.locations()
+		if len(locations) == 0 {
+			return "", "", fmt.Errorf("azure credentials must configure a subnet for at least one location")
+		}
+		region = locations[0]
+	}
+
+	location, err := c.TranslateRegion(region)
+	if err != nil {
+		return "", "", err
+	}
+	subnetID, exists := c.servicePrincipal.Subnets[location]
+	if !exists {
+		return "", "", fmt.Errorf("no subnet configured for Azure location %s", location)
+	}
+	return location, subnetID, nil
+}
+
+// launchImage returns the image reference for a launch: the requested Azure image if it
+// references one, otherwise the configured Talos image
+func (c *Client) launchImage(image string) (*armcompute.ImageReference, error) {
+	if reference, err := imageReference(image); err == nil {
+		return reference, nil
+	}
+	if c.servicePrincipal.ImageID == "" {
+		return nil, fmt.Errorf("image %q is not an Azure image and no imageId is configured in the Azure credentials", image)
+	}
+	return imageReference(c.servicePrincipal.ImageID)
+}
+
+// TerminateInstance deletes a VM together with its OS disk, network interface and public IP.
+// VMs that no longer exist are treated as terminated.
+func (c *Client) TerminateInstance(ctx context.Context, instanceID string) error {
+	resourceGroup, name, err := c.parseInstanceID(instanceID)
+	if err != nil {
+		return err
+	}
+	api, err := c.vmAPI()
+	if err != nil {
+		return err
+	}
+
+	if err := api.DeleteVM(ctx, resourceGroup, name); err != nil && !isNotFound(err) {
+		return fmt.Errorf("failed to delete VM %s: %w", name, apiError(err))
+	}
+	return nil
+}
+
+// GetInstanceStatus returns the current status of a VM
+func (c *Client) GetInstanceStatus(ctx context.Context, instanceID string) (*providers.InstanceStatus, error) {
+	resourceGroup, name, err := c.parseInstanceID(instanceID)
+	if err != nil {
+		return nil, err
+	}
+	api, err := c.vmAPI()
+	if err != nil {
+		return nil, err
+	}
+
+	vm, err := api.GetVM(ctx, resourceGroup, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get VM %s: %w", name, apiError(err))
+	}
+
+	status := &providers.InstanceStatus{
+		State:     mapVMState(vm),
+		UpdatedAt: time.Now(),
+	}
+	if nicID := primaryInterfaceID(vm); nicID != "" {
+		status.PublicIP, status.PrivateIP, _ = api.InterfaceAddresses(ctx, nicID)
+	}
+	return status, nil
+}
+
+// AttachDataDisk attaches an existing managed disk to a running VM
+func (c *Client) AttachDataDisk(ctx context.Context, instanceID string, disk providers.DataDisk) error {
+	if disk.ReadOnly {
+		return fmt.Errorf("azure does not support attaching managed disk %s read-only", disk.VolumeID)
+	}
+
+	resourceGroup, name, err := c.parseInstanceID(instanceID)
+	if err != nil {
+		return err
+	}
+	api, err := c.vmAPI()
+	if err != nil {
+		return err
+	}
+
+	err = api.AttachDetachDataDisks(ctx, resourceGroup, name, armcompute.AttachDetachDataDisksRequest{
+		DataDisksToAttach: []*armcompute.DataDisksToAttach{{DiskID: to.Ptr(disk.VolumeID)}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to attach disk %s: %w", disk.VolumeID, apiError(err))
+	}
+	return nil
+}
+
+// DetachDataDisk detaches a managed disk from a VM without deleting it
+func (c *Client) DetachDataDisk(ctx context.Context, instanceID, volumeID string) error {
+	resourceGroup, name, err := c.parseInstanceID(instanceID)
+	if err != nil {
+		return err
+	}
+	api, err := c.vmAPI()
+	if err != nil {
+		return err
+	}
+
+	err = api.AttachDetachDataDisks(ctx, resourceGroup, name, armcompute.AttachDetachDataDisksRequest{
+		DataDisksToDetach: []*armcompute.DataDisksToDetach{{DiskID: to.Ptr(volumeID)}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to detach disk %s: %w", volumeID, apiError(err))
+	}
+	return nil
+}
+
+// UpdateInstanceTags sets and removes tags on a running VM
+func (c *Client) UpdateInstanceTags(ctx context.Context, instanceID string, set map[string]string, remove []string) error {
+	resourceGroup, name, err := c.parseInstanceID(instanceID)
+	if err != nil {
+		return err
+	}
+	api, err := c.vmAPI()
+	if err != nil {
+		return err
+	}
+
+	// Updating tags replaces them all, so start from the current set
+	vm, err := api.GetVM(ctx, resourceGroup, name)
+	if err != nil {
+		return fmt.Errorf("failed to get VM %s: %w", name, apiError(err))
+	}
+
+	err = api.UpdateVM(ctx, resourceGroup, name, armcompute.VirtualMachineUpdate{
+		Tags: mergeTags(vm.Tags, set, remove),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set tags on VM %s: %w", name, apiError(err))
+	}
+	return nil
+}
+
+// MissingInstanceTags returns the wanted tags the VM lacks or holds a different value for,
+// comparing them under the sanitized keys tags are stored with
+func (c *Client) MissingInstanceTags(ctx context.Context, instanceID string, want map[string]string) (map[string]string, error) {
+	resourceGroup, name, err := c.parseInstanceID(instanceID)
+	if err != nil {
+		return nil, err
+	}
+	api, err := c.vmAPI()
+	if err != nil {
+		return nil, err
+	}
+
+	vm, err := api.GetVM(ctx, resourceGroup, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get VM %s: %w", name, apiError(err))
+	}
+
+	actual := make(map[string]string, len(vm.Tags))
+	for key, value := range vm.Tags {
+		if value != nil {
+			actual[key] = *value
+		}
+	}
+	return providers.MissingTags(actual, want, func(key, value string) (string, string) {
+		return sanitizeTagKey(key), value
+	}), nil
+}
+
+// TranslateGPUType translates a standard GPU type to the Azure VM size that provides it
+func (c *Client) TranslateGPUType(standard string) (string, error) {
+	size, err := lookupVMSize(standard)
+	if err != nil {
+		return "", err
+	}
+	return size.Name, nil
+}
+
+// TranslateRegion translates standard regions to Azure locations. Azure location names are used directly.
+func (c *Client) TranslateRegion(standard string) (string, error) {
+	if location, exists := standardRegions[standard]; exists {
+		return location, nil
+	}
+	location := strings.ToLower(strings.ReplaceAll(standard, " ", ""))
+	if _, exists := regionCountries[location]; exists {
+		return location, nil
+	}
+	if _, exists := c.servicePrincipal.Subnets[location]; exists {
+		return location, nil
+	}
+	return "", fmt.Errorf("unsupported region: %s", standard)
+}
+
+// RegionCountry returns the country an Azure location is in
+func (c *Client) RegionCountry(region string) (string, bool) {
+	country, ok := regionCountries[region]
+	return country, ok
+}
+
+// parseInstanceID splits a "resourceGroup/vmName" ID. IDs without a resource group use the configured one.
+func (c *Client) parseInstanceID(instanceID string) (resourceGroup, name string, err error) {
+	if instanceID == "" {
+		return "", "", fmt.Errorf("instance ID is required")
+	}
+	if resourceGroup, name, found := strings.Cut(instanceID, "/"); found {
+		return resourceGroup, name, nil
+	}
+	return c.servicePrincipal.ResourceGroup, instanceID, nil
+}
+
+// formatInstanceID records the resource group with the VM name, as VM names are only unique within one
+func formatInstanceID(resourceGroup, name string) string {
+	return resourceGroup + "/" + name
+}
@@ -0,0 +1,208 @@
+package azure
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// spotPriceFactor approximates the spot discount against pay-as-you-go pricing
+const spotPriceFactor = 0.3
+
+// VMSize describes the Azure VM size used for a GPU type
+type VMSize struct {
+	Name         string
+	Series       string
+	GPUType      string
+	GPUCount     int
+	VCPUs        int
+	GPUMemoryGiB int64
+	// HourlyPrice is the approximate East US pay-as-you-go Linux price in USD.
+	// These should ideally come from the Azure Retail Prices API.
+	HourlyPrice float64
+}
+
+// vmSizes maps standard GPU types to the smallest VM size providing them
+var vmSizes = map[string]VMSize{
+	"NVIDIA_V100":      {Name: "Standard_NC6s_v3", Series: "NCv3", GPUType: "NVIDIA_V100", GPUCount: 1, VCPUs: 6, GPUMemoryGiB: 16, HourlyPrice: 3.06},
+	"NVIDIA_A100":      {Name: "Standard_ND96asr_v4", Series: "NDasrA100_v4", GPUType: "NVIDIA_A100", GPUCount: 8, VCPUs: 96, GPUMemoryGiB: 40, HourlyPrice: 27.197},
+	"NVIDIA_A100_80GB": {Name: "Standard_ND96amsr_A100_v4", Series: "NDm_A100_v4", GPUType: "NVIDIA_A100_80GB", GPUCount: 8, VCPUs: 96, GPUMemoryGiB: 80, HourlyPrice: 32.77},
+	"NVIDIA_H100":      {Name: "Standard_ND96isr_H100_v5", Series: "ND_H100_v5", GPUType: "NVIDIA_H100", GPUCount: 8, VCPUs: 96, GPUMemoryGiB: 80, HourlyPrice: 98.32},
+	"AMD_MI25":         {Name: "Standard_NV32as_v4", Series: "NVv4", GPUType: "AMD_MI25", GPUCount: 1, VCPUs: 32, GPUMemoryGiB: 16, HourlyPrice: 1.808},
+}
+
+// multiGPUVMSizes lists the larger VM sizes of each GPU type, by increasing GPU count
+var multiGPUVMSizes = map[string][]VMSize{
+	"NVIDIA_V100": {
+		{Name: "Standard_NC12s_v3", Series: "NCv3", GPUType: "NVIDIA_V100", GPUCount: 2, VCPUs: 12, GPUMemoryGiB: 16, HourlyPrice: 6.12},
+		{Name: "Standard_NC24s_v3", Series: "NCv3", GPUType: "NVIDIA_V100", GPUCount: 4, VCPUs: 24, GPUMemoryGiB: 16, HourlyPrice: 12.24},
+	},
+}
+
+// gpuTypeAliases maps the short and alternative standard GPU type names to vmSizes keys
+var gpuTypeAliases = map[string]string{
+	"V100":             "NVIDIA_V100",
+	"A100":             "NVIDIA_A100",
+	"A100-80GB":        "NVIDIA_A100_80GB",
+	"A100_80GB":        "NVIDIA_A100_80GB",
+	"H100":             "NVIDIA_H100",
+	"NVIDIA_H100_80GB": "NVIDIA_H100",
+	"MI25":             "AMD_MI25",
+}
+
+// lookupVMSize returns the VM size for a standard GPU type
+func lookupVMSize(gpuType string) (VMSize, error) {
+	key := strings.ToUpper(gpuType)
+	if alias, exists := gpuTypeAliases[key]; exists {
+		key = alias
+	}
+	size, exists := vmSizes[key]
+	if !exists {
+		return VMSize{}, fmt.Errorf("unsupported GPU type: %s", gpuType)
+	}
+	return size, nil
+}
+
+// lookupVMSizeForGPUs returns the smallest VM size with at least count GPUs of a standard GPU type
+func lookupVMSizeForGPUs(gpuType string, count int) (VMSize, error) {
+	size, err := lookupVMSize(gpuType)
+	if err != nil || size.GPUCount >= count {
+		return size, err
+	}
+
+	for _, larger := range multiGPUVMSizes[size.GPUType] {
+		if larger.GPUCount >= count {
+			return larger, nil
+		}
+		size = larger
+	}
+	return VMSize{}, fmt.Errorf("no VM size offers %d %s GPUs, the largest is %s with %d",
+		count, gpuType, size.Name, size.GPUCount)
+}
+
+// supportedGPUTypes returns the standard GPU types Azure offers, sorted
+func supportedGPUTypes() []string {
+	gpuTypes := make([]string, 0, len(vmSizes))
+	for gpuType := range vmSizes {
+		gpuTypes = append(gpuTypes, gpuType)
+	}
+	sort.Strings(gpuTypes)
+	return gpuTypes
+}
+
+// standardRegions maps standard regions to Azure locations
+var standardRegions = map[string]string{
+	providers.RegionUSEast:      "eastus",
+	providers.RegionUSWest:      "westus2",
+	providers.RegionEUCentral:   "germanywestcentral",
+	providers.RegionAsiaPacific: "japaneast",
+}
+
+// regionCountries maps Azure locations to the ISO 3166-1 alpha-2 code of the country they are in
+var regionCountries = map[string]string{
+	"eastus":             "US",
+	"eastus2":            "US",
+	"southcentralus":     "US",
+	"westus2":            "US",
+	"westus3":            "US",
+	"canadacentral":      "CA",
+	"northeurope":        "IE",
+	"westeurope":         "NL",
+	"uksouth":            "GB",
+	"germanywestcentral": "DE",
+	"swedencentral":      "SE",
+	"centralindia":       "IN",
+	"japaneast":          "JP",
+	"southeastasia":      "SG",
+	"australiaeast":      "AU",
+}
+
+// regionalPriceMultiplier returns the approximate price of a location relative to East US
+func regionalPriceMultiplier(location string) float64 {
+	switch regionCountries[location] {
+	case "US":
+		return 1.0
+	case "CA", "SE", "IE", "NL":
+		return 1.1
+	case "GB", "DE":
+		return 1.15
+	default:
+		return 1.25
+	}
+}
+
+// vmSizeOffers returns the pay-as-you-go and spot offers for a VM size in a location
+func vmSizeOffers(size VMSize, location string) []providers.GPUOffer {
+	price := size.HourlyPrice * regionalPriceMultiplier(location)
+	spotPrice := price * spotPriceFactor
+
+	offer := providers.GPUOffer{
+		ID:          fmt.Sprintf("%s-%s", location, size.Name),
+		GPUType:     size.GPUType,
+		GPUCount:    size.GPUCount,
+		Region:      location,
+		HourlyPrice: price,
+		SpotPrice:   spotPrice,
+		GPUMemory:   size.GPUMemoryGiB,
+		Available:   true,
+		Provider:    ProviderName,
+		Verified:    true,
+		Country:     regionCountries[location],
+		VCPUs:       size.VCPUs,
+	}
+
+	spot := offer
+	spot.ID += "-spot"
+	spot.HourlyPrice = spotPrice
+	spot.IsSpot = true
+
+	return []providers.GPUOffer{offer, spot}
+}
+
+// filterOffers applies the filters to offers
+func filterOffers(offers []providers.GPUOffer, filters *providers.GPUFilters) []providers.GPUOffer {
+	var filtered []providers.GPUOffer
+
+	for _, offer := range offers {
+		if filters.GPUType != "" {
+			size, err := lookupVMSize(filters.GPUType)
+			if err != nil || size.GPUType != offer.GPUType {
+				continue
+			}
+		}
+
+		if !providers.CountryAllowed(offer.Country, filters.Countries) {
+			continue
+		}
+
+		if filters.MaxPrice > 0 && offer.HourlyPrice > filters.MaxPrice {
+			continue
+		}
+
+		if filters.MinMemory > 0 && offer.Memory < filters.MinMemory {
+			continue
+		}
+
+		if filters.SpotOnly && !offer.IsSpot {
+			continue
+		}
+
+		if filters.OnDemandOnly && offer.IsSpot {
+			continue
+		}
+
+		if !providers.VCPUsPerGPUAllowed(offer, filters.MinVCPUPerGPU) {
+			continue
+		}
+
+		if !providers.GPUMemoryAllowed(offer, filters.MinGPUMemoryGiB) {
+			continue
+		}
+
+		filtered = append(filtered, offer)
+	}
+
+	return filtered
+}
@@ -0,0 +1,435 @@
+package azure
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// vmAPI is the subset of the Azure Resource Manager API used by the client.
+// Long-running operations are polled to completion.
+type vmAPI interface {
+	CreateVM(ctx context.Context, resourceGroup, name string, vm armcompute.VirtualMachine) (armcompute.VirtualMachine, error)
+	DeleteVM(ctx context.Context, resourceGroup, name string) error
+	GetVM(ctx context.Context, resourceGroup, name string) (armcompute.VirtualMachine, error)
+	UpdateVM(ctx context.Context, resourceGroup, name string, update armcompute.VirtualMachineUpdate) error
+	AttachDetachDataDisks(ctx context.Context, resourceGroup, name string, request armcompute.AttachDetachDataDisksRequest) error
+	// InterfaceAddresses returns the public and private IP addresses of a network interface
+	InterfaceAddresses(ctx context.Context, interfaceID string) (publicIP, privateIP string, err error)
+	// ListVMsPage reads the first page of VMs in the resource group
+	ListVMsPage(ctx context.Context, resourceGroup string) error
+}
+
+// armVMAPI implements vmAPI with the Azure SDK
+type armVMAPI struct {
+	vms        *armcompute.VirtualMachinesClient
+	interfaces *armnetwork.InterfacesClient
+	publicIPs  *armnetwork.PublicIPAddressesClient
+}
+
+// newARMVMAPI creates the Azure SDK clients for a subscription
+func newARMVMAPI(subscriptionID string, credential azcore.TokenCredential, options *arm.ClientOptions) (*armVMAPI, error) {
+	vms, err := armcompute.NewVirtualMachinesClient(subscriptionID, credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create virtual machines client: %w", err)
+	}
+	interfaces, err := armnetwork.NewInterfacesClient(subscriptionID, credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create network interfaces client: %w", err)
+	}
+	publicIPs, err := armnetwork.NewPublicIPAddressesClient(subscriptionID, credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create public IP addresses client: %w", err)
+	}
+	return &armVMAPI{vms: vms, interfaces: interfaces, publicIPs: publicIPs}, nil
+}
+
+func (a *armVMAPI) CreateVM(ctx context.Context, resourceGroup, name string, vm armcompute.VirtualMachine) (armcompute.VirtualMachine, error) {
+	poller, err := a.vms.BeginCreateOrUpdate(ctx, resourceGroup, name, vm, nil)
+	if err != nil {
+		return armcompute.VirtualMachine{}, err
+	}
+	resp, err := poller.PollUntilDone(ctx, nil)
+	if err != nil {
+		return armcompute.VirtualMachine{}, err
+	}
+	return resp.VirtualMachine, nil
+}
+
+func (a *armVMAPI) DeleteVM(ctx context.Context, resourceGroup, name string) error {
+	poller, err := a.vms.BeginDelete(ctx, resourceGroup, name, nil)
+	if err != nil {
+		return err
+	}
+	_, err = poller.PollUntilDone(ctx, nil)
+	return err
+}
+
+func (a *armVMAPI) GetVM(ctx context.Context, resourceGroup, name string) (armcompute.VirtualMachine, error) {
+	resp, err := a.vms.Get(ctx, resourceGroup, name, &armcompute.VirtualMachinesClientGetOptions{
+		Expand: to.Ptr(armcompute.InstanceViewTypesInstanceView),
+	})
+	if err != nil {
+		return armcompute.VirtualMachine{}, err
+	}
+	return resp.VirtualMachine, nil
+}
+
+func (a *armVMAPI) UpdateVM(ctx context.Context, resourceGroup, name string, update armcompute.VirtualMachineUpdate) error {
+	poller, err := a.vms.BeginUpdate(ctx, resourceGroup, name, update, nil)
+	if err != nil {
+		return err
+	}
+	_, err = poller.PollUntilDone(ctx, nil)
+	return err
+}
+
+func (a *armVMAPI) AttachDetachDataDisks(ctx context.Context, resourceGroup, name string, request armcompute.AttachDetachDataDisksRequest) error {
+	poller, err := a.vms.BeginAttachDetachDataDisks(ctx, resourceGroup, name, request, nil)
+	if err != nil {
+		return err
+	}
+	_, err = poller.PollUntilDone(ctx, nil)
+	return err
+}
+
+func (a *armVMAPI) ListVMsPage(ctx context.Context, resourceGroup string) error {
+	_, err := a.vms.NewListPager(resourceGroup, nil).NextPage(ctx)
+	return err
+}
+
+func (a *armVMAPI) InterfaceAddresses(ctx context.Context, interfaceID string) (string, string, error) {
+	nicID, err := arm.ParseResourceID(interfaceID)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid network interface ID %s: %w", interfaceID, err)
+	}
+	nic, err := a.interfaces.Get(ctx, nicID.ResourceGroupName, nicID.Name, nil)
+	if err != nil {
+		return "", "", err
+	}
+	if nic.Properties == nil {
+		return "", "", nil
+	}
+
+	var publicIP, privateIP string
+	for _, ipConfig := range nic.Properties.IPConfigurations {
+		if ipConfig.Properties == nil {
+			continue
+		}
+		if privateIP == "" {
+			privateIP = deref(ipConfig.Properties.PrivateIPAddress)
+		}
+		if publicIP == "" && ipConfig.Properties.PublicIPAddress != nil && ipConfig.Properties.PublicIPAddress.ID != nil {
+			ipID, err := arm.ParseResourceID(*ipConfig.Properties.PublicIPAddress.ID)
+			if err != nil {
+				continue
+			}
+			address, err := a.publicIPs.Get(ctx, ipID.ResourceGroupName, ipID.Name, nil)
+			if err == nil && address.Properties != nil {
+				publicIP = deref(address.Properties.IPAddress)
+			}
+		}
+	}
+	return publicIP, privateIP, nil
+}
+
+// marketplaceImagePattern matches marketplace image URNs of the form publisher:offer:sku:version
+var marketplaceImagePattern = regexp.MustCompile(`^[^:/]+:[^:/]+:[^:/]+:[^:/]+$`)
+
+// imageReference returns the image reference for a managed image or gallery image resource ID,
+// a community gallery image ID or a marketplace URN
+func imageReference(image string) (*armcompute.ImageReference, error) {
+	switch {
+	case strings.HasPrefix(strings.ToLower(image), "/communitygalleries/"):
+		return &armcompute.ImageReference{CommunityGalleryImageID: to.Ptr(image)}, nil
+	case strings.HasPrefix(image, "/subscriptions/"):
+		return &armcompute.ImageReference{ID: to.Ptr(image)}, nil
+	case marketplaceImagePattern.MatchString(image):
+		parts := strings.Split(image, ":")
+		return &armcompute.ImageReference{
+			Publisher: to.Ptr(parts[0]),
+			Offer:     to.Ptr(parts[1]),
+			SKU:       to.Ptr(parts[2]),
+			Version:   to.Ptr(parts[3]),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported Azure image reference: %q", image)
+	}
+}
+
+const (
+	// defaultOSDiskGiB is the OS disk size used when the launch does not request one
+	defaultOSDiskGiB = 100
+	// minOSDiskGiB is the smallest OS disk Azure accepts for a Linux image
+	minOSDiskGiB = 30
+)
+
+// buildVirtualMachine returns the VM definition for a launch with an OS disk of osDiskGiB
+func buildVirtualMachine(name, location, subnetID string, size VMSize, image *armcompute.ImageReference, osDiskGiB int, req *providers.LaunchRequest) armcompute.VirtualMachine {
+	vm := armcompute.VirtualMachine{
+		Location: to.Ptr(location),
+		Tags:     buildTags(req),
+		Properties: &armcompute.VirtualMachineProperties{
+			HardwareProfile: &armcompute.HardwareProfile{
+				VMSize: to.Ptr(armcompute.VirtualMachineSizeTypes(size.Name)),
+			},
+			StorageProfile: &armcompute.StorageProfile{
+				ImageReference: image,
+				OSDisk: &armcompute.OSDisk{
+					CreateOption: to.Ptr(armcompute.DiskCreateOptionTypesFromImage),
+					DeleteOption: to.Ptr(armcompute.DiskDeleteOptionTypesDelete),
+					DiskSizeGB:   to.Ptr(int32(osDiskGiB)),
+					ManagedDisk: &armcompute.ManagedDiskParameters{
+						StorageAccountType: to.Ptr(armcompute.StorageAccountTypesPremiumLRS),
+					},
+				},
+				DataDisks: buildDataDisks(req.DataDisks),
+			},
+			OSProfile: &armcompute.OSProfile{
+				ComputerName:  to.Ptr(name),
+				AdminUsername: to.Ptr("talos"),
+				// Talos has no local users, but Azure requires credentials for Linux VMs
+				AdminPassword: to.Ptr(randomPassword()),
+				LinuxConfiguration: &armcompute.LinuxConfiguration{
+					DisablePasswordAuthentication: to.Ptr(false),
+				},
+			},
+			NetworkProfile: &armcompute.NetworkProfile{
+				NetworkAPIVersion: to.Ptr(armcompute.NetworkAPIVersionTwoThousandTwenty1101),
+				NetworkInterfaceConfigurations: []*armcompute.VirtualMachineNetworkInterfaceConfiguration{{
+					Name: to.Ptr(name + "-nic"),
+					Properties: &armcompute.VirtualMachineNetworkInterfaceConfigurationProperties{
+						Primary:      to.Ptr(true),
+						DeleteOption: to.Ptr(armcompute.DeleteOptionsDelete),
+						IPConfigurations: []*armcompute.VirtualMachineNetworkInterfaceIPConfiguration{{
+							Name: to.Ptr(name + "-ip"),
+							Properties: &armcompute.VirtualMachineNetworkInterfaceIPConfigurationProperties{
+								Primary: to.Ptr(true),
+								Subnet:  &armcompute.SubResource{ID: to.Ptr(subnetID)},
+								PublicIPAddressConfiguration: &armcompute.VirtualMachinePublicIPAddressConfiguration{
+									Name: to.Ptr(name + "-pip"),
+									SKU:  &armcompute.PublicIPAddressSKU{Name: to.Ptr(armcompute.PublicIPAddressSKUNameStandard)},
+									Properties: &armcompute.VirtualMachinePublicIPAddressConfigurationProperties{
+										DeleteOption:             to.Ptr(armcompute.DeleteOptionsDelete),
+										PublicIPAllocationMethod: to.Ptr(armcompute.PublicIPAllocationMethodStatic),
+									},
+								},
+							},
+						}},
+					},
+				}},
+			},
+		},
+	}
+
+	if req.UserData != "" {
+		vm.Properties.OSProfile.CustomData = to.Ptr(base64.StdEncoding.EncodeToString([]byte(req.UserData)))
+	}
+
+	if req.SpotInstance {
+		// A max price of -1 caps spot VMs at the pay-as-you-go price instead of a fixed price
+		maxPrice := -1.0
+		if req.MaxPrice > 0 {
+			maxPrice = req.MaxPrice
+		}
+		vm.Properties.Priority = to.Ptr(armcompute.VirtualMachinePriorityTypesSpot)
+		vm.Properties.EvictionPolicy = to.Ptr(armcompute.VirtualMachineEvictionPolicyTypesDelete)
+		vm.Properties.BillingProfile = &armcompute.BillingProfile{MaxPrice: to.Ptr(maxPrice)}
+	}
+
+	return vm
+}
+
+// buildDataDisks attaches existing managed disks at launch, keeping them when the VM is deleted
+func buildDataDisks(disks []providers.DataDisk) []*armcompute.DataDisk {
+	dataDisks := make([]*armcompute.DataDisk, 0, len(disks))
+	for i, disk := range disks {
+		dataDisks = append(dataDisks, &armcompute.DataDisk{
+			Lun:          to.Ptr(int32(i)),
+			CreateOption: to.Ptr(armcompute.DiskCreateOptionTypesAttach),
+			DeleteOption: to.Ptr(armcompute.DiskDeleteOptionTypesDetach),
+			ManagedDisk:  &armcompute.ManagedDiskParameters{ID: to.Ptr(disk.VolumeID)},
+		})
+	}
+	return dataDisks
+}
+
+// invalidTagKeyChars matches characters Azure does not allow in tag names
+var invalidTagKeyChars = regexp.MustCompile(`[<>%&\\?/]`)
+
+// sanitizeTagKey replaces characters Azure does not allow in tag names
+func sanitizeTagKey(key string) string {
+	return invalidTagKeyChars.ReplaceAllString(key, "_")
+}
+
+// buildTags returns the VM tags for a launch: its labels and cost-allocation tags
+func buildTags(req *providers.LaunchRequest) map[string]*string {
+	tags := make(map[string]*string, len(req.Labels)+len(req.Tags))
+	for key, value := range req.Labels {
+		tags[sanitizeTagKey(key)] = to.Ptr(value)
+	}
+	for key, value := range req.Tags {
+		tags[sanitizeTagKey(key)] = to.Ptr(value)
+	}
+	return tags
+}
+
+// mergeTags applies tag changes to a VM's existing tags
+func mergeTags(existing map[string]*string, set map[string]string, remove []string) map[string]*string {
+	merged := make(map[string]*string, len(existing)+len(set))
+	for key, value := range existing {
+		merged[key] = value
+	}
+	for _, key := range remove {
+		delete(merged, sanitizeTagKey(key))
+	}
+	for key, value := range set {
+		merged[sanitizeTagKey(key)] = to.Ptr(value)
+	}
+	return merged
+}
+
+// generateVMName creates a unique VM name, which is also used as the Linux computer name
+func generateVMName(req *providers.LaunchRequest) string {
+	nodepool := "default"
+	if pool, ok := req.Labels["nodepool"]; ok {
+		nodepool = pool
+	}
+	name := fmt.Sprintf("tgp-%s-%d-%s", strings.ToLower(nodepool), time.Now().Unix(), randomSuffix())
+	name = invalidNameChars.ReplaceAllString(name, "-")
+
+	// Linux computer names are limited to 64 characters
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return strings.Trim(name, "-")
+}
+
+// invalidNameChars matches characters not allowed in VM and computer names
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]`)
+
+// randomSuffix returns a short random suffix of lowercase hex characters
+func randomSuffix() string {
+	b := make([]byte, 3)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// randomPassword returns a random password meeting Azure's complexity requirements
+func randomPassword() string {
+	b := make([]byte, 24)
+	_, _ = rand.Read(b)
+	return "Tg1!" + base64.RawURLEncoding.EncodeToString(b)
+}
+
+// powerState returns the VM's power state from its instance view, e.g. "running"
+func powerState(vm armcompute.VirtualMachine) string {
+	if vm.Properties == nil || vm.Properties.InstanceView == nil {
+		return ""
+	}
+	for _, status := range vm.Properties.InstanceView.Statuses {
+		if code := deref(status.Code); strings.HasPrefix(code, "PowerState/") {
+			return strings.TrimPrefix(code, "PowerState/")
+		}
+	}
+	return ""
+}
+
+// mapVMState translates a VM's provisioning and power state to the standard instance state
+func mapVMState(vm armcompute.VirtualMachine) providers.InstanceState {
+	provisioningState := ""
+	if vm.Properties != nil {
+		provisioningState = strings.ToLower(deref(vm.Properties.ProvisioningState))
+	}
+
+	switch provisioningState {
+	case "failed":
+		return providers.InstanceStateFailed
+	case "deleting":
+		return providers.InstanceStateTerminating
+	case "creating", "updating":
+		return providers.InstanceStatePending
+	}
+
+	switch powerState(vm) {
+	case "running":
+		return providers.InstanceStateRunning
+	case "starting":
+		return providers.InstanceStatePending
+	case "stopping", "deallocating":
+		return providers.InstanceStateTerminating
+	case "stopped", "deallocated":
+		return providers.InstanceStateTerminated
+	default:
+		return providers.InstanceStateUnknown
+	}
+}
+
+// primaryInterfaceID returns the ID of the VM's primary network interface
+func primaryInterfaceID(vm armcompute.VirtualMachine) string {
+	if vm.Properties == nil || vm.Properties.NetworkProfile == nil {
+		return ""
+	}
+	var first string
+	for _, nic := range vm.Properties.NetworkProfile.NetworkInterfaces {
+		if nic.ID == nil {
+			continue
+		}
+		if nic.Properties != nil && deref(nic.Properties.Primary) {
+			return *nic.ID
+		}
+		if first == "" {
+			first = *nic.ID
+		}
+	}
+	return first
+}
+
+// deref returns the value a pointer refers to, or the zero value for nil
+func deref[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+	return *p
+}
+
+// isNotFound reports whether an Azure API error means the resource does not exist
+func isNotFound(err error) bool {
+	var respErr *azcore.ResponseError
+	return errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound
+}
+
+// errorKinds maps Azure API error codes to provider error kinds
+var errorKinds = map[string]error{
+	"SkuNotAvailable":                  providers.ErrNoCapacity,
+	"AllocationFailed":                 providers.ErrNoCapacity,
+	"ZonalAllocationFailed":            providers.ErrNoCapacity,
+	"OverconstrainedAllocationRequest": providers.ErrNoCapacity,
+	"QuotaExceeded":                    providers.ErrNoCapacity,
+	"ReadOnlyDisabledSubscription":     providers.ErrBilling,
+}
+
+// apiError classifies an Azure API error by its error code, falling back to its HTTP status
+func apiError(err error) error {
+	var respErr *azcore.ResponseError
+	if !errors.As(err, &respErr) {
+		return err
+	}
+	return providers.NewAPIError(ProviderName, errorKinds[respErr.ErrorCode], respErr.StatusCode, err)
+}
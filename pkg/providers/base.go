@@ -3,8 +3,13 @@ package providers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand/v2"
 	"net"
+	"net/http"
+	"slices"
+	"strconv"
 	"time"
 
 	"golang.org/x/time/rate"
@@ -170,16 +175,31 @@ func IsRetriableError(err error) (bool, RetriableErrorType) {
 	}
 
 	// Network errors
-	if netErr, ok := err.(net.Error); ok {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
 		if netErr.Timeout() {
 			return true, RetriableErrorNetwork
 		}
 	}
 
-	// Note: HTTP response checking would need custom error types
-	// For now, rely on error message patterns below
+	// Calls short-circuited by an open circuit breaker succeed once the provider recovers
+	if errors.Is(err, ErrCircuitOpen) {
+		return true, RetriableErrorTemporary
+	}
+
+	// Classified provider API errors
+	switch {
+	case errors.Is(err, ErrRateLimited):
+		return true, RetriableErrorRateLimit
+	case errors.Is(err, ErrNoCapacity):
+		return true, RetriableErrorTemporary
+	case errors.Is(err, ErrUnauthorized), errors.Is(err, ErrBilling), errors.Is(err, ErrNotFound):
+		return false, 0
+	case isServerError(err):
+		return true, RetriableErrorServerError
+	}
 
-	// Check error message for known patterns
+	// Fall back to message patterns for errors providers do not classify
 	errMsg := err.Error()
 	if containsAny(errMsg, []string{"rate limit", "too many requests", "throttled"}) {
 		return true, RetriableErrorRateLimit
@@ -190,61 +210,87 @@ func IsRetriableError(err error) (bool, RetriableErrorType) {
 	if containsAny(errMsg, []string{"temporary", "unavailable", "try again"}) {
 		return true, RetriableErrorTemporary
 	}
+	if containsAny(errMsg, []string{"server error", "Server Error", "bad gateway", "Bad Gateway"}) {
+		return true, RetriableErrorServerError
+	}
 
 	return false, 0
 }
 
-// RetryWithBackoff executes a function with exponential backoff retry logic
-func RetryWithBackoff(ctx context.Context, config *RetryConfig, operation func() error) error {
-	var lastErr error
-	delay := config.InitialDelay
+// RetryAfterError is implemented by errors carrying how long the provider asked callers to
+// wait before retrying, such as the Retry-After header of a 429 or 503 response
+type RetryAfterError interface {
+	error
+	RetryAfter() time.Duration
+}
 
-	for attempt := 0; attempt <= config.MaxRetries; attempt++ {
-		// First attempt doesn't wait
-		if attempt > 0 {
-			select {
-			case <-time.After(delay):
-			case <-ctx.Done():
-				return ctx.Err()
-			}
-		}
+// ParseRetryAfter returns the delay of a Retry-After header given in seconds or as an HTTP
+// date, or 0 when the header is missing or invalid
+func ParseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0)
+	}
+	return 0
+}
 
-		lastErr = operation()
-		if lastErr == nil {
-			return nil // Success
+// RetryDo runs an idempotent operation, retrying the errors retryable reports with jittered
+// exponential backoff. A provider-requested Retry-After delay is waited out instead when it
+// is longer, up to the config's MaxDelay. A nil retryable uses IsRetriableError and a nil
+// config DefaultRetryConfig. Waiting stops when the context is cancelled.
+func RetryDo(ctx context.Context, config *RetryConfig, retryable func(error) bool, operation func() error) error {
+	if config == nil {
+		config = DefaultRetryConfig()
+	}
+	if retryable == nil {
+		retryable = func(err error) bool {
+			retriable, _ := IsRetriableError(err)
+			return retriable
 		}
+	}
 
-		// Check if we should retry this error
-		shouldRetry, errorType := IsRetriableError(lastErr)
-		if !shouldRetry {
-			return lastErr // Non-retriable error
+	delay := config.InitialDelay
+	for attempt := 0; ; attempt++ {
+		err := operation()
+		if err == nil || attempt >= config.MaxRetries || !retryable(err) {
+			return err
 		}
 
-		// Check if this error type is configured to be retried
-		retriable := false
-		for _, retryType := range config.RetriableErrors {
-			if retryType == errorType {
-				retriable = true
-				break
-			}
+		// Equal jitter keeps at least half the backoff while spreading out retries from
+		// concurrent callers
+		wait := delay / 2
+		if delay > 0 {
+			wait += rand.N(delay/2 + 1)
 		}
-		if !retriable {
-			return lastErr
+		var retryAfterErr RetryAfterError
+		if errors.As(err, &retryAfterErr) {
+			wait = max(wait, min(retryAfterErr.RetryAfter(), config.MaxDelay))
 		}
 
-		// Don't retry on the last attempt
-		if attempt == config.MaxRetries {
-			break
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
 		}
 
-		// Calculate next delay with exponential backoff
-		delay = time.Duration(float64(delay) * config.BackoffFactor)
-		if delay > config.MaxDelay {
-			delay = config.MaxDelay
-		}
+		delay = min(time.Duration(float64(delay)*config.BackoffFactor), config.MaxDelay)
 	}
+}
 
-	return lastErr
+// RetryWithBackoff executes a function with exponential backoff retry logic, retrying the
+// error types listed in the config
+func RetryWithBackoff(ctx context.Context, config *RetryConfig, operation func() error) error {
+	return RetryDo(ctx, config, func(err error) bool {
+		shouldRetry, errorType := IsRetriableError(err)
+		return shouldRetry && slices.Contains(config.RetriableErrors, errorType)
+	}, operation)
 }
 
 // containsAny checks if a string contains any of the given substrings
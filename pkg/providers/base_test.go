@@ -0,0 +1,116 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// throttledError is a retryable error carrying a Retry-After delay
+type throttledError struct {
+	after time.Duration
+}
+
+func (e *throttledError) Error() string {
+	return "too many requests"
+}
+
+func (e *throttledError) RetryAfter() time.Duration {
+	return e.after
+}
+
+// fastRetryConfig retries quickly so tests do not wait on backoff
+func fastRetryConfig() *RetryConfig {
+	return &RetryConfig{MaxRetries: 3, InitialDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond, BackoffFactor: 2}
+}
+
+func TestRetryDo(t *testing.T) {
+	transient := errors.New("service unavailable")
+	permanent := errors.New("invalid GPU type")
+
+	calls := 0
+	err := RetryDo(context.Background(), fastRetryConfig(), nil, func() error {
+		calls++
+		if calls < 3 {
+			return transient
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("Expected success on the third call, got %v after %d calls", err, calls)
+	}
+
+	calls = 0
+	err = RetryDo(context.Background(), fastRetryConfig(), nil, func() error {
+		calls++
+		return permanent
+	})
+	if !errors.Is(err, permanent) || calls != 1 {
+		t.Errorf("Expected a permanent error to be returned without retrying, got %v after %d calls", err, calls)
+	}
+
+	calls = 0
+	err = RetryDo(context.Background(), fastRetryConfig(), func(error) bool { return true }, func() error {
+		calls++
+		return permanent
+	})
+	if !errors.Is(err, permanent) || calls != 4 {
+		t.Errorf("Expected the last error after MaxRetries retries, got %v after %d calls", err, calls)
+	}
+}
+
+func TestRetryDoRetryAfter(t *testing.T) {
+	config := fastRetryConfig()
+	config.MaxDelay = 50 * time.Millisecond
+
+	calls := 0
+	start := time.Now()
+	err := RetryDo(context.Background(), config, nil, func() error {
+		calls++
+		if calls == 1 {
+			// Capped at MaxDelay
+			return &throttledError{after: time.Hour}
+		}
+		return nil
+	})
+	if err != nil || calls != 2 {
+		t.Fatalf("Expected success on the second call, got %v after %d calls", err, calls)
+	}
+	if elapsed := time.Since(start); elapsed < config.MaxDelay || elapsed > time.Second {
+		t.Errorf("Expected to wait the Retry-After delay capped at %s, waited %s", config.MaxDelay, elapsed)
+	}
+}
+
+func TestRetryDoContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	config := fastRetryConfig()
+	config.InitialDelay = time.Hour
+	config.MaxDelay = time.Hour
+
+	calls := 0
+	err := RetryDo(ctx, config, nil, func() error {
+		calls++
+		cancel()
+		return errors.New("connection reset")
+	})
+	if !errors.Is(err, context.Canceled) || calls != 1 {
+		t.Errorf("Expected cancellation to stop retries, got %v after %d calls", err, calls)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if got := ParseRetryAfter("30"); got != 30*time.Second {
+		t.Errorf("Expected 30s, got %s", got)
+	}
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if got := ParseRetryAfter(date); got < 58*time.Second || got > time.Minute {
+		t.Errorf("Expected about a minute for %s, got %s", date, got)
+	}
+	for _, invalid := range []string{"", "soon", "-5"} {
+		if got := ParseRetryAfter(invalid); got != 0 {
+			t.Errorf("Expected 0 for %q, got %s", invalid, got)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package providers
+
+import "time"
+
+// Increment returns the unit of usage the billing model charges for. Unknown models are
+// treated as hourly, the coarsest granularity.
+func (m BillingModel) Increment() time.Duration {
+	switch m {
+	case BillingPerSecond:
+		return time.Second
+	case BillingPerMinute:
+		return time.Minute
+	default:
+		return time.Hour
+	}
+}
+
+// BilledDuration returns how long the provider charges for running an instance for d: d rounded
+// up to the billing increment, and no less than the minimum billing period
+func (i *ProviderInfo) BilledDuration(d time.Duration) time.Duration {
+	var model BillingModel
+	var minimum time.Duration
+	if i != nil {
+		model, minimum = i.BillingGranularity, i.MinBillingPeriod
+	}
+	increment := model.Increment()
+	billed := (d + increment - 1) / increment * increment
+	return max(billed, minimum)
+}
+
+// BillingOverhead returns how many times longer than d the provider bills for, the factor by
+// which its hourly price understates the cost of an instance running for d. It is 1 when no
+// duration is expected.
+func BillingOverhead(info *ProviderInfo, d time.Duration) float64 {
+	if d <= 0 {
+		return 1.0
+	}
+	return float64(info.BilledDuration(d)) / float64(d)
+}
@@ -0,0 +1,45 @@
+package providers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBilledDuration(t *testing.T) {
+	tests := []struct {
+		name string
+		info *ProviderInfo
+		run  time.Duration
+		want time.Duration
+	}{
+		{"per-second rounds to the second", &ProviderInfo{BillingGranularity: BillingPerSecond}, 90*time.Second + time.Millisecond, 91 * time.Second},
+		{"per-second minimum period", &ProviderInfo{BillingGranularity: BillingPerSecond, MinBillingPeriod: time.Minute}, 10 * time.Second, time.Minute},
+		{"per-minute rounds up", &ProviderInfo{BillingGranularity: BillingPerMinute}, 10*time.Minute + time.Second, 11 * time.Minute},
+		{"per-hour rounds up", &ProviderInfo{BillingGranularity: BillingPerHour}, 10 * time.Minute, time.Hour},
+		{"exact increments are not rounded", &ProviderInfo{BillingGranularity: BillingPerHour}, 2 * time.Hour, 2 * time.Hour},
+		{"unknown granularity is hourly", &ProviderInfo{}, 10 * time.Minute, time.Hour},
+		{"no provider info is hourly", nil, 10 * time.Minute, time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.info.BilledDuration(tt.run); got != tt.want {
+				t.Errorf("BilledDuration(%s) = %s, want %s", tt.run, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBillingOverhead(t *testing.T) {
+	hourly := &ProviderInfo{BillingGranularity: BillingPerHour, MinBillingPeriod: time.Hour}
+	if got := BillingOverhead(hourly, 10*time.Minute); got != 6 {
+		t.Errorf("BillingOverhead(hourly, 10m) = %v, want 6", got)
+	}
+	if got := BillingOverhead(hourly, 0); got != 1 {
+		t.Errorf("BillingOverhead without an expected duration = %v, want 1", got)
+	}
+	perSecond := &ProviderInfo{BillingGranularity: BillingPerSecond, MinBillingPeriod: time.Minute}
+	if got := BillingOverhead(perSecond, 10*time.Minute); got != 1 {
+		t.Errorf("BillingOverhead(per-second, 10m) = %v, want 1", got)
+	}
+}
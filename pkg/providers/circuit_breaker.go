@@ -0,0 +1,294 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCircuitBreakerThreshold is how many consecutive failures open a provider's circuit
+	DefaultCircuitBreakerThreshold = 5
+
+	// DefaultCircuitBreakerCooldown is how long an open circuit short-circuits calls before
+	// letting a probe through
+	DefaultCircuitBreakerCooldown = time.Minute
+)
+
+// CircuitState is the state of a provider's circuit breaker
+type CircuitState string
+
+const (
+	// CircuitClosed lets every call through
+	CircuitClosed CircuitState = "Closed"
+	// CircuitOpen fails calls immediately until the cooldown has passed
+	CircuitOpen CircuitState = "Open"
+	// CircuitHalfOpen lets a single probe call through to test whether the provider recovered
+	CircuitHalfOpen CircuitState = "HalfOpen"
+)
+
+// ErrCircuitOpen is wrapped by the errors returned for calls short-circuited by an open circuit
+var ErrCircuitOpen = errors.New("provider circuit breaker is open")
+
+// CircuitOpenError is returned instead of calling a provider whose circuit is open
+type CircuitOpenError struct {
+	Provider  string
+	Until     time.Time
+	LastError error
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%s API calls paused until %s after repeated failures: %v",
+		e.Provider, e.Until.Format(time.RFC3339), e.LastError)
+}
+
+func (e *CircuitOpenError) Unwrap() error {
+	return ErrCircuitOpen
+}
+
+// CircuitStatus describes a provider's circuit breaker for status reporting
+type CircuitStatus struct {
+	State               CircuitState
+	ConsecutiveFailures int
+	// OpenUntil is when an open circuit next lets a probe through
+	OpenUntil time.Time
+	LastError error
+}
+
+// circuit tracks the failures of a single provider
+type circuit struct {
+	state     CircuitState
+	failures  int
+	openUntil time.Time
+	probing   bool
+	lastErr   error
+	openErr   *CircuitOpenError
+}
+
+// CircuitBreakers tracks consecutive API failures per provider and stops calling providers
+// that keep failing. Provider clients are recreated on every reconcile, so the state is kept
+// here by provider name and shared by every client wrapped with Wrap.
+type CircuitBreakers struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	circuits  map[string]*circuit
+	now       func() time.Time
+}
+
+// NewCircuitBreakers creates circuit breakers that open after threshold consecutive failures
+// and probe for recovery once cooldown has passed
+func NewCircuitBreakers(threshold int, cooldown time.Duration) *CircuitBreakers {
+	if threshold <= 0 {
+		threshold = DefaultCircuitBreakerThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultCircuitBreakerCooldown
+	}
+	return &CircuitBreakers{
+		threshold: threshold,
+		cooldown:  cooldown,
+		circuits:  make(map[string]*circuit),
+		now:       time.Now,
+	}
+}
+
+// Wrap returns a client whose API calls go through the provider's circuit breaker.
+// A nil CircuitBreakers returns the client unchanged.
+func (b *CircuitBreakers) Wrap(client ProviderClient) ProviderClient {
+	if b == nil || client == nil {
+		return client
+	}
+	return &circuitBreakerClient{ProviderClient: client, breakers: b, provider: client.GetProviderInfo().Name}
+}
+
+// Status returns the circuit breaker state of a provider. Providers that have not been
+// called are closed.
+func (b *CircuitBreakers) Status(provider string) CircuitStatus {
+	if b == nil {
+		return CircuitStatus{State: CircuitClosed}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, exists := b.circuits[provider]
+	if !exists {
+		return CircuitStatus{State: CircuitClosed}
+	}
+	state := c.state
+	if state == CircuitOpen && !b.now().Before(c.openUntil) {
+		state = CircuitHalfOpen
+	}
+	return CircuitStatus{State: state, ConsecutiveFailures: c.failures, OpenUntil: c.openUntil, LastError: c.lastErr}
+}
+
+// allow reports whether a call to the provider may go ahead, returning the cached open
+// error when it may not. Once the cooldown has passed a single probe is let through.
+func (b *CircuitBreakers) allow(provider string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(provider)
+	switch c.state {
+	case CircuitOpen:
+		if b.now().Before(c.openUntil) {
+			return c.openErr
+		}
+		c.state = CircuitHalfOpen
+		c.probing = true
+		return nil
+	case CircuitHalfOpen:
+		if c.probing {
+			return c.openErr
+		}
+		c.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// record updates the provider's circuit with the outcome of a call. Only failures that
+// indicate the provider API is unhealthy count; other errors, like an unknown instance or a
+// launch without capacity, show the API is answering and close the circuit.
+func (b *CircuitBreakers) record(ctx context.Context, provider string, err error) {
+	// Calls abandoned by the caller say nothing about the provider
+	if err != nil && ctx.Err() != nil {
+		b.mu.Lock()
+		b.circuit(provider).probing = false
+		b.mu.Unlock()
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(provider)
+	c.probing = false
+	if !isAPIHealthFailure(err) {
+		c.state = CircuitClosed
+		c.failures = 0
+		c.lastErr = nil
+		c.openErr = nil
+		return
+	}
+
+	c.failures++
+	c.lastErr = err
+	if c.state == CircuitHalfOpen || c.failures >= b.threshold {
+		c.state = CircuitOpen
+		c.openUntil = b.now().Add(b.cooldown)
+		c.openErr = &CircuitOpenError{Provider: provider, Until: c.openUntil, LastError: err}
+	}
+}
+
+// isAPIHealthFailure reports whether err shows the provider API itself is unhealthy: rate
+// limited, failing with server errors or unreachable
+func isAPIHealthFailure(err error) bool {
+	retriable, errType := IsRetriableError(err)
+	if !retriable {
+		return false
+	}
+	switch errType {
+	case RetriableErrorRateLimit, RetriableErrorServerError, RetriableErrorNetwork:
+		return true
+	}
+	return false
+}
+
+// circuit returns the provider's circuit, creating a closed one on first use. The caller
+// must hold the lock.
+func (b *CircuitBreakers) circuit(provider string) *circuit {
+	c, exists := b.circuits[provider]
+	if !exists {
+		c = &circuit{state: CircuitClosed}
+		b.circuits[provider] = c
+	}
+	return c
+}
+
+// circuitBreakerClient guards the API calls of a provider client with its circuit breaker.
+// Metadata and translation calls do not reach the provider API and pass straight through,
+// and so does TerminateInstance, so removing instances never waits out a cooldown.
+type circuitBreakerClient struct {
+	ProviderClient
+	breakers *CircuitBreakers
+	provider string
+}
+
+// Unwrap returns the guarded client so its optional capabilities can be detected
+func (c *circuitBreakerClient) Unwrap() ProviderClient {
+	return c.ProviderClient
+}
+
+// call runs an API call through the circuit breaker
+func (c *circuitBreakerClient) call(ctx context.Context, operation func() error) error {
+	if err := c.breakers.allow(c.provider); err != nil {
+		return err
+	}
+	err := operation()
+	c.breakers.record(ctx, c.provider, err)
+	return err
+}
+
+func (c *circuitBreakerClient) LaunchInstance(ctx context.Context, req *LaunchRequest) (*GPUInstance, error) {
+	var instance *GPUInstance
+	err := c.call(ctx, func() (err error) {
+		instance, err = c.ProviderClient.LaunchInstance(ctx, req)
+		return err
+	})
+	return instance, err
+}
+
+func (c *circuitBreakerClient) GetInstanceStatus(ctx context.Context, instanceID string) (*InstanceStatus, error) {
+	var status *InstanceStatus
+	err := c.call(ctx, func() (err error) {
+		status, err = c.ProviderClient.GetInstanceStatus(ctx, instanceID)
+		return err
+	})
+	return status, err
+}
+
+func (c *circuitBreakerClient) AttachDataDisk(ctx context.Context, instanceID string, disk DataDisk) error {
+	return c.call(ctx, func() error {
+		return c.ProviderClient.AttachDataDisk(ctx, instanceID, disk)
+	})
+}
+
+func (c *circuitBreakerClient) DetachDataDisk(ctx context.Context, instanceID, volumeID string) error {
+	return c.call(ctx, func() error {
+		return c.ProviderClient.DetachDataDisk(ctx, instanceID, volumeID)
+	})
+}
+
+func (c *circuitBreakerClient) ListAvailableGPUs(ctx context.Context, filters *GPUFilters) ([]GPUOffer, error) {
+	var offers []GPUOffer
+	err := c.call(ctx, func() (err error) {
+		offers, err = c.ProviderClient.ListAvailableGPUs(ctx, filters)
+		return err
+	})
+	return offers, err
+}
+
+func (c *circuitBreakerClient) GetNormalizedPricing(ctx context.Context, gpuType, region string) (*NormalizedPricing, error) {
+	var pricing *NormalizedPricing
+	err := c.call(ctx, func() (err error) {
+		pricing, err = c.ProviderClient.GetNormalizedPricing(ctx, gpuType, region)
+		return err
+	})
+	return pricing, err
+}
+
+// Unwrap returns the client underneath any wrappers, such as the circuit breaker, so
+// optional provider capabilities can be detected with a type assertion
+func Unwrap(client ProviderClient) ProviderClient {
+	for {
+		wrapper, ok := client.(interface{ Unwrap() ProviderClient })
+		if !ok {
+			return client
+		}
+		client = wrapper.Unwrap()
+	}
+}
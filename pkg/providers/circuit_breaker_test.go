@@ -0,0 +1,144 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// flakyClient fails ListAvailableGPUs and LaunchInstance with err while it is set
+type flakyClient struct {
+	ProviderClient
+	err        error
+	calls      int
+	terminated []string
+}
+
+func (c *flakyClient) GetProviderInfo() *ProviderInfo {
+	return &ProviderInfo{Name: "flaky"}
+}
+
+func (c *flakyClient) ListAvailableGPUs(ctx context.Context, filters *GPUFilters) ([]GPUOffer, error) {
+	c.calls++
+	return nil, c.err
+}
+
+func (c *flakyClient) LaunchInstance(ctx context.Context, req *LaunchRequest) (*GPUInstance, error) {
+	c.calls++
+	return nil, c.err
+}
+
+func (c *flakyClient) TerminateInstance(ctx context.Context, instanceID string) error {
+	c.terminated = append(c.terminated, instanceID)
+	return nil
+}
+
+func (c *flakyClient) SelectAccount(account string) error {
+	return nil
+}
+
+func TestCircuitBreakers(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	breakers := NewCircuitBreakers(3, time.Minute)
+	breakers.now = func() time.Time { return now }
+
+	raw := &flakyClient{err: errors.New("502 bad gateway")}
+	client := breakers.Wrap(raw)
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.ListAvailableGPUs(ctx, nil); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("call %d: circuit opened before the threshold", i+1)
+		}
+	}
+	if status := breakers.Status("flaky"); status.State != CircuitOpen || status.ConsecutiveFailures != 3 {
+		t.Fatalf("expected open circuit after 3 failures, got %+v", status)
+	}
+
+	// Open circuits fail fast without calling the provider, from any client for the provider
+	_, first := breakers.Wrap(raw).ListAvailableGPUs(ctx, nil)
+	_, second := client.ListAvailableGPUs(ctx, nil)
+	if !errors.Is(first, ErrCircuitOpen) || first != second {
+		t.Fatalf("expected the cached open error, got %v and %v", first, second)
+	}
+	if retriable, _ := IsRetriableError(first); !retriable {
+		t.Error("expected open circuit errors to be retriable")
+	}
+	if raw.calls != 3 {
+		t.Errorf("expected 3 provider calls, got %d", raw.calls)
+	}
+
+	// Instances can still be terminated while the circuit is open
+	if err := client.TerminateInstance(ctx, "i-123"); err != nil || len(raw.terminated) != 1 {
+		t.Errorf("expected termination to bypass the open circuit, got %v", err)
+	}
+
+	// After the cooldown a failed probe reopens the circuit
+	now = now.Add(time.Minute)
+	if status := breakers.Status("flaky"); status.State != CircuitHalfOpen {
+		t.Fatalf("expected half-open circuit after the cooldown, got %s", status.State)
+	}
+	if _, err := client.ListAvailableGPUs(ctx, nil); errors.Is(err, ErrCircuitOpen) {
+		t.Fatal("expected the probe to reach the provider")
+	}
+	if status := breakers.Status("flaky"); status.State != CircuitOpen || !status.OpenUntil.Equal(now.Add(time.Minute)) {
+		t.Fatalf("expected circuit reopened by the failed probe, got %+v", status)
+	}
+
+	// A successful probe closes the circuit
+	now = now.Add(time.Minute)
+	raw.err = nil
+	if _, err := client.ListAvailableGPUs(ctx, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status := breakers.Status("flaky"); status.State != CircuitClosed || status.ConsecutiveFailures != 0 {
+		t.Errorf("expected closed circuit after a successful probe, got %+v", status)
+	}
+}
+
+func TestCircuitBreakersIgnoreHealthyErrors(t *testing.T) {
+	breakers := NewCircuitBreakers(1, time.Minute)
+	client := breakers.Wrap(&flakyClient{err: errors.New("instance not found")})
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.ListAvailableGPUs(context.Background(), nil); errors.Is(err, ErrCircuitOpen) {
+			t.Fatal("expected errors from a healthy API not to open the circuit")
+		}
+	}
+
+	// Launches without capacity are answered by a healthy API
+	outOfStock := breakers.Wrap(&flakyClient{err: fmt.Errorf("no H100 left: %w", ErrNoCapacity)})
+	for i := 0; i < 3; i++ {
+		if _, err := outOfStock.LaunchInstance(context.Background(), &LaunchRequest{}); errors.Is(err, ErrCircuitOpen) {
+			t.Fatal("expected capacity errors not to open the circuit")
+		}
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	failing := breakers.Wrap(&flakyClient{err: context.Canceled})
+	if _, err := failing.ListAvailableGPUs(canceled, nil); errors.Is(err, ErrCircuitOpen) {
+		t.Fatal("unexpected open circuit")
+	}
+	if status := breakers.Status("flaky"); status.State != CircuitClosed {
+		t.Errorf("expected canceled calls not to count, got %s", status.State)
+	}
+}
+
+func TestCircuitBreakersKeepCapabilities(t *testing.T) {
+	var breakers *CircuitBreakers
+	raw := &flakyClient{}
+	if breakers.Wrap(raw) != ProviderClient(raw) {
+		t.Error("expected nil circuit breakers to return the client unchanged")
+	}
+
+	wrapped := NewCircuitBreakers(0, 0).Wrap(raw)
+	if Unwrap(wrapped) != ProviderClient(raw) {
+		t.Error("expected Unwrap to return the guarded client")
+	}
+	if err := SelectAccount(wrapped, "team-a"); err != nil {
+		t.Errorf("expected the guarded client's account selection to be used, got %v", err)
+	}
+}
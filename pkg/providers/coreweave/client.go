@@ -0,0 +1,308 @@
+// Package coreweave implements the provider client for CoreWeave GPU virtual servers
+package coreweave
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+const ProviderName = "coreweave"
+
+// Client implements the ProviderClient interface for CoreWeave. Instances are VirtualServer
+// resources created through the Kubernetes API of the CoreWeave tenant namespace.
+type Client struct {
+	restConfig *rest.Config
+	namespace  string
+
+	mutex sync.Mutex
+	api   dynamic.Interface
+}
+
+// NewClient creates a new CoreWeave provider client from the kubeconfig CoreWeave issues for
+// API access. Virtual servers are created in the namespace of the kubeconfig's current context.
+func NewClient(kubeconfig string) (*Client, error) {
+	config, err := clientcmd.Load([]byte(kubeconfig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CoreWeave kubeconfig: %w", err)
+	}
+	restConfig, err := clientcmd.NewDefaultClientConfig(*config, nil).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CoreWeave kubeconfig: %w", err)
+	}
+	currentContext, exists := config.Contexts[config.CurrentContext]
+	if !exists || currentContext.Namespace == "" {
+		return nil, fmt.Errorf("CoreWeave kubeconfig must set the tenant namespace in its current context")
+	}
+	return &Client{restConfig: restConfig, namespace: currentContext.Namespace}, nil
+}
+
+// EnableDebugLogging logs every CoreWeave API request and response at debug level
+func (c *Client) EnableDebugLogging(log logr.Logger) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.restConfig.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return providers.NewDebugTransport(rt, log)
+	})
+	c.api = nil
+}
+
+// virtualServers returns the VirtualServer API of the tenant namespace, creating the client on first use
+func (c *Client) virtualServers() (dynamic.ResourceInterface, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.api == nil {
+		api, err := dynamic.NewForConfig(c.restConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create CoreWeave API client: %w", err)
+		}
+		c.api = api
+	}
+	return c.api.Resource(virtualServerResource).Namespace(c.namespace), nil
+}
+
+// GetProviderInfo returns information about the CoreWeave provider
+func (c *Client) GetProviderInfo() *providers.ProviderInfo {
+	return &providers.ProviderInfo{
+		Name:                  ProviderName,
+		APIVersion:            virtualServerResource.Version,
+		SupportedRegions:      supportedRegions(),
+		SupportedGPUTypes:     supportedGPUTypes(),
+		SupportsSpotInstances: false,
+		SupportsMultiGPU:      true,
+		BillingGranularity:    providers.BillingPerMinute,
+		MinBillingPeriod:      time.Minute,
+		ReliabilityTier:       providers.ReliabilityTierEnterprise,
+
+		SupportsReadOnlyDataDisks: true,
+	}
+}
+
+// GetRateLimits returns the rate limits for the CoreWeave Kubernetes API
+func (c *Client) GetRateLimits() *providers.RateLimitInfo {
+	return &providers.RateLimitInfo{
+		RequestsPerSecond: 5,
+		RequestsPerMinute: 300,
+		BurstCapacity:     10,
+		BackoffStrategy:   "exponential",
+		ResetWindow:       time.Minute,
+	}
+}
+
+// CheckCredentials lists a virtual server in the tenant namespace, which fails when the
+// kubeconfig's token is invalid or lacks access to the namespace
+func (c *Client) CheckCredentials(ctx context.Context) error {
+	api, err := c.virtualServers()
+	if err != nil {
+		return err
+	}
+	if _, err := api.List(ctx, metav1.ListOptions{Limit: 1}); err != nil {
+		return fmt.Errorf("failed to list virtual servers in namespace %s: %w", c.namespace, apiError(err))
+	}
+	return nil
+}
+
+// ListAvailableGPUs returns the CoreWeave GPU classes matching the filters in each region.
+// CoreWeave has no capacity API, so every class is listed as available.
+func (c *Client) ListAvailableGPUs(ctx context.Context, filters *providers.GPUFilters) ([]providers.GPUOffer, error) {
+	if filters == nil {
+		filters = &providers.GPUFilters{}
+	}
+
+	regions := supportedRegions()
+	if filters.Region != "" {
+		region, err := c.TranslateRegion(filters.Region)
+		if err != nil {
+			return nil, err
+		}
+		regions = []string{region}
+	}
+
+	var offers []providers.GPUOffer
+	for _, region := range regions {
+		for _, gpuType := range supportedGPUTypes() {
+			offers = append(offers, gpuClassOffer(gpuClasses[gpuType], region))
+		}
+	}
+	return filterOffers(offers, filters), nil
+}
+
+// GetNormalizedPricing returns the on-demand price of a single-GPU virtual server,
+// including its CPU and memory. CoreWeave prices are the same in every region.
+func (c *Client) GetNormalizedPricing(ctx context.Context, gpuType, region string) (*providers.NormalizedPricing, error) {
+	class, err := lookupGPUClass(gpuType)
+	if err != nil {
+		return nil, err
+	}
+	if region != "" {
+		if _, err := c.TranslateRegion(region); err != nil {
+			return nil, err
+		}
+	}
+
+	price := class.HourlyPrice(1)
+	return &providers.NormalizedPricing{
+		PricePerHour:   price,
+		PricePerSecond: price / 3600,
+		Currency:       "USD",
+		BillingModel:   providers.BillingPerMinute,
+		LastUpdated:    time.Now(),
+		ProviderSpecific: map[string]interface{}{
+			"gpuClass": class.Name,
+			"vcpus":    class.VCPUsPerGPU,
+			"memory":   fmt.Sprintf("%dGi", class.MemoryGiBPerGPU),
+		},
+	}, nil
+}
+
+// LaunchInstance creates a virtual server booting the Talos image with the launch's user data
+func (c *Client) LaunchInstance(ctx context.Context, req *providers.LaunchRequest) (*providers.GPUInstance, error) {
+	if req.SpotInstance {
+		return nil, fmt.Errorf("coreweave does not offer spot virtual servers")
+	}
+	class, err := lookupGPUClass(req.GPUType)
+	if err != nil {
+		return nil, err
+	}
+	if req.RequestedGPUs() > maxGPUsPerServer {
+		return nil, fmt.Errorf("coreweave virtual servers have at most %d GPUs, %d requested", maxGPUsPerServer, req.RequestedGPUs())
+	}
+
+	region := "ORD1"
+	if req.Region != "" {
+		if region, err = c.TranslateRegion(req.Region); err != nil {
+			return nil, err
+		}
+	}
+
+	servers, err := c.virtualServers()
+	if err != nil {
+		return nil, err
+	}
+
+	server, err := buildVirtualServer(generateServerName(req), c.namespace, region, class, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the virtual server, retrying with a fresh name if another launch took it
+	created, err := servers.Create(ctx, server, metav1.CreateOptions{})
+	for attempt := 0; apierrors.IsAlreadyExists(err) && attempt < req.NameCollisionRetries; attempt++ {
+		server.SetName(generateServerName(req))
+		created, err = servers.Create(ctx, server, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create virtual server %s: %w", server.GetName(), apiError(err))
+	}
+
+	state, _ := mapServerState(created)
+	instance := &providers.GPUInstance{
+		ID:        created.GetName(),
+		Status:    state,
+		CreatedAt: created.GetCreationTimestamp().Time,
+	}
+	if instance.CreatedAt.IsZero() {
+		instance.CreatedAt = time.Now()
+	}
+	instance.PublicIP, instance.PrivateIP = serverAddresses(created)
+	return instance, nil
+}
+
+// TerminateInstance deletes a virtual server. Virtual servers that no longer exist are treated as terminated.
+func (c *Client) TerminateInstance(ctx context.Context, instanceID string) error {
+	servers, err := c.virtualServers()
+	if err != nil {
+		return err
+	}
+	if err := servers.Delete(ctx, instanceID, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete virtual server %s: %w", instanceID, apiError(err))
+	}
+	return nil
+}
+
+// GetInstanceStatus returns the current status of a virtual server
+func (c *Client) GetInstanceStatus(ctx context.Context, instanceID string) (*providers.InstanceStatus, error) {
+	servers, err := c.virtualServers()
+	if err != nil {
+		return nil, err
+	}
+	server, err := servers.Get(ctx, instanceID, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get virtual server %s: %w", instanceID, apiError(err))
+	}
+
+	state, message := mapServerState(server)
+	status := &providers.InstanceStatus{
+		State:     state,
+		UpdatedAt: time.Now(),
+		Message:   message,
+	}
+	status.PublicIP, status.PrivateIP = serverAddresses(server)
+	return status, nil
+}
+
+// AttachDataDisk is not supported: CoreWeave attaches PVCs to virtual servers only at launch
+func (c *Client) AttachDataDisk(ctx context.Context, instanceID string, disk providers.DataDisk) error {
+	return fmt.Errorf("coreweave attaches data disks only at launch, cannot attach %s to %s", disk.VolumeID, instanceID)
+}
+
+// DetachDataDisk is not supported: CoreWeave attaches PVCs to virtual servers only at launch
+func (c *Client) DetachDataDisk(ctx context.Context, instanceID, volumeID string) error {
+	return fmt.Errorf("coreweave attaches data disks only at launch, cannot detach %s from %s", volumeID, instanceID)
+}
+
+// TranslateGPUType translates a standard GPU type to the CoreWeave GPU class
+func (c *Client) TranslateGPUType(standard string) (string, error) {
+	class, err := lookupGPUClass(standard)
+	if err != nil {
+		return "", err
+	}
+	return class.Name, nil
+}
+
+// TranslateRegion translates standard regions to CoreWeave regions. CoreWeave region names are used directly.
+func (c *Client) TranslateRegion(standard string) (string, error) {
+	if region, exists := standardRegions[standard]; exists {
+		return region, nil
+	}
+	region := strings.ToUpper(standard)
+	if _, exists := regionCountries[region]; exists {
+		return region, nil
+	}
+	return "", fmt.Errorf("unsupported region: %s", standard)
+}
+
+// RegionCountry returns the country a CoreWeave region is in
+func (c *Client) RegionCountry(region string) (string, bool) {
+	country, ok := regionCountries[region]
+	return country, ok
+}
+
+// apiError classifies a Kubernetes API error from the CoreWeave cloud by its status. Requests
+// rejected for exceeding the namespace's resource quota are capacity errors.
+func apiError(err error) error {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) {
+		return err
+	}
+	var kind error
+	if apierrors.IsForbidden(err) && strings.Contains(status.Status().Message, "exceeded quota") {
+		kind = providers.ErrNoCapacity
+	}
+	return providers.NewAPIError(ProviderName, kind, int(status.Status().Code), err)
+}
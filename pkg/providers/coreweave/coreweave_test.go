@@ -0,0 +1,263 @@
+package coreweave
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: coreweave
+  cluster:
+    server: https://k8s.ord1.coreweave.com
+contexts:
+- name: coreweave
+  context:
+    cluster: coreweave
+    user: token
+    namespace: tenant-test
+current-context: coreweave
+users:
+- name: token
+  user:
+    token: test-token
+`
+
+// newTestClient returns a client whose API calls go to a fake dynamic client
+func newTestClient(t *testing.T) (*Client, *dynamicfake.FakeDynamicClient) {
+	t.Helper()
+	client, err := NewClient(testKubeconfig)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	fake := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{virtualServerResource: "VirtualServerList"})
+	client.api = fake
+	return client, fake
+}
+
+func TestNewClient(t *testing.T) {
+	client, _ := newTestClient(t)
+	if client.namespace != "tenant-test" {
+		t.Errorf("Expected namespace from the kubeconfig context, got %s", client.namespace)
+	}
+
+	noNamespace := `apiVersion: v1
+kind: Config
+clusters:
+- name: coreweave
+  cluster:
+    server: https://k8s.ord1.coreweave.com
+contexts:
+- name: coreweave
+  context:
+    cluster: coreweave
+current-context: coreweave
+`
+	for _, invalid := range []string{"", "not: [yaml", noNamespace} {
+		if _, err := NewClient(invalid); err == nil {
+			t.Errorf("Expected error for kubeconfig %q", invalid)
+		}
+	}
+}
+
+func TestTranslateGPUType(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	tests := map[string]string{
+		"A40":              "A40",
+		"NVIDIA_A100":      "A100_PCIE_40GB",
+		"a100-80gb":        "A100_PCIE_80GB",
+		"NVIDIA_H100_80GB": "H100_PCIE",
+	}
+	for gpuType, expected := range tests {
+		got, err := client.TranslateGPUType(gpuType)
+		if err != nil || got != expected {
+			t.Errorf("TranslateGPUType(%s) = %s, %v, expected %s", gpuType, got, err, expected)
+		}
+	}
+
+	if _, err := client.TranslateGPUType("MI300X"); err == nil {
+		t.Error("Expected error for GPU type CoreWeave does not offer")
+	}
+}
+
+func TestTranslateRegion(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	tests := map[string]string{
+		providers.RegionUSEast: "LGA1",
+		"ord1":                 "ORD1",
+	}
+	for standard, expected := range tests {
+		got, err := client.TranslateRegion(standard)
+		if err != nil || got != expected {
+			t.Errorf("TranslateRegion(%s) = %s, %v, expected %s", standard, got, err, expected)
+		}
+	}
+
+	if _, err := client.TranslateRegion(providers.RegionEUCentral); err == nil {
+		t.Error("Expected error for region CoreWeave does not serve")
+	}
+}
+
+func TestListAvailableGPUs(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	offers, err := client.ListAvailableGPUs(context.Background(), &providers.GPUFilters{GPUType: "H100", Region: "ord1"})
+	if err != nil {
+		t.Fatalf("ListAvailableGPUs failed: %v", err)
+	}
+	if len(offers) != 1 {
+		t.Fatalf("Expected one H100 offer in ORD1, got %d", len(offers))
+	}
+	// GPU price plus 16 vCPUs and 128 GiB of memory
+	if offer := offers[0]; offer.HourlyPrice != 4.25+0.16+0.64 || offer.VCPUs != 16 || offer.Country != "US" {
+		t.Errorf("Unexpected offer: %+v", offer)
+	}
+
+	offers, err = client.ListAvailableGPUs(context.Background(), &providers.GPUFilters{Countries: []string{"DE"}})
+	if err != nil {
+		t.Fatalf("ListAvailableGPUs failed: %v", err)
+	}
+	if len(offers) != 0 {
+		t.Errorf("Expected no offers outside the US, got %d", len(offers))
+	}
+}
+
+func TestGetNormalizedPricing(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	pricing, err := client.GetNormalizedPricing(context.Background(), "A40", providers.RegionUSWest)
+	if err != nil {
+		t.Fatalf("GetNormalizedPricing failed: %v", err)
+	}
+	if expected := 1.28 + 0.08 + 0.24; pricing.PricePerHour != expected {
+		t.Errorf("Expected %.2f per hour, got %.2f", expected, pricing.PricePerHour)
+	}
+
+	if _, err := client.GetNormalizedPricing(context.Background(), "T4", ""); err == nil {
+		t.Error("Expected error for unsupported GPU type")
+	}
+}
+
+func TestLaunchInstance(t *testing.T) {
+	client, fake := newTestClient(t)
+	ctx := context.Background()
+
+	instance, err := client.LaunchInstance(ctx, &providers.LaunchRequest{
+		GPUType:     "A100",
+		GPUCount:    2,
+		Region:      providers.RegionUSEast,
+		Image:       "talos",
+		UserData:    "machine: config",
+		Labels:      map[string]string{"tgp.io/nodepool": "pool"},
+		Tags:        map[string]string{"team": "ml"},
+		DataDisks:   []providers.DataDisk{{VolumeID: "datasets", ReadOnly: true}},
+		BootDiskGiB: 120,
+	})
+	if err != nil {
+		t.Fatalf("LaunchInstance failed: %v", err)
+	}
+	if instance.Status != providers.InstanceStatePending {
+		t.Errorf("Expected pending virtual server, got %s", instance.Status)
+	}
+
+	server, err := fake.Resource(virtualServerResource).Namespace("tenant-test").Get(ctx, instance.ID, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected virtual server %s to be created: %v", instance.ID, err)
+	}
+
+	expectString := func(expected string, fields ...string) {
+		t.Helper()
+		if got, _, _ := unstructured.NestedString(server.Object, fields...); got != expected {
+			t.Errorf("Expected %v to be %q, got %q", fields, expected, got)
+		}
+	}
+	expectString("LGA1", "spec", "region")
+	expectString("A100_PCIE_40GB", "spec", "resources", "gpu", "type")
+	expectString("128Gi", "spec", "resources", "memory")
+	expectString("machine: config", "spec", "cloudInit")
+	expectString("120Gi", "spec", "storage", "root", "size")
+	expectString("block-nvme-lga1", "spec", "storage", "root", "storageClassName")
+	expectString("tenant-test", "spec", "storage", "root", "source", "pvc", "namespace")
+	expectString("talos", "spec", "storage", "root", "source", "pvc", "name")
+
+	if count, _, _ := unstructured.NestedInt64(server.Object, "spec", "resources", "gpu", "count"); count != 2 {
+		t.Errorf("Expected 2 GPUs, got %d", count)
+	}
+	if server.GetLabels()["tgp.io/nodepool"] != "pool" || server.GetAnnotations()[tagAnnotationPrefix+"team"] != "ml" {
+		t.Errorf("Expected labels and tags, got %v and %v", server.GetLabels(), server.GetAnnotations())
+	}
+	disks, _, _ := unstructured.NestedSlice(server.Object, "spec", "storage", "additionalDisks")
+	if len(disks) != 1 {
+		t.Errorf("Expected the data disk to be attached, got %v", disks)
+	}
+
+	if _, err := client.LaunchInstance(ctx, &providers.LaunchRequest{GPUType: "A100", SpotInstance: true}); err == nil {
+		t.Error("Expected error for spot launch")
+	}
+	if _, err := client.LaunchInstance(ctx, &providers.LaunchRequest{GPUType: "A100", GPUCount: 16}); err == nil {
+		t.Error("Expected error for more GPUs than a virtual server has")
+	}
+}
+
+func TestInstanceLifecycle(t *testing.T) {
+	client, fake := newTestClient(t)
+	ctx := context.Background()
+
+	server := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "virtualservers.coreweave.com/v1alpha1",
+		"kind":       "VirtualServer",
+		"metadata":   map[string]interface{}{"name": "tgp-a40-1234", "namespace": "tenant-test"},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True", "reason": "VirtualServerReady", "message": "running"},
+			},
+			"network": map[string]interface{}{"externalIP": "203.0.113.40", "internalIP": "10.135.0.7"},
+		},
+	}}
+	if _, err := fake.Resource(virtualServerResource).Namespace("tenant-test").Create(ctx, server, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to seed virtual server: %v", err)
+	}
+
+	status, err := client.GetInstanceStatus(ctx, "tgp-a40-1234")
+	if err != nil {
+		t.Fatalf("GetInstanceStatus failed: %v", err)
+	}
+	if status.State != providers.InstanceStateRunning || status.PublicIP != "203.0.113.40" || status.PrivateIP != "10.135.0.7" {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+
+	if err := client.TerminateInstance(ctx, "tgp-a40-1234"); err != nil {
+		t.Fatalf("TerminateInstance failed: %v", err)
+	}
+	if err := client.TerminateInstance(ctx, "tgp-a40-1234"); err != nil {
+		t.Errorf("Expected deleted virtual server to count as terminated, got %v", err)
+	}
+	if _, err := client.GetInstanceStatus(ctx, "tgp-a40-1234"); !errors.Is(err, providers.ErrNotFound) {
+		t.Errorf("Expected a not found error for the deleted virtual server, got %v", err)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	resource := virtualServerResource.GroupResource()
+	quota := apierrors.NewForbidden(resource, "tgp-a40-1234", errors.New("exceeded quota: gpu-quota"))
+	if err := apiError(quota); !errors.Is(err, providers.ErrNoCapacity) {
+		t.Errorf("Expected an exceeded quota to be a capacity error, got %v", err)
+	}
+	if err := apiError(apierrors.NewUnauthorized("invalid token")); !errors.Is(err, providers.ErrUnauthorized) {
+		t.Errorf("Expected a 401 to be an unauthorized error, got %v", err)
+	}
+}
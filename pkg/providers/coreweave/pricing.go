@@ -0,0 +1,161 @@
+package coreweave
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// Per-resource prices CoreWeave adds on top of the GPU price of a virtual server
+const (
+	vcpuHourlyPrice      = 0.01
+	memoryGiBHourlyPrice = 0.005
+)
+
+// maxGPUsPerServer is the most GPUs a single virtual server can have
+const maxGPUsPerServer = 8
+
+// GPUClass describes a CoreWeave GPU class and the CPU and memory given to each of its GPUs
+type GPUClass struct {
+	Name         string
+	GPUType      string
+	GPUMemoryGiB int64
+	VCPUsPerGPU  int
+	// MemoryGiBPerGPU is the system memory allocated per GPU
+	MemoryGiBPerGPU int
+	// GPUHourlyPrice is CoreWeave's published on-demand price per GPU in USD
+	GPUHourlyPrice float64
+}
+
+// HourlyPrice returns the on-demand price of a virtual server with count GPUs of the class,
+// including its CPU and memory
+func (g GPUClass) HourlyPrice(count int) float64 {
+	perGPU := g.GPUHourlyPrice + float64(g.VCPUsPerGPU)*vcpuHourlyPrice + float64(g.MemoryGiBPerGPU)*memoryGiBHourlyPrice
+	return perGPU * float64(count)
+}
+
+// gpuClasses maps standard GPU types to the CoreWeave GPU class providing them
+var gpuClasses = map[string]GPUClass{
+	"NVIDIA_A40":       {Name: "A40", GPUType: "NVIDIA_A40", GPUMemoryGiB: 48, VCPUsPerGPU: 8, MemoryGiBPerGPU: 48, GPUHourlyPrice: 1.28},
+	"NVIDIA_A100":      {Name: "A100_PCIE_40GB", GPUType: "NVIDIA_A100", GPUMemoryGiB: 40, VCPUsPerGPU: 12, MemoryGiBPerGPU: 64, GPUHourlyPrice: 2.06},
+	"NVIDIA_A100_80GB": {Name: "A100_PCIE_80GB", GPUType: "NVIDIA_A100_80GB", GPUMemoryGiB: 80, VCPUsPerGPU: 12, MemoryGiBPerGPU: 96, GPUHourlyPrice: 2.21},
+	"NVIDIA_H100":      {Name: "H100_PCIE", GPUType: "NVIDIA_H100", GPUMemoryGiB: 80, VCPUsPerGPU: 16, MemoryGiBPerGPU: 128, GPUHourlyPrice: 4.25},
+}
+
+// gpuTypeAliases maps the short and alternative standard GPU type names to gpuClasses keys
+var gpuTypeAliases = map[string]string{
+	"A40":              "NVIDIA_A40",
+	"A100":             "NVIDIA_A100",
+	"A100-80GB":        "NVIDIA_A100_80GB",
+	"A100_80GB":        "NVIDIA_A100_80GB",
+	"H100":             "NVIDIA_H100",
+	"NVIDIA_H100_80GB": "NVIDIA_H100",
+}
+
+// lookupGPUClass returns the GPU class for a standard GPU type
+func lookupGPUClass(gpuType string) (GPUClass, error) {
+	key := strings.ToUpper(gpuType)
+	if alias, exists := gpuTypeAliases[key]; exists {
+		key = alias
+	}
+	class, exists := gpuClasses[key]
+	if !exists {
+		return GPUClass{}, fmt.Errorf("unsupported GPU type: %s", gpuType)
+	}
+	return class, nil
+}
+
+// supportedGPUTypes returns the standard GPU types CoreWeave offers, sorted
+func supportedGPUTypes() []string {
+	gpuTypes := make([]string, 0, len(gpuClasses))
+	for gpuType := range gpuClasses {
+		gpuTypes = append(gpuTypes, gpuType)
+	}
+	sort.Strings(gpuTypes)
+	return gpuTypes
+}
+
+// standardRegions maps standard regions to CoreWeave regions
+var standardRegions = map[string]string{
+	providers.RegionUSEast: "LGA1",
+	providers.RegionUSWest: "LAS1",
+}
+
+// regionCountries maps CoreWeave regions to the ISO 3166-1 alpha-2 code of the country they are in
+var regionCountries = map[string]string{
+	"ORD1": "US",
+	"LGA1": "US",
+	"LAS1": "US",
+}
+
+// supportedRegions returns the CoreWeave regions, sorted
+func supportedRegions() []string {
+	regions := make([]string, 0, len(regionCountries))
+	for region := range regionCountries {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+// gpuClassOffer returns the single-GPU on-demand offer for a GPU class in a region
+func gpuClassOffer(class GPUClass, region string) providers.GPUOffer {
+	return providers.GPUOffer{
+		ID:          fmt.Sprintf("%s-%s", strings.ToLower(region), strings.ToLower(class.Name)),
+		GPUType:     class.GPUType,
+		GPUCount:    1,
+		Region:      region,
+		HourlyPrice: class.HourlyPrice(1),
+		Memory:      int64(class.MemoryGiBPerGPU),
+		GPUMemory:   class.GPUMemoryGiB,
+		Available:   true,
+		Provider:    ProviderName,
+		Verified:    true,
+		Country:     regionCountries[region],
+		VCPUs:       class.VCPUsPerGPU,
+	}
+}
+
+// filterOffers applies the filters to offers. CoreWeave virtual servers are on-demand only.
+func filterOffers(offers []providers.GPUOffer, filters *providers.GPUFilters) []providers.GPUOffer {
+	var filtered []providers.GPUOffer
+
+	for _, offer := range offers {
+		if filters.GPUType != "" {
+			class, err := lookupGPUClass(filters.GPUType)
+			if err != nil || class.GPUType != offer.GPUType {
+				continue
+			}
+		}
+
+		if !providers.CountryAllowed(offer.Country, filters.Countries) {
+			continue
+		}
+
+		if filters.MaxPrice > 0 && offer.HourlyPrice > filters.MaxPrice {
+			continue
+		}
+
+		if filters.MinMemory > 0 && offer.Memory < filters.MinMemory {
+			continue
+		}
+
+		if filters.SpotOnly {
+			continue
+		}
+
+		if !providers.VCPUsPerGPUAllowed(offer, filters.MinVCPUPerGPU) {
+			continue
+		}
+
+		if !providers.GPUMemoryAllowed(offer, filters.MinGPUMemoryGiB) {
+			continue
+		}
+
+		filtered = append(filtered, offer)
+	}
+
+	return filtered
+}
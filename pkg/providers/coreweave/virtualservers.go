@@ -0,0 +1,176 @@
+package coreweave
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// virtualServerResource is the CoreWeave VirtualServer custom resource instances are created as
+var virtualServerResource = schema.GroupVersionResource{
+	Group:    "virtualservers.coreweave.com",
+	Version:  "v1alpha1",
+	Resource: "virtualservers",
+}
+
+const (
+	// defaultRootDiskGiB is the size of the root disk cloned from the Talos image
+	defaultRootDiskGiB = 40
+
+	// minRootDiskGiB is the smallest root disk a Talos node can install to
+	minRootDiskGiB = 10
+
+	// tagAnnotationPrefix prefixes the annotations carrying the launch's cost-allocation tags
+	tagAnnotationPrefix = "tags.tgp.io/"
+)
+
+// generateServerName returns a unique virtual server name for a launch
+func generateServerName(req *providers.LaunchRequest) string {
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	gpuType := strings.ToLower(strings.ReplaceAll(req.GPUType, "_", "-"))
+	return fmt.Sprintf("tgp-%s-%s", gpuType, hex.EncodeToString(suffix))
+}
+
+// imageSource returns the namespace and name of the PVC holding the Talos image for a launch.
+// "namespace/name" references a PVC in another namespace, a bare name one in the tenant
+// namespace, and an empty image or "talos" the tenant's PVC named talos.
+func imageSource(image, namespace string) (string, string) {
+	if image == "" || strings.EqualFold(image, "talos") {
+		return namespace, "talos"
+	}
+	if sourceNamespace, name, ok := strings.Cut(image, "/"); ok {
+		return sourceNamespace, name
+	}
+	return namespace, image
+}
+
+// buildVirtualServer returns the VirtualServer for a launch: a Talos root disk cloned from
+// the image PVC, the GPU class's CPU and memory for each GPU, a public IP and the user data
+// as cloud-init
+func buildVirtualServer(name, namespace, region string, class GPUClass, req *providers.LaunchRequest) (*unstructured.Unstructured, error) {
+	gpuCount := req.RequestedGPUs()
+	sourceNamespace, sourceName := imageSource(req.Image, namespace)
+	rootDiskGiB, err := req.BootDiskSize(defaultRootDiskGiB, minRootDiskGiB)
+	if err != nil {
+		return nil, err
+	}
+
+	storage := map[string]interface{}{
+		"root": map[string]interface{}{
+			"size":             fmt.Sprintf("%dGi", rootDiskGiB),
+			"storageClassName": "block-nvme-" + strings.ToLower(region),
+			"source": map[string]interface{}{
+				"pvc": map[string]interface{}{
+					"namespace": sourceNamespace,
+					"name":      sourceName,
+				},
+			},
+		},
+	}
+	if len(req.DataDisks) > 0 {
+		disks := make([]interface{}, 0, len(req.DataDisks))
+		for _, disk := range req.DataDisks {
+			disks = append(disks, map[string]interface{}{
+				"name": disk.VolumeID,
+				"spec": map[string]interface{}{
+					"persistentVolumeClaim": map[string]interface{}{
+						"claimName": disk.VolumeID,
+						"readOnly":  disk.ReadOnly,
+					},
+				},
+			})
+		}
+		storage["additionalDisks"] = disks
+	}
+
+	server := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": virtualServerResource.GroupVersion().String(),
+		"kind":       "VirtualServer",
+		"spec": map[string]interface{}{
+			"region": region,
+			"os": map[string]interface{}{
+				"type": "linux",
+			},
+			"resources": map[string]interface{}{
+				"gpu": map[string]interface{}{
+					"type":  class.Name,
+					"count": int64(gpuCount),
+				},
+				"cpu": map[string]interface{}{
+					"count": int64(class.VCPUsPerGPU * gpuCount),
+				},
+				"memory": fmt.Sprintf("%dGi", class.MemoryGiBPerGPU*gpuCount),
+			},
+			"storage": storage,
+			"network": map[string]interface{}{
+				"public": true,
+			},
+			"cloudInit":         req.UserData,
+			"initializeRunning": true,
+		},
+	}}
+	server.SetName(name)
+	server.SetNamespace(namespace)
+
+	labels := map[string]string{"app.kubernetes.io/managed-by": "tgp-operator"}
+	for key, value := range req.Labels {
+		if len(validation.IsQualifiedName(key)) > 0 || len(validation.IsValidLabelValue(value)) > 0 {
+			return nil, fmt.Errorf("label %s=%s is not a valid Kubernetes label", key, value)
+		}
+		labels[key] = value
+	}
+	server.SetLabels(labels)
+
+	if len(req.Tags) > 0 {
+		annotations := make(map[string]string, len(req.Tags))
+		for key, value := range req.Tags {
+			annotations[tagAnnotationPrefix+key] = value
+		}
+		server.SetAnnotations(annotations)
+	}
+	return server, nil
+}
+
+// serverAddresses returns the public and private IPs of a virtual server once assigned
+func serverAddresses(server *unstructured.Unstructured) (string, string) {
+	publicIP, _, _ := unstructured.NestedString(server.Object, "status", "network", "externalIP")
+	privateIP, _, _ := unstructured.NestedString(server.Object, "status", "network", "internalIP")
+	return publicIP, privateIP
+}
+
+// mapServerState maps a virtual server's Ready condition to an instance state and the
+// condition's message
+func mapServerState(server *unstructured.Unstructured) (providers.InstanceState, string) {
+	if server.GetDeletionTimestamp() != nil {
+		return providers.InstanceStateTerminating, ""
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(server.Object, "status", "conditions")
+	for _, raw := range conditions {
+		condition, ok := raw.(map[string]interface{})
+		if !ok || condition["type"] != "Ready" {
+			continue
+		}
+		message, _ := condition["message"].(string)
+		reason, _ := condition["reason"].(string)
+		switch {
+		case condition["status"] == "True":
+			return providers.InstanceStateRunning, message
+		case strings.Contains(reason, "Failed") || strings.Contains(reason, "Error"):
+			return providers.InstanceStateFailed, message
+		case strings.Contains(reason, "Stopped"):
+			return providers.InstanceStateTerminated, message
+		default:
+			return providers.InstanceStatePending, message
+		}
+	}
+	return providers.InstanceStatePending, ""
+}
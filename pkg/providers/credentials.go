@@ -0,0 +1,30 @@
+package providers
+
+import (
+	"context"
+	"time"
+)
+
+// CredentialCheckTimeout bounds the authenticated call made to check a provider's credentials
+const CredentialCheckTimeout = 10 * time.Second
+
+// CredentialChecker is implemented by providers with a cheap authenticated API call, such
+// as listing regions or reading the account, so invalid credentials are caught before a launch.
+type CredentialChecker interface {
+	// CheckCredentials makes an authenticated call and returns its error
+	CheckCredentials(ctx context.Context) error
+}
+
+// CheckCredentials verifies the client's credentials against the provider API within
+// CredentialCheckTimeout. It reports whether a check was made; providers without a
+// credential check are not contacted.
+func CheckCredentials(ctx context.Context, client ProviderClient) (bool, error) {
+	checker, ok := Unwrap(client).(CredentialChecker)
+	if !ok {
+		return false, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, CredentialCheckTimeout)
+	defer cancel()
+	return true, checker.CheckCredentials(ctx)
+}
@@ -0,0 +1,42 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// checkedClient answers credential checks with err
+type checkedClient struct {
+	flakyClient
+	err      error
+	deadline time.Time
+}
+
+func (c *checkedClient) CheckCredentials(ctx context.Context) error {
+	c.deadline, _ = ctx.Deadline()
+	return c.err
+}
+
+func TestCheckCredentials(t *testing.T) {
+	ctx := context.Background()
+
+	if checked, err := CheckCredentials(ctx, &flakyClient{}); checked || err != nil {
+		t.Errorf("expected providers without a check to be skipped, got checked=%v err=%v", checked, err)
+	}
+
+	raw := &checkedClient{}
+	client := NewCircuitBreakers(3, time.Minute).Wrap(raw)
+	if checked, err := CheckCredentials(ctx, client); !checked || err != nil {
+		t.Errorf("expected a passing check through the wrapper, got checked=%v err=%v", checked, err)
+	}
+	if raw.deadline.IsZero() || time.Until(raw.deadline) > CredentialCheckTimeout {
+		t.Errorf("expected the check to be bounded by %v, got deadline %v", CredentialCheckTimeout, raw.deadline)
+	}
+
+	raw.err = errors.New("401 unauthorized")
+	if checked, err := CheckCredentials(ctx, client); !checked || !errors.Is(err, raw.err) {
+		t.Errorf("expected the check error, got checked=%v err=%v", checked, err)
+	}
+}
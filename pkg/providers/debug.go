@@ -0,0 +1,156 @@
+package providers
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// maxLoggedBodyBytes caps how much of each request and response body is logged
+const maxLoggedBodyBytes = 16 * 1024
+
+const redacted = "[REDACTED]"
+
+// sensitiveHeaders are request and response headers whose values are never logged
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"X-Api-Key":           true,
+	"Api-Key":             true,
+}
+
+var (
+	// sensitiveFields matches JSON string fields that carry credentials or node secrets
+	sensitiveFields = regexp.MustCompile(`("(?:user_data|userData|customData|cloudInit|password|adminPassword|private_key|access_token|refresh_token|api_key|apiKey|token|secret)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	// sensitiveMetadata matches GCP metadata items carrying the node's user data
+	sensitiveMetadata = regexp.MustCompile(`("key"\s*:\s*"user-data"\s*,\s*"value"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	// sensitiveFormField matches form-encoded parameters, such as the EC2 query API's UserData,
+	// that carry credentials or node secrets
+	sensitiveFormField = regexp.MustCompile(`(?i)(?:^|\.)(?:UserData|Password|Token|Secret|PrivateKey)$`)
+)
+
+// DebugLogger is implemented by providers that can log their raw API exchanges
+type DebugLogger interface {
+	EnableDebugLogging(log logr.Logger)
+}
+
+// EnableDebugLogging turns on API request/response logging for providers that support it.
+// It reports whether the provider supports debug logging.
+func EnableDebugLogging(client ProviderClient, log logr.Logger) bool {
+	if debugLogger, ok := Unwrap(client).(DebugLogger); ok {
+		debugLogger.EnableDebugLogging(log)
+		return true
+	}
+	return false
+}
+
+// debugTransport logs each request and response at debug level, redacting credentials
+type debugTransport struct {
+	base http.RoundTripper
+	log  logr.Logger
+}
+
+// NewDebugTransport wraps base so every API exchange is logged at debug level with
+// credentials redacted. A nil base uses http.DefaultTransport.
+func NewDebugTransport(base http.RoundTripper, log logr.Logger) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &debugTransport{base: base, log: log}
+}
+
+// RoundTrip logs the request, forwards it and logs the response
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	log := t.log.V(1)
+	if !log.Enabled() {
+		return t.base.RoundTrip(req)
+	}
+
+	var requestBody []byte
+	if req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			requestBody, _ = io.ReadAll(io.LimitReader(body, maxLoggedBodyBytes))
+			_ = body.Close()
+		}
+	}
+	log.Info("Provider API request",
+		"method", req.Method,
+		"url", req.URL.Redacted(),
+		"headers", redactHeaders(req.Header),
+		"body", redactBody(requestBody, req.Header.Get("Content-Type")))
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		log.Info("Provider API request failed", "method", req.Method, "url", req.URL.Redacted(),
+			"duration", time.Since(start).String(), "error", err.Error())
+		return resp, err
+	}
+
+	var responseBody []byte
+	if resp.Body != nil {
+		responseBody, err = io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+	}
+	if len(responseBody) > maxLoggedBodyBytes {
+		responseBody = responseBody[:maxLoggedBodyBytes]
+	}
+	log.Info("Provider API response",
+		"method", req.Method,
+		"url", req.URL.Redacted(),
+		"status", resp.StatusCode,
+		"duration", time.Since(start).String(),
+		"headers", redactHeaders(resp.Header),
+		"body", redactBody(responseBody, resp.Header.Get("Content-Type")))
+
+	return resp, nil
+}
+
+// redactHeaders returns a copy of the headers with credential values replaced
+func redactHeaders(headers http.Header) map[string]string {
+	result := make(map[string]string, len(headers))
+	for name, values := range headers {
+		if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+			result[name] = redacted
+			continue
+		}
+		if len(values) > 0 {
+			result[name] = values[0]
+		}
+	}
+	return result
+}
+
+// redactBody returns a request or response body with credentials and user data replaced
+func redactBody(body []byte, contentType string) string {
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType == "application/x-www-form-urlencoded" {
+		return redactForm(body)
+	}
+	redactedBody := sensitiveFields.ReplaceAll(body, []byte(`${1}"`+redacted+`"`))
+	redactedBody = sensitiveMetadata.ReplaceAll(redactedBody, []byte(`${1}"`+redacted+`"`))
+	return string(redactedBody)
+}
+
+// redactForm returns a form-encoded body with credential and user data parameters replaced.
+// Parameters that fail to decode, such as one cut off by the logged body limit, are dropped.
+func redactForm(body []byte) string {
+	values, _ := url.ParseQuery(string(body))
+	for key := range values {
+		if sensitiveFormField.MatchString(key) {
+			values[key] = []string{redacted}
+		}
+	}
+	return values.Encode()
+}
@@ -0,0 +1,112 @@
+package providers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+)
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		want        string
+		secret      string
+	}{
+		{
+			name:   "vultr user data",
+			body:   `{"region":"ewr","user_data":"c2VjcmV0LWNvbmZpZw=="}`,
+			want:   `{"region":"ewr","user_data":"[REDACTED]"}`,
+			secret: "c2VjcmV0LWNvbmZpZw==",
+		},
+		{
+			name:   "gcp metadata user data",
+			body:   `{"metadata":{"items":[{"key":"user-data","value":"machine:\n  token: \"abc\""}]}}`,
+			want:   `{"metadata":{"items":[{"key":"user-data","value":"[REDACTED]"}]}}`,
+			secret: "abc",
+		},
+		{
+			name:   "azure custom data and admin password",
+			body:   `{"osProfile":{"adminUsername":"tgp","adminPassword":"hunter2","customData":"bWFjaGluZTo="}}`,
+			want:   `{"osProfile":{"adminUsername":"tgp","adminPassword":"[REDACTED]","customData":"[REDACTED]"}}`,
+			secret: "hunter2",
+		},
+		{
+			name:   "coreweave cloud init",
+			body:   `{"spec":{"cloudInit":"machine:\n  token: \"abc\""}}`,
+			want:   `{"spec":{"cloudInit":"[REDACTED]"}}`,
+			secret: "abc",
+		},
+		{
+			name:   "access token",
+			body:   `{"access_token": "ya29.secret", "expires_in": 3599}`,
+			want:   `{"access_token": "[REDACTED]", "expires_in": 3599}`,
+			secret: "ya29.secret",
+		},
+		{
+			name:        "aws query user data",
+			body:        "Action=RunInstances&InstanceType=p4d.24xlarge&UserData=bWFjaGluZTogdG9rZW4%3D&Version=2016-11-15",
+			contentType: "application/x-www-form-urlencoded; charset=utf-8",
+			want:        "Action=RunInstances&InstanceType=p4d.24xlarge&UserData=%5BREDACTED%5D&Version=2016-11-15",
+			secret:      "bWFjaGluZTogdG9rZW4",
+		},
+		{
+			name: "no credentials",
+			body: `{"plans":[{"id":"vcg-a100-1c-6g-4vram"}]}`,
+			want: `{"plans":[{"id":"vcg-a100-1c-6g-4vram"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := redactBody([]byte(tt.body), tt.contentType)
+			if got != tt.want {
+				t.Errorf("redactBody() = %s, want %s", got, tt.want)
+			}
+			if tt.secret != "" && strings.Contains(got, tt.secret) {
+				t.Errorf("redactBody() leaked %q", tt.secret)
+			}
+		})
+	}
+}
+
+func TestDebugTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"error":"no capacity"}`)
+	}))
+	defer server.Close()
+
+	var logged []string
+	log := funcr.New(func(prefix, args string) {
+		logged = append(logged, args)
+	}, funcr.Options{Verbosity: 1})
+
+	client := &http.Client{Transport: NewDebugTransport(nil, log)}
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"plan":"a100"}`))
+	req.Header.Set("Authorization", "Bearer super-secret")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if string(body) != `{"error":"no capacity"}` {
+		t.Errorf("response body = %s, want it passed through unchanged", body)
+	}
+
+	output := strings.Join(logged, "\n")
+	if strings.Contains(output, "super-secret") {
+		t.Error("expected the authorization header to be redacted")
+	}
+	for _, want := range []string{`a100`, `no capacity`} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected debug log to contain %q, got: %s", want, output)
+		}
+	}
+}
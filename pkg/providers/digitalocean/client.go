@@ -0,0 +1,363 @@
+// Package digitalocean implements the provider client for DigitalOcean GPU droplets
+package digitalocean
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"github.com/go-logr/logr"
+	"golang.org/x/oauth2"
+
+	v1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+const ProviderName = "digitalocean"
+
+// Client implements the ProviderClient interface for DigitalOcean GPU droplets
+type Client struct {
+	token string
+	// transport replaces the default HTTP transport when debug logging is enabled
+	transport http.RoundTripper
+
+	mutex sync.Mutex
+	api   dropletAPI
+}
+
+// NewClient creates a new DigitalOcean provider client from an API token
+func NewClient(token string) (*Client, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, fmt.Errorf("DigitalOcean API token is required")
+	}
+	return &Client{token: token}, nil
+}
+
+// EnableDebugLogging logs every DigitalOcean API request and response at debug level
+func (c *Client) EnableDebugLogging(log logr.Logger) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.transport = providers.NewDebugTransport(nil, log)
+	c.api = nil
+}
+
+// dropletAPI returns the DigitalOcean API client, creating it on first use
+func (c *Client) dropletAPI() dropletAPI {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.api == nil {
+		httpClient := &http.Client{Transport: &oauth2.Transport{
+			Source: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: c.token}),
+			Base:   c.transport,
+		}}
+		c.api = &godoDropletAPI{client: godo.NewClient(httpClient)}
+	}
+	return c.api
+}
+
+// GetProviderInfo returns information about the DigitalOcean provider
+func (c *Client) GetProviderInfo() *providers.ProviderInfo {
+	return &providers.ProviderInfo{
+		Name:                  ProviderName,
+		APIVersion:            "v2",
+		SupportedRegions:      supportedRegions(),
+		SupportedGPUTypes:     supportedGPUTypes(),
+		SupportsSpotInstances: false,
+		SupportsMultiGPU:      true,
+		BillingGranularity:    providers.BillingPerSecond,
+		MinBillingPeriod:      time.Minute,
+		ReliabilityTier:       providers.ReliabilityTierEnterprise,
+	}
+}
+
+// GetRateLimits returns the rate limits for the DigitalOcean API
+func (c *Client) GetRateLimits() *providers.RateLimitInfo {
+	return &providers.RateLimitInfo{
+		RequestsPerSecond: 4,
+		RequestsPerMinute: 250,
+		BurstCapacity:     10,
+		BackoffStrategy:   "exponential",
+		ResetWindow:       time.Minute,
+	}
+}
+
+// CheckCredentials reads the account the API token belongs to, which fails when the token is invalid
+func (c *Client) CheckCredentials(ctx context.Context) error {
+	if err := c.dropletAPI().GetAccount(ctx); err != nil {
+		return fmt.Errorf("failed to get DigitalOcean account: %w", apiError(err))
+	}
+	return nil
+}
+
+// supportedRegions returns the DigitalOcean regions, sorted
+func supportedRegions() []string {
+	regions := make([]string, 0, len(regionCountries))
+	for region := range regionCountries {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+// ListAvailableGPUs returns the GPU droplet sizes matching the filters in each region they are offered in
+func (c *Client) ListAvailableGPUs(ctx context.Context, filters *providers.GPUFilters) ([]providers.GPUOffer, error) {
+	if filters == nil {
+		filters = &providers.GPUFilters{}
+	}
+	if filters.Region != "" {
+		region, err := c.TranslateRegion(filters.Region)
+		if err != nil {
+			return nil, err
+		}
+		regionFilters := *filters
+		regionFilters.Region = region
+		filters = &regionFilters
+	}
+
+	sizes, err := c.dropletAPI().ListSizes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list droplet sizes: %w", apiError(err))
+	}
+
+	var offers []providers.GPUOffer
+	for _, size := range sizes {
+		offers = append(offers, sizeOffers(size, filters)...)
+	}
+	return filterOffers(offers, filters), nil
+}
+
+// GetNormalizedPricing returns the hourly price of the smallest droplet size with the GPU type
+func (c *Client) GetNormalizedPricing(ctx context.Context, gpuType, region string) (*providers.NormalizedPricing, error) {
+	standard, err := standardGPUType(gpuType)
+	if err != nil {
+		return nil, err
+	}
+	if region != "" {
+		if region, err = c.TranslateRegion(region); err != nil {
+			return nil, err
+		}
+	}
+
+	sizes, err := c.dropletAPI().ListSizes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list droplet sizes: %w", apiError(err))
+	}
+	size, err := selectSize(sizes, standard, region, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	_, gpuCount := sizeGPUs(*size)
+	return &providers.NormalizedPricing{
+		PricePerHour:   size.PriceHourly,
+		PricePerSecond: size.PriceHourly / 3600,
+		Currency:       "USD",
+		BillingModel:   providers.BillingPerSecond,
+		LastUpdated:    time.Now(),
+		ProviderSpecific: map[string]interface{}{
+			"size":     size.Slug,
+			"gpuCount": gpuCount,
+		},
+	}, nil
+}
+
+// LaunchInstance creates a GPU droplet from the Talos image with the launch's user data
+func (c *Client) LaunchInstance(ctx context.Context, req *providers.LaunchRequest) (*providers.GPUInstance, error) {
+	if req.SpotInstance {
+		return nil, fmt.Errorf("digitalocean does not offer spot droplets")
+	}
+	gpuType, err := standardGPUType(req.GPUType)
+	if err != nil {
+		return nil, err
+	}
+	region := ""
+	if req.Region != "" {
+		if region, err = c.TranslateRegion(req.Region); err != nil {
+			return nil, err
+		}
+	}
+
+	api := c.dropletAPI()
+	sizes, err := api.ListSizes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list droplet sizes: %w", apiError(err))
+	}
+	size, err := selectSize(sizes, gpuType, region, req.RequestedGPUs())
+	if err != nil {
+		return nil, err
+	}
+	if region == "" {
+		region = size.Regions[0]
+	}
+	// The boot disk comes with the size and cannot be resized at creation
+	if req.BootDiskGiB > size.Disk {
+		return nil, fmt.Errorf("size %s has a %dGiB boot disk, smaller than the requested %dGiB", size.Slug, size.Disk, req.BootDiskGiB)
+	}
+
+	image, err := c.resolveImage(ctx, api, req.Image)
+	if err != nil {
+		return nil, err
+	}
+
+	createReq := &godo.DropletCreateRequest{
+		Name:     generateDropletName(req),
+		Region:   region,
+		Size:     size.Slug,
+		Image:    image,
+		UserData: req.UserData,
+		Tags:     formatTags(req.Labels, req.Tags),
+	}
+	for _, disk := range req.DataDisks {
+		if disk.ReadOnly {
+			return nil, fmt.Errorf("digitalocean does not support attaching volume %s read-only", disk.VolumeID)
+		}
+		createReq.Volumes = append(createReq.Volumes, godo.DropletCreateVolume{ID: disk.VolumeID})
+	}
+
+	droplet, err := api.CreateDroplet(ctx, createReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create droplet %s: %w", createReq.Name, apiError(err))
+	}
+
+	instance := &providers.GPUInstance{
+		ID:        strconv.Itoa(droplet.ID),
+		Status:    mapDropletStatus(droplet.Status),
+		CreatedAt: time.Now(),
+	}
+	if created, err := time.Parse(time.RFC3339, droplet.Created); err == nil {
+		instance.CreatedAt = created
+	}
+	instance.PublicIP, _ = droplet.PublicIPv4()
+	instance.PrivateIP, _ = droplet.PrivateIPv4()
+	return instance, nil
+}
+
+// TerminateInstance destroys a droplet. Droplets that no longer exist are treated as terminated.
+func (c *Client) TerminateInstance(ctx context.Context, instanceID string) error {
+	id, err := parseDropletID(instanceID)
+	if err != nil {
+		return err
+	}
+	if err := c.dropletAPI().DeleteDroplet(ctx, id); err != nil && !isNotFound(err) {
+		return fmt.Errorf("failed to delete droplet %s: %w", instanceID, apiError(err))
+	}
+	return nil
+}
+
+// GetInstanceStatus returns the current status of a droplet
+func (c *Client) GetInstanceStatus(ctx context.Context, instanceID string) (*providers.InstanceStatus, error) {
+	id, err := parseDropletID(instanceID)
+	if err != nil {
+		return nil, err
+	}
+	droplet, err := c.dropletAPI().GetDroplet(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get droplet %s: %w", instanceID, apiError(err))
+	}
+
+	status := &providers.InstanceStatus{
+		State:     mapDropletStatus(droplet.Status),
+		UpdatedAt: time.Now(),
+	}
+	status.PublicIP, _ = droplet.PublicIPv4()
+	status.PrivateIP, _ = droplet.PrivateIPv4()
+	return status, nil
+}
+
+// ListManagedInstances returns the droplets tagged as created by the operator, in the region
+// or in every region when it is empty
+func (c *Client) ListManagedInstances(ctx context.Context, region string) ([]providers.ManagedInstance, error) {
+	if region != "" {
+		var err error
+		if region, err = c.TranslateRegion(region); err != nil {
+			return nil, err
+		}
+	}
+
+	droplets, err := c.dropletAPI().ListDropletsByTag(ctx, managedTag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list droplets: %w", apiError(err))
+	}
+
+	instances := make([]providers.ManagedInstance, 0, len(droplets))
+	for _, droplet := range droplets {
+		if region != "" && (droplet.Region == nil || droplet.Region.Slug != region) {
+			continue
+		}
+		instance := providers.ManagedInstance{
+			GPUInstance: providers.GPUInstance{
+				ID:     strconv.Itoa(droplet.ID),
+				Status: mapDropletStatus(droplet.Status),
+			},
+			NodePool: tagValue(droplet.Tags, v1.NodeLabelNodePool),
+		}
+		instance.CreatedAt, _ = time.Parse(time.RFC3339, droplet.Created)
+		instance.PublicIP, _ = droplet.PublicIPv4()
+		instance.PrivateIP, _ = droplet.PrivateIPv4()
+		instances = append(instances, instance)
+	}
+	return instances, nil
+}
+
+// AttachDataDisk attaches an existing block storage volume to a droplet
+func (c *Client) AttachDataDisk(ctx context.Context, instanceID string, disk providers.DataDisk) error {
+	if disk.ReadOnly {
+		return fmt.Errorf("digitalocean does not support attaching volume %s read-only", disk.VolumeID)
+	}
+	id, err := parseDropletID(instanceID)
+	if err != nil {
+		return err
+	}
+	if err := c.dropletAPI().AttachVolume(ctx, disk.VolumeID, id); err != nil {
+		return fmt.Errorf("failed to attach volume %s: %w", disk.VolumeID, apiError(err))
+	}
+	return nil
+}
+
+// DetachDataDisk detaches a block storage volume from a droplet without deleting it
+func (c *Client) DetachDataDisk(ctx context.Context, instanceID, volumeID string) error {
+	id, err := parseDropletID(instanceID)
+	if err != nil {
+		return err
+	}
+	if err := c.dropletAPI().DetachVolume(ctx, volumeID, id); err != nil {
+		return fmt.Errorf("failed to detach volume %s: %w", volumeID, apiError(err))
+	}
+	return nil
+}
+
+// TranslateGPUType translates a standard GPU type to the single-GPU droplet size that provides it
+func (c *Client) TranslateGPUType(standard string) (string, error) {
+	gpuType, err := standardGPUType(standard)
+	if err != nil {
+		return "", err
+	}
+	return singleGPUSizes[gpuType], nil
+}
+
+// TranslateRegion translates standard regions to DigitalOcean regions. DigitalOcean region slugs are used directly.
+func (c *Client) TranslateRegion(standard string) (string, error) {
+	if region, exists := standardRegions[standard]; exists {
+		return region, nil
+	}
+	region := strings.ToLower(standard)
+	if _, exists := regionCountries[region]; exists {
+		return region, nil
+	}
+	return "", fmt.Errorf("unsupported region: %s", standard)
+}
+
+// RegionCountry returns the country a DigitalOcean region is in
+func (c *Client) RegionCountry(region string) (string, bool) {
+	country, ok := regionCountries[region]
+	return country, ok
+}
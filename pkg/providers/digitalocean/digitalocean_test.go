@@ -0,0 +1,351 @@
+package digitalocean
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/digitalocean/godo"
+
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// testSizes returns GPU droplet sizes like those the API lists, plus a size without GPUs
+func testSizes() []godo.Size {
+	return []godo.Size{
+		{Slug: "s-1vcpu-1gb", PriceHourly: 0.009, Vcpus: 1, Regions: []string{"nyc2"}, Available: true},
+		{
+			Slug: "gpu-h100x1-80gb", PriceHourly: 3.39, Vcpus: 20, Disk: 720, Available: true,
+			Regions: []string{"nyc2", "tor1"},
+			GPUInfo: &godo.GPUInfo{Count: 1, Model: "nvidia_h100", VRAM: &godo.VRAM{Amount: 80, Unit: "gib"}},
+		},
+		{
+			Slug: "gpu-h100x8-640gb", PriceHourly: 23.92, Vcpus: 160, Disk: 2046, Available: true,
+			Regions: []string{"nyc2"},
+			GPUInfo: &godo.GPUInfo{Count: 8, Model: "nvidia_h100", VRAM: &godo.VRAM{Amount: 80, Unit: "gib"}},
+		},
+		{Slug: "gpu-l40sx1-48gb", PriceHourly: 1.57, Vcpus: 8, Disk: 500, Available: true, Regions: []string{"ams3"}},
+		{Slug: "gpu-mi300x1-192gb", PriceHourly: 1.99, Vcpus: 20, Disk: 720, Available: false, Regions: []string{"nyc2"}},
+	}
+}
+
+// fakeDropletAPI records requests and returns canned responses
+type fakeDropletAPI struct {
+	images    []godo.Image
+	created   *godo.DropletCreateRequest
+	deleteErr error
+	deleted   []int
+	droplet   godo.Droplet
+	tagged    []godo.Droplet
+	listedTag string
+	accessErr error
+}
+
+func (f *fakeDropletAPI) ListSizes(ctx context.Context) ([]godo.Size, error) {
+	return testSizes(), nil
+}
+
+func (f *fakeDropletAPI) ListUserImages(ctx context.Context) ([]godo.Image, error) {
+	return f.images, nil
+}
+
+func (f *fakeDropletAPI) CreateDroplet(ctx context.Context, req *godo.DropletCreateRequest) (*godo.Droplet, error) {
+	f.created = req
+	return &godo.Droplet{
+		ID:      4242,
+		Status:  "new",
+		Created: "2026-01-02T03:04:05Z",
+		Networks: &godo.Networks{V4: []godo.NetworkV4{
+			{IPAddress: "203.0.113.30", Type: "public"},
+			{IPAddress: "10.116.0.2", Type: "private"},
+		}},
+	}, nil
+}
+
+func (f *fakeDropletAPI) GetDroplet(ctx context.Context, id int) (*godo.Droplet, error) {
+	return &f.droplet, nil
+}
+
+func (f *fakeDropletAPI) ListDropletsByTag(ctx context.Context, tag string) ([]godo.Droplet, error) {
+	f.listedTag = tag
+	return f.tagged, nil
+}
+
+func (f *fakeDropletAPI) DeleteDroplet(ctx context.Context, id int) error {
+	f.deleted = append(f.deleted, id)
+	return f.deleteErr
+}
+
+func (f *fakeDropletAPI) AttachVolume(ctx context.Context, volumeID string, dropletID int) error {
+	return nil
+}
+
+func (f *fakeDropletAPI) DetachVolume(ctx context.Context, volumeID string, dropletID int) error {
+	return nil
+}
+
+func (f *fakeDropletAPI) GetAccount(ctx context.Context) error {
+	return f.accessErr
+}
+
+// newTestClient returns a client whose API calls go to fake
+func newTestClient(t *testing.T, fake *fakeDropletAPI) *Client {
+	t.Helper()
+	client, err := NewClient("test-token")
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	client.api = fake
+	return client
+}
+
+func TestNewClient(t *testing.T) {
+	if _, err := NewClient("  "); err == nil {
+		t.Error("Expected error for empty token")
+	}
+	client := newTestClient(t, &fakeDropletAPI{})
+	if info := client.GetProviderInfo(); info.Name != ProviderName || info.SupportsSpotInstances {
+		t.Errorf("Unexpected provider info: %+v", info)
+	}
+}
+
+func TestTranslateGPUType(t *testing.T) {
+	client := newTestClient(t, &fakeDropletAPI{})
+
+	tests := map[string]string{
+		"H100":        "gpu-h100x1-80gb",
+		"NVIDIA_L40S": "gpu-l40sx1-48gb",
+		"rtx4000ada":  "gpu-4000adax1-20gb",
+		"MI300X":      "gpu-mi300x1-192gb",
+	}
+	for gpuType, expected := range tests {
+		got, err := client.TranslateGPUType(gpuType)
+		if err != nil || got != expected {
+			t.Errorf("TranslateGPUType(%s) = %s, %v, expected %s", gpuType, got, err, expected)
+		}
+	}
+
+	if _, err := client.TranslateGPUType("T4"); err == nil {
+		t.Error("Expected error for GPU type DigitalOcean does not offer")
+	}
+}
+
+func TestTranslateRegion(t *testing.T) {
+	client := newTestClient(t, &fakeDropletAPI{})
+
+	tests := map[string]string{
+		providers.RegionUSEast:    "nyc2",
+		providers.RegionEUCentral: "ams3",
+		"TOR1":                    "tor1",
+	}
+	for standard, expected := range tests {
+		got, err := client.TranslateRegion(standard)
+		if err != nil || got != expected {
+			t.Errorf("TranslateRegion(%s) = %s, %v, expected %s", standard, got, err, expected)
+		}
+	}
+
+	if _, err := client.TranslateRegion("moon"); err == nil {
+		t.Error("Expected error for unknown region")
+	}
+}
+
+func TestListAvailableGPUs(t *testing.T) {
+	client := newTestClient(t, &fakeDropletAPI{})
+
+	offers, err := client.ListAvailableGPUs(context.Background(), &providers.GPUFilters{GPUType: "H100"})
+	if err != nil {
+		t.Fatalf("ListAvailableGPUs failed: %v", err)
+	}
+	if len(offers) != 3 {
+		t.Fatalf("Expected H100 offers for each size and region, got %d", len(offers))
+	}
+	for _, offer := range offers {
+		if offer.GPUType != "NVIDIA_H100" || offer.GPUMemory != 80 || offer.Provider != ProviderName {
+			t.Errorf("Unexpected offer: %+v", offer)
+		}
+	}
+
+	offers, err = client.ListAvailableGPUs(context.Background(), &providers.GPUFilters{Countries: []string{"CA"}})
+	if err != nil {
+		t.Fatalf("ListAvailableGPUs failed: %v", err)
+	}
+	if len(offers) != 1 || offers[0].Region != "tor1" {
+		t.Errorf("Expected only the Toronto offer, got %+v", offers)
+	}
+
+	offers, err = client.ListAvailableGPUs(context.Background(), &providers.GPUFilters{SpotOnly: true})
+	if err != nil {
+		t.Fatalf("ListAvailableGPUs failed: %v", err)
+	}
+	if len(offers) != 0 {
+		t.Errorf("Expected no spot offers, got %d", len(offers))
+	}
+}
+
+func TestSelectSize(t *testing.T) {
+	sizes := testSizes()
+
+	size, err := selectSize(sizes, "NVIDIA_H100", "", 1)
+	if err != nil || size.Slug != "gpu-h100x1-80gb" {
+		t.Errorf("Expected single-GPU size, got %v, %v", size, err)
+	}
+	size, err = selectSize(sizes, "NVIDIA_H100", "nyc2", 4)
+	if err != nil || size.Slug != "gpu-h100x8-640gb" {
+		t.Errorf("Expected 8-GPU size for 4 GPUs, got %v, %v", size, err)
+	}
+
+	if _, err := selectSize(sizes, "NVIDIA_H100", "", 16); err == nil || !strings.Contains(err.Error(), "the largest has 8") {
+		t.Errorf("Expected error naming the largest size, got %v", err)
+	}
+	if _, err := selectSize(sizes, "NVIDIA_H100", "tor1", 8); err == nil {
+		t.Error("Expected error for GPU count not offered in the region")
+	}
+	if _, err := selectSize(sizes, "AMD_MI300X", "", 1); err == nil {
+		t.Error("Expected error for unavailable size")
+	}
+	if _, err := selectSize(sizes, "NVIDIA_H100", "ams3", 1); !errors.Is(err, providers.ErrNotAvailableInRegion) {
+		t.Errorf("Expected a not available in region error, got %v", err)
+	}
+}
+
+func TestParseGPUSlug(t *testing.T) {
+	tests := []struct {
+		slug  string
+		model string
+		count int
+		ok    bool
+	}{
+		{"gpu-h100x8-640gb", "h100", 8, true},
+		{"gpu-4000adax1-20gb", "4000ada", 1, true},
+		{"s-2vcpu-4gb", "", 0, false},
+		{"gpu-h100", "", 0, false},
+	}
+	for _, tt := range tests {
+		model, count, ok := parseGPUSlug(tt.slug)
+		if model != tt.model || count != tt.count || ok != tt.ok {
+			t.Errorf("parseGPUSlug(%s) = %s, %d, %v, expected %s, %d, %v", tt.slug, model, count, ok, tt.model, tt.count, tt.ok)
+		}
+	}
+}
+
+func TestLaunchInstance(t *testing.T) {
+	fake := &fakeDropletAPI{images: []godo.Image{
+		{ID: 1, Name: "talos-v1.9.0", Status: "available", Created: "2025-01-01T00:00:00Z"},
+		{ID: 2, Name: "talos-v1.10.5", Status: "available", Created: "2025-06-01T00:00:00Z"},
+		{ID: 3, Name: "talos-v1.11.0", Status: "pending", Created: "2025-09-01T00:00:00Z"},
+		{ID: 4, Name: "ubuntu", Status: "available", Created: "2025-10-01T00:00:00Z"},
+	}}
+	client := newTestClient(t, fake)
+
+	instance, err := client.LaunchInstance(context.Background(), &providers.LaunchRequest{
+		GPUType:   "H100",
+		GPUCount:  2,
+		Image:     "talos",
+		UserData:  "machine: config",
+		Labels:    map[string]string{"tgp.io/nodepool": "pool"},
+		Tags:      map[string]string{"team": "ml"},
+		DataDisks: []providers.DataDisk{{VolumeID: "vol-1"}},
+	})
+	if err != nil {
+		t.Fatalf("LaunchInstance failed: %v", err)
+	}
+
+	if instance.ID != "4242" || instance.Status != providers.InstanceStatePending {
+		t.Errorf("Unexpected instance: %+v", instance)
+	}
+	if instance.PublicIP != "203.0.113.30" || instance.PrivateIP != "10.116.0.2" {
+		t.Errorf("Unexpected addresses: %s %s", instance.PublicIP, instance.PrivateIP)
+	}
+
+	created := fake.created
+	if created.Size != "gpu-h100x8-640gb" || created.Region != "nyc2" {
+		t.Errorf("Expected 8-GPU size in its region, got %s in %s", created.Size, created.Region)
+	}
+	if created.Image.ID != 2 {
+		t.Errorf("Expected newest available Talos image, got %+v", created.Image)
+	}
+	if created.UserData != "machine: config" {
+		t.Errorf("Expected user data to be passed through, got %q", created.UserData)
+	}
+	expectedTags := []string{"tgp-operator", "team:ml", "tgp_io_nodepool:pool"}
+	if strings.Join(created.Tags, ",") != strings.Join(expectedTags, ",") {
+		t.Errorf("Expected tags %v, got %v", expectedTags, created.Tags)
+	}
+	if len(created.Volumes) != 1 || created.Volumes[0].ID != "vol-1" {
+		t.Errorf("Expected data volume to be attached, got %+v", created.Volumes)
+	}
+
+	if _, err := client.LaunchInstance(context.Background(), &providers.LaunchRequest{GPUType: "H100", SpotInstance: true}); err == nil {
+		t.Error("Expected error for spot launch")
+	}
+}
+
+func TestTerminateInstance(t *testing.T) {
+	notFound := &godo.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+	fake := &fakeDropletAPI{deleteErr: notFound}
+	client := newTestClient(t, fake)
+
+	if err := client.TerminateInstance(context.Background(), "4242"); err != nil {
+		t.Errorf("Expected deleted droplet to count as terminated, got %v", err)
+	}
+	if len(fake.deleted) != 1 || fake.deleted[0] != 4242 {
+		t.Errorf("Expected droplet 4242 to be deleted, got %v", fake.deleted)
+	}
+	if err := client.TerminateInstance(context.Background(), "tgp-abc"); err == nil {
+		t.Error("Expected error for non-numeric droplet ID")
+	}
+
+	fake.deleteErr = &godo.ErrorResponse{Response: &http.Response{StatusCode: http.StatusPaymentRequired}}
+	if err := client.TerminateInstance(context.Background(), "4242"); !providers.IsBillingError(err) {
+		t.Errorf("Expected a billing error, got %v", err)
+	}
+}
+
+func TestGetInstanceStatus(t *testing.T) {
+	fake := &fakeDropletAPI{droplet: godo.Droplet{ID: 4242, Status: "active", Networks: &godo.Networks{}}}
+	client := newTestClient(t, fake)
+
+	status, err := client.GetInstanceStatus(context.Background(), "4242")
+	if err != nil {
+		t.Fatalf("GetInstanceStatus failed: %v", err)
+	}
+	if status.State != providers.InstanceStateRunning {
+		t.Errorf("Expected running droplet, got %s", status.State)
+	}
+}
+
+func TestListManagedInstances(t *testing.T) {
+	fake := &fakeDropletAPI{tagged: []godo.Droplet{
+		{
+			ID:      1,
+			Status:  "active",
+			Created: "2026-01-02T03:04:05Z",
+			Region:  &godo.Region{Slug: "nyc2"},
+			Tags:    []string{"tgp-operator", "tgp_io_nodepool:training"},
+		},
+		{ID: 2, Status: "new", Region: &godo.Region{Slug: "tor1"}, Tags: []string{"tgp-operator"}},
+	}}
+	client := newTestClient(t, fake)
+
+	instances, err := providers.ListManagedInstances(context.Background(), client, "")
+	if err != nil {
+		t.Fatalf("ListManagedInstances failed: %v", err)
+	}
+	if fake.listedTag != "tgp-operator" {
+		t.Errorf("Expected droplets to be listed by the operator tag, got %q", fake.listedTag)
+	}
+	if len(instances) != 2 || instances[0].ID != "1" || instances[0].NodePool != "training" || instances[0].Status != providers.InstanceStateRunning {
+		t.Fatalf("Unexpected instances %+v", instances)
+	}
+	if instances[0].CreatedAt.IsZero() || instances[1].NodePool != "" {
+		t.Errorf("Unexpected instances %+v", instances)
+	}
+
+	instances, err = client.ListManagedInstances(context.Background(), "tor1")
+	if err != nil || len(instances) != 1 || instances[0].ID != "2" {
+		t.Errorf("Expected only the droplet in tor1, got %+v (err %v)", instances, err)
+	}
+}
@@ -0,0 +1,244 @@
+package digitalocean
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/digitalocean/godo"
+
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// dropletAPI is the subset of the DigitalOcean API used by the client. List calls return every page.
+type dropletAPI interface {
+	ListSizes(ctx context.Context) ([]godo.Size, error)
+	ListUserImages(ctx context.Context) ([]godo.Image, error)
+	CreateDroplet(ctx context.Context, req *godo.DropletCreateRequest) (*godo.Droplet, error)
+	GetDroplet(ctx context.Context, id int) (*godo.Droplet, error)
+	ListDropletsByTag(ctx context.Context, tag string) ([]godo.Droplet, error)
+	DeleteDroplet(ctx context.Context, id int) error
+	AttachVolume(ctx context.Context, volumeID string, dropletID int) error
+	DetachVolume(ctx context.Context, volumeID string, dropletID int) error
+	GetAccount(ctx context.Context) error
+}
+
+// godoDropletAPI implements dropletAPI with the godo client
+type godoDropletAPI struct {
+	client *godo.Client
+}
+
+func (a *godoDropletAPI) ListSizes(ctx context.Context) ([]godo.Size, error) {
+	var sizes []godo.Size
+	options := &godo.ListOptions{PerPage: 200}
+	for {
+		page, resp, err := a.client.Sizes.List(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		sizes = append(sizes, page...)
+		if resp == nil || resp.Links == nil || resp.Links.IsLastPage() {
+			return sizes, nil
+		}
+		current, err := resp.Links.CurrentPage()
+		if err != nil {
+			return nil, err
+		}
+		options.Page = current + 1
+	}
+}
+
+func (a *godoDropletAPI) ListUserImages(ctx context.Context) ([]godo.Image, error) {
+	var images []godo.Image
+	options := &godo.ListOptions{PerPage: 200}
+	for {
+		page, resp, err := a.client.Images.ListUser(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, page...)
+		if resp == nil || resp.Links == nil || resp.Links.IsLastPage() {
+			return images, nil
+		}
+		current, err := resp.Links.CurrentPage()
+		if err != nil {
+			return nil, err
+		}
+		options.Page = current + 1
+	}
+}
+
+func (a *godoDropletAPI) GetAccount(ctx context.Context) error {
+	_, _, err := a.client.Account.Get(ctx)
+	return err
+}
+
+func (a *godoDropletAPI) CreateDroplet(ctx context.Context, req *godo.DropletCreateRequest) (*godo.Droplet, error) {
+	droplet, _, err := a.client.Droplets.Create(ctx, req)
+	return droplet, err
+}
+
+func (a *godoDropletAPI) GetDroplet(ctx context.Context, id int) (*godo.Droplet, error) {
+	droplet, _, err := a.client.Droplets.Get(ctx, id)
+	return droplet, err
+}
+
+func (a *godoDropletAPI) ListDropletsByTag(ctx context.Context, tag string) ([]godo.Droplet, error) {
+	var droplets []godo.Droplet
+	options := &godo.ListOptions{PerPage: 200}
+	for {
+		page, resp, err := a.client.Droplets.ListByTag(ctx, tag, options)
+		if err != nil {
+			return nil, err
+		}
+		droplets = append(droplets, page...)
+		if resp == nil || resp.Links == nil || resp.Links.IsLastPage() {
+			return droplets, nil
+		}
+		current, err := resp.Links.CurrentPage()
+		if err != nil {
+			return nil, err
+		}
+		options.Page = current + 1
+	}
+}
+
+func (a *godoDropletAPI) DeleteDroplet(ctx context.Context, id int) error {
+	_, err := a.client.Droplets.Delete(ctx, id)
+	return err
+}
+
+func (a *godoDropletAPI) AttachVolume(ctx context.Context, volumeID string, dropletID int) error {
+	_, _, err := a.client.StorageActions.Attach(ctx, volumeID, dropletID)
+	return err
+}
+
+func (a *godoDropletAPI) DetachVolume(ctx context.Context, volumeID string, dropletID int) error {
+	_, _, err := a.client.StorageActions.DetachByDropletID(ctx, volumeID, dropletID)
+	return err
+}
+
+// isNotFound reports whether a DigitalOcean API error is a 404
+func isNotFound(err error) bool {
+	var apiErr *godo.ErrorResponse
+	return errors.As(err, &apiErr) && apiErr.Response != nil && apiErr.Response.StatusCode == http.StatusNotFound
+}
+
+// apiError classifies a DigitalOcean API error by its HTTP status
+func apiError(err error) error {
+	var apiErr *godo.ErrorResponse
+	if !errors.As(err, &apiErr) || apiErr.Response == nil {
+		return err
+	}
+	return providers.NewAPIError(ProviderName, nil, apiErr.Response.StatusCode, err)
+}
+
+// parseDropletID parses the numeric droplet ID used as the instance ID
+func parseDropletID(instanceID string) (int, error) {
+	id, err := strconv.Atoi(instanceID)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid DigitalOcean droplet ID: %q", instanceID)
+	}
+	return id, nil
+}
+
+// talosImageName matches the names of custom Talos images uploaded to the account
+var talosImageName = regexp.MustCompile(`(?i)^talos`)
+
+// resolveImage returns the image to create a droplet from: a numeric custom image ID, the
+// newest custom image whose name starts with "talos" when the launch asks for Talos, or an
+// image slug otherwise
+func (c *Client) resolveImage(ctx context.Context, api dropletAPI, image string) (godo.DropletCreateImage, error) {
+	if id, err := strconv.Atoi(image); err == nil {
+		return godo.DropletCreateImage{ID: id}, nil
+	}
+	if image != "" && !strings.EqualFold(image, "talos") {
+		return godo.DropletCreateImage{Slug: image}, nil
+	}
+
+	images, err := api.ListUserImages(ctx)
+	if err != nil {
+		return godo.DropletCreateImage{}, fmt.Errorf("failed to list custom images: %w", apiError(err))
+	}
+	var talos []godo.Image
+	for _, candidate := range images {
+		if talosImageName.MatchString(candidate.Name) && (candidate.Status == "" || candidate.Status == "available") {
+			talos = append(talos, candidate)
+		}
+	}
+	if len(talos) == 0 {
+		return godo.DropletCreateImage{}, fmt.Errorf("no custom Talos image found; upload one named talos-* or set the launch image to its ID")
+	}
+	sort.Slice(talos, func(i, j int) bool { return talos[i].Created > talos[j].Created })
+	return godo.DropletCreateImage{ID: talos[0].ID}, nil
+}
+
+// generateDropletName returns a unique droplet name for a launch
+func generateDropletName(req *providers.LaunchRequest) string {
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	gpuType := strings.ToLower(strings.ReplaceAll(req.GPUType, "_", "-"))
+	return fmt.Sprintf("tgp-%s-%s", gpuType, hex.EncodeToString(suffix))
+}
+
+// managedTag marks every droplet the operator creates
+const managedTag = "tgp-operator"
+
+// invalidTagChars matches characters DigitalOcean does not allow in tags
+var invalidTagChars = regexp.MustCompile(`[^a-zA-Z0-9_:\-]`)
+
+// maxTagLength is the longest tag DigitalOcean accepts
+const maxTagLength = 255
+
+// formatTags converts labels and cost-allocation tags to DigitalOcean's "key:value" tags,
+// replacing characters tags may not contain
+func formatTags(labels, tags map[string]string) []string {
+	formatted := []string{managedTag}
+	for _, source := range []map[string]string{tags, labels} {
+		keys := make([]string, 0, len(source))
+		for key := range source {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			tag := invalidTagChars.ReplaceAllString(key+":"+source[key], "_")
+			if len(tag) > maxTagLength {
+				tag = tag[:maxTagLength]
+			}
+			formatted = append(formatted, tag)
+		}
+	}
+	return formatted
+}
+
+// tagValue returns the value of the key:value tag formatTags created for the key, or ""
+func tagValue(tags []string, key string) string {
+	prefix := invalidTagChars.ReplaceAllString(key, "_") + ":"
+	for _, tag := range tags {
+		if value, found := strings.CutPrefix(tag, prefix); found {
+			return value
+		}
+	}
+	return ""
+}
+
+// mapDropletStatus maps a droplet status to an instance state
+func mapDropletStatus(status string) providers.InstanceState {
+	switch status {
+	case "new":
+		return providers.InstanceStatePending
+	case "active":
+		return providers.InstanceStateRunning
+	case "archive":
+		return providers.InstanceStateTerminated
+	default:
+		return providers.InstanceStateUnknown
+	}
+}
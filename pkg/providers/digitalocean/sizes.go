@@ -0,0 +1,264 @@
+package digitalocean
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/digitalocean/godo"
+
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// slugGPUTypes maps the GPU model in a GPU droplet size slug, like "h100" in
+// gpu-h100x1-80gb, to the standard GPU type
+var slugGPUTypes = map[string]string{
+	"h100":    "NVIDIA_H100",
+	"h200":    "NVIDIA_H200",
+	"l40s":    "NVIDIA_L40S",
+	"4000ada": "NVIDIA_RTX_4000_ADA",
+	"6000ada": "NVIDIA_RTX_6000_ADA",
+	"mi300x":  "AMD_MI300X",
+	"mi325x":  "AMD_MI325X",
+}
+
+// singleGPUSizes maps standard GPU types to the single-GPU droplet size providing them,
+// used to translate GPU types without querying the API
+var singleGPUSizes = map[string]string{
+	"NVIDIA_H100":         "gpu-h100x1-80gb",
+	"NVIDIA_H200":         "gpu-h200x1-141gb",
+	"NVIDIA_L40S":         "gpu-l40sx1-48gb",
+	"NVIDIA_RTX_4000_ADA": "gpu-4000adax1-20gb",
+	"NVIDIA_RTX_6000_ADA": "gpu-6000adax1-48gb",
+	"AMD_MI300X":          "gpu-mi300x1-192gb",
+	"AMD_MI325X":          "gpu-mi325x1-256gb",
+}
+
+// gpuTypeAliases maps the short and alternative standard GPU type names to singleGPUSizes keys
+var gpuTypeAliases = map[string]string{
+	"H100":             "NVIDIA_H100",
+	"NVIDIA_H100_80GB": "NVIDIA_H100",
+	"H200":             "NVIDIA_H200",
+	"L40S":             "NVIDIA_L40S",
+	"RTX4000ADA":       "NVIDIA_RTX_4000_ADA",
+	"RTX6000ADA":       "NVIDIA_RTX_6000_ADA",
+	"MI300X":           "AMD_MI300X",
+	"MI325X":           "AMD_MI325X",
+}
+
+// standardGPUType resolves a standard or alias GPU type name
+func standardGPUType(gpuType string) (string, error) {
+	key := strings.ToUpper(gpuType)
+	if alias, exists := gpuTypeAliases[key]; exists {
+		key = alias
+	}
+	if _, exists := singleGPUSizes[key]; !exists {
+		return "", fmt.Errorf("unsupported GPU type: %s", gpuType)
+	}
+	return key, nil
+}
+
+// supportedGPUTypes returns the standard GPU types DigitalOcean offers, sorted
+func supportedGPUTypes() []string {
+	gpuTypes := make([]string, 0, len(singleGPUSizes))
+	for gpuType := range singleGPUSizes {
+		gpuTypes = append(gpuTypes, gpuType)
+	}
+	sort.Strings(gpuTypes)
+	return gpuTypes
+}
+
+// sizeGPUs returns the standard GPU type and GPU count of a droplet size, or an empty type
+// for sizes without GPUs. The count comes from the size's GPU info, falling back to the slug.
+func sizeGPUs(size godo.Size) (string, int) {
+	model, count, ok := parseGPUSlug(size.Slug)
+	if !ok {
+		return "", 0
+	}
+	gpuType, exists := slugGPUTypes[model]
+	if !exists && size.GPUInfo != nil && size.GPUInfo.Model != "" {
+		gpuType = strings.ToUpper(size.GPUInfo.Model)
+	}
+	if gpuType == "" {
+		return "", 0
+	}
+	if size.GPUInfo != nil && size.GPUInfo.Count > 0 {
+		count = size.GPUInfo.Count
+	}
+	return gpuType, count
+}
+
+// parseGPUSlug splits a GPU droplet slug like gpu-h100x8-640gb into its model and GPU count
+func parseGPUSlug(slug string) (string, int, bool) {
+	parts := strings.Split(strings.ToLower(slug), "-")
+	if len(parts) < 2 || parts[0] != "gpu" {
+		return "", 0, false
+	}
+	x := strings.LastIndex(parts[1], "x")
+	if x <= 0 {
+		return "", 0, false
+	}
+	count, err := strconv.Atoi(parts[1][x+1:])
+	if err != nil {
+		return "", 0, false
+	}
+	return parts[1][:x], count, true
+}
+
+// sizeVRAMGiB returns the memory of each GPU in a droplet size, if the API reports it
+func sizeVRAMGiB(size godo.Size) int64 {
+	if size.GPUInfo == nil || size.GPUInfo.VRAM == nil {
+		return 0
+	}
+	vram := int64(size.GPUInfo.VRAM.Amount)
+	if strings.EqualFold(size.GPUInfo.VRAM.Unit, "mib") {
+		vram /= 1024
+	}
+	return vram
+}
+
+// selectSize picks the available size of the GPU type in the region with the fewest GPUs
+// that satisfies count, then the cheapest. An empty region matches any region.
+func selectSize(sizes []godo.Size, gpuType, region string, count int) (*godo.Size, error) {
+	var best *godo.Size
+	bestCount, largestCount := 0, 0
+	for i := range sizes {
+		size := &sizes[i]
+		sizeType, sizeCount := sizeGPUs(*size)
+		if sizeType != gpuType || !size.Available || (region != "" && !containsRegion(size.Regions, region)) {
+			continue
+		}
+
+		largestCount = max(largestCount, sizeCount)
+		if sizeCount < count {
+			continue
+		}
+		if best == nil || sizeCount < bestCount || (sizeCount == bestCount && size.PriceHourly < best.PriceHourly) {
+			best = size
+			bestCount = sizeCount
+		}
+	}
+
+	if best == nil {
+		if largestCount > 0 {
+			return nil, fmt.Errorf("no %s droplet size has %d GPUs, the largest has %d", gpuType, count, largestCount)
+		}
+		if region != "" {
+			return nil, fmt.Errorf("no %s droplet size in region %s: %w", gpuType, region, providers.ErrNotAvailableInRegion)
+		}
+		return nil, fmt.Errorf("no %s droplet size is available", gpuType)
+	}
+	return best, nil
+}
+
+func containsRegion(regions []string, region string) bool {
+	for _, candidate := range regions {
+		if candidate == region {
+			return true
+		}
+	}
+	return false
+}
+
+// standardRegions maps standard regions to DigitalOcean regions with GPU droplets
+var standardRegions = map[string]string{
+	providers.RegionUSEast:    "nyc2",
+	providers.RegionUSWest:    "sfo3",
+	providers.RegionEUCentral: "ams3",
+}
+
+// regionCountries maps DigitalOcean regions to the ISO 3166-1 alpha-2 code of the country they are in
+var regionCountries = map[string]string{
+	"nyc1": "US",
+	"nyc2": "US",
+	"nyc3": "US",
+	"atl1": "US",
+	"sfo2": "US",
+	"sfo3": "US",
+	"tor1": "CA",
+	"ams3": "NL",
+	"lon1": "GB",
+	"fra1": "DE",
+	"blr1": "IN",
+	"sgp1": "SG",
+	"syd1": "AU",
+}
+
+// sizeOffers returns the on-demand offers for a GPU droplet size in each of its regions
+// matching the filters. DigitalOcean has no spot droplets.
+func sizeOffers(size godo.Size, filters *providers.GPUFilters) []providers.GPUOffer {
+	gpuType, gpuCount := sizeGPUs(size)
+	if gpuType == "" {
+		return nil
+	}
+
+	var offers []providers.GPUOffer
+	for _, region := range size.Regions {
+		if filters.Region != "" && region != filters.Region {
+			continue
+		}
+		offers = append(offers, providers.GPUOffer{
+			ID:          fmt.Sprintf("%s-%s", region, size.Slug),
+			GPUType:     gpuType,
+			GPUCount:    gpuCount,
+			Region:      region,
+			HourlyPrice: size.PriceHourly,
+			Memory:      int64(size.Memory / 1024),
+			GPUMemory:   sizeVRAMGiB(size),
+			Storage:     int64(size.Disk),
+			Available:   size.Available,
+			Provider:    ProviderName,
+			Verified:    true,
+			Country:     regionCountries[region],
+			VCPUs:       size.Vcpus,
+		})
+	}
+	return offers
+}
+
+// filterOffers applies the filters to offers
+func filterOffers(offers []providers.GPUOffer, filters *providers.GPUFilters) []providers.GPUOffer {
+	var filtered []providers.GPUOffer
+
+	for _, offer := range offers {
+		if filters.GPUType != "" {
+			gpuType, err := standardGPUType(filters.GPUType)
+			if err != nil || gpuType != offer.GPUType {
+				continue
+			}
+		}
+
+		if !providers.CountryAllowed(offer.Country, filters.Countries) {
+			continue
+		}
+
+		if filters.MaxPrice > 0 && offer.HourlyPrice > filters.MaxPrice {
+			continue
+		}
+
+		if filters.MinMemory > 0 && offer.Memory < filters.MinMemory {
+			continue
+		}
+
+		if filters.MinStorage > 0 && offer.Storage < filters.MinStorage {
+			continue
+		}
+
+		if filters.SpotOnly {
+			continue
+		}
+
+		if !providers.VCPUsPerGPUAllowed(offer, filters.MinVCPUPerGPU) {
+			continue
+		}
+
+		if !providers.GPUMemoryAllowed(offer, filters.MinGPUMemoryGiB) {
+			continue
+		}
+
+		filtered = append(filtered, offer)
+	}
+
+	return filtered
+}
@@ -0,0 +1,85 @@
+package providers
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Kinds of provider API failures. Providers wrap the errors of their API calls in an
+// APIError so callers can tell them apart with errors.Is.
+var (
+	// ErrRateLimited is a request rejected because the provider's rate limit was reached
+	ErrRateLimited = errors.New("rate limited")
+	// ErrUnauthorized is a request rejected because the credentials are invalid or lack permission
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrNoCapacity is a launch that failed because the provider has no capacity for it
+	ErrNoCapacity = errors.New("no capacity")
+	// ErrBilling is a request rejected because of the account's billing status, such as
+	// insufficient funds or an unverified payment method
+	ErrBilling = errors.New("billing error")
+	// ErrNotFound is a request for an instance or resource that does not exist
+	ErrNotFound = errors.New("not found")
+)
+
+// APIError is a failed provider API call, classified into one of the error kinds
+type APIError struct {
+	// Provider is the name of the provider the call was made to
+	Provider string
+	// StatusCode is the HTTP status of the response, if there was one
+	StatusCode int
+	// Kind is one of the Err* error kinds, or nil when the failure is not classified
+	Kind error
+	// Err is the error returned by the provider's API client
+	Err error
+}
+
+func (e *APIError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error kind and the underlying error, so errors.Is matches the kind and
+// errors.As still finds the API client's own error types
+func (e *APIError) Unwrap() []error {
+	if e.Kind == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// NewAPIError wraps an API client error with its kind, deriving the kind from the HTTP status
+// when kind is nil. A nil err returns nil.
+func NewAPIError(provider string, kind error, statusCode int, err error) error {
+	if err == nil {
+		return nil
+	}
+	if kind == nil {
+		kind = ErrorKindForStatus(statusCode)
+	}
+	return &APIError{Provider: provider, StatusCode: statusCode, Kind: kind, Err: err}
+}
+
+// ErrorKindForStatus returns the error kind of an HTTP status, or nil when it has none
+func ErrorKindForStatus(statusCode int) error {
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	case http.StatusPaymentRequired:
+		return ErrBilling
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// IsBillingError reports whether err was caused by the account's billing status
+func IsBillingError(err error) bool {
+	return errors.Is(err, ErrBilling)
+}
+
+// isServerError reports whether err is an API error with a 5xx status
+func isServerError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode >= http.StatusInternalServerError
+}
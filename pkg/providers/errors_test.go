@@ -0,0 +1,65 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestNewAPIError(t *testing.T) {
+	if NewAPIError("test", nil, http.StatusTooManyRequests, nil) != nil {
+		t.Error("expected a nil error to stay nil")
+	}
+
+	cause := errors.New(`{"error":"Unauthorized","status":401}`)
+	err := fmt.Errorf("failed to list plans: %w", NewAPIError("test", nil, http.StatusUnauthorized, cause))
+	if !errors.Is(err, ErrUnauthorized) || !errors.Is(err, cause) {
+		t.Errorf("expected the error to match its kind and cause, got %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Provider != "test" || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected the API error to be found, got %+v", apiErr)
+	}
+	if err.Error() != "failed to list plans: "+cause.Error() {
+		t.Errorf("expected the cause's message, got %q", err.Error())
+	}
+
+	if err := NewAPIError("test", ErrNoCapacity, http.StatusConflict, cause); !errors.Is(err, ErrNoCapacity) {
+		t.Errorf("expected an explicit kind to override the status, got %v", err)
+	}
+	if err := NewAPIError("test", nil, http.StatusBadRequest, cause); errors.Is(err, ErrNotFound) || IsBillingError(err) {
+		t.Errorf("expected a 400 to be unclassified, got %v", err)
+	}
+	if !IsBillingError(NewAPIError("test", nil, http.StatusPaymentRequired, cause)) {
+		t.Error("expected a 402 to be a billing error")
+	}
+}
+
+func TestIsRetriableErrorKinds(t *testing.T) {
+	cause := errors.New("request failed")
+	tests := []struct {
+		name      string
+		err       error
+		retriable bool
+		errType   RetriableErrorType
+	}{
+		{"rate limited", NewAPIError("test", nil, http.StatusTooManyRequests, cause), true, RetriableErrorRateLimit},
+		{"no capacity", NewAPIError("test", ErrNoCapacity, 0, cause), true, RetriableErrorTemporary},
+		{"server error", NewAPIError("test", nil, http.StatusBadGateway, cause), true, RetriableErrorServerError},
+		{"unauthorized", NewAPIError("test", nil, http.StatusForbidden, cause), false, 0},
+		{"billing", NewAPIError("test", nil, http.StatusPaymentRequired, cause), false, 0},
+		// The kind wins over a message that looks transient
+		{"not found", NewAPIError("test", nil, http.StatusNotFound, errors.New("network not found")), false, 0},
+		{"unclassified message", errors.New("service temporarily unavailable"), true, RetriableErrorTemporary},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retriable, errType := IsRetriableError(tt.err)
+			if retriable != tt.retriable || errType != tt.errType {
+				t.Errorf("IsRetriableError() = %v, %v; want %v, %v", retriable, errType, tt.retriable, tt.errType)
+			}
+		})
+	}
+}
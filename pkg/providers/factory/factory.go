@@ -0,0 +1,91 @@
+// Package factory creates provider clients by provider name, so every code path supports
+// the same providers. Adding a provider means adding its constructor here.
+package factory
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/solanyn/tgp-operator/pkg/providers"
+	"github.com/solanyn/tgp-operator/pkg/providers/aws"
+	"github.com/solanyn/tgp-operator/pkg/providers/azure"
+	"github.com/solanyn/tgp-operator/pkg/providers/coreweave"
+	"github.com/solanyn/tgp-operator/pkg/providers/digitalocean"
+	"github.com/solanyn/tgp-operator/pkg/providers/gcp"
+	"github.com/solanyn/tgp-operator/pkg/providers/oci"
+	"github.com/solanyn/tgp-operator/pkg/providers/vultr"
+)
+
+// constructor creates a provider's client from its credentials
+type constructor struct {
+	// displayName names the provider in errors
+	displayName string
+	newClient   func(credentials string) (providers.ProviderClient, error)
+	// translateGPUType translates GPU types with the provider's static tables, without credentials
+	translateGPUType func(standard string) (string, error)
+}
+
+// constructors maps provider names to their client constructors
+var constructors = map[string]constructor{
+	vultr.ProviderName: {"Vultr", func(credentials string) (providers.ProviderClient, error) {
+		return vultr.NewClient(credentials)
+	}, (&vultr.Client{}).TranslateGPUType},
+	gcp.ProviderName: {"GCP", func(credentials string) (providers.ProviderClient, error) {
+		// Initialize is called when the client is first used
+		return gcp.NewClient(credentials), nil
+	}, (&gcp.Client{}).TranslateGPUType},
+	aws.ProviderName: {"AWS", func(credentials string) (providers.ProviderClient, error) {
+		return aws.NewClient(credentials)
+	}, (&aws.Client{}).TranslateGPUType},
+	azure.ProviderName: {"Azure", func(credentials string) (providers.ProviderClient, error) {
+		return azure.NewClient(credentials)
+	}, (&azure.Client{}).TranslateGPUType},
+	digitalocean.ProviderName: {"DigitalOcean", func(credentials string) (providers.ProviderClient, error) {
+		return digitalocean.NewClient(credentials)
+	}, (&digitalocean.Client{}).TranslateGPUType},
+	coreweave.ProviderName: {"CoreWeave", func(credentials string) (providers.ProviderClient, error) {
+		return coreweave.NewClient(credentials)
+	}, (&coreweave.Client{}).TranslateGPUType},
+	oci.ProviderName: {"OCI", func(credentials string) (providers.ProviderClient, error) {
+		return oci.NewClient(credentials)
+	}, (&oci.Client{}).TranslateGPUType},
+}
+
+// NewClientFor creates the client of the named provider from its credentials
+func NewClientFor(name, credentials string) (providers.ProviderClient, error) {
+	c, exists := constructors[name]
+	if !exists {
+		return nil, fmt.Errorf("unsupported provider: %s", name)
+	}
+	client, err := c.newClient(credentials)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s client: %w", c.displayName, err)
+	}
+	return client, nil
+}
+
+// IsSupported reports whether the named provider is supported
+func IsSupported(name string) bool {
+	_, exists := constructors[name]
+	return exists
+}
+
+// TranslateGPUType translates a standard GPU type to the named provider's. It uses the
+// provider's static translation tables, so it needs no credentials.
+func TranslateGPUType(name, gpuType string) (string, error) {
+	c, exists := constructors[name]
+	if !exists {
+		return "", fmt.Errorf("unsupported provider: %s", name)
+	}
+	return c.translateGPUType(gpuType)
+}
+
+// Names returns the names of the supported providers, sorted
+func Names() []string {
+	names := make([]string, 0, len(constructors))
+	for name := range constructors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
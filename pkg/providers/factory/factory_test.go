@@ -0,0 +1,44 @@
+package factory
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/solanyn/tgp-operator/pkg/providers/gcp"
+)
+
+func TestNewClientFor(t *testing.T) {
+	client, err := NewClientFor(gcp.ProviderName, "{}")
+	if err != nil {
+		t.Fatalf("NewClientFor(gcp) failed: %v", err)
+	}
+	if client.GetProviderInfo().Name != gcp.ProviderName {
+		t.Errorf("Expected a GCP client, got %s", client.GetProviderInfo().Name)
+	}
+
+	if _, err := NewClientFor("lambdalabs", "key"); err == nil || !strings.Contains(err.Error(), "unsupported provider") {
+		t.Errorf("Expected unsupported provider error, got %v", err)
+	}
+	if _, err := NewClientFor("oci", ""); err == nil || !strings.Contains(err.Error(), "failed to create OCI client") {
+		t.Errorf("Expected the constructor error to name the provider, got %v", err)
+	}
+}
+
+func TestNames(t *testing.T) {
+	expected := "aws,azure,coreweave,digitalocean,gcp,oci,vultr"
+	if got := strings.Join(Names(), ","); got != expected {
+		t.Errorf("Names() = %s, expected %s", got, expected)
+	}
+}
+
+func TestTranslateGPUType(t *testing.T) {
+	if !IsSupported(gcp.ProviderName) || IsSupported("lambdalabs") {
+		t.Error("expected only the supported providers to be reported as supported")
+	}
+	if got, err := TranslateGPUType(gcp.ProviderName, "A100"); err != nil || got == "" {
+		t.Errorf("TranslateGPUType(gcp, A100) = %q, %v, want a GCP GPU type", got, err)
+	}
+	if _, err := TranslateGPUType("lambdalabs", "A100"); err == nil || !strings.Contains(err.Error(), "unsupported provider") {
+		t.Errorf("Expected unsupported provider error, got %v", err)
+	}
+}
@@ -2,26 +2,50 @@ package gcp
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"regexp"
 	"strings"
 	"time"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	computepb "cloud.google.com/go/compute/apiv1/computepb"
+	"github.com/go-logr/logr"
 	"github.com/solanyn/tgp-operator/pkg/providers"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 	"google.golang.org/protobuf/proto"
 )
 
+const ProviderName = "gcp"
+
+// projectIDPattern matches valid GCP project IDs
+var projectIDPattern = regexp.MustCompile(`^[a-z][a-z0-9-]{4,28}[a-z0-9]$`)
+
 // Client implements the ProviderClient interface for Google Cloud Platform
 type Client struct {
-	projectID     string
-	credentials   string
-	computeClient *compute.InstancesClient
-	machineClient *compute.MachineTypesClient
-	imagesClient  *compute.ImagesClient
-	regionsClient *compute.RegionsClient
+	projectID       string
+	projectOverride string
+	credentials     string
+	// defaultCredentials are the application default credentials used without a service account key
+	defaultCredentials *google.Credentials
+	computeClient      *compute.InstancesClient
+	machineClient      *compute.MachineTypesClient
+	imagesClient       *compute.ImagesClient
+	regionsClient      *compute.RegionsClient
+	// httpClient replaces the default authenticated client when debug logging is enabled
+	httpClient *http.Client
+	// billing looks up live prices, falling back to the static price list
+	billing *billingCatalog
+	// imagePrefix is the name prefix of the Talos images launches boot from
+	imagePrefix string
 }
 
 // ServiceAccountKey represents the structure of a GCP service account JSON key
@@ -40,24 +64,41 @@ type ServiceAccountKey struct {
 
 // NewClient creates a new GCP provider client
 func NewClient(credentialsJSON string) *Client {
-	return &Client{
+	c := &Client{
 		credentials: credentialsJSON,
+		imagePrefix: DefaultTalosImagePrefix,
 	}
+	c.billing = newBillingCatalog(DefaultPricingCacheTTL, c.listComputeSKUs)
+	return c
 }
 
-// Initialize sets up the GCP client with proper authentication
+// Initialize sets up the GCP client with proper authentication. Without a service account
+// key the application default credentials are used, such as GKE workload identity.
 func (c *Client) Initialize(ctx context.Context) error {
-	// Parse service account key to get project ID
-	var serviceAccount ServiceAccountKey
-	if err := json.Unmarshal([]byte(c.credentials), &serviceAccount); err != nil {
-		return fmt.Errorf("failed to parse service account JSON: %w", err)
+	if c.credentials == "" {
+		creds, err := google.FindDefaultCredentials(ctx, compute.DefaultAuthScopes()...)
+		if err != nil {
+			return fmt.Errorf("failed to find application default credentials: %w", err)
+		}
+		c.defaultCredentials = creds
+		c.projectID = creds.ProjectID
+	} else {
+		// Parse service account key to get project ID
+		var serviceAccount ServiceAccountKey
+		if err := json.Unmarshal([]byte(c.credentials), &serviceAccount); err != nil {
+			return fmt.Errorf("failed to parse service account JSON: %w", err)
+		}
+		c.projectID = serviceAccount.ProjectID
+	}
+	if c.projectOverride != "" {
+		c.projectID = c.projectOverride
+	}
+	if c.projectID == "" {
+		return fmt.Errorf("no GCP project found in the credentials; select a project as the provider account")
 	}
-	c.projectID = serviceAccount.ProjectID
 
 	// Set up client options
-	opts := []option.ClientOption{
-		option.WithCredentialsJSON([]byte(c.credentials)),
-	}
+	opts := c.clientOptions()
 
 	// Initialize compute clients
 	var err error
@@ -84,10 +125,63 @@ func (c *Client) Initialize(ctx context.Context) error {
 	return nil
 }
 
+// clientOptions returns the options for creating compute API clients
+func (c *Client) clientOptions() []option.ClientOption {
+	if c.httpClient != nil {
+		return []option.ClientOption{option.WithHTTPClient(c.httpClient)}
+	}
+	if c.defaultCredentials != nil {
+		return []option.ClientOption{option.WithCredentials(c.defaultCredentials)}
+	}
+	return []option.ClientOption{option.WithCredentialsJSON([]byte(c.credentials))}
+}
+
+// EnableDebugLogging logs every compute API request and response at debug level.
+// It must be called before Initialize.
+func (c *Client) EnableDebugLogging(log logr.Logger) {
+	creds, err := c.tokenCredentials(context.Background())
+	if err != nil {
+		log.Error(err, "Failed to parse credentials, API debug logging stays disabled")
+		return
+	}
+	c.httpClient = &http.Client{
+		Transport: &oauth2.Transport{Source: creds.TokenSource, Base: providers.NewDebugTransport(nil, log)},
+	}
+}
+
+// tokenCredentials returns the credentials the client authenticates with: the service
+// account key, or the application default credentials when there is none
+func (c *Client) tokenCredentials(ctx context.Context) (*google.Credentials, error) {
+	if c.credentials == "" {
+		return google.FindDefaultCredentials(ctx, compute.DefaultAuthScopes()...)
+	}
+	return google.CredentialsFromJSON(ctx, []byte(c.credentials), compute.DefaultAuthScopes()...)
+}
+
+// SetImagePrefix sets the name prefix of the Talos images launches boot from. An empty
+// prefix keeps DefaultTalosImagePrefix.
+func (c *Client) SetImagePrefix(prefix string) {
+	if prefix != "" {
+		c.imagePrefix = prefix
+	}
+}
+
+// SelectAccount provisions into the given project instead of the service account's own project
+func (c *Client) SelectAccount(projectID string) error {
+	if !projectIDPattern.MatchString(projectID) {
+		return fmt.Errorf("invalid GCP project ID: %s", projectID)
+	}
+	c.projectOverride = projectID
+	if c.computeClient != nil {
+		c.projectID = projectID
+	}
+	return nil
+}
+
 // GetProviderInfo returns information about the GCP provider
 func (c *Client) GetProviderInfo() *providers.ProviderInfo {
 	return &providers.ProviderInfo{
-		Name:       "gcp",
+		Name:       ProviderName,
 		APIVersion: "v1",
 		SupportedRegions: []string{
 			"us-central1", "us-east1", "us-east4", "us-west1", "us-west2", "us-west3", "us-west4",
@@ -101,6 +195,9 @@ func (c *Client) GetProviderInfo() *providers.ProviderInfo {
 		},
 		SupportsSpotInstances: true,
 		BillingGranularity:    "per-minute",
+		ReliabilityTier:       providers.ReliabilityTierEnterprise,
+
+		SupportsReadOnlyDataDisks: true,
 	}
 }
 
@@ -124,6 +221,11 @@ func (c *Client) ListAvailableGPUs(ctx context.Context, filters *providers.GPUFi
 	regions := c.getRegionsToSearch(filters.Region)
 
 	for _, region := range regions {
+		// Skip regions outside the allowed countries without querying their zones
+		if !providers.CountryAllowed(regionCountries[region], filters.Countries) {
+			continue
+		}
+
 		zones := c.getZonesForRegion(region)
 		for _, zone := range zones {
 			zoneOffers, err := c.getGPUOffersForZone(ctx, zone, filters)
@@ -146,14 +248,13 @@ func (c *Client) GetNormalizedPricing(ctx context.Context, gpuType, region strin
 		return nil, fmt.Errorf("failed to initialize client: %w", err)
 	}
 
-	// Get base instance pricing (e.g., n1-standard-4 with GPU)
-	machineType := c.getRecommendedMachineTypeForGPU(gpuType)
-	machinePrice := c.getMachinePricing(machineType, region)
+	// Price the recommended machine type (e.g., n1-standard-4) with its GPUs
+	totalHourlyPrice, live := c.instancePrice(ctx, gpuType, region)
 
-	// Get GPU pricing
-	gpuPrice := c.getGPUPricing(gpuType, region)
-
-	totalHourlyPrice := machinePrice + gpuPrice
+	source := "static"
+	if live {
+		source = "cloud-billing"
+	}
 
 	return &providers.NormalizedPricing{
 		PricePerHour:   totalHourlyPrice,
@@ -161,6 +262,10 @@ func (c *Client) GetNormalizedPricing(ctx context.Context, gpuType, region strin
 		Currency:       "USD",
 		BillingModel:   providers.BillingPerMinute,
 		LastUpdated:    time.Now(),
+		ProviderSpecific: map[string]interface{}{
+			"machineType":   c.getRecommendedMachineTypeForGPU(gpuType),
+			"pricingSource": source,
+		},
 	}, nil
 }
 
@@ -174,29 +279,58 @@ func (c *Client) LaunchInstance(ctx context.Context, req *providers.LaunchReques
 	instanceName := c.generateInstanceName(req)
 	zone := c.selectBestZone(req.Region, req.GPUType)
 
+	// Zonal data disks can only be attached to instances in the same zone
+	if diskZone := c.dataDiskZone(req.DataDisks); diskZone != "" {
+		zone = diskZone
+	}
+
+	machineType, gpuCount, err := c.machineTypeForGPUs(req.GPUType, req.RequestedGPUs())
+	if err != nil {
+		return nil, err
+	}
+
+	bootDiskGiB, err := req.BootDiskSize(defaultBootDiskGiB, minBootDiskGiB)
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := c.resolveImage(ctx, req.Image)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build instance configuration
 	instance := &computepb.Instance{
 		Name:              proto.String(instanceName),
-		MachineType:       proto.String(c.getMachineTypeURL(c.getRecommendedMachineTypeForGPU(req.GPUType), zone)),
+		MachineType:       proto.String(c.getMachineTypeURL(machineType, zone)),
 		Labels:            c.buildLabels(req),
 		Metadata:          c.buildMetadata(req),
-		Disks:             c.buildDiskConfig(),
+		Disks:             append(c.buildDiskConfig(image, bootDiskGiB), c.buildDataDiskConfig(req.DataDisks, zone)...),
 		NetworkInterfaces: c.buildNetworkConfig(),
 		ServiceAccounts:   c.buildServiceAccountConfig(),
-		GuestAccelerators: c.buildGPUConfig(req.GPUType, 1),
+		GuestAccelerators: c.buildGPUConfig(req.GPUType, gpuCount),
 		Scheduling: &computepb.Scheduling{
 			Preemptible: proto.Bool(req.SpotInstance),
 		},
 	}
 
-	// Launch the instance
+	// Launch the instance, retrying with a freshly suffixed name if another launch took it
 	op, err := c.computeClient.Insert(ctx, &computepb.InsertInstanceRequest{
 		Project:          c.projectID,
 		Zone:             zone,
 		InstanceResource: instance,
 	})
+	for attempt := 0; isAlreadyExists(err) && attempt < req.NameCollisionRetries; attempt++ {
+		instanceName = withNameSuffix(c.generateInstanceName(req), randomSuffix())
+		instance.Name = proto.String(instanceName)
+		op, err = c.computeClient.Insert(ctx, &computepb.InsertInstanceRequest{
+			Project:          c.projectID,
+			Zone:             zone,
+			InstanceResource: instance,
+		})
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to launch instance: %w", err)
+		return nil, fmt.Errorf("failed to launch instance: %w", apiError(err))
 	}
 
 	// Wait for operation to complete
@@ -211,7 +345,7 @@ func (c *Client) LaunchInstance(ctx context.Context, req *providers.LaunchReques
 		Instance: instanceName,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get created instance: %w", err)
+		return nil, fmt.Errorf("failed to get created instance: %w", apiError(err))
 	}
 
 	return c.instanceToGPUInstance(createdInstance, zone), nil
@@ -230,13 +364,61 @@ func (c *Client) TerminateInstance(ctx context.Context, instanceID string) error
 		Zone:     zone,
 		Instance: instanceName,
 	})
+	if isNotFound(err) {
+		// The instance is already gone
+		return nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to delete instance: %w", err)
+		return fmt.Errorf("failed to delete instance: %w", apiError(err))
 	}
 
 	return c.waitForZoneOperation(ctx, op.Name(), zone)
 }
 
+// TerminateInstances deletes several instances, issuing every delete before waiting on
+// the resulting operations so large pools are torn down in parallel
+func (c *Client) TerminateInstances(ctx context.Context, instanceIDs []string) map[string]error {
+	failures := make(map[string]error)
+	if err := c.ensureInitialized(ctx); err != nil {
+		for _, instanceID := range instanceIDs {
+			failures[instanceID] = fmt.Errorf("failed to initialize client: %w", err)
+		}
+		return failures
+	}
+
+	type pendingDelete struct {
+		instanceID string
+		operation  string
+		zone       string
+	}
+
+	var pending []pendingDelete
+	for _, instanceID := range instanceIDs {
+		zone, instanceName := c.parseInstanceID(instanceID)
+		op, err := c.computeClient.Delete(ctx, &computepb.DeleteInstanceRequest{
+			Project:  c.projectID,
+			Zone:     zone,
+			Instance: instanceName,
+		})
+		if isNotFound(err) {
+			continue
+		}
+		if err != nil {
+			failures[instanceID] = fmt.Errorf("failed to delete instance: %w", apiError(err))
+			continue
+		}
+		pending = append(pending, pendingDelete{instanceID: instanceID, operation: op.Name(), zone: zone})
+	}
+
+	for _, p := range pending {
+		if err := c.waitForZoneOperation(ctx, p.operation, p.zone); err != nil {
+			failures[p.instanceID] = err
+		}
+	}
+
+	return failures
+}
+
 // GetInstanceStatus returns the current status of an instance
 func (c *Client) GetInstanceStatus(ctx context.Context, instanceID string) (*providers.InstanceStatus, error) {
 	if err := c.ensureInitialized(ctx); err != nil {
@@ -251,7 +433,7 @@ func (c *Client) GetInstanceStatus(ctx context.Context, instanceID string) (*pro
 		Instance: instanceName,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get instance: %w", err)
+		return nil, fmt.Errorf("failed to get instance: %w", apiError(err))
 	}
 
 	return &providers.InstanceStatus{
@@ -262,6 +444,157 @@ func (c *Client) GetInstanceStatus(ctx context.Context, instanceID string) (*pro
 	}, nil
 }
 
+// AttachDataDisk attaches an existing persistent disk to a running instance
+func (c *Client) AttachDataDisk(ctx context.Context, instanceID string, disk providers.DataDisk) error {
+	if err := c.ensureInitialized(ctx); err != nil {
+		return fmt.Errorf("failed to initialize client: %w", err)
+	}
+
+	zone, instanceName := c.parseInstanceID(instanceID)
+
+	op, err := c.computeClient.AttachDisk(ctx, &computepb.AttachDiskInstanceRequest{
+		Project:              c.projectID,
+		Zone:                 zone,
+		Instance:             instanceName,
+		AttachedDiskResource: c.buildAttachedDataDisk(disk, zone),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to attach disk %s: %w", disk.VolumeID, apiError(err))
+	}
+
+	return c.waitForZoneOperation(ctx, op.Name(), zone)
+}
+
+// DetachDataDisk detaches a persistent disk from an instance without deleting it
+func (c *Client) DetachDataDisk(ctx context.Context, instanceID, volumeID string) error {
+	if err := c.ensureInitialized(ctx); err != nil {
+		return fmt.Errorf("failed to initialize client: %w", err)
+	}
+
+	zone, instanceName := c.parseInstanceID(instanceID)
+
+	op, err := c.computeClient.DetachDisk(ctx, &computepb.DetachDiskInstanceRequest{
+		Project:    c.projectID,
+		Zone:       zone,
+		Instance:   instanceName,
+		DeviceName: c.diskName(volumeID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to detach disk %s: %w", volumeID, apiError(err))
+	}
+
+	return c.waitForZoneOperation(ctx, op.Name(), zone)
+}
+
+// UpdateInstanceTags updates the labels of a running instance
+func (c *Client) UpdateInstanceTags(ctx context.Context, instanceID string, set map[string]string, remove []string) error {
+	if err := c.ensureInitialized(ctx); err != nil {
+		return fmt.Errorf("failed to initialize client: %w", err)
+	}
+
+	zone, instanceName := c.parseInstanceID(instanceID)
+
+	// SetLabels replaces all labels, so start from the current set and its fingerprint
+	instance, err := c.computeClient.Get(ctx, &computepb.GetInstanceRequest{
+		Project:  c.projectID,
+		Zone:     zone,
+		Instance: instanceName,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get instance: %w", apiError(err))
+	}
+
+	op, err := c.computeClient.SetLabels(ctx, &computepb.SetLabelsInstanceRequest{
+		Project:  c.projectID,
+		Zone:     zone,
+		Instance: instanceName,
+		InstancesSetLabelsRequestResource: &computepb.InstancesSetLabelsRequest{
+			Labels:           mergeLabels(instance.GetLabels(), set, remove),
+			LabelFingerprint: instance.LabelFingerprint,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set labels on instance %s: %w", instanceName, apiError(err))
+	}
+
+	return c.waitForZoneOperation(ctx, op.Name(), zone)
+}
+
+// MissingInstanceTags returns the wanted tags the instance's labels lack or hold a different
+// value for, comparing them in the sanitized form labels are stored in
+func (c *Client) MissingInstanceTags(ctx context.Context, instanceID string, want map[string]string) (map[string]string, error) {
+	if err := c.ensureInitialized(ctx); err != nil {
+		return nil, fmt.Errorf("failed to initialize client: %w", err)
+	}
+
+	zone, instanceName := c.parseInstanceID(instanceID)
+	instance, err := c.computeClient.Get(ctx, &computepb.GetInstanceRequest{
+		Project:  c.projectID,
+		Zone:     zone,
+		Instance: instanceName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance: %w", apiError(err))
+	}
+
+	return providers.MissingTags(instance.GetLabels(), want, func(key, value string) (string, string) {
+		return sanitizeLabel(key), sanitizeLabel(value)
+	}), nil
+}
+
+// ListManagedInstances returns the project's instances labelled as managed by the operator,
+// across every zone of the region or of all regions when region is empty
+func (c *Client) ListManagedInstances(ctx context.Context, region string) ([]providers.ManagedInstance, error) {
+	if err := c.ensureInitialized(ctx); err != nil {
+		return nil, fmt.Errorf("failed to initialize client: %w", err)
+	}
+	if region != "" {
+		var err error
+		if region, err = c.TranslateRegion(region); err != nil {
+			return nil, err
+		}
+	}
+
+	it := c.computeClient.AggregatedList(ctx, &computepb.AggregatedListInstancesRequest{
+		Project: c.projectID,
+		Filter:  proto.String(`labels.managed-by = "tgp-operator"`),
+	})
+	var instances []providers.ManagedInstance
+	for {
+		pair, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			return instances, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list instances of project %s: %w", c.projectID, apiError(err))
+		}
+
+		zone := strings.TrimPrefix(pair.Key, "zones/")
+		if region != "" && c.zoneToRegion(zone) != region {
+			continue
+		}
+		for _, instance := range pair.Value.GetInstances() {
+			instances = append(instances, c.managedInstance(instance, zone))
+		}
+	}
+}
+
+// CheckCredentials lists one of the project's regions, which fails when the service account
+// key is invalid or cannot access the project
+func (c *Client) CheckCredentials(ctx context.Context) error {
+	if err := c.ensureInitialized(ctx); err != nil {
+		return err
+	}
+	it := c.regionsClient.List(ctx, &computepb.ListRegionsRequest{
+		Project:    c.projectID,
+		MaxResults: proto.Uint32(1),
+	})
+	if _, err := it.Next(); err != nil && !errors.Is(err, iterator.Done) {
+		return fmt.Errorf("failed to list regions of project %s: %w", c.projectID, apiError(err))
+	}
+	return nil
+}
+
 // ensureInitialized checks if the client is initialized and initializes if needed
 func (c *Client) ensureInitialized(ctx context.Context) error {
 	if c.computeClient == nil {
@@ -289,6 +622,42 @@ func (c *Client) generateInstanceName(req *providers.LaunchRequest) string {
 	return strings.Trim(name, "-")
 }
 
+// withNameSuffix appends a suffix to an instance name, shortening the name to keep it within 63 characters
+func withNameSuffix(name, suffix string) string {
+	if maxLen := 63 - len(suffix) - 1; len(name) > maxLen {
+		name = strings.TrimRight(name[:maxLen], "-")
+	}
+	return name + "-" + suffix
+}
+
+// randomSuffix returns a short random suffix of lowercase hex characters
+func randomSuffix() string {
+	b := make([]byte, 3)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// isAlreadyExists reports whether a compute API error means the resource name is taken
+func isAlreadyExists(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusConflict
+}
+
+// isNotFound reports whether a compute API error means the resource does not exist
+func isNotFound(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound
+}
+
+// apiError classifies a compute API error by its HTTP status
+func apiError(err error) error {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	return providers.NewAPIError(ProviderName, nil, apiErr.Code, err)
+}
+
 // Close cleans up the client connections
 func (c *Client) Close() error {
 	var errs []error
@@ -1,15 +1,34 @@
 package gcp
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	computepb "cloud.google.com/go/compute/apiv1/computepb"
+	v1 "github.com/solanyn/tgp-operator/pkg/api/v1"
 	"github.com/solanyn/tgp-operator/pkg/providers"
+	"google.golang.org/api/iterator"
 	"google.golang.org/protobuf/proto"
 )
 
+// maxMetadataValueBytes is the maximum size of a single GCP metadata value
+const maxMetadataValueBytes = 256 * 1024
+
+// DefaultTalosImagePrefix is the name prefix of the Talos images launches boot from unless configured
+const DefaultTalosImagePrefix = "talos-"
+
+const (
+	// defaultBootDiskGiB is the boot disk size used when the launch does not request one
+	defaultBootDiskGiB = 50
+	// minBootDiskGiB is the smallest persistent disk GCP creates
+	minBootDiskGiB = 10
+)
+
 // buildLabels creates labels for the instance
 func (c *Client) buildLabels(req *providers.LaunchRequest) map[string]string {
 	labels := map[string]string{
@@ -18,23 +37,40 @@ func (c *Client) buildLabels(req *providers.LaunchRequest) map[string]string {
 		"managed-by":   "tgp-operator",
 	}
 
-	// Add custom labels from request
+	// Add node class tags, then custom labels from request
+	for k, v := range req.Tags {
+		labels[sanitizeLabel(k)] = sanitizeLabel(v)
+	}
 	for k, v := range req.Labels {
-		// GCP labels must be lowercase and cannot contain dots or underscores
-		key := strings.ToLower(k)
-		key = strings.ReplaceAll(key, ".", "-")
-		key = strings.ReplaceAll(key, "_", "-")
-		
-		value := strings.ToLower(v)
-		value = strings.ReplaceAll(value, ".", "-")
-		value = strings.ReplaceAll(value, "_", "-")
-		
-		labels[key] = value
+		labels[sanitizeLabel(k)] = sanitizeLabel(v)
 	}
 
 	return labels
 }
 
+// sanitizeLabel converts a label key or value to GCP's format, which must be
+// lowercase and cannot contain dots or underscores
+func sanitizeLabel(s string) string {
+	s = strings.ToLower(s)
+	s = strings.ReplaceAll(s, ".", "-")
+	return strings.ReplaceAll(s, "_", "-")
+}
+
+// mergeLabels applies tag changes to an instance's existing labels
+func mergeLabels(existing, set map[string]string, remove []string) map[string]string {
+	labels := make(map[string]string, len(existing)+len(set))
+	for k, v := range existing {
+		labels[k] = v
+	}
+	for _, k := range remove {
+		delete(labels, sanitizeLabel(k))
+	}
+	for k, v := range set {
+		labels[sanitizeLabel(k)] = sanitizeLabel(v)
+	}
+	return labels
+}
+
 // buildMetadata creates metadata for the instance (user data)
 func (c *Client) buildMetadata(req *providers.LaunchRequest) *computepb.Metadata {
 	items := []*computepb.Items{
@@ -57,21 +93,86 @@ func (c *Client) buildMetadata(req *providers.LaunchRequest) *computepb.Metadata
 	}
 }
 
-// buildDiskConfig creates the disk configuration
-func (c *Client) buildDiskConfig() []*computepb.AttachedDisk {
+// RenderUserData validates the Talos machine config fits in a single GCP metadata value
+func (c *Client) RenderUserData(machineConfig string) (string, error) {
+	if len(machineConfig) > maxMetadataValueBytes {
+		return "", fmt.Errorf("machine config is %d bytes, exceeding the GCP metadata limit of %d bytes", len(machineConfig), maxMetadataValueBytes)
+	}
+	return machineConfig, nil
+}
+
+// buildDiskConfig creates the disk configuration, booting from the given image
+func (c *Client) buildDiskConfig(image string, sizeGiB int) []*computepb.AttachedDisk {
 	return []*computepb.AttachedDisk{
 		{
 			Boot:       proto.Bool(true),
 			AutoDelete: proto.Bool(true),
 			InitializeParams: &computepb.AttachedDiskInitializeParams{
-				DiskSizeGb:  proto.Int64(50),        // 50GB boot disk
+				DiskSizeGb:  proto.Int64(int64(sizeGiB)),
 				DiskType:    proto.String("pd-ssd"), // SSD for better performance
-				SourceImage: proto.String(c.getTalosImageURL()),
+				SourceImage: proto.String(image),
 			},
 		},
 	}
 }
 
+// buildDataDiskConfig creates attached disk entries for existing data disks in the given zone
+func (c *Client) buildDataDiskConfig(disks []providers.DataDisk, zone string) []*computepb.AttachedDisk {
+	attached := make([]*computepb.AttachedDisk, 0, len(disks))
+	for _, disk := range disks {
+		attached = append(attached, c.buildAttachedDataDisk(disk, zone))
+	}
+	return attached
+}
+
+// buildAttachedDataDisk creates an attached disk entry for an existing persistent disk
+func (c *Client) buildAttachedDataDisk(disk providers.DataDisk, zone string) *computepb.AttachedDisk {
+	mode := computepb.AttachedDisk_READ_WRITE
+	if disk.ReadOnly {
+		mode = computepb.AttachedDisk_READ_ONLY
+	}
+
+	deviceName := disk.DeviceName
+	if deviceName == "" {
+		deviceName = c.diskName(disk.VolumeID)
+	}
+
+	return &computepb.AttachedDisk{
+		Source:     proto.String(c.getDiskURL(disk.VolumeID, zone)),
+		Mode:       proto.String(mode.String()),
+		DeviceName: proto.String(deviceName),
+		AutoDelete: proto.Bool(false),
+		Boot:       proto.Bool(false),
+	}
+}
+
+// getDiskURL returns the disk URL, resolving bare disk names within the given zone
+func (c *Client) getDiskURL(volumeID, zone string) string {
+	if strings.Contains(volumeID, "/") {
+		return volumeID
+	}
+	return fmt.Sprintf("projects/%s/zones/%s/disks/%s", c.projectID, zone, volumeID)
+}
+
+// diskName extracts the disk name from a disk name or self link
+func (c *Client) diskName(volumeID string) string {
+	parts := strings.Split(volumeID, "/")
+	return parts[len(parts)-1]
+}
+
+// dataDiskZone returns the zone pinned by data disk self links, if any
+func (c *Client) dataDiskZone(disks []providers.DataDisk) string {
+	for _, disk := range disks {
+		parts := strings.Split(disk.VolumeID, "/")
+		for i := 0; i < len(parts)-1; i++ {
+			if parts[i] == "zones" {
+				return parts[i+1]
+			}
+		}
+	}
+	return ""
+}
+
 // buildNetworkConfig creates the network configuration
 func (c *Client) buildNetworkConfig() []*computepb.NetworkInterface {
 	return []*computepb.NetworkInterface{
@@ -182,15 +283,133 @@ func (c *Client) getRecommendedMachineTypeForGPU(gpuType string) string {
 	return "n1-standard-4"
 }
 
+// gpuMachineShape is a machine type and the number of GPUs launched with it
+type gpuMachineShape struct {
+	machineType string
+	gpus        int32
+}
+
+// gpuMachineShapes lists the GPU counts each accelerator can be launched with, smallest first.
+// A2, A3 and G2 machine types bundle a fixed number of GPUs; N1 machine types are sized to the
+// number of GPUs attached to them.
+var gpuMachineShapes = map[string][]gpuMachineShape{
+	"nvidia-tesla-k80":  {{"n1-standard-4", 1}, {"n1-standard-8", 2}, {"n1-standard-16", 4}, {"n1-standard-32", 8}},
+	"nvidia-tesla-p4":   {{"n1-standard-4", 1}, {"n1-standard-8", 2}, {"n1-standard-16", 4}},
+	"nvidia-tesla-t4":   {{"n1-standard-4", 1}, {"n1-standard-8", 2}, {"n1-standard-16", 4}},
+	"nvidia-tesla-p100": {{"n1-standard-8", 1}, {"n1-standard-16", 2}, {"n1-standard-32", 4}},
+	"nvidia-tesla-v100": {{"n1-standard-8", 1}, {"n1-standard-16", 2}, {"n1-standard-32", 4}, {"n1-standard-64", 8}},
+	"nvidia-tesla-a100": {{"a2-highgpu-1g", 1}, {"a2-highgpu-2g", 2}, {"a2-highgpu-4g", 4}, {"a2-highgpu-8g", 8}, {"a2-megagpu-16g", 16}},
+	"nvidia-a100-80gb":  {{"a2-ultragpu-1g", 1}, {"a2-ultragpu-2g", 2}, {"a2-ultragpu-4g", 4}, {"a2-ultragpu-8g", 8}},
+	"nvidia-h100-80gb":  {{"a3-highgpu-8g", 8}},
+	"nvidia-l4":         {{"g2-standard-4", 1}, {"g2-standard-24", 2}, {"g2-standard-48", 4}, {"g2-standard-96", 8}},
+}
+
+// machineTypeForGPUs returns the smallest machine type with at least count GPUs of the given
+// type and the number of GPUs it is launched with. Unknown GPU types fall back to a single
+// GPU on an N1 machine.
+func (c *Client) machineTypeForGPUs(gpuType string, count int) (string, int32, error) {
+	shapes, ok := gpuMachineShapes[c.translateGPUTypeToGCP(gpuType)]
+	if !ok {
+		if count > 1 {
+			return "", 0, fmt.Errorf("no known machine type offers %d %s GPUs", count, gpuType)
+		}
+		return c.getRecommendedMachineTypeForGPU(gpuType), 1, nil
+	}
+
+	for _, shape := range shapes {
+		if int(shape.gpus) >= count {
+			return shape.machineType, shape.gpus, nil
+		}
+	}
+	largest := shapes[len(shapes)-1]
+	return "", 0, fmt.Errorf("no machine type offers %d %s GPUs, the largest is %s with %d", count, gpuType, largest.machineType, largest.gpus)
+}
+
+// a2VCPUsPerGPU is the number of vCPUs A2 machine types have for each attached A100
+const a2VCPUsPerGPU = 12
+
+// machineTypeShape returns the vCPU and GPU counts implied by a machine type name. Machine
+// types without bundled GPUs, like n1-standard-4, get the single GPU the client attaches.
+// Unknown shapes report zero vCPUs.
+func machineTypeShape(machineType string) (int, int) {
+	if machineType == "a3-highgpu-8g" {
+		return 208, 8
+	}
+
+	parts := strings.Split(machineType, "-")
+	last := parts[len(parts)-1]
+	if strings.HasPrefix(machineType, "a2-") && strings.HasSuffix(last, "g") {
+		gpus, err := strconv.Atoi(strings.TrimSuffix(last, "g"))
+		if err != nil {
+			return 0, 1
+		}
+		return gpus * a2VCPUsPerGPU, gpus
+	}
+
+	vcpus, err := strconv.Atoi(last)
+	if err != nil {
+		return 0, 1
+	}
+	return vcpus, 1
+}
+
 // getMachineTypeURL builds the full machine type URL
 func (c *Client) getMachineTypeURL(machineType, zone string) string {
 	return fmt.Sprintf("projects/%s/zones/%s/machineTypes/%s", c.projectID, zone, machineType)
 }
 
-// getTalosImageURL returns the URL for the Talos Linux image
-func (c *Client) getTalosImageURL() string {
-	// Return a standard Talos image name that users should upload to their project
-	return fmt.Sprintf("projects/%s/global/images/talos-linux-latest", c.projectID)
+// resolveImage returns the boot image for a launch. Image paths and URLs are used as given and
+// bare names refer to an image in the project. An empty image or "talos" boots the newest
+// ready image in the project whose name starts with the Talos image prefix.
+func (c *Client) resolveImage(ctx context.Context, image string) (string, error) {
+	if strings.Contains(image, "/") {
+		return image, nil
+	}
+	if image != "" && !strings.EqualFold(image, "talos") {
+		return fmt.Sprintf("projects/%s/global/images/%s", c.projectID, image), nil
+	}
+
+	var images []*computepb.Image
+	it := c.imagesClient.List(ctx, &computepb.ListImagesRequest{Project: c.projectID})
+	for {
+		img, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to list images in project %s: %w", c.projectID, err)
+		}
+		images = append(images, img)
+	}
+
+	newest, err := newestTalosImage(images, c.imagePrefix)
+	if err != nil {
+		return "", fmt.Errorf("project %s: %w", c.projectID, err)
+	}
+	return fmt.Sprintf("projects/%s/global/images/%s", c.projectID, newest.GetName()), nil
+}
+
+// newestTalosImage returns the most recently created ready image whose name starts with prefix.
+// The error lists the images that are available when none match.
+func newestTalosImage(images []*computepb.Image, prefix string) (*computepb.Image, error) {
+	var newest *computepb.Image
+	var newestCreated time.Time
+	available := make([]string, 0, len(images))
+	for _, img := range images {
+		available = append(available, img.GetName())
+		if !strings.HasPrefix(img.GetName(), prefix) || img.GetStatus() != "READY" {
+			continue
+		}
+		created, _ := time.Parse(time.RFC3339, img.GetCreationTimestamp())
+		if newest == nil || created.After(newestCreated) {
+			newest, newestCreated = img, created
+		}
+	}
+	if newest == nil {
+		sort.Strings(available)
+		return nil, fmt.Errorf("no ready Talos image named %s* found; available images: [%s]", prefix, strings.Join(available, ", "))
+	}
+	return newest, nil
 }
 
 // instanceToGPUInstance converts a GCP instance to our GPUInstance format
@@ -203,6 +422,15 @@ func (c *Client) instanceToGPUInstance(instance *computepb.Instance, zone string
 		PrivateIP: c.extractPrivateIP(instance),
 		Status:    c.translateInstanceState(instance.GetStatus()),
 		CreatedAt: c.extractLaunchTime(instance),
+		IsSpot:    c.isSpotInstance(instance),
+	}
+}
+
+// managedInstance converts an instance listed as managed by the operator
+func (c *Client) managedInstance(instance *computepb.Instance, zone string) providers.ManagedInstance {
+	return providers.ManagedInstance{
+		GPUInstance: *c.instanceToGPUInstance(instance, zone),
+		NodePool:    instance.GetLabels()[sanitizeLabel(v1.NodeLabelNodePool)],
 	}
 }
 
@@ -9,7 +9,6 @@ import (
 	compute "cloud.google.com/go/compute/apiv1"
 	computepb "cloud.google.com/go/compute/apiv1/computepb"
 	"github.com/solanyn/tgp-operator/pkg/providers"
-	"google.golang.org/api/option"
 )
 
 // waitForZoneOperation waits for a GCP zone operation to complete
@@ -19,9 +18,7 @@ func (c *Client) waitForZoneOperation(ctx context.Context, opName, zone string)
 	}
 
 	// Create zone operations client for monitoring
-	opts := []option.ClientOption{
-		option.WithCredentialsJSON([]byte(c.credentials)),
-	}
+	opts := c.clientOptions()
 
 	zoneOpsClient, err := compute.NewZoneOperationsRESTClient(ctx, opts...)
 	if err != nil {
@@ -59,11 +56,7 @@ func (c *Client) waitForZoneOperation(ctx context.Context, opName, zone string)
 			case computepb.Operation_DONE:
 				// Check for errors
 				if currentOp.GetError() != nil {
-					var errorMsgs []string
-					for _, e := range currentOp.GetError().GetErrors() {
-						errorMsgs = append(errorMsgs, e.GetMessage())
-					}
-					return fmt.Errorf("operation failed: %s", strings.Join(errorMsgs, "; "))
+					return operationError(currentOp.GetError())
 				}
 				return nil
 
@@ -78,15 +71,34 @@ func (c *Client) waitForZoneOperation(ctx context.Context, opName, zone string)
 	}
 }
 
+// operationErrorKinds maps the error codes of failed operations to provider error kinds
+var operationErrorKinds = map[string]error{
+	"ZONE_RESOURCE_POOL_EXHAUSTED":              providers.ErrNoCapacity,
+	"ZONE_RESOURCE_POOL_EXHAUSTED_WITH_DETAILS": providers.ErrNoCapacity,
+	"QUOTA_EXCEEDED":                            providers.ErrNoCapacity,
+	"RATE_LIMIT_EXCEEDED":                       providers.ErrRateLimited,
+}
+
+// operationError returns the error of a failed zone operation, classified by its error codes
+func operationError(opErr *computepb.Error) error {
+	var errorMsgs []string
+	var kind error
+	for _, e := range opErr.GetErrors() {
+		errorMsgs = append(errorMsgs, e.GetMessage())
+		if kind == nil {
+			kind = operationErrorKinds[e.GetCode()]
+		}
+	}
+	return providers.NewAPIError(ProviderName, kind, 0, fmt.Errorf("operation failed: %s", strings.Join(errorMsgs, "; ")))
+}
+
 // waitForGlobalOperation waits for a global operation to complete (e.g., image operations)
 func (c *Client) waitForGlobalOperation(ctx context.Context, op *computepb.Operation) error {
 	if op == nil {
 		return fmt.Errorf("operation is nil")
 	}
 
-	opts := []option.ClientOption{
-		option.WithCredentialsJSON([]byte(c.credentials)),
-	}
+	opts := c.clientOptions()
 
 	globalOpsClient, err := compute.NewGlobalOperationsRESTClient(ctx, opts...)
 	if err != nil {
@@ -209,9 +221,7 @@ func (c *Client) validateInstanceConfig(req *providers.LaunchRequest) error {
 func (c *Client) getOperationProgress(ctx context.Context, op *computepb.Operation, zone string) (int32, string, error) {
 	if zone == "" {
 		// Global operation
-		opts := []option.ClientOption{
-			option.WithCredentialsJSON([]byte(c.credentials)),
-		}
+		opts := c.clientOptions()
 
 		globalOpsClient, err := compute.NewGlobalOperationsRESTClient(ctx, opts...)
 		if err != nil {
@@ -230,9 +240,7 @@ func (c *Client) getOperationProgress(ctx context.Context, op *computepb.Operati
 		return currentOp.GetProgress(), currentOp.GetStatusMessage(), nil
 	} else {
 		// Zone operation
-		opts := []option.ClientOption{
-			option.WithCredentialsJSON([]byte(c.credentials)),
-		}
+		opts := c.clientOptions()
 
 		zoneOpsClient, err := compute.NewZoneOperationsRESTClient(ctx, opts...)
 		if err != nil {
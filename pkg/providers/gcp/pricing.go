@@ -8,6 +8,37 @@ import (
 	"github.com/solanyn/tgp-operator/pkg/providers"
 )
 
+// instancePrice returns the on-demand hourly price of the machine type recommended for a GPU
+// type, with its GPUs, in a region. Live Cloud Billing prices are used when available, falling
+// back to the static price list if the catalog cannot be queried; live reports which was used.
+func (c *Client) instancePrice(ctx context.Context, gpuType, region string) (price float64, live bool) {
+	machineType := c.getRecommendedMachineTypeForGPU(gpuType)
+	if c.billing != nil {
+		if price, err := c.billing.hourlyPrice(ctx, machineType, c.translateGPUTypeToGCP(gpuType), region, false); err == nil {
+			return price, true
+		}
+	}
+	return c.getMachinePricing(machineType, region) + c.getGPUPricing(gpuType, region), false
+}
+
+// fallbackSpotPriceRatio estimates the spot price from the on-demand price when the billing
+// catalog cannot be queried. Real spot discounts vary by GPU and region, often 60-91%, so this
+// is only a last resort.
+const fallbackSpotPriceRatio = 0.7
+
+// spotInstancePrice returns the spot hourly price of the machine type recommended for a GPU
+// type, with its GPUs, in a region, from the Cloud Billing catalog's spot SKUs. Without live
+// prices it is estimated from the on-demand price; live reports which was used.
+func (c *Client) spotInstancePrice(ctx context.Context, gpuType, region string, onDemandPrice float64) (price float64, live bool) {
+	if c.billing != nil {
+		machineType := c.getRecommendedMachineTypeForGPU(gpuType)
+		if price, err := c.billing.hourlyPrice(ctx, machineType, c.translateGPUTypeToGCP(gpuType), region, true); err == nil {
+			return price, true
+		}
+	}
+	return onDemandPrice * fallbackSpotPriceRatio, false
+}
+
 // getMachinePricing returns hourly pricing for machine types
 func (c *Client) getMachinePricing(machineType, region string) float64 {
 	// GCP machine type pricing (approximate USD per hour)
@@ -148,32 +179,36 @@ func (c *Client) getGPUOffersForZone(ctx context.Context, zone string, filters *
 
 		// Calculate pricing
 		machineType := c.getRecommendedMachineTypeForGPU(gpuType)
-		machinePrice := c.getMachinePricing(machineType, region)
-		gpuPrice := c.getGPUPricing(gpuType, region)
-		totalPrice := machinePrice + gpuPrice
+		totalPrice, _ := c.instancePrice(ctx, gpuType, region)
+		spotPrice, _ := c.spotInstancePrice(ctx, gpuType, region, totalPrice)
 
 		// Skip if over budget
 		if filters.MaxPrice > 0 && totalPrice > filters.MaxPrice {
 			continue
 		}
 
+		vcpus, gpuCount := machineTypeShape(machineType)
 		offer := &providers.GPUOffer{
 			ID:          fmt.Sprintf("gcp-%s-%s", zone, strings.ToLower(gpuType)),
 			Provider:    "gcp",
 			Region:      region,
 			GPUType:     gpuType,
+			GPUCount:    gpuCount,
+			VCPUs:       vcpus,
 			HourlyPrice: totalPrice,
-			SpotPrice:   totalPrice * 0.7, // Spot instances ~30% cheaper
-			Memory:      c.getGPUMemory(gpuType),
+			SpotPrice:   spotPrice,
+			GPUMemory:   c.getGPUMemory(gpuType),
 			Storage:     50, // Default 50GB SSD
 			Available:   true,
 			IsSpot:      false,
+			Verified:    true,
+			Country:     regionCountries[region],
 		}
 
 		offers = append(offers, offer)
 
-		// Add spot instance variant if not filtering spot only
-		if !filters.SpotOnly {
+		// Add spot instance variant unless only on-demand offers were requested
+		if !filters.OnDemandOnly {
 			spotOffer := *offer
 			spotOffer.ID = fmt.Sprintf("gcp-%s-%s-spot", zone, strings.ToLower(gpuType))
 			spotOffer.HourlyPrice = spotOffer.SpotPrice
@@ -280,6 +315,54 @@ func (c *Client) getRegionsToSearch(regionFilter string) []string {
 	return matchingRegions
 }
 
+// regionCountries maps GCP regions to the ISO 3166-1 alpha-2 code of the country they are in
+var regionCountries = map[string]string{
+	"us-central1":             "US",
+	"us-east1":                "US",
+	"us-east4":                "US",
+	"us-east5":                "US",
+	"us-south1":               "US",
+	"us-west1":                "US",
+	"us-west2":                "US",
+	"us-west3":                "US",
+	"us-west4":                "US",
+	"northamerica-northeast1": "CA",
+	"northamerica-northeast2": "CA",
+	"southamerica-east1":      "BR",
+	"europe-central2":         "PL",
+	"europe-north1":           "FI",
+	"europe-southwest1":       "ES",
+	"europe-west1":            "BE",
+	"europe-west2":            "GB",
+	"europe-west3":            "DE",
+	"europe-west4":            "NL",
+	"europe-west6":            "CH",
+	"europe-west8":            "IT",
+	"europe-west9":            "FR",
+	"europe-west10":           "DE",
+	"europe-west12":           "IT",
+	"asia-east1":              "TW",
+	"asia-east2":              "HK",
+	"asia-northeast1":         "JP",
+	"asia-northeast2":         "JP",
+	"asia-northeast3":         "KR",
+	"asia-south1":             "IN",
+	"asia-south2":             "IN",
+	"asia-southeast1":         "SG",
+	"asia-southeast2":         "ID",
+	"australia-southeast1":    "AU",
+	"australia-southeast2":    "AU",
+	"me-central1":             "QA",
+	"me-west1":                "IL",
+	"africa-south1":           "ZA",
+}
+
+// RegionCountry returns the country a GCP region is in
+func (c *Client) RegionCountry(region string) (string, bool) {
+	country, ok := regionCountries[region]
+	return country, ok
+}
+
 // getZonesForRegion returns zones for a given region
 func (c *Client) getZonesForRegion(region string) []string {
 	regionZones := map[string][]string{
@@ -342,6 +425,10 @@ func (c *Client) filterOffers(offers []providers.GPUOffer, filters *providers.GP
 			continue
 		}
 
+		if !providers.CountryAllowed(offer.Country, filters.Countries) {
+			continue
+		}
+
 		if filters.Region != "" && !strings.Contains(strings.ToLower(offer.Region), strings.ToLower(filters.Region)) {
 			continue
 		}
@@ -354,6 +441,14 @@ func (c *Client) filterOffers(offers []providers.GPUOffer, filters *providers.GP
 			continue
 		}
 
+		if !providers.VCPUsPerGPUAllowed(offer, filters.MinVCPUPerGPU) {
+			continue
+		}
+
+		if !providers.GPUMemoryAllowed(offer, filters.MinGPUMemoryGiB) {
+			continue
+		}
+
 		filtered = append(filtered, offer)
 	}
 
@@ -0,0 +1,264 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/api/cloudbilling/v1"
+)
+
+const (
+	// computeEngineServiceID is the Cloud Billing Catalog ID of the Compute Engine service
+	computeEngineServiceID = "6F81-5844-456A"
+
+	// DefaultPricingCacheTTL is how long live prices are reused before the catalog is queried again
+	DefaultPricingCacheTTL = 6 * time.Hour
+
+	// pricingErrorBackoff is how long a failed catalog query is reused before the catalog is queried
+	// again, so missing permissions do not cost an API call for every price lookup
+	pricingErrorBackoff = 10 * time.Minute
+)
+
+// Cloud Billing SKU usage types
+const (
+	usageTypeOnDemand = "OnDemand"
+	// usageTypePreemptible is the usage type of spot and legacy preemptible VM SKUs
+	usageTypePreemptible = "Preemptible"
+)
+
+// gpuSKUNames maps GCP accelerator types to the name their Cloud Billing SKUs describe them by
+var gpuSKUNames = map[string]string{
+	"nvidia-tesla-k80":  "Nvidia Tesla K80 GPU",
+	"nvidia-tesla-p4":   "Nvidia Tesla P4 GPU",
+	"nvidia-tesla-p100": "Nvidia Tesla P100 GPU",
+	"nvidia-tesla-v100": "Nvidia Tesla V100 GPU",
+	"nvidia-tesla-t4":   "Nvidia Tesla T4 GPU",
+	"nvidia-tesla-a100": "Nvidia Tesla A100 GPU",
+	"nvidia-a100-80gb":  "Nvidia Tesla A100 80GB GPU",
+	"nvidia-h100-80gb":  "Nvidia H100 80GB GPU",
+	"nvidia-l4":         "Nvidia L4 GPU",
+}
+
+// livePrice is a price computed from the catalog
+type livePrice struct {
+	hourly    float64
+	fetchedAt time.Time
+}
+
+// billingCatalog looks up on-demand prices in the Cloud Billing Catalog, caching the
+// Compute Engine SKUs and the prices computed from them
+type billingCatalog struct {
+	mutex sync.Mutex
+	ttl   time.Duration
+	// listSKUs fetches every Compute Engine SKU
+	listSKUs func(ctx context.Context) ([]*cloudbilling.Sku, error)
+
+	skus          []*cloudbilling.Sku
+	skusErr       error
+	skusFetchedAt time.Time
+	// prices is keyed by machine type and region
+	prices map[string]livePrice
+}
+
+// newBillingCatalog creates a catalog that refreshes prices once per ttl
+func newBillingCatalog(ttl time.Duration, listSKUs func(ctx context.Context) ([]*cloudbilling.Sku, error)) *billingCatalog {
+	return &billingCatalog{
+		ttl:      ttl,
+		listSKUs: listSKUs,
+		prices:   make(map[string]livePrice),
+	}
+}
+
+// listComputeSKUs fetches every Compute Engine SKU priced in USD
+func (c *Client) listComputeSKUs(ctx context.Context) ([]*cloudbilling.Sku, error) {
+	service, err := cloudbilling.NewService(ctx, c.clientOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cloud billing client: %w", err)
+	}
+
+	var skus []*cloudbilling.Sku
+	err = service.Services.Skus.List("services/"+computeEngineServiceID).
+		CurrencyCode("USD").
+		PageSize(5000).
+		Pages(ctx, func(page *cloudbilling.ListSkusResponse) error {
+			skus = append(skus, page.Skus...)
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list compute SKUs: %w", err)
+	}
+	return skus, nil
+}
+
+// hourlyPrice returns the live on-demand or spot price of a machine type with its GPUs in a region
+func (b *billingCatalog) hourlyPrice(ctx context.Context, machineType, acceleratorType, region string, spot bool) (float64, error) {
+	usageType := usageTypeOnDemand
+	if spot {
+		usageType = usageTypePreemptible
+	}
+	key := machineType + "/" + acceleratorType + "/" + region + "/" + usageType
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if cached, exists := b.prices[key]; exists && time.Since(cached.fetchedAt) < b.ttl {
+		return cached.hourly, nil
+	}
+
+	skus, err := b.computeSKUs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	price, err := machineTypePrice(skus, machineType, acceleratorType, region, usageType)
+	if err != nil {
+		return 0, err
+	}
+	b.prices[key] = livePrice{hourly: price, fetchedAt: time.Now()}
+	return price, nil
+}
+
+// computeSKUs returns the cached Compute Engine SKUs, fetching them when expired.
+// The caller must hold the mutex.
+func (b *billingCatalog) computeSKUs(ctx context.Context) ([]*cloudbilling.Sku, error) {
+	if !b.skusFetchedAt.IsZero() {
+		age := time.Since(b.skusFetchedAt)
+		if b.skusErr == nil && age < b.ttl {
+			return b.skus, nil
+		}
+		if b.skusErr != nil && age < pricingErrorBackoff {
+			return nil, b.skusErr
+		}
+	}
+
+	skus, err := b.listSKUs(ctx)
+	b.skusFetchedAt = time.Now()
+	b.skusErr = err
+	if err != nil {
+		return nil, err
+	}
+	b.skus = skus
+	return skus, nil
+}
+
+// machineTypePrice computes the hourly price of a machine type and its GPUs from the SKUs of the usage type
+func machineTypePrice(skus []*cloudbilling.Sku, machineType, acceleratorType, region, usageType string) (float64, error) {
+	vcpus, gpus := machineTypeShape(machineType)
+	memoryGiB := machineTypeMemoryGiB(machineType)
+	if vcpus == 0 || memoryGiB == 0 {
+		return 0, fmt.Errorf("unknown shape for machine type %s", machineType)
+	}
+
+	family := strings.ToUpper(strings.SplitN(machineType, "-", 2)[0])
+	corePrice, err := findSKUPrice(skus, region, usageType, family+" Instance Core", family+" Predefined Instance Core")
+	if err != nil {
+		return 0, err
+	}
+	ramPrice, err := findSKUPrice(skus, region, usageType, family+" Instance Ram", family+" Predefined Instance Ram")
+	if err != nil {
+		return 0, err
+	}
+
+	gpuName, exists := gpuSKUNames[acceleratorType]
+	if !exists {
+		return 0, fmt.Errorf("no billing SKU known for accelerator %s", acceleratorType)
+	}
+	gpuPrice, err := findSKUPrice(skus, region, usageType, gpuName)
+	if err != nil {
+		return 0, err
+	}
+
+	return float64(vcpus)*corePrice + memoryGiB*ramPrice + float64(gpus)*gpuPrice, nil
+}
+
+// findSKUPrice returns the unit price of the SKU of the usage type in the region whose description
+// is one of the given names followed by its location, e.g. "N1 Predefined Instance Core running in Americas"
+func findSKUPrice(skus []*cloudbilling.Sku, region, usageType string, names ...string) (float64, error) {
+	for _, sku := range skus {
+		if sku.Category == nil || sku.Category.UsageType != usageType || !skuInRegion(sku, region) {
+			continue
+		}
+		for _, name := range names {
+			for _, description := range skuDescriptions(name, usageType) {
+				if strings.HasPrefix(sku.Description, description+" running in") {
+					if price, ok := skuUnitPrice(sku); ok {
+						return price, nil
+					}
+				}
+			}
+		}
+	}
+	return 0, fmt.Errorf("no %s SKU for %s in %s", usageType, names[0], region)
+}
+
+// skuDescriptions returns the descriptions a resource's SKUs of the usage type may have before
+// their location. Spot SKUs are described as "Spot Preemptible N1 Predefined Instance Core",
+// "Nvidia Tesla T4 GPU attached to Spot Preemptible VMs" or, for older SKUs, with a
+// "Preemptible" prefix.
+func skuDescriptions(name, usageType string) []string {
+	if usageType != usageTypePreemptible {
+		return []string{name}
+	}
+	return []string{
+		"Spot Preemptible " + name,
+		name + " attached to Spot Preemptible VMs",
+		"Preemptible " + name,
+		name,
+	}
+}
+
+// skuInRegion reports whether the SKU is sold in the region
+func skuInRegion(sku *cloudbilling.Sku, region string) bool {
+	for _, serviceRegion := range sku.ServiceRegions {
+		if serviceRegion == region {
+			return true
+		}
+	}
+	return false
+}
+
+// skuUnitPrice returns the USD unit price of the SKU's current pricing, using its highest usage tier
+func skuUnitPrice(sku *cloudbilling.Sku) (float64, bool) {
+	if len(sku.PricingInfo) == 0 || sku.PricingInfo[0].PricingExpression == nil {
+		return 0, false
+	}
+	rates := sku.PricingInfo[0].PricingExpression.TieredRates
+	if len(rates) == 0 || rates[len(rates)-1].UnitPrice == nil {
+		return 0, false
+	}
+	unitPrice := rates[len(rates)-1].UnitPrice
+	return float64(unitPrice.Units) + float64(unitPrice.Nanos)/1e9, true
+}
+
+// machineTypeMemoryGiB returns the memory of a predefined machine type, or zero if unknown
+func machineTypeMemoryGiB(machineType string) float64 {
+	if machineType == "a3-highgpu-8g" {
+		return 1872
+	}
+
+	parts := strings.Split(machineType, "-")
+	last := parts[len(parts)-1]
+	switch {
+	case strings.HasPrefix(machineType, "a2-highgpu-"), strings.HasPrefix(machineType, "a2-ultragpu-"):
+		gpus, err := strconv.Atoi(strings.TrimSuffix(last, "g"))
+		if err != nil {
+			return 0
+		}
+		if strings.HasPrefix(machineType, "a2-ultragpu-") {
+			return float64(gpus) * 170
+		}
+		return float64(gpus) * 85
+	case strings.HasPrefix(machineType, "n1-standard-"):
+		vcpus, _ := strconv.Atoi(last)
+		return float64(vcpus) * 3.75
+	case strings.HasPrefix(machineType, "n2-standard-"), strings.HasPrefix(machineType, "g2-standard-"):
+		vcpus, _ := strconv.Atoi(last)
+		return float64(vcpus) * 4
+	default:
+		return 0
+	}
+}
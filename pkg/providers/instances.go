@@ -0,0 +1,38 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrListingNotSupported is returned for providers that cannot list the instances the
+// operator launched
+var ErrListingNotSupported = errors.New("listing operator instances is not supported")
+
+// ManagedInstance is an instance the operator launched, as found at the provider
+type ManagedInstance struct {
+	GPUInstance
+	// NodePool is the GPUNodePool label the launch carried, in the provider's label format
+	NodePool string
+}
+
+// InstanceLister is implemented by providers that can find the instances the operator
+// launched from the labels or tags each launch carries, so leaked instances can be
+// recovered without a matching Kubernetes object.
+type InstanceLister interface {
+	// ListManagedInstances returns the operator's instances that still exist, including
+	// stopped ones that keep incurring storage costs. An
+	// empty region lists the client's default region, or every region where the provider
+	// lists them in one call.
+	ListManagedInstances(ctx context.Context, region string) ([]ManagedInstance, error)
+}
+
+// ListManagedInstances returns the instances the operator launched with the provider
+func ListManagedInstances(ctx context.Context, client ProviderClient, region string) ([]ManagedInstance, error) {
+	lister, ok := Unwrap(client).(InstanceLister)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", client.GetProviderInfo().Name, ErrListingNotSupported)
+	}
+	return lister.ListManagedInstances(ctx, region)
+}
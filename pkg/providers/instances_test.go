@@ -0,0 +1,38 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// listingClient lists a fixed set of managed instances
+type listingClient struct {
+	flakyClient
+	instances []ManagedInstance
+	region    string
+}
+
+func (c *listingClient) ListManagedInstances(ctx context.Context, region string) ([]ManagedInstance, error) {
+	c.region = region
+	return c.instances, nil
+}
+
+func TestListManagedInstances(t *testing.T) {
+	ctx := context.Background()
+
+	if _, err := ListManagedInstances(ctx, &flakyClient{}, ""); !errors.Is(err, ErrListingNotSupported) {
+		t.Errorf("expected providers without listing to be unsupported, got %v", err)
+	}
+
+	raw := &listingClient{instances: []ManagedInstance{{GPUInstance: GPUInstance{ID: "i-1"}, NodePool: "training"}}}
+	client := NewCircuitBreakers(3, time.Minute).Wrap(raw)
+	instances, err := ListManagedInstances(ctx, client, "us-east")
+	if err != nil || len(instances) != 1 || instances[0].ID != "i-1" {
+		t.Errorf("expected the instances through the wrapper, got %+v (err %v)", instances, err)
+	}
+	if raw.region != "us-east" {
+		t.Errorf("expected the region to be passed on, got %q", raw.region)
+	}
+}
@@ -2,6 +2,7 @@ package providers
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	v1 "github.com/solanyn/tgp-operator/pkg/api/v1"
@@ -14,6 +15,10 @@ type ProviderClient interface {
 	TerminateInstance(ctx context.Context, instanceID string) error
 	GetInstanceStatus(ctx context.Context, instanceID string) (*InstanceStatus, error)
 
+	// Data disk operations for existing provider volumes
+	AttachDataDisk(ctx context.Context, instanceID string, disk DataDisk) error
+	DetachDataDisk(ctx context.Context, instanceID, volumeID string) error
+
 	// Discovery and pricing with normalization
 	ListAvailableGPUs(ctx context.Context, filters *GPUFilters) ([]GPUOffer, error)
 	GetNormalizedPricing(ctx context.Context, gpuType, region string) (*NormalizedPricing, error)
@@ -30,6 +35,7 @@ type ProviderClient interface {
 // LaunchRequest contains all parameters needed to launch an instance
 type LaunchRequest struct {
 	GPUType      string
+	GPUCount     int // GPUs the instance must have; 0 means one
 	Region       string
 	Image        string
 	UserData     string
@@ -37,18 +43,57 @@ type LaunchRequest struct {
 	SpotInstance bool
 	MaxPrice     float64 // Per hour in USD
 	TalosConfig  *v1.TalosConfig
+	DataDisks    []DataDisk        // Existing volumes to attach at launch
+	Tags         map[string]string // Cost-allocation tags from the node class
+	BootDiskGiB  int               // Boot disk size; 0 uses the provider's default
+
+	// MinCUDAVersion is the oldest CUDA version the host's driver must support, as major.minor;
+	// empty allows any. Providers that report their hosts' drivers fail launches that cannot meet it.
+	MinCUDAVersion string
+
+	// NameCollisionRetries is how many fresh instance names to try if the generated name is taken
+	NameCollisionRetries int
+}
+
+// RequestedGPUs returns the number of GPUs the launched instance must have, at least one
+func (r *LaunchRequest) RequestedGPUs() int {
+	return max(r.GPUCount, 1)
+}
+
+// BootDiskSize returns the boot disk size in GiB for the launch: the requested size, or
+// defaultGiB when none is requested. Sizes below the provider's minimum are rejected.
+func (r *LaunchRequest) BootDiskSize(defaultGiB, minimumGiB int) (int, error) {
+	if r.BootDiskGiB == 0 {
+		return defaultGiB, nil
+	}
+	if r.BootDiskGiB < minimumGiB {
+		return 0, fmt.Errorf("boot disk of %dGiB is below the provider minimum of %dGiB", r.BootDiskGiB, minimumGiB)
+	}
+	return r.BootDiskGiB, nil
+}
+
+// DataDisk references an existing provider volume to attach to an instance
+type DataDisk struct {
+	VolumeID   string // Provider-specific volume ID, name or self link
+	ReadOnly   bool
+	DeviceName string // Optional device name hint
 }
 
 type GPUFilters struct {
 	GPUType         string
 	Region          string
 	MaxPrice        float64
-	MinMemory       int64
+	MinMemory       int64 // Minimum system memory in GB
 	MinStorage      int64
 	SpotOnly        bool
 	OnDemandOnly    bool
 	PreferredVendor string
 	WorkloadType    string
+	VerifiedOnly    bool
+	Countries       []string // ISO 3166-1 alpha-2 codes offers must be located in
+	MinVCPUPerGPU   int      // Minimum vCPUs the offer must have for each GPU
+	MinGPUMemoryGiB int64    // Minimum memory each GPU must have, in GiB
+	MinCUDAVersion  string   // Oldest CUDA version the host's driver must support, as major.minor
 }
 
 // NormalizedPricing provides standardized pricing across providers
@@ -72,6 +117,31 @@ const (
 	BillingPerHour   BillingModel = "per-hour"
 )
 
+// ReliabilityTier describes how reliable a provider's capacity is
+type ReliabilityTier string
+
+const (
+	// ReliabilityTierCommunity is capacity on unvetted marketplace hosts
+	ReliabilityTierCommunity ReliabilityTier = "Community"
+	// ReliabilityTierVerified is marketplace capacity on hosts vetted by the provider
+	ReliabilityTierVerified ReliabilityTier = "Verified"
+	// ReliabilityTierEnterprise is capacity in provider-operated datacenters
+	ReliabilityTierEnterprise ReliabilityTier = "Enterprise"
+)
+
+// reliabilityTierRanks orders tiers from least to most reliable
+var reliabilityTierRanks = map[ReliabilityTier]int{
+	ReliabilityTierCommunity:  0,
+	ReliabilityTierVerified:   1,
+	ReliabilityTierEnterprise: 2,
+}
+
+// Meets reports whether the tier is at least as reliable as minimum.
+// Unknown tiers are treated as Community.
+func (t ReliabilityTier) Meets(minimum ReliabilityTier) bool {
+	return reliabilityTierRanks[t] >= reliabilityTierRanks[minimum]
+}
+
 // ProviderInfo contains metadata about provider capabilities
 type ProviderInfo struct {
 	Name                  string
@@ -82,6 +152,15 @@ type ProviderInfo struct {
 	SupportsMultiGPU      bool
 	BillingGranularity    BillingModel
 	MinBillingPeriod      time.Duration
+	ReliabilityTier       ReliabilityTier
+
+	// AttachesDataDisksAfterLaunch is set by providers that cannot attach data disks in the
+	// launch request; they are attached with AttachDataDisk once the instance is running
+	AttachesDataDisksAfterLaunch bool
+
+	// SupportsReadOnlyDataDisks is set by providers that can attach data disks read-only, so
+	// several instances can share them
+	SupportsReadOnlyDataDisks bool
 }
 
 // RateLimitInfo contains rate limiting information for the provider
@@ -100,6 +179,7 @@ type GPUInstance struct {
 	PrivateIP string
 	Status    InstanceState
 	CreatedAt time.Time
+	IsSpot    bool
 }
 
 // InstanceStatus represents the current status of an instance
@@ -157,12 +237,17 @@ type GPUOffer struct {
 	Region      string
 	HourlyPrice float64
 	SpotPrice   float64
-	Memory      int64 // GB
+	Memory      int64 // System memory in GB, if known
+	GPUMemory   int64 // Memory of each GPU in GiB, if known
 	Storage     int64 // GB
 	Bandwidth   int64 // Mbps
 	IsSpot      bool
 	Available   bool
 	Provider    string
+	Verified    bool   // Host has been vetted by the provider
+	Country     string // ISO 3166-1 alpha-2 code of the offer's location, if known
+	VCPUs       int    // vCPUs on the offer's instance or host, if known
+	CUDAVersion string // Newest CUDA version the host's driver supports, as major.minor, if known
 }
 
 // ProviderCredentials contains authentication credentials for a provider
@@ -0,0 +1,27 @@
+package providers
+
+import "testing"
+
+func TestBootDiskSize(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested int
+		want      int
+		wantErr   bool
+	}{
+		{"no size uses the default", 0, 50, false},
+		{"requested size is used", 200, 200, false},
+		{"minimum size is allowed", 20, 20, false},
+		{"size below the minimum is rejected", 10, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &LaunchRequest{BootDiskGiB: tt.requested}
+			got, err := req.BootDiskSize(50, 20)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("BootDiskSize() = %d, %v, want %d, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// DefaultMaxConcurrentLaunches is how many launch calls are made to a provider at once when
+// no limit is configured, serializing launches per provider
+const DefaultMaxConcurrentLaunches = 1
+
+// LaunchLimiters bounds the concurrent launch calls to each provider, as some providers
+// throttle or fail concurrent creates. Launches to different providers do not wait on each
+// other. Provider clients are recreated on every reconcile, so the launches in flight are
+// counted here by provider name and shared by every client wrapped with Wrap.
+type LaunchLimiters struct {
+	mu       sync.Mutex
+	limit    func(provider string) int
+	inFlight map[string]int
+	// released is closed and replaced whenever a launch finishes, waking the waiting launches
+	released chan struct{}
+}
+
+// NewLaunchLimiters creates launch limiters allowing limit(provider) concurrent launches per
+// provider. The limit is read for every launch, so a changed limit applies to the next ones.
+// A nil limit or a limit below one uses DefaultMaxConcurrentLaunches.
+func NewLaunchLimiters(limit func(provider string) int) *LaunchLimiters {
+	return &LaunchLimiters{
+		limit:    limit,
+		inFlight: make(map[string]int),
+		released: make(chan struct{}),
+	}
+}
+
+// Wrap returns a client whose launches wait for one of the provider's launch slots.
+// A nil LaunchLimiters returns the client unchanged.
+func (l *LaunchLimiters) Wrap(client ProviderClient) ProviderClient {
+	if l == nil || client == nil {
+		return client
+	}
+	return &launchLimitedClient{ProviderClient: client, limiters: l, provider: client.GetProviderInfo().Name}
+}
+
+// limitFor returns the provider's current launch limit
+func (l *LaunchLimiters) limitFor(provider string) int {
+	if l.limit != nil {
+		if limit := l.limit(provider); limit > 0 {
+			return limit
+		}
+	}
+	return DefaultMaxConcurrentLaunches
+}
+
+// acquire waits for a launch slot of the provider, or until the context is done
+func (l *LaunchLimiters) acquire(ctx context.Context, provider string) error {
+	for {
+		l.mu.Lock()
+		if l.inFlight[provider] < l.limitFor(provider) {
+			l.inFlight[provider]++
+			l.mu.Unlock()
+			return nil
+		}
+		released := l.released
+		l.mu.Unlock()
+
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// release frees a launch slot of the provider and wakes the waiting launches
+func (l *LaunchLimiters) release(provider string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight[provider]--
+	close(l.released)
+	l.released = make(chan struct{})
+}
+
+// launchLimitedClient makes launches wait for a launch slot of the provider
+type launchLimitedClient struct {
+	ProviderClient
+	limiters *LaunchLimiters
+	provider string
+}
+
+// Unwrap returns the limited client so its optional capabilities can be detected
+func (c *launchLimitedClient) Unwrap() ProviderClient {
+	return c.ProviderClient
+}
+
+func (c *launchLimitedClient) LaunchInstance(ctx context.Context, req *LaunchRequest) (*GPUInstance, error) {
+	if err := c.limiters.acquire(ctx, c.provider); err != nil {
+		return nil, fmt.Errorf("waiting for a %s launch slot: %w", c.provider, err)
+	}
+	defer c.limiters.release(c.provider)
+
+	return c.ProviderClient.LaunchInstance(ctx, req)
+}
@@ -0,0 +1,123 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingLaunchClient holds every launch until release is closed, tracking the peak number
+// of concurrent launches
+type blockingLaunchClient struct {
+	flakyClient
+	name    string
+	release chan struct{}
+
+	mu      sync.Mutex
+	running int
+	peak    int
+}
+
+func (c *blockingLaunchClient) GetProviderInfo() *ProviderInfo {
+	return &ProviderInfo{Name: c.name}
+}
+
+func (c *blockingLaunchClient) LaunchInstance(ctx context.Context, req *LaunchRequest) (*GPUInstance, error) {
+	c.mu.Lock()
+	c.running++
+	c.peak = max(c.peak, c.running)
+	c.mu.Unlock()
+
+	<-c.release
+
+	c.mu.Lock()
+	c.running--
+	c.mu.Unlock()
+	return &GPUInstance{ID: "i-1"}, nil
+}
+
+func TestLaunchLimiters(t *testing.T) {
+	limiters := NewLaunchLimiters(func(provider string) int {
+		if provider == "parallel" {
+			return 2
+		}
+		return 0
+	})
+	release := make(chan struct{})
+	serial := &blockingLaunchClient{name: "serial", release: release}
+	parallel := &blockingLaunchClient{name: "parallel", release: release}
+
+	var wg sync.WaitGroup
+	for _, raw := range []*blockingLaunchClient{serial, serial, serial, parallel, parallel, parallel} {
+		client := limiters.Wrap(raw)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.LaunchInstance(context.Background(), &LaunchRequest{}); err != nil {
+				t.Errorf("LaunchInstance() error = %v", err)
+			}
+		}()
+	}
+
+	// Let the launches that got a slot reach the provider before releasing them
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if serial.peak != DefaultMaxConcurrentLaunches {
+		t.Errorf("expected at most %d concurrent launch by default, got %d", DefaultMaxConcurrentLaunches, serial.peak)
+	}
+	if parallel.peak != 2 {
+		t.Errorf("expected the configured 2 concurrent launches, got %d", parallel.peak)
+	}
+}
+
+func TestLaunchLimitersCancelledWait(t *testing.T) {
+	limiters := NewLaunchLimiters(nil)
+	raw := &blockingLaunchClient{name: "serial", release: make(chan struct{})}
+	client := limiters.Wrap(raw)
+
+	go client.LaunchInstance(context.Background(), &LaunchRequest{})
+	defer close(raw.release)
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.LaunchInstance(ctx, &LaunchRequest{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the wait for a slot to end with the context, got %v", err)
+	}
+	if Unwrap(client) != raw {
+		t.Error("expected Unwrap to return the limited client")
+	}
+}
+
+func TestLaunchLimitersReadChangedLimit(t *testing.T) {
+	var mu sync.Mutex
+	limit := 1
+	limiters := NewLaunchLimiters(func(string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return limit
+	})
+	raw := &blockingLaunchClient{name: "changing", release: make(chan struct{})}
+	client := limiters.Wrap(raw)
+
+	go client.LaunchInstance(context.Background(), &LaunchRequest{})
+	defer close(raw.release)
+	time.Sleep(10 * time.Millisecond)
+
+	// Raising the limit admits another launch while the first is still running
+	mu.Lock()
+	limit = 2
+	mu.Unlock()
+	go client.LaunchInstance(context.Background(), &LaunchRequest{})
+	time.Sleep(10 * time.Millisecond)
+
+	raw.mu.Lock()
+	defer raw.mu.Unlock()
+	if raw.running != 2 {
+		t.Errorf("expected 2 launches running after raising the limit, got %d", raw.running)
+	}
+}
@@ -0,0 +1,25 @@
+package providers
+
+import "strings"
+
+// RegionLocator is implemented by providers that know which country each of their
+// regions is in, so offers can be restricted for data residency.
+type RegionLocator interface {
+	// RegionCountry returns the ISO 3166-1 alpha-2 code of the country the region is in
+	RegionCountry(region string) (country string, ok bool)
+}
+
+// CountryAllowed reports whether a location in the given ISO 3166-1 alpha-2 country
+// satisfies the allowed countries. No allowed countries permits any location, while an
+// unknown country never satisfies a restriction.
+func CountryAllowed(country string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, code := range allowed {
+		if country != "" && strings.EqualFold(code, country) {
+			return true
+		}
+	}
+	return false
+}
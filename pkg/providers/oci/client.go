@@ -0,0 +1,399 @@
+// Package oci implements the provider client for Oracle Cloud Infrastructure GPU shapes
+package oci
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+const ProviderName = "oci"
+
+// Client implements the ProviderClient interface for Oracle Cloud Infrastructure
+type Client struct {
+	apiKey APIKey
+	signer *requestSigner
+	// transport replaces the default HTTP transport when debug logging is enabled
+	transport http.RoundTripper
+
+	mutex sync.Mutex
+	api   computeAPI
+}
+
+// NewClient creates a new OCI provider client from an API key JSON blob or an OCI CLI
+// config file
+func NewClient(credentials string) (*Client, error) {
+	apiKey, err := parseCredentials(credentials)
+	if err != nil {
+		return nil, err
+	}
+	key, err := parsePrivateKey(apiKey.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		apiKey: apiKey,
+		signer: newRequestSigner(apiKey.Tenancy, apiKey.User, apiKey.Fingerprint, key),
+	}, nil
+}
+
+// EnableDebugLogging logs every OCI API request and response at debug level
+func (c *Client) EnableDebugLogging(log logr.Logger) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.transport = providers.NewDebugTransport(nil, log)
+	c.api = nil
+}
+
+// computeAPI returns the OCI API client, creating it on first use
+func (c *Client) computeAPI() computeAPI {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.api == nil {
+		c.api = &restComputeAPI{
+			signer:     c.signer,
+			httpClient: &http.Client{Transport: c.transport, Timeout: time.Minute},
+			endpoint:   serviceEndpoint,
+		}
+	}
+	return c.api
+}
+
+// GetProviderInfo returns information about the OCI provider
+func (c *Client) GetProviderInfo() *providers.ProviderInfo {
+	return &providers.ProviderInfo{
+		Name:                  ProviderName,
+		APIVersion:            apiVersion,
+		SupportedRegions:      c.regions(),
+		SupportedGPUTypes:     supportedGPUTypes(),
+		SupportsSpotInstances: true,
+		SupportsMultiGPU:      true,
+		BillingGranularity:    providers.BillingPerSecond,
+		MinBillingPeriod:      time.Minute,
+		ReliabilityTier:       providers.ReliabilityTierEnterprise,
+
+		SupportsReadOnlyDataDisks: true,
+	}
+}
+
+// GetRateLimits returns the rate limits for the OCI Core API
+func (c *Client) GetRateLimits() *providers.RateLimitInfo {
+	return &providers.RateLimitInfo{
+		RequestsPerSecond: 10,
+		RequestsPerMinute: 300,
+		BurstCapacity:     20,
+		BackoffStrategy:   "exponential",
+		ResetWindow:       time.Minute,
+	}
+}
+
+// CheckCredentials lists the tenancy's availability domains in the credentials' region,
+// which fails when the API key is invalid
+func (c *Client) CheckCredentials(ctx context.Context) error {
+	if _, err := c.computeAPI().ListAvailabilityDomains(ctx, c.apiKey.Region, c.apiKey.Tenancy); err != nil {
+		return fmt.Errorf("failed to list OCI availability domains in %s: %w", c.apiKey.Region, err)
+	}
+	return nil
+}
+
+// regions returns the regions instances can be launched in: those with a configured subnet, sorted
+func (c *Client) regions() []string {
+	regions := make([]string, 0, len(c.apiKey.Subnets))
+	for region := range c.apiKey.Subnets {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+// ListAvailableGPUs returns the GPU shapes matching the filters that the compartment can
+// launch in each region with a subnet
+func (c *Client) ListAvailableGPUs(ctx context.Context, filters *providers.GPUFilters) ([]providers.GPUOffer, error) {
+	if filters == nil {
+		filters = &providers.GPUFilters{}
+	}
+
+	regions := c.regions()
+	if filters.Region != "" {
+		region, err := c.TranslateRegion(filters.Region)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := c.apiKey.Subnets[region]; !exists {
+			return nil, nil
+		}
+		regions = []string{region}
+	}
+
+	api := c.computeAPI()
+	var offers []providers.GPUOffer
+	for _, region := range regions {
+		if !providers.CountryAllowed(regionCountries[region], filters.Countries) {
+			continue
+		}
+		shapes, err := api.ListShapes(ctx, region, c.apiKey.CompartmentID, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list shapes in %s: %w", region, err)
+		}
+		offered := shapeNames(shapes)
+		for _, gpuType := range supportedGPUTypes() {
+			for _, shape := range gpuShapes[gpuType] {
+				if offered[shape.Name] {
+					offers = append(offers, shapeOffers(shape, region)...)
+				}
+			}
+		}
+	}
+
+	return filterOffers(offers, filters), nil
+}
+
+// GetNormalizedPricing returns on-demand pricing for the smallest shape with the GPU type.
+// OCI prices are the same in every region.
+func (c *Client) GetNormalizedPricing(ctx context.Context, gpuType, region string) (*providers.NormalizedPricing, error) {
+	shape, err := lookupShape(gpuType, 1)
+	if err != nil {
+		return nil, err
+	}
+	if region != "" {
+		if _, err := c.TranslateRegion(region); err != nil {
+			return nil, err
+		}
+	}
+
+	return &providers.NormalizedPricing{
+		PricePerHour:   shape.HourlyPrice,
+		PricePerSecond: shape.HourlyPrice / 3600,
+		Currency:       "USD",
+		BillingModel:   providers.BillingPerSecond,
+		LastUpdated:    time.Now(),
+		ProviderSpecific: map[string]interface{}{
+			"shape":            shape.Name,
+			"gpuCount":         shape.GPUCount,
+			"preemptiblePrice": shape.HourlyPrice * preemptiblePriceFactor,
+		},
+	}, nil
+}
+
+// LaunchInstance launches an instance of the smallest shape with the requested GPUs from the
+// Talos image, trying each availability domain offering the shape until one has capacity
+func (c *Client) LaunchInstance(ctx context.Context, req *providers.LaunchRequest) (*providers.GPUInstance, error) {
+	shape, err := lookupShape(req.GPUType, req.RequestedGPUs())
+	if err != nil {
+		return nil, err
+	}
+
+	region, subnetID, err := c.launchRegion(req.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	bootVolumeGB, err := req.BootDiskSize(defaultBootVolumeGB, minBootVolumeGB)
+	if err != nil {
+		return nil, err
+	}
+
+	api := c.computeAPI()
+	imageID, err := c.resolveImage(ctx, api, region, req.Image)
+	if err != nil {
+		return nil, err
+	}
+
+	domains, err := c.shapeAvailabilityDomains(ctx, api, region, shape.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	details := buildLaunchDetails(generateInstanceName(req), c.apiKey.CompartmentID, subnetID, imageID, bootVolumeGB, shape, req)
+	var instance *Instance
+	for _, domain := range domains {
+		details.AvailabilityDomain = domain
+		instance, err = api.LaunchInstance(ctx, region, details)
+		if err == nil || !isOutOfCapacity(err) {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to launch instance %s: %w", details.DisplayName, err)
+	}
+
+	result := &providers.GPUInstance{
+		ID:        formatInstanceID(region, instance.ID),
+		Status:    mapLifecycleState(instance.LifecycleState),
+		CreatedAt: time.Now(),
+		IsSpot:    req.SpotInstance,
+	}
+	if !instance.TimeCreated.IsZero() {
+		result.CreatedAt = instance.TimeCreated
+	}
+	return result, nil
+}
+
+// launchRegion returns the region to launch in and the subnet instances join there
+func (c *Client) launchRegion(region string) (string, string, error) {
+	if region == "" {
+		regions := c.regions()
+		if len(regions) == 0 {
+			return "", "", fmt.Errorf("OCI credentials must configure a subnet for at least one region")
+		}
+		region = regions[0]
+	}
+
+	region, err := c.TranslateRegion(region)
+	if err != nil {
+		return "", "", err
+	}
+	subnetID, exists := c.apiKey.Subnets[region]
+	if !exists {
+		return "", "", fmt.Errorf("no subnet configured for OCI region %s", region)
+	}
+	return region, subnetID, nil
+}
+
+// shapeAvailabilityDomains returns the availability domains of a region that offer a shape
+func (c *Client) shapeAvailabilityDomains(ctx context.Context, api computeAPI, region, shape string) ([]string, error) {
+	domains, err := api.ListAvailabilityDomains(ctx, region, c.apiKey.Tenancy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list availability domains in %s: %w", region, err)
+	}
+
+	var offering []string
+	for _, domain := range domains {
+		shapes, err := api.ListShapes(ctx, region, c.apiKey.CompartmentID, domain)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list shapes in %s: %w", domain, err)
+		}
+		if shapeNames(shapes)[shape] {
+			offering = append(offering, domain)
+		}
+	}
+	if len(offering) == 0 {
+		return nil, fmt.Errorf("shape %s is not available to the compartment in %s", shape, region)
+	}
+	return offering, nil
+}
+
+// TerminateInstance terminates an instance and deletes its boot volume.
+// Instances that no longer exist are treated as terminated.
+func (c *Client) TerminateInstance(ctx context.Context, instanceID string) error {
+	region, id, err := c.parseInstanceID(instanceID)
+	if err != nil {
+		return err
+	}
+	if err := c.computeAPI().TerminateInstance(ctx, region, id); err != nil && !isNotFound(err) {
+		return fmt.Errorf("failed to terminate instance %s: %w", id, err)
+	}
+	return nil
+}
+
+// GetInstanceStatus returns the current status of an instance
+func (c *Client) GetInstanceStatus(ctx context.Context, instanceID string) (*providers.InstanceStatus, error) {
+	region, id, err := c.parseInstanceID(instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	api := c.computeAPI()
+	instance, err := api.GetInstance(ctx, region, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance %s: %w", id, err)
+	}
+
+	status := &providers.InstanceStatus{
+		State:     mapLifecycleState(instance.LifecycleState),
+		UpdatedAt: time.Now(),
+	}
+	if status.State == providers.InstanceStateRunning {
+		// Addresses are informational; the node joins the cluster over its own network
+		status.PublicIP, status.PrivateIP, _ = api.InstanceAddresses(ctx, region, instance.CompartmentID, id)
+	}
+	return status, nil
+}
+
+// AttachDataDisk attaches an existing block volume to an instance
+func (c *Client) AttachDataDisk(ctx context.Context, instanceID string, disk providers.DataDisk) error {
+	region, id, err := c.parseInstanceID(instanceID)
+	if err != nil {
+		return err
+	}
+
+	err = c.computeAPI().AttachVolume(ctx, region, AttachVolumeDetails{
+		Type:       volumeAttachmentType,
+		InstanceID: id,
+		VolumeID:   disk.VolumeID,
+		IsReadOnly: disk.ReadOnly,
+		Device:     volumeDevice(disk.DeviceName),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to attach volume %s: %w", disk.VolumeID, err)
+	}
+	return nil
+}
+
+// DetachDataDisk detaches a block volume from an instance without deleting it
+func (c *Client) DetachDataDisk(ctx context.Context, instanceID, volumeID string) error {
+	region, id, err := c.parseInstanceID(instanceID)
+	if err != nil {
+		return err
+	}
+	if err := c.computeAPI().DetachVolume(ctx, region, c.apiKey.CompartmentID, id, volumeID); err != nil {
+		return fmt.Errorf("failed to detach volume %s: %w", volumeID, err)
+	}
+	return nil
+}
+
+// TranslateGPUType translates a standard GPU type to the smallest OCI shape that provides it
+func (c *Client) TranslateGPUType(standard string) (string, error) {
+	shape, err := lookupShape(standard, 1)
+	if err != nil {
+		return "", err
+	}
+	return shape.Name, nil
+}
+
+// TranslateRegion translates standard regions to OCI regions. OCI region identifiers are used directly.
+func (c *Client) TranslateRegion(standard string) (string, error) {
+	if region, exists := standardRegions[standard]; exists {
+		return region, nil
+	}
+	region := strings.ToLower(standard)
+	if _, exists := regionCountries[region]; exists {
+		return region, nil
+	}
+	if _, exists := c.apiKey.Subnets[region]; exists {
+		return region, nil
+	}
+	return "", fmt.Errorf("unsupported region: %s", standard)
+}
+
+// RegionCountry returns the country an OCI region is in
+func (c *Client) RegionCountry(region string) (string, bool) {
+	country, ok := regionCountries[region]
+	return country, ok
+}
+
+// parseInstanceID splits a "region/ocid" ID. IDs without a region use the credentials' region.
+func (c *Client) parseInstanceID(instanceID string) (region, id string, err error) {
+	if instanceID == "" {
+		return "", "", fmt.Errorf("instance ID is required")
+	}
+	if region, id, found := strings.Cut(instanceID, "/"); found {
+		return region, id, nil
+	}
+	return c.apiKey.Region, instanceID, nil
+}
+
+// formatInstanceID records the region with the instance OCID, as API calls go to regional endpoints
+func formatInstanceID(region, id string) string {
+	return region + "/" + id
+}
@@ -0,0 +1,364 @@
+package oci
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// apiVersion is the version of the OCI Core and Identity APIs used by the client
+const apiVersion = "20160918"
+
+// Shape is a compute shape as listed by the Core API
+type Shape struct {
+	Shape string `json:"shape"`
+	GPUs  int    `json:"gpus,omitempty"`
+}
+
+// Image is a compute image as listed by the Core API
+type Image struct {
+	ID             string    `json:"id"`
+	DisplayName    string    `json:"displayName"`
+	LifecycleState string    `json:"lifecycleState"`
+	TimeCreated    time.Time `json:"timeCreated"`
+}
+
+// Instance is a compute instance as returned by the Core API
+type Instance struct {
+	ID                 string    `json:"id"`
+	DisplayName        string    `json:"displayName"`
+	AvailabilityDomain string    `json:"availabilityDomain"`
+	CompartmentID      string    `json:"compartmentId"`
+	Shape              string    `json:"shape"`
+	LifecycleState     string    `json:"lifecycleState"`
+	TimeCreated        time.Time `json:"timeCreated"`
+}
+
+// LaunchInstanceDetails is the Core API request body for launching an instance
+type LaunchInstanceDetails struct {
+	AvailabilityDomain        string                      `json:"availabilityDomain"`
+	CompartmentID             string                      `json:"compartmentId"`
+	Shape                     string                      `json:"shape"`
+	DisplayName               string                      `json:"displayName"`
+	FreeformTags              map[string]string           `json:"freeformTags,omitempty"`
+	Metadata                  map[string]string           `json:"metadata,omitempty"`
+	SourceDetails             InstanceSourceDetails       `json:"sourceDetails"`
+	CreateVnicDetails         CreateVnicDetails           `json:"createVnicDetails"`
+	PreemptibleInstanceConfig *PreemptibleInstanceConfig  `json:"preemptibleInstanceConfig,omitempty"`
+	LaunchVolumeAttachments   []LaunchAttachVolumeDetails `json:"launchVolumeAttachments,omitempty"`
+}
+
+// InstanceSourceDetails selects the image an instance boots from
+type InstanceSourceDetails struct {
+	SourceType          string `json:"sourceType"`
+	ImageID             string `json:"imageId"`
+	BootVolumeSizeInGBs int64  `json:"bootVolumeSizeInGBs,omitempty"`
+}
+
+// CreateVnicDetails configures an instance's primary VNIC
+type CreateVnicDetails struct {
+	SubnetID       string `json:"subnetId"`
+	AssignPublicIP bool   `json:"assignPublicIp"`
+}
+
+// PreemptibleInstanceConfig launches an instance as preemptible capacity
+type PreemptibleInstanceConfig struct {
+	PreemptionAction PreemptionAction `json:"preemptionAction"`
+}
+
+// PreemptionAction is what OCI does with a preemptible instance when it reclaims the capacity
+type PreemptionAction struct {
+	Type               string `json:"type"`
+	PreserveBootVolume bool   `json:"preserveBootVolume"`
+}
+
+// LaunchAttachVolumeDetails attaches an existing block volume to an instance at launch
+type LaunchAttachVolumeDetails struct {
+	Type       string `json:"type"`
+	VolumeID   string `json:"volumeId"`
+	IsReadOnly bool   `json:"isReadOnly"`
+	Device     string `json:"device,omitempty"`
+}
+
+// AttachVolumeDetails is the Core API request body for attaching a block volume
+type AttachVolumeDetails struct {
+	Type       string `json:"type"`
+	InstanceID string `json:"instanceId"`
+	VolumeID   string `json:"volumeId"`
+	IsReadOnly bool   `json:"isReadOnly"`
+	Device     string `json:"device,omitempty"`
+}
+
+// computeAPI is the subset of the OCI Core and Identity APIs used by the client. Every call
+// is made against the given region. List calls return every page.
+type computeAPI interface {
+	ListAvailabilityDomains(ctx context.Context, region, tenancy string) ([]string, error)
+	ListShapes(ctx context.Context, region, compartmentID, availabilityDomain string) ([]Shape, error)
+	ListImages(ctx context.Context, region, compartmentID string) ([]Image, error)
+	LaunchInstance(ctx context.Context, region string, details LaunchInstanceDetails) (*Instance, error)
+	GetInstance(ctx context.Context, region, instanceID string) (*Instance, error)
+	TerminateInstance(ctx context.Context, region, instanceID string) error
+	InstanceAddresses(ctx context.Context, region, compartmentID, instanceID string) (publicIP, privateIP string, err error)
+	AttachVolume(ctx context.Context, region string, details AttachVolumeDetails) error
+	DetachVolume(ctx context.Context, region, compartmentID, instanceID, volumeID string) error
+}
+
+// apiError is an error response from the OCI API
+type apiError struct {
+	StatusCode int
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	// retryAfter is the delay the Retry-After header asked for, if any
+	retryAfter time.Duration
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("OCI API error %d (%s): %s", e.StatusCode, e.Code, e.Message)
+}
+
+// RetryAfter implements providers.RetryAfterError
+func (e *apiError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+// Is matches the provider error kind of the response, so callers can classify OCI errors
+// with errors.Is
+func (e *apiError) Is(target error) bool {
+	if target == providers.ErrNoCapacity {
+		return strings.Contains(strings.ToLower(e.Message), "out of host capacity")
+	}
+	kind := providers.ErrorKindForStatus(e.StatusCode)
+	return kind != nil && kind == target
+}
+
+// isRetryable reports whether a failed call may succeed if repeated: throttling, server
+// errors other than 501, and the network errors providers.IsRetriableError recognises
+func isRetryable(err error) bool {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests ||
+			(apiErr.StatusCode >= 500 && apiErr.StatusCode != http.StatusNotImplemented)
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	retriable, _ := providers.IsRetriableError(err)
+	return retriable
+}
+
+// isNotFound reports whether an OCI API error is a 404
+func isNotFound(err error) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// restComputeAPI implements computeAPI with signed requests to the OCI REST endpoints
+type restComputeAPI struct {
+	signer     *requestSigner
+	httpClient *http.Client
+	// endpoint returns the base URL of a service in a region
+	endpoint func(service, region string) string
+	// retry configures how GET requests are retried; nil uses providers.DefaultRetryConfig
+	retry *providers.RetryConfig
+}
+
+// serviceEndpoint returns the public endpoint of an OCI service in a region
+func serviceEndpoint(service, region string) string {
+	return fmt.Sprintf("https://%s.%s.oraclecloud.com/%s", service, region, apiVersion)
+}
+
+// do sends a signed request and decodes the JSON response into out, if given.
+// It returns the response headers for pagination. GET requests are retried on
+// transient failures; other methods are sent once as they may not be idempotent.
+func (a *restComputeAPI) do(ctx context.Context, method, rawURL string, in, out interface{}) (http.Header, error) {
+	if method != http.MethodGet {
+		return a.doOnce(ctx, method, rawURL, in, out)
+	}
+
+	var header http.Header
+	err := providers.RetryDo(ctx, a.retry, isRetryable, func() error {
+		var err error
+		header, err = a.doOnce(ctx, method, rawURL, in, out)
+		return err
+	})
+	return header, err
+}
+
+// doOnce sends a single signed request and decodes the JSON response into out, if given
+func (a *restComputeAPI) doOnce(ctx context.Context, method, rawURL string, in, out interface{}) (http.Header, error) {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if err := a.signer.sign(req, body); err != nil {
+		return nil, err
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		apiErr := &apiError{StatusCode: resp.StatusCode, retryAfter: providers.ParseRetryAfter(resp.Header.Get("Retry-After"))}
+		if json.Unmarshal(data, apiErr) != nil || apiErr.Message == "" {
+			apiErr.Message = http.StatusText(resp.StatusCode)
+		}
+		return nil, apiErr
+	}
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return nil, fmt.Errorf("failed to decode OCI API response: %w", err)
+		}
+	}
+	return resp.Header, nil
+}
+
+// list fetches every page of a list call and returns the items of all pages
+func list[T any](ctx context.Context, a *restComputeAPI, baseURL string, query url.Values) ([]T, error) {
+	var items []T
+	for {
+		var page []T
+		header, err := a.do(ctx, http.MethodGet, baseURL+"?"+query.Encode(), nil, &page)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+
+		next := header.Get("Opc-Next-Page")
+		if next == "" {
+			return items, nil
+		}
+		query.Set("page", next)
+	}
+}
+
+func (a *restComputeAPI) ListAvailabilityDomains(ctx context.Context, region, tenancy string) ([]string, error) {
+	var domains []struct {
+		Name string `json:"name"`
+	}
+	query := url.Values{"compartmentId": {tenancy}}
+	if _, err := a.do(ctx, http.MethodGet, a.endpoint("identity", region)+"/availabilityDomains?"+query.Encode(), nil, &domains); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		names = append(names, domain.Name)
+	}
+	return names, nil
+}
+
+func (a *restComputeAPI) ListShapes(ctx context.Context, region, compartmentID, availabilityDomain string) ([]Shape, error) {
+	query := url.Values{"compartmentId": {compartmentID}, "limit": {"100"}}
+	if availabilityDomain != "" {
+		query.Set("availabilityDomain", availabilityDomain)
+	}
+	return list[Shape](ctx, a, a.endpoint("iaas", region)+"/shapes", query)
+}
+
+func (a *restComputeAPI) ListImages(ctx context.Context, region, compartmentID string) ([]Image, error) {
+	query := url.Values{"compartmentId": {compartmentID}, "lifecycleState": {"AVAILABLE"}, "limit": {"100"}}
+	return list[Image](ctx, a, a.endpoint("iaas", region)+"/images", query)
+}
+
+func (a *restComputeAPI) LaunchInstance(ctx context.Context, region string, details LaunchInstanceDetails) (*Instance, error) {
+	var instance Instance
+	if _, err := a.do(ctx, http.MethodPost, a.endpoint("iaas", region)+"/instances", details, &instance); err != nil {
+		return nil, err
+	}
+	return &instance, nil
+}
+
+func (a *restComputeAPI) GetInstance(ctx context.Context, region, instanceID string) (*Instance, error) {
+	var instance Instance
+	if _, err := a.do(ctx, http.MethodGet, a.endpoint("iaas", region)+"/instances/"+url.PathEscape(instanceID), nil, &instance); err != nil {
+		return nil, err
+	}
+	return &instance, nil
+}
+
+func (a *restComputeAPI) TerminateInstance(ctx context.Context, region, instanceID string) error {
+	query := url.Values{"preserveBootVolume": {"false"}}
+	_, err := a.do(ctx, http.MethodDelete, a.endpoint("iaas", region)+"/instances/"+url.PathEscape(instanceID)+"?"+query.Encode(), nil, nil)
+	return err
+}
+
+func (a *restComputeAPI) InstanceAddresses(ctx context.Context, region, compartmentID, instanceID string) (string, string, error) {
+	type vnicAttachment struct {
+		VnicID         string `json:"vnicId"`
+		LifecycleState string `json:"lifecycleState"`
+	}
+	query := url.Values{"compartmentId": {compartmentID}, "instanceId": {instanceID}}
+	attachments, err := list[vnicAttachment](ctx, a, a.endpoint("iaas", region)+"/vnicAttachments", query)
+	if err != nil {
+		return "", "", err
+	}
+
+	for _, attachment := range attachments {
+		if attachment.LifecycleState != "ATTACHED" || attachment.VnicID == "" {
+			continue
+		}
+		var vnic struct {
+			PublicIP  string `json:"publicIp"`
+			PrivateIP string `json:"privateIp"`
+			IsPrimary bool   `json:"isPrimary"`
+		}
+		if _, err := a.do(ctx, http.MethodGet, a.endpoint("iaas", region)+"/vnics/"+url.PathEscape(attachment.VnicID), nil, &vnic); err != nil {
+			return "", "", err
+		}
+		if vnic.IsPrimary {
+			return vnic.PublicIP, vnic.PrivateIP, nil
+		}
+	}
+	return "", "", nil
+}
+
+func (a *restComputeAPI) AttachVolume(ctx context.Context, region string, details AttachVolumeDetails) error {
+	_, err := a.do(ctx, http.MethodPost, a.endpoint("iaas", region)+"/volumeAttachments", details, nil)
+	return err
+}
+
+func (a *restComputeAPI) DetachVolume(ctx context.Context, region, compartmentID, instanceID, volumeID string) error {
+	type volumeAttachment struct {
+		ID             string `json:"id"`
+		LifecycleState string `json:"lifecycleState"`
+	}
+	query := url.Values{"compartmentId": {compartmentID}, "instanceId": {instanceID}, "volumeId": {volumeID}}
+	attachments, err := list[volumeAttachment](ctx, a, a.endpoint("iaas", region)+"/volumeAttachments", query)
+	if err != nil {
+		return err
+	}
+
+	for _, attachment := range attachments {
+		if attachment.LifecycleState == "DETACHING" || attachment.LifecycleState == "DETACHED" {
+			continue
+		}
+		if _, err := a.do(ctx, http.MethodDelete, a.endpoint("iaas", region)+"/volumeAttachments/"+url.PathEscape(attachment.ID), nil, nil); err != nil && !isNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package oci
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// APIKey is the JSON structure of the OCI credentials secret
+type APIKey struct {
+	Tenancy     string `json:"tenancy"`
+	User        string `json:"user"`
+	Fingerprint string `json:"fingerprint"`
+	// PrivateKey is the PEM-encoded API signing key
+	PrivateKey string `json:"privateKey"`
+	// Region is the region of instance IDs recorded without one
+	Region string `json:"region"`
+
+	// CompartmentID holds the instances the operator creates; defaults to the tenancy
+	CompartmentID string `json:"compartmentId,omitempty"`
+	// Subnets maps each region to provision in to the OCID of the subnet instances join there
+	Subnets map[string]string `json:"subnets,omitempty"`
+}
+
+// parseCredentials parses an API key JSON blob or the DEFAULT profile of an OCI CLI config file
+func parseCredentials(credentials string) (APIKey, error) {
+	credentials = strings.TrimSpace(credentials)
+
+	var apiKey APIKey
+	if strings.HasPrefix(credentials, "{") {
+		if err := json.Unmarshal([]byte(credentials), &apiKey); err != nil {
+			return APIKey{}, fmt.Errorf("failed to parse OCI API key JSON: %w", err)
+		}
+	} else {
+		var err error
+		if apiKey, err = parseConfigFile(credentials); err != nil {
+			return APIKey{}, err
+		}
+	}
+
+	if apiKey.Tenancy == "" || apiKey.User == "" || apiKey.Fingerprint == "" || apiKey.PrivateKey == "" || apiKey.Region == "" {
+		return APIKey{}, fmt.Errorf("OCI credentials require tenancy, user, fingerprint, privateKey and region")
+	}
+	if apiKey.CompartmentID == "" {
+		apiKey.CompartmentID = apiKey.Tenancy
+	}
+	return apiKey, nil
+}
+
+// parseConfigFile reads the DEFAULT profile of an OCI CLI config file. The signing key is
+// read from key_file. The operator's compartment_id and subnet_id keys, which the OCI CLI
+// ignores, set the compartment and the subnet for the profile's region.
+func parseConfigFile(config string) (APIKey, error) {
+	values := map[string]string{}
+	profile := ""
+	scanner := bufio.NewScanner(strings.NewReader(config))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			profile = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		if profile != "DEFAULT" {
+			continue
+		}
+		if key, value, found := strings.Cut(line, "="); found {
+			values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	if len(values) == 0 {
+		return APIKey{}, fmt.Errorf("OCI credentials are neither API key JSON nor a config file with a DEFAULT profile")
+	}
+
+	apiKey := APIKey{
+		Tenancy:       values["tenancy"],
+		User:          values["user"],
+		Fingerprint:   values["fingerprint"],
+		Region:        values["region"],
+		CompartmentID: values["compartment_id"],
+	}
+	if subnet := values["subnet_id"]; subnet != "" && apiKey.Region != "" {
+		apiKey.Subnets = map[string]string{apiKey.Region: subnet}
+	}
+	if keyFile := values["key_file"]; keyFile != "" {
+		if rest, found := strings.CutPrefix(keyFile, "~/"); found {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return APIKey{}, fmt.Errorf("failed to expand OCI key_file %s: %w", keyFile, err)
+			}
+			keyFile = filepath.Join(home, rest)
+		}
+		key, err := os.ReadFile(keyFile)
+		if err != nil {
+			return APIKey{}, fmt.Errorf("failed to read OCI key_file: %w", err)
+		}
+		apiKey.PrivateKey = string(key)
+	}
+	return apiKey, nil
+}
@@ -0,0 +1,159 @@
+package oci
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+const (
+	// defaultBootVolumeGB is the size of the boot volume created from the Talos image
+	defaultBootVolumeGB = 100
+
+	// minBootVolumeGB is the smallest boot volume OCI creates
+	minBootVolumeGB = 50
+
+	// volumeAttachmentType attaches block volumes without iSCSI setup on the instance
+	volumeAttachmentType = "paravirtualized"
+
+	// volumeDevicePrefix prefixes the consistent device paths OCI can assign to attachments
+	volumeDevicePrefix = "/dev/oracleoci/"
+)
+
+// shapeNames returns the set of shape names in a shape list
+func shapeNames(shapes []Shape) map[string]bool {
+	names := make(map[string]bool, len(shapes))
+	for _, shape := range shapes {
+		names[shape.Shape] = true
+	}
+	return names
+}
+
+// talosImageName matches the names of Talos images imported into the compartment
+var talosImageName = regexp.MustCompile(`(?i)^talos`)
+
+// resolveImage returns the OCID of the image to launch from: the image itself if it is an
+// image OCID, the newest image with that display name, or the newest image whose name starts
+// with "talos" when the launch asks for Talos or passes an Image Factory URL. OCI cannot boot
+// from a URL, so Talos images must be imported into each region first.
+func (c *Client) resolveImage(ctx context.Context, api computeAPI, region, image string) (string, error) {
+	if strings.HasPrefix(image, "ocid1.image.") {
+		return image, nil
+	}
+	byName := image != "" && !strings.EqualFold(image, "talos") && !strings.Contains(image, "://")
+
+	images, err := api.ListImages(ctx, region, c.apiKey.CompartmentID)
+	if err != nil {
+		return "", fmt.Errorf("failed to list images in %s: %w", region, err)
+	}
+	var candidates []Image
+	for _, candidate := range images {
+		if candidate.LifecycleState != "" && candidate.LifecycleState != "AVAILABLE" {
+			continue
+		}
+		if (byName && candidate.DisplayName == image) || (!byName && talosImageName.MatchString(candidate.DisplayName)) {
+			candidates = append(candidates, candidate)
+		}
+	}
+	if len(candidates) == 0 {
+		if byName {
+			return "", fmt.Errorf("no image named %s found in %s", image, region)
+		}
+		return "", fmt.Errorf("no Talos image found in %s; import one named talos-* or set the launch image to its OCID", region)
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].TimeCreated.After(candidates[j].TimeCreated) })
+	return candidates[0].ID, nil
+}
+
+// buildLaunchDetails returns the launch request for an instance of the shape booting the
+// image in the subnet with a boot volume of bootVolumeGB, with the user data as instance metadata and the data disks attached.
+// The availability domain is set by the caller.
+func buildLaunchDetails(name, compartmentID, subnetID, imageID string, bootVolumeGB int, shape GPUShape, req *providers.LaunchRequest) LaunchInstanceDetails {
+	details := LaunchInstanceDetails{
+		CompartmentID: compartmentID,
+		Shape:         shape.Name,
+		DisplayName:   name,
+		FreeformTags:  buildTags(req),
+		SourceDetails: InstanceSourceDetails{
+			SourceType:          "image",
+			ImageID:             imageID,
+			BootVolumeSizeInGBs: int64(bootVolumeGB),
+		},
+		CreateVnicDetails: CreateVnicDetails{SubnetID: subnetID, AssignPublicIP: true},
+	}
+	if req.UserData != "" {
+		details.Metadata = map[string]string{"user_data": base64.StdEncoding.EncodeToString([]byte(req.UserData))}
+	}
+	for _, disk := range req.DataDisks {
+		details.LaunchVolumeAttachments = append(details.LaunchVolumeAttachments, LaunchAttachVolumeDetails{
+			Type:       volumeAttachmentType,
+			VolumeID:   disk.VolumeID,
+			IsReadOnly: disk.ReadOnly,
+			Device:     volumeDevice(disk.DeviceName),
+		})
+	}
+	if req.SpotInstance {
+		details.PreemptibleInstanceConfig = &PreemptibleInstanceConfig{
+			PreemptionAction: PreemptionAction{Type: "TERMINATE"},
+		}
+	}
+	return details
+}
+
+// buildTags returns the freeform tags for a launch: its labels and cost-allocation tags
+func buildTags(req *providers.LaunchRequest) map[string]string {
+	tags := map[string]string{"tgp-operator": "true"}
+	for key, value := range req.Labels {
+		tags[key] = value
+	}
+	for key, value := range req.Tags {
+		tags[key] = value
+	}
+	return tags
+}
+
+// volumeDevice returns the device path to request for an attachment, if the hint is one OCI accepts
+func volumeDevice(hint string) string {
+	if strings.HasPrefix(hint, volumeDevicePrefix) {
+		return hint
+	}
+	return ""
+}
+
+// isOutOfCapacity reports whether a launch failed because the availability domain has no
+// capacity left for the shape, so another domain may still succeed
+func isOutOfCapacity(err error) bool {
+	return errors.Is(err, providers.ErrNoCapacity)
+}
+
+// generateInstanceName returns a unique instance display name for a launch
+func generateInstanceName(req *providers.LaunchRequest) string {
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	gpuType := strings.ToLower(strings.ReplaceAll(req.GPUType, "_", "-"))
+	return fmt.Sprintf("tgp-%s-%s", gpuType, hex.EncodeToString(suffix))
+}
+
+// mapLifecycleState maps an instance lifecycle state to an instance state
+func mapLifecycleState(state string) providers.InstanceState {
+	switch state {
+	case "PROVISIONING", "STARTING", "MOVING":
+		return providers.InstanceStatePending
+	case "RUNNING", "CREATING_IMAGE":
+		return providers.InstanceStateRunning
+	case "STOPPING", "TERMINATING":
+		return providers.InstanceStateTerminating
+	case "STOPPED", "TERMINATED":
+		return providers.InstanceStateTerminated
+	default:
+		return providers.InstanceStateUnknown
+	}
+}
@@ -0,0 +1,528 @@
+package oci
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// testKey is the API signing key used by test clients
+var testKey = func() *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}()
+
+// testKeyPEM returns the test key PEM encoded in PKCS#8 form, as the OCI console generates it
+func testKeyPEM(t *testing.T) string {
+	der, err := x509.MarshalPKCS8PrivateKey(testKey)
+	if err != nil {
+		t.Fatalf("failed to encode key: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+// testCredentials returns API key JSON with a subnet in us-ashburn-1
+func testCredentials(t *testing.T) string {
+	data, _ := json.Marshal(APIKey{
+		Tenancy:     "ocid1.tenancy.oc1..tenancy",
+		User:        "ocid1.user.oc1..user",
+		Fingerprint: "aa:bb:cc",
+		PrivateKey:  testKeyPEM(t),
+		Region:      "us-ashburn-1",
+		Subnets:     map[string]string{"us-ashburn-1": "ocid1.subnet.oc1.iad.subnet"},
+	})
+	return string(data)
+}
+
+// fakeComputeAPI records requests and returns canned responses
+type fakeComputeAPI struct {
+	// domainShapes lists the shapes each availability domain offers
+	domainShapes map[string][]Shape
+	images       []Image
+	// fullDomains are the availability domains out of capacity
+	fullDomains  map[string]bool
+	launched     []LaunchInstanceDetails
+	instance     Instance
+	terminateErr error
+	terminated   []string
+	detached     []string
+}
+
+func (f *fakeComputeAPI) ListAvailabilityDomains(ctx context.Context, region, tenancy string) ([]string, error) {
+	return []string{"AD-1", "AD-2"}, nil
+}
+
+func (f *fakeComputeAPI) ListShapes(ctx context.Context, region, compartmentID, availabilityDomain string) ([]Shape, error) {
+	if availabilityDomain != "" {
+		return f.domainShapes[availabilityDomain], nil
+	}
+	var shapes []Shape
+	for _, domain := range []string{"AD-1", "AD-2"} {
+		shapes = append(shapes, f.domainShapes[domain]...)
+	}
+	return shapes, nil
+}
+
+func (f *fakeComputeAPI) ListImages(ctx context.Context, region, compartmentID string) ([]Image, error) {
+	return f.images, nil
+}
+
+func (f *fakeComputeAPI) LaunchInstance(ctx context.Context, region string, details LaunchInstanceDetails) (*Instance, error) {
+	f.launched = append(f.launched, details)
+	if f.fullDomains[details.AvailabilityDomain] {
+		return nil, &apiError{StatusCode: http.StatusInternalServerError, Code: "InternalError", Message: "Out of host capacity."}
+	}
+	return &Instance{
+		ID:             "ocid1.instance.oc1.iad.instance",
+		LifecycleState: "PROVISIONING",
+		TimeCreated:    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}, nil
+}
+
+func (f *fakeComputeAPI) GetInstance(ctx context.Context, region, instanceID string) (*Instance, error) {
+	return &f.instance, nil
+}
+
+func (f *fakeComputeAPI) TerminateInstance(ctx context.Context, region, instanceID string) error {
+	f.terminated = append(f.terminated, region+"/"+instanceID)
+	return f.terminateErr
+}
+
+func (f *fakeComputeAPI) InstanceAddresses(ctx context.Context, region, compartmentID, instanceID string) (string, string, error) {
+	return "203.0.113.40", "10.0.0.4", nil
+}
+
+func (f *fakeComputeAPI) AttachVolume(ctx context.Context, region string, details AttachVolumeDetails) error {
+	return nil
+}
+
+func (f *fakeComputeAPI) DetachVolume(ctx context.Context, region, compartmentID, instanceID, volumeID string) error {
+	f.detached = append(f.detached, volumeID)
+	return nil
+}
+
+// newTestClient returns a client whose API calls go to fake
+func newTestClient(t *testing.T, fake *fakeComputeAPI) *Client {
+	client, err := NewClient(testCredentials(t))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	client.api = fake
+	return client
+}
+
+func TestNewClient(t *testing.T) {
+	client := newTestClient(t, &fakeComputeAPI{})
+	if client.apiKey.CompartmentID != client.apiKey.Tenancy {
+		t.Errorf("Expected the compartment to default to the tenancy, got %s", client.apiKey.CompartmentID)
+	}
+
+	for _, invalid := range []string{"", "not-a-config", `{"tenancy":"t","user":"u","fingerprint":"f","region":"us-ashburn-1"}`} {
+		if _, err := NewClient(invalid); err == nil {
+			t.Errorf("Expected error for credentials %q", invalid)
+		}
+	}
+
+	encrypted := strings.Replace(testCredentials(t), "PRIVATE KEY", "ENCRYPTED PRIVATE KEY", 2)
+	if _, err := NewClient(encrypted); err == nil || !strings.Contains(err.Error(), "passphrase") {
+		t.Errorf("Expected error for an encrypted key, got %v", err)
+	}
+}
+
+func TestNewClientFromConfigFile(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "oci_api_key.pem")
+	if err := os.WriteFile(keyFile, []byte(testKeyPEM(t)), 0o600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+
+	client, err := NewClient(`[DEFAULT]
+user=ocid1.user.oc1..user
+fingerprint=aa:bb:cc
+tenancy=ocid1.tenancy.oc1..tenancy
+region=eu-frankfurt-1
+key_file=` + keyFile + `
+compartment_id=ocid1.compartment.oc1..gpus
+subnet_id=ocid1.subnet.oc1.eu-frankfurt-1.subnet
+
+[OTHER]
+region=us-phoenix-1
+`)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	if client.apiKey.CompartmentID != "ocid1.compartment.oc1..gpus" {
+		t.Errorf("Expected configured compartment, got %s", client.apiKey.CompartmentID)
+	}
+	if regions := client.regions(); len(regions) != 1 || regions[0] != "eu-frankfurt-1" {
+		t.Errorf("Expected the DEFAULT profile's region with its subnet, got %v", regions)
+	}
+}
+
+func TestTranslateGPUType(t *testing.T) {
+	client := newTestClient(t, &fakeComputeAPI{})
+
+	tests := map[string]string{
+		"A10":         "VM.GPU.A10.1",
+		"NVIDIA_V100": "VM.GPU3.1",
+		"A100-80GB":   "BM.GPU.A100-v2.8",
+		"h100":        "BM.GPU.H100.8",
+	}
+	for gpuType, expected := range tests {
+		got, err := client.TranslateGPUType(gpuType)
+		if err != nil || got != expected {
+			t.Errorf("TranslateGPUType(%s) = %s, %v, expected %s", gpuType, got, err, expected)
+		}
+	}
+
+	if _, err := client.TranslateGPUType("RTX4090"); err == nil {
+		t.Error("Expected error for GPU type OCI does not offer")
+	}
+}
+
+func TestTranslateRegion(t *testing.T) {
+	client := newTestClient(t, &fakeComputeAPI{})
+
+	tests := map[string]string{
+		providers.RegionUSEast: "us-ashburn-1",
+		"EU-Frankfurt-1":       "eu-frankfurt-1",
+	}
+	for standard, expected := range tests {
+		got, err := client.TranslateRegion(standard)
+		if err != nil || got != expected {
+			t.Errorf("TranslateRegion(%s) = %s, %v, expected %s", standard, got, err, expected)
+		}
+	}
+
+	if _, err := client.TranslateRegion("moon"); err == nil {
+		t.Error("Expected error for unknown region")
+	}
+}
+
+func TestListAvailableGPUs(t *testing.T) {
+	client := newTestClient(t, &fakeComputeAPI{domainShapes: map[string][]Shape{
+		"AD-1": {{Shape: "VM.Standard.E4.Flex"}, {Shape: "VM.GPU.A10.1", GPUs: 1}},
+		"AD-2": {{Shape: "VM.GPU.A10.1", GPUs: 1}, {Shape: "BM.GPU4.8", GPUs: 8}},
+	}})
+
+	offers, err := client.ListAvailableGPUs(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListAvailableGPUs failed: %v", err)
+	}
+	if len(offers) != 4 {
+		t.Fatalf("Expected on-demand and preemptible offers for the two GPU shapes, got %d", len(offers))
+	}
+	for _, offer := range offers {
+		if offer.Region != "us-ashburn-1" || offer.Country != "US" || offer.Provider != ProviderName {
+			t.Errorf("Unexpected offer: %+v", offer)
+		}
+		if offer.IsSpot && offer.HourlyPrice != offer.SpotPrice {
+			t.Errorf("Expected preemptible offers priced at the preemptible price, got %+v", offer)
+		}
+	}
+
+	offers, err = client.ListAvailableGPUs(context.Background(), &providers.GPUFilters{GPUType: "A100", OnDemandOnly: true})
+	if err != nil {
+		t.Fatalf("ListAvailableGPUs failed: %v", err)
+	}
+	if len(offers) != 1 || offers[0].HourlyPrice != 24.40 || offers[0].VCPUs != 128 {
+		t.Errorf("Expected the on-demand BM.GPU4.8 offer, got %+v", offers)
+	}
+
+	// Regions without a subnet cannot be launched in
+	offers, err = client.ListAvailableGPUs(context.Background(), &providers.GPUFilters{Region: "eu-frankfurt-1"})
+	if err != nil {
+		t.Fatalf("ListAvailableGPUs failed: %v", err)
+	}
+	if len(offers) != 0 {
+		t.Errorf("Expected no offers without a subnet, got %d", len(offers))
+	}
+}
+
+func TestLaunchInstance(t *testing.T) {
+	fake := &fakeComputeAPI{
+		domainShapes: map[string][]Shape{
+			"AD-1": {{Shape: "VM.GPU.A10.2"}},
+			"AD-2": {{Shape: "VM.GPU.A10.2"}},
+		},
+		fullDomains: map[string]bool{"AD-1": true},
+		images: []Image{
+			{ID: "ocid1.image.oc1.iad.old", DisplayName: "talos-v1.9.0", LifecycleState: "AVAILABLE", TimeCreated: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+			{ID: "ocid1.image.oc1.iad.new", DisplayName: "talos-v1.10.0", LifecycleState: "AVAILABLE", TimeCreated: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+			{ID: "ocid1.image.oc1.iad.importing", DisplayName: "talos-v1.11.0", LifecycleState: "IMPORTING", TimeCreated: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+			{ID: "ocid1.image.oc1.iad.ubuntu", DisplayName: "ubuntu-24.04", LifecycleState: "AVAILABLE", TimeCreated: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+	client := newTestClient(t, fake)
+
+	instance, err := client.LaunchInstance(context.Background(), &providers.LaunchRequest{
+		GPUType:      "A10",
+		GPUCount:     2,
+		Region:       providers.RegionUSEast,
+		Image:        "https://factory.talos.dev/image/abc/v1.10.0/oracle-amd64.qcow2",
+		UserData:     "machine: config",
+		Tags:         map[string]string{"team": "ml"},
+		SpotInstance: true,
+		DataDisks:    []providers.DataDisk{{VolumeID: "ocid1.volume.oc1.iad.data", ReadOnly: true, DeviceName: "sdb"}},
+		BootDiskGiB:  250,
+	})
+	if err != nil {
+		t.Fatalf("LaunchInstance failed: %v", err)
+	}
+
+	if instance.ID != "us-ashburn-1/ocid1.instance.oc1.iad.instance" || !instance.IsSpot || instance.Status != providers.InstanceStatePending {
+		t.Errorf("Unexpected instance: %+v", instance)
+	}
+	if len(fake.launched) != 2 || fake.launched[1].AvailabilityDomain != "AD-2" {
+		t.Fatalf("Expected a retry in the second availability domain, got %+v", fake.launched)
+	}
+
+	details := fake.launched[1]
+	if details.Shape != "VM.GPU.A10.2" || details.SourceDetails.ImageID != "ocid1.image.oc1.iad.new" {
+		t.Errorf("Expected the newest available Talos image on VM.GPU.A10.2, got %s on %s", details.SourceDetails.ImageID, details.Shape)
+	}
+	if details.SourceDetails.BootVolumeSizeInGBs != 250 {
+		t.Errorf("Expected a 250GB boot volume, got %d", details.SourceDetails.BootVolumeSizeInGBs)
+	}
+	if details.CreateVnicDetails.SubnetID != "ocid1.subnet.oc1.iad.subnet" || details.PreemptibleInstanceConfig == nil {
+		t.Errorf("Unexpected network or capacity type: %+v", details)
+	}
+	if details.Metadata["user_data"] != base64.StdEncoding.EncodeToString([]byte("machine: config")) {
+		t.Errorf("Expected base64 user data, got %q", details.Metadata["user_data"])
+	}
+	if details.FreeformTags["team"] != "ml" {
+		t.Errorf("Expected cost-allocation tags, got %v", details.FreeformTags)
+	}
+	if len(details.LaunchVolumeAttachments) != 1 || !details.LaunchVolumeAttachments[0].IsReadOnly || details.LaunchVolumeAttachments[0].Device != "" {
+		t.Errorf("Unexpected volume attachments: %+v", details.LaunchVolumeAttachments)
+	}
+}
+
+func TestLaunchInstanceErrors(t *testing.T) {
+	fake := &fakeComputeAPI{domainShapes: map[string][]Shape{"AD-1": {{Shape: "VM.GPU.A10.1"}}}}
+	client := newTestClient(t, fake)
+
+	tests := map[string]*providers.LaunchRequest{
+		"too many GPUs":         {GPUType: "A10", GPUCount: 8, Image: "ocid1.image.oc1.iad.talos"},
+		"no subnet":             {GPUType: "A10", Region: "eu-frankfurt-1", Image: "ocid1.image.oc1.iad.talos"},
+		"shape not offered":     {GPUType: "V100", Image: "ocid1.image.oc1.iad.talos"},
+		"no Talos image":        {GPUType: "A10"},
+		"boot volume too small": {GPUType: "A10", Image: "ocid1.image.oc1.iad.talos", BootDiskGiB: 20},
+	}
+	for name, req := range tests {
+		if _, err := client.LaunchInstance(context.Background(), req); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if len(fake.launched) != 0 {
+		t.Errorf("Expected no launches, got %d", len(fake.launched))
+	}
+}
+
+func TestTerminateInstance(t *testing.T) {
+	fake := &fakeComputeAPI{terminateErr: &apiError{StatusCode: http.StatusNotFound, Code: "NotAuthorizedOrNotFound"}}
+	client := newTestClient(t, fake)
+
+	if err := client.TerminateInstance(context.Background(), "ap-tokyo-1/ocid1.instance.oc1.nrt.gone"); err != nil {
+		t.Errorf("Expected missing instances to be treated as terminated, got %v", err)
+	}
+	if err := client.TerminateInstance(context.Background(), "ocid1.instance.oc1.iad.bare"); err != nil {
+		t.Errorf("TerminateInstance failed: %v", err)
+	}
+	expected := []string{"ap-tokyo-1/ocid1.instance.oc1.nrt.gone", "us-ashburn-1/ocid1.instance.oc1.iad.bare"}
+	if strings.Join(fake.terminated, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected terminations %v, got %v", expected, fake.terminated)
+	}
+}
+
+func TestGetInstanceStatus(t *testing.T) {
+	fake := &fakeComputeAPI{instance: Instance{LifecycleState: "RUNNING"}}
+	client := newTestClient(t, fake)
+
+	status, err := client.GetInstanceStatus(context.Background(), "us-ashburn-1/ocid1.instance.oc1.iad.instance")
+	if err != nil {
+		t.Fatalf("GetInstanceStatus failed: %v", err)
+	}
+	if status.State != providers.InstanceStateRunning || status.PublicIP != "203.0.113.40" || status.PrivateIP != "10.0.0.4" {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+
+	fake.instance.LifecycleState = "TERMINATED"
+	if status, err = client.GetInstanceStatus(context.Background(), "us-ashburn-1/ocid1.instance.oc1.iad.instance"); err != nil || status.State != providers.InstanceStateTerminated {
+		t.Errorf("Expected a preempted instance to be terminated, got %+v, %v", status, err)
+	}
+}
+
+func TestDataDisks(t *testing.T) {
+	fake := &fakeComputeAPI{}
+	client := newTestClient(t, fake)
+
+	if err := client.AttachDataDisk(context.Background(), "us-ashburn-1/ocid1.instance.oc1.iad.instance", providers.DataDisk{VolumeID: "ocid1.volume.oc1.iad.data", ReadOnly: true}); err != nil {
+		t.Errorf("AttachDataDisk failed: %v", err)
+	}
+	if err := client.DetachDataDisk(context.Background(), "us-ashburn-1/ocid1.instance.oc1.iad.instance", "ocid1.volume.oc1.iad.data"); err != nil {
+		t.Errorf("DetachDataDisk failed: %v", err)
+	}
+	if len(fake.detached) != 1 || fake.detached[0] != "ocid1.volume.oc1.iad.data" {
+		t.Errorf("Expected the volume to be detached, got %v", fake.detached)
+	}
+}
+
+func TestMapLifecycleState(t *testing.T) {
+	tests := map[string]providers.InstanceState{
+		"PROVISIONING": providers.InstanceStatePending,
+		"RUNNING":      providers.InstanceStateRunning,
+		"TERMINATING":  providers.InstanceStateTerminating,
+		"STOPPED":      providers.InstanceStateTerminated,
+		"TERMINATED":   providers.InstanceStateTerminated,
+		"SOMETHING":    providers.InstanceStateUnknown,
+	}
+	for state, expected := range tests {
+		if got := mapLifecycleState(state); got != expected {
+			t.Errorf("mapLifecycleState(%s) = %s, expected %s", state, got, expected)
+		}
+	}
+}
+
+func TestAPIErrorKinds(t *testing.T) {
+	tests := []struct {
+		err  *apiError
+		kind error
+	}{
+		{&apiError{StatusCode: http.StatusUnauthorized, Code: "NotAuthenticated"}, providers.ErrUnauthorized},
+		{&apiError{StatusCode: http.StatusTooManyRequests, Code: "TooManyRequests"}, providers.ErrRateLimited},
+		{&apiError{StatusCode: http.StatusNotFound, Code: "NotAuthorizedOrNotFound"}, providers.ErrNotFound},
+		{&apiError{StatusCode: http.StatusInternalServerError, Code: "InternalError", Message: "Out of host capacity."}, providers.ErrNoCapacity},
+	}
+	for _, tt := range tests {
+		if !errors.Is(fmt.Errorf("launch: %w", tt.err), tt.kind) {
+			t.Errorf("Expected %v to be %v", tt.err, tt.kind)
+		}
+	}
+
+	if errors.Is(&apiError{StatusCode: http.StatusInternalServerError}, providers.ErrNoCapacity) {
+		t.Error("Expected a plain server error not to be a capacity error")
+	}
+}
+
+// authorizationParams matches the key="value" parameters of a signature Authorization header
+var authorizationParams = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+func TestRequestSigning(t *testing.T) {
+	var verifyErr error
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verifyErr = verifySignature(r)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"ocid1.instance.oc1.iad.instance","lifecycleState":"PROVISIONING"}`))
+	}))
+	defer server.Close()
+
+	api := &restComputeAPI{
+		signer:     newRequestSigner("tenancy", "user", "aa:bb", testKey),
+		httpClient: server.Client(),
+		endpoint:   func(service, region string) string { return server.URL + "/" + apiVersion },
+	}
+
+	instance, err := api.LaunchInstance(context.Background(), "us-ashburn-1", LaunchInstanceDetails{Shape: "VM.GPU.A10.1"})
+	if err != nil {
+		t.Fatalf("LaunchInstance failed: %v", err)
+	}
+	if verifyErr != nil {
+		t.Errorf("Signature verification failed: %v", verifyErr)
+	}
+	if instance.LifecycleState != "PROVISIONING" {
+		t.Errorf("Unexpected instance: %+v", instance)
+	}
+
+	if _, err := api.GetInstance(context.Background(), "us-ashburn-1", "ocid1.instance.oc1.iad.instance"); err != nil || verifyErr != nil {
+		t.Errorf("Expected a signed GET, got %v, %v", err, verifyErr)
+	}
+}
+
+func TestRequestRetries(t *testing.T) {
+	var gets, posts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			posts++
+		} else {
+			gets++
+		}
+		if r.Method == http.MethodPost || gets < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"code":"ServiceUnavailable","message":"try again"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"ocid1.instance.oc1.iad.instance","lifecycleState":"RUNNING"}`))
+	}))
+	defer server.Close()
+
+	api := &restComputeAPI{
+		signer:     newRequestSigner("tenancy", "user", "aa:bb", testKey),
+		httpClient: server.Client(),
+		endpoint:   func(service, region string) string { return server.URL + "/" + apiVersion },
+		retry:      &providers.RetryConfig{MaxRetries: 3, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, BackoffFactor: 2},
+	}
+
+	instance, err := api.GetInstance(context.Background(), "us-ashburn-1", "ocid1.instance.oc1.iad.instance")
+	if err != nil || instance.LifecycleState != "RUNNING" || gets != 3 {
+		t.Errorf("Expected GET to succeed on the third attempt, got %+v, %v after %d attempts", instance, err, gets)
+	}
+
+	if _, err := api.LaunchInstance(context.Background(), "us-ashburn-1", LaunchInstanceDetails{}); err == nil || posts != 1 {
+		t.Errorf("Expected a single failed POST, got %v after %d attempts", err, posts)
+	}
+}
+
+// verifySignature checks a request's Authorization header against the test key
+func verifySignature(r *http.Request) error {
+	params := map[string]string{}
+	for _, match := range authorizationParams.FindAllStringSubmatch(r.Header.Get("Authorization"), -1) {
+		params[match[1]] = match[2]
+	}
+	if params["keyId"] != "tenancy/user/aa:bb" || params["algorithm"] != "rsa-sha256" {
+		return &apiError{Message: "unexpected key or algorithm: " + r.Header.Get("Authorization")}
+	}
+
+	var lines []string
+	for _, header := range strings.Split(params["headers"], " ") {
+		switch header {
+		case "(request-target)":
+			lines = append(lines, header+": "+strings.ToLower(r.Method)+" "+r.URL.RequestURI())
+		case "host":
+			lines = append(lines, header+": "+r.Host)
+		default:
+			lines = append(lines, header+": "+r.Header.Get(header))
+		}
+	}
+	if r.Method == http.MethodPost && !strings.Contains(params["headers"], "x-content-sha256") {
+		return &apiError{Message: "body digest not signed"}
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(params["signature"])
+	if err != nil {
+		return err
+	}
+	hashed := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return rsa.VerifyPKCS1v15(&testKey.PublicKey, crypto.SHA256, hashed[:], signature)
+}
@@ -0,0 +1,201 @@
+package oci
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// preemptiblePriceFactor is the price of preemptible capacity relative to on-demand.
+// OCI discounts preemptible instances by a flat 50%.
+const preemptiblePriceFactor = 0.5
+
+// GPUShape describes an OCI compute shape with GPUs
+type GPUShape struct {
+	Name         string
+	GPUType      string
+	GPUCount     int
+	OCPUs        int
+	GPUMemoryGiB int64
+	// HourlyPrice is the on-demand list price in USD, which OCI charges in every region.
+	// These should ideally come from the OCI price list API.
+	HourlyPrice float64
+}
+
+// gpuShapes lists the GPU shapes of each standard GPU type, by increasing GPU count
+var gpuShapes = map[string][]GPUShape{
+	"NVIDIA_A10": {
+		{Name: "VM.GPU.A10.1", GPUType: "NVIDIA_A10", GPUCount: 1, OCPUs: 15, GPUMemoryGiB: 24, HourlyPrice: 2.00},
+		{Name: "VM.GPU.A10.2", GPUType: "NVIDIA_A10", GPUCount: 2, OCPUs: 30, GPUMemoryGiB: 24, HourlyPrice: 4.00},
+		{Name: "BM.GPU.A10.4", GPUType: "NVIDIA_A10", GPUCount: 4, OCPUs: 64, GPUMemoryGiB: 24, HourlyPrice: 8.00},
+	},
+	"NVIDIA_V100": {
+		{Name: "VM.GPU3.1", GPUType: "NVIDIA_V100", GPUCount: 1, OCPUs: 6, GPUMemoryGiB: 16, HourlyPrice: 2.95},
+		{Name: "VM.GPU3.2", GPUType: "NVIDIA_V100", GPUCount: 2, OCPUs: 12, GPUMemoryGiB: 16, HourlyPrice: 5.90},
+		{Name: "VM.GPU3.4", GPUType: "NVIDIA_V100", GPUCount: 4, OCPUs: 24, GPUMemoryGiB: 16, HourlyPrice: 11.80},
+		{Name: "BM.GPU3.8", GPUType: "NVIDIA_V100", GPUCount: 8, OCPUs: 52, GPUMemoryGiB: 16, HourlyPrice: 23.60},
+	},
+	"NVIDIA_A100": {
+		{Name: "BM.GPU4.8", GPUType: "NVIDIA_A100", GPUCount: 8, OCPUs: 64, GPUMemoryGiB: 40, HourlyPrice: 24.40},
+	},
+	"NVIDIA_A100_80GB": {
+		{Name: "BM.GPU.A100-v2.8", GPUType: "NVIDIA_A100_80GB", GPUCount: 8, OCPUs: 128, GPUMemoryGiB: 80, HourlyPrice: 32.00},
+	},
+	"NVIDIA_H100": {
+		{Name: "BM.GPU.H100.8", GPUType: "NVIDIA_H100", GPUCount: 8, OCPUs: 112, GPUMemoryGiB: 80, HourlyPrice: 80.00},
+	},
+}
+
+// gpuTypeAliases maps the short and alternative standard GPU type names to gpuShapes keys
+var gpuTypeAliases = map[string]string{
+	"A10":              "NVIDIA_A10",
+	"V100":             "NVIDIA_V100",
+	"A100":             "NVIDIA_A100",
+	"A100-80GB":        "NVIDIA_A100_80GB",
+	"A100_80GB":        "NVIDIA_A100_80GB",
+	"H100":             "NVIDIA_H100",
+	"NVIDIA_H100_80GB": "NVIDIA_H100",
+}
+
+// standardGPUType resolves a standard or alias GPU type name
+func standardGPUType(gpuType string) (string, error) {
+	key := strings.ToUpper(gpuType)
+	if alias, exists := gpuTypeAliases[key]; exists {
+		key = alias
+	}
+	if _, exists := gpuShapes[key]; !exists {
+		return "", fmt.Errorf("unsupported GPU type: %s", gpuType)
+	}
+	return key, nil
+}
+
+// lookupShape returns the smallest shape with at least count GPUs of a standard GPU type
+func lookupShape(gpuType string, count int) (GPUShape, error) {
+	key, err := standardGPUType(gpuType)
+	if err != nil {
+		return GPUShape{}, err
+	}
+
+	shapes := gpuShapes[key]
+	for _, shape := range shapes {
+		if shape.GPUCount >= count {
+			return shape, nil
+		}
+	}
+	largest := shapes[len(shapes)-1]
+	return GPUShape{}, fmt.Errorf("no shape offers %d %s GPUs, the largest is %s with %d",
+		count, gpuType, largest.Name, largest.GPUCount)
+}
+
+// supportedGPUTypes returns the standard GPU types OCI offers, sorted
+func supportedGPUTypes() []string {
+	gpuTypes := make([]string, 0, len(gpuShapes))
+	for gpuType := range gpuShapes {
+		gpuTypes = append(gpuTypes, gpuType)
+	}
+	sort.Strings(gpuTypes)
+	return gpuTypes
+}
+
+// standardRegions maps standard regions to OCI regions
+var standardRegions = map[string]string{
+	providers.RegionUSEast:      "us-ashburn-1",
+	providers.RegionUSWest:      "us-phoenix-1",
+	providers.RegionEUCentral:   "eu-frankfurt-1",
+	providers.RegionAsiaPacific: "ap-tokyo-1",
+}
+
+// regionCountries maps OCI regions to the ISO 3166-1 alpha-2 code of the country they are in
+var regionCountries = map[string]string{
+	"us-ashburn-1":   "US",
+	"us-phoenix-1":   "US",
+	"us-chicago-1":   "US",
+	"us-sanjose-1":   "US",
+	"ca-toronto-1":   "CA",
+	"sa-saopaulo-1":  "BR",
+	"uk-london-1":    "GB",
+	"eu-frankfurt-1": "DE",
+	"eu-amsterdam-1": "NL",
+	"eu-paris-1":     "FR",
+	"ap-tokyo-1":     "JP",
+	"ap-osaka-1":     "JP",
+	"ap-mumbai-1":    "IN",
+	"ap-singapore-1": "SG",
+	"ap-sydney-1":    "AU",
+}
+
+// shapeOffers returns the on-demand and preemptible offers for a shape in a region
+func shapeOffers(shape GPUShape, region string) []providers.GPUOffer {
+	spotPrice := shape.HourlyPrice * preemptiblePriceFactor
+
+	offer := providers.GPUOffer{
+		ID:          fmt.Sprintf("%s-%s", region, shape.Name),
+		GPUType:     shape.GPUType,
+		GPUCount:    shape.GPUCount,
+		Region:      region,
+		HourlyPrice: shape.HourlyPrice,
+		SpotPrice:   spotPrice,
+		GPUMemory:   shape.GPUMemoryGiB,
+		Available:   true,
+		Provider:    ProviderName,
+		Verified:    true,
+		Country:     regionCountries[region],
+		// Each OCPU is a physical core with two hardware threads
+		VCPUs: shape.OCPUs * 2,
+	}
+
+	spot := offer
+	spot.ID += "-preemptible"
+	spot.HourlyPrice = spotPrice
+	spot.IsSpot = true
+
+	return []providers.GPUOffer{offer, spot}
+}
+
+// filterOffers applies the filters to offers
+func filterOffers(offers []providers.GPUOffer, filters *providers.GPUFilters) []providers.GPUOffer {
+	var filtered []providers.GPUOffer
+
+	for _, offer := range offers {
+		if filters.GPUType != "" {
+			gpuType, err := standardGPUType(filters.GPUType)
+			if err != nil || gpuType != offer.GPUType {
+				continue
+			}
+		}
+
+		if !providers.CountryAllowed(offer.Country, filters.Countries) {
+			continue
+		}
+
+		if filters.MaxPrice > 0 && offer.HourlyPrice > filters.MaxPrice {
+			continue
+		}
+
+		if filters.MinMemory > 0 && offer.Memory < filters.MinMemory {
+			continue
+		}
+
+		if filters.SpotOnly && !offer.IsSpot {
+			continue
+		}
+
+		if filters.OnDemandOnly && offer.IsSpot {
+			continue
+		}
+
+		if !providers.VCPUsPerGPUAllowed(offer, filters.MinVCPUPerGPU) {
+			continue
+		}
+
+		if !providers.GPUMemoryAllowed(offer, filters.MinGPUMemoryGiB) {
+			continue
+		}
+
+		filtered = append(filtered, offer)
+	}
+
+	return filtered
+}
@@ -0,0 +1,107 @@
+package oci
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// requestSigner signs OCI API requests with an API signing key, as described in
+// https://docs.oracle.com/en-us/iaas/Content/API/Concepts/signingrequests.htm
+type requestSigner struct {
+	keyID string
+	key   *rsa.PrivateKey
+	// now returns the request date; replaced in tests
+	now func() time.Time
+}
+
+// newRequestSigner creates a signer for the API key of a user in a tenancy
+func newRequestSigner(tenancy, user, fingerprint string, key *rsa.PrivateKey) *requestSigner {
+	return &requestSigner{
+		keyID: tenancy + "/" + user + "/" + fingerprint,
+		key:   key,
+		now:   time.Now,
+	}
+}
+
+// sign adds the date, body digest and Authorization headers to a request with the given body
+func (s *requestSigner) sign(req *http.Request, body []byte) error {
+	req.Header.Set("Date", s.now().UTC().Format(http.TimeFormat))
+	headers := []string{"date", "(request-target)", "host"}
+
+	// Requests with a body also sign its length, type and digest
+	if req.Method == http.MethodPost || req.Method == http.MethodPut || req.Method == http.MethodPatch {
+		digest := sha256.Sum256(body)
+		req.Header.Set("Content-Length", strconv.Itoa(len(body)))
+		if req.Header.Get("Content-Type") == "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set("X-Content-Sha256", base64.StdEncoding.EncodeToString(digest[:]))
+		headers = append(headers, "content-length", "content-type", "x-content-sha256")
+	}
+
+	var signingString bytes.Buffer
+	for i, header := range headers {
+		if i > 0 {
+			signingString.WriteByte('\n')
+		}
+		signingString.WriteString(header + ": " + signedHeaderValue(req, header))
+	}
+
+	hashed := sha256.Sum256(signingString.Bytes())
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, hashed[:])
+	if err != nil {
+		return fmt.Errorf("failed to sign OCI request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		`Signature version="1",keyId="%s",algorithm="rsa-sha256",headers="%s",signature="%s"`,
+		s.keyID, strings.Join(headers, " "), base64.StdEncoding.EncodeToString(signature)))
+	return nil
+}
+
+// signedHeaderValue returns the value of a header as it appears in the signing string
+func signedHeaderValue(req *http.Request, header string) string {
+	switch header {
+	case "(request-target)":
+		return strings.ToLower(req.Method) + " " + req.URL.RequestURI()
+	case "host":
+		return req.URL.Host
+	default:
+		return req.Header.Get(header)
+	}
+}
+
+// parsePrivateKey parses an unencrypted PEM-encoded RSA API signing key in PKCS#1 or PKCS#8 form
+func parsePrivateKey(keyPEM string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(strings.TrimSpace(keyPEM)))
+	if block == nil {
+		return nil, fmt.Errorf("OCI private key is not PEM encoded")
+	}
+	if block.Type == "ENCRYPTED PRIVATE KEY" || block.Headers["Proc-Type"] != "" {
+		return nil, fmt.Errorf("OCI private key must not be encrypted with a passphrase")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OCI private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("OCI private key must be an RSA key")
+	}
+	return key, nil
+}
@@ -0,0 +1,70 @@
+package providers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// VCPUsPerGPUAllowed reports whether an offer has at least minPerGPU vCPUs for each of its
+// GPUs. No minimum permits any offer, while an offer with unknown vCPU or GPU counts never
+// satisfies a minimum.
+func VCPUsPerGPUAllowed(offer GPUOffer, minPerGPU int) bool {
+	if minPerGPU <= 0 {
+		return true
+	}
+	if offer.VCPUs <= 0 || offer.GPUCount <= 0 {
+		return false
+	}
+	return offer.VCPUs >= minPerGPU*offer.GPUCount
+}
+
+// GPUMemoryAllowed reports whether an offer has at least minGiB of memory on each GPU. No
+// minimum permits any offer, while an offer with unknown GPU memory never satisfies a minimum.
+func GPUMemoryAllowed(offer GPUOffer, minGiB int64) bool {
+	if minGiB <= 0 {
+		return true
+	}
+	return offer.GPUMemory >= minGiB
+}
+
+// CUDAVersionAllowed reports whether an offer's host driver supports CUDA minVersion, given as
+// major.minor. No minimum permits any offer, and so does an offer whose CUDA version is unknown,
+// as its driver then comes from the node image rather than the provider. Malformed versions
+// never satisfy a minimum.
+func CUDAVersionAllowed(offer GPUOffer, minVersion string) bool {
+	if minVersion == "" || offer.CUDAVersion == "" {
+		return true
+	}
+	minMajor, minMinor, ok := parseCUDAVersion(minVersion)
+	if !ok {
+		return false
+	}
+	major, minor, ok := parseCUDAVersion(offer.CUDAVersion)
+	if !ok {
+		return false
+	}
+	return major > minMajor || (major == minMajor && minor >= minMinor)
+}
+
+// parseCUDAVersion parses a major.minor CUDA version, ignoring any patch version
+func parseCUDAVersion(version string) (int, int, bool) {
+	majorPart, rest, found := strings.Cut(version, ".")
+	if !found {
+		return 0, 0, false
+	}
+	minorPart, _, _ := strings.Cut(rest, ".")
+	major, err := strconv.Atoi(majorPart)
+	if err != nil || major < 0 {
+		return 0, 0, false
+	}
+	minor, err := strconv.Atoi(minorPart)
+	if err != nil || minor < 0 {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
+// ErrNotAvailableInRegion is returned, wrapped, when a provider does not offer the GPU type in
+// the requested region, so callers skip the provider rather than compare a price for elsewhere
+var ErrNotAvailableInRegion = errors.New("not available in region")
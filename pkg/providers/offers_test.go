@@ -0,0 +1,75 @@
+package providers
+
+import "testing"
+
+func TestVCPUsPerGPUAllowed(t *testing.T) {
+	tests := []struct {
+		name      string
+		offer     GPUOffer
+		minPerGPU int
+		want      bool
+	}{
+		{"no minimum allows any offer", GPUOffer{}, 0, true},
+		{"enough vCPUs per GPU", GPUOffer{VCPUs: 96, GPUCount: 8}, 12, true},
+		{"CPU-starved host is excluded", GPUOffer{VCPUs: 8, GPUCount: 8}, 4, false},
+		{"unknown vCPU count is excluded", GPUOffer{GPUCount: 1}, 4, false},
+		{"unknown GPU count is excluded", GPUOffer{VCPUs: 16}, 4, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VCPUsPerGPUAllowed(tt.offer, tt.minPerGPU); got != tt.want {
+				t.Errorf("VCPUsPerGPUAllowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGPUMemoryAllowed(t *testing.T) {
+	tests := []struct {
+		name   string
+		offer  GPUOffer
+		minGiB int64
+		want   bool
+	}{
+		{"no minimum allows any offer", GPUOffer{}, 0, true},
+		{"enough memory per GPU", GPUOffer{GPUMemory: 80, Memory: 16}, 40, true},
+		{"system memory does not count", GPUOffer{GPUMemory: 24, Memory: 512}, 40, false},
+		{"unknown GPU memory is excluded", GPUOffer{GPUCount: 1}, 40, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GPUMemoryAllowed(tt.offer, tt.minGiB); got != tt.want {
+				t.Errorf("GPUMemoryAllowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCUDAVersionAllowed(t *testing.T) {
+	tests := []struct {
+		name       string
+		offer      GPUOffer
+		minVersion string
+		want       bool
+	}{
+		{"no minimum allows any offer", GPUOffer{CUDAVersion: "11.8"}, "", true},
+		{"newer driver", GPUOffer{CUDAVersion: "12.4"}, "12.1", true},
+		{"same version", GPUOffer{CUDAVersion: "12.1"}, "12.1", true},
+		{"newer major version", GPUOffer{CUDAVersion: "13.0"}, "12.8", true},
+		{"minor versions compare numerically", GPUOffer{CUDAVersion: "12.10"}, "12.9", true},
+		{"patch version is ignored", GPUOffer{CUDAVersion: "12.1.1"}, "12.1", true},
+		{"older driver is excluded", GPUOffer{CUDAVersion: "11.8"}, "12.0", false},
+		{"unknown driver comes from the image", GPUOffer{}, "12.1", true},
+		{"malformed version is excluded", GPUOffer{CUDAVersion: "latest"}, "12.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CUDAVersionAllowed(tt.offer, tt.minVersion); got != tt.want {
+				t.Errorf("CUDAVersionAllowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
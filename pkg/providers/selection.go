@@ -0,0 +1,118 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Names of the built-in provider selection strategies
+const (
+	StrategyCheapest = "Cheapest"
+	StrategyPriority = "Priority"
+)
+
+// priorityWeight is the fraction added to a candidate's price for each step of priority
+const priorityWeight = 0.1
+
+// ErrNoCandidates is returned by a strategy asked to choose between no providers
+var ErrNoCandidates = errors.New("no provider candidates")
+
+// ProviderCandidate is a provider able to satisfy a launch, priced for the capacity it would use
+type ProviderCandidate struct {
+	Name   string
+	Client ProviderClient
+
+	// Priority is the provider's configured priority; lower numbers are preferred
+	Priority int
+
+	// Price is the hourly price of the capacity the provider would launch, in USD
+	Price float64
+
+	// Spot reports whether the price is for spot capacity
+	Spot bool
+
+	// Penalty multiplies the price of providers nearing their instance limit; 1 or less means none
+	Penalty float64
+
+	// BillingOverhead multiplies the price of providers whose billing increment or minimum
+	// period charges for longer than nodes are expected to run; 1 or less means none
+	BillingOverhead float64
+}
+
+// WeightedPrice returns the candidate's price weighted by its priority, penalty and billing overhead
+func (c ProviderCandidate) WeightedPrice() float64 {
+	weighted := c.Price
+	if c.Priority > 0 {
+		weighted *= 1.0 + float64(c.Priority)*priorityWeight
+	}
+	if c.Penalty > 1 {
+		weighted *= c.Penalty
+	}
+	if c.BillingOverhead > 1 {
+		weighted *= c.BillingOverhead
+	}
+	return weighted
+}
+
+// SelectionRequirement describes the capacity providers are selected for
+type SelectionRequirement struct {
+	GPUType  string
+	GPUCount int
+	Region   string
+}
+
+// SelectionStrategy chooses the provider to launch on among the candidates able to do so
+type SelectionStrategy interface {
+	Select(ctx context.Context, candidates []ProviderCandidate, requirement SelectionRequirement) (string, ProviderClient, error)
+}
+
+// CheapestStrategy selects the candidate with the lowest priority-weighted price, preferring
+// the earliest candidate on a tie
+type CheapestStrategy struct{}
+
+// Select implements SelectionStrategy
+func (CheapestStrategy) Select(ctx context.Context, candidates []ProviderCandidate, requirement SelectionRequirement) (string, ProviderClient, error) {
+	if len(candidates) == 0 {
+		return "", nil, ErrNoCandidates
+	}
+	best := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if candidate.WeightedPrice() < best.WeightedPrice() {
+			best = candidate
+		}
+	}
+	return best.Name, best.Client, nil
+}
+
+// PriorityStrategy selects the candidate with the lowest priority number, falling back to
+// the lowest weighted price between candidates of equal priority
+type PriorityStrategy struct{}
+
+// Select implements SelectionStrategy
+func (PriorityStrategy) Select(ctx context.Context, candidates []ProviderCandidate, requirement SelectionRequirement) (string, ProviderClient, error) {
+	if len(candidates) == 0 {
+		return "", nil, ErrNoCandidates
+	}
+	best := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if candidate.Priority < best.Priority ||
+			(candidate.Priority == best.Priority && candidate.WeightedPrice() < best.WeightedPrice()) {
+			best = candidate
+		}
+	}
+	return best.Name, best.Client, nil
+}
+
+// SelectionStrategyFor returns the built-in strategy with the given name. An empty name
+// selects CheapestStrategy.
+func SelectionStrategyFor(name string) (SelectionStrategy, error) {
+	switch name {
+	case "", StrategyCheapest:
+		return CheapestStrategy{}, nil
+	case StrategyPriority:
+		return PriorityStrategy{}, nil
+	default:
+		return nil, fmt.Errorf("unknown provider selection strategy %q", name)
+	}
+}
@@ -0,0 +1,69 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSelectionStrategies(t *testing.T) {
+	candidates := []ProviderCandidate{
+		{Name: "cheap-low-priority", Priority: 5, Price: 2.0},
+		{Name: "preferred", Priority: 0, Price: 3.5},
+		{Name: "cheapest-near-limit", Priority: 0, Price: 1.0, Penalty: 4},
+		{Name: "preferred-cheaper", Priority: 0, Price: 3.0},
+	}
+
+	tests := []struct {
+		name     string
+		strategy string
+		want     string
+	}{
+		{"default is cheapest weighted price", "", "cheap-low-priority"},
+		{"cheapest weighs priority and penalty", StrategyCheapest, "cheap-low-priority"},
+		{"priority prefers the lowest number, then price", StrategyPriority, "preferred-cheaper"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := SelectionStrategyFor(tt.strategy)
+			if err != nil {
+				t.Fatalf("SelectionStrategyFor(%q) error = %v", tt.strategy, err)
+			}
+			name, _, err := strategy.Select(context.Background(), candidates, SelectionRequirement{GPUType: "NVIDIA_H100"})
+			if err != nil {
+				t.Fatalf("Select() error = %v", err)
+			}
+			if name != tt.want {
+				t.Errorf("Select() = %s, want %s", name, tt.want)
+			}
+		})
+	}
+
+	if _, err := SelectionStrategyFor("RoundRobin"); err == nil {
+		t.Error("expected an error for an unknown strategy")
+	}
+	if _, _, err := (CheapestStrategy{}).Select(context.Background(), nil, SelectionRequirement{}); !errors.Is(err, ErrNoCandidates) {
+		t.Errorf("expected ErrNoCandidates without candidates, got %v", err)
+	}
+}
+
+func TestWeightedPrice(t *testing.T) {
+	candidate := ProviderCandidate{Price: 2.0, Priority: 5, Penalty: 2}
+	if got, want := candidate.WeightedPrice(), 6.0; got != want {
+		t.Errorf("WeightedPrice() = %v, want %v", got, want)
+	}
+}
+
+func TestWeightedPriceBillingOverhead(t *testing.T) {
+	// A ten minute run on an hourly provider bills six times the run
+	hourly := ProviderCandidate{Name: "hourly", Price: 1.0, BillingOverhead: 6}
+	perSecond := ProviderCandidate{Name: "per-second", Price: 2.0, BillingOverhead: 1}
+	name, _, err := (CheapestStrategy{}).Select(context.Background(), []ProviderCandidate{hourly, perSecond}, SelectionRequirement{})
+	if err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	if name != "per-second" {
+		t.Errorf("Select() = %s, want the per-second provider for a short run", name)
+	}
+}
@@ -0,0 +1,36 @@
+package providers
+
+import "context"
+
+// TagUpdater is implemented by providers that can change the tags or labels of a
+// running instance, so cost-allocation tags can follow changes to the node class.
+type TagUpdater interface {
+	// UpdateInstanceTags sets the given tags on the instance and removes the listed keys,
+	// leaving any other tags untouched
+	UpdateInstanceTags(ctx context.Context, instanceID string, set map[string]string, remove []string) error
+}
+
+// TagVerifier is implemented by providers that can read back the tags of a running instance,
+// so tags changed or removed outside the operator can be restored.
+type TagVerifier interface {
+	// MissingInstanceTags returns the tags among want that the instance lacks or holds a
+	// different value for, comparing them in the form the provider stores them
+	MissingInstanceTags(ctx context.Context, instanceID string, want map[string]string) (map[string]string, error)
+}
+
+// MissingTags returns the tags in want that actual lacks or holds a different value for.
+// stored maps a wanted tag to the key and value the provider stores for it, and may be nil
+// when tags are stored unchanged.
+func MissingTags(actual, want map[string]string, stored func(key, value string) (string, string)) map[string]string {
+	missing := make(map[string]string)
+	for key, value := range want {
+		storedKey, storedValue := key, value
+		if stored != nil {
+			storedKey, storedValue = stored(key, value)
+		}
+		if current, exists := actual[storedKey]; !exists || current != storedValue {
+			missing[key] = value
+		}
+	}
+	return missing
+}
@@ -0,0 +1,25 @@
+package providers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMissingTags(t *testing.T) {
+	want := map[string]string{"team": "ml", "env": "prod", "cost-center": "42"}
+	actual := map[string]string{"team": "ml", "env": "dev", "other": "kept"}
+
+	missing := MissingTags(actual, want, nil)
+	if len(missing) != 2 || missing["env"] != "prod" || missing["cost-center"] != "42" {
+		t.Errorf("MissingTags() = %v, want the changed env and absent cost-center tags", missing)
+	}
+
+	// Tags are compared in the stored form but reported as wanted
+	stored := map[string]string{"team": "ml", "env": "prod", "cost_center": "42"}
+	missing = MissingTags(stored, want, func(key, value string) (string, string) {
+		return strings.ReplaceAll(key, "-", "_"), value
+	})
+	if len(missing) != 0 {
+		t.Errorf("MissingTags() with a stored form = %v, want none", missing)
+	}
+}
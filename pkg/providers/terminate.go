@@ -0,0 +1,28 @@
+package providers
+
+import "context"
+
+// BatchTerminator is implemented by providers that can terminate several instances
+// more efficiently than one call per instance.
+type BatchTerminator interface {
+	// TerminateInstances terminates the instances and returns the error for each instance
+	// that could not be terminated
+	TerminateInstances(ctx context.Context, instanceIDs []string) map[string]error
+}
+
+// TerminateInstances terminates the instances with the provider's batch API when it has one,
+// falling back to a TerminateInstance call per instance. It returns the error for each
+// instance that could not be terminated.
+func TerminateInstances(ctx context.Context, client ProviderClient, instanceIDs []string) map[string]error {
+	if terminator, ok := Unwrap(client).(BatchTerminator); ok {
+		return terminator.TerminateInstances(ctx, instanceIDs)
+	}
+
+	failures := make(map[string]error)
+	for _, instanceID := range instanceIDs {
+		if err := client.TerminateInstance(ctx, instanceID); err != nil {
+			failures[instanceID] = err
+		}
+	}
+	return failures
+}
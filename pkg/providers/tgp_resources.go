@@ -60,7 +60,7 @@ func SelectOptimalGPU(requirements *TGPResourceRequirements, offers []GPUOffer)
 
 	// Filter by VRAM requirement
 	for _, offer := range offers {
-		if requirements.MinVRAM > 0 && offer.Memory < requirements.MinVRAM {
+		if !GPUMemoryAllowed(offer, requirements.MinVRAM) {
 			continue
 		}
 		candidates = append(candidates, offer)
@@ -94,6 +94,32 @@ func SelectOptimalGPU(requirements *TGPResourceRequirements, offers []GPUOffer)
 	return best
 }
 
+// SelectGPUType picks the GPU type for vendor-agnostic requirements from their VRAM and vendor preference
+func SelectGPUType(requirements *TGPResourceRequirements) string {
+	// TODO: This is a simplified implementation - we should get actual available GPUs from providers
+	// For now, use static selection based on VRAM requirements
+	if requirements.MinVRAM <= 2 {
+		// Small VRAM requirements
+		if requirements.PreferredVendor == "amd" {
+			return "AMD_MI325X" // Placeholder - would need real AMD options
+		}
+		return "NVIDIA_A16" // 2GB VRAM
+	}
+	if requirements.MinVRAM <= 8 {
+		// Medium VRAM requirements
+		if requirements.PreferredVendor == "amd" {
+			return "AMD_MI300X"
+		}
+		return "NVIDIA_A40" // 48GB VRAM (overkill but available)
+	}
+
+	// High VRAM requirements
+	if requirements.PreferredVendor == "amd" {
+		return "AMD_MI300X"
+	}
+	return "NVIDIA_A100" // 80GB VRAM
+}
+
 func matchesVendor(gpuType, preferredVendor string) bool {
 	gpuType = strings.ToUpper(gpuType)
 	switch strings.ToLower(preferredVendor) {
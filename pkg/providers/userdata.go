@@ -0,0 +1,17 @@
+package providers
+
+// UserDataRenderer is implemented by providers whose bootstrap mechanism needs the
+// generated Talos machine config adapted before launch, e.g. wrapped for a metadata
+// service or passed as a container command instead of instance user-data.
+type UserDataRenderer interface {
+	RenderUserData(machineConfig string) (string, error)
+}
+
+// RenderUserData adapts the machine config for the given provider. Providers that
+// consume the Talos machine config directly as user-data receive it unchanged.
+func RenderUserData(client ProviderClient, machineConfig string) (string, error) {
+	if renderer, ok := Unwrap(client).(UserDataRenderer); ok {
+		return renderer.RenderUserData(machineConfig)
+	}
+	return machineConfig, nil
+}
@@ -3,11 +3,15 @@ package vultr
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/vultr/govultr/v3"
 	"golang.org/x/oauth2"
 
@@ -29,32 +33,62 @@ func NewClient(apiKey string) (*Client, error) {
 		return nil, fmt.Errorf("API key is required")
 	}
 
-	config := &oauth2.Config{}
-	ctx := context.Background()
-	ts := config.TokenSource(ctx, &oauth2.Token{AccessToken: apiKey})
-	vultrClient := govultr.NewClient(oauth2.NewClient(ctx, ts))
-
 	return &Client{
-		client: vultrClient,
+		client: govultr.NewClient(newHTTPClient(apiKey, nil)),
 		apiKey: apiKey,
 	}, nil
 }
 
+// newHTTPClient returns an HTTP client that authenticates with the API key over the given transport
+func newHTTPClient(apiKey string, base http.RoundTripper) *http.Client {
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: apiKey})
+	return &http.Client{Transport: &oauth2.Transport{Source: ts, Base: base}}
+}
+
+// EnableDebugLogging logs every Vultr API request and response at debug level
+func (c *Client) EnableDebugLogging(log logr.Logger) {
+	c.client = govultr.NewClient(newHTTPClient(c.apiKey, providers.NewDebugTransport(nil, log)))
+}
+
 func (c *Client) LaunchInstance(ctx context.Context, req *providers.LaunchRequest) (*providers.GPUInstance, error) {
+	if len(req.DataDisks) > 0 {
+		return nil, fmt.Errorf("vultr does not support attaching data disks at launch; use AttachDataDisk once the instance is active")
+	}
+
+	if req.Region != "" {
+		region, err := c.TranslateRegion(req.Region)
+		if err != nil {
+			return nil, err
+		}
+		regionReq := *req
+		regionReq.Region = region
+		req = &regionReq
+	}
+
 	plan, err := c.findBestPlan(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find suitable plan: %w", err)
 	}
+	region := req.Region
+	if region == "" {
+		// Vultr needs a region, so use the first one the plan is available in
+		region = plan.Locations[0]
+	}
+	// The boot disk comes with the plan and cannot be resized at creation
+	if req.BootDiskGiB > plan.Disk {
+		return nil, fmt.Errorf("plan %s has a %dGB boot disk, smaller than the requested %dGiB", plan.ID, plan.Disk, req.BootDiskGiB)
+	}
 
 	// Base64 encode the user data as required by Vultr
 	encodedUserData := base64.StdEncoding.EncodeToString([]byte(req.UserData))
 
 	instanceReq := &govultr.InstanceCreateReq{
-		Region:   req.Region,
+		Region:   region,
 		Plan:     plan.ID,
 		OsID:     2284, // Talos Linux OS ID
 		Label:    fmt.Sprintf("tgp-%s", req.GPUType),
 		UserData: encodedUserData,
+		Tags:     formatTags(req.Tags),
 	}
 
 	// Debug logging
@@ -63,7 +97,7 @@ func (c *Client) LaunchInstance(ctx context.Context, req *providers.LaunchReques
 
 	instance, _, err := c.client.Instance.Create(ctx, instanceReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Vultr instance: %w", err)
+		return nil, fmt.Errorf("failed to create Vultr instance: %w", apiError(err))
 	}
 
 	createdAt, _ := time.Parse("2006-01-02T15:04:05-07:00", instance.DateCreated)
@@ -79,16 +113,113 @@ func (c *Client) LaunchInstance(ctx context.Context, req *providers.LaunchReques
 
 func (c *Client) TerminateInstance(ctx context.Context, instanceID string) error {
 	err := c.client.Instance.Delete(ctx, instanceID)
+	if isNotFound(err) {
+		// The instance is already gone
+		return nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to delete Vultr instance %s: %w", instanceID, err)
+		return fmt.Errorf("failed to delete Vultr instance %s: %w", instanceID, apiError(err))
 	}
 	return nil
 }
 
+// AttachDataDisk attaches an existing block storage volume to an instance
+func (c *Client) AttachDataDisk(ctx context.Context, instanceID string, disk providers.DataDisk) error {
+	if disk.ReadOnly {
+		return fmt.Errorf("vultr block storage does not support read-only attachments")
+	}
+
+	live := true
+	if err := c.client.BlockStorage.Attach(ctx, disk.VolumeID, &govultr.BlockStorageAttach{
+		InstanceID: instanceID,
+		Live:       &live,
+	}); err != nil {
+		return fmt.Errorf("failed to attach Vultr block storage %s to instance %s: %w", disk.VolumeID, instanceID, apiError(err))
+	}
+	return nil
+}
+
+// DetachDataDisk detaches a block storage volume from its instance
+func (c *Client) DetachDataDisk(ctx context.Context, instanceID, volumeID string) error {
+	live := true
+	if err := c.client.BlockStorage.Detach(ctx, volumeID, &govultr.BlockStorageDetach{Live: &live}); err != nil {
+		return fmt.Errorf("failed to detach Vultr block storage %s from instance %s: %w", volumeID, instanceID, apiError(err))
+	}
+	return nil
+}
+
+// UpdateInstanceTags updates the tags of a running instance
+func (c *Client) UpdateInstanceTags(ctx context.Context, instanceID string, set map[string]string, remove []string) error {
+	instance, _, err := c.client.Instance.Get(ctx, instanceID)
+	if err != nil {
+		return fmt.Errorf("failed to get Vultr instance %s: %w", instanceID, apiError(err))
+	}
+
+	if _, _, err := c.client.Instance.Update(ctx, instanceID, &govultr.InstanceUpdateReq{
+		Tags: mergeTags(instance.Tags, set, remove),
+	}); err != nil {
+		return fmt.Errorf("failed to update tags on Vultr instance %s: %w", instanceID, apiError(err))
+	}
+	return nil
+}
+
+// MissingInstanceTags returns the wanted tags the instance lacks or holds a different value for
+func (c *Client) MissingInstanceTags(ctx context.Context, instanceID string, want map[string]string) (map[string]string, error) {
+	instance, _, err := c.client.Instance.Get(ctx, instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Vultr instance %s: %w", instanceID, apiError(err))
+	}
+	return providers.MissingTags(parseTags(instance.Tags), want, nil), nil
+}
+
+// parseTags converts Vultr's "key=value" string tags back to key/value tags
+func parseTags(tags []string) map[string]string {
+	parsed := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		key, value, _ := strings.Cut(tag, "=")
+		parsed[key] = value
+	}
+	return parsed
+}
+
+// formatTags converts key/value tags to Vultr's plain string tags as "key=value"
+func formatTags(tags map[string]string) []string {
+	formatted := make([]string, 0, len(tags))
+	for k, v := range tags {
+		formatted = append(formatted, k+"="+v)
+	}
+	sort.Strings(formatted)
+	return formatted
+}
+
+// mergeTags applies tag changes to an instance's existing tags, replacing any tag with the same key
+func mergeTags(existing []string, set map[string]string, remove []string) []string {
+	merged := make([]string, 0, len(existing)+len(set))
+	for _, tag := range existing {
+		key, _, _ := strings.Cut(tag, "=")
+		if _, replaced := set[key]; replaced {
+			continue
+		}
+		removed := false
+		for _, k := range remove {
+			if k == key {
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			merged = append(merged, tag)
+		}
+	}
+	merged = append(merged, formatTags(set)...)
+	sort.Strings(merged)
+	return merged
+}
+
 func (c *Client) GetInstanceStatus(ctx context.Context, instanceID string) (*providers.InstanceStatus, error) {
 	instance, _, err := c.client.Instance.Get(ctx, instanceID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get Vultr instance %s: %w", instanceID, err)
+		return nil, fmt.Errorf("failed to get Vultr instance %s: %w", instanceID, apiError(err))
 	}
 
 	return &providers.InstanceStatus{
@@ -101,10 +232,20 @@ func (c *Client) GetInstanceStatus(ctx context.Context, instanceID string) (*pro
 }
 
 func (c *Client) ListAvailableGPUs(ctx context.Context, filters *providers.GPUFilters) ([]providers.GPUOffer, error) {
+	if filters != nil && filters.Region != "" {
+		region, err := c.TranslateRegion(filters.Region)
+		if err != nil {
+			return nil, err
+		}
+		regionFilters := *filters
+		regionFilters.Region = region
+		filters = &regionFilters
+	}
+
 	options := &govultr.ListOptions{}
 	plans, _, _, err := c.client.Plan.List(ctx, "vcg", options)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list GPU plans: %w", err)
+		return nil, fmt.Errorf("failed to list GPU plans: %w", apiError(err))
 	}
 
 	var offers []providers.GPUOffer
@@ -118,41 +259,47 @@ func (c *Client) ListAvailableGPUs(ctx context.Context, filters *providers.GPUFi
 			continue
 		}
 
-		if filters != nil && filters.Region != "" && !c.isPlanAvailableInRegion(&plan, filters.Region) {
-			continue
-		}
-
 		hourlyPrice := c.calculateHourlyPrice(plan.MonthlyCost)
 		if filters != nil && filters.MaxPrice > 0 && hourlyPrice > filters.MaxPrice {
 			continue
 		}
 
-		region := ""
-		if filters != nil {
-			region = filters.Region
-		}
-
 		// Extract VRAM from plan ID (e.g., vcg-a16-2c-8g-2vram -> 2GB VRAM)
 		vram := c.extractVRAMFromPlan(&plan)
 
-		// Apply VRAM filtering if specified
-		if filters != nil && filters.MinMemory > 0 && vram < filters.MinMemory {
+		// Skip plans with too little memory on each GPU
+		if filters != nil && !providers.GPUMemoryAllowed(providers.GPUOffer{GPUMemory: vram}, filters.MinGPUMemoryGiB) {
+			continue
+		}
+
+		// Skip plans with too little system memory
+		memory := int64(plan.RAM / 1024)
+		if filters != nil && filters.MinMemory > 0 && memory < filters.MinMemory {
 			continue
 		}
 
-		offer := providers.GPUOffer{
-			ID:          plan.ID,
-			GPUType:     gpuType,
-			GPUCount:    gpuCount,
-			Region:      region,
-			HourlyPrice: hourlyPrice,
-			Memory:      vram, // Use VRAM instead of system RAM
-			Storage:     int64(plan.Disk),
-			Available:   true,
-			Provider:    ProviderName,
+		// Skip plans without enough vCPUs to feed their GPUs
+		if filters != nil && !providers.VCPUsPerGPUAllowed(providers.GPUOffer{VCPUs: plan.VCPUCount, GPUCount: gpuCount}, filters.MinVCPUPerGPU) {
+			continue
 		}
 
-		offers = append(offers, offer)
+		for _, region := range offerRegions(&plan, filters) {
+			offers = append(offers, providers.GPUOffer{
+				ID:          plan.ID,
+				GPUType:     gpuType,
+				GPUCount:    gpuCount,
+				Region:      region,
+				HourlyPrice: hourlyPrice,
+				Memory:      memory,
+				GPUMemory:   vram,
+				Storage:     int64(plan.Disk),
+				Available:   true,
+				Provider:    ProviderName,
+				Verified:    true,
+				Country:     regionCountries[region],
+				VCPUs:       plan.VCPUCount,
+			})
+		}
 	}
 
 	return offers, nil
@@ -166,11 +313,14 @@ func (c *Client) GetNormalizedPricing(ctx context.Context, gpuType, region strin
 
 	offers, err := c.ListAvailableGPUs(ctx, filters)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get pricing: %w", err)
+		return nil, fmt.Errorf("failed to get pricing: %w", apiError(err))
 	}
 
 	if len(offers) == 0 {
-		return nil, fmt.Errorf("no pricing available for %s in region %s", gpuType, region)
+		if region != "" {
+			return nil, fmt.Errorf("no %s plan in region %s: %w", gpuType, region, providers.ErrNotAvailableInRegion)
+		}
+		return nil, fmt.Errorf("no pricing available for %s", gpuType)
 	}
 
 	// Find the cheapest offer
@@ -190,6 +340,14 @@ func (c *Client) GetNormalizedPricing(ctx context.Context, gpuType, region strin
 	}, nil
 }
 
+// CheckCredentials reads the account the API key belongs to, which fails when the key is invalid
+func (c *Client) CheckCredentials(ctx context.Context) error {
+	if _, _, err := c.client.Account.Get(ctx); err != nil {
+		return fmt.Errorf("failed to get Vultr account: %w", apiError(err))
+	}
+	return nil
+}
+
 func (c *Client) GetProviderInfo() *providers.ProviderInfo {
 	return &providers.ProviderInfo{
 		Name:                  ProviderName,
@@ -199,6 +357,9 @@ func (c *Client) GetProviderInfo() *providers.ProviderInfo {
 		SupportsMultiGPU:      true,
 		BillingGranularity:    providers.BillingPerHour,
 		MinBillingPeriod:      time.Hour,
+		ReliabilityTier:       providers.ReliabilityTierEnterprise,
+
+		AttachesDataDisksAfterLaunch: true,
 	}
 }
 
@@ -235,22 +396,85 @@ func (c *Client) TranslateGPUType(standard string) (string, error) {
 	return "", fmt.Errorf("unsupported GPU type: %s", standard)
 }
 
+// TranslateRegion returns the Vultr region ID, such as "ewr", for a standard region or a Vultr region ID
 func (c *Client) TranslateRegion(standard string) (string, error) {
-	// Vultr uses region IDs like "ewr" (New Jersey), "lax" (Los Angeles), etc.
-	// This would need to be implemented based on actual region mappings
-	return standard, nil
+	if region, exists := standardRegions[standard]; exists {
+		return region, nil
+	}
+	region := strings.ToLower(standard)
+	if _, exists := regionCountries[region]; exists {
+		return region, nil
+	}
+	return "", fmt.Errorf("unsupported region: %s", standard)
+}
+
+// standardRegions maps the standard regions to the Vultr region serving each
+var standardRegions = map[string]string{
+	providers.RegionUSEast:      "ewr",
+	providers.RegionUSWest:      "lax",
+	providers.RegionEUCentral:   "fra",
+	providers.RegionAsiaPacific: "nrt",
+}
+
+// regionCountries maps Vultr region IDs to the ISO 3166-1 alpha-2 code of the country they are in
+var regionCountries = map[string]string{
+	"atl": "US",
+	"dfw": "US",
+	"ewr": "US",
+	"hnl": "US",
+	"lax": "US",
+	"mia": "US",
+	"ord": "US",
+	"sea": "US",
+	"sjc": "US",
+	"yto": "CA",
+	"mex": "MX",
+	"sao": "BR",
+	"scl": "CL",
+	"ams": "NL",
+	"cdg": "FR",
+	"fra": "DE",
+	"lhr": "GB",
+	"man": "GB",
+	"mad": "ES",
+	"sto": "SE",
+	"waw": "PL",
+	"tlv": "IL",
+	"jnb": "ZA",
+	"blr": "IN",
+	"bom": "IN",
+	"del": "IN",
+	"icn": "KR",
+	"itm": "JP",
+	"nrt": "JP",
+	"sgp": "SG",
+	"mel": "AU",
+	"syd": "AU",
+}
+
+// RegionCountry returns the country a Vultr region is in
+func (c *Client) RegionCountry(region string) (string, bool) {
+	country, ok := regionCountries[region]
+	return country, ok
 }
 
 func (c *Client) findBestPlan(ctx context.Context, req *providers.LaunchRequest) (*govultr.Plan, error) {
 	options := &govultr.ListOptions{}
 	plans, _, _, err := c.client.Plan.List(ctx, "vcg", options)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list GPU plans: %w", err)
+		return nil, fmt.Errorf("failed to list GPU plans: %w", apiError(err))
 	}
 
+	return c.selectPlan(plans, req)
+}
+
+// selectPlan picks the plan with the fewest GPUs that satisfies the request, then the cheapest
+func (c *Client) selectPlan(plans []govultr.Plan, req *providers.LaunchRequest) (*govultr.Plan, error) {
+	wanted := req.RequestedGPUs()
 	var bestPlan *govultr.Plan
+	bestCount, largestCount := 0, 0
 	for _, plan := range plans {
-		gpuType, _ := c.extractGPUFromPlan(&plan)
+		gpuType, gpuCount := c.extractGPUFromPlan(&plan)
 		if !strings.EqualFold(gpuType, req.GPUType) {
 			continue
 		}
@@ -259,12 +483,21 @@ func (c *Client) findBestPlan(ctx context.Context, req *providers.LaunchRequest)
 			continue
 		}
 
-		if bestPlan == nil || plan.MonthlyCost < bestPlan.MonthlyCost {
+		largestCount = max(largestCount, gpuCount)
+		if gpuCount < wanted {
+			continue
+		}
+
+		if bestPlan == nil || gpuCount < bestCount || (gpuCount == bestCount && plan.MonthlyCost < bestPlan.MonthlyCost) {
 			bestPlan = &plan
+			bestCount = gpuCount
 		}
 	}
 
 	if bestPlan == nil {
+		if largestCount > 0 {
+			return nil, fmt.Errorf("no %s plan in region %s has %d GPUs, the largest has %d", req.GPUType, req.Region, wanted, largestCount)
+		}
 		return nil, fmt.Errorf("no suitable GPU plan found for %s in region %s", req.GPUType, req.Region)
 	}
 
@@ -276,24 +509,50 @@ func (c *Client) extractGPUFromPlan(plan *govultr.Plan) (string, int) {
 	if plan.ID != "" {
 		planID := strings.ToLower(plan.ID)
 		if gpu := c.extractGPUFromID(planID); gpu != "" {
-			return gpu, 1
+			return gpu, c.planGPUCount(plan, gpu)
 		}
 	}
 
 	// Use GPUType field if available, keep full string
 	if plan.GPUType != "" {
-		return strings.ToUpper(plan.GPUType), 1
+		gpu := strings.ToUpper(plan.GPUType)
+		return gpu, c.planGPUCount(plan, gpu)
 	}
 
 	// Fallback to plan type parsing
 	planType := strings.ToUpper(plan.Type)
 	if gpu := c.extractGPUFromID(planType); gpu != "" {
-		return gpu, 1
+		return gpu, c.planGPUCount(plan, gpu)
 	}
 
 	return "", 0
 }
 
+// gpuMemoryGB is the memory of one full GPU of each type, used to count the GPUs in a plan
+var gpuMemoryGB = map[string]int64{
+	"NVIDIA_H100": 80,
+	"NVIDIA_L40S": 48,
+	"NVIDIA_A100": 80,
+	"NVIDIA_A40":  48,
+	"NVIDIA_A16":  16,
+	"AMD_MI325X":  256,
+	"AMD_MI300X":  192,
+}
+
+// planGPUCount returns how many GPUs a plan attaches, derived from its total VRAM. Plans with
+// a fraction of a GPU, or whose VRAM is unknown, expose a single GPU.
+func (c *Client) planGPUCount(plan *govultr.Plan, gpuType string) int {
+	vram := int64(plan.GPUVRAM)
+	if vram == 0 {
+		vram = c.extractVRAMFromPlan(plan)
+	}
+	perGPU, ok := gpuMemoryGB[gpuType]
+	if !ok || vram < perGPU {
+		return 1
+	}
+	return int(vram / perGPU)
+}
+
 // extractGPUFromID extracts GPU type from plan ID or type string
 func (c *Client) extractGPUFromID(idOrType string) string {
 	idOrType = strings.ToUpper(idOrType)
@@ -347,9 +606,27 @@ func (c *Client) calculateHourlyPrice(monthlyCost float32) float64 {
 	return float64(monthlyCost) / 730.0
 }
 
+// offerRegions returns the plan locations to report an offer in: the filter's region when the plan
+// is available there, otherwise every location, keeping only those in an allowed country
+func offerRegions(plan *govultr.Plan, filters *providers.GPUFilters) []string {
+	var regions []string
+	for _, location := range plan.Locations {
+		if filters != nil && filters.Region != "" && location != filters.Region {
+			continue
+		}
+		if filters != nil && !providers.CountryAllowed(regionCountries[location], filters.Countries) {
+			continue
+		}
+		regions = append(regions, location)
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+// isPlanAvailableInRegion reports whether the plan is available in the region, or anywhere without one
 func (c *Client) isPlanAvailableInRegion(plan *govultr.Plan, region string) bool {
 	if region == "" {
-		return true
+		return len(plan.Locations) > 0
 	}
 
 	for _, availableRegion := range plan.Locations {
@@ -374,3 +651,32 @@ func (c *Client) mapInstanceStatus(status string) providers.InstanceState {
 		return providers.InstanceStateUnknown
 	}
 }
+
+// isNotFound reports whether a Vultr API error means the resource does not exist
+func isNotFound(err error) bool {
+	return responseStatus(err) == http.StatusNotFound
+}
+
+// responseStatus returns the HTTP status of a Vultr API error, or 0 when it has none.
+// govultr returns the response body as the error, e.g. {"error":"...","status":404}.
+func responseStatus(err error) int {
+	if err == nil {
+		return 0
+	}
+	var body struct {
+		Status int `json:"status"`
+	}
+	if json.Unmarshal([]byte(err.Error()), &body) != nil {
+		return 0
+	}
+	return body.Status
+}
+
+// apiError classifies a Vultr API error by its HTTP status
+func apiError(err error) error {
+	status := responseStatus(err)
+	if status == 0 {
+		return err
+	}
+	return providers.NewAPIError(ProviderName, nil, status, err)
+}
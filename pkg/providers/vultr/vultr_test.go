@@ -2,7 +2,9 @@ package vultr
 
 import (
 	"context"
+	"errors"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/solanyn/tgp-operator/pkg/providers"
@@ -53,6 +55,9 @@ func TestClient_GetProviderInfo(t *testing.T) {
 	if len(info.SupportedGPUTypes) != len(expectedGPUs) {
 		t.Errorf("GetProviderInfo().SupportedGPUTypes returned %d GPU types, want %d", len(info.SupportedGPUTypes), len(expectedGPUs))
 	}
+	if !info.AttachesDataDisksAfterLaunch {
+		t.Error("GetProviderInfo().AttachesDataDisksAfterLaunch = false, want true")
+	}
 }
 
 func TestClient_GetRateLimits(t *testing.T) {
@@ -100,12 +105,30 @@ func TestClient_TranslateGPUType(t *testing.T) {
 func TestClient_TranslateRegion(t *testing.T) {
 	client, _ := NewClient("test-key")
 
-	result, err := client.TranslateRegion("us-east")
-	if err != nil {
-		t.Errorf("TranslateRegion() error = %v", err)
+	tests := []struct {
+		region   string
+		expected string
+		wantErr  bool
+	}{
+		{providers.RegionUSEast, "ewr", false},
+		{providers.RegionUSWest, "lax", false},
+		{providers.RegionEUCentral, "fra", false},
+		{providers.RegionAsiaPacific, "nrt", false},
+		{"ams", "ams", false},
+		{"SJC", "sjc", false},
+		{"mars-1", "", true},
 	}
-	if result != "us-east" {
-		t.Errorf("TranslateRegion() = %s, want us-east", result)
+
+	for _, tt := range tests {
+		t.Run(tt.region, func(t *testing.T) {
+			result, err := client.TranslateRegion(tt.region)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TranslateRegion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if result != tt.expected {
+				t.Errorf("TranslateRegion() = %s, want %s", result, tt.expected)
+			}
+		})
 	}
 }
 
@@ -165,6 +188,103 @@ func TestClient_mapInstanceStatus(t *testing.T) {
 	}
 }
 
+func TestMergeTags(t *testing.T) {
+	existing := []string{"team=ml", "cost-center=old", "legacy", "env=dev"}
+	merged := mergeTags(existing, map[string]string{"cost-center": "new"}, []string{"env"})
+
+	expected := []string{"cost-center=new", "legacy", "team=ml"}
+	if len(merged) != len(expected) {
+		t.Fatalf("mergeTags() = %v, want %v", merged, expected)
+	}
+	for i := range expected {
+		if merged[i] != expected[i] {
+			t.Errorf("mergeTags() = %v, want %v", merged, expected)
+			break
+		}
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	tags := parseTags([]string{"team=ml", "legacy", "url=a=b"})
+	if len(tags) != 3 || tags["team"] != "ml" || tags["legacy"] != "" || tags["url"] != "a=b" {
+		t.Errorf("parseTags() = %v, want team=ml, legacy and url=a=b", tags)
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	if !isNotFound(errors.New(`{"error":"Invalid instance-id.","status":404}`)) {
+		t.Error("Expected a 404 response to be treated as a missing instance")
+	}
+	if isNotFound(errors.New(`{"error":"Unauthorized","status":401}`)) {
+		t.Error("Expected a 401 response not to be treated as a missing instance")
+	}
+	if isNotFound(nil) {
+		t.Error("Expected no error not to be treated as a missing instance")
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	if err := apiError(errors.New(`{"error":"Unauthorized","status":401}`)); !errors.Is(err, providers.ErrUnauthorized) {
+		t.Errorf("Expected a 401 response to be an unauthorized error, got %v", err)
+	}
+	if err := apiError(errors.New(`{"error":"Insufficient funds","status":402}`)); !providers.IsBillingError(err) {
+		t.Errorf("Expected a 402 response to be a billing error, got %v", err)
+	}
+	if err := apiError(errors.New("connection reset")); errors.Is(err, providers.ErrUnauthorized) {
+		t.Errorf("Expected an unclassified error, got %v", err)
+	}
+}
+
+func TestOfferRegions(t *testing.T) {
+	plan := &govultr.Plan{Locations: []string{"ewr", "fra", "ams", "cdg"}}
+
+	tests := []struct {
+		name     string
+		filters  *providers.GPUFilters
+		expected []string
+	}{
+		{
+			name:     "no filters reports every location",
+			filters:  nil,
+			expected: []string{"ams", "cdg", "ewr", "fra"},
+		},
+		{
+			name:     "region filter reports that region",
+			filters:  &providers.GPUFilters{Region: "ewr"},
+			expected: []string{"ewr"},
+		},
+		{
+			name:     "region the plan is not available in",
+			filters:  &providers.GPUFilters{Region: "lax"},
+			expected: nil,
+		},
+		{
+			name:     "region outside allowed countries is dropped",
+			filters:  &providers.GPUFilters{Region: "ewr", Countries: []string{"DE"}},
+			expected: nil,
+		},
+		{
+			name:     "country filter reports each allowed location",
+			filters:  &providers.GPUFilters{Countries: []string{"DE", "FR"}},
+			expected: []string{"cdg", "fra"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			regions := offerRegions(plan, tt.filters)
+			if len(regions) != len(tt.expected) {
+				t.Fatalf("offerRegions() = %v, want %v", regions, tt.expected)
+			}
+			for i := range tt.expected {
+				if regions[i] != tt.expected[i] {
+					t.Errorf("offerRegions() = %v, want %v", regions, tt.expected)
+				}
+			}
+		})
+	}
+}
+
 func TestClient_calculateHourlyPrice(t *testing.T) {
 	client, _ := NewClient("test-key")
 
@@ -246,7 +366,7 @@ func TestClient_VRAMFiltering_Integration(t *testing.T) {
 
 	// Test filtering by minimum VRAM (4GB+)
 	filters := &providers.GPUFilters{
-		MinMemory: 4, // 4GB VRAM minimum
+		MinGPUMemoryGiB: 4, // 4GB VRAM minimum
 	}
 
 	gpus, err := client.ListAvailableGPUs(ctx, filters)
@@ -259,11 +379,50 @@ func TestClient_VRAMFiltering_Integration(t *testing.T) {
 		if i >= 3 { // Show only first 3
 			break
 		}
-		t.Logf("  %d. %s - %dGB VRAM - $%.2f/hr (ID: %s)", i+1, gpu.GPUType, gpu.Memory, gpu.HourlyPrice, gpu.ID)
+		t.Logf("  %d. %s - %dGB VRAM - $%.2f/hr (ID: %s)", i+1, gpu.GPUType, gpu.GPUMemory, gpu.HourlyPrice, gpu.ID)
 
 		// Verify all results have at least 4GB VRAM
-		if gpu.Memory < 4 {
-			t.Errorf("GPU offer %s has %dGB VRAM, expected >= 4GB", gpu.ID, gpu.Memory)
+		if gpu.GPUMemory < 4 {
+			t.Errorf("GPU offer %s has %dGB VRAM, expected >= 4GB", gpu.ID, gpu.GPUMemory)
 		}
 	}
 }
+
+func TestClient_selectPlan(t *testing.T) {
+	client, _ := NewClient("test-key")
+
+	plans := []govultr.Plan{
+		{ID: "vcg-a100-3c-30g-20vram", MonthlyCost: 400, Locations: []string{"ewr"}},
+		{ID: "vcg-a100-12c-120g-80vram", MonthlyCost: 1750, Locations: []string{"ewr"}},
+		{ID: "vcg-a100-24c-240g-160vram", MonthlyCost: 3500, Locations: []string{"ewr"}},
+		{ID: "vcg-a100-48c-480g-320vram", MonthlyCost: 7000, Locations: []string{"ewr"}},
+		{ID: "vcg-a100-64c-512g-320vram", MonthlyCost: 6500, Locations: []string{"ewr"}},
+	}
+
+	tests := []struct {
+		name     string
+		gpuCount int
+		expectID string
+	}{
+		{"unset count picks the cheapest plan", 0, "vcg-a100-3c-30g-20vram"},
+		{"two GPUs", 2, "vcg-a100-24c-240g-160vram"},
+		{"three GPUs round up to the smallest plan that fits", 3, "vcg-a100-64c-512g-320vram"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := client.selectPlan(plans, &providers.LaunchRequest{GPUType: "NVIDIA_A100", GPUCount: tt.gpuCount, Region: "ewr"})
+			if err != nil {
+				t.Fatalf("selectPlan() error = %v", err)
+			}
+			if plan.ID != tt.expectID {
+				t.Errorf("selectPlan() = %s, want %s", plan.ID, tt.expectID)
+			}
+		})
+	}
+
+	_, err := client.selectPlan(plans, &providers.LaunchRequest{GPUType: "NVIDIA_A100", GPUCount: 8, Region: "ewr"})
+	if err == nil || !strings.Contains(err.Error(), "the largest has 4") {
+		t.Errorf("selectPlan() for 8 GPUs error = %v, want an error naming the largest plan", err)
+	}
+}
@@ -0,0 +1,206 @@
+package tailscale
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultAPIURL  = "https://api.tailscale.com"
+	DefaultTimeout = 30 * time.Second
+
+	// DefaultKeyExpiry is how long generated auth keys remain usable. Nodes only need the key
+	// to join the tailnet once, so it is kept short.
+	DefaultKeyExpiry = time.Hour
+
+	// tokenExpiryMargin renews access tokens this long before they expire
+	tokenExpiryMargin = time.Minute
+)
+
+// Client generates Tailscale auth keys with the credentials of an OAuth client
+type Client struct {
+	baseURL      string
+	clientID     string
+	clientSecret string
+	httpClient   *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	tokenExpiry time.Time
+}
+
+// NewClient creates a new Tailscale API client for an OAuth client
+func NewClient(baseURL, clientID, clientSecret string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultAPIURL
+	}
+
+	return &Client{
+		baseURL:      strings.TrimSuffix(baseURL, "/"),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		httpClient: &http.Client{
+			Timeout: DefaultTimeout,
+		},
+	}
+}
+
+// AuthKeyOptions describes the auth key to generate
+type AuthKeyOptions struct {
+	// Tags are applied to the device joining with the key. OAuth clients may only create tagged keys.
+	Tags []string
+
+	// Ephemeral removes the device from the tailnet once it goes offline
+	Ephemeral bool
+
+	// Description is shown for the key in the admin console
+	Description string
+
+	// Expiry is how long the key remains usable, DefaultKeyExpiry when zero
+	Expiry time.Duration
+}
+
+// tokenResponse represents the response from the OAuth token endpoint
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// createKeyRequest represents a request to create an auth key
+type createKeyRequest struct {
+	Capabilities  keyCapabilities `json:"capabilities"`
+	ExpirySeconds int64           `json:"expirySeconds"`
+	Description   string          `json:"description,omitempty"`
+}
+
+type keyCapabilities struct {
+	Devices struct {
+		Create struct {
+			Reusable      bool     `json:"reusable"`
+			Ephemeral     bool     `json:"ephemeral"`
+			Preauthorized bool     `json:"preauthorized"`
+			Tags          []string `json:"tags"`
+		} `json:"create"`
+	} `json:"devices"`
+}
+
+// createKeyResponse represents the response from creating an auth key
+type createKeyResponse struct {
+	ID  string `json:"id"`
+	Key string `json:"key"`
+}
+
+// CreateAuthKey generates a single-use, preauthorized auth key
+func (c *Client) CreateAuthKey(ctx context.Context, opts AuthKeyOptions) (string, error) {
+	if len(opts.Tags) == 0 {
+		return "", fmt.Errorf("auth keys created with OAuth credentials must have at least one tag")
+	}
+
+	expiry := opts.Expiry
+	if expiry == 0 {
+		expiry = DefaultKeyExpiry
+	}
+
+	req := createKeyRequest{
+		ExpirySeconds: int64(expiry.Seconds()),
+		Description:   opts.Description,
+	}
+	req.Capabilities.Devices.Create.Ephemeral = opts.Ephemeral
+	req.Capabilities.Devices.Create.Preauthorized = true
+	req.Capabilities.Devices.Create.Tags = opts.Tags
+
+	reqBody, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	token, err := c.token(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get access token: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/v2/tailnet/-/keys", bytes.NewReader(reqBody))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var keyResp createKeyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&keyResp); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if keyResp.Key == "" {
+		return "", fmt.Errorf("API response did not include an auth key")
+	}
+
+	return keyResp.Key, nil
+}
+
+// token returns an access token for the OAuth client, exchanging the client credentials for a
+// new one when the cached token is about to expire
+func (c *Client) token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.accessToken != "" && time.Now().Add(tokenExpiryMargin).Before(c.tokenExpiry) {
+		return c.accessToken, nil
+	}
+
+	form := url.Values{
+		"client_id":     {c.clientID},
+		"client_secret": {c.clientSecret},
+		"grant_type":    {"client_credentials"},
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/v2/oauth/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("token request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var tokenResp tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if tokenResp.AccessToken == "" {
+		return "", fmt.Errorf("token response did not include an access token")
+	}
+
+	c.accessToken = tokenResp.AccessToken
+	c.tokenExpiry = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+
+	return c.accessToken, nil
+}
@@ -0,0 +1,88 @@
+package tailscale
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// fakeAPI serves the OAuth token and auth key endpoints of the Tailscale API
+type fakeAPI struct {
+	tokenRequests int
+	keyRequests   []createKeyRequest
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/api/v2/oauth/token":
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("client_id") != "id" || r.PostForm.Get("client_secret") != "secret" {
+			http.Error(w, "invalid client", http.StatusUnauthorized)
+			return
+		}
+		f.tokenRequests++
+		_ = json.NewEncoder(w).Encode(tokenResponse{AccessToken: "token", ExpiresIn: 3600})
+	case "/api/v2/tailnet/-/keys":
+		if r.Header.Get("Authorization") != "Bearer token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var req createKeyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.keyRequests = append(f.keyRequests, req)
+		_ = json.NewEncoder(w).Encode(createKeyResponse{ID: "k", Key: fmt.Sprintf("tskey-auth-%d", len(f.keyRequests))})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestCreateAuthKey(t *testing.T) {
+	api := &fakeAPI{}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	client := NewClient(server.URL, "id", "secret")
+	opts := AuthKeyOptions{Tags: []string{"tag:gpu"}, Ephemeral: true, Description: "node"}
+
+	first, err := client.CreateAuthKey(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("CreateAuthKey failed: %v", err)
+	}
+	second, err := client.CreateAuthKey(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("CreateAuthKey failed: %v", err)
+	}
+
+	if first == second {
+		t.Errorf("expected a new key for each call, got %s twice", first)
+	}
+	if api.tokenRequests != 1 {
+		t.Errorf("expected the access token to be reused, got %d token requests", api.tokenRequests)
+	}
+
+	create := api.keyRequests[0].Capabilities.Devices.Create
+	if create.Reusable || !create.Ephemeral || !create.Preauthorized || !slices.Equal(create.Tags, opts.Tags) {
+		t.Errorf("unexpected key capabilities %+v", create)
+	}
+	if api.keyRequests[0].ExpirySeconds != int64(DefaultKeyExpiry.Seconds()) {
+		t.Errorf("expected expiry %v, got %ds", DefaultKeyExpiry, api.keyRequests[0].ExpirySeconds)
+	}
+}
+
+func TestCreateAuthKeyErrors(t *testing.T) {
+	server := httptest.NewServer(&fakeAPI{})
+	defer server.Close()
+
+	if _, err := NewClient(server.URL, "id", "secret").CreateAuthKey(context.Background(), AuthKeyOptions{}); err == nil {
+		t.Error("expected untagged keys to be rejected")
+	}
+	if _, err := NewClient(server.URL, "id", "wrong").CreateAuthKey(context.Background(), AuthKeyOptions{Tags: []string{"tag:gpu"}}); err == nil {
+		t.Error("expected invalid OAuth credentials to fail")
+	}
+}
@@ -3,13 +3,17 @@ package webhooks
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers/factory"
 	"github.com/solanyn/tgp-operator/pkg/validation"
 )
 
@@ -59,110 +63,183 @@ func (v *GPUNodeClassValidator) ValidateDelete(ctx context.Context, obj runtime.
 	return nil, nil
 }
 
-// validateGPUNodeClass performs comprehensive validation of a GPUNodeClass
+// validateGPUNodeClass performs comprehensive validation of a GPUNodeClass,
+// returning an Invalid error listing every field that fails
 func (v *GPUNodeClassValidator) validateGPUNodeClass(nodeClass *tgpv1.GPUNodeClass) (admission.Warnings, error) {
 	var warnings admission.Warnings
+	specPath := field.NewPath("spec")
 
-	// Validate TalosConfig if present
+	var errs field.ErrorList
 	if nodeClass.Spec.TalosConfig != nil {
-		if err := v.validateTalosConfig(nodeClass.Spec.TalosConfig); err != nil {
-			return warnings, fmt.Errorf("invalid TalosConfig: %w", err)
+		errs = append(errs, v.validateTalosConfig(nodeClass.Spec.TalosConfig, specPath.Child("talosConfig"))...)
+	}
+	errs = append(errs, v.validateProviders(nodeClass.Spec.Providers, specPath.Child("providers"))...)
+	errs = append(errs, v.validateGPUTypes(nodeClass, specPath.Child("instanceRequirements", "gpuTypes"))...)
+	errs = append(errs, v.validateLimits(nodeClass.Spec.Limits, specPath.Child("limits"))...)
+	warnings = append(warnings, disallowedFallbackWarnings(nodeClass.Spec.InstanceRequirements, specPath.Child("instanceRequirements", "fallbackGPUTypes"))...)
+	if tailscaleConfig := nodeClass.Spec.TailscaleConfig; tailscaleConfig != nil {
+		errs = append(errs, v.validateTailscaleConfig(tailscaleConfig, specPath.Child("tailscaleConfig"))...)
+		if tailscaleConfig.OAuthSecretRef == nil && tailscaleConfig.AuthKeySecretRef != nil {
+			warnings = append(warnings, "spec.tailscaleConfig.authKeySecretRef is deprecated: every node joins with the same key; use oauthSecretRef to generate a key per node")
 		}
 	}
 
-	// Add other validations as needed
-	if err := v.validateProviders(nodeClass.Spec.Providers); err != nil {
-		return warnings, fmt.Errorf("invalid provider configuration: %w", err)
+	if len(errs) > 0 {
+		return warnings, apierrors.NewInvalid(tgpv1.GroupVersion.WithKind("GPUNodeClass").GroupKind(), nodeClass.Name, errs)
 	}
-
-	if err := v.validateLimits(nodeClass.Spec.Limits); err != nil {
-		return warnings, fmt.Errorf("invalid limits: %w", err)
-	}
-
 	return warnings, nil
 }
 
 // validateTalosConfig validates the TalosConfig
-func (v *GPUNodeClassValidator) validateTalosConfig(talosConfig *tgpv1.TalosConfig) error {
+func (v *GPUNodeClassValidator) validateTalosConfig(talosConfig *tgpv1.TalosConfig, path *field.Path) field.ErrorList {
 	// Check that MachineConfigSecretRef is provided
 	if talosConfig.MachineConfigSecretRef == nil {
-		return fmt.Errorf("machineConfigSecretRef is required")
-	}
-
-	// Validate the secret reference
-	if err := v.validateSecretRef(talosConfig.MachineConfigSecretRef); err != nil {
-		return fmt.Errorf("invalid machine config secret reference: %w", err)
+		return field.ErrorList{field.Required(path.Child("machineConfigSecretRef"), "machineConfigSecretRef is required")}
 	}
 
-	return nil
+	return v.validateSecretRef(talosConfig.MachineConfigSecretRef, path.Child("machineConfigSecretRef"))
 }
 
 // validateSecretRef validates a secret reference
-func (v *GPUNodeClassValidator) validateSecretRef(secretRef *tgpv1.SecretKeyRef) error {
+func (v *GPUNodeClassValidator) validateSecretRef(secretRef *tgpv1.SecretKeyRef, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
 	if secretRef.Name == "" {
-		return fmt.Errorf("secret name cannot be empty")
+		errs = append(errs, field.Required(path.Child("name"), "secret name cannot be empty"))
 	}
 	if secretRef.Key == "" {
-		return fmt.Errorf("secret key cannot be empty")
+		errs = append(errs, field.Required(path.Child("key"), "secret key cannot be empty"))
 	}
-	return nil
+	return errs
+}
+
+// validateTailscaleConfig validates the Tailscale credentials and key settings
+func (v *GPUNodeClassValidator) validateTailscaleConfig(tailscaleConfig *tgpv1.TailscaleConfig, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	switch {
+	case tailscaleConfig.OAuthSecretRef != nil:
+		if tailscaleConfig.OAuthSecretRef.Name == "" {
+			errs = append(errs, field.Required(path.Child("oauthSecretRef", "name"), "secret name cannot be empty"))
+		}
+		if len(tailscaleConfig.Tags) == 0 {
+			errs = append(errs, field.Required(path.Child("tags"), "auth keys generated from OAuth credentials must be tagged"))
+		}
+	case tailscaleConfig.AuthKeySecretRef != nil:
+		errs = append(errs, v.validateSecretRef(tailscaleConfig.AuthKeySecretRef, path.Child("authKeySecretRef"))...)
+	default:
+		errs = append(errs, field.Required(path.Child("oauthSecretRef"), "oauthSecretRef or authKeySecretRef is required"))
+	}
+	return errs
 }
 
 // validateProviders validates provider configurations
-func (v *GPUNodeClassValidator) validateProviders(providers []tgpv1.ProviderConfig) error {
+func (v *GPUNodeClassValidator) validateProviders(providers []tgpv1.ProviderConfig, path *field.Path) field.ErrorList {
 	if len(providers) == 0 {
-		return fmt.Errorf("at least one provider must be configured")
+		return field.ErrorList{field.Required(path, "at least one provider must be configured")}
 	}
 
+	var errs field.ErrorList
 	enabledCount := 0
-	for _, provider := range providers {
-		if provider.Enabled != nil && *provider.Enabled {
+	for i, provider := range providers {
+		providerPath := path.Index(i)
+		if provider.Enabled == nil || *provider.Enabled {
 			enabledCount++
 		}
 
-		// Validate provider name
-		validProviders := map[string]bool{
-			"gcp": true,
-		}
-		if !validProviders[provider.Name] {
-			return fmt.Errorf("invalid provider name: %s", provider.Name)
+		if !factory.IsSupported(provider.Name) {
+			errs = append(errs, field.NotSupported(providerPath.Child("name"), provider.Name, factory.Names()))
 		}
 
-		// Validate credentials reference
-		if provider.CredentialsRef.Name == "" {
-			return fmt.Errorf("provider %s missing credentials reference", provider.Name)
+		errs = append(errs, v.validateSecretRef(&provider.CredentialsRef, providerPath.Child("credentialsRef"))...)
+	}
+
+	if enabledCount == 0 {
+		errs = append(errs, field.Invalid(path, len(providers), "at least one provider must be enabled"))
+	}
+
+	return errs
+}
+
+// validateGPUTypes checks that every requested GPU type is supported by at least one
+// of the node class's enabled providers
+func (v *GPUNodeClassValidator) validateGPUTypes(nodeClass *tgpv1.GPUNodeClass, path *field.Path) field.ErrorList {
+	if nodeClass.Spec.InstanceRequirements == nil {
+		return nil
+	}
+
+	var enabled []string
+	for _, provider := range nodeClass.Spec.Providers {
+		if factory.IsSupported(provider.Name) && (provider.Enabled == nil || *provider.Enabled) {
+			enabled = append(enabled, provider.Name)
 		}
-		if provider.CredentialsRef.Key == "" {
-			return fmt.Errorf("provider %s missing credentials key", provider.Name)
+	}
+	if len(enabled) == 0 {
+		// Reported by validateProviders
+		return nil
+	}
+
+	var errs field.ErrorList
+	for i, gpuType := range nodeClass.Spec.InstanceRequirements.GPUTypes {
+		if !anyProviderSupports(enabled, gpuType) {
+			errs = append(errs, field.Invalid(path.Index(i), gpuType,
+				fmt.Sprintf("GPU type is not supported by any configured provider (%s)", strings.Join(enabled, ", "))))
 		}
 	}
+	return errs
+}
 
-	if enabledCount == 0 {
-		return fmt.Errorf("at least one provider must be enabled")
+// disallowedFallbackWarnings warns about fallback GPU types missing from the class's allowed
+// GPU types, which are never provisioned
+func disallowedFallbackWarnings(requirements *tgpv1.InstanceRequirements, path *field.Path) admission.Warnings {
+	if requirements == nil || len(requirements.GPUTypes) == 0 {
+		return nil
 	}
 
-	return nil
+	var warnings admission.Warnings
+	for i, fallback := range requirements.FallbackGPUTypes {
+		allowed := false
+		for _, gpuType := range requirements.GPUTypes {
+			if strings.EqualFold(gpuType, fallback) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			warnings = append(warnings, fmt.Sprintf("%s: fallback GPU type %s is not in spec.instanceRequirements.gpuTypes and will never be provisioned", path.Index(i), fallback))
+		}
+	}
+	return warnings
 }
 
 // validateLimits validates resource limits
-func (v *GPUNodeClassValidator) validateLimits(limits *tgpv1.NodeClassLimits) error {
+func (v *GPUNodeClassValidator) validateLimits(limits *tgpv1.NodeClassLimits, path *field.Path) field.ErrorList {
 	if limits == nil {
 		return nil // Limits are optional
 	}
 
+	var errs field.ErrorList
 	if limits.MaxNodes != nil && *limits.MaxNodes <= 0 {
-		return fmt.Errorf("maxNodes must be greater than 0")
+		errs = append(errs, field.Invalid(path.Child("maxNodes"), *limits.MaxNodes, "maxNodes must be greater than 0"))
 	}
 
 	// Validate maxHourlyCost format if present
 	if limits.MaxHourlyCost != nil && *limits.MaxHourlyCost != "" {
 		// Could add more sophisticated cost validation here
 		if len(*limits.MaxHourlyCost) == 0 {
-			return fmt.Errorf("maxHourlyCost cannot be empty string")
+			errs = append(errs, field.Invalid(path.Child("maxHourlyCost"), *limits.MaxHourlyCost, "maxHourlyCost cannot be empty string"))
 		}
 	}
 
-	return nil
+	return errs
+}
+
+// anyProviderSupports reports whether any of the providers can translate the GPU type
+func anyProviderSupports(providerNames []string, gpuType string) bool {
+	for _, name := range providerNames {
+		if _, err := factory.TranslateGPUType(name, gpuType); err == nil {
+			return true
+		}
+	}
+	return false
 }
 
 // Ensure GPUNodeClassValidator implements the webhook.CustomValidator interface
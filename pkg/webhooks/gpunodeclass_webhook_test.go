@@ -0,0 +1,165 @@
+package webhooks
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+func TestGPUNodeClassValidator(t *testing.T) {
+	validClass := func() *tgpv1.GPUNodeClass {
+		return &tgpv1.GPUNodeClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "gpus"},
+			Spec: tgpv1.GPUNodeClassSpec{
+				Providers: []tgpv1.ProviderConfig{
+					{Name: "vultr", CredentialsRef: tgpv1.SecretKeyRef{Name: "creds", Key: "VULTR_API_KEY"}},
+					{Name: "aws", CredentialsRef: tgpv1.SecretKeyRef{Name: "creds", Key: "AWS_CREDENTIALS_JSON"}},
+				},
+				InstanceRequirements: &tgpv1.InstanceRequirements{GPUTypes: []string{"H100", "T4"}},
+			},
+		}
+	}
+	disabled := false
+
+	tests := []struct {
+		name         string
+		mutate       func(*tgpv1.GPUNodeClass)
+		expectFields []string
+	}{
+		{
+			name:   "valid class is accepted",
+			mutate: func(*tgpv1.GPUNodeClass) {},
+		},
+		{
+			name: "unsupported provider name",
+			mutate: func(c *tgpv1.GPUNodeClass) {
+				c.Spec.Providers[1].Name = "runpod"
+			},
+			expectFields: []string{"spec.providers[1].name"},
+		},
+		{
+			name: "empty credentials secret name",
+			mutate: func(c *tgpv1.GPUNodeClass) {
+				c.Spec.Providers[0].CredentialsRef.Name = ""
+			},
+			expectFields: []string{"spec.providers[0].credentialsRef.name"},
+		},
+		{
+			name: "GPU type no provider supports",
+			mutate: func(c *tgpv1.GPUNodeClass) {
+				c.Spec.InstanceRequirements.GPUTypes = []string{"H100", "TPU"}
+			},
+			expectFields: []string{"spec.instanceRequirements.gpuTypes[1]"},
+		},
+		{
+			name: "GPU type only a disabled provider supports",
+			mutate: func(c *tgpv1.GPUNodeClass) {
+				c.Spec.Providers[1].Enabled = &disabled
+			},
+			expectFields: []string{"spec.instanceRequirements.gpuTypes[1]"},
+		},
+		{
+			name: "tailscale OAuth without tags",
+			mutate: func(c *tgpv1.GPUNodeClass) {
+				c.Spec.TailscaleConfig = &tgpv1.TailscaleConfig{OAuthSecretRef: &tgpv1.TailscaleOAuthSecretRef{Name: "tailscale"}}
+			},
+			expectFields: []string{"spec.tailscaleConfig.tags"},
+		},
+		{
+			name: "tailscale without credentials",
+			mutate: func(c *tgpv1.GPUNodeClass) {
+				c.Spec.TailscaleConfig = &tgpv1.TailscaleConfig{Tags: []string{"tag:gpu"}}
+			},
+			expectFields: []string{"spec.tailscaleConfig.oauthSecretRef"},
+		},
+		{
+			name: "tailscale OAuth with tags",
+			mutate: func(c *tgpv1.GPUNodeClass) {
+				c.Spec.TailscaleConfig = &tgpv1.TailscaleConfig{
+					Tags:           []string{"tag:gpu"},
+					OAuthSecretRef: &tgpv1.TailscaleOAuthSecretRef{Name: "tailscale"},
+				}
+			},
+		},
+		{
+			name: "no providers",
+			mutate: func(c *tgpv1.GPUNodeClass) {
+				c.Spec.Providers = nil
+			},
+			expectFields: []string{"spec.providers"},
+		},
+	}
+
+	validator := NewGPUNodeClassValidator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeClass := validClass()
+			tt.mutate(nodeClass)
+
+			_, err := validator.ValidateCreate(context.Background(), nodeClass)
+			if len(tt.expectFields) == 0 {
+				if err != nil {
+					t.Fatalf("expected class to be accepted, got: %v", err)
+				}
+				return
+			}
+
+			if !apierrors.IsInvalid(err) {
+				t.Fatalf("expected an Invalid error, got: %v", err)
+			}
+			for _, field := range tt.expectFields {
+				if !strings.Contains(err.Error(), field) {
+					t.Errorf("expected error to name %s, got: %v", field, err)
+				}
+			}
+		})
+	}
+}
+
+func TestGPUNodeClassValidatorWarnsOnSharedTailscaleKey(t *testing.T) {
+	nodeClass := &tgpv1.GPUNodeClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpus"},
+		Spec: tgpv1.GPUNodeClassSpec{
+			Providers: []tgpv1.ProviderConfig{
+				{Name: "vultr", CredentialsRef: tgpv1.SecretKeyRef{Name: "creds", Key: "VULTR_API_KEY"}},
+			},
+			TailscaleConfig: &tgpv1.TailscaleConfig{AuthKeySecretRef: &tgpv1.SecretKeyRef{Name: "tailscale", Key: "authkey"}},
+		},
+	}
+
+	warnings, err := NewGPUNodeClassValidator().ValidateCreate(context.Background(), nodeClass)
+	if err != nil {
+		t.Fatalf("expected class to be accepted, got: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "authKeySecretRef is deprecated") {
+		t.Errorf("expected a deprecation warning, got %v", warnings)
+	}
+}
+
+func TestGPUNodeClassValidatorWarnsOnDisallowedFallback(t *testing.T) {
+	nodeClass := &tgpv1.GPUNodeClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpus"},
+		Spec: tgpv1.GPUNodeClassSpec{
+			Providers: []tgpv1.ProviderConfig{
+				{Name: "vultr", CredentialsRef: tgpv1.SecretKeyRef{Name: "creds", Key: "VULTR_API_KEY"}},
+			},
+			InstanceRequirements: &tgpv1.InstanceRequirements{
+				GPUTypes:         []string{"H100", "A100"},
+				FallbackGPUTypes: []string{"a100", "A40"},
+			},
+		},
+	}
+
+	warnings, err := NewGPUNodeClassValidator().ValidateCreate(context.Background(), nodeClass)
+	if err != nil {
+		t.Fatalf("expected class to be accepted, got: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "fallbackGPUTypes[1]") {
+		t.Errorf("expected a warning for the A40 fallback, got %v", warnings)
+	}
+}
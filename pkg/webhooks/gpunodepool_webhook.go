@@ -0,0 +1,72 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+// GPUNodePoolDefaulter fills in defaults for GPUNodePool resources
+type GPUNodePoolDefaulter struct{}
+
+// NewGPUNodePoolDefaulter creates a new GPUNodePool defaulter
+func NewGPUNodePoolDefaulter() *GPUNodePoolDefaulter {
+	return &GPUNodePoolDefaulter{}
+}
+
+// SetupWithManager registers the webhook with the manager
+func (d *GPUNodePoolDefaulter) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&tgpv1.GPUNodePool{}).
+		WithDefaulter(d).
+		Complete()
+}
+
+// Default sets defaults for unset GPUNodePool fields. Values set by the user are never overwritten.
+func (d *GPUNodePoolDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	nodePool, ok := obj.(*tgpv1.GPUNodePool)
+	if !ok {
+		return fmt.Errorf("expected GPUNodePool, got %T", obj)
+	}
+
+	defaultGPUNodePool(nodePool)
+	return nil
+}
+
+// defaultGPUNodePool applies the documented defaults to a GPUNodePool
+func defaultGPUNodePool(nodePool *tgpv1.GPUNodePool) {
+	if nodePool.Spec.Weight == nil {
+		weight := tgpv1.DefaultNodePoolWeight
+		nodePool.Spec.Weight = &weight
+	}
+
+	if disruption := nodePool.Spec.Disruption; disruption != nil && disruption.ConsolidationPolicy == "" {
+		disruption.ConsolidationPolicy = tgpv1.ConsolidationPolicyWhenIdle
+	}
+
+	if nodePool.Spec.MaxHourlyPrice != nil {
+		price := normalizePrice(*nodePool.Spec.MaxHourlyPrice)
+		nodePool.Spec.MaxHourlyPrice = &price
+	}
+}
+
+// normalizePrice rewrites a price such as " $1.50 " as "1.5" so the controller can parse it.
+// Prices that are not numbers are returned unchanged.
+func normalizePrice(price string) string {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(price), "$")
+	value, err := strconv.ParseFloat(strings.TrimSpace(trimmed), 64)
+	if err != nil {
+		return price
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// Ensure GPUNodePoolDefaulter implements the webhook.CustomDefaulter interface
+var _ webhook.CustomDefaulter = &GPUNodePoolDefaulter{}
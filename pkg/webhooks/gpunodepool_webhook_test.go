@@ -0,0 +1,90 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+)
+
+func TestGPUNodePoolDefaulter(t *testing.T) {
+	weight := func(w int32) *int32 { return &w }
+	price := func(p string) *string { return &p }
+
+	tests := []struct {
+		name         string
+		spec         tgpv1.GPUNodePoolSpec
+		expectWeight int32
+		expectPolicy tgpv1.ConsolidationPolicy
+		expectPrice  *string
+	}{
+		{
+			name:         "unset weight defaults to 10",
+			spec:         tgpv1.GPUNodePoolSpec{},
+			expectWeight: tgpv1.DefaultNodePoolWeight,
+		},
+		{
+			name:         "explicit weight is kept",
+			spec:         tgpv1.GPUNodePoolSpec{Weight: weight(0)},
+			expectWeight: 0,
+		},
+		{
+			name: "empty consolidation policy defaults to WhenIdle",
+			spec: tgpv1.GPUNodePoolSpec{
+				Disruption: &tgpv1.DisruptionSpec{ExpireAfter: &metav1.Duration{Duration: time.Hour}},
+			},
+			expectWeight: tgpv1.DefaultNodePoolWeight,
+			expectPolicy: tgpv1.ConsolidationPolicyWhenIdle,
+		},
+		{
+			name: "explicit consolidation policy is kept",
+			spec: tgpv1.GPUNodePoolSpec{
+				Disruption: &tgpv1.DisruptionSpec{ConsolidationPolicy: tgpv1.ConsolidationPolicyNever},
+			},
+			expectWeight: tgpv1.DefaultNodePoolWeight,
+			expectPolicy: tgpv1.ConsolidationPolicyNever,
+		},
+		{
+			name:         "price is normalized",
+			spec:         tgpv1.GPUNodePoolSpec{MaxHourlyPrice: price(" $2.50 ")},
+			expectWeight: tgpv1.DefaultNodePoolWeight,
+			expectPrice:  price("2.5"),
+		},
+		{
+			name:         "unparsable price is kept for validation to report",
+			spec:         tgpv1.GPUNodePoolSpec{MaxHourlyPrice: price("cheap")},
+			expectWeight: tgpv1.DefaultNodePoolWeight,
+			expectPrice:  price("cheap"),
+		},
+	}
+
+	defaulter := NewGPUNodePoolDefaulter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodePool := &tgpv1.GPUNodePool{Spec: tt.spec}
+			if err := defaulter.Default(context.Background(), nodePool); err != nil {
+				t.Fatalf("Default failed: %v", err)
+			}
+
+			if nodePool.Spec.Weight == nil || *nodePool.Spec.Weight != tt.expectWeight {
+				t.Errorf("expected weight %d, got %v", tt.expectWeight, nodePool.Spec.Weight)
+			}
+			if nodePool.Spec.Disruption == nil {
+				if tt.expectPolicy != "" {
+					t.Errorf("expected consolidation policy %s, got no disruption spec", tt.expectPolicy)
+				}
+			} else if nodePool.Spec.Disruption.ConsolidationPolicy != tt.expectPolicy {
+				t.Errorf("expected consolidation policy %s, got %s", tt.expectPolicy, nodePool.Spec.Disruption.ConsolidationPolicy)
+			}
+			switch {
+			case tt.expectPrice == nil && nodePool.Spec.MaxHourlyPrice != nil:
+				t.Errorf("expected no price, got %s", *nodePool.Spec.MaxHourlyPrice)
+			case tt.expectPrice != nil && (nodePool.Spec.MaxHourlyPrice == nil || *nodePool.Spec.MaxHourlyPrice != *tt.expectPrice):
+				t.Errorf("expected price %s, got %v", *tt.expectPrice, nodePool.Spec.MaxHourlyPrice)
+			}
+		})
+	}
+}
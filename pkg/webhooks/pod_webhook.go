@@ -0,0 +1,153 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+// PodGPUDefaulter adds the scheduling hints of a matching GPUNodePool to pods that request
+// vendor-agnostic tgp.io/gpu resources, so they do not need provider-specific labels
+type PodGPUDefaulter struct {
+	Reader client.Reader
+}
+
+// NewPodGPUDefaulter creates a new pod defaulter that looks up pools through reader
+func NewPodGPUDefaulter(reader client.Reader) *PodGPUDefaulter {
+	return &PodGPUDefaulter{Reader: reader}
+}
+
+// SetupWithManager registers the webhook with the manager
+func (d *PodGPUDefaulter) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&corev1.Pod{}).
+		WithDefaulter(d).
+		Complete()
+}
+
+// Default translates the pod's tgp.io/gpu request into a GPU type, using the same selection as
+// the GPUNodePool controller, then adds a node selector for that type and tolerations for the
+// taints of the pool that will launch it. Pods no pool can host are left unchanged.
+func (d *PodGPUDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return fmt.Errorf("expected Pod, got %T", obj)
+	}
+	if !requestsTGPGPU(pod) {
+		return nil
+	}
+
+	var pools tgpv1.GPUNodePoolList
+	if err := d.Reader.List(ctx, &pools); err != nil {
+		return fmt.Errorf("failed to list GPUNodePools: %w", err)
+	}
+
+	defaultPodGPU(pod, pools.Items)
+	return nil
+}
+
+// defaultPodGPU adds the GPU type node selector and pool tolerations to a pod requesting tgp.io/gpu
+func defaultPodGPU(pod *corev1.Pod, pools []tgpv1.GPUNodePool) {
+	gpuType := pod.Spec.NodeSelector[tgpv1.NodeLabelGPUType]
+	if gpuType == "" {
+		requirements, _ := providers.ExtractTGPRequirements(pod)
+		gpuType = providers.SelectGPUType(requirements)
+	}
+
+	pool := poolForGPUType(pod, pools, gpuType)
+	if pool == nil {
+		return
+	}
+
+	if pod.Spec.NodeSelector == nil {
+		pod.Spec.NodeSelector = make(map[string]string)
+	}
+	pod.Spec.NodeSelector[tgpv1.NodeLabelGPUType] = gpuType
+
+	for _, taint := range pool.Spec.Template.Spec.Taints {
+		tolerated := slices.ContainsFunc(pod.Spec.Tolerations, func(toleration corev1.Toleration) bool {
+			return toleration.ToleratesTaint(&taint)
+		})
+		if !tolerated {
+			pod.Spec.Tolerations = append(pod.Spec.Tolerations, corev1.Toleration{
+				Key:      taint.Key,
+				Operator: corev1.TolerationOpEqual,
+				Value:    taint.Value,
+				Effect:   taint.Effect,
+			})
+		}
+	}
+}
+
+// poolForGPUType returns the highest-weighted pool, breaking ties on namespace and name, that
+// offers the GPU type and satisfies the pod's other node selectors, or nil if none does
+func poolForGPUType(pod *corev1.Pod, pools []tgpv1.GPUNodePool, gpuType string) *tgpv1.GPUNodePool {
+	var candidates []*tgpv1.GPUNodePool
+	for i := range pools {
+		pool := &pools[i]
+		if pool.DeletionTimestamp != nil || !poolOffersLabel(pool, tgpv1.NodeLabelGPUType, gpuType) {
+			continue
+		}
+		matches := true
+		for key, value := range pod.Spec.NodeSelector {
+			if key != tgpv1.NodeLabelGPUType && !poolOffersLabel(pool, key, value) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			candidates = append(candidates, pool)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if weightA, weightB := a.Spec.GetWeight(), b.Spec.GetWeight(); weightA != weightB {
+			return weightA > weightB
+		}
+		return a.Namespace+"/"+a.Name < b.Namespace+"/"+b.Name
+	})
+	return candidates[0]
+}
+
+// poolOffersLabel reports whether nodes launched by the pool carry the label value, either
+// through the template labels or the template requirements
+func poolOffersLabel(pool *tgpv1.GPUNodePool, key, value string) bool {
+	if pool.Spec.Template.Metadata != nil {
+		if labelValue, exists := pool.Spec.Template.Metadata.Labels[key]; exists {
+			return labelValue == value
+		}
+	}
+	for _, requirement := range pool.Spec.Template.Spec.Requirements {
+		if requirement.Key == key && slices.Contains(requirement.Values, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestsTGPGPU reports whether any container requests the vendor-agnostic tgp.io/gpu resource
+func requestsTGPGPU(pod *corev1.Pod) bool {
+	for _, container := range pod.Spec.Containers {
+		if quantity, exists := container.Resources.Requests[providers.ResourceTGPGPU]; exists && !quantity.IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// Ensure PodGPUDefaulter implements the webhook.CustomDefaulter interface
+var _ webhook.CustomDefaulter = &PodGPUDefaulter{}
@@ -0,0 +1,98 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	tgpv1 "github.com/solanyn/tgp-operator/pkg/api/v1"
+	"github.com/solanyn/tgp-operator/pkg/providers"
+)
+
+func TestPodGPUDefaulter(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = tgpv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	pool := func(name string, weight int32, gpuType string, taints ...corev1.Taint) *tgpv1.GPUNodePool {
+		return &tgpv1.GPUNodePool{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: tgpv1.GPUNodePoolSpec{
+				Weight: &weight,
+				Template: tgpv1.NodePoolTemplate{Spec: tgpv1.NodeSpec{
+					Requirements: []tgpv1.NodeSelectorRequirement{{Key: tgpv1.NodeLabelGPUType, Values: []string{gpuType}}},
+					Taints:       taints,
+				}},
+			},
+		}
+	}
+	gpuTaint := corev1.Taint{Key: "nvidia.com/gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule}
+	spotTaint := corev1.Taint{Key: "tgp.io/spot", Effect: corev1.TaintEffectNoSchedule}
+
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		pool("a100", 10, "NVIDIA_A100", gpuTaint),
+		pool("a100-spot", 5, "NVIDIA_A100", gpuTaint, spotTaint),
+		pool("a16", 10, "NVIDIA_A16"),
+	).Build()
+	defaulter := NewPodGPUDefaulter(reader)
+
+	gpuPod := func(vram string) *corev1.Pod {
+		requests := corev1.ResourceList{providers.ResourceTGPGPU: resource.MustParse("1")}
+		if vram != "" {
+			requests[providers.ResourceTGPMemory] = resource.MustParse(vram)
+		}
+		return &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:      "train",
+			Resources: corev1.ResourceRequirements{Requests: requests},
+		}}}}
+	}
+
+	t.Run("high VRAM request lands on the A100 pool", func(t *testing.T) {
+		pod := gpuPod("40Gi")
+		if err := defaulter.Default(context.Background(), pod); err != nil {
+			t.Fatalf("Default() error = %v", err)
+		}
+		if got := pod.Spec.NodeSelector[tgpv1.NodeLabelGPUType]; got != "NVIDIA_A100" {
+			t.Errorf("expected NVIDIA_A100 node selector, got %q", got)
+		}
+		if len(pod.Spec.Tolerations) != 1 || !pod.Spec.Tolerations[0].ToleratesTaint(&gpuTaint) {
+			t.Errorf("expected a toleration for the highest-weighted pool's taint only, got %+v", pod.Spec.Tolerations)
+		}
+	})
+
+	t.Run("existing tolerations are not duplicated", func(t *testing.T) {
+		pod := gpuPod("40Gi")
+		pod.Spec.Tolerations = []corev1.Toleration{{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists}}
+		if err := defaulter.Default(context.Background(), pod); err != nil {
+			t.Fatalf("Default() error = %v", err)
+		}
+		if len(pod.Spec.Tolerations) != 1 {
+			t.Errorf("expected the existing toleration to be kept alone, got %+v", pod.Spec.Tolerations)
+		}
+	})
+
+	t.Run("pod without a matching pool is unchanged", func(t *testing.T) {
+		pod := gpuPod("8Gi")
+		if err := defaulter.Default(context.Background(), pod); err != nil {
+			t.Fatalf("Default() error = %v", err)
+		}
+		if pod.Spec.NodeSelector != nil || pod.Spec.Tolerations != nil {
+			t.Errorf("expected no scheduling hints without an A40 pool, got %v %v", pod.Spec.NodeSelector, pod.Spec.Tolerations)
+		}
+	})
+
+	t.Run("pod without tgp.io/gpu is ignored", func(t *testing.T) {
+		pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "web"}}}}
+		if err := defaulter.Default(context.Background(), pod); err != nil {
+			t.Fatalf("Default() error = %v", err)
+		}
+		if pod.Spec.NodeSelector != nil {
+			t.Errorf("expected the pod to be left alone, got %v", pod.Spec.NodeSelector)
+		}
+	})
+}